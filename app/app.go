@@ -0,0 +1,161 @@
+package app
+
+import (
+	"github.com/koki/json"
+	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/transform"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+An app groups short resources under one name, as a lightweight alternative to a Helm chart
+for simple apps. It's a document of its own, which expands to its resources:
+
+	app:
+	  name: web
+	  namespace: shop
+	  version: 1.4.2
+	  labels:
+	    team: storefront
+	  resources:
+	  - deployment:
+	      name: web
+	      ...
+	  - service:
+	      name: web
+	      ...
+
+Each Kubernetes object of the app gets:
+  - the namespace, unless it sets its own or is cluster-scoped,
+  - the labels, along with app.kubernetes.io/name, on itself, its pod template and the
+    selectors of those pods (like --add-label),
+  - the annotations, on itself and its pod template (like --add-annotation),
+  - app.kubernetes.io/version, on itself. Pod templates don't get it, so that a new
+    version doesn't restart the pods of every workload.
+
+*/
+
+// Key is the short syntax key of an app document.
+const Key = "app"
+
+// Labels that the name and version of an app are written to.
+const (
+	NameLabel    = "app.kubernetes.io/name"
+	VersionLabel = "app.kubernetes.io/version"
+)
+
+type App struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Resources are short documents, e.g. {"deployment": {...}}.
+	Resources []map[string]interface{} `json:"resources"`
+}
+
+// Parse returns the app of a short document, or nil if the document isn't an app.
+func Parse(obj map[string]interface{}) (*App, error) {
+	val, ok := obj[Key]
+	if !ok || len(obj) != 1 {
+		return nil, nil
+	}
+	body, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(val, "expected an app to be a dictionary")
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, body, "serializing app")
+	}
+	a := &App{}
+	err = json.Unmarshal(b, a)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextError(err, body, a)
+	}
+
+	// Check for unparsed fields--potential typos.
+	extraneousPaths, err := jsonutil.ExtraneousFieldPaths(body, a)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in app")
+	}
+	if len(extraneousPaths) > 0 {
+		return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+	}
+
+	if len(a.Name) == 0 {
+		return nil, serrors.InvalidInstanceErrorf(a, "an app needs a name")
+	}
+	for i, resource := range a.Resources {
+		if _, ok := resource[Key]; ok {
+			return nil, serrors.InvalidValueErrorf(resource, "app %s: resources[%d] is an app, which can't be nested", a.Name, i)
+		}
+	}
+
+	return a, nil
+}
+
+// Expand replaces each app among the documents with its resources. apps has the app of each resulting
+// document, or nil for documents that aren't part of one.
+func Expand(objs []map[string]interface{}) ([]map[string]interface{}, []*App, error) {
+	results := []map[string]interface{}{}
+	apps := []*App{}
+	for _, obj := range objs {
+		a, err := Parse(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		if a == nil {
+			results = append(results, obj)
+			apps = append(apps, nil)
+			continue
+		}
+
+		for _, resource := range a.Resources {
+			results = append(results, resource)
+			apps = append(apps, a)
+		}
+	}
+
+	return results, apps, nil
+}
+
+// Apply adds the app's namespace, labels and annotations to a Kubernetes object, in place.
+func (a *App) Apply(obj map[string]interface{}) {
+	labels := map[string]string{NameLabel: a.Name}
+	for key, val := range a.Labels {
+		labels[key] = val
+	}
+	common := transform.Common{
+		Labels:      labels,
+		Annotations: a.Annotations,
+	}
+	common.Apply(obj)
+
+	// The profile's defaulting keeps the namespace and version label the object sets itself.
+	defaults := profile.Profile{
+		Settings: profile.Settings{
+			Namespace: a.Namespace,
+		},
+	}
+	if len(a.Version) > 0 {
+		defaults.Labels = map[string]string{VersionLabel: a.Version}
+	}
+	defaults.Apply(obj)
+}
+
+// ApplyToObj is Apply for a converted (typed) Kubernetes object, returning it as a dictionary.
+func (a *App) ApplyToObj(obj interface{}) (interface{}, error) {
+	objMap, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return nil, err
+	}
+	a.Apply(objMap)
+
+	return objMap, nil
+}
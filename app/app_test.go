@@ -0,0 +1,114 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestExpand(t *testing.T) {
+	configMap := map[string]interface{}{"config_map": map[string]interface{}{"name": "settings"}}
+	deployment := map[string]interface{}{"deployment": map[string]interface{}{"name": "web"}}
+	service := map[string]interface{}{"service": map[string]interface{}{"name": "web"}}
+
+	objs, apps, err := Expand([]map[string]interface{}{
+		configMap,
+		{
+			"app": map[string]interface{}{
+				"name":      "web",
+				"namespace": "shop",
+				"resources": []interface{}{deployment, service},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]interface{}{configMap, deployment, service}
+	if !reflect.DeepEqual(objs, expected) {
+		t.Errorf("unexpected documents %s", pretty.Sprint(objs))
+	}
+	if len(apps) != 3 || apps[0] != nil || apps[1] == nil || apps[1] != apps[2] {
+		t.Fatalf("unexpected apps %s", pretty.Sprint(apps))
+	}
+	if apps[1].Name != "web" || apps[1].Namespace != "shop" {
+		t.Errorf("unexpected app %s", pretty.Sprint(apps[1]))
+	}
+
+	for _, obj := range []map[string]interface{}{
+		{"app": map[string]interface{}{"resources": []interface{}{deployment}}},
+		{"app": map[string]interface{}{"name": "web", "resources": []interface{}{map[string]interface{}{"app": map[string]interface{}{"name": "db"}}}}},
+		{"app": map[string]interface{}{"name": "web", "replicas": 2}},
+	} {
+		if _, _, err := Expand([]map[string]interface{}{obj}); err == nil {
+			t.Errorf("expected an error for %s", pretty.Sprint(obj))
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	a := &App{
+		Name:        "web",
+		Namespace:   "shop",
+		Version:     "1.4.2",
+		Labels:      map[string]string{"team": "storefront"},
+		Annotations: map[string]string{"owner": "storefront@example.com"},
+	}
+
+	deployment := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"tier": "web"}},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"tier": "web"}},
+			},
+		},
+	}
+	a.Apply(deployment)
+
+	expected := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"namespace":   "shop",
+			"labels":      map[string]interface{}{NameLabel: "web", "team": "storefront", VersionLabel: "1.4.2"},
+			"annotations": map[string]interface{}{"owner": "storefront@example.com"},
+		},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"tier": "web", NameLabel: "web", "team": "storefront"}},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels":      map[string]interface{}{"tier": "web", NameLabel: "web", "team": "storefront"},
+					"annotations": map[string]interface{}{"owner": "storefront@example.com"},
+				},
+			},
+		},
+	}
+	if diff := pretty.Diff(deployment, expected); len(diff) > 0 {
+		t.Errorf("unexpected deployment:\n%s", pretty.Sprint(diff))
+	}
+
+	// Objects keep their own namespace, and cluster-scoped objects don't get one.
+	configMap := map[string]interface{}{
+		"kind":     "ConfigMap",
+		"metadata": map[string]interface{}{"name": "settings", "namespace": "shared"},
+	}
+	a.Apply(configMap)
+	if ns := configMap["metadata"].(map[string]interface{})["namespace"]; ns != "shared" {
+		t.Errorf("expected namespace shared, got %v", ns)
+	}
+
+	clusterRole := map[string]interface{}{
+		"kind":     "ClusterRole",
+		"metadata": map[string]interface{}{"name": "web-reader"},
+	}
+	a.Apply(clusterRole)
+	if ns, ok := clusterRole["metadata"].(map[string]interface{})["namespace"]; ok {
+		t.Errorf("expected no namespace, got %v", ns)
+	}
+}
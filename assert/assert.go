@@ -0,0 +1,64 @@
+package assert
+
+import (
+	"github.com/koki/short/client"
+)
+
+// Failure describes an assertion that did not hold.
+type Failure struct {
+	Expr string
+}
+
+// IndexObjs arranges short objects by kind and name, e.g. "deployment" -> "web" -> {...}.
+// Kube-native objects are converted to short syntax first.
+func IndexObjs(objs []map[string]interface{}) (map[string]interface{}, error) {
+	kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[string]interface{}{}
+	for _, kokiObj := range kokiObjs {
+		for kind, body := range kokiObj {
+			byName, ok := index[kind].(map[string]interface{})
+			if !ok {
+				byName = map[string]interface{}{}
+				index[kind] = byName
+			}
+
+			name := ""
+			if bodyMap, ok := body.(map[string]interface{}); ok {
+				name, _ = bodyMap["name"].(string)
+			}
+			byName[name] = body
+		}
+	}
+
+	return index, nil
+}
+
+// Check evaluates each expression against the objects and returns the ones that failed.
+func Check(objs []map[string]interface{}, exprs []string) ([]Failure, error) {
+	index, err := IndexObjs(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := []Failure{}
+	for _, s := range exprs {
+		expr, err := ParseExpr(s)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := expr.Eval(index)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			failures = append(failures, Failure{Expr: s})
+		}
+	}
+
+	return failures, nil
+}
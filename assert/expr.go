@@ -0,0 +1,511 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Assertions are written in a small subset of CEL:
+
+	deployment.web.replicas >= 2 && deployment.*.containers.*.mem != null
+
+Paths walk the short structure. "*" selects every entry of a map or list.
+A comparison against a wildcard path holds only if it holds for every
+selected value (and trivially holds if nothing is selected).
+
+Supported operators: ! && || == != < <= > >= and parentheses.
+Supported literals: numbers, "strings", 'strings', true, false, null.
+
+*/
+
+// Expr is a parsed assertion expression.
+type Expr struct {
+	Source string
+	root   node
+}
+
+// Eval evaluates the expression against the given data and reports whether it holds.
+func (e *Expr) Eval(data interface{}) (bool, error) {
+	val, err := e.root.eval(data)
+	if err != nil {
+		return false, serrors.ContextualizeErrorf(err, "evaluating (%s)", e.Source)
+	}
+
+	b, ok := val.(bool)
+	if !ok {
+		return false, serrors.InvalidValueErrorf(val, "expression (%s) is not a boolean", e.Source)
+	}
+
+	return b, nil
+}
+
+// ParseExpr parses an assertion expression.
+func ParseExpr(s string) (*Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "parsing (%s)", s)
+	}
+	if !p.done() {
+		return nil, serrors.InvalidValueErrorf(s, "unexpected token (%s)", p.peek().text)
+	}
+
+	return &Expr{Source: s, root: root}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", "."}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '*'
+}
+
+func tokenize(s string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j == len(runes) {
+				return nil, serrors.InvalidValueErrorf(s, "unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) ||
+				(runes[j] == '.' && j+1 < len(runes) && unicode.IsDigit(runes[j+1]))) {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j])})
+			i = j
+		case isIdentRune(r):
+			j := i + 1
+			for j < len(runes) && isIdentRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[i:j])})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{tokenOp, op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, serrors.InvalidValueErrorf(s, "unexpected character (%c)", r)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *exprParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) acceptOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if p.done() || t.kind != tokenOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if _, ok := p.acceptOp("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	if _, ok := p.acceptOp("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOp(")"); !ok {
+			return nil, serrors.InvalidValueErrorf(p.peek().text, "expected )")
+		}
+		return inner, nil
+	}
+
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, t.text, "not a number")
+		}
+		return &literalNode{val: f}, nil
+	case tokenString:
+		return &literalNode{val: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{val: true}, nil
+		case "false":
+			return &literalNode{val: false}, nil
+		case "null":
+			return &literalNode{val: nil}, nil
+		}
+		return p.parsePath(t.text)
+	default:
+		return nil, serrors.InvalidValueErrorf(t.text, "unexpected token")
+	}
+}
+
+func (p *exprParser) parsePath(first string) (node, error) {
+	path := &pathNode{segments: []string{first}}
+	for {
+		if _, ok := p.acceptOp("."); ok {
+			t := p.peek()
+			if p.done() || (t.kind != tokenIdent && t.kind != tokenNumber) {
+				return nil, serrors.InvalidValueErrorf(t.text, "expected path segment after '.'")
+			}
+			p.pos++
+			path.segments = append(path.segments, t.text)
+			continue
+		}
+		if _, ok := p.acceptOp("["); ok {
+			t := p.peek()
+			if p.done() || t.kind == tokenOp {
+				return nil, serrors.InvalidValueErrorf(t.text, "expected index after '['")
+			}
+			p.pos++
+			if _, ok := p.acceptOp("]"); !ok {
+				return nil, serrors.InvalidValueErrorf(p.peek().text, "expected ]")
+			}
+			path.segments = append(path.segments, t.text)
+			continue
+		}
+		return path, nil
+	}
+}
+
+type node interface {
+	eval(data interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	val interface{}
+}
+
+func (n *literalNode) eval(data interface{}) (interface{}, error) {
+	return n.val, nil
+}
+
+// multiValue is the result of a path that contains a wildcard.
+type multiValue []interface{}
+
+type pathNode struct {
+	segments []string
+}
+
+func (n *pathNode) eval(data interface{}) (interface{}, error) {
+	vals := []interface{}{data}
+	multi := false
+	for _, seg := range n.segments {
+		next := []interface{}{}
+		for _, val := range vals {
+			if seg == "*" {
+				multi = true
+				switch val := val.(type) {
+				case map[string]interface{}:
+					for _, key := range sortedKeys(val) {
+						next = append(next, val[key])
+					}
+				case []interface{}:
+					next = append(next, val...)
+				}
+				continue
+			}
+
+			switch val := val.(type) {
+			case map[string]interface{}:
+				next = append(next, val[seg])
+			case []interface{}:
+				i, err := strconv.Atoi(seg)
+				if err != nil || i < 0 || i >= len(val) {
+					next = append(next, nil)
+				} else {
+					next = append(next, val[i])
+				}
+			default:
+				next = append(next, nil)
+			}
+		}
+		vals = next
+	}
+
+	if multi {
+		return multiValue(vals), nil
+	}
+
+	return normalize(vals[0]), nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(data interface{}) (interface{}, error) {
+	val, err := n.operand.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(val, "operand of ! is not a boolean")
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(data interface{}) (interface{}, error) {
+	left, err := evalBool(n.left, data)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !left {
+		return false, nil
+	}
+	if n.op == "||" && left {
+		return true, nil
+	}
+	return evalBool(n.right, data)
+}
+
+func evalBool(n node, data interface{}) (bool, error) {
+	val, err := n.eval(data)
+	if err != nil {
+		return false, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, serrors.InvalidValueErrorf(val, "operand is not a boolean")
+	}
+	return b, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(data interface{}) (interface{}, error) {
+	left, err := n.left.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range expand(left) {
+		for _, r := range expand(right) {
+			ok, err := compare(n.op, normalize(l), normalize(r))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func expand(val interface{}) []interface{} {
+	if multi, ok := val.(multiValue); ok {
+		return multi
+	}
+	return []interface{}{val}
+}
+
+// normalize converts numeric values to float64 so they can be compared.
+func normalize(val interface{}) interface{} {
+	switch val := val.(type) {
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case float32:
+		return float64(val)
+	}
+	return val
+}
+
+func compare(op string, left, right interface{}) (bool, error) {
+	switch op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	// Missing values never satisfy an ordering.
+	if left == nil || right == nil {
+		return false, nil
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return orderHolds(op, l < r, l == r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return orderHolds(op, l < r, l == r), nil
+		}
+	}
+
+	return false, serrors.InvalidValueErrorf([]interface{}{left, right}, "can't compare with (%s)", op)
+}
+
+func orderHolds(op string, less, eq bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || eq
+	case ">":
+		return !less && !eq
+	default:
+		return !less
+	}
+}
+
+func equal(left, right interface{}) bool {
+	return reflect.DeepEqual(left, right)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package assert
+
+import (
+	"testing"
+
+	"github.com/koki/short/yaml"
+)
+
+var manifest0 = `
+deployment:
+  name: web
+  replicas: 3
+  containers:
+  - name: nginx
+    image: nginx
+    mem:
+      max: 1Gi
+  - name: sidecar
+    image: envoy
+`
+
+func TestExprs(t *testing.T) {
+	obj := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(manifest0), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := IndexObjs([]map[string]interface{}{obj})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]bool{
+		`deployment.web.replicas >= 2`:                                    true,
+		`deployment.web.replicas < 2`:                                     false,
+		`deployment.web.containers[0].image == "nginx"`:                   true,
+		`deployment.web.containers.1.name == 'sidecar'`:                   true,
+		`deployment.*.containers.*.image != null`:                         true,
+		`deployment.*.containers.*.mem.max != null`:                       false,
+		`deployment.web.missing == null`:                                  true,
+		`service.*.name == "anything"`:                                    true,
+		`!(deployment.web.replicas == 3) || deployment.web.name == "web"`: true,
+		`deployment.web.replicas >= 2 && deployment.web.replicas <= 2.5`:  false,
+	}
+
+	for s, expected := range cases {
+		expr, err := ParseExpr(s)
+		if err != nil {
+			t.Errorf("parsing (%s): %v", s, err)
+			continue
+		}
+
+		ok, err := expr.Eval(index)
+		if err != nil {
+			t.Errorf("evaluating (%s): %v", s, err)
+			continue
+		}
+
+		if ok != expected {
+			t.Errorf("(%s) evaluated to %v, expected %v", s, ok, expected)
+		}
+	}
+}
+
+func TestInvalidExprs(t *testing.T) {
+	for _, s := range []string{
+		`deployment.web.replicas >=`,
+		`(deployment.web.replicas == 1`,
+		`deployment.web.name == "web`,
+		`deployment.web.replicas # 2`,
+	} {
+		if _, err := ParseExpr(s); err == nil {
+			t.Errorf("expected error parsing (%s)", s)
+		}
+	}
+
+	expr, err := ParseExpr(`deployment.web.name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expr.Eval(map[string]interface{}{}); err == nil {
+		t.Errorf("expected error for non-boolean expression")
+	}
+}
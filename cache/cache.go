@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+The conversion cache stores the result of converting an object, keyed by the content of the object.
+The key is the SHA-256 of the canonical JSON of the object, along with a salt that identifies
+everything else the result depends on, i.e. the version of short and the conversion settings.
+
+Each entry records the SHA-256 of its result. If a signing key is set, it also records an HMAC-SHA256
+over the key and the result, so results written by anyone without the key are rejected.
+Entries that fail verification are treated as misses and overwritten.
+
+*/
+
+// formatVersion changes whenever the layout of an entry changes.
+const formatVersion = "1"
+
+type entry struct {
+	Key    string          `json:"key"`
+	SHA256 string          `json:"sha256"`
+	HMAC   string          `json:"hmac,omitempty"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Cache is a conversion cache on top of a Store. It's safe for concurrent use if its Store is.
+type Cache struct {
+	store      Store
+	signingKey []byte
+	salt       string
+
+	// hits and misses count lookups, for reporting.
+	lock   sync.Mutex
+	hits   int
+	misses int
+}
+
+// New returns a Cache. The salt must change whenever the conversion result for the same input could change.
+func New(store Store, signingKey []byte, salt string) *Cache {
+	return &Cache{
+		store:      store,
+		signingKey: signingKey,
+		salt:       salt,
+	}
+}
+
+// Key returns the content-addressed key of an object.
+func (c *Cache) Key(obj interface{}) (string, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return "", serrors.InvalidValueContextErrorf(err, obj, "computing cache key")
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(formatVersion + "\x00" + c.salt + "\x00"))
+	hash.Write(b)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (c *Cache) sign(key string, result []byte) string {
+	mac := hmac.New(sha256.New, c.signingKey)
+	mac.Write([]byte(key + "\x00"))
+	mac.Write(result)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func checksum(result []byte) string {
+	sum := sha256.Sum256(result)
+	return hex.EncodeToString(sum[:])
+}
+
+// Get looks up the result for a key. Entries that fail verification are misses.
+func (c *Cache) Get(key string) (interface{}, bool, error) {
+	b, ok, err := c.store.Get(key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	e := entry{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		glog.Warningf("ignoring malformed cache entry %s: %s", key, err)
+		return nil, false, nil
+	}
+	if err := c.verify(key, e); err != nil {
+		glog.Warningf("ignoring cache entry %s: %s", key, err)
+		return nil, false, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(e.Result, &result); err != nil {
+		glog.Warningf("ignoring malformed cache entry %s: %s", key, err)
+		return nil, false, nil
+	}
+
+	return result, true, nil
+}
+
+func (c *Cache) verify(key string, e entry) error {
+	if e.Key != key {
+		return serrors.InvalidValueErrorf(e.Key, "entry is for a different key")
+	}
+	if e.SHA256 != checksum(e.Result) {
+		return serrors.InvalidValueErrorf(e.SHA256, "checksum mismatch")
+	}
+	if len(c.signingKey) == 0 {
+		return nil
+	}
+	if len(e.HMAC) == 0 {
+		return serrors.InvalidValueErrorf(e, "entry isn't signed")
+	}
+	if !hmac.Equal([]byte(e.HMAC), []byte(c.sign(key, e.Result))) {
+		return serrors.InvalidValueErrorf(e.HMAC, "signature mismatch")
+	}
+
+	return nil
+}
+
+// Put stores the result for a key.
+func (c *Cache) Put(key string, result interface{}) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return serrors.InvalidValueContextErrorf(err, result, "serializing cache entry")
+	}
+
+	e := entry{
+		Key:    key,
+		SHA256: checksum(b),
+		Result: b,
+	}
+	if len(c.signingKey) > 0 {
+		e.HMAC = c.sign(key, b)
+	}
+
+	eb, err := json.Marshal(e)
+	if err != nil {
+		return serrors.InvalidValueContextErrorf(err, e, "serializing cache entry")
+	}
+
+	return c.store.Put(key, eb)
+}
+
+// Convert returns the cached result for obj, or calls convert and caches its result.
+// Failing to read or write the cache isn't fatal, it only costs a conversion.
+func (c *Cache) Convert(obj interface{}, convert func() (interface{}, error)) (interface{}, error) {
+	return c.ConvertUnlessSkipped(obj, func() (interface{}, bool, error) {
+		result, err := convert()
+		return result, false, err
+	})
+}
+
+// ConvertUnlessSkipped is Convert, except that a result isn't cached if convert says to skip it,
+// e.g. because the conversion had warnings that a cached result wouldn't repeat.
+func (c *Cache) ConvertUnlessSkipped(obj interface{}, convert func() (result interface{}, skip bool, err error)) (interface{}, error) {
+	key, err := c.Key(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok, err := c.Get(key)
+	if err != nil {
+		glog.Warningf("reading conversion cache: %s", err)
+	}
+	c.count(ok)
+	if ok {
+		return result, nil
+	}
+
+	result, skip, err := convert()
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return result, nil
+	}
+
+	if err := c.Put(key, result); err != nil {
+		glog.Warningf("writing conversion cache: %s", err)
+	}
+
+	return result, nil
+}
+
+func (c *Cache) count(hit bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+// Stats returns the number of lookups that were found in the cache, and the number that weren't.
+func (c *Cache) Stats() (hits, misses int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.hits, c.misses
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var obj0 = map[string]interface{}{
+	"config_map": map[string]interface{}{
+		"name": "settings",
+		"data": map[string]interface{}{"a": "b"},
+	},
+}
+
+var result0 = map[string]interface{}{
+	"apiVersion": "v1",
+	"kind":       "ConfigMap",
+	"metadata":   map[string]interface{}{"name": "settings"},
+	"data":       map[string]interface{}{"a": "b"},
+}
+
+func convert0(calls *int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		return result0, nil
+	}
+}
+
+func tempStore(t *testing.T) *DirStore {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &DirStore{Dir: dir}
+}
+
+func testConvert(t *testing.T, c *Cache, wantCalls int) {
+	calls := 0
+	for i := 0; i < 2; i++ {
+		result, err := c.Convert(obj0, convert0(&calls))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result, result0) {
+			t.Fatalf("unexpected result %#v", result)
+		}
+	}
+	if calls != wantCalls {
+		t.Fatalf("expected %d conversions, got %d", wantCalls, calls)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	store := tempStore(t)
+	defer os.RemoveAll(store.Dir)
+
+	testConvert(t, New(store, nil, "salt"), 1)
+	// A different salt is a different key.
+	testConvert(t, New(store, nil, "other"), 1)
+	// Another cache with the same salt reuses the results.
+	testConvert(t, New(store, nil, "salt"), 0)
+}
+
+func TestConvertUnlessSkipped(t *testing.T) {
+	store := tempStore(t)
+	defer os.RemoveAll(store.Dir)
+
+	c := New(store, nil, "salt")
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, err := c.ConvertUnlessSkipped(obj0, func() (interface{}, bool, error) {
+			calls++
+			return result0, true, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected skipped results to be converted every time, got %d conversions", calls)
+	}
+}
+
+func TestHTTPStore(t *testing.T) {
+	lock := sync.Mutex{}
+	entries := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			entry, ok := entries[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(entry)
+		case http.MethodPut:
+			entries[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+		}
+	}))
+	defer server.Close()
+
+	store, err := Open(server.URL+"/prefix/", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testConvert(t, New(store, nil, "salt"), 1)
+	testConvert(t, New(store, nil, "salt"), 0)
+
+	for path := range entries {
+		if !strings.HasPrefix(path, "/prefix/") {
+			t.Fatalf("unexpected path %s", path)
+		}
+	}
+
+	err = NewHTTPStore(server.URL, "wrong").Put("key", []byte("{}"))
+	if err == nil {
+		t.Fatal("expected an error without the right token")
+	}
+}
+
+func TestVerification(t *testing.T) {
+	store := tempStore(t)
+	defer os.RemoveAll(store.Dir)
+
+	c := New(store, []byte("secret"), "salt")
+	testConvert(t, c, 1)
+
+	key, err := c.Key(obj0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _, err := store.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the result breaks its checksum.
+	tampered := []byte(strings.Replace(string(entry), "ConfigMap", "Secret", 1))
+	if err := store.Put(key, tampered); err != nil {
+		t.Fatal(err)
+	}
+	testConvert(t, New(store, []byte("secret"), "salt"), 1)
+
+	// Entries signed with another key, or not signed, are rejected.
+	testConvert(t, New(store, []byte("other"), "salt"), 1)
+	testConvert(t, New(store, nil, "salt"), 0)
+	testConvert(t, New(store, []byte("secret"), "salt"), 1)
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open("gs://bucket/prefix", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url := store.(*HTTPStore).BaseURL; url != "https://storage.googleapis.com/bucket/prefix" {
+		t.Fatalf("unexpected URL %s", url)
+	}
+
+	if _, err := Open("s3://bucket", ""); err == nil {
+		t.Fatal("expected an error for an unsupported scheme")
+	}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// Store holds cache entries by key. Get reports whether the key was found.
+type Store interface {
+	Get(key string) ([]byte, bool, error)
+	Put(key string, entry []byte) error
+}
+
+// Open returns the store for a location:
+//
+//	/path/to/dir            a local directory
+//	http(s)://host/prefix   an HTTP server that supports GET and PUT, e.g. a presigned bucket proxy
+//	gs://bucket/prefix      a Google Cloud Storage bucket, through its XML API
+//
+// The token, if any, is sent as a bearer token to HTTP and GCS stores.
+func Open(location, token string) (Store, error) {
+	switch {
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return NewHTTPStore(location, token), nil
+	case strings.HasPrefix(location, "gs://"):
+		return NewHTTPStore("https://storage.googleapis.com/"+strings.TrimPrefix(location, "gs://"), token), nil
+	case strings.Contains(location, "://"):
+		return nil, serrors.InvalidValueErrorf(location, "unsupported cache location, expected a directory, an http(s):// URL or a gs:// bucket")
+	default:
+		return &DirStore{Dir: location}, nil
+	}
+}
+
+// DirStore keeps entries in a local directory, fanned out by the first two characters of the key.
+type DirStore struct {
+	Dir string
+}
+
+func (s *DirStore) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(s.Dir, key)
+	}
+
+	return filepath.Join(s.Dir, key[:2], key)
+}
+
+func (s *DirStore) Get(key string) ([]byte, bool, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, serrors.ContextualizeErrorf(err, "reading cache entry %s", key)
+	}
+
+	return b, true, nil
+}
+
+func (s *DirStore) Put(key string, entry []byte) error {
+	path := s.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "creating %s", filepath.Dir(path))
+	}
+
+	// Write to a temporary file first, so concurrent readers never see a partial entry.
+	tmp, err := ioutil.TempFile(filepath.Dir(path), key+".tmp")
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "writing cache entry %s", key)
+	}
+	_, err = tmp.Write(entry)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return serrors.ContextualizeErrorf(err, "writing cache entry %s", key)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// HTTPStore keeps entries at <BaseURL>/<key>.
+type HTTPStore struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func NewHTTPStore(baseURL, token string) *HTTPStore {
+	return &HTTPStore{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *HTTPStore) do(method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.BaseURL+"/"+key, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "%s cache entry %s", method, key)
+	}
+
+	return resp, nil
+}
+
+func (s *HTTPStore) Get(key string) ([]byte, bool, error) {
+	resp, err := s.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, false, serrors.ContextualizeErrorf(err, "reading cache entry %s", key)
+		}
+		return b, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("GET cache entry %s: %s", key, resp.Status)
+	}
+}
+
+func (s *HTTPStore) Put(key string, entry []byte) error {
+	resp, err := s.do(http.MethodPut, key, entry)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("PUT cache entry %s: %s", key, resp.Status)
+	}
+
+	return nil
+}
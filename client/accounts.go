@@ -0,0 +1,53 @@
+package client
+
+import (
+	"strings"
+)
+
+// DefaultServiceAccount exists in every namespace, so references to it are always satisfied.
+const DefaultServiceAccount = "default"
+
+// AccountRef is an object that runs pods as a ServiceAccount.
+type AccountRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Account   string
+}
+
+// MissingServiceAccounts finds references (the "account" key) to ServiceAccounts that aren't among the given Koki objects.
+func MissingServiceAccounts(kokiObjs []map[string]interface{}) []AccountRef {
+	accounts := map[string]bool{}
+	refs := []AccountRef{}
+	for _, obj := range kokiObjs {
+		for kind, body := range obj {
+			bodyMap, ok := body.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _ := bodyMap["name"].(string)
+			namespace, _ := bodyMap["namespace"].(string)
+			if kind == "service_account" {
+				accounts[namespace+"/"+name] = true
+				continue
+			}
+
+			account, _ := bodyMap["account"].(string)
+			// Strip the automount suffix, e.g. "builder:auto".
+			account = strings.SplitN(account, ":", 2)[0]
+			if len(account) > 0 && account != DefaultServiceAccount {
+				refs = append(refs, AccountRef{Kind: kind, Name: name, Namespace: namespace, Account: account})
+			}
+		}
+	}
+
+	missing := []AccountRef{}
+	for _, ref := range refs {
+		if !accounts[ref.Namespace+"/"+ref.Account] {
+			missing = append(missing, ref)
+		}
+	}
+
+	return missing
+}
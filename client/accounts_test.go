@@ -0,0 +1,23 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestMissingServiceAccounts(t *testing.T) {
+	objs := []map[string]interface{}{
+		{"service_account": map[string]interface{}{"name": "builder", "namespace": "ci"}},
+		{"pod": map[string]interface{}{"name": "a", "namespace": "ci", "account": "builder:auto"}},
+		{"deployment": map[string]interface{}{"name": "b", "namespace": "prod", "account": "builder"}},
+		{"job": map[string]interface{}{"name": "c", "account": "default"}},
+	}
+
+	missing := MissingServiceAccounts(objs)
+	expected := []AccountRef{{Kind: "deployment", Name: "b", Namespace: "prod", Account: "builder"}}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Error(pretty.Diff(missing, expected))
+	}
+}
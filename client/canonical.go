@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Short objects are written in a canonical order, so the same object always serializes the same way:
+
+	deployment:        the kind
+	  version: ...     then the metadata fields, in metaKeys order
+	  name: ...
+	  labels: ...
+	  containers: ...  then every other field, alphabetically
+	_unsupported: ...  and finally the fields short doesn't support, if any
+
+Nested dictionaries are ordered alphabetically. Kubernetes objects keep their usual order:
+alphabetical in YAML, and the order of the Kubernetes types in JSON.
+
+*/
+
+// metaKeys are the metadata fields of every short type, in output order.
+var metaKeys = []string{"version", "cluster", "name", "namespace", "labels", "annotations", "owners", "finalizers"}
+
+// CanonicalYAML serializes an object as YAML, with short objects in canonical order.
+func CanonicalYAML(obj interface{}) ([]byte, error) {
+	ordered, err := canonicalize(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := yaml.Marshal(ordered)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, obj, "couldn't serialize as yaml")
+	}
+
+	return b, nil
+}
+
+// CanonicalJSON serializes an object as indented JSON, with short objects in canonical order.
+func CanonicalJSON(obj interface{}) ([]byte, error) {
+	ordered, err := canonicalize(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ordered.(yaml.MapSlice); !ok || !isShortObject(ordered) {
+		return json.MarshalIndent(obj, "", "  ")
+	}
+
+	buf := &bytes.Buffer{}
+	err = writeJSON(ordered, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	indented := &bytes.Buffer{}
+	err = json.Indent(indented, buf.Bytes(), "", "  ")
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, buf.String(), "indenting json")
+	}
+
+	return indented.Bytes(), nil
+}
+
+// canonicalize converts obj to nested MapSlices in canonical order.
+func canonicalize(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, obj, "marshalling to JSON")
+	}
+
+	// yaml.Unmarshal keeps integers as integers, unlike json.Unmarshal.
+	var generic interface{}
+	err = yaml.Unmarshal(b, &generic)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(b), "converting to dictionary")
+	}
+
+	ordered := order(generic)
+	if isShortObject(ordered) {
+		slice := ordered.(yaml.MapSlice)
+		sort.SliceStable(slice, func(i, j int) bool {
+			return slice[i].Key != UnsupportedKey && slice[j].Key == UnsupportedKey
+		})
+		slice[0].Value = orderFirst(slice[0].Value.(yaml.MapSlice), metaKeys)
+	}
+
+	return ordered, nil
+}
+
+// isShortObject is true for a dictionary with a single key (the kind) whose value is a dictionary,
+// along with an optional "_unsupported" section.
+func isShortObject(obj interface{}) bool {
+	slice, ok := obj.(yaml.MapSlice)
+	if !ok {
+		return false
+	}
+
+	kinds := 0
+	for _, item := range slice {
+		if item.Key == UnsupportedKey {
+			continue
+		}
+		if _, ok := item.Value.(yaml.MapSlice); !ok {
+			return false
+		}
+		kinds++
+	}
+
+	return kinds == 1
+}
+
+// order converts each dictionary to a MapSlice sorted by key.
+func order(obj interface{}) interface{} {
+	switch obj := obj.(type) {
+	case map[interface{}]interface{}:
+		slice := yaml.MapSlice{}
+		for key, val := range obj {
+			slice = append(slice, yaml.MapItem{Key: fmt.Sprint(key), Value: order(val)})
+		}
+		sort.Slice(slice, func(i, j int) bool {
+			return slice[i].Key.(string) < slice[j].Key.(string)
+		})
+		return slice
+	case []interface{}:
+		list := make([]interface{}, len(obj))
+		for i, item := range obj {
+			list[i] = order(item)
+		}
+		return list
+	default:
+		return obj
+	}
+}
+
+// orderFirst moves the first keys to the front of a sorted MapSlice, in the order given.
+func orderFirst(slice yaml.MapSlice, first []string) yaml.MapSlice {
+	rank := map[string]int{}
+	for i, key := range first {
+		rank[key] = i - len(first)
+	}
+
+	sort.SliceStable(slice, func(i, j int) bool {
+		return rank[slice[i].Key.(string)] < rank[slice[j].Key.(string)]
+	})
+
+	return slice
+}
+
+func writeJSON(obj interface{}, buf *bytes.Buffer) error {
+	switch obj := obj.(type) {
+	case yaml.MapSlice:
+		buf.WriteString("{")
+		for i, item := range obj {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSON(item.Key, buf); err != nil {
+				return err
+			}
+			buf.WriteString(":")
+			if err := writeJSON(item.Value, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case []interface{}:
+		buf.WriteString("[")
+		for i, item := range obj {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSON(item, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return serrors.InvalidValueContextErrorf(err, obj, "couldn't serialize as json")
+		}
+		buf.Write(b)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/koki/short/parser"
+)
+
+func TestCanonicalYAML(t *testing.T) {
+	obj := map[string]interface{}{
+		"persistent_volume": map[string]interface{}{
+			"storage":     "1Gi",
+			"name":        "vol",
+			"annotations": map[string]interface{}{"b": "1", "a": "2"},
+			"aws_ebs":     map[string]interface{}{"vol_id": "v", "fs": "ext4"},
+			"version":     "v1",
+			"namespace":   "ns",
+		},
+	}
+
+	expected := `persistent_volume:
+  version: v1
+  name: vol
+  namespace: ns
+  annotations:
+    a: "2"
+    b: "1"
+  aws_ebs:
+    fs: ext4
+    vol_id: v
+  storage: 1Gi
+`
+	for i := 0; i < 10; i++ {
+		b, err := CanonicalYAML(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Fatalf("unexpected order:\n%s", b)
+		}
+	}
+
+	b, err := CanonicalJSON(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "{\n  \"persistent_volume\": {\n    \"version\": \"v1\",\n    \"name\": \"vol\",") {
+		t.Fatalf("unexpected order:\n%s", b)
+	}
+}
+
+// TestCanonicalOrderForEveryType checks the order of every short type in the test data.
+func TestCanonicalOrderForEveryType(t *testing.T) {
+	files, err := filepath.Glob("../testdata/*/*.short.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		objs, err := parser.Parse([]string{file}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, obj := range objs {
+			parsed, err := parser.ParseKokiNativeObject(obj)
+			if err != nil {
+				// Some test data is only valid after imports are evaluated.
+				continue
+			}
+
+			b, err := CanonicalYAML(parsed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ordered := yaml.MapSlice{}
+			if err := yaml.Unmarshal(b, &ordered); err != nil {
+				t.Fatal(err)
+			}
+			body, ok := ordered[0].Value.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			keys := []string{}
+			for _, item := range body {
+				keys = append(keys, item.Key.(string))
+			}
+			if !isCanonicalOrder(keys) {
+				t.Errorf("%s: %s isn't in canonical order: %v", file, ordered[0].Key, keys)
+			}
+		}
+	}
+}
+
+func isCanonicalOrder(keys []string) bool {
+	meta := 0
+	for meta < len(keys) {
+		i := indexOf(metaKeys, keys[meta])
+		if i < 0 {
+			break
+		}
+		if meta > 0 && indexOf(metaKeys, keys[meta-1]) > i {
+			return false
+		}
+		meta++
+	}
+
+	return sort.StringsAreSorted(keys[meta:])
+}
+
+func indexOf(keys []string, key string) int {
+	for i, k := range keys {
+		if k == key {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -6,8 +6,8 @@ import (
 	"github.com/koki/json"
 	"github.com/koki/json/jsonutil"
 	"github.com/koki/short/converter"
+	"github.com/koki/short/converter/converters"
 	"github.com/koki/short/parser"
-	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
 
@@ -17,6 +17,7 @@ Converting text streams and JSON/YAML dictionaries to objects. (And also the rev
 
 This package is the canonical integration point for using Koki Short functionality.
 It's used for the command-line tool and functional tests.
+Programs that embed short should prefer the stable API in koki/short/convert.
 
 */
 
@@ -33,23 +34,7 @@ func ConvertKokiStreams(kokiStreams []io.ReadCloser) ([]interface{}, error) {
 func ConvertKokiMaps(objs []map[string]interface{}) ([]interface{}, error) {
 	convertedObjs := make([]interface{}, len(objs))
 	for i, obj := range objs {
-		// 1. Parse.
-		parsedObj, err := parser.ParseKokiNativeObject(obj)
-		if err != nil {
-			return nil, err
-		}
-
-		// 2. Check for unparsed fields--potential typos.
-		extraneousPaths, err := jsonutil.ExtraneousFieldPaths(obj, parsedObj)
-		if err != nil {
-			return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in input")
-		}
-		if len(extraneousPaths) > 0 {
-			return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
-		}
-
-		// 3. Convert.
-		convertedObj, err := converter.DetectAndConvertFromKokiObj(parsedObj)
+		convertedObj, err := ConvertKokiMap(obj, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -59,6 +44,43 @@ func ConvertKokiMaps(objs []map[string]interface{}) ([]interface{}, error) {
 	return convertedObjs, nil
 }
 
+// ConvertKokiMap converts one Koki object to a Kube object, adding what it can't convert to warnings.
+func ConvertKokiMap(obj map[string]interface{}, warnings *converters.Warnings) (interface{}, error) {
+	// 0. Set aside the fields short doesn't support.
+	obj, unsupported, err := SplitUnsupported(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Parse.
+	parsedObj, err := parser.ParseKokiNativeObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Check for unparsed fields--potential typos.
+	extraneousPaths, err := ExtraneousKokiFieldPaths(obj, parsedObj)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in input")
+	}
+	if len(extraneousPaths) > 0 {
+		return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+	}
+
+	// 3. Convert.
+	convertedObj, err := converter.DetectAndConvertFromKokiObjWithWarnings(parsedObj, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Restore the fields short doesn't support.
+	if len(unsupported) > 0 {
+		return InjectUnsupported(convertedObj, unsupported)
+	}
+
+	return convertedObj, nil
+}
+
 // ConvertKubeStreams to Koki objects.
 func ConvertKubeStreams(kubeStreams []io.ReadCloser) ([]interface{}, error) {
 	objs, err := parser.ParseStreams(kubeStreams)
@@ -72,32 +94,91 @@ func ConvertKubeStreams(kubeStreams []io.ReadCloser) ([]interface{}, error) {
 func ConvertKubeMaps(objs []map[string]interface{}) ([]interface{}, error) {
 	convertedObjs := make([]interface{}, len(objs))
 	for i, obj := range objs {
-		// 1. Parse.
-		parsedObj, err := parser.ParseSingleKubeNative(obj)
+		convertedObj, err := ConvertKubeMap(obj, nil)
 		if err != nil {
 			return nil, err
 		}
+		convertedObjs[i] = convertedObj
+	}
 
-		// 2. Check for unparsed fields--potential typos.
-		extraneousPaths, err := jsonutil.ExtraneousFieldPaths(obj, parsedObj)
+	return convertedObjs, nil
+}
+
+// ConvertKubeMap converts one Kube object to a Koki object, adding what it can't convert to warnings.
+func ConvertKubeMap(obj map[string]interface{}, warnings *converters.Warnings) (interface{}, error) {
+	// 1. Parse.
+	parsedObj, err := parser.ParseSingleKubeNative(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Check for unparsed fields--potential typos.
+	extraneousPaths, err := jsonutil.ExtraneousFieldPaths(obj, parsedObj)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in input")
+	}
+	if len(extraneousPaths) > 0 {
+		return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+	}
+
+	// 3. Convert.
+	return converter.DetectAndConvertFromKubeObjWithWarnings(parsedObj, warnings)
+}
+
+// ConvertEitherMapsToKoki converts either Koki or Kube objects to Koki dictionaries.
+func ConvertEitherMapsToKoki(objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	kokiMaps := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		if _, err := parser.ParseKokiNativeObject(obj); err == nil {
+			kokiMaps[i] = obj
+			continue
+		}
+
+		kokiObjs, err := ConvertKubeMaps([]map[string]interface{}{obj})
 		if err != nil {
-			return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in input")
+			return nil, serrors.ContextualizeErrorf(err, "input is neither Koki nor Kube syntax")
 		}
-		if len(extraneousPaths) > 0 {
-			return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+
+		b, err := json.Marshal(kokiObjs[0])
+		if err != nil {
+			return nil, serrors.InvalidInstanceContextErrorf(err, kokiObjs[0], "marshalling to JSON")
 		}
 
-		// 3. Convert.
-		convertedObj, err := converter.DetectAndConvertFromKubeObj(parsedObj)
+		kokiMap := map[string]interface{}{}
+		err = json.Unmarshal(b, &kokiMap)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, string(b), "converting Koki object to dictionary")
+		}
+		kokiMaps[i] = kokiMap
+	}
+
+	return kokiMaps, nil
+}
+
+// ConvertEitherMaps converts each object in the other direction: Koki objects (see parser.IsKokiNativeObject)
+// to Kube objects, and the rest to Koki objects.
+func ConvertEitherMaps(objs []map[string]interface{}) ([]interface{}, error) {
+	convertedObjs := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		converted, err := ConvertEitherMap(obj, nil)
 		if err != nil {
 			return nil, err
 		}
-		convertedObjs[i] = convertedObj
+		convertedObjs[i] = converted
 	}
 
 	return convertedObjs, nil
 }
 
+// ConvertEitherMap converts one object like ConvertEitherMaps, adding what it can't convert to warnings.
+func ConvertEitherMap(obj map[string]interface{}, warnings *converters.Warnings) (interface{}, error) {
+	if parser.IsKokiNativeObject(obj) {
+		return ConvertKokiMap(obj, warnings)
+	}
+
+	return ConvertKubeMap(obj, warnings)
+}
+
 // ConvertEitherStreamsToKube either Koki or Kube to just Kube objects.
 func ConvertEitherStreamsToKube(eitherStreams []io.ReadCloser) ([]interface{}, error) {
 	objs, err := parser.ParseStreams(eitherStreams)
@@ -131,9 +212,9 @@ func WriteObjsToYamlStream(objs []interface{}, yamlStream io.Writer) error {
 			}
 		}
 
-		b, err := yaml.Marshal(obj)
+		b, err := CanonicalYAML(obj)
 		if err != nil {
-			return serrors.InvalidValueErrorf(obj, "couldn't serialize as yaml")
+			return err
 		}
 		_, err = yamlStream.Write(b)
 		if err != nil {
@@ -154,7 +235,7 @@ func WriteObjsToJSONStream(objs []interface{}, jsonStream io.Writer) error {
 			}
 		}
 
-		b, err := json.MarshalIndent(obj, "", "  ")
+		b, err := CanonicalJSON(obj)
 		if err != nil {
 			return serrors.InvalidValueErrorf(obj, "couldn't serialize as json")
 		}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+
+	"github.com/koki/short/types"
+)
+
+func TestConvertEitherMaps(t *testing.T) {
+	objs := []map[string]interface{}{
+		{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "kube"},
+		},
+		{
+			"config_map": map[string]interface{}{"name": "short", "version": "v1"},
+		},
+	}
+
+	converted, err := ConvertEitherMaps(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrapper, ok := converted[0].(*types.ConfigMapWrapper); !ok || wrapper.ConfigMap.Name != "kube" {
+		t.Errorf("expected a short ConfigMap, got %#v", converted[0])
+	}
+	if configMap, ok := converted[1].(*v1.ConfigMap); !ok || configMap.Name != "short" {
+		t.Errorf("expected a Kubernetes ConfigMap, got %#v", converted[1])
+	}
+
+	_, err = ConvertEitherMaps([]map[string]interface{}{{"config_map": map[string]interface{}{"name": "short", "typo": "x"}}})
+	if err == nil {
+		t.Error("expected an error for an extraneous field")
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Encoder writes objects in either syntax in an output format, e.g. as a YAML stream.
+type Encoder interface {
+	Encode(objs []interface{}, w io.Writer) error
+}
+
+// EncoderFunc is a function that's an Encoder.
+type EncoderFunc func(objs []interface{}, w io.Writer) error
+
+// Encode calls f.
+func (f EncoderFunc) Encode(objs []interface{}, w io.Writer) error {
+	return f(objs, w)
+}
+
+var encoders = map[string]Encoder{
+	"yaml":  EncoderFunc(WriteObjsToYamlStream),
+	"json":  EncoderFunc(WriteObjsToJSONStream),
+	"jsonl": EncoderFunc(WriteObjsToJSONLines),
+}
+
+// RegisterEncoder adds an output format, usually from an init function, so that importing the
+// encoder's package is enough to use it. It panics if the format is already registered.
+func RegisterEncoder(format string, encoder Encoder) {
+	if _, ok := encoders[format]; ok {
+		panic(fmt.Sprintf("client: output format %s is already registered", format))
+	}
+
+	encoders[format] = encoder
+}
+
+// EncoderFor returns the Encoder of an output format.
+func EncoderFor(format string) (Encoder, bool) {
+	encoder, ok := encoders[format]
+	return encoder, ok
+}
+
+// EncoderFormats returns the registered output formats, sorted.
+func EncoderFormats() []string {
+	formats := []string{}
+	for format := range encoders {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestEncoders(t *testing.T) {
+	if formats := EncoderFormats(); !reflect.DeepEqual(formats, []string{"json", "jsonl", "yaml"}) {
+		t.Errorf("unexpected formats %v", formats)
+	}
+
+	encoder, ok := EncoderFor("yaml")
+	if !ok {
+		t.Fatal("expected a yaml encoder")
+	}
+	buf := &bytes.Buffer{}
+	err := encoder.Encode([]interface{}{map[string]interface{}{"a": "b"}, map[string]interface{}{"c": "d"}}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a: b\n---\nc: d\n" {
+		t.Errorf("unexpected yaml\n%s", buf.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering yaml again to panic")
+		}
+	}()
+	RegisterEncoder("yaml", EncoderFunc(func(objs []interface{}, w io.Writer) error { return nil }))
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kr/text"
+
+	"github.com/koki/json"
+	"github.com/koki/json/jsonutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+// DocumentError is the failure to read, check or convert one document of an input file.
+type DocumentError struct {
+	// Op is what failed, e.g. "converting".
+	Op   string
+	File string
+	// Document is the index of the document in the file, from 0, or -1 for the whole file.
+	Document int
+	Kind     string
+	Name     string
+	Err      error
+}
+
+// NewDocumentError wraps err with the file and document it came from.
+// The kind and name are read from obj, which may be in Kubernetes or short syntax.
+func NewDocumentError(op, file string, document int, obj map[string]interface{}, err error) *DocumentError {
+	kind, name := kindAndName(obj)
+	return &DocumentError{
+		Op:       op,
+		File:     file,
+		Document: document,
+		Kind:     kind,
+		Name:     name,
+		Err:      err,
+	}
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Op, e.location(), e.Err.Error())
+}
+
+// PrettyError formats the error like serrors.PrettyError.
+func (e *DocumentError) PrettyError() string {
+	return fmt.Sprintf("%s %s:\n%s", e.Op, e.location(), text.Indent(serrors.PrettyError(e.Err), "  "))
+}
+
+func (e *DocumentError) location() string {
+	location := e.File
+	if e.Document >= 0 {
+		location = fmt.Sprintf("%s (document %d", location, e.Document)
+		if len(e.Kind) > 0 {
+			location = fmt.Sprintf("%s, %s %s", location, e.Kind, e.Name)
+		}
+		location += ")"
+	}
+	return location
+}
+
+func kindAndName(obj map[string]interface{}) (string, string) {
+	if kind, ok := obj["kind"].(string); ok {
+		name := ""
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+		return kind, name
+	}
+
+	for key, val := range obj {
+		if key == UnsupportedKey {
+			continue
+		}
+		name := ""
+		if body, ok := val.(map[string]interface{}); ok {
+			name, _ = body["name"].(string)
+		}
+		return key, name
+	}
+
+	return "", ""
+}
+
+// ErrorRecord is an error in a machine-readable form, for --error-format json.
+type ErrorRecord struct {
+	File     string `json:"file,omitempty"`
+	Document *int   `json:"document,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name,omitempty"`
+	// Path is the field the error is about, if it's known, e.g. "$.deployment.containers.0.image".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorRecords breaks err down into records: one for each extraneous field, or one for anything else.
+func ErrorRecords(err error) []ErrorRecord {
+	record := ErrorRecord{}
+	if docErr, ok := err.(*DocumentError); ok {
+		record.File = docErr.File
+		if docErr.Document >= 0 {
+			document := docErr.Document
+			record.Document = &document
+		}
+		record.Kind = docErr.Kind
+		record.Name = docErr.Name
+		err = docErr.Err
+	}
+
+	context := []string{}
+	baseErr := err
+	for {
+		if contextErr, ok := baseErr.(*serrors.ErrorWithContext); ok {
+			context = append(context, serrors.ReversedStringsList(contextErr.Context)...)
+			baseErr = contextErr.BaseError
+			continue
+		}
+		if docErr, ok := baseErr.(*DocumentError); ok {
+			baseErr = docErr.Err
+			continue
+		}
+		break
+	}
+
+	switch baseErr := baseErr.(type) {
+	case *jsonutil.ExtraneousFieldsError:
+		records := []ErrorRecord{}
+		for _, path := range baseErr.Paths {
+			fieldRecord := record
+			fieldRecord.Path = "$." + strings.Join(path, ".")
+			fieldRecord.Message = "extraneous field (typo?)"
+			records = append(records, fieldRecord)
+		}
+		return records
+	case *json.UnmarshalTypeError:
+		if len(baseErr.Field) > 0 {
+			record.Path = "$." + baseErr.Field
+		}
+	}
+	// The context may name the full path of the field, e.g. "$.deployment.replicas".
+	for _, contextItem := range context {
+		if strings.HasPrefix(contextItem, "$.") && !strings.ContainsAny(contextItem, " \n") {
+			record.Path = contextItem
+		}
+	}
+
+	record.Message = strings.Join(append(context, baseErr.Error()), ": ")
+	return []ErrorRecord{record}
+}
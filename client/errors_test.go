@@ -0,0 +1,64 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/json/jsonutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+func TestErrorRecords(t *testing.T) {
+	pod := map[string]interface{}{
+		"pod": map[string]interface{}{"name": "web"},
+	}
+	service := map[string]interface{}{
+		"kind":     "Service",
+		"metadata": map[string]interface{}{"name": "api"},
+	}
+	zero, one := 0, 1
+
+	testCases := []struct {
+		err      error
+		expected []ErrorRecord
+	}{
+		{
+			err: NewDocumentError("converting", "web.short.yaml", 0, pod, &jsonutil.ExtraneousFieldsError{
+				Paths: [][]string{{"pod", "imagee"}, {"pod", "nmae"}},
+			}),
+			expected: []ErrorRecord{
+				{File: "web.short.yaml", Document: &zero, Kind: "pod", Name: "web", Path: "$.pod.imagee", Message: "extraneous field (typo?)"},
+				{File: "web.short.yaml", Document: &zero, Kind: "pod", Name: "web", Path: "$.pod.nmae", Message: "extraneous field (typo?)"},
+			},
+		},
+		{
+			err: NewDocumentError("converting", "api.yaml", 1, service,
+				serrors.ContextualizeErrorf(serrors.ContextualizeErrorf(errors.New("bad port"), "$.spec.ports.0"), "service")),
+			expected: []ErrorRecord{
+				{File: "api.yaml", Document: &one, Kind: "Service", Name: "api", Path: "$.spec.ports.0", Message: "service: $.spec.ports.0: bad port"},
+			},
+		},
+		{
+			err: NewDocumentError("parsing", "stdin", -1, nil, errors.New("invalid YAML")),
+			expected: []ErrorRecord{
+				{File: "stdin", Message: "invalid YAML"},
+			},
+		},
+		{
+			err: errors.New("no input"),
+			expected: []ErrorRecord{
+				{Message: "no input"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		records := ErrorRecords(testCase.err)
+		if !reflect.DeepEqual(records, testCase.expected) {
+			t.Error(pretty.Sprint(testCase.err.Error(), records, testCase.expected))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/parser"
+)
+
+// ExtraneousKokiFieldPaths finds the fields of a short object that weren't parsed--potential typos.
+// Some fields can be written in more than one form (e.g. a volume's "vol_id" or its named selector
+// fields), so a field that's missing from the reserialized object is only extraneous if removing
+// it doesn't change the parsed object.
+func ExtraneousKokiFieldPaths(obj map[string]interface{}, parsedObj interface{}) ([][]string, error) {
+	paths, err := jsonutil.ExtraneousFieldPaths(obj, parsedObj)
+	if err != nil {
+		return nil, err
+	}
+	reserialized, err := jsonutil.MarshalMap(parsedObj)
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, compactedFieldPaths([]string{}, obj, reserialized)...)
+
+	extraneousPaths := [][]string{}
+	for _, path := range paths {
+		withoutField, ok := withoutFieldPath(obj, path)
+		if !ok {
+			extraneousPaths = append(extraneousPaths, path)
+			continue
+		}
+
+		reparsedObj, err := parser.ParseKokiNativeObject(withoutField.(map[string]interface{}))
+		if err == nil && reflect.DeepEqual(reparsedObj, parsedObj) {
+			extraneousPaths = append(extraneousPaths, path)
+		}
+	}
+
+	return extraneousPaths, nil
+}
+
+// compactedFieldPaths finds the fields of dictionaries that are written back in a compact form, e.g. a volume
+// written back as "aws_ebs:vol-0:ext4". ExtraneousFieldPaths can't compare their fields.
+func compactedFieldPaths(prefix []string, before, after interface{}) [][]string {
+	paths := [][]string{}
+	switch before := before.(type) {
+	case map[string]interface{}:
+		afterMap, ok := after.(map[string]interface{})
+		for key, beforeVal := range before {
+			if jsonutil.FieldValIsEmpty(beforeVal) {
+				continue
+			}
+			path := jsonutil.ExtendPrefix(prefix, key)
+			if !ok {
+				if after != nil {
+					paths = append(paths, path)
+				}
+				continue
+			}
+			paths = append(paths, compactedFieldPaths(path, beforeVal, afterMap[key])...)
+		}
+	case []interface{}:
+		afterSlice, _ := after.([]interface{})
+		for i, beforeVal := range before {
+			if i < len(afterSlice) {
+				paths = append(paths, compactedFieldPaths(jsonutil.ExtendPrefix(prefix, strconv.Itoa(i)), beforeVal, afterSlice[i])...)
+			}
+		}
+	}
+
+	return paths
+}
+
+// withoutFieldPath copies the maps and slices along path, leaving out the field at the end of it.
+func withoutFieldPath(val interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+
+	switch val := val.(type) {
+	case map[string]interface{}:
+		child, ok := val[path[0]]
+		if !ok {
+			return nil, false
+		}
+		copied := make(map[string]interface{}, len(val))
+		for key, childVal := range val {
+			copied[key] = childVal
+		}
+		if len(path) == 1 {
+			delete(copied, path[0])
+			return copied, true
+		}
+		newChild, ok := withoutFieldPath(child, path[1:])
+		if !ok {
+			return nil, false
+		}
+		copied[path[0]] = newChild
+		return copied, true
+	case []interface{}:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= len(val) {
+			return nil, false
+		}
+		copied := append([]interface{}{}, val...)
+		if len(path) == 1 {
+			return append(copied[:i], copied[i+1:]...), true
+		}
+		newChild, ok := withoutFieldPath(val[i], path[1:])
+		if !ok {
+			return nil, false
+		}
+		copied[i] = newChild
+		return copied, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/koki/short/parser"
+)
+
+func TestExtraneousKokiFieldPaths(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want [][]string
+	}{
+		// The named form of a glusterfs selector is written back as "vol_id".
+		{`
+persistent_volume:
+  name: gluster
+  vol_type: glusterfs
+  endpoints: glusterfs-cluster
+  path: kube_vol
+  ro: true
+`, [][]string{}},
+		{`
+persistent_volume:
+  name: gluster
+  vol_type: glusterfs
+  vol_id: glusterfs-cluster:kube_vol
+  readonly: true
+`, [][]string{{"persistent_volume", "readonly"}}},
+		{`
+pod:
+  name: web
+  volumes:
+    data:
+      vol_type: aws_ebs
+      volume_id: vol-0
+      fs: ext4
+      fstype: xfs
+`, [][]string{{"pod", "volumes", "data", "fstype"}}},
+	}
+
+	for _, testCase := range testCases {
+		objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(strings.NewReader(testCase.in))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsedObj, err := parser.ParseKokiNativeObject(objs[0])
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+
+		paths, err := ExtraneousKokiFieldPaths(objs[0], parsedObj)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(paths, testCase.want) {
+			t.Errorf("%s: got %v, want %v", testCase.in, paths, testCase.want)
+		}
+	}
+}
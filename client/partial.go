@@ -0,0 +1,226 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/converter"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A partial conversion converts what it can instead of failing the whole object.
+
+Kubernetes objects are converted to short syntax and back again. Every field of the input
+that's missing after the round trip was dropped, either because the vendored Kubernetes API
+doesn't have it or because the short converter for its kind doesn't support it yet.
+Objects that can't be converted at all, e.g. because short doesn't support their kind,
+are passed through unchanged.
+
+List items with a "name" are matched by name, since short syntax may reorder them.
+
+*/
+
+// rewrittenFields are fields that the converters express another way, by kind ("" for every kind).
+// They're moved by the round trip, but not dropped. Paths match the end of a field's path.
+var rewrittenFields = map[string][]string{
+	"": {
+		// Converted to node affinity.
+		"spec.nodeSelector",
+		// Converted to match labels if each expression is "In" with one value.
+		"selector.matchExpressions",
+		// Copied to each container.
+		"spec.securityContext.runAsNonRoot",
+		"spec.securityContext.runAsUser",
+		"spec.securityContext.seLinuxOptions",
+	},
+	"ReplicationController": {
+		// Left out if it's the same as the pod template's labels.
+		"spec.selector",
+	},
+}
+
+// Dropped lists what a partial conversion left out of an input object.
+type Dropped struct {
+	File      string `json:"file,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Unconverted is why the whole object was passed through unchanged, if it was.
+	Unconverted string `json:"unconverted,omitempty"`
+	// Fields are the paths of the dropped fields, e.g. "$.spec.containers.0.ports.0.hostIP".
+	Fields []string `json:"fields,omitempty"`
+}
+
+// IsEmpty is true if nothing was left out.
+func (d Dropped) IsEmpty() bool {
+	return len(d.Unconverted) == 0 && len(d.Fields) == 0
+}
+
+// Comment describes what was left out, one line per field.
+func (d Dropped) Comment() string {
+	if len(d.Unconverted) > 0 {
+		return fmt.Sprintf("# not converted: %s\n", d.Unconverted)
+	}
+
+	lines := []string{}
+	for _, field := range d.Fields {
+		lines = append(lines, fmt.Sprintf("# dropped: %s\n", field))
+	}
+
+	return strings.Join(lines, "")
+}
+
+// ConvertKubeMapsPartially converts Kubernetes objects to short syntax, leaving out what isn't supported.
+// It returns what was left out of each object.
+func ConvertKubeMapsPartially(objs []map[string]interface{}) ([]interface{}, []Dropped, error) {
+	convertedObjs := make([]interface{}, len(objs))
+	dropped := make([]Dropped, len(objs))
+	for i, obj := range objs {
+		dropped[i] = droppedFor(obj)
+		if len(dropped[i].Kind) == 0 {
+			return nil, nil, serrors.InvalidValueErrorf(obj, "expected a Kubernetes object with a kind")
+		}
+
+		convertedObj, fields, err := convertKubeMapPartially(obj)
+		if err != nil {
+			convertedObjs[i] = obj
+			dropped[i].Unconverted = err.Error()
+			continue
+		}
+
+		convertedObjs[i] = convertedObj
+		dropped[i].Fields = fields
+	}
+
+	return convertedObjs, dropped, nil
+}
+
+func droppedFor(obj map[string]interface{}) Dropped {
+	d := Dropped{}
+	d.Kind, _ = obj["kind"].(string)
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		d.Namespace, _ = metadata["namespace"].(string)
+		d.Name, _ = metadata["name"].(string)
+	}
+
+	return d
+}
+
+func convertKubeMapPartially(obj map[string]interface{}) (interface{}, []string, error) {
+	parsedObj, err := parser.ParseSingleKubeNative(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kokiObj, err := converter.DetectAndConvertFromKubeObj(parsedObj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeObj, err := converter.DetectAndConvertFromKokiObj(kokiObj)
+	if err != nil {
+		return nil, nil, serrors.ContextualizeErrorf(err, "converting back to kube syntax")
+	}
+
+	roundTripped, err := objutil.ToDictionary(kubeObj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kind, _ := obj["kind"].(string)
+	fields := []string{}
+	for _, path := range missingPaths(nil, obj, roundTripped) {
+		if !isRewritten(kind, path) {
+			fields = append(fields, "$."+strings.Join(path, "."))
+		}
+	}
+	sort.Strings(fields)
+
+	return kokiObj, fields, nil
+}
+
+func isRewritten(kind string, path []string) bool {
+	joined := strings.Join(path, ".")
+	for _, suffix := range append(rewrittenFields[""], rewrittenFields[kind]...) {
+		if joined == suffix || strings.HasSuffix(joined, "."+suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// missingPaths lists the non-empty fields of before that aren't in after.
+func missingPaths(prefix []string, before, after interface{}) [][]string {
+	if jsonutil.FieldValIsEmpty(before) {
+		return nil
+	}
+
+	switch before := before.(type) {
+	case map[string]interface{}:
+		afterMap, _ := after.(map[string]interface{})
+		paths := [][]string{}
+		for key, beforeVal := range before {
+			if jsonutil.FieldValIsEmpty(beforeVal) {
+				continue
+			}
+			path := jsonutil.ExtendPrefix(prefix, key)
+			afterVal, ok := afterMap[key]
+			if !ok {
+				paths = append(paths, path)
+				continue
+			}
+			paths = append(paths, missingPaths(path, beforeVal, afterVal)...)
+		}
+		return paths
+	case []interface{}:
+		afterList, _ := after.([]interface{})
+		paths := [][]string{}
+		for i, beforeVal := range before {
+			path := jsonutil.ExtendPrefix(prefix, fmt.Sprintf("%d", i))
+			afterVal, ok := matchingItem(i, beforeVal, afterList)
+			if !ok {
+				paths = append(paths, path)
+				continue
+			}
+			paths = append(paths, missingPaths(path, beforeVal, afterVal)...)
+		}
+		return paths
+	default:
+		return nil
+	}
+}
+
+// matchingItem finds the item of a list that corresponds to the i-th item of the input list.
+func matchingItem(i int, item interface{}, list []interface{}) (interface{}, bool) {
+	if itemMap, ok := item.(map[string]interface{}); ok {
+		if name, ok := itemMap["name"].(string); ok && len(name) > 0 {
+			if i < len(list) && hasName(list[i], name) {
+				return list[i], true
+			}
+			for _, other := range list {
+				if hasName(other, name) {
+					return other, true
+				}
+			}
+			return nil, false
+		}
+	}
+
+	if i < len(list) {
+		return list[i], true
+	}
+
+	return nil, false
+}
+
+func hasName(item interface{}, name string) bool {
+	itemMap, ok := item.(map[string]interface{})
+	return ok && itemMap["name"] == name
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/types"
+)
+
+const partialInput = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: prod
+spec:
+  nodeSelector:
+    disk: ssd
+  volumes:
+  - name: b
+    emptyDir: {}
+  - name: a
+    emptyDir: {}
+  containers:
+  - name: web
+    image: nginx
+    ports:
+    - containerPort: 80
+      hostIP: 127.0.0.1
+    volumeMounts:
+    - name: a
+      mountPath: /a
+    - name: b
+      mountPath: /b
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget
+spec:
+  size: 3
+`
+
+func TestConvertKubeMapsPartially(t *testing.T) {
+	objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(strings.NewReader(partialInput))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, dropped, err := ConvertKubeMapsPartially(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := converted[0].(*types.PodWrapper); !ok {
+		t.Errorf("expected a short Pod, got %T", converted[0])
+	}
+	if !reflect.DeepEqual(converted[1], objs[1]) {
+		t.Errorf("expected the unsupported object to be passed through, got %#v", converted[1])
+	}
+
+	// Reordered volumes and the node selector (converted to affinity) aren't dropped.
+	expected := Dropped{Kind: "Pod", Namespace: "prod", Name: "web", Fields: []string{"$.spec.containers.0.ports.0.hostIP"}}
+	if !reflect.DeepEqual(dropped[0], expected) {
+		t.Error(pretty.Diff(dropped[0], expected))
+	}
+	if dropped[1].Kind != "Widget" || len(dropped[1].Unconverted) == 0 {
+		t.Errorf("expected the Widget to be reported as unconverted, got %#v", dropped[1])
+	}
+	if comment := dropped[0].Comment(); comment != "# dropped: $.spec.containers.0.ports.0.hostIP\n" {
+		t.Errorf("unexpected comment %q", comment)
+	}
+}
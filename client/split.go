@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/koki/json"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+// WriteObjsToJSONLines writes each object as JSON on a line of its own.
+func WriteObjsToJSONLines(objs []interface{}, jsonStream io.Writer) error {
+	for _, obj := range objs {
+		b, err := CanonicalJSON(obj)
+		if err != nil {
+			return serrors.InvalidValueErrorf(obj, "couldn't serialize as json")
+		}
+
+		line := &bytes.Buffer{}
+		err = json.Compact(line, b)
+		if err != nil {
+			return serrors.InvalidValueContextErrorf(err, string(b), "compacting json")
+		}
+		line.WriteByte('\n')
+
+		_, err = jsonStream.Write(line.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteObjsToFiles writes each object to a YAML file of its own in the directory, named after its kind and name.
+// The directory is created if it doesn't exist. It returns the paths of the files.
+func WriteObjsToFiles(objs []interface{}, dir string) ([]string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "creating %s", dir)
+	}
+
+	paths := []string{}
+	written := map[string]bool{}
+	for _, obj := range objs {
+		name, err := FileNameFor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, name)
+		if written[path] {
+			return nil, serrors.InvalidValueErrorf(obj, "another object was already written to %s", path)
+		}
+		written[path] = true
+
+		b, err := CanonicalYAML(obj)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(path, b, 0644)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "writing %s", path)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// FileNameFor names the YAML file of a Kubernetes or short object, e.g. "deployment-web.yaml". It's an error if the
+// kind or name contains a path separator, so the file is always in the directory it's written to.
+func FileNameFor(obj interface{}) (string, error) {
+	objMap, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return "", err
+	}
+
+	kind, name := "", ""
+	if kubeKind, ok := objMap["kind"].(string); ok {
+		kind = kubeKind
+		if metadata, ok := objMap["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+	} else {
+		for key, val := range objMap {
+			if key == UnsupportedKey {
+				continue
+			}
+			kind = key
+			if body, ok := val.(map[string]interface{}); ok {
+				name, _ = body["name"].(string)
+			}
+		}
+	}
+	if len(kind) == 0 || len(name) == 0 {
+		return "", serrors.InvalidValueErrorf(obj, "expected an object with a kind and a name")
+	}
+	// Kubernetes names can't contain "/", so a name that does would only write the file somewhere else.
+	if strings.ContainsAny(kind+name, `/\`) {
+		return "", serrors.InvalidValueErrorf(obj, "the kind (%s) and name (%s) can't contain a path separator", kind, name)
+	}
+
+	return fmt.Sprintf("%s-%s.yaml", strings.ToLower(kind), name), nil
+}
+
+// ClusterOf is the cluster of a Kubernetes or short object, from metadata.clusterName or the cluster field,
+// or "" if it doesn't have one.
+func ClusterOf(obj interface{}) (string, error) {
+	objMap, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := objMap["kind"].(string); ok {
+		if metadata, ok := objMap["metadata"].(map[string]interface{}); ok {
+			cluster, _ := metadata["clusterName"].(string)
+			return cluster, nil
+		}
+		return "", nil
+	}
+
+	for key, val := range objMap {
+		if key == UnsupportedKey {
+			continue
+		}
+		if body, ok := val.(map[string]interface{}); ok {
+			cluster, _ := body["cluster"].(string)
+			return cluster, nil
+		}
+	}
+
+	return "", nil
+}
+
+// GroupByCluster groups the objects by their cluster (see ClusterOf), keeping their order within each group.
+// The clusters are in the order they first appear, and "" is the group of the objects without one.
+func GroupByCluster(objs []interface{}) ([]string, map[string][]interface{}, error) {
+	clusters := []string{}
+	groups := map[string][]interface{}{}
+	for _, obj := range objs {
+		cluster, err := ClusterOf(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := groups[cluster]; !ok {
+			clusters = append(clusters, cluster)
+		}
+		groups[cluster] = append(groups[cluster], obj)
+	}
+
+	return clusters, groups, nil
+}
+
+// WriteObjsToClusterDirs is WriteObjsToFiles, except that the objects of each cluster are written to a
+// subdirectory named after it. Objects without a cluster are written to the directory itself.
+func WriteObjsToClusterDirs(objs []interface{}, dir string) ([]string, error) {
+	clusters, groups, err := GroupByCluster(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, cluster := range clusters {
+		clusterDir := dir
+		if cluster == "." || cluster == ".." {
+			return nil, serrors.InvalidValueErrorf(cluster, "a cluster can't be named . or ..")
+		}
+		if len(cluster) > 0 {
+			// Context names are often ARNs, e.g. "arn:aws:eks:us-east-1:123456789012:cluster/prod".
+			clusterDir = filepath.Join(dir, strings.Replace(cluster, "/", "_", -1))
+		}
+
+		clusterPaths, err := WriteObjsToFiles(groups[cluster], clusterDir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, clusterPaths...)
+	}
+
+	return paths, nil
+}
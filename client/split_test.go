@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileNameFor(t *testing.T) {
+	objs := map[string]interface{}{
+		"deployment-web.yaml": map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "web"},
+		},
+		"config_map-settings.yaml": map[string]interface{}{
+			"config_map": map[string]interface{}{"name": "settings"},
+		},
+		"service-api.yaml": map[string]interface{}{
+			"service":      map[string]interface{}{"name": "api"},
+			"_unsupported": map[string]interface{}{},
+		},
+	}
+	for expected, obj := range objs {
+		name, err := FileNameFor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != expected {
+			t.Errorf("expected %s, got %s", expected, name)
+		}
+	}
+
+	_, err := FileNameFor(map[string]interface{}{"kind": "Pod"})
+	if err == nil {
+		t.Error("expected an error for an object without a name")
+	}
+
+	dir, err := ioutil.TempDir("", "short-split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"x/../../../evil", `..\evil`} {
+		obj := map[string]interface{}{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": name}}
+		if _, err := FileNameFor(obj); err == nil {
+			t.Errorf("expected an error for the name %s", name)
+		}
+		if _, err := WriteObjsToFiles([]interface{}{obj}, dir); err == nil {
+			t.Errorf("expected an error writing the name %s", name)
+		}
+	}
+}
+
+func TestWriteObjsToJSONLines(t *testing.T) {
+	objs := []interface{}{
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "a"}},
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "b"}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := WriteObjsToJSONLines(objs, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"kind":"Pod","metadata":{"name":"a"}}
+{"kind":"Pod","metadata":{"name":"b"}}
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestGroupByCluster(t *testing.T) {
+	objs := []interface{}{
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "a", "clusterName": "prod"}},
+		map[string]interface{}{"service": map[string]interface{}{"name": "b"}},
+		map[string]interface{}{"config_map": map[string]interface{}{"name": "c", "cluster": "staging"}},
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "d", "clusterName": "prod"}},
+	}
+
+	clusters, groups, err := GroupByCluster(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(clusters, []string{"prod", "", "staging"}) {
+		t.Errorf("unexpected clusters %q", clusters)
+	}
+	if len(groups["prod"]) != 2 || len(groups[""]) != 1 || len(groups["staging"]) != 1 {
+		t.Errorf("unexpected groups %v", groups)
+	}
+	if !reflect.DeepEqual(groups["prod"], []interface{}{objs[0], objs[3]}) {
+		t.Errorf("expected the order to be kept, got %v", groups["prod"])
+	}
+}
+
+func TestWriteObjsToClusterDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The output directory is created if it doesn't exist.
+	outDir := filepath.Join(dir, "generated")
+	objs := []interface{}{
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "a", "clusterName": "prod/east"}},
+		map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "a"}},
+	}
+	paths, err := WriteObjsToClusterDirs(objs, outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{filepath.Join(outDir, "prod_east", "pod-a.yaml"), filepath.Join(outDir, "pod-a.yaml")}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %q, got %q", expected, paths)
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/koki/short/util/objutil"
+)
+
+// Summary describes a conversion, e.g. to show how much smaller short syntax is, or to catch
+// fields that were lost.
+type Summary struct {
+	// Kinds counts the converted objects of each kind, as written in the output, e.g. "deployment" or "Deployment".
+	Kinds map[string]int `json:"kinds"`
+	// Warnings counts the problems that didn't stop the conversion.
+	Warnings int `json:"warnings"`
+	// DroppedFields counts the fields that a partial conversion left out.
+	DroppedFields int `json:"dropped_fields"`
+	// Unconverted counts the objects that a partial conversion passed through unchanged.
+	Unconverted int `json:"unconverted"`
+	// InputBytes and OutputBytes are the sizes of the input and output objects as YAML, so that
+	// formatting, comments and the output format don't change the comparison.
+	InputBytes  int `json:"input_bytes"`
+	OutputBytes int `json:"output_bytes"`
+}
+
+// AddInputs adds the size of the input objects.
+func (s *Summary) AddInputs(objs []map[string]interface{}) error {
+	for _, obj := range objs {
+		b, err := CanonicalYAML(obj)
+		if err != nil {
+			return err
+		}
+		s.InputBytes += len(b)
+	}
+
+	return nil
+}
+
+// AddOutputs counts the converted objects by kind, and adds their size.
+func (s *Summary) AddOutputs(objs []interface{}) error {
+	if s.Kinds == nil {
+		s.Kinds = map[string]int{}
+	}
+	for _, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return err
+		}
+		s.Kinds[kindOf(objMap)]++
+
+		b, err := CanonicalYAML(objMap)
+		if err != nil {
+			return err
+		}
+		s.OutputBytes += len(b)
+	}
+
+	return nil
+}
+
+// AddDropped counts what a partial conversion left out.
+func (s *Summary) AddDropped(dropped []Dropped) {
+	for _, d := range dropped {
+		s.DroppedFields += len(d.Fields)
+		if len(d.Unconverted) > 0 {
+			s.Unconverted++
+		}
+	}
+}
+
+// Reduction is how much smaller the output is than the input, in percent. It's negative if the output is larger.
+func (s *Summary) Reduction() float64 {
+	if s.InputBytes == 0 {
+		return 0
+	}
+
+	return 100 * float64(s.InputBytes-s.OutputBytes) / float64(s.InputBytes)
+}
+
+// Write writes the summary as a table of kinds, followed by the totals.
+func (s *Summary) Write(w io.Writer) error {
+	kinds := make([]string, 0, len(s.Kinds))
+	objects := 0
+	for kind, count := range s.Kinds {
+		kinds = append(kinds, kind)
+		objects += count
+	}
+	sort.Strings(kinds)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tCOUNT")
+	for _, kind := range kinds {
+		fmt.Fprintf(tw, "%s\t%d\n", kind, s.Kinds[kind])
+	}
+	fmt.Fprintf(tw, "total\t%d\n", objects)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nwarnings: %d\n", s.Warnings)
+	fmt.Fprintf(w, "dropped fields: %d\n", s.DroppedFields)
+	if s.Unconverted > 0 {
+		fmt.Fprintf(w, "unconverted objects: %d\n", s.Unconverted)
+	}
+	_, err := fmt.Fprintf(w, "size: %d bytes -> %d bytes (%.1f%% smaller)\n", s.InputBytes, s.OutputBytes, s.Reduction())
+
+	return err
+}
+
+// kindOf is the kind of a Kubernetes object, or the key of a short object.
+func kindOf(obj map[string]interface{}) string {
+	if kind, ok := obj["kind"].(string); ok {
+		return kind
+	}
+	for key := range obj {
+		if key != UnsupportedKey {
+			return key
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	inputs := []map[string]interface{}{
+		{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "a"}},
+		{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "b"}},
+		{"config_map": map[string]interface{}{"name": "c"}},
+	}
+	outputs := []interface{}{
+		map[string]interface{}{"namespace": map[string]interface{}{"name": "a"}},
+		map[string]interface{}{"namespace": map[string]interface{}{"name": "b"}, UnsupportedKey: map[string]interface{}{}},
+		map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "c"}},
+	}
+
+	s := &Summary{Warnings: 1}
+	if err := s.AddInputs(inputs); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddOutputs(outputs); err != nil {
+		t.Fatal(err)
+	}
+	s.AddDropped([]Dropped{{Fields: []string{"$.a", "$.b"}}, {Unconverted: "unsupported kind"}})
+
+	if s.Kinds["namespace"] != 2 || s.Kinds["ConfigMap"] != 1 || len(s.Kinds) != 2 {
+		t.Errorf("unexpected kinds %v", s.Kinds)
+	}
+	if s.DroppedFields != 2 || s.Unconverted != 1 {
+		t.Errorf("unexpected dropped fields %d and unconverted objects %d", s.DroppedFields, s.Unconverted)
+	}
+	if s.InputBytes == 0 || s.OutputBytes == 0 {
+		t.Errorf("unexpected sizes %d and %d", s.InputBytes, s.OutputBytes)
+	}
+
+	s = &Summary{Kinds: map[string]int{"deployment": 2, "service": 1}, Warnings: 1, InputBytes: 1000, OutputBytes: 400}
+	buf := &bytes.Buffer{}
+	if err := s.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `KIND        COUNT
+deployment  2
+service     1
+total       3
+
+warnings: 1
+dropped fields: 0
+size: 1000 bytes -> 400 bytes (60.0% smaller)
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Fields that short doesn't support yet can be kept in the "_unsupported" section of a short
+manifest, next to the object itself:
+
+	deployment:
+	  name: web
+	  ...
+	_unsupported:
+	  $.spec.template.spec.containers[name=web].ports[0].hostIP: 127.0.0.1
+
+Each key is the path of a field in the Kubernetes object, and each value is the field's value.
+List items with a name are selected by name, since short syntax may reorder them.
+Converting back to Kubernetes syntax sets each field again.
+
+*/
+
+// UnsupportedKey is the section of a short manifest that holds the fields short doesn't support.
+const UnsupportedKey = "_unsupported"
+
+var selectorRegexp = regexp.MustCompile(`^([^\[]*)((?:\[[^\]]*\])*)$`)
+
+// ConvertKubeMapsKeepingUnsupported converts Kubernetes objects to short syntax,
+// keeping the fields that short doesn't support in an "_unsupported" section.
+func ConvertKubeMapsKeepingUnsupported(objs []map[string]interface{}) ([]interface{}, error) {
+	convertedObjs := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		kokiObj, fields, err := convertKubeMapPartially(obj)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			convertedObjs[i] = kokiObj
+			continue
+		}
+
+		unsupported := map[string]interface{}{}
+		for _, field := range fields {
+			path := strings.Split(strings.TrimPrefix(field, "$."), ".")
+			val, err := objutil.AtPathIn(obj, path)
+			if err != nil {
+				return nil, err
+			}
+			unsupported[selectorPath(obj, path)] = val
+		}
+
+		kokiMap, err := objutil.ToDictionary(kokiObj)
+		if err != nil {
+			return nil, err
+		}
+		kokiMap[UnsupportedKey] = unsupported
+		convertedObjs[i] = kokiMap
+	}
+
+	return convertedObjs, nil
+}
+
+// selectorPath formats a path in obj, e.g. "spec.containers.0.ports.0.hostIP", as "$.spec.containers[name=web].ports[0].hostIP".
+func selectorPath(obj interface{}, path []string) string {
+	formatted := "$"
+	for _, segment := range path {
+		list, ok := obj.([]interface{})
+		if !ok {
+			formatted += "." + segment
+			obj, _ = objutil.AtPathIn(obj, []string{segment})
+			continue
+		}
+
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= len(list) {
+			formatted += fmt.Sprintf("[%s]", segment)
+			obj = nil
+			continue
+		}
+
+		obj = list[i]
+		if item, ok := obj.(map[string]interface{}); ok {
+			if name, ok := item["name"].(string); ok && len(name) > 0 && isUniqueName(list, name) {
+				formatted += fmt.Sprintf("[name=%s]", name)
+				continue
+			}
+		}
+		formatted += fmt.Sprintf("[%d]", i)
+	}
+
+	return formatted
+}
+
+func isUniqueName(list []interface{}, name string) bool {
+	count := 0
+	for _, item := range list {
+		if hasName(item, name) {
+			count++
+		}
+	}
+
+	return count == 1
+}
+
+// SplitUnsupported separates the "_unsupported" section from a short manifest. The manifest isn't modified.
+func SplitUnsupported(obj map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	section, ok := obj[UnsupportedKey]
+	if !ok {
+		return obj, nil, nil
+	}
+
+	unsupported, ok := section.(map[string]interface{})
+	if !ok {
+		return nil, nil, serrors.InvalidValueErrorf(section, "expected a dictionary of field paths and values in %s", UnsupportedKey)
+	}
+
+	rest := map[string]interface{}{}
+	for key, val := range obj {
+		if key != UnsupportedKey {
+			rest[key] = val
+		}
+	}
+
+	return rest, unsupported, nil
+}
+
+// InjectUnsupported sets the fields from an "_unsupported" section in a converted Kubernetes object.
+func InjectUnsupported(kubeObj interface{}, unsupported map[string]interface{}) (map[string]interface{}, error) {
+	obj, err := objutil.ToDictionary(kubeObj)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range unsupported {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		err = setAtSelectorPath(obj, path, unsupported[path])
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "restoring %s from %s", path, UnsupportedKey)
+		}
+	}
+
+	return obj, nil
+}
+
+func setAtSelectorPath(obj map[string]interface{}, path string, val interface{}) error {
+	if !strings.HasPrefix(path, "$.") {
+		return serrors.InvalidValueErrorf(path, "expected a path starting with $.")
+	}
+
+	var parent interface{} = obj
+	segments := splitSelectorPath(strings.TrimPrefix(path, "$."))
+	for i, segment := range segments {
+		match := selectorRegexp.FindStringSubmatch(segment)
+		if match == nil || len(match[1]) == 0 {
+			return serrors.InvalidValueErrorf(segment, "expected a field name, optionally followed by [index] or [name=...]")
+		}
+		key := match[1]
+		selectors := []string{}
+		if len(match[2]) > 0 {
+			selectors = strings.Split(strings.Trim(match[2], "[]"), "][")
+		}
+		last := i == len(segments)-1
+
+		parentMap, ok := parent.(map[string]interface{})
+		if !ok {
+			return serrors.InvalidValueErrorf(parent, "expected a dictionary at %s", key)
+		}
+		if last && len(selectors) == 0 {
+			parentMap[key] = val
+			return nil
+		}
+
+		child, ok := parentMap[key]
+		if !ok && len(selectors) == 0 {
+			child = map[string]interface{}{}
+			parentMap[key] = child
+		}
+
+		for j, selector := range selectors {
+			list, ok := child.([]interface{})
+			if !ok {
+				return serrors.InvalidValueErrorf(child, "expected a list at %s", key)
+			}
+			index, err := selectItem(list, selector)
+			if err != nil {
+				return err
+			}
+			if last && j == len(selectors)-1 {
+				list[index] = val
+				return nil
+			}
+			child = list[index]
+		}
+
+		parent = child
+	}
+
+	return nil
+}
+
+// splitSelectorPath splits a path at each "." outside of brackets.
+func splitSelectorPath(path string) []string {
+	segments := []string{}
+	depth, start := 0, 0
+	for i, c := range path {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case c == '.' && depth == 0:
+			segments = append(segments, path[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(segments, path[start:])
+}
+
+func selectItem(list []interface{}, selector string) (int, error) {
+	if strings.HasPrefix(selector, "name=") {
+		name := strings.TrimPrefix(selector, "name=")
+		for i, item := range list {
+			if hasName(item, name) {
+				return i, nil
+			}
+		}
+		return 0, serrors.InvalidValueErrorf(selector, "no list item named %s", name)
+	}
+
+	i, err := strconv.Atoi(selector)
+	if err != nil {
+		return 0, serrors.InvalidValueContextErrorf(err, selector, "expected an index or name=...")
+	}
+	if i < 0 || i >= len(list) {
+		return 0, serrors.InvalidValueErrorf(selector, "index out of range (list has %d items)", len(list))
+	}
+
+	return i, nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/util/objutil"
+)
+
+const unsupportedInput = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  volumes:
+  - name: b
+    emptyDir: {}
+  - name: a
+    emptyDir: {}
+  containers:
+  - name: web
+    image: nginx
+    ports:
+    - containerPort: 80
+      hostIP: 127.0.0.1
+  - name: logger
+    image: fluentd
+    ports:
+    - containerPort: 24224
+      hostIP: 10.0.0.1
+`
+
+func TestKeepUnsupported(t *testing.T) {
+	objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(strings.NewReader(unsupportedInput))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kokiObjs, err := ConvertKubeMapsKeepingUnsupported(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kokiMap, ok := kokiObjs[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a dictionary, got %T", kokiObjs[0])
+	}
+	expected := map[string]interface{}{
+		"$.spec.containers[name=logger].ports[0].hostIP": "10.0.0.1",
+		"$.spec.containers[name=web].ports[0].hostIP":    "127.0.0.1",
+	}
+	if !reflect.DeepEqual(kokiMap[UnsupportedKey], expected) {
+		t.Error(pretty.Diff(kokiMap[UnsupportedKey], expected))
+	}
+
+	// Round trip through YAML, since that's how short manifests are stored.
+	b, err := CanonicalYAML(kokiMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "pod:") || !strings.Contains(string(b), "\n_unsupported:\n") {
+		t.Errorf("expected the kind first and then the unsupported fields:\n%s", b)
+	}
+	kokiMaps, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(strings.NewReader(string(b)))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeObjs, err := ConvertKokiMaps(kokiMaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeObj, err := objutil.ToDictionary(kubeObjs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, container := range []string{"0", "1"} {
+		hostIP, err := objutil.AtPathIn(kubeObj, []string{"spec", "containers", container, "ports", "0", "hostIP"})
+		if err != nil || hostIP == nil {
+			t.Errorf("expected container %s to get its hostIP back, got %v", container, err)
+		}
+	}
+}
+
+func TestInjectUnsupported(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b.c"},
+			},
+		},
+	}
+
+	injected, err := InjectUnsupported(obj, map[string]interface{}{
+		"$.spec.containers[name=b.c].ports": []interface{}{"x"},
+		"$.spec.containers[0].extra.nested": true,
+		"$.spec.os":                         "linux",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "extra": map[string]interface{}{"nested": true}},
+				map[string]interface{}{"name": "b.c", "ports": []interface{}{"x"}},
+			},
+			"os": "linux",
+		},
+	}
+	if !reflect.DeepEqual(injected, expected) {
+		t.Error(pretty.Diff(injected, expected))
+	}
+
+	for _, path := range []string{"$.spec.containers[name=c].ports", "$.spec.containers[2].ports", "spec.os"} {
+		if _, err := InjectUnsupported(obj, map[string]interface{}{path: 1}); err == nil {
+			t.Errorf("expected an error for %s", path)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koki/short/compat"
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/deprecation"
+	"github.com/koki/short/util/objutil"
+)
+
+/*
+
+Warnings are problems that don't stop a conversion, e.g. a field that short syntax can't
+express or a deprecated apiVersion.
+
+Most are found by checking the Kubernetes side of a conversion: the input when converting
+to short syntax, and the output when converting to Kubernetes syntax. The checks only
+read the object, so they give the same warnings whether or not the conversion was cached.
+The rest are reported by the converters themselves (see converters.Warnings), and are
+only known when the object is actually converted.
+
+*/
+
+// Warning is a problem with an object that didn't stop its conversion.
+type Warning struct {
+	File string `json:"file,omitempty"`
+	// Document is the index of the document in the file, from 0.
+	Document *int   `json:"document,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name,omitempty"`
+	// Path is the field the warning is about, if there is one, e.g. "$.spec.template.spec.automountServiceAccountToken".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	location := fmt.Sprintf("%s (%s)", w.Kind, w.Name)
+	if len(w.File) > 0 {
+		location = fmt.Sprintf("%s in %s", location, w.File)
+		if w.Document != nil {
+			location = fmt.Sprintf("%s (document %d)", location, *w.Document)
+		}
+	}
+	if len(w.Path) > 0 {
+		return fmt.Sprintf("%s: %s: %s", location, w.Path, w.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", location, w.Message)
+}
+
+// warningChecks look for problems in a Kubernetes object. Each returns warnings with only the path and message set.
+var warningChecks = []func(kind string, obj map[string]interface{}) []Warning{
+	checkDeprecatedAPIVersion,
+	checkDeprecatedFields,
+	checkAutomountServiceAccountToken,
+}
+
+// KubeWarnings checks the Kubernetes side of a conversion, a typed object or a dictionary.
+func KubeWarnings(kubeObj interface{}) ([]Warning, error) {
+	obj, ok := kubeObj.(map[string]interface{})
+	if !ok {
+		var err error
+		obj, err = objutil.ToDictionary(kubeObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kind, name := kindAndName(obj)
+	warnings := []Warning{}
+	for _, check := range warningChecks {
+		for _, warning := range check(kind, obj) {
+			warning.Kind = kind
+			warning.Name = name
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings, nil
+}
+
+// ShortWarnings is KubeWarnings for the result of converting a short syntax document, kokiObj, to kubeObj. A short
+// document without a "version" gets the converter's default apiVersion, which isn't something the document says,
+// so it isn't warned about.
+func ShortWarnings(kokiObj map[string]interface{}, kubeObj interface{}) ([]Warning, error) {
+	warnings, err := KubeWarnings(kubeObj)
+	if err != nil || setsVersion(kokiObj) {
+		return warnings, err
+	}
+
+	kept := []Warning{}
+	for _, warning := range warnings {
+		if warning.Path != "$.apiVersion" {
+			kept = append(kept, warning)
+		}
+	}
+
+	return kept, nil
+}
+
+// setsVersion is true if a short syntax document sets its "version".
+func setsVersion(kokiObj map[string]interface{}) bool {
+	for key, val := range kokiObj {
+		if key == UnsupportedKey {
+			continue
+		}
+		if body, ok := val.(map[string]interface{}); ok {
+			if _, ok := body["version"]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkDeprecatedAPIVersion warns about apiVersions that newer releases replace or no longer serve,
+// unless the targeted release (see converters.SetKubeVersion) prefers them.
+func checkDeprecatedAPIVersion(kind string, obj map[string]interface{}) []Warning {
+	apiVersion, _ := obj["apiVersion"].(string)
+	api, isDeprecated := deprecation.ForAPI(apiVersion, kind)
+
+	var message string
+	if replacement, ok := converters.DeprecatedAPIVersion(kind, apiVersion); ok {
+		message = fmt.Sprintf("%s is deprecated, use %s", apiVersion, replacement)
+		if isDeprecated && len(api.Removed) > 0 {
+			message = fmt.Sprintf("%s is removed in Kubernetes %s, use %s", apiVersion, api.Removed, replacement)
+		}
+	} else if isDeprecated && !converters.TargetedAPIVersion(kind, apiVersion) {
+		message = api.Message()
+	} else {
+		return nil
+	}
+	if isDeprecated && api.Migratable {
+		message = fixableMessage(message)
+	}
+
+	return []Warning{{
+		Path:    "$.apiVersion",
+		Message: message,
+	}}
+}
+
+// checkDeprecatedFields warns about fields that are deprecated or removed, along with what replaces them.
+func checkDeprecatedFields(kind string, obj map[string]interface{}) []Warning {
+	warnings := []Warning{}
+	for _, finding := range deprecation.Check(obj) {
+		message := finding.Message
+		if finding.Fixable {
+			message = fixableMessage(message)
+		}
+		warnings = append(warnings, Warning{
+			Path:    finding.Path,
+			Message: message,
+		})
+	}
+
+	return warnings
+}
+
+func fixableMessage(message string) string {
+	return message + " (--fix-deprecated migrates it)"
+}
+
+// checkAutomountServiceAccountToken warns that short syntax can only say to automount the service account token.
+// Turning it off explicitly is dropped, which leaves it up to the service account.
+func checkAutomountServiceAccountToken(kind string, obj map[string]interface{}) []Warning {
+	podSpecPath, ok := compat.PodSpecPath(kind)
+	if !ok {
+		return nil
+	}
+
+	path := append(strings.Split(podSpecPath, "."), "automountServiceAccountToken")
+	if automount, err := objutil.AtPathIn(obj, path); err != nil || automount != false {
+		return nil
+	}
+
+	return []Warning{{
+		Path:    "$." + strings.Join(path, "."),
+		Message: "false is dropped, so the service account decides whether its token is mounted",
+	}}
+}
+
+// ConverterWarnings turns the warnings a converter reported about an object into Warnings,
+// given the Kubernetes side of its conversion.
+func ConverterWarnings(kubeObj interface{}, converted []converters.Warning) ([]Warning, error) {
+	if len(converted) == 0 {
+		return nil, nil
+	}
+	obj, ok := kubeObj.(map[string]interface{})
+	if !ok {
+		var err error
+		obj, err = objutil.ToDictionary(kubeObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kind, name := kindAndName(obj)
+	warnings := make([]Warning, len(converted))
+	for i, warning := range converted {
+		warnings[i] = Warning{Kind: kind, Name: name, Path: warning.Path, Message: warning.Message}
+	}
+
+	return warnings, nil
+}
+
+// ConvertKubeMapsWithWarnings is ConvertKubeMaps, and also returns the warnings for each object.
+func ConvertKubeMapsWithWarnings(objs []map[string]interface{}) ([]interface{}, [][]Warning, error) {
+	convertedObjs := make([]interface{}, len(objs))
+	warnings := make([][]Warning, len(objs))
+	for i, obj := range objs {
+		converterWarnings := &converters.Warnings{}
+		convertedObj, err := ConvertKubeMap(obj, converterWarnings)
+		if err != nil {
+			return nil, nil, err
+		}
+		convertedObjs[i] = convertedObj
+
+		warnings[i], err = objWarnings(nil, obj, converterWarnings)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return convertedObjs, warnings, nil
+}
+
+// ConvertKokiMapsWithWarnings is ConvertKokiMaps, and also returns the warnings for each object.
+func ConvertKokiMapsWithWarnings(objs []map[string]interface{}) ([]interface{}, [][]Warning, error) {
+	convertedObjs := make([]interface{}, len(objs))
+	warnings := make([][]Warning, len(objs))
+	for i, obj := range objs {
+		converterWarnings := &converters.Warnings{}
+		convertedObj, err := ConvertKokiMap(obj, converterWarnings)
+		if err != nil {
+			return nil, nil, err
+		}
+		convertedObjs[i] = convertedObj
+
+		warnings[i], err = objWarnings(obj, convertedObj, converterWarnings)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return convertedObjs, warnings, nil
+}
+
+// objWarnings returns the converter's warnings about an object, followed by those of KubeWarnings, or of
+// ShortWarnings if kokiObj, the short syntax document it was converted from, isn't nil.
+func objWarnings(kokiObj map[string]interface{}, kubeObj interface{}, converterWarnings *converters.Warnings) ([]Warning, error) {
+	warnings, err := ConverterWarnings(kubeObj, converterWarnings.List())
+	if err != nil {
+		return nil, err
+	}
+	var checked []Warning
+	if kokiObj != nil {
+		checked, err = ShortWarnings(kokiObj, kubeObj)
+	} else {
+		checked, err = KubeWarnings(kubeObj)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) == 0 {
+		return checked, nil
+	}
+
+	return append(warnings, checked...), nil
+}
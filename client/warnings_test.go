@@ -0,0 +1,111 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+func TestKubeWarnings(t *testing.T) {
+	zero := 0
+	testCases := []struct {
+		obj      map[string]interface{}
+		expected []Warning
+	}{
+		{
+			obj: map[string]interface{}{
+				"apiVersion": "extensions/v1beta1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "web"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{"automountServiceAccountToken": false},
+					},
+				},
+			},
+			expected: []Warning{
+				{Kind: "Deployment", Name: "web", Path: "$.apiVersion", Message: "extensions/v1beta1 is removed in Kubernetes 1.16, use apps/v1 (--fix-deprecated migrates it)"},
+				{Kind: "Deployment", Name: "web", Path: "$.spec.template.spec.automountServiceAccountToken", Message: "false is dropped, so the service account decides whether its token is mounted"},
+			},
+		},
+		{
+			obj: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]interface{}{"name": "web"},
+				"spec":       map[string]interface{}{"automountServiceAccountToken": true},
+			},
+			expected: []Warning{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		warnings, err := KubeWarnings(testCase.obj)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(warnings, testCase.expected) {
+			t.Errorf("case %d: %s", i, pretty.Sprint(pretty.Diff(warnings, testCase.expected)))
+		}
+	}
+
+	warning := Warning{File: "web.yaml", Document: &zero, Kind: "Deployment", Name: "web", Path: "$.apiVersion", Message: "deprecated"}
+	if s := warning.String(); s != "Deployment (web) in web.yaml (document 0): $.apiVersion: deprecated" {
+		t.Errorf("unexpected string %s", s)
+	}
+}
+
+func TestShortWarnings(t *testing.T) {
+	for _, testCase := range []struct {
+		short    string
+		expected int
+	}{
+		// The apiVersion is short's default.
+		{short: "deployment:\n  name: web\n  containers:\n  - image: nginx\n", expected: 0},
+		{short: "deployment:\n  name: web\n  version: extensions/v1beta1\n  containers:\n  - image: nginx\n", expected: 1},
+	} {
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(testCase.short), &obj); err != nil {
+			t.Fatal(err)
+		}
+		_, warnings, err := ConvertKokiMapsWithWarnings([]map[string]interface{}{obj})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings[0]) != testCase.expected {
+			t.Errorf("%s: expected %d warnings, got %v", testCase.short, testCase.expected, warnings[0])
+		}
+	}
+}
+
+func TestConvertKubeMapsWithWarnings(t *testing.T) {
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "generateName": "settings-"},
+	}
+
+	_, warnings, err := ConvertKubeMapsWithWarnings([]map[string]interface{}{obj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]Warning{{
+		{Kind: "ConfigMap", Name: "settings", Path: "$.metadata.generateName", Message: "dropped, since the object has a name"},
+	}}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Error(pretty.Sprint(pretty.Diff(warnings, expected)))
+	}
+
+	// Without a name, the generateName is the short name.
+	delete(obj["metadata"].(map[string]interface{}), "name")
+	_, warnings, err = ConvertKubeMapsWithWarnings([]map[string]interface{}{obj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings[0]) != 0 {
+		t.Errorf("unexpected warnings %s", pretty.Sprint(warnings))
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/assert"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	assertCmd = &cobra.Command{
+		Use:   "assert",
+		Short: "Check expressions against manifests",
+		Long: `Assert evaluates expressions over the short representation of manifests.
+
+Objects are addressed by kind and name, and "*" matches every entry of a map or list.
+Each failed expression is printed, and the command exits with an error.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runAssert(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Require at least two replicas of the web deployment
+  short assert -f app.short.yaml --expr 'deployment.web.replicas >= 2'
+
+  # Require a memory limit on every container of every deployment
+  short assert -f app.short.yaml --expr 'deployment.*.containers.*.mem.max != null'
+`,
+	}
+
+	// assertExprs holds the expressions that must hold for the input manifests
+	assertExprs []string
+)
+
+func init() {
+	assertCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path or url to input files to read manifests")
+	assertCmd.Flags().StringArrayVarP(&assertExprs, "expr", "e", nil, "expression that must hold for the manifests")
+}
+
+func runAssert(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(assertExprs) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one --expr is required")
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(filenames, len(filenames) == 0)
+	if err != nil {
+		return err
+	}
+
+	failures, err := assert.Check(objs, assertExprs)
+	if err != nil {
+		return err
+	}
+
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "assertion failed: %s\n", failure.Expr)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d assertions failed", len(failures), len(assertExprs))
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/drift"
+	"github.com/koki/short/impact"
+	"github.com/koki/short/owners"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/site"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	checkCmd = &cobra.Command{
+		Use:   "check -f FILE --against-cluster",
+		Short: "Report what applying short manifests would change in the cluster",
+		Long: `Check converts short manifests like apply does, applies them to the cluster with a dry run on
+the server ("kubectl apply --dry-run=server"), and compares the result to the live objects. Objects
+that would change are listed with each field that differs, and objects that don't exist yet are
+listed as added. Nothing in the cluster changes.
+
+Status and server-populated fields are left out of the comparison. Check exits with an error if any
+object would change, so it can gate a deployment pipeline.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runCheck(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", prettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # What would applying the app change?
+  short check -f app.short.yaml --against-cluster
+
+  # Check a directory against the staging cluster
+  short check -f manifests/ --against-cluster --context staging -n web
+`,
+	}
+
+	// checkAgainstCluster denotes that the manifests should be checked against the live objects in the cluster
+	checkAgainstCluster bool
+)
+
+func init() {
+	kubectlFlags.AddTo(checkCmd.Flags())
+	checkCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to short manifests to check")
+	checkCmd.Flags().BoolVar(&checkAgainstCluster, "against-cluster", false, "compare the manifests to the live objects, with a dry-run kubectl apply")
+	checkCmd.Flags().StringVarP(&profileFile, "profile", "", "", "add the labels, annotations, namespace, resources and registry of this profile to each object (default "+profile.DefaultFile+" if it exists)")
+	checkCmd.Flags().StringArrayVarP(&preHooks, "pre-hook", "", nil, "pass the short documents through this shell command before conversion, as a JSON list on stdin and stdout (repeatable)")
+	checkCmd.Flags().StringArrayVarP(&postHooks, "post-hook", "", nil, "pass the converted documents through this shell command before they're checked, as a JSON list on stdin and stdout (repeatable)")
+	addOwnersFlag(checkCmd.Flags())
+}
+
+func runCheck(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+	if !checkAgainstCluster {
+		return serrors.UsageErrorf(c.CommandPath(), "--against-cluster is required, since it's the only check so far")
+	}
+
+	kubeObjs, err := kubeObjsToApply(filenames)
+	if err != nil {
+		return err
+	}
+	o, err := owners.Load(ownersFile)
+	if err != nil {
+		return err
+	}
+
+	changes, err := drift.Check(context.Background(), kubectlFlags, kubeObjs)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("no objects would change")
+		return nil
+	}
+
+	if o != nil {
+		labels, err := labelsByID(kubeObjs)
+		if err != nil {
+			return err
+		}
+		findings := make([]owners.Finding, len(changes))
+		for i, change := range changes {
+			buf := &bytes.Buffer{}
+			err = impact.Write([]impact.Change{change}, buf)
+			if err != nil {
+				return err
+			}
+			// Objects without a namespace in the manifest get one when they're applied.
+			objLabels, ok := labels[change.ID]
+			if !ok {
+				objLabels = labels[site.ID{Kind: change.Kind, Name: change.Name}]
+			}
+			findings[i] = owners.Finding{Labels: objLabels, Text: buf.String()}
+		}
+		return reportByOwner(o, findings)
+	}
+
+	err = impact.Write(changes, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 1 {
+		return fmt.Errorf("1 object would change")
+	}
+	return fmt.Errorf("%d objects would change", len(changes))
+}
+
+// labelsByID returns the labels of each Kubernetes object, by its kind, namespace and name.
+func labelsByID(kubeObjs []interface{}) (map[site.ID]map[string]string, error) {
+	labels := map[site.ID]map[string]string{}
+	for _, kubeObj := range kubeObjs {
+		obj, err := objutil.ToDictionary(kubeObj)
+		if err != nil {
+			return nil, err
+		}
+		id := site.ID{}
+		id.Kind, _ = obj["kind"].(string)
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		id.Namespace, _ = metadata["namespace"].(string)
+		id.Name, _ = metadata["name"].(string)
+		labels[id] = objutil.Labels(obj)
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion SHELL",
+	Short: "Write the shell completion script for bash or zsh",
+	Long: `Completion writes a script that completes the commands, flags and kinds of short (e.g. for
+"short new") in bash or zsh.
+`,
+	RunE: func(c *cobra.Command, args []string) error {
+		err := runCompletion(c, args)
+		if err != nil {
+			return fmt.Errorf("%s", serrors.PrettyError(err))
+		}
+
+		return nil
+	},
+	SilenceUsage: true,
+	ValidArgs:    []string{"bash", "zsh"},
+	Example: `
+  # Load completions in the current bash shell
+  source <(short completion bash)
+
+  # Load them in every zsh shell
+  short completion zsh > "${fpath[1]}/_short"
+`,
+}
+
+func runCompletion(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return serrors.UsageErrorf(c.CommandPath(), "expected a shell (bash or zsh)")
+	}
+
+	switch args[0] {
+	case "bash":
+		return RootCmd.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return RootCmd.GenZshCompletion(os.Stdout)
+	}
+
+	return serrors.UsageErrorf(c.CommandPath(), "unexpected shell %s (bash or zsh)", args[0])
+}
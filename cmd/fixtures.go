@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/fixtures"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/transform"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	fixturesCmd = &cobra.Command{
+		Use:   "fixtures [TYPE [NAME...]]",
+		Short: "Generate golden test files from Kubernetes manifests or a live cluster",
+		Long: `Fixtures converts each Kubernetes object to short syntax and back, and writes the golden files
+the functional tests compare against: <name>.yaml, <name>.short.yaml, and <name>.rekube.yaml if the
+object doesn't convert back to the same Kubernetes object. Each object goes in the directory of its kind.
+
+With TYPE, the objects are read from the cluster with "kubectl get". Otherwise, they're read from -f or stdin.
+Status and server-populated fields are left out.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runFixtures(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Add fixtures for the deployments and services in a cluster
+  short fixtures deployments,services -n prod
+
+  # Add fixtures for the Kubernetes examples
+  short fixtures -f ../examples/staging/
+
+  # Regenerate a fixture
+  short fixtures -f testdata/pods/pod_web.yaml --force
+`,
+	}
+
+	// fixturesDir is the directory the fixtures are written to
+	fixturesDir string
+	// fixturesForce denotes that existing fixtures should be overwritten
+	fixturesForce bool
+)
+
+func init() {
+	kubectlFlags.AddTo(fixturesCmd.Flags())
+	fixturesCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests (default stdin)")
+	fixturesCmd.Flags().StringVarP(&fixturesDir, "out-dir", "", "testdata", "directory to write the fixtures to, one subdirectory per kind")
+	fixturesCmd.Flags().BoolVarP(&fixturesForce, "force", "", false, "overwrite existing fixtures")
+}
+
+func runFixtures(c *cobra.Command, args []string) error {
+	if len(args) > 0 && len(filenames) > 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "read objects either from the cluster (TYPE) or from files (-f), not both")
+	}
+
+	var objs []map[string]interface{}
+	var err error
+	if len(args) > 0 {
+		objs, err = kubectlFlags.Get(args...)
+	} else {
+		var inputs []string
+		inputs, err = parser.ExpandFilenames(filenames)
+		if err != nil {
+			return err
+		}
+
+		glog.V(3).Info("parsing input data")
+		objs, err = parser.Parse(inputs, len(inputs) == 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	kubeObjs := []map[string]interface{}{}
+	for _, obj := range objs {
+		items, err := parser.FlattenList(obj)
+		if err != nil {
+			return err
+		}
+		kubeObjs = append(kubeObjs, items...)
+	}
+	for _, obj := range kubeObjs {
+		transform.StripServerFields(obj)
+	}
+
+	generated, err := fixtures.Generate(kubeObjs)
+	if err != nil {
+		return err
+	}
+
+	paths, err := fixtures.Write(generated, fixturesDir, fixturesForce)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+	return nil
+}
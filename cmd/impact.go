@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/impact"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	impactCmd = &cobra.Command{
+		Use:   "impact",
+		Short: "Summarize which objects and fields change between two git revisions",
+		Long: `Impact converts both revisions of each changed manifest and reports the objects that were added,
+removed or changed. Changed objects list each field that differs, by its path in short syntax.
+
+Only files that changed between the revisions are read, and imports aren't resolved.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runImpact(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Summarize the last commit
+  short impact
+
+  # Summarize a branch, only looking at manifests/
+  short impact --from master --to my-branch -f manifests/
+`,
+	}
+
+	// impactFrom denotes the git revision to compare from
+	impactFrom string
+	// impactTo denotes the git revision to compare to
+	impactTo string
+)
+
+func init() {
+	impactCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "files or directories to compare (default all *.short.yaml files)")
+	impactCmd.Flags().StringVar(&impactFrom, "from", "HEAD~1", "git revision to compare from")
+	impactCmd.Flags().StringVar(&impactTo, "to", "HEAD", "git revision to compare to")
+}
+
+func runImpact(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	pathspecs := filenames
+	if len(pathspecs) == 0 {
+		pathspecs = []string{"*.short.yaml"}
+	}
+
+	changes, err := impact.CompareRevisions(impactFrom, impactTo, pathspecs)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("no objects changed")
+		return nil
+	}
+
+	return impact.Write(changes, os.Stdout)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/index"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	indexCmd = &cobra.Command{
+		Use:   "index",
+		Short: "Export manifests as a SQL script for SQLite, or load them into a SQLite database",
+		Long: `Index prints a SQL script that loads manifests into an "objects" table, one row per object.
+With --db, it runs the script with the sqlite3 command line tool instead, replacing the objects
+already in the database.
+
+The labels and body of each object are stored as JSON in short syntax, so they can be queried with SQLite's JSON functions.
+The script also creates the views "containers", "images", "unlimited_containers" and "privileged_containers".
+Kube-native manifests are converted to short syntax first.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runIndex(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Load every manifest in a directory into a SQLite database
+  short index -f manifests/ --db manifests.sqlite
+
+  # The same, with the script
+  short index -f manifests/ | sqlite3 manifests.sqlite
+
+  # Find the images in use
+  sqlite3 manifests.sqlite 'SELECT * FROM images ORDER BY uses DESC'
+`,
+	}
+
+	// indexDB is the SQLite database to load the manifests into, instead of printing the script
+	indexDB string
+)
+
+func init() {
+	indexCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	indexCmd.Flags().StringVarP(&indexDB, "db", "", "", "load the manifests into this SQLite database with the sqlite3 tool, instead of printing the SQL script")
+}
+
+func runIndex(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+	if err != nil {
+		return err
+	}
+
+	if len(indexDB) > 0 {
+		return index.Load(indexDB, kokiObjs)
+	}
+
+	return index.WriteSQL(os.Stdout, kokiObjs)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/koki/json"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/converter"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	kindsCmd = &cobra.Command{
+		Use:   "kinds",
+		Short: "List the kinds that short syntax supports",
+		Long: `Kinds lists each kind that short converts, with its short syntax key and the apiVersions that
+are converted to short syntax. Custom resources registered with plugins are included.
+
+Objects of other kinds are left in Kubernetes syntax.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runKinds(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # List the supported kinds
+  short kinds
+
+  # List them as JSON, for scripts
+  short kinds --json
+`,
+	}
+
+	// kindsJSON denotes whether to list the kinds as JSON
+	kindsJSON bool
+)
+
+func init() {
+	kindsCmd.Flags().BoolVar(&kindsJSON, "json", false, "list the kinds as JSON")
+}
+
+func runKinds(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	kinds := converter.ListSupportedKinds()
+	if kindsJSON {
+		b, err := json.MarshalIndent(kinds, "", "  ")
+		if err != nil {
+			return serrors.InvalidValueContextErrorf(err, kinds, "serializing kinds")
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tKIND\tAPI VERSIONS")
+	for _, kind := range kinds {
+		apiVersions := strings.Join(kind.APIVersions, ",")
+		if len(apiVersions) == 0 {
+			apiVersions = "-"
+		}
+		if kind.Plugin {
+			apiVersions += " (plugin)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", kind.Key, kind.Kind, apiVersions)
+	}
+
+	return w.Flush()
+}
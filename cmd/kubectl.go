@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/hook"
+	"github.com/koki/short/kubectl"
+	"github.com/koki/short/order"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/transform"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	getCmd = &cobra.Command{
+		Use:   "get TYPE [NAME...]",
+		Short: "Get objects from the cluster in short syntax",
+		Long: `Get reads objects from the cluster with "kubectl get" and prints them in short syntax.
+Status and server-populated fields are left out, so the output can be applied again.
+
+Installed as the kubectl plugin kubectl-short, this is "kubectl short get".
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runGet(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", prettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Get a deployment in short syntax
+  kubectl short get deploy web -n prod
+
+  # Get every service with a label in every namespace
+  short get services -l app=web -A --context staging
+
+  # Keep the status and server-populated fields
+  short get pod web-0 --server-fields
+`,
+	}
+
+	applyCmd = &cobra.Command{
+		Use:   "apply -f FILE [-- KUBECTL_FLAGS...]",
+		Short: "Convert short manifests and apply them to the cluster",
+		Long: `Apply converts short manifests to Kubernetes syntax and pipes them to "kubectl apply".
+Objects are applied in dependency order, as with "short -k --sort".
+Arguments after "--" are passed on to kubectl.
+
+Installed as the kubectl plugin kubectl-short, this is "kubectl short apply".
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runApply(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", prettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Apply a short manifest
+  kubectl short apply -f web.short.yaml
+
+  # Apply a directory of short manifests to another namespace, pruning what's gone
+  short apply -f manifests/ -n staging -- --prune -l app=web
+
+  # Apply each object to the context named by its cluster field
+  short apply -f clusters/ --by-cluster
+`,
+	}
+
+	// kubectlFlags are the kubeconfig, context and namespace flags passed on to kubectl
+	kubectlFlags kubectl.Flags
+	// getSelector is the label selector of the objects to get
+	getSelector string
+	// getOutput is the output format of the get command
+	getOutput string
+	// getAllNamespaces denotes that objects should be fetched from every namespace
+	getAllNamespaces bool
+	// getServerFields denotes that status and server-populated fields should be kept
+	getServerFields bool
+)
+
+func init() {
+	kubectlFlags.AddTo(getCmd.Flags())
+	getCmd.Flags().StringVarP(&getSelector, "selector", "l", "", "label selector of the objects to get")
+	getCmd.Flags().StringVarP(&getOutput, "output", "o", "yaml", "output format (yaml*|json)")
+	getCmd.Flags().BoolVarP(&getAllNamespaces, "all-namespaces", "A", false, "get objects from every namespace")
+	getCmd.Flags().BoolVarP(&getServerFields, "server-fields", "", false, "keep status and server-populated fields")
+
+	kubectlFlags.AddTo(applyCmd.Flags())
+	applyCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to short manifests to apply")
+	applyCmd.Flags().StringVarP(&profileFile, "profile", "", "", "add the labels, annotations, namespace, resources and registry of this profile to each object (default "+profile.DefaultFile+" if it exists)")
+	applyCmd.Flags().StringArrayVarP(&preHooks, "pre-hook", "", nil, "pass the short documents through this shell command before conversion, as a JSON list on stdin and stdout (repeatable)")
+	applyCmd.Flags().StringArrayVarP(&postHooks, "post-hook", "", nil, "pass the converted documents through this shell command before they're applied, as a JSON list on stdin and stdout (repeatable)")
+	applyCmd.Flags().BoolVarP(&byCluster, "by-cluster", "", false, "apply the objects of each cluster (the cluster field) to the kubeconfig context of that name, and the others to --context")
+}
+
+func runGet(c *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "the type of the objects to get is required")
+	}
+	if getOutput != "yaml" && getOutput != "json" {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected value %s for -o --output", getOutput)
+	}
+
+	if len(getSelector) > 0 {
+		args = append(args, "--selector", getSelector)
+	}
+	if getAllNamespaces {
+		if len(kubectlFlags.Namespace) > 0 {
+			return serrors.UsageErrorf(c.CommandPath(), "--all-namespaces and --namespace can't be used together")
+		}
+		args = append(args, "--all-namespaces")
+	}
+	objs, err := kubectlFlags.Get(args...)
+	if err != nil {
+		return err
+	}
+
+	if !getServerFields {
+		for _, obj := range objs {
+			transform.StripServerFields(obj)
+		}
+	}
+
+	kokiObjs, err := client.ConvertKubeMaps(objs)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if getOutput == "json" {
+		err = client.WriteObjsToJSONStream(kokiObjs, buf)
+	} else {
+		err = client.WriteObjsToYamlStream(kokiObjs, buf)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(buf.String())
+	return nil
+}
+
+func runApply(c *cobra.Command, args []string) error {
+	if len(args) > 0 && c.ArgsLenAtDash() != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q, pass kubectl flags after --", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	kubeObjs, err := kubeObjsToApply(filenames)
+	if err != nil {
+		return err
+	}
+
+	if !byCluster {
+		return applyObjs(kubectlFlags, kubeObjs, args)
+	}
+
+	clusters, groups, err := client.GroupByCluster(kubeObjs)
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		flags := kubectlFlags
+		if len(cluster) > 0 {
+			flags.Context = cluster
+		}
+		if len(clusters) > 1 {
+			fmt.Fprintf(os.Stderr, "applying %d objects to %s\n", len(groups[cluster]), contextName(flags))
+		}
+		err = applyObjs(flags, groups[cluster], args)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kubeObjsToApply converts short manifests to Kubernetes objects, in the order they're applied.
+func kubeObjsToApply(filenames []string) ([]interface{}, error) {
+	files, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	kokiModules, unsupported, apps, err := loadKokiFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeObjs, converterWarnings, err := convertKokiModules(kokiModules, unsupported, apps)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := moduleWarnings(kokiModules, kubeObjs, converterWarnings)
+	if err != nil {
+		return nil, err
+	}
+	reportWarnings(warnings, os.Stderr)
+
+	kubeObjs, err = applyProfile(kubeObjs)
+	if err != nil {
+		return nil, err
+	}
+	kubeObjs, err = hook.RunOnObjs(postHooks, hook.Post, hook.Kube, kubeObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	return order.ForApply(kubeObjs)
+}
+
+// applyObjs pipes the objects to "kubectl apply", with the kubectl flags after "--".
+func applyObjs(flags kubectl.Flags, kubeObjs []interface{}, args []string) error {
+	buf := &bytes.Buffer{}
+	err := client.WriteObjsToYamlStream(kubeObjs, buf)
+	if err != nil {
+		return err
+	}
+
+	return flags.Run(buf, append([]string{"apply", "-f", "-"}, args...)...)
+}
+
+func contextName(flags kubectl.Flags) string {
+	if len(flags.Context) == 0 {
+		return "the current context"
+	}
+
+	return fmt.Sprintf("context %s", flags.Context)
+}
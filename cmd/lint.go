@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/lint"
+	"github.com/koki/short/owners"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	lintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Check the references between manifests",
+		Long: `Lint checks short manifests against best-practice rules: resource limits, pinned image tags,
+liveness probes, privileged containers and host_path volumes.
+
+It also converts them to Kubernetes objects and checks the references between them (the
+"references" rule): objects that use ConfigMaps, Secrets, PVCs or ServiceAccounts that aren't in
+the input, Services whose selector matches no pods, and Ingresses that route to Services that
+aren't in the input.
+
+Each warning names the object, the path of the problem and the rule. Paths are in short syntax,
+except for the references rule, which reports them in Kubernetes syntax.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runLint(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Run every rule on a directory of manifests
+  short lint -f manifests/
+
+  # Only check references and image tags
+  short lint -f manifests/ --rule references,latest-tag
+
+  # Skip a rule
+  short lint -f manifests/ --skip-rule liveness-probe
+`,
+	}
+
+	// lintRules are the rules to run, or empty for all of them
+	lintRules []string
+	// lintSkipRules are the rules not to run
+	lintSkipRules []string
+	// lintListRules denotes that the rules should be listed instead of run
+	lintListRules bool
+)
+
+func init() {
+	lintCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	lintCmd.Flags().StringSliceVar(&lintRules, "rule", nil, "rules to run (default all of them)")
+	lintCmd.Flags().StringSliceVar(&lintSkipRules, "skip-rule", nil, "rules not to run")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "list the rules")
+	addOwnersFlag(lintCmd.Flags())
+}
+
+func runLint(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if lintListRules {
+		for _, name := range lint.RuleNames() {
+			description := "references between objects should resolve"
+			if rule, ok := lint.Rules[name]; ok {
+				description = rule.Description
+			}
+			fmt.Printf("%s\t%s\n", name, description)
+		}
+		return nil
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	files, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	rules, err := lint.SelectRules(lintRules, lintSkipRules)
+	if err != nil {
+		return err
+	}
+	o, err := owners.Load(ownersFile)
+	if err != nil {
+		return err
+	}
+
+	warnings, err := lint.Files(files, rules)
+	if err != nil {
+		return err
+	}
+	if len(warnings) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+
+	if o != nil {
+		findings := make([]owners.Finding, len(warnings))
+		for i, warning := range warnings {
+			findings[i] = owners.Finding{File: warning.File, Labels: warning.Labels, Text: fmt.Sprintf("%s: %s", warning.File, warning)}
+		}
+		return reportByOwner(o, findings)
+	}
+
+	for _, warning := range warnings {
+		fmt.Printf("%s: %s\n", warning.File, warning)
+	}
+
+	return fmt.Errorf("%d problems found", len(warnings))
+}
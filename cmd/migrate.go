@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/migrate"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert Kubernetes manifests from one API version to another",
+		Long: `Migrate converts each Kubernetes object with the --from apiVersion to short syntax and back,
+targeting the --to apiVersion. Other objects are written unchanged.
+
+Fields the new API version requires, like a Deployment's selector, are filled in. Fields whose
+default changed between the API versions are set to the old default, so the objects behave the same.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runMigrate(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Move Deployments, DaemonSets and ReplicaSets to apps/v1
+  short migrate --from extensions/v1beta1 --to apps/v1 -f manifests/
+
+  # Read from stdin
+  cat deployment.yaml | short migrate --from apps/v1beta2 --to apps/v1
+`,
+	}
+
+	// migrateFrom denotes the apiVersion of the objects to migrate
+	migrateFrom string
+	// migrateTo denotes the apiVersion to migrate objects to
+	migrateTo string
+)
+
+func init() {
+	migrateCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests (default stdin)")
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "apiVersion of the objects to migrate, e.g. extensions/v1beta1")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "apiVersion to migrate objects to, e.g. apps/v1")
+}
+
+func runMigrate(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(migrateFrom) == 0 || len(migrateTo) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "both --from and --to are required")
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := migrate.Migrate(objs, migrateFrom, migrateTo)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	err = client.WriteObjsToYamlStream(migrated, buf)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/scaffold"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	newCmd = &cobra.Command{
+		Use:   "new KIND [NAME]",
+		Short: "Write a minimal short manifest of a kind to start from",
+		Long: `New writes a minimal short manifest of a kind, e.g. deployment, with its values filled in
+from --set, from the answers to prompts with --interactive, or from their defaults.
+
+Values without a default that aren't given are left as ${param} template holes, which can be
+filled later with "short -k --set param=value".
+
+Kinds: ` + strings.Join(scaffold.Kinds(), ", ") + `
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runNew(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		ValidArgs:    scaffold.Kinds(),
+		Example: `
+  # Start a deployment, and fill in the image when converting it
+  short new deployment web > web.short.yaml
+  short -k -f web.short.yaml --set image=nginx:1.15
+
+  # Give the values on the command line
+  short new service web --set port=443 --set target_port=8443
+
+  # Answer a prompt for each value
+  short new stateful_set db -i
+`,
+	}
+
+	// newValues are "param=value" pairs that fill the params of the new manifest
+	newValues []string
+	// newInteractive denotes that the values of the params that aren't set should be prompted for
+	newInteractive bool
+)
+
+func init() {
+	newCmd.Flags().StringArrayVarP(&newValues, "set", "", nil, "fill a param of the manifest with a value (param=value, repeatable)")
+	newCmd.Flags().BoolVarP(&newInteractive, "interactive", "i", false, "prompt for the value of each param that isn't set")
+}
+
+func runNew(c *cobra.Command, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return serrors.UsageErrorf(c.CommandPath(), "expected a kind (%s) and an optional name", strings.Join(scaffold.Kinds(), ", "))
+	}
+	s, ok := scaffold.ForKind(args[0])
+	if !ok {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected kind %s (%s)", args[0], strings.Join(scaffold.Kinds(), ", "))
+	}
+
+	values := map[string]string{}
+	if len(args) == 2 {
+		values["name"] = args[1]
+	}
+	for _, newValue := range newValues {
+		segments := strings.SplitN(newValue, "=", 2)
+		if len(segments) != 2 || len(segments[0]) == 0 {
+			return serrors.UsageErrorf(c.CommandPath(), "expected param=value for --set, got %s", newValue)
+		}
+		values[segments[0]] = segments[1]
+	}
+
+	if newInteractive {
+		err := s.Prompt(values, os.Stdin, os.Stderr)
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := s.Fill(values)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(b))
+	return nil
+}
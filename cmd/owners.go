@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/koki/short/owners"
+)
+
+// addOwnersFlag adds --owners to a command that reports findings.
+func addOwnersFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&ownersFile, "owners", "", "group findings by the team that owns them, as declared in this file, and fail only for teams over their max_findings (default "+owners.DefaultFile+" if it exists)")
+}
+
+// reportByOwner prints the findings grouped by team, and returns an error if a team has more than its max_findings.
+func reportByOwner(o *owners.Owners, findings []owners.Finding) error {
+	groups := o.Group(findings)
+	err := owners.Write(groups, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	return owners.Check(groups)
+}
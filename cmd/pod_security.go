@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/converter"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/podsecurity"
+	"github.com/koki/short/types"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	podSecurityCmd = &cobra.Command{
+		Use:   "pod-security [NAMESPACE...]",
+		Short: "Map PodSecurityPolicies to Pod Security admission levels",
+		Long: `Pod-security maps each PodSecurityPolicy in the input to the most restrictive Pod Security
+Standard (privileged, baseline or restricted) that still admits every pod the policy admits,
+and says why it isn't more restrictive.
+
+Given namespaces, it writes them as short Namespaces instead, with the Pod Security labels
+for the least restrictive of the policies: pods in the namespace may be using any of them.
+Unless that's restricted, the namespace also warns about pods that restricted wouldn't admit.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runPodSecurity(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Show the level of each policy
+  short pod-security -f policies/
+
+  # Write the labels for the namespaces that use these policies
+  short pod-security -f policies/restricted.yaml -f policies/baseline.yaml shop checkout
+`,
+	}
+)
+
+func init() {
+	podSecurityCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests, in Kubernetes or short syntax (default stdin)")
+}
+
+func runPodSecurity(c *cobra.Command, namespaces []string) error {
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if len(namespaces) == 0 {
+		fmt.Fprintln(w, "POLICY\tLEVEL\tREASONS")
+	}
+	levels := []string{}
+	for _, obj := range objs {
+		if _, ok := obj["pod_security_policy"]; ok {
+			kubeObj, err := converter.ConvertOneToKubeNative(obj)
+			if err != nil {
+				return err
+			}
+			obj, err = objutil.ToDictionary(kubeObj)
+			if err != nil {
+				return err
+			}
+		}
+		if obj["kind"] != "PodSecurityPolicy" {
+			continue
+		}
+
+		level, reasons := podsecurity.Level(obj)
+		levels = append(levels, level)
+		if len(namespaces) == 0 {
+			metadata, _ := obj["metadata"].(map[string]interface{})
+			fmt.Fprintf(w, "%s\t%s\t%s\n", metadata["name"], level, strings.Join(reasons, "; "))
+		}
+	}
+	if len(levels) == 0 {
+		return serrors.InvalidValueErrorf(filenames, "no PodSecurityPolicies in the input")
+	}
+	if len(namespaces) == 0 {
+		return w.Flush()
+	}
+
+	level := podsecurity.LeastRestrictive(levels)
+	kokiObjs := []interface{}{}
+	for _, namespace := range namespaces {
+		kokiNamespace := &types.NamespaceWrapper{}
+		kokiNamespace.Namespace.Name = namespace
+		kokiNamespace.Namespace.PodSecurity = &types.PodSecurity{Enforce: level}
+		if level != types.PodSecurityRestricted {
+			kokiNamespace.Namespace.PodSecurity.Warn = types.PodSecurityRestricted
+		}
+		kokiObjs = append(kokiObjs, kokiNamespace)
+	}
+
+	buf := &bytes.Buffer{}
+	err = client.WriteObjsToYamlStream(kokiObjs, buf)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/project"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	projectsCmd = &cobra.Command{
+		Use:   "projects",
+		Short: "List the manifest projects in a monorepo",
+		Long: `Projects lists the independent sets of manifests under the projects root (see '--projects-root').
+
+A directory is a project root if it has a short.project.yaml file, or else if it's the topmost directory
+that directly contains *.short.yaml files.
+
+Pass a project's name (or directory) to any command with '--project' to run it on that project's manifests.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runProjects(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", prettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # List the projects in the current repository
+  short projects
+
+  # Convert only the payments project's manifests
+  short -k --project payments
+
+  # Run any other command on one project
+  short table --project payments --columns kind,name
+`,
+	}
+
+	// projectName denotes the project that commands should run on
+	projectName string
+
+	// projectsRoot denotes the directory to discover projects in
+	projectsRoot string
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&projectName, "project", "", "run on the manifests of this project (see the projects command)")
+	RootCmd.PersistentFlags().StringVar(&projectsRoot, "projects-root", ".", "directory to discover projects in")
+	RootCmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		err := applyProject(c)
+		if err != nil {
+			return fmt.Errorf("%s", prettyError(err))
+		}
+
+		return nil
+	}
+}
+
+func runProjects(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	projects, err := project.Discover(projectsRoot)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDIR\tFOUND BY")
+	for _, p := range projects {
+		foundBy := "convention"
+		if p.Configured {
+			foundBy = project.ConfigFilename
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Dir, foundBy)
+	}
+
+	return w.Flush()
+}
+
+// applyProject points the input files, transforms and vendor directories at the selected project,
+// unless they're given on the command line.
+func applyProject(c *cobra.Command) error {
+	if len(projectName) == 0 || c == projectsCmd {
+		return nil
+	}
+
+	projects, err := project.Discover(projectsRoot)
+	if err != nil {
+		return err
+	}
+	p, err := project.Find(projects, projectName)
+	if err != nil {
+		return err
+	}
+	glog.V(3).Infof("using project %s in %s", p.Name, p.Dir)
+
+	if len(filenames) == 0 {
+		filenames, err = p.Files()
+		if err != nil {
+			return err
+		}
+		if len(filenames) == 0 {
+			return serrors.InvalidValueErrorf(p.Name, "project (%s) has no manifests", p.Name)
+		}
+	}
+
+	if len(transformsFile) == 0 {
+		transformsFile = p.Path(p.Transforms)
+	}
+	if len(vendorDir) == 0 && len(p.VendorDir) > 0 {
+		vendorDir = p.Vendor()
+	}
+	if !vendorCmd.Flags().Changed("output") {
+		vendorOutputDir = p.Vendor()
+	}
+	if !verifyLockCmd.Flags().Changed("dir") {
+		verifyLockDir = p.Vendor()
+	}
+
+	return nil
+}
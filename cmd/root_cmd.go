@@ -4,14 +4,28 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 
+	"github.com/koki/short/cache"
 	"github.com/koki/short/client"
+	"github.com/koki/short/comments"
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/defaults"
+	"github.com/koki/short/gitops"
+	"github.com/koki/short/hook"
+	"github.com/koki/short/order"
 	"github.com/koki/short/parser"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/secretcrypt"
+	"github.com/koki/short/sourcemap"
+	"github.com/koki/short/transform"
+	"github.com/koki/short/util/objutil"
 	serrors "github.com/koki/structurederrors"
 )
 
@@ -25,12 +39,17 @@ Full documentation available at https://docs.koki.io/short
 `,
 		RunE: func(c *cobra.Command, args []string) error {
 			err := short(c, args)
+			if err != nil && errorFormat == "json" {
+				c.SilenceErrors = true
+				writeErrorRecords(err, os.Stderr)
+				return err
+			}
 			if err != nil && !verboseErrors {
 				fmt.Fprintln(os.Stderr, "Use flag '--verbose-errors' for more detailed error info.")
 			}
 
 			if err != nil {
-				return fmt.Errorf(serrors.PrettyError(err))
+				return fmt.Errorf(prettyError(err))
 			}
 
 			return nil
@@ -51,16 +70,52 @@ Full documentation available at https://docs.koki.io/short
   short --kube-native -f pod_short.yaml
   short -k -f pod_short.yaml
 
+  # Convert each document of a mixed stream in the other direction
+  cat pod.yaml pod_short.yaml | short -
+
   # Output to file
   short -f pod.yaml > pod_short.yaml
 
-  # Output as yaml* or json
+  # Output as yaml*, json, or one JSON object per line
   short -f pod.yaml -o json
+  short -f manifests.yaml -o jsonl | jq .
+
+  # Write one file per resource (e.g. deployment-web.yaml) to a directory
+  short -f manifests.yaml -o split --out-dir short/
+
+  # Re-apply a live object without its status and server-populated fields
+  kubectl get deployment web -o yaml | short -k --apply-ready -
+
+  # Convert for a release branch that still deploys to Kubernetes 1.8
+  short -k --as-of 1.8 -f deployment_short.yaml
+
+  # Fill the ${...} holes of a short manifest
+  short -k --set tag=v1.2 --env --strict -f deployment_short.yaml
+
+  # Apply a directory of short manifests again on every save
+  short -k --watch --apply -f manifests/ --context minikube
+
+  # Use the apiVersions preferred by Kubernetes 1.9 (e.g. apps/v1 Deployments)
+  short -k --kube-version 1.9 -f deployment_short.yaml
 `,
 	}
 
 	// kubeNative denotes that the conversion must output in kubernetes native syntax
 	kubeNative bool
+	// redactSecrets denotes that Secret data and sensitive env var values should be replaced with placeholders
+	redactSecrets bool
+	// redactEnv are the patterns (matched against "NAME=value") of the env vars that --redact-secrets redacts
+	redactEnv []string
+	// redaction is what --redact-secrets redacts, or nil
+	redaction *transform.Redaction
+	// secretOptions choose how Secrets are kept encrypted with SOPS or Sealed Secrets
+	secretOptions secretcrypt.Options
+	// fixDeprecated denotes that deprecated apiVersions and fields should be migrated to their replacements, where short can
+	fixDeprecated bool
+	// showSummary denotes that a summary of the conversion (kinds, dropped fields and sizes) should be written to stderr
+	showSummary bool
+	// shortNative denotes that the conversion must output in short syntax, instead of detecting the direction of each document
+	shortNative bool
 	// filenames holds the input files that are to be converted to shorthand or kuberenetes native syntax
 	filenames []string
 	// output denotes the destination of the converted data
@@ -71,6 +126,89 @@ Full documentation available at https://docs.koki.io/short
 	verboseErrors bool
 	// debugImportsDepth is the number of levels of imports to output debug info for
 	debugImportsDepth int
+	// transformsFile is the path to a config of field transforms to apply to the converted data
+	transformsFile string
+	// onlyPaths are the field paths to keep in each output object, leaving out everything else
+	onlyPaths []string
+	// keepPaths are more onlyPaths, from --keep
+	keepPaths []string
+	// explode denotes that the output should have one line per leaf value, addressed by its full path
+	explode bool
+	// implode denotes that the input is in exploded form and should be reconstructed without conversion
+	implode bool
+	// vendorDir is the directory written by the vendor command, to read imports from
+	vendorDir string
+	// applyReady denotes that status and server-populated fields should be left out, so the output can be applied again
+	applyReady bool
+	// kubeVersion is the Kubernetes release whose preferred apiVersions are used when a manifest doesn't set one
+	kubeVersion string
+	// asOf is the Kubernetes release that conversions should behave as, including which apiVersions and fields it has
+	asOf string
+	// cacheLocation is the conversion cache: a directory, an http(s):// URL or a gs:// bucket
+	cacheLocation string
+	// partial denotes that unsupported fields and kinds should be left out and reported, instead of failing the conversion
+	partial bool
+	// partialReport is the file to write the fields left out by a partial conversion to
+	partialReport string
+	// partialComments denotes that the fields left out by a partial conversion should be listed as comments in the output
+	partialComments bool
+	// keepUnsupported denotes that fields short doesn't support should be kept in an "_unsupported" section, instead of failing the conversion
+	keepUnsupported bool
+	// keepComments denotes that the comments of short manifests should be kept in an annotation, so converting back restores them
+	keepComments bool
+	// ownersFile declares the teams that own manifests, to group the findings of lint, validate and check by
+	ownersFile string
+	// profileFile is the organization profile of labels, annotations and other settings for converted objects
+	profileFile string
+	// explicitDefaults denotes that Kubernetes output should include the defaults the API server would fill in
+	explicitDefaults bool
+	// humanizeUnits denotes that short output should render durations and quantities in friendlier units
+	humanizeUnits bool
+	// provenance denotes that converted objects should be annotated with the short version and the manifest they came from
+	provenance bool
+	// preHooks and postHooks are shell commands that change the documents before and after conversion
+	preHooks  []string
+	postHooks []string
+	// sourceMapFile is the file to write a source map to, relating each line of the output to the short field it comes from
+	sourceMapFile string
+	// parallelism is the number of documents to convert at once, or 0 for one per CPU
+	parallelism int
+	// setValues are "key=value" pairs that fill the ${key} template holes of the input
+	setValues []string
+	// useEnv denotes that environment variables should fill the template holes that aren't set otherwise
+	useEnv bool
+	// strictVariables denotes that template holes that nothing fills should fail the conversion. It's the default, unless keepUnresolved is set
+	strictVariables bool
+	// keepUnresolved denotes that template holes that nothing fills should be left as they are, instead of failing the conversion
+	keepUnresolved bool
+	// sortOrder is how the output is ordered: "apply" so it applies cleanly, e.g. Namespaces and ConfigMaps before the Deployments
+	// that use them, or "name" to group it by kind and sort it by namespace and name. It's "" to keep the input order
+	sortOrder string
+	// watchFiles denotes that the input files should be converted again whenever they change
+	watchFiles bool
+	// watchInterval is how often watched files are checked for changes
+	watchInterval time.Duration
+	// watchApply denotes that watched files should be applied to the cluster with kubectl instead of printed
+	watchApply bool
+	// outDir is the directory that -o split writes to, or that watched files are converted into, one manifest per input file
+	outDir string
+	// gitopsOptions say which GitOps controller's objects to wrap the -o split directory in, and where it is in git
+	gitopsOptions gitops.Options
+	// byCluster denotes that -o split writes the objects of each cluster to a subdirectory named after it, and that
+	// apply applies them to the kubeconfig context of that name
+	byCluster bool
+	// conversionCache is opened from cacheLocation, or nil if there's no cache
+	conversionCache *cache.Cache
+	// decryptedFiles are the input files with documents that were decrypted or unsealed. Their conversions skip
+	// the conversion cache, which may be shared, so that it never holds their plaintext.
+	decryptedFiles = map[string]bool{}
+	// errorFormat is how errors are written: text, or json for one record per line
+	errorFormat string
+	// addLabels and addAnnotations are "key=value" pairs to add to every object, from --add-label and --add-annotation
+	addLabels      []string
+	addAnnotations []string
+	// common is what's added to every object: the parsed labels and annotations, and the name prefix and suffix
+	common transform.Common
 )
 
 const (
@@ -81,11 +219,68 @@ const (
 func init() {
 	// local flags to root command
 	RootCmd.Flags().BoolVarP(&kubeNative, "kube-native", "k", false, "convert to kube-native syntax")
-	RootCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path or url to input files to read manifests")
-	RootCmd.Flags().StringVarP(&output, "output", "o", "yaml", "output format (yaml*|json)")
+	RootCmd.Flags().BoolVarP(&redactSecrets, "redact-secrets", "", false, "replace the data of Secrets, and the values of env vars that look like credentials, with placeholders")
+	RootCmd.Flags().StringArrayVarP(&redactEnv, "redact-env", "", nil, "with --redact-secrets, redact the env vars that match this regular expression, as NAME=value, instead of those with names like PASSWORD or TOKEN (repeatable)")
+	RootCmd.Flags().BoolVarP(&secretOptions.SOPS, "sops", "", false, "with -k, encrypt the data of Secrets with sops; otherwise, decrypt documents that sops encrypted")
+	RootCmd.Flags().StringVarP(&secretOptions.SealCert, "seal-cert", "", "", "with -k, turn Secrets into SealedSecrets with this Sealed Secrets controller certificate (uses kubeseal)")
+	RootCmd.Flags().StringVarP(&secretOptions.UnsealKey, "unseal-key", "", "", "turn SealedSecrets back into Secrets with this Sealed Secrets controller private key (uses kubeseal)")
+	RootCmd.Flags().BoolVarP(&fixDeprecated, "fix-deprecated", "", false, "migrate deprecated apiVersions and fields to their replacements, where it doesn't change what the objects do")
+	RootCmd.Flags().BoolVarP(&showSummary, "summary", "", false, "write the number of converted objects of each kind, the dropped fields and the size reduction to stderr")
+	RootCmd.Flags().BoolVarP(&shortNative, "short-native", "", false, "convert to short syntax, instead of converting short syntax documents to kube-native syntax")
+	RootCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	RootCmd.Flags().StringVarP(&output, "output", "o", "yaml", "output format (yaml*|bundle|json|jsonl|split, or toml and hcl in builds with -tags toml and -tags hcl)")
 	RootCmd.Flags().BoolVarP(&dryRun, "dry-run", "r", false, "do not invoke any installers")
 	RootCmd.Flags().BoolVarP(&verboseErrors, "verbose-errors", "", false, "include more information in errors")
+	RootCmd.Flags().StringVarP(&errorFormat, "error-format", "", "text", "error format (text*|json): json writes one record per error to stderr, with its file, document, kind, name and field path")
 	RootCmd.Flags().IntVarP(&debugImportsDepth, "debug-imports-depth", "", defaultDebugImportsDepth, "how many levels of imports to output debug info for")
+	RootCmd.Flags().BoolVarP(&explode, "explode", "", false, "output one line per value with its full path (for diff/grep)")
+	RootCmd.Flags().BoolVarP(&implode, "implode", "", false, "reconstruct documents from exploded input")
+	RootCmd.Flags().StringVarP(&transformsFile, "transforms", "", "", "path to a file of field transforms (drop, hash, redact) to apply to the output")
+	RootCmd.Flags().StringArrayVarP(&onlyPaths, "only", "", nil, "keep only the fields at this path in each output object, e.g. deployment.containers (repeatable)")
+	RootCmd.Flags().StringArrayVarP(&keepPaths, "keep", "", nil, "same as --only")
+	RootCmd.Flags().StringArrayVarP(&addLabels, "add-label", "", nil, "add a label (key=value) to every object, its pod template and its selectors (repeatable)")
+	RootCmd.Flags().StringArrayVarP(&addAnnotations, "add-annotation", "", nil, "add an annotation (key=value) to every object and its pod template (repeatable)")
+	RootCmd.Flags().StringVarP(&common.NamePrefix, "name-prefix", "", "", "prepend to the name of every object")
+	RootCmd.Flags().StringVarP(&common.NameSuffix, "name-suffix", "", "", "append to the name of every object")
+	RootCmd.Flags().StringVarP(&vendorDir, "vendor-dir", "", "", "read locked imports from this directory, and pin images to their locked digests (see the vendor command)")
+	RootCmd.Flags().BoolVarP(&applyReady, "apply-ready", "", false, "leave out status and server-populated fields (uid, resourceVersion, ...) so the output can be applied again")
+	RootCmd.Flags().BoolVarP(&applyReady, "no-status", "", false, "same as --apply-ready")
+	RootCmd.Flags().StringVarP(&asOf, "as-of", "", "", "convert as for an older Kubernetes release, e.g. 1.8: use its default apiVersions and reject newer apiVersions and fields")
+	RootCmd.Flags().BoolVarP(&partial, "partial", "", false, "convert unsupported kinds and fields to short syntax as far as possible, and report what was left out")
+	RootCmd.Flags().StringVarP(&partialReport, "partial-report", "", "", "write the fields left out by --partial to this file (yaml)")
+	RootCmd.Flags().BoolVarP(&partialComments, "partial-comments", "", false, "list the fields left out by --partial as comments in the output")
+	RootCmd.Flags().BoolVarP(&keepUnsupported, "keep-unsupported", "", false, "keep fields short doesn't support in an \"_unsupported\" section of each short manifest, so converting back restores them")
+	RootCmd.Flags().BoolVarP(&keepComments, "keep-comments", "", false, "with -k, keep the comments of short manifests in an annotation, so converting back to short syntax restores them")
+	RootCmd.Flags().StringVarP(&profileFile, "profile", "", "", "with -k, add the labels, annotations, namespace, resources and registry of this profile to each object (default "+profile.DefaultFile+" if it exists)")
+	RootCmd.Flags().BoolVarP(&explicitDefaults, "explicit-defaults", "", false, "with -k, fill in the defaults the API server would set (restartPolicy, protocol, imagePullPolicy, ...)")
+	RootCmd.Flags().BoolVarP(&humanizeUnits, "humanize", "", false, "in short output, write durations and resource quantities in friendlier units, e.g. 1m30s and 1.5Gi")
+	RootCmd.Flags().BoolVarP(&provenance, "provenance", "", false, "with -k, annotate each object with the short version, its source file (or stdin) and a hash of the source")
+	RootCmd.Flags().StringArrayVarP(&preHooks, "pre-hook", "", nil, "pass the input documents through this shell command before conversion, as a JSON list on stdin and stdout (repeatable)")
+	RootCmd.Flags().StringArrayVarP(&postHooks, "post-hook", "", nil, "pass the converted documents through this shell command, as a JSON list on stdin and stdout (repeatable)")
+	RootCmd.Flags().StringVarP(&sourceMapFile, "source-map", "", "", "with -k, write a source map (json) to this file, relating each output line to the short field and line it comes from")
+	RootCmd.Flags().IntVarP(&parallelism, "parallelism", "", 0, "number of documents to convert at once (default one per CPU)")
+	RootCmd.Flags().StringArrayVarP(&setValues, "set", "", nil, "fill the ${key} template holes of the input with a value (key=value, repeatable)")
+	RootCmd.Flags().BoolVarP(&useEnv, "env", "", false, "fill template holes that aren't set otherwise with environment variables")
+	RootCmd.Flags().BoolVarP(&strictVariables, "strict", "", false, "fail if a template hole isn't filled (the default, unless --keep-unresolved is given)")
+	RootCmd.Flags().BoolVarP(&keepUnresolved, "keep-unresolved", "", false, "leave template holes that nothing fills as they are, with a warning, instead of failing")
+	RootCmd.Flags().StringVarP(&sortOrder, "sort", "", "", "order the output: apply (the default for a bare --sort, -k only) so it applies cleanly: namespaces, CRDs, RBAC, config, workloads, then webhooks; or name to group it by kind and sort it by namespace and name")
+	RootCmd.Flags().Lookup("sort").NoOptDefVal = "apply"
+	RootCmd.Flags().BoolVarP(&watchFiles, "watch", "", false, "with -k, convert each input file again whenever it changes")
+	RootCmd.Flags().DurationVarP(&watchInterval, "watch-interval", "", time.Second, "how often --watch checks the input files for changes")
+	RootCmd.Flags().BoolVarP(&watchApply, "apply", "", false, "with --watch, apply each changed file to the cluster with kubectl")
+	RootCmd.Flags().StringVarP(&outDir, "out-dir", "", "", "directory for -o split, or for --watch to write each changed file's manifest to")
+	RootCmd.Flags().StringVarP(&gitopsOptions.Tool, "gitops", "", "", "with -k -o split, also write the objects that sync --out-dir from git with this GitOps tool (argocd|flux), next to it")
+	RootCmd.Flags().StringVarP(&gitopsOptions.Name, "gitops-name", "", "", "name of the --gitops objects (default the name of --out-dir)")
+	RootCmd.Flags().StringVarP(&gitopsOptions.Repo, "gitops-repo", "", "", "URL of the git repository for --gitops (default the origin remote of the clone that holds --out-dir)")
+	RootCmd.Flags().StringVarP(&gitopsOptions.Path, "gitops-path", "", "", "path of --out-dir in the git repository for --gitops (default its path in the clone)")
+	RootCmd.Flags().StringVarP(&gitopsOptions.Revision, "gitops-revision", "", "", "branch for --gitops to sync (default the clone's current branch)")
+	RootCmd.Flags().BoolVarP(&byCluster, "by-cluster", "", false, "with -o split, write the objects of each cluster (the cluster field or metadata.clusterName) to a subdirectory named after it")
+	kubectlFlags.AddTo(RootCmd.Flags())
+	// The namespace flag also moves the converted objects, so they match what --apply uses.
+	RootCmd.Flags().Lookup("namespace").Usage = "move every namespaced object to this namespace, along with role binding subjects, webhook services and volume claims in the namespaces it leaves"
+	RootCmd.Flags().StringVarP(&cacheLocation, "cache", "", "", "reuse conversion results from a content-addressed cache: a directory, an http(s):// URL or a gs:// bucket")
+	RootCmd.Flags().StringVarP(&cacheLocation, "cache-dir", "", "", "same as --cache")
+	RootCmd.Flags().StringVarP(&kubeVersion, "kube-version", "", "", "target Kubernetes release for default apiVersions, e.g. 1.9 (one of 1.7, 1.8, 1.9, 1.10)")
 
 	// parse the go default flagset to get flags for glog and other packages in future
 	RootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
@@ -97,11 +292,50 @@ func init() {
 	flag.CommandLine.Parse([]string{})
 
 	RootCmd.AddCommand(versionCmd)
+	RootCmd.AddCommand(assertCmd)
+	RootCmd.AddCommand(tableCmd)
+	RootCmd.AddCommand(indexCmd)
+	RootCmd.AddCommand(siteCmd)
+	RootCmd.AddCommand(vendorCmd)
+	RootCmd.AddCommand(verifyLockCmd)
+	RootCmd.AddCommand(tuiCmd)
+	RootCmd.AddCommand(projectsCmd)
+	RootCmd.AddCommand(impactCmd)
+	RootCmd.AddCommand(migrateCmd)
+	RootCmd.AddCommand(unusedCmd)
+	RootCmd.AddCommand(validateCmd)
+	RootCmd.AddCommand(lintCmd)
+	RootCmd.AddCommand(getCmd)
+	RootCmd.AddCommand(applyCmd)
+	RootCmd.AddCommand(setImageCmd)
+	RootCmd.AddCommand(fixturesCmd)
+	RootCmd.AddCommand(podSecurityCmd)
+	RootCmd.AddCommand(newCmd)
+	RootCmd.AddCommand(completionCmd)
+	RootCmd.AddCommand(kindsCmd)
+	RootCmd.AddCommand(checkCmd)
 }
 
 func short(c *cobra.Command, args []string) error {
 	var err error
 	serrors.SetVerboseErrors(verboseErrors)
+	if len(asOf) > 0 {
+		asOf, err = converters.ParseKubeVersion(asOf)
+		if err != nil {
+			return err
+		}
+		if len(kubeVersion) == 0 {
+			kubeVersion = asOf
+		}
+	}
+	err = converters.SetKubeVersion(kubeVersion)
+	if err != nil {
+		return err
+	}
+	err = openConversionCache()
+	if err != nil {
+		return err
+	}
 	// validate that the user used the command correctly
 	glog.V(3).Infof("validating command %q", args)
 
@@ -119,8 +353,71 @@ func short(c *cobra.Command, args []string) error {
 		}
 	}
 
-	if strings.ToLower(output) != "yaml" && strings.ToLower(output) != "json" {
-		return serrors.UsageErrorf("unexpected value %s for -o --output", output)
+	if errorFormat != "text" && errorFormat != "json" {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected value %s for --error-format", errorFormat)
+	}
+
+	if kubeNative && shortNative {
+		return serrors.UsageErrorf(c.CommandPath(), "-k and --short-native can't be used together")
+	}
+
+	output = strings.ToLower(output)
+	switch output {
+	case "yaml", "json", "jsonl", "split":
+	case "bundle":
+		// One multi-document YAML stream is what yaml output is already.
+		output = "yaml"
+	default:
+		// Formats that encoder packages register, e.g. toml.
+		if _, ok := client.EncoderFor(output); !ok {
+			return serrors.UsageErrorf(c.CommandPath(), "unexpected value %s for -o --output (%s, bundle or split)", output, strings.Join(client.EncoderFormats(), ", "))
+		}
+	}
+	switch sortOrder {
+	case "", "name":
+	case "apply":
+		if !kubeNative || implode {
+			return serrors.UsageErrorf(c.CommandPath(), "--sort=apply only applies when converting to kube-native syntax (-k)")
+		}
+	default:
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected value %s for --sort", sortOrder)
+	}
+	if watchApply && output == "split" {
+		return serrors.UsageErrorf(c.CommandPath(), "--apply and -o split can't be used together")
+	}
+	if byCluster && output != "split" {
+		return serrors.UsageErrorf(c.CommandPath(), "--by-cluster only applies to -o split")
+	}
+	if len(gitopsOptions.Tool) > 0 {
+		if !gitops.IsTool(gitopsOptions.Tool) {
+			return serrors.UsageErrorf(c.CommandPath(), "unexpected value %s for --gitops (%s)", gitopsOptions.Tool, strings.Join(gitops.Tools, ", "))
+		}
+		if !kubeNative || implode || output != "split" || len(outDir) == 0 || byCluster || explode {
+			return serrors.UsageErrorf(c.CommandPath(), "--gitops only applies when converting to kube-native syntax (-k) with -o split and --out-dir, without --by-cluster")
+		}
+	} else if len(gitopsOptions.Name) > 0 || len(gitopsOptions.Repo) > 0 || len(gitopsOptions.Path) > 0 || len(gitopsOptions.Revision) > 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "--gitops-name, --gitops-repo, --gitops-path and --gitops-revision only apply with --gitops")
+	}
+
+	if (partial || len(partialReport) > 0 || partialComments) && (kubeNative || implode) {
+		return serrors.UsageErrorf(c.CommandPath(), "--partial only applies when converting to short syntax")
+	}
+	partial = partial || len(partialReport) > 0 || partialComments
+	if keepUnsupported && (partial || kubeNative || implode) {
+		return serrors.UsageErrorf(c.CommandPath(), "--keep-unsupported only applies when converting to short syntax, without --partial")
+	}
+
+	common.Namespace = kubectlFlags.Namespace
+	common.Labels, err = transform.ParseKeyValues(addLabels)
+	if err != nil {
+		return serrors.UsageErrorf(c.CommandPath(), "--add-label: %s", serrors.PrettyError(err))
+	}
+	common.Annotations, err = transform.ParseKeyValues(addAnnotations)
+	if err != nil {
+		return serrors.UsageErrorf(c.CommandPath(), "--add-annotation: %s", serrors.PrettyError(err))
+	}
+	if !common.IsEmpty() && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--add-label, --add-annotation, --name-prefix, --name-suffix and --namespace don't apply to --implode")
 	}
 
 	useStdin := false
@@ -128,80 +425,556 @@ func short(c *cobra.Command, args []string) error {
 		glog.V(3).Info("using stdin for input data")
 		useStdin = true
 	}
+	if (len(setValues) > 0 || useEnv || strictVariables || keepUnresolved) && (!kubeNative || implode) {
+		return serrors.UsageErrorf(c.CommandPath(), "--set, --env, --strict and --keep-unresolved only apply when converting short manifests to kube-native syntax (-k)")
+	}
+	if strictVariables && keepUnresolved {
+		return serrors.UsageErrorf(c.CommandPath(), "--strict and --keep-unresolved can't be used together")
+	}
 
+	redaction = nil
+	if len(redactEnv) > 0 && !redactSecrets {
+		return serrors.UsageErrorf(c.CommandPath(), "--redact-env only applies with --redact-secrets")
+	}
+	if redactSecrets && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--redact-secrets doesn't apply to --implode")
+	}
+	if redactSecrets {
+		patterns := redactEnv
+		if len(patterns) == 0 {
+			patterns = transform.DefaultRedactEnv
+		}
+		redaction, err = transform.NewRedaction(patterns)
+		if err != nil {
+			return serrors.UsageErrorf(c.CommandPath(), "--redact-env: %s", serrors.PrettyError(err))
+		}
+	}
+	if (secretOptions.SOPS || len(secretOptions.SealCert) > 0 || len(secretOptions.UnsealKey) > 0) && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--sops, --seal-cert and --unseal-key don't apply to --implode")
+	}
+	if secretOptions.SOPS && len(secretOptions.SealCert) > 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "--sops and --seal-cert can't be used together")
+	}
+	if len(secretOptions.SealCert) > 0 && !kubeNative {
+		return serrors.UsageErrorf(c.CommandPath(), "--seal-cert only applies when converting to kube-native syntax (-k)")
+	}
+	if len(secretOptions.UnsealKey) > 0 && kubeNative {
+		return serrors.UsageErrorf(c.CommandPath(), "--unseal-key only applies when converting to short syntax")
+	}
+	if provenance && (!kubeNative || implode) {
+		return serrors.UsageErrorf(c.CommandPath(), "--provenance only applies when converting to kube-native syntax (-k)")
+	}
+	if fixDeprecated && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--fix-deprecated doesn't apply to --implode")
+	}
+	if showSummary && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--summary doesn't apply to --implode")
+	}
+	if (len(preHooks) > 0 || len(postHooks) > 0) && implode {
+		return serrors.UsageErrorf(c.CommandPath(), "--pre-hook and --post-hook don't apply to --implode")
+	}
+	if len(sourceMapFile) > 0 && (!kubeNative || implode || useStdin || output != "yaml" || explode || len(sortOrder) > 0 || watchFiles) {
+		return serrors.UsageErrorf(c.CommandPath(), "--source-map only applies when converting short manifest files (-f) to kube-native syntax (-k) with yaml output, without --explode, --sort or --watch")
+	}
+
+	if watchFiles {
+		return watchInput(c)
+	}
+
+	return convertInput(useStdin, os.Stdout)
+}
+
+// convertInput converts the input files (or stdin) and writes the result.
+func convertInput(useStdin bool, out io.Writer) error {
+	var err error
 	var convertedData []interface{}
-	if !useStdin && kubeNative {
+	// dropped lists what a partial conversion left out of each object in convertedData.
+	var dropped []client.Dropped
+	// docComments are the comments carried over to each object in convertedData.
+	var docComments []comments.Comments
+	// warnings are the problems that didn't stop the conversion.
+	var warnings []client.Warning
+	// sources are where the fields of each object in convertedData come from, for the source map.
+	var sources []*sourcemap.Source
+	// summary describes the conversion, for --summary.
+	summary := &client.Summary{}
+	if !useStdin {
+		filenames, err = parser.ExpandFilenames(filenames)
+		if err != nil {
+			return err
+		}
+	}
+	if implode {
+		glog.V(3).Info("reconstructing exploded input")
+		convertedData, err = implodeInput(useStdin)
+		if err != nil {
+			return err
+		}
+	} else if !useStdin && kubeNative {
 		// Imports are only supported for normal files in koki syntax.
-		kokiModules, err := loadKokiFiles(filenames)
+		kokiModules, unsupported, apps, err := loadKokiFiles(filenames)
 		if err != nil {
 			return err
 		}
 
-		convertedData, err = convertKokiModules(kokiModules)
+		kokiObjs := []map[string]interface{}{}
+		for _, kokiModule := range kokiModules {
+			kokiObjs = append(kokiObjs, kokiModule.Export.Raw)
+		}
+		warnMissingServiceAccounts(kokiObjs)
+		if showSummary {
+			err = summary.AddInputs(kokiObjs)
+			if err != nil {
+				return err
+			}
+		}
+
+		var converterWarnings [][]converters.Warning
+		convertedData, converterWarnings, err = convertKokiModules(kokiModules, unsupported, apps)
 		if err != nil {
 			return err
 		}
+		for i := range convertedData {
+			convertedData[i], err = applyFixDeprecated(convertedData[i])
+			if err != nil {
+				return err
+			}
+		}
+		warnings, err = moduleWarnings(kokiModules, convertedData, converterWarnings)
+		if err != nil {
+			return err
+		}
+
+		if len(sourceMapFile) > 0 {
+			glog.V(3).Info("mapping converted fields to their sources")
+			sources, err = mapSources(kokiModules)
+			if err != nil {
+				return err
+			}
+		}
+		if keepComments {
+			convertedData, err = attachComments(kokiModules, convertedData)
+			if err != nil {
+				return err
+			}
+		}
+		if provenance {
+			convertedData, err = attachProvenance(kokiModules, convertedData)
+			if err != nil {
+				return err
+			}
+		}
 	} else {
 		// parse input data from one of the sources - files or stdin
 		glog.V(3).Info("parsing input data")
 		fileDatas := map[string][]map[string]interface{}{}
+		// inputNames keeps the files in their input order.
+		inputNames := filenames
+		// stdinHash is the hash of stdin, for --provenance.
+		var stdinHash string
 		if useStdin {
-			fileDatas["stdin"], err = parser.Parse(nil, true)
+			inputNames = []string{"stdin"}
+			fileDatas["stdin"], stdinHash, err = parseStdin()
 			if err != nil {
-				return fmt.Errorf("parsing stdin: %s", err.Error())
+				return client.NewDocumentError("parsing", "stdin", -1, nil, err)
+			}
+			// Documents from stdin aren't evaluated as modules, so their holes are only filled when asked to.
+			if kubeNative && (len(setValues) > 0 || useEnv || strictVariables) {
+				fileDatas["stdin"], err = fillStdinHoles(fileDatas["stdin"])
+				if err != nil {
+					return client.NewDocumentError("filling template holes in", "stdin", -1, nil, err)
+				}
 			}
 		} else {
 			for _, filename := range filenames {
 				fileDatas[filename], err = parser.Parse([]string{filename}, false)
 				if err != nil {
-					return fmt.Errorf("parsing %s: %s", filename, err.Error())
+					return client.NewDocumentError("parsing", filename, -1, nil, err)
+				}
+			}
+		}
+		inputSyntax := hook.Kube
+		if kubeNative {
+			inputSyntax = hook.Short
+		}
+		for _, filename := range inputNames {
+			fileDatas[filename], err = hook.Run(preHooks, hook.Pre, inputSyntax, filename, fileDatas[filename])
+			if err != nil {
+				return client.NewDocumentError("running hooks on", filename, -1, nil, err)
+			}
+			if !kubeNative {
+				for _, obj := range fileDatas[filename] {
+					if secretcrypt.IsEncrypted(obj) || secretcrypt.IsSealed(obj) {
+						decryptedFiles[filename] = true
+					}
+				}
+				fileDatas[filename], err = secretOptions.Decrypt(fileDatas[filename])
+				if err != nil {
+					return client.NewDocumentError("decrypting", filename, -1, nil, err)
+				}
+			}
+			if showSummary {
+				err = summary.AddInputs(fileDatas[filename])
+				if err != nil {
+					return err
 				}
 			}
 		}
 
+		// References are moved out of the namespaces of all the input objects, not just those in the same file.
+		common.FromNamespaces = nil
+		if len(common.Namespace) > 0 && !kubeNative {
+			allData := []map[string]interface{}{}
+			for _, filename := range inputNames {
+				allData = append(allData, fileDatas[filename]...)
+			}
+			common.FromNamespaces = transform.NamespacesOf(allData)
+		}
+
+		// Without -k or --short-native, short syntax documents are converted to kube-native syntax instead.
+		// --partial and --keep-unsupported are only for Kubernetes documents.
+		detect := !kubeNative && !shortNative && !partial && !keepUnsupported
+
 		i := 0
 		convertedData = []interface{}{}
+		kokiObjs := []map[string]interface{}{}
 
-		for filename, data := range fileDatas {
-			if err != nil {
-				return err
-			}
+		for _, filename := range inputNames {
+			data := fileDatas[filename]
 
 			if kubeNative {
 				glog.V(3).Info("converting input to kubernetes native syntax")
-				objs, err := client.ConvertKokiMaps(data)
+				if applyReady {
+					// Accept objects read back from the cluster, e.g. "kubectl get -o yaml | short -k --apply-ready -".
+					for _, obj := range data {
+						transform.StripServerFields(obj)
+					}
+					data, err = client.ConvertEitherMapsToKoki(data)
+					if err != nil {
+						return client.NewDocumentError("converting", filename, -1, nil, err)
+					}
+				}
+				objs, converterWarnings, err := convertMaps(filename, data, client.ConvertKokiMap)
 				if err != nil {
-					return fmt.Errorf("converting %s: %s", filename, err.Error())
+					return err
+				}
+				for j := range objs {
+					objs[j], err = applyFixDeprecated(objs[j])
+					if err != nil {
+						return client.NewDocumentError("fixing", filename, j, data[j], err)
+					}
+					warnings, err = appendWarnings(warnings, filename, j, data[j], objs[j], converterWarnings[j])
+					if err != nil {
+						return err
+					}
+					// Files are converted as modules above, so these documents are from stdin.
+					if provenance {
+						objs[j], err = withProvenance(objs[j], filename, stdinHash)
+						if err != nil {
+							return err
+						}
+					}
 				}
 				convertedData = append(convertedData, objs...)
+				kokiObjs = append(kokiObjs, data...)
 			} else {
 				glog.V(3).Info("converting input to koki native syntax")
-				objs, err := client.ConvertKubeMaps(data)
+				docComments = append(docComments, detachComments(data)...)
+				if applyReady {
+					for _, obj := range data {
+						transform.StripServerFields(obj)
+					}
+				}
+				// isShort marks the short syntax documents, which are converted to kube-native syntax.
+				isShort := make([]bool, len(data))
+				kubeData := []map[string]interface{}{}
+				for j, obj := range data {
+					isShort[j] = detect && parser.IsKokiNativeObject(obj)
+					if !isShort[j] {
+						if fixDeprecated {
+							obj, err = fixDeprecatedMap(obj)
+							if err != nil {
+								return client.NewDocumentError("fixing", filename, j, data[j], err)
+							}
+							data[j] = obj
+						}
+						common.Apply(obj)
+						if redaction != nil {
+							redaction.Apply(obj)
+						}
+						kubeData = append(kubeData, obj)
+					}
+				}
+				if len(asOf) > 0 {
+					err = checkCompat(kubeData)
+					if err != nil {
+						return client.NewDocumentError("checking", filename, -1, nil, err)
+					}
+				}
+				var objs []interface{}
+				// converterWarnings are empty for partial conversions, which report what they drop instead.
+				converterWarnings := make([][]converters.Warning, len(data))
+				if partial {
+					var objsDropped []client.Dropped
+					objs, objsDropped, err = client.ConvertKubeMapsPartially(data)
+					if err != nil {
+						return client.NewDocumentError("converting", filename, -1, nil, err)
+					}
+					for j := range objsDropped {
+						objsDropped[j].File = filename
+					}
+					dropped = append(dropped, objsDropped...)
+				} else if keepUnsupported {
+					objs, converterWarnings, err = convertMaps(filename, data, convertKubeMapKeepingUnsupported)
+				} else if detect {
+					objs, converterWarnings, err = convertMaps(filename, data, client.ConvertEitherMap)
+				} else {
+					objs, converterWarnings, err = convertMaps(filename, data, client.ConvertKubeMap)
+				}
 				if err != nil {
-					return fmt.Errorf("converting %s: %s", filename, err.Error())
+					return err
+				}
+				for j, obj := range data {
+					var kubeObj interface{} = obj
+					var kokiObj map[string]interface{}
+					if isShort[j] {
+						kokiObj = obj
+						objs[j], err = applyCommon(objs[j])
+						if err != nil {
+							return err
+						}
+						objs[j], err = applyRedaction(objs[j])
+						if err != nil {
+							return err
+						}
+						objs[j], err = applyFixDeprecated(objs[j])
+						if err != nil {
+							return client.NewDocumentError("fixing", filename, j, obj, err)
+						}
+						kubeObj = objs[j]
+					}
+					warnings, err = appendWarnings(warnings, filename, j, kokiObj, kubeObj, converterWarnings[j])
+					if err != nil {
+						return err
+					}
 				}
 				convertedData = append(convertedData, objs...)
 			}
 			i = i + 1
 		}
+		warnMissingServiceAccounts(kokiObjs)
+	}
+
+	reportWarnings(warnings, os.Stderr)
+
+	if partial {
+		err = reportDropped(dropped)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conversionCache != nil {
+		hits, misses := conversionCache.Stats()
+		glog.V(1).Infof("conversion cache: %d hits, %d misses", hits, misses)
+	}
+
+	if len(asOf) > 0 && kubeNative && !implode {
+		glog.V(3).Infof("checking output against Kubernetes %s", asOf)
+		kubeObjs := make([]map[string]interface{}, len(convertedData))
+		for i, obj := range convertedData {
+			kubeObjs[i], err = objutil.ToDictionary(obj)
+			if err != nil {
+				return err
+			}
+		}
+		err = checkCompat(kubeObjs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if kubeNative && !implode && !common.IsEmpty() {
+		glog.V(3).Info("adding common labels, annotations and name affixes")
+		convertedData, err = common.ApplyToObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if applyReady && kubeNative && !implode {
+		glog.V(3).Info("stripping server-populated fields")
+		convertedData, err = transform.StripServerFieldsFromObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if redaction != nil && kubeNative && !implode {
+		glog.V(3).Info("redacting secrets")
+		convertedData, err = redaction.ApplyToObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if kubeNative && !implode {
+		convertedData, err = applyProfile(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if explicitDefaults && kubeNative && !implode {
+		glog.V(3).Info("filling in defaults")
+		convertedData, err = defaults.Objs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if kubeNative && !implode {
+		convertedData, err = secretOptions.EncryptObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if sortOrder == "apply" {
+		glog.V(3).Info("sorting objects for apply")
+		convertedData, err = order.ForApply(convertedData)
+		if err != nil {
+			return err
+		}
+	} else if sortOrder == "name" {
+		glog.V(3).Info("sorting objects by kind and name")
+		convertedData, dropped, docComments, err = sortByName(convertedData, dropped, docComments)
+		if err != nil {
+			return err
+		}
+	}
+
+	if humanizeUnits && !kubeNative && !implode {
+		glog.V(3).Info("humanizing durations and quantities")
+		convertedData, err = humanizeShortObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(transformsFile) > 0 {
+		glog.V(3).Info("applying field transforms")
+		transforms, err := transform.LoadConfig(transformsFile)
+		if err != nil {
+			return err
+		}
+
+		convertedData, err = transforms.ApplyToObjs(convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(onlyPaths) > 0 || len(keepPaths) > 0 {
+		glog.V(3).Info("keeping only the selected fields")
+		convertedData, dropped, docComments, err = keepOnly(convertedData, dropped, docComments, append(onlyPaths, keepPaths...))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(postHooks) > 0 {
+		glog.V(3).Info("running post-conversion hooks")
+		outputSyntax := hook.Short
+		if kubeNative {
+			outputSyntax = hook.Kube
+		}
+		convertedData, err = hook.RunOnObjs(postHooks, hook.Post, outputSyntax, convertedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if showSummary {
+		summary.Warnings = len(warnings)
+		summary.AddDropped(dropped)
+		err = summary.AddOutputs(convertedData)
+		if err != nil {
+			return err
+		}
+		err = reportSummary(summary, os.Stderr)
+		if err != nil {
+			return err
+		}
 	}
 
 	buf := &bytes.Buffer{}
-	if strings.ToLower(output) == "yaml" {
+	if explode {
+		glog.V(3).Info("exploding converted data")
+		err = objutil.Explode(convertedData, buf)
+		if err != nil {
+			return err
+		}
+	} else if output == "split" {
+		dir := outDir
+		if len(dir) == 0 {
+			dir = "."
+		}
+		glog.V(3).Infof("writing converted data to one file per object in %s", dir)
+		writeObjs := client.WriteObjsToFiles
+		if byCluster {
+			writeObjs = client.WriteObjsToClusterDirs
+		}
+		paths, err := writeObjs(convertedData, dir)
+		if err != nil {
+			return err
+		}
+		if len(gitopsOptions.Tool) > 0 {
+			path, err := writeGitOpsWrapper(dir)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+		}
+		for _, path := range paths {
+			fmt.Fprintf(out, "wrote %s\n", path)
+		}
+		return nil
+	} else if output == "jsonl" {
+		glog.V(3).Info("marshalling converted data into json lines")
+		err = client.WriteObjsToJSONLines(convertedData, out)
+		if err != nil {
+			return err
+		}
+		return nil
+	} else if output == "yaml" && (partialComments || hasComments(docComments)) {
+		glog.V(3).Info("marshalling converted data into yaml with comments")
+		err = writeYamlWithComments(convertedData, dropped, docComments, buf)
+		if err != nil {
+			return err
+		}
+	} else if output == "yaml" {
 		glog.V(3).Info("marshalling converted data into yaml")
 		err = client.WriteObjsToYamlStream(convertedData, buf)
 		if err != nil {
 			return err
 		}
+		if len(sourceMapFile) > 0 {
+			err = writeSourceMap(buf.Bytes(), sources)
+			if err != nil {
+				return err
+			}
+		}
 	} else {
-		glog.V(3).Info("marshalling converted data into json")
-		err = client.WriteObjsToJSONStream(convertedData, buf)
+		glog.V(3).Infof("marshalling converted data into %s", output)
+		encoder, _ := client.EncoderFor(output)
+		err = encoder.Encode(convertedData, buf)
 		if err != nil {
 			return err
 		}
 	}
 
-	fmt.Printf("%s\n", buf.String())
+	fmt.Fprintf(out, "%s\n", buf.String())
 
 	return nil
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/edit"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	setImageCmd = &cobra.Command{
+		Use:   "set-image KIND/NAME CONTAINER=IMAGE...",
+		Short: "Change the images of a workload's containers in its manifest files",
+		Long: `Set-image changes the container images of a Pod, PodTemplate, Deployment, ReplicaSet,
+ReplicationController, StatefulSet, DaemonSet, Job or CronJob, and writes the manifest files
+back in place. Only the image lines change, so formatting and comments are kept.
+
+Manifests may be in short or Kubernetes syntax. KIND is either, e.g. deployment, stateful_set
+or StatefulSet. Use * as the container name to change every container, including init containers.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := setImage(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Update the web container of the frontend deployment
+  short set-image deployment/frontend web=nginx:1.25 -f manifests/
+
+  # Update every container of a stateful set
+  short set-image stateful_set/db '*=postgres:16' -f db.short.yaml
+`,
+	}
+
+	// setImageNamespace is the namespace of the object to change, if there are several with the same name
+	setImageNamespace string
+)
+
+func init() {
+	setImageCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to edit")
+	setImageCmd.Flags().StringVarP(&setImageNamespace, "namespace", "n", "", "only change the object in this namespace")
+}
+
+func setImage(c *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return serrors.UsageErrorf(c.CommandPath(), "expected KIND/NAME and at least one CONTAINER=IMAGE")
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	segments := strings.SplitN(args[0], "/", 2)
+	if len(segments) != 2 || len(segments[0]) == 0 || len(segments[1]) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "expected KIND/NAME, not %q", args[0])
+	}
+	target := edit.Target{Kind: segments[0], Name: segments[1], Namespace: setImageNamespace}
+
+	images := map[string]string{}
+	for _, arg := range args[1:] {
+		segments := strings.SplitN(arg, "=", 2)
+		if len(segments) != 2 || len(segments[0]) == 0 || len(segments[1]) == 0 {
+			return serrors.UsageErrorf(c.CommandPath(), "expected CONTAINER=IMAGE, not %q", arg)
+		}
+		images[segments[0]] = segments[1]
+	}
+
+	files, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "reading %s", file)
+		}
+		if !edit.Contains(data, target) {
+			continue
+		}
+		found = true
+
+		edited, changed, err := edit.SetImage(data, target, images)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, file)
+		}
+		if changed == 0 {
+			fmt.Printf("%s: no matching containers\n", file)
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(file, edited, info.Mode())
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "writing %s", file)
+		}
+		fmt.Printf("%s: updated %d container(s)\n", file, changed)
+	}
+
+	if !found {
+		return serrors.InvalidValueErrorf(args[0], "%s isn't in the input files", args[0])
+	}
+
+	return nil
+}
@@ -1,12 +1,40 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/koki/json"
 	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/app"
+	"github.com/koki/short/cache"
+	"github.com/koki/short/client"
+	"github.com/koki/short/comments"
+	"github.com/koki/short/compat"
+	"github.com/koki/short/convert"
 	"github.com/koki/short/converter"
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/gitops"
+	"github.com/koki/short/hook"
 	"github.com/koki/short/imports"
+	"github.com/koki/short/lock"
+	"github.com/koki/short/migrate"
+	"github.com/koki/short/order"
 	"github.com/koki/short/parser"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/sourcemap"
+	"github.com/koki/short/template"
+	"github.com/koki/short/util/humanize"
+	"github.com/koki/short/util/objutil"
 	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
@@ -26,62 +54,987 @@ func debugLogModule(module imports.Module) {
 	}
 }
 
-func loadKokiFiles(filenames []string) ([]imports.Module, error) {
+func warnMissingServiceAccounts(kokiObjs []map[string]interface{}) {
+	for _, ref := range client.MissingServiceAccounts(kokiObjs) {
+		glog.Warningf("%s (%s) uses service account (%s), which isn't defined in the input", ref.Kind, ref.Name, ref.Account)
+	}
+}
+
+// loadKokiFiles evaluates the modules in each file, after expanding the apps among them into their resources.
+// It also returns the "_unsupported" section of each module, if any, and the app it's part of, if any.
+func loadKokiFiles(filenames []string) ([]imports.Module, []map[string]interface{}, []*app.App, error) {
+	readFromPath := imports.ReadFromLocalPath
+	l, err := readVendorLock()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if l != nil {
+		readFromPath = lock.VendoredReader(l, vendorDir)
+	}
+
+	// Set aside the "_unsupported" sections and apps, which aren't part of the modules.
+	unsupportedByPath := map[string][]map[string]interface{}{}
+	appsByPath := map[string][]*app.App{}
+	readWithoutUnsupported := func(path string) ([]map[string]interface{}, error) {
+		objs, err := readFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		objs, err = hook.Run(preHooks, hook.Pre, hook.Short, path, objs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading %s", path)
+		}
+		objs, objApps, err := app.Expand(objs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading %s", path)
+		}
+
+		// Sections that only define templates don't become modules.
+		sections := []map[string]interface{}{}
+		apps := []*app.App{}
+		for i, obj := range objs {
+			var section map[string]interface{}
+			objs[i], section, err = client.SplitUnsupported(obj)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "reading %s", path)
+			}
+			if !imports.DefinesOnlyTemplates(objs[i]) {
+				sections = append(sections, section)
+				apps = append(apps, objApps[i])
+			}
+		}
+		unsupportedByPath[path] = sections
+		appsByPath[path] = apps
+		return objs, nil
+	}
+
+	setVariables, variables, err := substitutionVariables()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	results := []imports.Module{}
+	unsupported := []map[string]interface{}{}
+	apps := []*app.App{}
 	for _, filename := range filenames {
 		evalContext := imports.EvalContext{
 			RawToTyped:        parser.ParseKokiNativeObject,
 			ResolveImportPath: imports.ResolveImportLocalPath,
-			ReadFromPath:      imports.ReadFromLocalPath,
+			ReadFromPath:      readWithoutUnsupported,
+			Variables:         template.ResolverForParams(variables),
+			KeepUnresolved:    keepUnresolved,
 		}
 
 		modules, err := evalContext.Parse(filename)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, client.NewDocumentError("parsing", filename, -1, nil, err)
 		}
+		sections := unsupportedByPath[filename]
+		fileApps := appsByPath[filename]
 
-		for _, module := range modules {
-			err = evalContext.EvaluateModule(&module, nil)
+		for i, module := range modules {
+			// Values from --set also override the defaults of a file's own params.
+			params := map[string]interface{}{}
+			for key, val := range setVariables {
+				params[key] = val
+			}
+			err = evalContext.EvaluateModule(&module, params)
 			if err != nil {
 				debugLogModule(module)
-				return nil, err
+				return nil, nil, nil, client.NewDocumentError("evaluating", filename, i, module.Export.Raw, err)
 			}
 
 			export := module.Export
 			if err, ok := export.TypedResult.(error); ok {
 				debugLogModule(module)
-				return nil, err
+				return nil, nil, nil, client.NewDocumentError("parsing", filename, i, export.Raw, err)
 			}
 
 			results = append(results, module)
+			if i < len(sections) {
+				unsupported = append(unsupported, sections[i])
+				apps = append(apps, fileApps[i])
+			} else {
+				unsupported = append(unsupported, nil)
+				apps = append(apps, nil)
+			}
 		}
 	}
 
-	return results, nil
+	return results, unsupported, apps, nil
 }
 
-func convertKokiModules(kokiModules []imports.Module) ([]interface{}, error) {
-	kubeObjs := []interface{}{}
-	for _, kokiModule := range kokiModules {
+// substitutionVariables gets the values for template holes from --set and, with --env, the environment.
+// It returns the values from --set, and all the values, where --set wins over the environment.
+func substitutionVariables() (map[string]interface{}, map[string]interface{}, error) {
+	setVariables := map[string]interface{}{}
+	variables := map[string]interface{}{}
+	if useEnv {
+		for _, env := range os.Environ() {
+			segments := strings.SplitN(env, "=", 2)
+			if len(segments) == 2 {
+				variables[segments[0]] = segments[1]
+			}
+		}
+	}
+
+	for _, setValue := range setValues {
+		segments := strings.SplitN(setValue, "=", 2)
+		if len(segments) != 2 || len(segments[0]) == 0 {
+			return nil, nil, serrors.InvalidValueErrorf(setValue, "expected --set key=value")
+		}
+		setVariables[segments[0]] = parseSetValue(segments[1])
+		variables[segments[0]] = setVariables[segments[0]]
+	}
+
+	return setVariables, variables, nil
+}
+
+// fillStdinHoles fills the template holes of short documents from stdin, which aren't evaluated as modules.
+func fillStdinHoles(objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	_, variables, err := substitutionVariables()
+	if err != nil {
+		return nil, err
+	}
+	resolveVariable := template.ResolverForParams(variables)
+	resolver := func(ident string) (interface{}, error) {
+		if val, err := resolveVariable(ident); err == nil {
+			return val, nil
+		}
+		if keepUnresolved {
+			glog.Warningf("nothing sets (%s) for (stdin), leaving it as is", ident)
+			return "${" + ident + "}", nil
+		}
+		return nil, serrors.InvalidValueErrorf(ident, "invalid template param (%s) for (stdin)", ident)
+	}
+
+	filled := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		filled[i], err = template.ReplaceMap(obj, resolver)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filled, nil
+}
+
+// parseSetValue reads numbers and booleans as such, unless that would change how they're written (e.g. "1.10" or "007").
+func parseSetValue(raw string) interface{} {
+	var val interface{}
+	err := yaml.Unmarshal([]byte(raw), &val)
+	if err != nil {
+		return raw
+	}
+
+	switch val.(type) {
+	case bool, int, int64, float64:
+		if fmt.Sprintf("%v", val) == raw {
+			return val
+		}
+	}
+
+	return raw
+}
+
+// moduleDocuments returns the index of each module in its file.
+func moduleDocuments(kokiModules []imports.Module) []int {
+	documents := make([]int, len(kokiModules))
+	for i := 1; i < len(kokiModules); i++ {
+		if kokiModules[i].Path == kokiModules[i-1].Path {
+			documents[i] = documents[i-1] + 1
+		}
+	}
+
+	return documents
+}
+
+// readVendorLock reads the lock file of --vendor-dir, if it's given.
+func readVendorLock() (*lock.Lock, error) {
+	if len(vendorDir) == 0 {
+		return nil, nil
+	}
+
+	return lock.ReadLock(vendorDir)
+}
+
+// convertKokiModules converts each module, restoring the fields in its "_unsupported" section and adding the
+// settings of its app, if any. Images are pinned to their digests in the lock file of --vendor-dir, if any.
+// It also returns the converter's warnings for each module.
+func convertKokiModules(kokiModules []imports.Module, unsupported []map[string]interface{}, apps []*app.App) ([]interface{}, [][]converters.Warning, error) {
+	l, err := readVendorLock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documents := moduleDocuments(kokiModules)
+	warnings := make([][]converters.Warning, len(kokiModules))
+	kubeObjs, err := convert.Parallel(len(kokiModules), parallelism, func(i int) (interface{}, error) {
+		kokiModule := kokiModules[i]
 		kokiExport := kokiModule.Export
 		data := kokiExport.Raw
-		extraneousPaths, err := jsonutil.ExtraneousFieldPaths(data, kokiExport.TypedResult)
+		extraneousPaths, err := client.ExtraneousKokiFieldPaths(data, kokiExport.TypedResult)
 		if err != nil {
 			return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in input")
 		}
 		if len(extraneousPaths) > 0 {
-			return nil, &jsonutil.ExtraneousFieldsError{
+			return nil, client.NewDocumentError("converting", kokiModule.Path, documents[i], data, &jsonutil.ExtraneousFieldsError{
 				Paths: extraneousPaths,
-			}
+			})
 		}
 
-		kubeObj, err := converter.DetectAndConvertFromKokiObj(kokiExport.TypedResult)
+		// Secrets that are encrypted or sealed after conversion would be in the cache in plaintext.
+		_, isSecret := data["secret"]
+		cacheable := !isSecret || (!secretOptions.SOPS && len(secretOptions.SealCert) == 0)
+		kubeObj, objWarnings, err := convertCached(data, cacheable, func(warnings *converters.Warnings) (interface{}, error) {
+			return converter.DetectAndConvertFromKokiObjWithWarnings(kokiExport.TypedResult, warnings)
+		})
 		if err != nil {
 			debugLogModule(kokiModule)
+			return nil, client.NewDocumentError("converting", kokiModule.Path, documents[i], data, err)
+		}
+		if len(unsupported[i]) > 0 {
+			kubeObj, err = client.InjectUnsupported(kubeObj, unsupported[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		warnings[i] = objWarnings
+		if apps[i] != nil {
+			kubeObj, err = apps[i].ApplyToObj(kubeObj)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if l != nil {
+			return lock.PinImages(kubeObj, l)
+		}
+
+		return kubeObj, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return kubeObjs, warnings, nil
+}
+
+// openConversionCache opens the cache at cacheLocation, if any.
+// SHORT_CACHE_KEY signs and verifies entries, and SHORT_CACHE_TOKEN authenticates to remote caches.
+func openConversionCache() error {
+	if len(cacheLocation) == 0 {
+		return nil
+	}
+
+	store, err := cache.Open(cacheLocation, os.Getenv("SHORT_CACHE_TOKEN"))
+	if err != nil {
+		return err
+	}
+
+	// Conversion results depend on the build of short and on the settings below.
+	build, err := buildID()
+	if err != nil {
+		glog.Warningf("not using the conversion cache: %s", err)
+		return nil
+	}
+	// --keep-unsupported and --short-native choose the conversion function, e.g. whether unsupported fields fail.
+	salt := fmt.Sprintf("%s kube-native=%t kube-version=%s keep-unsupported=%t short-native=%t", build, kubeNative, kubeVersion, keepUnsupported, shortNative)
+	conversionCache = cache.New(store, []byte(os.Getenv("SHORT_CACHE_KEY")), salt)
+	return nil
+}
+
+// buildID identifies the build of short. It's the version for release builds. Development builds are all
+// version "HEAD", whatever their code, so they're identified by the SHA-256 of the executable instead.
+func buildID() (string, error) {
+	if GITCOMMIT != "HEAD" {
+		return GITCOMMIT, nil
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "finding the short executable")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "reading %s", path)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "reading %s", path)
+	}
+
+	return fmt.Sprintf("HEAD sha256:%x", hash.Sum(nil)), nil
+}
+
+// convertCached converts obj, reusing the result from the conversion cache if there is one, and returns the
+// converter's warnings. Conversions with warnings aren't cached, so their warnings are reported every time, and
+// neither are those that aren't cacheable, e.g. of decrypted Secrets.
+func convertCached(obj map[string]interface{}, cacheable bool, convert func(*converters.Warnings) (interface{}, error)) (interface{}, []converters.Warning, error) {
+	warnings := &converters.Warnings{}
+	if conversionCache == nil || !cacheable {
+		converted, err := convert(warnings)
+		return converted, warnings.List(), err
+	}
+
+	converted, err := conversionCache.ConvertUnlessSkipped(obj, func() (interface{}, bool, error) {
+		converted, err := convert(warnings)
+		return converted, len(warnings.List()) > 0, err
+	})
+	return converted, warnings.List(), err
+}
+
+// convertMaps converts the objs of filename on parallelism goroutines, one at a time so each result can come from the conversion cache.
+// It also returns the converter's warnings for each object. A failure is reported as a client.DocumentError.
+func convertMaps(filename string, objs []map[string]interface{}, convertFn func(map[string]interface{}, *converters.Warnings) (interface{}, error)) ([]interface{}, [][]converters.Warning, error) {
+	warnings := make([][]converters.Warning, len(objs))
+	converted, err := convert.Parallel(len(objs), parallelism, func(i int) (interface{}, error) {
+		obj := objs[i]
+		converted, objWarnings, err := convertCached(obj, !decryptedFiles[filename], func(w *converters.Warnings) (interface{}, error) {
+			return convertFn(obj, w)
+		})
+		if err != nil {
+			return nil, client.NewDocumentError("converting", filename, i, obj, err)
+		}
+		warnings[i] = objWarnings
+		return converted, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return converted, warnings, nil
+}
+
+// convertKubeMapKeepingUnsupported is client.ConvertKubeMapsKeepingUnsupported for convertMaps. It has no warnings,
+// since what it can't convert is kept instead.
+func convertKubeMapKeepingUnsupported(obj map[string]interface{}, _ *converters.Warnings) (interface{}, error) {
+	converted, err := client.ConvertKubeMapsKeepingUnsupported([]map[string]interface{}{obj})
+	if err != nil {
+		return nil, err
+	}
+
+	return converted[0], nil
+}
+
+// prettyError is serrors.PrettyError, along with the file and document of a client.DocumentError.
+func prettyError(err error) string {
+	if docErr, ok := err.(*client.DocumentError); ok {
+		return docErr.PrettyError()
+	}
+
+	return serrors.PrettyError(err)
+}
+
+// writeErrorRecords writes err as JSON lines, one for each client.ErrorRecord.
+func writeErrorRecords(err error, w io.Writer) {
+	encoder := json.NewEncoder(w)
+	for _, record := range client.ErrorRecords(err) {
+		if encodeErr := encoder.Encode(record); encodeErr != nil {
+			glog.Error(encodeErr)
+		}
+	}
+}
+
+// appendWarnings appends the converter's warnings about a document, and the warnings for the Kubernetes side of its conversion.
+// kokiObj is the short syntax document that was converted to kubeObj, or nil if kubeObj is the input.
+func appendWarnings(warnings []client.Warning, filename string, document int, kokiObj map[string]interface{}, kubeObj interface{}, converted []converters.Warning) ([]client.Warning, error) {
+	objWarnings, err := client.ConverterWarnings(kubeObj, converted)
+	if err != nil {
+		return nil, err
+	}
+	checked, err := client.KubeWarnings(kubeObj)
+	if kokiObj != nil {
+		checked, err = client.ShortWarnings(kokiObj, kubeObj)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range append(objWarnings, checked...) {
+		warning.File = filename
+		warning.Document = &document
+		warnings = append(warnings, warning)
+	}
+
+	return warnings, nil
+}
+
+// moduleWarnings returns the warnings for the conversion of each module to kubeObjs, along with the converter's.
+func moduleWarnings(kokiModules []imports.Module, kubeObjs []interface{}, converted [][]converters.Warning) ([]client.Warning, error) {
+	var err error
+	warnings := []client.Warning{}
+	documents := moduleDocuments(kokiModules)
+	for i, kubeObj := range kubeObjs {
+		warnings, err = appendWarnings(warnings, kokiModules[i].Path, documents[i], kokiModules[i].Export.Raw, kubeObj, converted[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// warningRecord is a client.Warning for --error-format json, marked so it can't be mistaken for an error.
+type warningRecord struct {
+	Level string `json:"level"`
+	client.Warning
+}
+
+// reportWarnings writes the warnings to w, as JSON lines for --error-format json, or logs them.
+func reportWarnings(warnings []client.Warning, w io.Writer) {
+	if errorFormat != "json" {
+		for _, warning := range warnings {
+			glog.Warning(warning)
+		}
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, warning := range warnings {
+		if err := encoder.Encode(warningRecord{Level: "warning", Warning: warning}); err != nil {
+			glog.Error(err)
+		}
+	}
+}
+
+type summaryRecord struct {
+	Level string `json:"level"`
+	*client.Summary
+}
+
+// reportSummary writes the summary to w, as a JSON line for --error-format json.
+func reportSummary(summary *client.Summary, w io.Writer) error {
+	if errorFormat != "json" {
+		return summary.Write(w)
+	}
+
+	return json.NewEncoder(w).Encode(summaryRecord{Level: "summary", Summary: summary})
+}
+
+// reportDropped logs what a partial conversion left out, and writes it to partialReport if it's set.
+func reportDropped(dropped []client.Dropped) error {
+	report := []client.Dropped{}
+	for _, d := range dropped {
+		if d.IsEmpty() {
+			continue
+		}
+		report = append(report, d)
+		if len(d.Unconverted) > 0 {
+			glog.Warningf("%s (%s) in %s wasn't converted: %s", d.Kind, d.Name, d.File, d.Unconverted)
+		} else {
+			glog.Warningf("%s (%s) in %s: dropped %s", d.Kind, d.Name, d.File, strings.Join(d.Fields, ", "))
+		}
+	}
+
+	if len(partialReport) == 0 {
+		return nil
+	}
+
+	b, err := yaml.Marshal(report)
+	if err != nil {
+		return serrors.InvalidValueContextErrorf(err, report, "serializing partial conversion report")
+	}
+
+	return ioutil.WriteFile(partialReport, b, 0644)
+}
+
+// sortByName groups objs by kind and sorts them by namespace and name (see order.ByName),
+// along with their dropped fields and comments.
+func sortByName(objs []interface{}, dropped []client.Dropped, docComments []comments.Comments) ([]interface{}, []client.Dropped, []comments.Comments, error) {
+	indices, err := order.ByName(objs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sortedObjs := make([]interface{}, len(objs))
+	sortedDropped := []client.Dropped{}
+	sortedComments := []comments.Comments{}
+	for j, i := range indices {
+		sortedObjs[j] = objs[i]
+		if i < len(dropped) {
+			sortedDropped = append(sortedDropped, dropped[i])
+		}
+		if i < len(docComments) {
+			sortedComments = append(sortedComments, docComments[i])
+		}
+	}
+
+	return sortedObjs, sortedDropped, sortedComments, nil
+}
+
+// keepOnly narrows each object down to the fields at the paths (see objutil.KeepPaths).
+// Objects that have none of them are left out, along with their dropped fields and comments.
+func keepOnly(objs []interface{}, dropped []client.Dropped, docComments []comments.Comments, paths []string) ([]interface{}, []client.Dropped, []comments.Comments, error) {
+	keptObjs := []interface{}{}
+	keptDropped := []client.Dropped{}
+	keptComments := []comments.Comments{}
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		kept, err := objutil.KeepPaths(objMap, paths)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if kept == nil {
+			continue
+		}
+
+		keptObjs = append(keptObjs, kept)
+		if i < len(dropped) {
+			keptDropped = append(keptDropped, dropped[i])
+		}
+		if i < len(docComments) {
+			keptComments = append(keptComments, docComments[i])
+		}
+	}
+
+	if len(keptObjs) == 0 && len(objs) > 0 {
+		return nil, nil, nil, serrors.InvalidValueErrorf(strings.Join(paths, ", "), "none of the output objects have these fields")
+	}
+
+	return keptObjs, keptDropped, keptComments, nil
+}
+
+// writeYamlWithComments writes objs as a YAML stream, with the comments carried over from short manifests,
+// and what a partial conversion left out of each object as comments above it.
+func writeYamlWithComments(objs []interface{}, dropped []client.Dropped, docComments []comments.Comments, w io.Writer) error {
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if i < len(dropped) {
+			if _, err := io.WriteString(w, dropped[i].Comment()); err != nil {
+				return err
+			}
+		}
+
+		b, err := client.CanonicalYAML(obj)
+		if err != nil {
+			return err
+		}
+		if i < len(docComments) {
+			b = comments.Insert(b, docComments[i])
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachComments keeps the comments of each short manifest in an annotation on the Kubernetes object it was converted to.
+func attachComments(kokiModules []imports.Module, kubeObjs []interface{}) ([]interface{}, error) {
+	moduleCounts := map[string]int{}
+	for _, kokiModule := range kokiModules {
+		moduleCounts[kokiModule.Path]++
+	}
+
+	fileDocs := map[string][][]byte{}
+	moduleIndices := map[string]int{}
+	results := make([]interface{}, len(kubeObjs))
+	for i, kokiModule := range kokiModules {
+		results[i] = kubeObjs[i]
+		path := kokiModule.Path
+		index := moduleIndices[path]
+		moduleIndices[path]++
+
+		docs, ok := fileDocs[path]
+		if !ok {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				glog.Warningf("can't keep the comments of %s: %s", path, err)
+			} else if docs = comments.SplitDocuments(b); len(docs) != moduleCounts[path] {
+				glog.Warningf("can't keep the comments of %s: expected %d documents, found %d", path, moduleCounts[path], len(docs))
+				docs = nil
+			}
+			fileDocs[path] = docs
+		}
+		if docs == nil {
+			continue
+		}
+
+		docComments := comments.Extract(docs[index])
+		if len(docComments) == 0 {
+			continue
+		}
+		b, err := json.Marshal(docComments)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, docComments, "serializing comments of %s", path)
+		}
+
+		obj, err := objutil.ToDictionary(kubeObjs[i])
+		if err != nil {
 			return nil, err
 		}
-		kubeObjs = append(kubeObjs, kubeObj)
+		setAnnotation(obj, comments.Annotation, string(b))
+		results[i] = obj
+	}
+
+	return results, nil
+}
+
+// applyProfile adds the settings of the organization profile, if there is one, to Kubernetes objects.
+func applyProfile(kubeObjs []interface{}) ([]interface{}, error) {
+	p, err := profile.Load(profileFile)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return kubeObjs, nil
+	}
+
+	glog.V(3).Info("applying profile")
+	return p.Objs(kubeObjs)
+}
+
+// Provenance annotations, added with --provenance.
+const (
+	versionAnnotation    = "short.koki.io/version"
+	sourceAnnotation     = "short.koki.io/source"
+	sourceHashAnnotation = "short.koki.io/source-hash"
+)
+
+// attachProvenance records on each Kubernetes object which version of short converted it, and from which short manifest.
+func attachProvenance(kokiModules []imports.Module, kubeObjs []interface{}) ([]interface{}, error) {
+	fileHashes := map[string]string{}
+	results := make([]interface{}, len(kubeObjs))
+	for i, kokiModule := range kokiModules {
+		path := kokiModule.Path
+		hash, ok := fileHashes[path]
+		if !ok {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "hashing %s", path)
+			}
+			hash = sourceHash(b)
+			fileHashes[path] = hash
+		}
+
+		var err error
+		results[i], err = withProvenance(kubeObjs[i], path, hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// withProvenance adds the provenance annotations to a Kubernetes object, given its source and the source's hash.
+func withProvenance(kubeObj interface{}, source, hash string) (map[string]interface{}, error) {
+	obj, err := objutil.ToDictionary(kubeObj)
+	if err != nil {
+		return nil, err
+	}
+	setAnnotation(obj, versionAnnotation, GITCOMMIT)
+	setAnnotation(obj, sourceAnnotation, source)
+	setAnnotation(obj, sourceHashAnnotation, hash)
+
+	return obj, nil
+}
+
+func sourceHash(b []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
+
+// parseStdin parses the documents on stdin, and also returns the hash of stdin for --provenance.
+func parseStdin() ([]map[string]interface{}, string, error) {
+	b, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, "", serrors.ContextualizeErrorf(err, "reading stdin")
+	}
+	objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(bytes.NewReader(b))})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return objs, sourceHash(b), nil
+}
+
+func setAnnotation(obj map[string]interface{}, key, value string) {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations[key] = value
+}
+
+func hasComments(docComments []comments.Comments) bool {
+	for _, c := range docComments {
+		if len(c) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// detachComments removes the comments annotation from each Kubernetes object, and returns the comments it held.
+func detachComments(kubeObjs []map[string]interface{}) []comments.Comments {
+	docComments := make([]comments.Comments, len(kubeObjs))
+	for i, obj := range kubeObjs {
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		annotation, ok := annotations[comments.Annotation].(string)
+		if !ok {
+			continue
+		}
+
+		delete(annotations, comments.Annotation)
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+
+		err := json.Unmarshal([]byte(annotation), &docComments[i])
+		if err != nil {
+			glog.Warningf("ignoring the comments of %s (%s): %s", obj["kind"], metadata["name"], err)
+		}
+	}
+
+	return docComments
+}
+
+func implodeInput(useStdin bool) ([]interface{}, error) {
+	var streams []io.ReadCloser
+	if useStdin {
+		streams = []io.ReadCloser{os.Stdin}
+	} else {
+		var err error
+		streams, err = parser.OpenStreamsFromFiles(filenames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := []interface{}{}
+	for _, stream := range streams {
+		defer stream.Close()
+
+		imploded, err := objutil.Implode(stream)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range imploded {
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// applyCommon adds the common labels, annotations and name affixes to a converted Kubernetes object.
+func applyCommon(kubeObj interface{}) (interface{}, error) {
+	if common.IsEmpty() {
+		return kubeObj, nil
+	}
+	objs, err := common.ApplyToObjs([]interface{}{kubeObj})
+	if err != nil {
+		return nil, err
+	}
+
+	return objs[0], nil
+}
+
+// applyRedaction redacts a converted Kubernetes object for --redact-secrets.
+func applyRedaction(kubeObj interface{}) (interface{}, error) {
+	if redaction == nil {
+		return kubeObj, nil
+	}
+	objs, err := redaction.ApplyToObjs([]interface{}{kubeObj})
+	if err != nil {
+		return nil, err
+	}
+
+	return objs[0], nil
+}
+
+// applyFixDeprecated migrates the deprecated apiVersion and fields of a converted Kubernetes object for --fix-deprecated.
+func applyFixDeprecated(kubeObj interface{}) (interface{}, error) {
+	if !fixDeprecated {
+		return kubeObj, nil
+	}
+	obj, err := objutil.ToDictionary(kubeObj)
+	if err != nil {
+		return nil, err
+	}
+	fixed, ok, err := migrate.FixDeprecated(obj)
+	if err != nil || !ok {
+		return kubeObj, err
+	}
+
+	return fixed, nil
+}
+
+// fixDeprecatedMap is applyFixDeprecated for a Kubernetes document that's yet to be converted.
+func fixDeprecatedMap(obj map[string]interface{}) (map[string]interface{}, error) {
+	fixed, err := applyFixDeprecated(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return objutil.ToDictionary(fixed)
+}
+
+// writeGitOpsWrapper writes the --gitops objects for the -o split directory to a file next to it, named
+// after them and the tool, e.g. web.argocd.yaml for deploy/web. It returns the file's path.
+func writeGitOpsWrapper(dir string) (string, error) {
+	o := gitopsOptions
+	source, err := gitops.DetectSource(dir, o.Source)
+	if err != nil {
+		return "", err
+	}
+	o.Source = source
+	if len(o.Name) == 0 {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		o.Name = filepath.Base(abs)
+	}
+
+	objs, err := gitops.Wrap(o)
+	if err != nil {
+		return "", err
+	}
+	wrappers := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		wrappers[i] = obj
+	}
+	buf := &bytes.Buffer{}
+	err = client.WriteObjsToYamlStream(wrappers, buf)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(filepath.Dir(filepath.Clean(dir)), fmt.Sprintf("%s.%s.yaml", o.Name, o.Tool))
+	err = ioutil.WriteFile(path, buf.Bytes(), 0644)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "writing %s", path)
+	}
+
+	return path, nil
+}
+
+// humanizeShortObjs is humanize.Objs for the short objects among objs, leaving Kubernetes objects
+// (converted from short syntax documents) as they are.
+func humanizeShortObjs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		if _, ok := obj.(runtime.Object); ok {
+			results[i] = obj
+			continue
+		}
+		humanized, err := humanize.Objs([]interface{}{obj})
+		if err != nil {
+			return nil, err
+		}
+		results[i] = humanized[0]
+	}
+
+	return results, nil
+}
+
+// checkCompat fails if Kubernetes objects use apiVersions or fields that the --as-of release doesn't have.
+func checkCompat(kubeObjs []map[string]interface{}) error {
+	problems := []string{}
+	for _, obj := range kubeObjs {
+		objProblems, err := compat.Check(obj, asOf)
+		if err != nil {
+			return err
+		}
+
+		name := ""
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+		for _, problem := range objProblems {
+			problems = append(problems, fmt.Sprintf("%s (%s): %s", obj["kind"], name, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("not available in Kubernetes %s:\n  %s", asOf, strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+// mapSources finds where the fields of each module's Kubernetes object come from, for --source-map.
+func mapSources(kokiModules []imports.Module) ([]*sourcemap.Source, error) {
+	moduleCounts := map[string]int{}
+	for _, kokiModule := range kokiModules {
+		moduleCounts[kokiModule.Path]++
+	}
+	fileLines := map[string][]map[string]int{}
+	for path := range moduleCounts {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading %s for its source map", path)
+		}
+		if docs := sourcemap.DocumentLines(b); len(docs) == moduleCounts[path] {
+			fileLines[path] = docs
+		} else {
+			glog.Warningf("the source map won't have the lines of %s: expected %d documents, found %d", path, moduleCounts[path], len(docs))
+		}
+	}
+
+	documents := moduleDocuments(kokiModules)
+	sources, err := convert.Parallel(len(kokiModules), parallelism, func(i int) (interface{}, error) {
+		kokiModule := kokiModules[i]
+		fields, err := sourcemap.Fields(kokiModule.Export.Raw, func(obj map[string]interface{}) (map[string]interface{}, error) {
+			kubeObjs, err := client.ConvertKokiMaps([]map[string]interface{}{obj})
+			if err != nil {
+				return nil, err
+			}
+			return objutil.ToDictionary(kubeObjs[0])
+		})
+		if err != nil {
+			return nil, client.NewDocumentError("mapping", kokiModule.Path, documents[i], kokiModule.Export.Raw, err)
+		}
+
+		source := &sourcemap.Source{
+			File:     kokiModule.Path,
+			Document: documents[i],
+			Fields:   fields,
+		}
+		if lines, ok := fileLines[kokiModule.Path]; ok {
+			source.Lines = lines[documents[i]]
+		}
+		return source, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*sourcemap.Source, len(sources))
+	for i, source := range sources {
+		results[i] = source.(*sourcemap.Source)
+	}
+	return results, nil
+}
+
+// writeSourceMap writes the source map of YAML output to sourceMapFile.
+func writeSourceMap(output []byte, sources []*sourcemap.Source) error {
+	b, err := json.MarshalIndent(sourcemap.Build(output, sources), "", "  ")
+	if err != nil {
+		return serrors.InvalidValueContextErrorf(err, sources, "serializing the source map")
+	}
+	err = ioutil.WriteFile(sourceMapFile, append(b, '\n'), 0644)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "writing the source map to %s", sourceMapFile)
 	}
 
-	return kubeObjs, nil
+	return nil
 }
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/koki/short/converter/converters"
+)
+
+func TestConversionCacheSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "short-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(location string, keep bool) {
+		cacheLocation, keepUnsupported, conversionCache = location, keep, nil
+	}(cacheLocation, keepUnsupported)
+	cacheLocation = dir
+
+	obj := map[string]interface{}{"kind": "ConfigMap"}
+	convertWith := func(keep bool, result string) interface{} {
+		keepUnsupported = keep
+		if err := openConversionCache(); err != nil {
+			t.Fatal(err)
+		}
+		converted, _, err := convertCached(obj, true, func(*converters.Warnings) (interface{}, error) {
+			return result, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return converted
+	}
+
+	convertWith(true, "kept")
+	if converted := convertWith(true, "converted again"); converted != "kept" {
+		t.Errorf("expected the cached result, got %v", converted)
+	}
+	// Without --keep-unsupported, the result of a --keep-unsupported run mustn't be reused.
+	if converted := convertWith(false, "failed"); converted != "failed" {
+		t.Errorf("expected a new conversion, got %v", converted)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/site"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	siteCmd = &cobra.Command{
+		Use:   "site",
+		Short: "Generate a static HTML site for browsing manifests",
+		Long: `Site generates static HTML pages for a set of manifests.
+
+There is a page per namespace, and a page per object that shows its short and Kubernetes syntax side by side,
+along with the ConfigMaps, Secrets, PVCs and ServiceAccounts it references and the objects that reference it.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runSite(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Generate a site for a directory of manifests
+  short site -f manifests/ -o ./site
+`,
+	}
+
+	// siteDir denotes the directory to write the site into
+	siteDir string
+)
+
+func init() {
+	siteCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	siteCmd.Flags().StringVarP(&siteDir, "output", "o", "site", "directory to write the site into")
+}
+
+func runSite(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	s, err := site.Build(objs)
+	if err != nil {
+		return err
+	}
+
+	return s.Write(siteDir)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/table"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	tableCmd = &cobra.Command{
+		Use:   "table",
+		Short: "Export fields of manifests as a table",
+		Long: `Table prints selected fields of each object as CSV, TSV or a markdown table.
+
+Columns are paths into the short representation of each object, and "*" matches every entry of a map or list.
+The "kind" column is the short resource name, e.g. "deployment".
+Kube-native manifests are converted to short syntax first.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runTable(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Summarize every manifest in a directory as CSV
+  short table -f manifests/ --columns kind,name,replicas,image=containers.*.image
+
+  # Produce a markdown table for a report
+  short table -f app.yaml --columns kind,name,cpu=containers.*.cpu.min --format markdown
+`,
+	}
+
+	// tableColumns denotes the comma-separated columns to export
+	tableColumns string
+
+	// tableFormat denotes the output format: csv, tsv or markdown
+	tableFormat string
+)
+
+func init() {
+	tableCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	tableCmd.Flags().StringVar(&tableColumns, "columns", "kind,name", "comma-separated columns, each 'path' or 'header=path'")
+	tableCmd.Flags().StringVar(&tableFormat, "format", table.FormatCSV, "output format: csv, tsv or markdown")
+}
+
+func runTable(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	columns, err := table.ParseColumns(tableColumns)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+	if err != nil {
+		return err
+	}
+
+	rows, err := table.Rows(kokiObjs, columns)
+	if err != nil {
+		return err
+	}
+
+	return table.Write(os.Stdout, tableFormat, columns, rows)
+}
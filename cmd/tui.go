@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/site"
+	"github.com/koki/short/tui"
+	serrors "github.com/koki/structurederrors"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse manifests in the terminal",
+	Long: `Tui opens a terminal browser for a set of manifests.
+
+Objects are listed by namespace, kind and name. Opening an object shows its short and Kubernetes syntax side by side.
+Mark an object with 'm' and press 'd' on another to diff their short syntax.
+Objects copied with 'c' are printed in short syntax when the browser exits, so they can be piped or redirected.
+`,
+	RunE: func(c *cobra.Command, args []string) error {
+		err := runTui(c, args)
+		if err != nil {
+			return fmt.Errorf("%s", serrors.PrettyError(err))
+		}
+
+		return nil
+	},
+	SilenceUsage: true,
+	Example: `
+  # Browse a directory of manifests
+  short tui -f manifests/
+
+  # Save the objects copied while browsing
+  short tui -f manifests/ > snippets.short.yaml
+`,
+}
+
+func init() {
+	tuiCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+}
+
+func runTui(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	glog.V(3).Info("parsing input data")
+	objs, err := parser.Parse(inputs, len(inputs) == 0)
+	if err != nil {
+		return err
+	}
+
+	s, err := site.Build(objs)
+	if err != nil {
+		return err
+	}
+
+	browser := tui.NewBrowser(s.Objects)
+	err = tui.Run(browser)
+	if err != nil {
+		return err
+	}
+
+	if len(browser.Copied) > 0 {
+		fmt.Print(strings.Join(browser.Copied, "---\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/unused"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	unusedCmd = &cobra.Command{
+		Use:   "unused",
+		Short: "Find ConfigMaps, Secrets, PVCs and ServiceAccounts that nothing references",
+		Long: `Unused lists the ConfigMaps, Secrets, PVCs and ServiceAccounts defined in the input manifests
+that no other input object references.
+
+With --cluster, objects used by pods running in the cluster also count as referenced, so objects
+used by workloads managed elsewhere aren't reported. This runs kubectl with its current context.
+
+With --fix, manifest files that only define unused objects are moved to the attic directory.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runUnused(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # List unused objects
+  short unused -f manifests/
+
+  # Also check what running pods use, then move unused manifests to attic/
+  short unused -f manifests/ --cluster --fix
+`,
+	}
+
+	// unusedCluster denotes that references from running pods should be checked too
+	unusedCluster bool
+	// unusedContext is the kubectl context of the cluster to check
+	unusedContext string
+	// unusedFix denotes that files with only unused objects should be moved to the attic
+	unusedFix bool
+	// unusedAttic is the directory that unused files are moved to
+	unusedAttic string
+)
+
+func init() {
+	unusedCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	unusedCmd.Flags().BoolVar(&unusedCluster, "cluster", false, "also count objects used by pods running in the cluster as referenced")
+	unusedCmd.Flags().StringVar(&unusedContext, "context", "", "kubectl context of the cluster (default the current context)")
+	unusedCmd.Flags().BoolVar(&unusedFix, "fix", false, "move files that only define unused objects to the attic directory")
+	unusedCmd.Flags().StringVar(&unusedAttic, "attic", "attic", "directory to move unused files to")
+}
+
+func runUnused(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	files, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	objects, refs, err := unused.Read(files)
+	if err != nil {
+		return err
+	}
+
+	if unusedCluster {
+		liveRefs, err := unused.LiveRefs(unusedContext)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, liveRefs...)
+	}
+
+	unusedObjects := unused.Find(objects, refs)
+	if len(unusedObjects) == 0 {
+		fmt.Println("no unused objects")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tFILE")
+	for _, object := range unusedObjects {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", object.Kind, object.Namespace, object.Name, object.File)
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if !unusedFix {
+		return nil
+	}
+
+	moved, kept, err := unused.MoveToAttic(objects, unusedObjects, unusedAttic)
+	if err != nil {
+		return err
+	}
+	for _, file := range moved {
+		fmt.Printf("moved %s to %s\n", file, unusedAttic)
+	}
+	for _, file := range kept {
+		fmt.Printf("kept %s, it also defines objects in use\n", file)
+	}
+
+	return nil
+}
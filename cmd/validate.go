@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/owners"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/validate"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check manifests against the Kubernetes OpenAPI schema before applying them",
+		Long: `Validate converts the input manifests to Kubernetes objects and checks them against the OpenAPI
+schema of a Kubernetes release. It reports unknown fields, values of the wrong type and missing
+required fields, which the API server would otherwise only reject at apply time.
+
+The schema is read from a file (--schema), e.g. one saved with "kubectl get --raw /openapi/v2",
+or fetched from a live cluster (--cluster) with kubectl and its current context.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runValidate(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Validate against a saved schema
+  kubectl get --raw /openapi/v2 > openapi.json
+  short validate -f manifests/ --schema openapi.json
+
+  # Validate against the cluster of a kubectl context
+  short validate -f pod.short.yaml --cluster --context staging
+`,
+	}
+
+	// validateSchema is the path of the OpenAPI schema file
+	validateSchema string
+	// validateCluster denotes that the schema should be fetched from a live cluster
+	validateCluster bool
+	// validateContext is the kubectl context of the cluster to fetch the schema from
+	validateContext string
+)
+
+func init() {
+	validateCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	validateCmd.Flags().StringVar(&validateSchema, "schema", "", "path to a Kubernetes OpenAPI (v2) schema")
+	validateCmd.Flags().BoolVar(&validateCluster, "cluster", false, "fetch the OpenAPI schema from the cluster")
+	validateCmd.Flags().StringVar(&validateContext, "context", "", "kubectl context of the cluster (default the current context)")
+	addOwnersFlag(validateCmd.Flags())
+}
+
+func runValidate(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+	if len(validateSchema) > 0 == validateCluster {
+		return serrors.UsageErrorf(c.CommandPath(), "exactly one of --schema and --cluster is required")
+	}
+
+	files, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+	o, err := owners.Load(ownersFile)
+	if err != nil {
+		return err
+	}
+
+	var schema *validate.Schema
+	if validateCluster {
+		schema, err = validate.FetchSchema(validateContext)
+	} else {
+		var data []byte
+		data, err = ioutil.ReadFile(validateSchema)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "reading schema %s", validateSchema)
+		}
+		schema, err = validate.LoadSchema(data)
+	}
+	if err != nil {
+		return err
+	}
+
+	problems, err := schema.Files(files)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+
+	if o != nil {
+		findings := make([]owners.Finding, len(problems))
+		for i, problem := range problems {
+			findings[i] = owners.Finding{File: problem.File, Labels: problem.Labels, Text: problem.String()}
+		}
+		return reportByOwner(o, findings)
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	return fmt.Errorf("%d problems found", len(problems))
+}
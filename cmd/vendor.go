@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/lock"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	vendorCmd = &cobra.Command{
+		Use:   "vendor",
+		Short: "Pin imports and image digests for offline use",
+		Long: `Vendor copies every file imported by the input Short manifests into a vendor directory,
+and writes a lock file with the hash of each import and the digest of each container image.
+
+Pass the vendor directory to later conversions with '--vendor-dir' to read imports from it, and
+to pin each image to its locked digest.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runVendor(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", prettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Vendor the dependencies of a directory of manifests
+  short vendor -f manifests/ -o vendor
+
+  # Convert using only the vendored imports
+  short -k -f manifests/app.short.yaml --vendor-dir vendor
+`,
+	}
+
+	// vendorOutputDir denotes the directory to write vendored imports and the lock file into
+	vendorOutputDir string
+
+	// vendorResolveDigests denotes that image digests should be fetched from registries
+	vendorResolveDigests bool
+
+	// vendorRegistryTimeout denotes how long to wait for each registry request
+	vendorRegistryTimeout time.Duration
+)
+
+func init() {
+	vendorCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	vendorCmd.Flags().StringVarP(&vendorOutputDir, "output", "o", "vendor", "directory to write vendored imports and the lock file into")
+	vendorCmd.Flags().BoolVar(&vendorResolveDigests, "digests", true, "resolve image digests from their registries")
+	vendorCmd.Flags().DurationVar(&vendorRegistryTimeout, "registry-timeout", 30*time.Second, "timeout for each registry request")
+}
+
+func runVendor(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	l, err := buildLock(inputs)
+	if err != nil {
+		return err
+	}
+
+	return lock.Vendor(l, vendorOutputDir)
+}
+
+// buildLock collects the imports and images used by the input files and pins them.
+func buildLock(inputs []string) (*lock.Lock, error) {
+	glog.V(3).Info("collecting imports")
+	importPaths, err := lock.CollectImports(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Imported files are evaluated as part of the files that import them.
+	isImported := map[string]bool{}
+	for _, path := range importPaths {
+		isImported[path] = true
+	}
+	topLevel := []string{}
+	for _, input := range inputs {
+		if !isImported[filepath.Clean(input)] {
+			topLevel = append(topLevel, input)
+		}
+	}
+
+	glog.V(3).Info("collecting images")
+	modules, _, _, err := loadKokiFiles(topLevel)
+	if err != nil {
+		return nil, err
+	}
+	objs := []map[string]interface{}{}
+	for _, module := range modules {
+		objs = append(objs, module.Export.Raw)
+	}
+	images := lock.CollectImages(objs)
+
+	var resolver lock.Resolver
+	if vendorResolveDigests {
+		resolver = lock.NewRegistryResolver(vendorRegistryTimeout)
+	}
+
+	return lock.Build(importPaths, images, resolver)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/lock"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+var (
+	verifyLockCmd = &cobra.Command{
+		Use:   "verify-lock",
+		Short: "Check that the lock file matches the inputs",
+		Long: `Verify-lock rebuilds the lock file for the input Short manifests and compares it with the one
+written by the vendor command. It fails if an import was added, removed or changed, if the set of
+container images changed, or if an image tag now resolves to a different digest.
+
+The vendored copy of each import is also checked against its locked hash.
+`,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := runVerifyLock(c, args)
+			if err != nil {
+				return fmt.Errorf("%s", serrors.PrettyError(err))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+		Example: `
+  # Fail a CI build if manifests/ drifted from vendor/short.lock
+  short verify-lock -f manifests/ --dir vendor
+
+  # Skip registry lookups
+  short verify-lock -f manifests/ --dir vendor --digests=false
+`,
+	}
+
+	// verifyLockDir denotes the vendor directory holding the lock file to verify
+	verifyLockDir string
+)
+
+func init() {
+	verifyLockCmd.Flags().StringSliceVarP(&filenames, "filenames", "f", nil, "path to input files or directories to read manifests")
+	verifyLockCmd.Flags().StringVar(&verifyLockDir, "dir", "vendor", "vendor directory holding the lock file")
+	verifyLockCmd.Flags().BoolVar(&vendorResolveDigests, "digests", true, "resolve image digests from their registries and compare them")
+	verifyLockCmd.Flags().DurationVar(&vendorRegistryTimeout, "registry-timeout", 30*time.Second, "timeout for each registry request")
+}
+
+func runVerifyLock(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "unexpected values %q", args)
+	}
+	if len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "at least one input file is required")
+	}
+
+	locked, err := lock.ReadLock(verifyLockDir)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := parser.ExpandFilenames(filenames)
+	if err != nil {
+		return err
+	}
+
+	current, err := buildLock(inputs)
+	if err != nil {
+		return err
+	}
+
+	problems := append(lock.Verify(locked, current), lock.VerifyVendored(locked, verifyLockDir)...)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is out of date:\n  %s", filepath.Join(verifyLockDir, lock.LockFilename), strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
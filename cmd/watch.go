@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/koki/short/watch"
+	serrors "github.com/koki/structurederrors"
+)
+
+// watchInput converts each input file again whenever it changes, until the process is stopped.
+func watchInput(c *cobra.Command) error {
+	if !kubeNative || implode || len(filenames) == 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "--watch only applies when converting short manifest files (-f) to kube-native syntax (-k)")
+	}
+	if watchApply && len(outDir) > 0 {
+		return serrors.UsageErrorf(c.CommandPath(), "--apply and --out-dir can't be used together")
+	}
+	// convertWatchedFile replaces filenames with the file it converts.
+	inputs := filenames
+	// sources are the input files written to each output file, so that two never overwrite each other.
+	sources := map[string]string{}
+	if len(outDir) > 0 {
+		err := os.MkdirAll(outDir, 0755)
+		if err != nil {
+			return err
+		}
+		for _, file := range inputs {
+			if info, err := os.Stat(file); err == nil && info.IsDir() {
+				continue
+			}
+			outFile := outputPath(inputs, file)
+			if source, ok := sources[outFile]; ok {
+				return serrors.UsageErrorf(c.CommandPath(), "%s and %s would both be written to %s", source, file, outFile)
+			}
+			sources[outFile] = file
+		}
+	}
+
+	watcher := watch.NewWatcher(inputs)
+	onChange := func(files []string) error {
+		for _, file := range files {
+			if len(outDir) > 0 && isWithin(file, outDir) {
+				continue
+			}
+
+			glog.V(1).Infof("converting %s", file)
+			err := convertWatchedFile(inputs, sources, file)
+			if err != nil {
+				return serrors.ContextualizeErrorf(err, "converting %s", file)
+			}
+		}
+
+		return nil
+	}
+	onError := func(err error) {
+		fmt.Fprintln(os.Stderr, prettyError(err))
+	}
+
+	return watcher.Run(watchInterval, nil, onChange, onError)
+}
+
+// convertWatchedFile converts one of the watched files. With --out-dir, sources are the input files written to each
+// output file so far.
+func convertWatchedFile(inputs []string, sources map[string]string, file string) error {
+	filenames = []string{file}
+	buf := &bytes.Buffer{}
+	err := convertInput(false, buf)
+	if err != nil {
+		return err
+	}
+
+	if watchApply {
+		return kubectlFlags.Run(buf, "apply", "-f", "-")
+	}
+
+	if output == "split" {
+		// The objects are written to files already.
+		fmt.Print(buf.String())
+		return nil
+	}
+
+	if len(outDir) > 0 {
+		outFile := outputPath(inputs, file)
+		if source, ok := sources[outFile]; ok && source != file {
+			return serrors.InvalidValueErrorf(file, "%s is written to %s already", source, outFile)
+		}
+		sources[outFile] = file
+		err = os.MkdirAll(filepath.Dir(outFile), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(outFile, buf.Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", outFile)
+		return nil
+	}
+
+	fmt.Printf("# %s\n---\n%s", file, buf.String())
+	return nil
+}
+
+// outputPath is where --out-dir has the manifest of a watched file. Files in a watched directory keep their
+// path in it, e.g. "manifests/db/pg.short.yaml" is written to "db/pg.yaml" when "manifests" is watched.
+func outputPath(inputs []string, file string) string {
+	name := outputName(file)
+	for _, input := range inputs {
+		if input == file {
+			break
+		}
+		if rel, err := filepath.Rel(input, filepath.Dir(file)); err == nil && isWithin(file, input) {
+			name = filepath.Join(rel, name)
+			break
+		}
+	}
+
+	return filepath.Join(outDir, name)
+}
+
+// outputName is the name of the kube-native manifest for a short file, e.g. "web.yaml" for "web.short.yaml".
+func outputName(file string) string {
+	name := filepath.Base(file)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.TrimSuffix(name, ".short")
+	if output == "json" || output == "jsonl" {
+		return name + ".json"
+	}
+
+	return name + ".yaml"
+}
+
+// isWithin is true if the file is inside the directory.
+func isWithin(file, dir string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
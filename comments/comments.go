@@ -0,0 +1,387 @@
+package comments
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+
+Comments in hand-written short manifests are lost when they're parsed, so they're carried
+through a conversion separately: each comment is attached to the path of the line it's on
+or above, e.g. "deployment.containers.0.image", and inserted again at the same path.
+
+When converting to Kubernetes syntax, the comments are kept in an annotation on the object,
+and converting back to short syntax moves them from the annotation back into the manifest.
+Comments whose path no longer exists are attached to the nearest parent that does.
+
+Lines are matched by indentation, which covers the block style that short writes and that
+manifests are usually written in. Flow style ("{...}" or "[...]") is treated as a single line.
+
+*/
+
+// Annotation holds the comments of a short manifest on the Kubernetes object it was converted to.
+const Annotation = "short.koki.io/comments"
+
+// Comment is the comments attached to a line.
+type Comment struct {
+	// Above are the comment lines above the line, e.g. "# Serves the web UI".
+	Above []string `json:"above,omitempty"`
+	// Inline is the comment at the end of the line.
+	Inline string `json:"inline,omitempty"`
+}
+
+// Comments maps the path of each commented line to its comments.
+// Comments after the last line of a document have the path "".
+type Comments map[string]Comment
+
+type frame struct {
+	col     int
+	segment string
+	item    bool
+	// items counts the list items under a key.
+	items int
+}
+
+// tracker follows the path of each line of a YAML document.
+type tracker struct {
+	frames   []*frame
+	blockCol int
+	// inlinePath is the path that an inline comment on the last line belongs to. It's the path of the line,
+	// except for a list item that starts with a key ("- image: nginx # pinned"), where it's the key's path.
+	inlinePath string
+}
+
+func newTracker() *tracker {
+	return &tracker{blockCol: -1}
+}
+
+func (t *tracker) path() string {
+	segments := make([]string, len(t.frames))
+	for i, f := range t.frames {
+		segments[i] = f.segment
+	}
+
+	return strings.Join(segments, ".")
+}
+
+func (t *tracker) pop(keep func(f *frame) bool) {
+	for len(t.frames) > 0 && !keep(t.frames[len(t.frames)-1]) {
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+}
+
+// next returns the path of a line, or false if it isn't a key or a list item.
+func (t *tracker) next(line string) (string, bool) {
+	content := strings.TrimLeft(line, " ")
+	col := len(line) - len(content)
+	if len(strings.TrimSpace(content)) == 0 {
+		return "", false
+	}
+
+	// Skip the contents of block scalars ("key: |").
+	if t.blockCol >= 0 {
+		if col > t.blockCol {
+			return "", false
+		}
+		t.blockCol = -1
+	}
+
+	if strings.HasPrefix(content, "#") {
+		return "", false
+	}
+	if content == "---" || strings.HasPrefix(content, "--- ") {
+		t.frames = nil
+		return "", false
+	}
+
+	code, _ := splitInline(content)
+	if code == "-" || strings.HasPrefix(code, "- ") {
+		t.pop(func(f *frame) bool {
+			return f.col < col || (f.col == col && !f.item)
+		})
+		index := 0
+		if len(t.frames) > 0 {
+			owner := t.frames[len(t.frames)-1]
+			index = owner.items
+			owner.items++
+		}
+		t.frames = append(t.frames, &frame{col: col, segment: strconv.Itoa(index), item: true})
+		path := t.path()
+		t.inlinePath = path
+
+		rest := strings.TrimPrefix(code, "-")
+		restContent := strings.TrimLeft(rest, " ")
+		restCol := col + 1 + len(rest) - len(restContent)
+		if key, value, ok := parseKey(restContent); ok {
+			t.frames = append(t.frames, &frame{col: restCol, segment: key})
+			t.inlinePath = t.path()
+			if isBlockScalar(value) {
+				t.blockCol = restCol
+			}
+		} else if isBlockScalar(restContent) {
+			t.blockCol = col
+		}
+
+		return path, true
+	}
+
+	key, value, ok := parseKey(code)
+	if !ok {
+		return "", false
+	}
+
+	t.pop(func(f *frame) bool {
+		return f.col < col
+	})
+	t.frames = append(t.frames, &frame{col: col, segment: key})
+	if isBlockScalar(value) {
+		t.blockCol = col
+	}
+	t.inlinePath = t.path()
+
+	return t.inlinePath, true
+}
+
+func isBlockScalar(value string) bool {
+	return strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">")
+}
+
+// parseKey splits "key: value" or "key:" into its key and value.
+func parseKey(code string) (string, string, bool) {
+	if len(code) == 0 {
+		return "", "", false
+	}
+
+	end := -1
+	if quote := code[0]; quote == '"' || quote == '\'' {
+		closing := strings.IndexByte(code[1:], quote)
+		if closing < 0 {
+			return "", "", false
+		}
+		end = closing + 2
+		if end < len(code) && code[end] != ':' {
+			return "", "", false
+		}
+	} else {
+		for i := 0; i < len(code); i++ {
+			if code[i] == ':' && (i+1 == len(code) || code[i+1] == ' ') {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 || end >= len(code) {
+		return "", "", false
+	}
+
+	key := code[:end]
+	if unquoted, err := strconv.Unquote(key); err == nil {
+		key = unquoted
+	} else if strings.HasPrefix(key, "'") {
+		key = strings.Replace(strings.Trim(key, "'"), "''", "'", -1)
+	}
+
+	return key, strings.TrimSpace(code[end+1:]), true
+}
+
+// splitInline splits a line into its content and its comment, if it has one.
+func splitInline(content string) (string, string) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || content[i-1] == ' ' || content[i-1] == '-' || content[i-1] == ':' {
+				quote = c
+			}
+		case c == '#' && i > 0 && content[i-1] == ' ':
+			return strings.TrimRight(content[:i], " "), content[i:]
+		}
+	}
+
+	return strings.TrimRight(content, " "), ""
+}
+
+func lines(doc []byte) []string {
+	result := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(doc))
+	scanner.Buffer(make([]byte, 64*1024), len(doc)+1)
+	for scanner.Scan() {
+		result = append(result, scanner.Text())
+	}
+
+	return result
+}
+
+// Extract finds the comments in a YAML document.
+func Extract(doc []byte) Comments {
+	comments := Comments{}
+	t := newTracker()
+	var above []string
+	for _, line := range lines(doc) {
+		content := strings.TrimSpace(line)
+		inBlock := t.blockCol >= 0 && len(line)-len(strings.TrimLeft(line, " ")) > t.blockCol
+		if strings.HasPrefix(content, "#") && !inBlock {
+			above = append(above, content)
+			continue
+		}
+
+		path, ok := t.next(line)
+		if !ok {
+			continue
+		}
+
+		if len(above) > 0 {
+			comments[path] = Comment{Above: above}
+			above = nil
+		}
+		if _, inline := splitInline(strings.TrimLeft(line, " ")); len(inline) > 0 {
+			comment := comments[t.inlinePath]
+			comment.Inline = inline
+			comments[t.inlinePath] = comment
+		}
+	}
+	if len(above) > 0 {
+		comments[""] = Comment{Above: above}
+	}
+
+	return comments
+}
+
+// LinePaths returns the path of each line of a YAML document, e.g. "deployment.containers.0.image",
+// or "" for lines that aren't a key or a list item. A list item's line has the item's path,
+// even if it starts with a key ("- name: web").
+func LinePaths(doc []byte) []string {
+	paths := []string{}
+	t := newTracker()
+	for _, line := range lines(doc) {
+		path, ok := t.next(line)
+		if !ok {
+			path = ""
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// Insert adds comments to a YAML document. Comments whose path isn't in the document
+// are attached to the nearest parent that is, or to the end of the document.
+func Insert(doc []byte, comments Comments) []byte {
+	if len(comments) == 0 {
+		return doc
+	}
+
+	docLines := lines(doc)
+	paths := map[string]bool{}
+	t := newTracker()
+	for _, line := range docLines {
+		if path, ok := t.next(line); ok {
+			paths[path] = true
+			paths[t.inlinePath] = true
+		}
+	}
+
+	commentPaths := []string{}
+	for path := range comments {
+		commentPaths = append(commentPaths, path)
+	}
+	sort.Strings(commentPaths)
+
+	placed := Comments{}
+	for _, path := range commentPaths {
+		comment := comments[path]
+		target := path
+		for len(target) > 0 && !paths[target] {
+			if i := strings.LastIndex(target, "."); i >= 0 {
+				target = target[:i]
+			} else {
+				target = ""
+			}
+		}
+
+		existing := placed[target]
+		if target == path {
+			existing.Above = append(append([]string{}, comment.Above...), existing.Above...)
+			existing.Inline = comment.Inline
+		} else {
+			existing.Above = append(existing.Above, comment.Above...)
+			if len(comment.Inline) > 0 {
+				existing.Above = append(existing.Above, comment.Inline)
+			}
+		}
+		placed[target] = existing
+	}
+
+	buf := &bytes.Buffer{}
+	t = newTracker()
+	for _, line := range docLines {
+		path, ok := t.next(line)
+		comment, found := placed[path]
+		// The first key of a list item is on the item's line, so its comments go there too.
+		if keyComment, keyFound := placed[t.inlinePath]; ok && keyFound && t.inlinePath != path {
+			comment.Above = append(comment.Above, keyComment.Above...)
+			if len(keyComment.Inline) > 0 {
+				comment.Inline = keyComment.Inline
+			}
+			found = true
+			delete(placed, t.inlinePath)
+		}
+		if !ok || !found {
+			buf.WriteString(line + "\n")
+			continue
+		}
+
+		indent := strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " ")))
+		for _, above := range comment.Above {
+			buf.WriteString(indent + above + "\n")
+		}
+		if len(comment.Inline) > 0 {
+			line = line + " " + comment.Inline
+		}
+		buf.WriteString(line + "\n")
+		delete(placed, path)
+	}
+	for _, above := range placed[""].Above {
+		buf.WriteString(above + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+// SplitDocuments splits a YAML stream into its documents, leaving out documents without content.
+func SplitDocuments(data []byte) [][]byte {
+	docs := [][]byte{}
+	doc := &bytes.Buffer{}
+	hasContent := false
+	flush := func() {
+		if hasContent {
+			docs = append(docs, doc.Bytes())
+		}
+		doc = &bytes.Buffer{}
+		hasContent = false
+	}
+
+	for _, line := range lines(data) {
+		if line == "---" || strings.HasPrefix(line, "--- ") {
+			flush()
+			continue
+		}
+		content := strings.TrimSpace(line)
+		if len(content) > 0 && !strings.HasPrefix(content, "#") {
+			hasContent = true
+		}
+		doc.WriteString(line + "\n")
+	}
+	flush()
+
+	return docs
+}
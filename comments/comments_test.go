@@ -0,0 +1,123 @@
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+const commented = `# The web frontend.
+deployment:
+  name: web # public name
+  replicas: 3
+  containers:
+  # The app itself.
+  - name: web # the app's container
+    image: nginx:1.13
+    args:
+    - --port=80 # must match the service
+    command: |
+      # not a comment
+      run
+  # Ships logs.
+  - image: fluentd:v1.0 # pinned
+    name: logger
+  labels:
+    'app': web
+# End of the web frontend.
+`
+
+func TestExtract(t *testing.T) {
+	expected := Comments{
+		"deployment":                     {Above: []string{"# The web frontend."}},
+		"deployment.name":                {Inline: "# public name"},
+		"deployment.containers.0":        {Above: []string{"# The app itself."}},
+		"deployment.containers.0.name":   {Inline: "# the app's container"},
+		"deployment.containers.0.args.0": {Inline: "# must match the service"},
+		"deployment.containers.1":        {Above: []string{"# Ships logs."}},
+		"deployment.containers.1.image":  {Inline: "# pinned"},
+		"":                               {Above: []string{"# End of the web frontend."}},
+	}
+
+	comments := Extract([]byte(commented))
+	if !reflect.DeepEqual(comments, expected) {
+		t.Error(pretty.Diff(comments, expected))
+	}
+}
+
+func TestInsert(t *testing.T) {
+	comments := Extract([]byte(commented))
+
+	// As written by short: sorted, with lists at the same indentation as their key.
+	written := `deployment:
+  name: web
+  containers:
+  - args:
+    - --port=80
+    command: |
+      # not a comment
+      run
+    image: nginx:1.13
+    name: web
+  - image: fluentd:v1.0
+    name: logger
+  labels:
+    app: web
+  replicas: 3
+`
+	// Inline comments on the first key of a list item stay with the key, wherever it's written.
+	expected := `# The web frontend.
+deployment:
+  name: web # public name
+  containers:
+  # The app itself.
+  - args:
+    - --port=80 # must match the service
+    command: |
+      # not a comment
+      run
+    image: nginx:1.13
+    name: web # the app's container
+  # Ships logs.
+  - image: fluentd:v1.0 # pinned
+    name: logger
+  labels:
+    app: web
+  replicas: 3
+# End of the web frontend.
+`
+	if inserted := string(Insert([]byte(written), comments)); inserted != expected {
+		t.Errorf("unexpected comments:\n%s", inserted)
+	}
+
+	// Comments on fields that are gone move to the nearest parent.
+	written = `deployment:
+  name: web
+  containers:
+  - image: nginx:1.13
+    name: web
+`
+	expected = `# The web frontend.
+deployment:
+  name: web # public name
+  # Ships logs.
+  # pinned
+  containers:
+  # The app itself.
+  # must match the service
+  - image: nginx:1.13
+    name: web # the app's container
+# End of the web frontend.
+`
+	if inserted := string(Insert([]byte(written), comments)); inserted != expected {
+		t.Errorf("unexpected comments:\n%s", inserted)
+	}
+}
+
+func TestSplitDocuments(t *testing.T) {
+	docs := SplitDocuments([]byte("# header\n---\na: 1\n---\n# b\nb: 2\n"))
+	if len(docs) != 2 || string(docs[1]) != "# b\nb: 2\n" {
+		t.Errorf("unexpected documents %q", docs)
+	}
+}
@@ -0,0 +1,192 @@
+package compat
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Compat checks Kubernetes objects against an older Kubernetes release, for manifests that
+are maintained on long-lived release branches.
+
+It knows which apiVersions and fields each release introduced, starting from 1.8.
+Everything older than that is assumed to be available. Paths are in Kubernetes syntax,
+and "[]" stands for every item of a list.
+
+*/
+
+// apiVersionsIntroduced maps "apiVersion kind" to the release that started serving it.
+var apiVersionsIntroduced = map[string]string{
+	"admissionregistration.k8s.io/v1beta1 MutatingWebhookConfiguration":   "1.9",
+	"admissionregistration.k8s.io/v1beta1 ValidatingWebhookConfiguration": "1.9",
+	"apps/v1 ControllerRevision":                                          "1.9",
+	"apps/v1 DaemonSet":                                                   "1.9",
+	"apps/v1 Deployment":                                                  "1.9",
+	"apps/v1 ReplicaSet":                                                  "1.9",
+	"apps/v1 StatefulSet":                                                 "1.9",
+	"apps/v1beta2 ControllerRevision":                                     "1.8",
+	"apps/v1beta2 DaemonSet":                                              "1.8",
+	"apps/v1beta2 Deployment":                                             "1.8",
+	"apps/v1beta2 ReplicaSet":                                             "1.8",
+	"apps/v1beta2 StatefulSet":                                            "1.8",
+	"autoscaling/v2beta1 HorizontalPodAutoscaler":                         "1.8",
+	"batch/v1beta1 CronJob":                                               "1.8",
+	"policy/v1beta1 PodSecurityPolicy":                                    "1.10",
+	"rbac.authorization.k8s.io/v1 ClusterRole":                            "1.8",
+	"rbac.authorization.k8s.io/v1 ClusterRoleBinding":                     "1.8",
+	"rbac.authorization.k8s.io/v1 Role":                                   "1.8",
+	"rbac.authorization.k8s.io/v1 RoleBinding":                            "1.8",
+	"scheduling.k8s.io/v1alpha1 PriorityClass":                            "1.8",
+}
+
+// podSpecFieldsIntroduced maps pod spec fields to the release that added them.
+var podSpecFieldsIntroduced = map[string]string{
+	"containers[].volumeDevices":                   "1.9",
+	"containers[].volumeMounts[].mountPropagation": "1.8",
+	"dnsConfig":                      "1.9",
+	"initContainers[].volumeDevices": "1.9",
+	"priority":                       "1.8",
+	"priorityClassName":              "1.8",
+	"shareProcessNamespace":          "1.10",
+}
+
+// fieldsIntroduced maps other fields, by kind, to the release that added them.
+var fieldsIntroduced = map[string]map[string]string{
+	"CustomResourceDefinition": {
+		"spec.subresources": "1.10",
+		"spec.validation":   "1.8",
+	},
+	"PersistentVolume": {
+		"spec.volumeMode": "1.9",
+	},
+	"PersistentVolumeClaim": {
+		"spec.volumeMode": "1.9",
+	},
+	"Service": {
+		"spec.publishNotReadyAddresses": "1.9",
+	},
+	"StorageClass": {
+		"allowVolumeExpansion": "1.8",
+		"mountOptions":         "1.8",
+		"volumeBindingMode":    "1.9",
+	},
+}
+
+// podSpecPaths are the locations of the pod spec in each kind that has one.
+var podSpecPaths = map[string]string{
+	"CronJob":               "spec.jobTemplate.spec.template.spec",
+	"DaemonSet":             "spec.template.spec",
+	"Deployment":            "spec.template.spec",
+	"Job":                   "spec.template.spec",
+	"Pod":                   "spec",
+	"PodTemplate":           "template.spec",
+	"ReplicaSet":            "spec.template.spec",
+	"ReplicationController": "spec.template.spec",
+	"StatefulSet":           "spec.template.spec",
+}
+
+// PodSpecPath is the location of the pod spec in objects of the kind, e.g. "spec.template.spec".
+func PodSpecPath(kind string) (string, bool) {
+	path, ok := podSpecPaths[kind]
+	return path, ok
+}
+
+// Check lists what a Kubernetes object uses that the release (e.g. "1.9") doesn't have yet.
+func Check(obj map[string]interface{}, release string) ([]string, error) {
+	minor, err := minorVersion(release)
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+
+	problems := []string{}
+	if introduced, ok := apiVersionsIntroduced[apiVersion+" "+kind]; ok && after(introduced, minor) {
+		problems = append(problems, fmt.Sprintf("%s %s was introduced in %s", apiVersion, kind, introduced))
+	}
+
+	fields := map[string]string{}
+	for path, introduced := range fieldsIntroduced[kind] {
+		fields[path] = introduced
+	}
+	if podSpecPath, ok := podSpecPaths[kind]; ok {
+		for path, introduced := range podSpecFieldsIntroduced {
+			fields[podSpecPath+"."+path] = introduced
+		}
+	}
+
+	paths := []string{}
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		introduced := fields[path]
+		if after(introduced, minor) && isSet(obj, strings.Split(path, ".")) {
+			problems = append(problems, fmt.Sprintf("%s was introduced in %s", path, introduced))
+		}
+	}
+
+	return problems, nil
+}
+
+func minorVersion(release string) (int, error) {
+	segments := strings.Split(strings.TrimPrefix(release, "v"), ".")
+	if len(segments) < 2 || segments[0] != "1" {
+		return 0, serrors.InvalidValueErrorf(release, "expected a Kubernetes release like 1.9")
+	}
+
+	minor, err := strconv.Atoi(segments[1])
+	if err != nil {
+		return 0, serrors.InvalidValueContextErrorf(err, release, "expected a Kubernetes release like 1.9")
+	}
+
+	return minor, nil
+}
+
+// after is true if the introduced release is newer than the given minor version.
+func after(introduced string, minor int) bool {
+	introducedMinor, err := minorVersion(introduced)
+	if err != nil {
+		panic(err)
+	}
+
+	return introducedMinor > minor
+}
+
+// isSet is true if the field at the path has a value in the object.
+func isSet(obj interface{}, segments []string) bool {
+	if len(segments) == 0 {
+		return obj != nil
+	}
+
+	segment := segments[0]
+	key := strings.TrimSuffix(segment, "[]")
+	objMap, ok := obj.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	val, ok := objMap[key]
+	if !ok {
+		return false
+	}
+
+	if key == segment {
+		return isSet(val, segments[1:])
+	}
+
+	list, _ := val.([]interface{})
+	for _, item := range list {
+		if isSet(item, segments[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
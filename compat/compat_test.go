@@ -0,0 +1,62 @@
+package compat
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var deployment0 = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      priorityClassName: high
+      shareProcessNamespace: true
+      containers:
+      - name: web
+        image: nginx
+        volumeMounts:
+        - name: data
+          mountPath: /data
+          mountPropagation: HostToContainer
+`
+
+func TestCheck(t *testing.T) {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(deployment0), &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := Check(obj, "1.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"apps/v1 Deployment was introduced in 1.9",
+		"spec.template.spec.containers[].volumeMounts[].mountPropagation was introduced in 1.8",
+		"spec.template.spec.priorityClassName was introduced in 1.8",
+		"spec.template.spec.shareProcessNamespace was introduced in 1.10",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Error(pretty.Diff(problems, expected))
+	}
+
+	problems, err = Check(obj, "v1.10.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("expected no problems for 1.10, got %v", problems)
+	}
+
+	if _, err := Check(obj, "latest"); err == nil {
+		t.Error("expected an error for a malformed release")
+	}
+}
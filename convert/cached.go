@@ -0,0 +1,77 @@
+package convert
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/converter"
+	"github.com/koki/short/parser"
+)
+
+// CachedConverter is ConvertKubeToShort for programs, e.g. controllers, that see the same objects over and over.
+// An object is only converted again when its UID or resourceVersion changes, and objects without both are always
+// converted. Results are shared between callers and must not be modified. It's safe for concurrent use.
+type CachedConverter struct {
+	cache *converter.CachedConverter
+}
+
+// Cached returns a converter that remembers up to size conversions, dropping the least recently used.
+func Cached(size int) *CachedConverter {
+	return &CachedConverter{cache: converter.Cached(size)}
+}
+
+// ConvertKubeToShort is a memoized ConvertKubeToShort.
+func (c *CachedConverter) ConvertKubeToShort(obj runtime.Object) (interface{}, error) {
+	obj, err := withGroupVersionKind(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.cache.ConvertFromKubeObj(obj)
+}
+
+// ConvertKubeToShortWithWarnings is a memoized ConvertKubeToShortWithWarnings.
+func (c *CachedConverter) ConvertKubeToShortWithWarnings(obj runtime.Object) (interface{}, []Warning, error) {
+	obj, err := withGroupVersionKind(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shortObj, converterWarnings, err := c.cache.ConvertFromKubeObjWithWarnings(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings, err := client.ConverterWarnings(obj, converterWarnings)
+	if err != nil {
+		return nil, nil, err
+	}
+	checked, err := client.KubeWarnings(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shortObj, fromClientWarnings(append(warnings, checked...)), nil
+}
+
+// Len is the number of cached conversions.
+func (c *CachedConverter) Len() int {
+	return c.cache.Len()
+}
+
+// withGroupVersionKind returns obj if its apiVersion and kind are set, and otherwise a copy with them set.
+// Unlike ConvertKubeToShort, it doesn't copy objects that already have them, since that would cost as much
+// as a cache hit saves.
+func withGroupVersionKind(obj runtime.Object) (runtime.Object, error) {
+	if !obj.GetObjectKind().GroupVersionKind().Empty() {
+		return obj, nil
+	}
+
+	obj = obj.DeepCopyObject()
+	err := parser.SetGroupVersionKind(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
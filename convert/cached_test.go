@@ -0,0 +1,49 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCached(t *testing.T) {
+	c := Cached(1)
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", GenerateName: "settings-", UID: "1234", ResourceVersion: "1"},
+	}
+
+	first, warnings, err := c.ConvertKubeToShortWithWarnings(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("unexpected warnings %#v", warnings)
+	}
+	if !configMap.GetObjectKind().GroupVersionKind().Empty() {
+		t.Error("the object was modified")
+	}
+
+	second, cachedWarnings, err := c.ConvertKubeToShortWithWarnings(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cachedWarnings, warnings) {
+		t.Errorf("unexpected cached warnings %#v", cachedWarnings)
+	}
+	if first != second {
+		t.Error("expected the cached result")
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected 1 cached conversion, got %d", c.Len())
+	}
+
+	uncached, err := Cached(1).ConvertKubeToShort(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(uncached, first) {
+		t.Errorf("expected the same conversion, got %#v", uncached)
+	}
+}
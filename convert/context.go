@@ -0,0 +1,154 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/client"
+)
+
+/*
+
+The Context variants stop converting when their context is done, so a program can cancel the
+conversion of a huge cluster dump, or give up on it after a deadline. They return the context's
+error, e.g. context.Canceled.
+
+Documents are converted one at a time, and a document that has started converting can't be
+interrupted. With WithDocumentTimeout, the conversion waits for each document at most the
+timeout, and returns a *DocumentTimeoutError if it takes longer. This protects servers that
+convert untrusted input from documents that take pathologically long, though the abandoned
+conversion keeps running in the background until it's done.
+
+*/
+
+type documentTimeoutKey struct{}
+
+// WithDocumentTimeout returns a context under which the Context variants give each document at most timeout to convert.
+func WithDocumentTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, documentTimeoutKey{}, timeout)
+}
+
+func documentTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(documentTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// DocumentTimeoutError is returned when a document takes longer to convert than the timeout of WithDocumentTimeout.
+type DocumentTimeoutError struct {
+	// Index is the document's index in its stream, from 0.
+	Index   int
+	Timeout time.Duration
+}
+
+func (e *DocumentTimeoutError) Error() string {
+	return fmt.Sprintf("document %d took longer than %s to convert", e.Index, e.Timeout)
+}
+
+// convertDocument runs convert for the i'th document, unless ctx is done first.
+func convertDocument(ctx context.Context, i int, convert func() (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	timeout := documentTimeout(ctx)
+	if timeout <= 0 && ctx.Done() == nil {
+		return convert()
+	}
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	type result struct {
+		obj      interface{}
+		err      error
+		panicked interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		// A panic is passed on to the caller, so it can recover from it like from a conversion on its own goroutine.
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{panicked: r}
+			}
+		}()
+		obj, err := convert()
+		done <- result{obj: obj, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.panicked != nil {
+			panic(r.panicked)
+		}
+		return r.obj, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer:
+		return nil, &DocumentTimeoutError{Index: i, Timeout: timeout}
+	}
+}
+
+// ConvertKubeToShortContext is ConvertKubeToShort, stopping when ctx is done.
+func ConvertKubeToShortContext(ctx context.Context, obj runtime.Object) (interface{}, error) {
+	return convertDocument(ctx, 0, func() (interface{}, error) {
+		return ConvertKubeToShort(obj)
+	})
+}
+
+// ConvertShortToKubeContext is ConvertShortToKube, stopping when ctx is done.
+func ConvertShortToKubeContext(ctx context.Context, data []byte) ([]runtime.Object, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]runtime.Object, len(objs))
+	for i, obj := range objs {
+		kubeObj, err := convertDocument(ctx, i, func() (interface{}, error) {
+			kubeObjs, err := client.ConvertKokiMaps([]map[string]interface{}{obj})
+			if err != nil {
+				return nil, err
+			}
+			return kubeObjs[0], nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		results[i], err = toRuntimeObject(kubeObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// ConvertKubeBytesToShortContext is ConvertKubeBytesToShort, stopping when ctx is done.
+func ConvertKubeBytesToShortContext(ctx context.Context, data []byte) ([]interface{}, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		results[i], err = convertDocument(ctx, i, func() (interface{}, error) {
+			shortObjs, err := client.ConvertKubeMaps([]map[string]interface{}{obj})
+			if err != nil {
+				return nil, err
+			}
+			return shortObjs[0], nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,87 @@
+package convert
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+)
+
+func TestConvertContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = WithDocumentTimeout(ctx, time.Minute)
+
+	kubeObjs, err := ConvertShortToKubeContext(ctx, []byte("config_map:\n  name: settings\n---\nconfig_map:\n  name: other\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeObjs) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(kubeObjs))
+	}
+	if _, ok := kubeObjs[1].(*v1.ConfigMap); !ok {
+		t.Errorf("expected a ConfigMap, got %T", kubeObjs[1])
+	}
+
+	decoder := NewDecoder(strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"))
+	if _, err := decoder.DecodeKubeToShortContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvertContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ConvertKubeBytesToShortContext(ctx, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"))
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	converted := 0
+	_, err = ParallelContext(ctx, 10, 2, func(i int) (interface{}, error) {
+		converted++
+		return i, nil
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if converted > 0 {
+		t.Errorf("expected no conversions after cancelling, got %d", converted)
+	}
+}
+
+func TestDocumentTimeout(t *testing.T) {
+	ctx := WithDocumentTimeout(context.Background(), 10*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := ParallelContext(ctx, 3, 3, func(i int) (interface{}, error) {
+		if i == 1 {
+			<-release
+		}
+		return i, nil
+	})
+	timeoutErr, ok := err.(*DocumentTimeoutError)
+	if !ok {
+		t.Fatalf("expected a document timeout, got %v", err)
+	}
+	if timeoutErr.Index != 1 {
+		t.Errorf("expected document 1 to time out, got %d", timeoutErr.Index)
+	}
+}
+
+func TestConvertContextPanics(t *testing.T) {
+	ctx := WithDocumentTimeout(context.Background(), time.Minute)
+	defer func() {
+		if r := recover(); r != "pathological" {
+			t.Errorf("expected the conversion's panic, got %v", r)
+		}
+	}()
+
+	convertDocument(ctx, 0, func() (interface{}, error) {
+		panic("pathological")
+	})
+}
@@ -0,0 +1,94 @@
+package convert
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/converter"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A stable API for programs that embed short, independent of the command-line tool.
+
+Kubernetes objects are the typed objects from k8s.io/api, e.g. *appsv1.Deployment.
+Short objects are the wrapper types from github.com/koki/short/types, e.g. *types.DeploymentWrapper.
+Serialized manifests are YAML or JSON, with multiple documents separated by "---".
+Decoder and Encoder process serialized manifests one document at a time.
+
+*/
+
+// ConvertKubeToShort converts a typed Kubernetes object to short syntax. The object isn't modified.
+// If its apiVersion and kind aren't set (as with objects returned by client-go), they're inferred from its type.
+func ConvertKubeToShort(obj runtime.Object) (interface{}, error) {
+	obj = obj.DeepCopyObject()
+	err := parser.SetGroupVersionKind(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.DetectAndConvertFromKubeObj(obj)
+}
+
+// ConvertShortToKube converts serialized short manifests to typed Kubernetes objects.
+func ConvertShortToKube(data []byte) ([]runtime.Object, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeObjs, err := client.ConvertKokiMaps(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]runtime.Object, len(kubeObjs))
+	for i, kubeObj := range kubeObjs {
+		results[i], err = toRuntimeObject(kubeObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func toRuntimeObject(kubeObj interface{}) (runtime.Object, error) {
+	result, ok := kubeObj.(runtime.Object)
+	if !ok {
+		return nil, serrors.TypeErrorf(kubeObj, "converted to an unexpected type")
+	}
+
+	return result, nil
+}
+
+// ConvertKubeBytesToShort converts serialized Kubernetes manifests to short syntax.
+func ConvertKubeBytesToShort(data []byte) ([]interface{}, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ConvertKubeMaps(objs)
+}
+
+// Marshal serializes objects in either syntax as a YAML stream.
+func Marshal(objs []interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := client.WriteObjsToYamlStream(objs, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func parse(data []byte) ([]map[string]interface{}, error) {
+	return parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(bytes.NewReader(data))})
+}
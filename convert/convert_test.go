@@ -0,0 +1,89 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koki/short/types"
+)
+
+func TestConvertKubeToShort(t *testing.T) {
+	// As returned by client-go, without apiVersion and kind.
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "web", Image: "nginx"}},
+				},
+			},
+		},
+	}
+
+	shortObj, err := ConvertKubeToShort(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapper, ok := shortObj.(*types.DeploymentWrapper)
+	if !ok {
+		t.Fatalf("expected a short Deployment, got %T", shortObj)
+	}
+	if wrapper.Deployment.Version != "apps/v1" || wrapper.Deployment.Name != "web" {
+		t.Errorf("unexpected short Deployment %#v", wrapper.Deployment)
+	}
+	if len(deployment.APIVersion) > 0 {
+		t.Error("expected the input object to be left unmodified")
+	}
+
+	b, err := Marshal([]interface{}{shortObj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "deployment:") {
+		t.Errorf("unexpected short syntax:\n%s", b)
+	}
+}
+
+func TestConvertShortToKube(t *testing.T) {
+	manifests := `
+config_map:
+  name: settings
+  data:
+    a: b
+---
+service:
+  name: web
+  port: 80
+  selector:
+    app: web
+`
+
+	kubeObjs, err := ConvertShortToKube([]byte(manifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeObjs) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(kubeObjs))
+	}
+	if _, ok := kubeObjs[0].(*v1.ConfigMap); !ok {
+		t.Errorf("expected a ConfigMap, got %T", kubeObjs[0])
+	}
+	if _, ok := kubeObjs[1].(*v1.Service); !ok {
+		t.Errorf("expected a Service, got %T", kubeObjs[1])
+	}
+
+	shortObjs, err := ConvertKubeBytesToShort([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := shortObjs[0].(*types.ConfigMapWrapper); !ok {
+		t.Errorf("expected a short ConfigMap, got %T", shortObjs[0])
+	}
+}
@@ -0,0 +1,85 @@
+package convert
+
+import (
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// FuncMap returns template functions that convert manifests inline, e.g. for generating documentation
+// that shows both syntaxes side by side:
+//
+//	toShort: Kubernetes manifests (a string, []byte or typed Kubernetes object) to a short YAML stream.
+//	toKube: short manifests (a string or []byte) to a Kubernetes YAML stream.
+//
+// A failed conversion stops the template's execution with its error. The map can be converted to an
+// html/template.FuncMap.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"toShort": toShort,
+		"toKube":  toKube,
+	}
+}
+
+func toShort(input interface{}) (string, error) {
+	var objs []interface{}
+	switch input := input.(type) {
+	case runtime.Object:
+		obj, err := ConvertKubeToShort(input)
+		if err != nil {
+			return "", err
+		}
+		objs = []interface{}{obj}
+	default:
+		data, err := templateInput(input)
+		if err != nil {
+			return "", err
+		}
+		objs, err = ConvertKubeBytesToShort(data)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return marshalString(objs)
+}
+
+func toKube(input interface{}) (string, error) {
+	data, err := templateInput(input)
+	if err != nil {
+		return "", err
+	}
+	kubeObjs, err := ConvertShortToKube(data)
+	if err != nil {
+		return "", err
+	}
+
+	objs := make([]interface{}, len(kubeObjs))
+	for i, kubeObj := range kubeObjs {
+		objs[i] = kubeObj
+	}
+
+	return marshalString(objs)
+}
+
+func templateInput(input interface{}) ([]byte, error) {
+	switch input := input.(type) {
+	case string:
+		return []byte(input), nil
+	case []byte:
+		return input, nil
+	}
+
+	return nil, serrors.TypeErrorf(input, "expected serialized manifests")
+}
+
+func marshalString(objs []interface{}) (string, error) {
+	b, err := Marshal(objs)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
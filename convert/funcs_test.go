@@ -0,0 +1,76 @@
+package convert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func executeTemplate(text string, data interface{}) (string, error) {
+	tmpl, err := template.New("doc").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+
+	return buf.String(), err
+}
+
+func TestFuncMap(t *testing.T) {
+	short := `
+config_map:
+  name: settings
+  version: v1
+  data:
+    a: b
+`
+	out, err := executeTemplate("{{ toKube . }}", short)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "kind: ConfigMap") || !strings.Contains(out, "name: settings") {
+		t.Errorf("unexpected Kubernetes syntax:\n%s", out)
+	}
+
+	// Round trip, as a documentation generator would show both forms.
+	out, err = executeTemplate("{{ toKube . | toShort }}", short)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "config_map:") || !strings.Contains(out, "name: settings") {
+		t.Errorf("unexpected short syntax:\n%s", out)
+	}
+
+	// Typed objects, as returned by client-go.
+	configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings"}}
+	out, err = executeTemplate("{{ toShort . }}", configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "config_map:") {
+		t.Errorf("unexpected short syntax:\n%s", out)
+	}
+}
+
+func TestFuncMapErrors(t *testing.T) {
+	for text, data := range map[string]interface{}{
+		"{{ toKube . }}":  "config_map:\n  name: settings\n  typo: x\n",
+		"{{ toShort . }}": "apiVersion: v1\nkind: NotAKind\n",
+		"{{ toKube 1 }}":  nil,
+	} {
+		out, err := executeTemplate(text, data)
+		if err == nil {
+			t.Errorf("%s: expected an error, got\n%s", text, out)
+		}
+	}
+
+	_, err := executeTemplate("{{ toKube . }}", "config_map:\n  name: settings\n  typo: x\n")
+	if err == nil || !strings.Contains(err.Error(), "toKube") || !strings.Contains(err.Error(), "typo") {
+		t.Errorf("expected the conversion error, got %v", err)
+	}
+}
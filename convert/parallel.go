@@ -0,0 +1,65 @@
+package convert
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Parallel calls convert for each index from 0 to n-1 on up to parallelism goroutines,
+// and returns the results in order. A parallelism less than 1 means one goroutine per CPU.
+// If any conversions fail, it returns the error for the lowest index.
+func Parallel(n, parallelism int, convert func(i int) (interface{}, error)) ([]interface{}, error) {
+	return ParallelContext(context.Background(), n, parallelism, convert)
+}
+
+// ParallelContext is Parallel, stopping when ctx is done. Each index is given the timeout of
+// WithDocumentTimeout, if ctx has one.
+func ParallelContext(ctx context.Context, n, parallelism int, convert func(i int) (interface{}, error)) ([]interface{}, error) {
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+	if parallelism > n {
+		parallelism = n
+	}
+
+	results := make([]interface{}, n)
+	errs := make([]error, n)
+	indices := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				i := i
+				results[i], errs[i] = convertDocument(ctx, i, func() (interface{}, error) {
+					return convert(i)
+				})
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Indices that weren't started have no error of their own.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	running, maxRunning := int32(0), int32(0)
+	results, err := Parallel(20, 4, func(i int) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		// Finish out of order.
+		time.Sleep(time.Duration(20-i) * time.Millisecond)
+		return i * i, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, result := range results {
+		if result != i*i {
+			t.Fatalf("result %d out of order: %v", i, results)
+		}
+	}
+	if maxRunning > 4 {
+		t.Errorf("expected at most 4 concurrent conversions, got %d", maxRunning)
+	}
+}
+
+func TestParallelError(t *testing.T) {
+	for _, parallelism := range []int{0, 1, 8} {
+		_, err := Parallel(10, parallelism, func(i int) (interface{}, error) {
+			if i%3 == 2 {
+				return nil, fmt.Errorf("failed %d", i)
+			}
+			return i, nil
+		})
+		if err == nil || err.Error() != "failed 2" {
+			t.Errorf("parallelism %d: expected the error for the lowest index, got %v", parallelism, err)
+		}
+	}
+
+	results, err := Parallel(0, 4, nil)
+	if err != nil || !reflect.DeepEqual(results, []interface{}{}) {
+		t.Errorf("expected no results, got %v, %v", results, err)
+	}
+}
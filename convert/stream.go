@@ -0,0 +1,135 @@
+package convert
+
+import (
+	"context"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/koki/short/client"
+)
+
+// Decoder reads serialized manifests one document at a time, so large streams
+// (e.g. operator bundles or cluster dumps) don't have to fit in memory.
+type Decoder struct {
+	decoder *yaml.YAMLOrJSONDecoder
+	// count is the number of documents read.
+	count int
+}
+
+// NewDecoder reads documents from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{decoder: yaml.NewYAMLOrJSONDecoder(r, 1024)}
+}
+
+// Decode reads the next document as a dictionary. It returns io.EOF at the end of the stream.
+func (d *Decoder) Decode() (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	err := d.decoder.Decode(&obj)
+	if err != nil {
+		return nil, err
+	}
+
+	d.count++
+	return obj, nil
+}
+
+// DecodeShortToKube reads the next short manifest and converts it to a typed Kubernetes object.
+// It returns io.EOF at the end of the stream.
+func (d *Decoder) DecodeShortToKube() (runtime.Object, error) {
+	return d.DecodeShortToKubeContext(context.Background())
+}
+
+// DecodeShortToKubeContext is DecodeShortToKube, stopping when ctx is done.
+func (d *Decoder) DecodeShortToKubeContext(ctx context.Context) (runtime.Object, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	obj, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	kubeObj, err := convertDocument(ctx, d.count-1, func() (interface{}, error) {
+		kubeObjs, err := client.ConvertKokiMaps([]map[string]interface{}{obj})
+		if err != nil {
+			return nil, err
+		}
+		return kubeObjs[0], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toRuntimeObject(kubeObj)
+}
+
+// DecodeKubeToShort reads the next Kubernetes manifest and converts it to short syntax.
+// It returns io.EOF at the end of the stream.
+func (d *Decoder) DecodeKubeToShort() (interface{}, error) {
+	return d.DecodeKubeToShortContext(context.Background())
+}
+
+// DecodeKubeToShortContext is DecodeKubeToShort, stopping when ctx is done.
+func (d *Decoder) DecodeKubeToShortContext(ctx context.Context) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	obj, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDocument(ctx, d.count-1, func() (interface{}, error) {
+		shortObjs, err := client.ConvertKubeMaps([]map[string]interface{}{obj})
+		if err != nil {
+			return nil, err
+		}
+		return shortObjs[0], nil
+	})
+}
+
+// Encoder writes objects in either syntax one at a time, in the same format as Marshal.
+type Encoder struct {
+	w     io.Writer
+	json  bool
+	count int
+}
+
+// NewEncoder writes a YAML stream to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// NewJSONEncoder writes a stream of JSON documents to w.
+func NewJSONEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, json: true}
+}
+
+// Encode writes the next object.
+func (e *Encoder) Encode(obj interface{}) error {
+	objs := []interface{}{obj}
+	if e.json {
+		if e.count > 0 {
+			if _, err := e.w.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
+		if err := client.WriteObjsToJSONStream(objs, e.w); err != nil {
+			return err
+		}
+	} else {
+		if e.count > 0 {
+			if _, err := e.w.Write([]byte("---\n")); err != nil {
+				return err
+			}
+		}
+		if err := client.WriteObjsToYamlStream(objs, e.w); err != nil {
+			return err
+		}
+	}
+
+	e.count++
+	return nil
+}
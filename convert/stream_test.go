@@ -0,0 +1,195 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+const kubeDoc = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings-%d
+data:
+  a: b
+`
+
+const kubePersistentVolumeDoc = `apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv-%d
+  labels:
+    app: db
+spec:
+  capacity:
+    storage: 10Gi
+  accessModes:
+  - ReadWriteOnce
+  persistentVolumeReclaimPolicy: Retain
+  storageClassName: ssd
+  rbd:
+    monitors:
+    - 10.0.0.1:6789
+    image: db
+    pool: kube
+    fsType: ext4
+    readOnly: true
+`
+
+// manifestReader generates a stream of n Kubernetes manifests without holding them in memory.
+// Each manifest is doc (kubeDoc if it's empty), formatted with its index.
+type manifestReader struct {
+	n, i int
+	doc  string
+	buf  bytes.Buffer
+}
+
+func (r *manifestReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.i == r.n {
+			return 0, io.EOF
+		}
+		if r.i > 0 {
+			r.buf.WriteString("---\n")
+		}
+		doc := r.doc
+		if len(doc) == 0 {
+			doc = kubeDoc
+		}
+		fmt.Fprintf(&r.buf, doc, r.i)
+		r.i++
+	}
+
+	return r.buf.Read(p)
+}
+
+func TestStream(t *testing.T) {
+	input, err := ioutil.ReadAll(&manifestReader{n: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shortObjs, err := ConvertKubeBytesToShort(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := Marshal(shortObjs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := NewDecoder(&manifestReader{n: 3})
+	out := &bytes.Buffer{}
+	encoder := NewEncoder(out)
+	for {
+		shortObj, err := decoder.DecodeKubeToShort()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := encoder.Encode(shortObj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out.String() != string(expected) {
+		t.Fatalf("streamed output differs from Marshal:\n%s\n---\n%s", out.String(), expected)
+	}
+
+	decoder = NewDecoder(bytes.NewReader(expected))
+	for i := 0; i < 3; i++ {
+		kubeObj, err := decoder.DecodeShortToKube()
+		if err != nil {
+			t.Fatal(err)
+		}
+		configMap, ok := kubeObj.(*v1.ConfigMap)
+		if !ok || configMap.Name != fmt.Sprintf("settings-%d", i) {
+			t.Fatalf("unexpected object %#v", kubeObj)
+		}
+	}
+	if _, err := decoder.DecodeShortToKube(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	out := &bytes.Buffer{}
+	encoder := NewJSONEncoder(out)
+	for i := 0; i < 2; i++ {
+		if err := encoder.Encode(map[string]interface{}{"a": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decoder := NewDecoder(strings.NewReader(out.String()))
+	for i := 0; i < 2; i++ {
+		obj, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(obj["a"]) != fmt.Sprint(i) {
+			t.Fatalf("unexpected document %#v", obj)
+		}
+	}
+}
+
+// BenchmarkStream converts streams of increasing size. The peak heap should stay
+// about the same for every size, since only one document is in memory at a time.
+func BenchmarkStream(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			peak := uint64(0)
+			stats := runtime.MemStats{}
+			for i := 0; i < b.N; i++ {
+				decoder := NewDecoder(&manifestReader{n: n})
+				encoder := NewEncoder(ioutil.Discard)
+				for j := 0; ; j++ {
+					shortObj, err := decoder.DecodeKubeToShort()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						b.Fatal(err)
+					}
+					if err := encoder.Encode(shortObj); err != nil {
+						b.Fatal(err)
+					}
+					if j%100 == 0 {
+						runtime.ReadMemStats(&stats)
+						if stats.HeapInuse > peak {
+							peak = stats.HeapInuse
+						}
+					}
+				}
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+		})
+	}
+}
+
+// BenchmarkPersistentVolumes converts a dump of 1000 persistent volumes to short syntax and back.
+func BenchmarkPersistentVolumes(b *testing.B) {
+	input, err := ioutil.ReadAll(&manifestReader{n: 1000, doc: kubePersistentVolumeDoc})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		shortObjs, err := ConvertKubeBytesToShort(input)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := Marshal(shortObjs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package convert
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/converter"
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/parser"
+)
+
+/*
+
+The WithWarnings variants also return the problems that didn't stop each conversion: what the
+converters couldn't express in the other syntax, e.g. a generateName next to a name, and what's
+wrong with the Kubernetes side, e.g. a deprecated apiVersion.
+
+*/
+
+// Warning is a problem with an object that didn't stop its conversion.
+type Warning struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Path is the field the warning is about, if there is one, e.g. "$.metadata.generateName".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return client.Warning{Kind: w.Kind, Name: w.Name, Path: w.Path, Message: w.Message}.String()
+}
+
+func fromClientWarnings(clientWarnings []client.Warning) []Warning {
+	warnings := make([]Warning, len(clientWarnings))
+	for i, warning := range clientWarnings {
+		warnings[i] = Warning{Kind: warning.Kind, Name: warning.Name, Path: warning.Path, Message: warning.Message}
+	}
+
+	return warnings
+}
+
+// ConvertKubeToShortWithWarnings is ConvertKubeToShort, and also returns the object's warnings.
+func ConvertKubeToShortWithWarnings(obj runtime.Object) (interface{}, []Warning, error) {
+	obj = obj.DeepCopyObject()
+	err := parser.SetGroupVersionKind(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converterWarnings := &converters.Warnings{}
+	shortObj, err := converter.DetectAndConvertFromKubeObjWithWarnings(obj, converterWarnings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings, err := client.ConverterWarnings(obj, converterWarnings.List())
+	if err != nil {
+		return nil, nil, err
+	}
+	checked, err := client.KubeWarnings(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shortObj, fromClientWarnings(append(warnings, checked...)), nil
+}
+
+// ConvertShortToKubeWithWarnings is ConvertShortToKube, and also returns the warnings of each object.
+func ConvertShortToKubeWithWarnings(data []byte) ([]runtime.Object, [][]Warning, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeObjs, clientWarnings, err := client.ConvertKokiMapsWithWarnings(objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]runtime.Object, len(kubeObjs))
+	warnings := make([][]Warning, len(kubeObjs))
+	for i, kubeObj := range kubeObjs {
+		results[i], err = toRuntimeObject(kubeObj)
+		if err != nil {
+			return nil, nil, err
+		}
+		warnings[i] = fromClientWarnings(clientWarnings[i])
+	}
+
+	return results, warnings, nil
+}
+
+// ConvertKubeBytesToShortWithWarnings is ConvertKubeBytesToShort, and also returns the warnings of each object.
+func ConvertKubeBytesToShortWithWarnings(data []byte) ([]interface{}, [][]Warning, error) {
+	objs, err := parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shortObjs, clientWarnings, err := client.ConvertKubeMapsWithWarnings(objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings := make([][]Warning, len(shortObjs))
+	for i := range shortObjs {
+		warnings[i] = fromClientWarnings(clientWarnings[i])
+	}
+
+	return shortObjs, warnings, nil
+}
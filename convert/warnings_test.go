@@ -0,0 +1,40 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertWithWarnings(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", GenerateName: "settings-"},
+	}
+	expected := []Warning{{Kind: "ConfigMap", Name: "settings", Path: "$.metadata.generateName", Message: "dropped, since the object has a name"}}
+
+	_, warnings, err := ConvertKubeToShortWithWarnings(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("unexpected warnings %#v", warnings)
+	}
+
+	_, streamWarnings, err := ConvertKubeBytesToShortWithWarnings([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  generateName: settings-\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(streamWarnings, [][]Warning{expected}) {
+		t.Errorf("unexpected warnings %#v", streamWarnings)
+	}
+
+	_, streamWarnings, err = ConvertShortToKubeWithWarnings([]byte("config_map:\n  name: settings\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streamWarnings) != 1 || len(streamWarnings[0]) != 0 {
+		t.Errorf("unexpected warnings %#v", streamWarnings)
+	}
+}
@@ -0,0 +1,142 @@
+package converter
+
+import (
+	"container/list"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/koki/short/converter/converters"
+)
+
+/*
+
+CachedConverter memoizes Kube-to-Koki conversions for programs (e.g. controllers)
+that see the same objects over and over.
+
+Results are keyed on the object's UID and resourceVersion, so an unchanged object
+is only converted once. Objects without both are always converted. The key also has the
+object's apiVersion and kind, since the same object read as another version converts
+differently, and fake clients or other clusters may reuse UIDs.
+
+The cached result is shared between callers and must not be modified. Its warnings are cached
+with it, so ConvertFromKubeObjWithWarnings returns them on every call.
+
+*/
+
+type CachedConverter struct {
+	size int
+
+	lock    sync.Mutex
+	entries map[cacheKey]*list.Element
+	// Most recently used entries are at the front.
+	order *list.List
+}
+
+type cacheKey struct {
+	gvk             schema.GroupVersionKind
+	uid             apitypes.UID
+	resourceVersion string
+}
+
+type cacheEntry struct {
+	key      cacheKey
+	kokiObj  interface{}
+	warnings []converters.Warning
+}
+
+// Cached returns a converter that remembers up to size conversions.
+func Cached(size int) *CachedConverter {
+	return &CachedConverter{
+		size:    size,
+		entries: map[cacheKey]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// ConvertFromKubeObj is a memoized DetectAndConvertFromKubeObj.
+func (c *CachedConverter) ConvertFromKubeObj(kubeObj runtime.Object) (interface{}, error) {
+	kokiObj, _, err := c.ConvertFromKubeObjWithWarnings(kubeObj)
+	return kokiObj, err
+}
+
+// ConvertFromKubeObjWithWarnings is a memoized DetectAndConvertFromKubeObjWithWarnings, returning the warnings.
+func (c *CachedConverter) ConvertFromKubeObjWithWarnings(kubeObj runtime.Object) (interface{}, []converters.Warning, error) {
+	key, ok := keyForObj(kubeObj)
+	if !ok || c.size <= 0 {
+		warnings := &converters.Warnings{}
+		kokiObj, err := DetectAndConvertFromKubeObjWithWarnings(kubeObj, warnings)
+		return kokiObj, warnings.List(), err
+	}
+
+	if entry, ok := c.get(key); ok {
+		return entry.kokiObj, entry.warnings, nil
+	}
+
+	warnings := &converters.Warnings{}
+	kokiObj, err := DetectAndConvertFromKubeObjWithWarnings(kubeObj, warnings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.put(&cacheEntry{key: key, kokiObj: kokiObj, warnings: warnings.List()})
+	return kokiObj, warnings.List(), nil
+}
+
+// Len is the number of cached conversions.
+func (c *CachedConverter) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+func keyForObj(kubeObj runtime.Object) (cacheKey, bool) {
+	meta, ok := kubeObj.(metav1.Object)
+	if !ok {
+		return cacheKey{}, false
+	}
+
+	key := cacheKey{
+		gvk:             kubeObj.GetObjectKind().GroupVersionKind(),
+		uid:             meta.GetUID(),
+		resourceVersion: meta.GetResourceVersion(),
+	}
+	if len(key.uid) == 0 || len(key.resourceVersion) == 0 {
+		return cacheKey{}, false
+	}
+
+	return key, true
+}
+
+func (c *CachedConverter) get(key cacheKey) (*cacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*cacheEntry), true
+	}
+
+	return nil, false
+}
+
+func (c *CachedConverter) put(entry *cacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
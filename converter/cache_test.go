@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	exts "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/koki/short/types"
+)
+
+func configMap(uid, resourceVersion string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            uid,
+			UID:             apitypes.UID(uid),
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+func TestCachedConverter(t *testing.T) {
+	c := Cached(2)
+
+	a1, err := c.ConvertFromKubeObj(configMap("a", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a1Again, err := c.ConvertFromKubeObj(configMap("a", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1 != a1Again {
+		t.Errorf("expected cached result for unchanged object")
+	}
+
+	a2, err := c.ConvertFromKubeObj(configMap("a", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1 == a2 {
+		t.Errorf("expected new result for new resourceVersion")
+	}
+
+	_, err = c.ConvertFromKubeObj(configMap("b", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 cached conversions, got %d", c.Len())
+	}
+
+	a1Evicted, err := c.ConvertFromKubeObj(configMap("a", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1 == a1Evicted {
+		t.Errorf("expected least recently used entry to be evicted")
+	}
+
+	_, err = c.ConvertFromKubeObj(configMap("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("objects without UID and resourceVersion shouldn't be cached")
+	}
+}
+
+func TestCachedConverterKeysOnKind(t *testing.T) {
+	c := Cached(2)
+
+	deployment := func(apiVersion string) *exts.Deployment {
+		return &exts.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "a", ResourceVersion: "1"},
+		}
+	}
+	asConfigMap, err := c.ConvertFromKubeObj(configMap("a", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	asDeployment, err := c.ConvertFromKubeObj(deployment("extensions/v1beta1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asConfigMap == asDeployment {
+		t.Errorf("expected a new result for another kind with the same UID and resourceVersion")
+	}
+	if _, ok := asDeployment.(*types.DeploymentWrapper); !ok {
+		t.Errorf("expected a deployment, got %#v", asDeployment)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 cached conversions, got %d", c.Len())
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"k8s.io/api/core/v1"
 
-	"github.com/golang/glog"
 	"github.com/koki/short/parser/expressions"
 	"github.com/koki/short/types"
 	serrors "github.com/koki/structurederrors"
@@ -256,50 +255,5 @@ func splitAndRevertNodeAffinity(affinities []string) (hard []v1.NodeSelectorTerm
 }
 
 func parseNodeExprs(s string) (*v1.NodeSelectorTerm, error) {
-	reqs := []v1.NodeSelectorRequirement{}
-	segs := strings.Split(s, "&")
-	for _, seg := range segs {
-		expr, err := expressions.ParseExpr(seg, []string{"!=", "=", ">", "<"})
-		if err != nil {
-			err = serrors.InvalidValueForTypeContextError(err, s, v1.NodeSelectorTerm{})
-			return nil, err
-		}
-
-		if expr == nil {
-			if seg[0] == '!' {
-				reqs = append(reqs, v1.NodeSelectorRequirement{
-					Key:      seg[1:],
-					Operator: v1.NodeSelectorOpDoesNotExist,
-				})
-			} else {
-				reqs = append(reqs, v1.NodeSelectorRequirement{
-					Key:      seg,
-					Operator: v1.NodeSelectorOpExists,
-				})
-			}
-
-			continue
-		}
-
-		var op v1.NodeSelectorOperator
-		switch expr.Op {
-		case "=":
-			op = v1.NodeSelectorOpIn
-		case "!=":
-			op = v1.NodeSelectorOpNotIn
-		case ">":
-			op = v1.NodeSelectorOpGt
-		case "<":
-			op = v1.NodeSelectorOpLt
-		default:
-			glog.Fatal("unreachable")
-		}
-		reqs = append(reqs, v1.NodeSelectorRequirement{
-			Key:      expr.Key,
-			Operator: op,
-			Values:   expr.Values,
-		})
-	}
-
-	return &v1.NodeSelectorTerm{reqs, nil}, nil
+	return expressions.ParseNodeSelectorTerm(s)
 }
@@ -30,6 +30,10 @@ var nodeAffinity5 = types.Affinity{
 	NodeAffinity: "value6weighted:soft:100",
 }
 
+var nodeAffinity6 = types.Affinity{
+	NodeAffinity: "zone=a&@metadata.name=node-1:soft:20",
+}
+
 var podAffinity0 = types.Affinity{
 	PodAffinity: "existentKeyPa",
 }
@@ -68,7 +72,7 @@ var podAntiAffinity2 = types.Affinity{
 func TestRevert(t *testing.T) {
 	testRevertAffinities(t)
 	testRevertAffinities(t, nodeAffinity0)
-	testRevertAffinities(t, nodeAffinity0, nodeAffinity1, nodeAffinity2, nodeAffinity3, nodeAffinity4, nodeAffinity5)
+	testRevertAffinities(t, nodeAffinity0, nodeAffinity1, nodeAffinity2, nodeAffinity3, nodeAffinity4, nodeAffinity5, nodeAffinity6)
 	testRevertAffinities(t, podAffinity0)
 	testRevertAffinities(t, podAffinity0, podAffinity1, podAffinity2, podAffinity3, podAffinity4)
 	testRevertAffinities(t, podAntiAffinity0)
@@ -0,0 +1,136 @@
+package converters
+
+import (
+	"sort"
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Several kinds are served under more than one API version, e.g. a Deployment can be
+extensions/v1beta1, apps/v1beta1, apps/v1beta2 or apps/v1.
+
+If a Short manifest sets "version", that's always the apiVersion of the Kubernetes manifest.
+Otherwise, the apiVersion is the one preferred by the target Kubernetes release (see SetKubeVersion).
+When no release is targeted, the converters keep their historical defaults.
+
+*/
+
+// preferredAPIVersions maps each Kubernetes release to the preferred apiVersion of its multi-version kinds.
+var preferredAPIVersions = map[string]map[string]string{
+	"1.7": {
+		"CronJob":     "batch/v2alpha1",
+		"DaemonSet":   "extensions/v1beta1",
+		"Deployment":  "extensions/v1beta1",
+		"ReplicaSet":  "extensions/v1beta1",
+		"StatefulSet": "apps/v1beta1",
+	},
+	"1.8": {
+		"CronJob":     "batch/v1beta1",
+		"DaemonSet":   "apps/v1beta2",
+		"Deployment":  "apps/v1beta2",
+		"ReplicaSet":  "apps/v1beta2",
+		"StatefulSet": "apps/v1beta2",
+	},
+	"1.9": {
+		"CronJob":     "batch/v1beta1",
+		"DaemonSet":   "apps/v1",
+		"Deployment":  "apps/v1",
+		"ReplicaSet":  "apps/v1",
+		"StatefulSet": "apps/v1",
+	},
+	"1.10": {
+		"CronJob":     "batch/v1beta1",
+		"DaemonSet":   "apps/v1",
+		"Deployment":  "apps/v1",
+		"ReplicaSet":  "apps/v1",
+		"StatefulSet": "apps/v1",
+	},
+}
+
+// kubeVersion is the targeted Kubernetes release, or "" for the historical defaults.
+var kubeVersion string
+
+// KubeVersions lists the Kubernetes releases that SetKubeVersion accepts.
+func KubeVersions() []string {
+	versions := []string{}
+	for version := range preferredAPIVersions {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return len(versions[i]) < len(versions[j]) || (len(versions[i]) == len(versions[j]) && versions[i] < versions[j])
+	})
+
+	return versions
+}
+
+// ParseKubeVersion normalizes a Kubernetes release like "1.9", "v1.9" or "1.9.3" to its minor version, e.g. "1.9".
+func ParseKubeVersion(release string) (string, error) {
+	version := strings.TrimPrefix(release, "v")
+	if segments := strings.Split(version, "."); len(segments) > 2 {
+		version = strings.Join(segments[:2], ".")
+	}
+
+	if _, ok := preferredAPIVersions[version]; !ok {
+		return "", serrors.InvalidValueErrorf(release, "unsupported Kubernetes version (%s), expected one of (%s). Newer releases need a newer vendored Kubernetes API", release, strings.Join(KubeVersions(), ", "))
+	}
+
+	return version, nil
+}
+
+// SetKubeVersion targets a Kubernetes release (see ParseKubeVersion).
+// An empty release restores the historical defaults.
+func SetKubeVersion(release string) error {
+	if len(release) == 0 {
+		kubeVersion = ""
+		return nil
+	}
+
+	version, err := ParseKubeVersion(release)
+	if err != nil {
+		return err
+	}
+
+	kubeVersion = version
+	return nil
+}
+
+// defaultAPIVersion picks the apiVersion for a kind whose Short manifest doesn't set "version".
+func defaultAPIVersion(kind, fallback string) string {
+	if apiVersion, ok := preferredAPIVersions[kubeVersion][kind]; ok {
+		return apiVersion
+	}
+
+	return fallback
+}
+
+// DeprecatedAPIVersion returns the apiVersion to use instead of the given one, unless it's preferred
+// by the newest release or by the targeted one. Kinds served under one apiVersion are never deprecated.
+func DeprecatedAPIVersion(kind, apiVersion string) (string, bool) {
+	versions := KubeVersions()
+	newest := preferredAPIVersions[versions[len(versions)-1]][kind]
+	if len(newest) == 0 || apiVersion == newest {
+		return "", false
+	}
+	if TargetedAPIVersion(kind, apiVersion) {
+		return "", false
+	}
+
+	return newest, true
+}
+
+// TargetedAPIVersion reports whether the targeted release prefers the apiVersion for the kind.
+func TargetedAPIVersion(kind, apiVersion string) bool {
+	return len(kubeVersion) > 0 && preferredAPIVersions[kubeVersion][kind] == apiVersion
+}
+
+// shortAPIVersion leaves out the apiVersion from a Short manifest if it's the one preferred by the targeted release.
+func shortAPIVersion(kind, apiVersion string) string {
+	if TargetedAPIVersion(kind, apiVersion) {
+		return ""
+	}
+
+	return apiVersion
+}
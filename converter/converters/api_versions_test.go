@@ -0,0 +1,93 @@
+package converters
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	exts "k8s.io/api/extensions/v1beta1"
+
+	"github.com/koki/short/types"
+)
+
+func TestSetKubeVersion(t *testing.T) {
+	defer SetKubeVersion("")
+
+	for _, release := range []string{"1.9", "v1.9", "1.9.3"} {
+		if err := SetKubeVersion(release); err != nil {
+			t.Fatalf("%s: %v", release, err)
+		}
+		if kubeVersion != "1.9" {
+			t.Errorf("%s: got %s, expected 1.9", release, kubeVersion)
+		}
+	}
+
+	if err := SetKubeVersion("1.6"); err == nil {
+		t.Error("expected an error for an unsupported release")
+	}
+}
+
+func TestDefaultDeploymentAPIVersion(t *testing.T) {
+	defer SetKubeVersion("")
+
+	deployment := func() *types.DeploymentWrapper {
+		return &types.DeploymentWrapper{
+			Deployment: types.Deployment{
+				Name:     "example",
+				Selector: &types.RSSelector{Shorthand: "app=example"},
+			},
+		}
+	}
+
+	kubeObj, err := Convert_Koki_Deployment_to_Kube_Deployment(deployment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kubeObj.(*exts.Deployment); !ok {
+		t.Errorf("without a target release, expected an extensions/v1beta1 Deployment, got %T", kubeObj)
+	}
+
+	SetKubeVersion("1.10")
+	kubeObj, err = Convert_Koki_Deployment_to_Kube_Deployment(deployment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kubeObj.(*appsv1.Deployment); !ok {
+		t.Errorf("for 1.10, expected an apps/v1 Deployment, got %T", kubeObj)
+	}
+
+	explicit := deployment()
+	explicit.Deployment.Version = "extensions/v1beta1"
+	kubeObj, err = Convert_Koki_Deployment_to_Kube_Deployment(explicit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kubeObj.(*exts.Deployment); !ok {
+		t.Errorf("an explicit version should win over the target release, got %T", kubeObj)
+	}
+
+	if version := shortAPIVersion("Deployment", "apps/v1"); len(version) > 0 {
+		t.Errorf("expected the preferred apiVersion to be left out, got %s", version)
+	}
+	if version := shortAPIVersion("Deployment", "apps/v1beta2"); version != "apps/v1beta2" {
+		t.Errorf("expected a non-preferred apiVersion to be kept, got %s", version)
+	}
+}
+
+func TestDeprecatedAPIVersion(t *testing.T) {
+	defer SetKubeVersion("")
+
+	if replacement, ok := DeprecatedAPIVersion("Deployment", "extensions/v1beta1"); !ok || replacement != "apps/v1" {
+		t.Errorf("expected extensions/v1beta1 to be deprecated for apps/v1, got (%s, %t)", replacement, ok)
+	}
+	if _, ok := DeprecatedAPIVersion("Deployment", "apps/v1"); ok {
+		t.Error("expected apps/v1 not to be deprecated")
+	}
+	if _, ok := DeprecatedAPIVersion("Service", "v1"); ok {
+		t.Error("expected a single-version kind not to be deprecated")
+	}
+
+	SetKubeVersion("1.7")
+	if _, ok := DeprecatedAPIVersion("Deployment", "extensions/v1beta1"); ok {
+		t.Error("expected the apiVersion preferred by the target release not to be deprecated")
+	}
+}
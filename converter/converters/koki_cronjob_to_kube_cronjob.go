@@ -48,7 +48,11 @@ func Convert_Koki_CronJob_to_Kube_batch_v1beta1_CronJob(kokiObj *types.CronJobWr
 
 	kubeCronJob.Name = kokiCronJob.Name
 	kubeCronJob.Namespace = kokiCronJob.Namespace
-	kubeCronJob.APIVersion = kokiCronJob.Version
+	if len(kokiCronJob.Version) == 0 {
+		kubeCronJob.APIVersion = defaultAPIVersion("CronJob", "")
+	} else {
+		kubeCronJob.APIVersion = kokiCronJob.Version
+	}
 	kubeCronJob.Kind = "CronJob"
 	kubeCronJob.ClusterName = kokiCronJob.Cluster
 	kubeCronJob.Labels = kokiCronJob.Labels
@@ -1,6 +1,7 @@
 package converters
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	exts "k8s.io/api/extensions/v1beta1"
 
@@ -30,6 +31,8 @@ func Convert_Koki_DaemonSet_to_Kube_DaemonSet(daemonSet *types.DaemonSetWrapper)
 	}
 
 	switch versionedDaemonSet := versionedDaemonSet.(type) {
+	case *appsv1.DaemonSet:
+		// Perform apps/v1-specific initialization here.
 	case *appsv1beta2.DaemonSet:
 		// Perform apps/v1beta2-specific initialization here.
 	case *exts.DaemonSet:
@@ -49,7 +52,7 @@ func Convert_Koki_DaemonSet_to_Kube_apps_v1beta2_DaemonSet(daemonSet *types.Daem
 	kubeDaemonSet.Name = kokiDaemonSet.Name
 	kubeDaemonSet.Namespace = kokiDaemonSet.Namespace
 	if len(kokiDaemonSet.Version) == 0 {
-		kubeDaemonSet.APIVersion = "extensions/v1beta1"
+		kubeDaemonSet.APIVersion = defaultAPIVersion("DaemonSet", "extensions/v1beta1")
 	} else {
 		kubeDaemonSet.APIVersion = kokiDaemonSet.Version
 	}
@@ -1,6 +1,7 @@
 package converters
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	exts "k8s.io/api/extensions/v1beta1"
@@ -31,6 +32,8 @@ func Convert_Koki_Deployment_to_Kube_Deployment(deployment *types.DeploymentWrap
 	}
 
 	switch versionedDeployment := versionedDeployment.(type) {
+	case *appsv1.Deployment:
+		// Perform apps/v1-specific initialization here.
 	case *appsv1beta1.Deployment:
 		// Perform apps/v1beta1-specific initialization here.
 	case *appsv1beta2.Deployment:
@@ -52,7 +55,7 @@ func Convert_Koki_Deployment_to_Kube_apps_v1beta2_Deployment(deployment *types.D
 	kubeDeployment.Name = kokiDeployment.Name
 	kubeDeployment.Namespace = kokiDeployment.Namespace
 	if len(kokiDeployment.Version) == 0 {
-		kubeDeployment.APIVersion = "extensions/v1beta1"
+		kubeDeployment.APIVersion = defaultAPIVersion("Deployment", "extensions/v1beta1")
 	} else {
 		kubeDeployment.APIVersion = kokiDeployment.Version
 	}
@@ -65,38 +65,39 @@ func revertSubsets(subsets []types.EndpointSubset) ([]v1.EndpointSubset, error)
 	return kubeSubsets, nil
 }
 
+// revertEndpointPorts parses ports of the form "[PROTOCOL://]PORT[:NAME]".
 func revertEndpointPorts(ports []string) ([]v1.EndpointPort, error) {
 	var kubePorts []v1.EndpointPort
 
 	for i := range ports {
 		port := ports[i]
 
-		fields := strings.Split(port, ":")
-
 		protocol := v1.ProtocolTCP
-		portVal := 0
-		name := ""
-		if len(fields) >= 2 {
-			if strings.ToLower(fields[0]) == "udp" {
+		rest := port
+		if fields := strings.SplitN(port, "://", 2); len(fields) == 2 {
+			switch strings.ToLower(fields[0]) {
+			case "tcp":
+			case "udp":
 				protocol = v1.ProtocolUDP
-			} else if strings.ToLower(fields[0]) != "tcp" {
+			default:
 				return nil, serrors.InvalidValueErrorf(fields[0], "invalid protocol")
 			}
+			rest = fields[1]
+		}
 
-			portStr := strings.TrimPrefix(fields[1], "//")
-			portV, err := strconv.Atoi(portStr)
-			if err != nil {
-				return nil, err
-			}
-			portVal = portV
+		fields := strings.Split(rest, ":")
+		if len(fields) > 2 {
+			return nil, serrors.InvalidValueErrorf(port, "invalid endpoints port format")
 		}
 
-		if len(fields) == 3 {
-			name = fields[2]
+		portVal, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, serrors.InvalidValueErrorf(port, "invalid port number")
 		}
 
-		if len(fields) != 2 && len(fields) != 3 {
-			return nil, serrors.InvalidValueErrorf(port, "invalid endpoints port format")
+		name := ""
+		if len(fields) == 2 {
+			name = fields[1]
 		}
 
 		kubePort := v1.EndpointPort{
@@ -0,0 +1,43 @@
+package converters
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+func Convert_Koki_Lease_to_Kube(lease *types.LeaseWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeLease{}
+	koki := &lease.Lease
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	if len(koki.Version) == 0 {
+		kube.APIVersion = "coordination.k8s.io/v1"
+	} else {
+		kube.APIVersion = koki.Version
+	}
+	kube.Kind = parser.LeaseGroupKind.Kind
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.HolderIdentity = koki.Holder
+	kube.Spec.LeaseDurationSeconds = koki.DurationSeconds
+	kube.Spec.AcquireTime = koki.AcquireTime
+	kube.Spec.RenewTime = koki.RenewTime
+	kube.Spec.LeaseTransitions = koki.Transitions
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(kube)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, kube, "converting Lease to an unstructured object")
+	}
+
+	// Typed objects leave out the empty creationTimestamp when they're written, so do the same here.
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+
+	return &unstructured.Unstructured{Object: obj}, nil
+}
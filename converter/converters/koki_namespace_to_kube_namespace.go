@@ -1,6 +1,8 @@
 package converters
 
 import (
+	"strings"
+
 	"k8s.io/api/core/v1"
 
 	"github.com/koki/short/types"
@@ -21,7 +23,10 @@ func Convert_Koki_Namespace_to_Kube_Namespace(kokiWrapper *types.NamespaceWrappe
 	}
 	kubeNamespace.Kind = "Namespace"
 	kubeNamespace.ClusterName = kokiNamespace.Cluster
-	kubeNamespace.Labels = kokiNamespace.Labels
+	kubeNamespace.Labels, err = revertPodSecurityLabels(kokiNamespace.Labels, kokiNamespace.PodSecurity)
+	if err != nil {
+		return nil, err
+	}
 	kubeNamespace.Annotations = kokiNamespace.Annotations
 
 	spec, err := revertNamespaceSpec(kokiNamespace)
@@ -89,5 +94,10 @@ func revertFinalizer(kokiFinalizer types.FinalizerName) (v1.FinalizerName, error
 		return v1.FinalizerKubernetes, nil
 	}
 
-	return "", serrors.InvalidValueErrorf(kokiFinalizer, "unrecognized value")
+	// Other finalizers are domain-qualified names, e.g. "example.com/cleanup".
+	if !strings.Contains(string(kokiFinalizer), "/") {
+		return "", serrors.InvalidValueErrorf(kokiFinalizer, "unrecognized value")
+	}
+
+	return v1.FinalizerName(kokiFinalizer), nil
 }
@@ -0,0 +1,134 @@
+package converters
+
+import (
+	"k8s.io/api/core/v1"
+
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+func Convert_Koki_Node_to_Kube(node *types.NodeWrapper) (*v1.Node, error) {
+	kube := &v1.Node{}
+	koki := &node.Node
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	if len(koki.Version) == 0 {
+		kube.APIVersion = "v1"
+	} else {
+		kube.APIVersion = koki.Version
+	}
+	kube.Kind = "Node"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.PodCIDR = koki.PodCIDR
+	kube.Spec.ProviderID = koki.ProviderID
+	kube.Spec.DoNotUse_ExternalID = koki.ExternalID
+	kube.Spec.Unschedulable = koki.Unschedulable
+	kube.Spec.Taints = revertTaints(koki.Taints)
+	if koki.ConfigSource != nil {
+		configMapRef, err := revertTarget(koki.ConfigSource)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "node config source")
+		}
+		kube.Spec.ConfigSource = &v1.NodeConfigSource{
+			ConfigMapRef: configMapRef,
+		}
+	}
+
+	status, err := revertNodeStatus(koki.NodeStatus)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "node status")
+	}
+	kube.Status = *status
+
+	return kube, nil
+}
+
+func revertTaints(taints []types.Taint) []v1.Taint {
+	var kubeTaints []v1.Taint
+	for _, taint := range taints {
+		kubeTaints = append(kubeTaints, v1.Taint{
+			Key:       taint.Key,
+			Value:     taint.Value,
+			Effect:    v1.TaintEffect(taint.Effect),
+			TimeAdded: taint.TimeAdded,
+		})
+	}
+
+	return kubeTaints
+}
+
+func revertNodeStatus(status types.NodeStatus) (*v1.NodeStatus, error) {
+	kubeStatus := &v1.NodeStatus{
+		Capacity:    status.Capacity,
+		Allocatable: status.Allocatable,
+	}
+	kubeStatus.DaemonEndpoints.KubeletEndpoint.Port = status.KubeletPort
+
+	phase, err := revertNodePhase(status.Phase)
+	if err != nil {
+		return nil, err
+	}
+	kubeStatus.Phase = phase
+
+	for i, condition := range status.Conditions {
+		conditionStatus, err := revertConditionStatus(condition.Status)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "conditions[%d]", i)
+		}
+		kubeStatus.Conditions = append(kubeStatus.Conditions, v1.NodeCondition{
+			Type:               v1.NodeConditionType(condition.Type),
+			Status:             conditionStatus,
+			LastHeartbeatTime:  condition.LastHeartbeatTime,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+
+	for _, address := range status.Addresses {
+		kubeStatus.Addresses = append(kubeStatus.Addresses, v1.NodeAddress{
+			Type:    v1.NodeAddressType(address.Type),
+			Address: address.Address,
+		})
+	}
+
+	if status.Info != nil {
+		kubeStatus.NodeInfo = v1.NodeSystemInfo(*status.Info)
+	}
+
+	for _, image := range status.Images {
+		kubeStatus.Images = append(kubeStatus.Images, v1.ContainerImage(image))
+	}
+
+	for _, volume := range status.VolumesInUse {
+		kubeStatus.VolumesInUse = append(kubeStatus.VolumesInUse, v1.UniqueVolumeName(volume))
+	}
+
+	for _, volume := range status.VolumesAttached {
+		kubeStatus.VolumesAttached = append(kubeStatus.VolumesAttached, v1.AttachedVolume{
+			Name:       v1.UniqueVolumeName(volume.Name),
+			DevicePath: volume.DevicePath,
+		})
+	}
+
+	return kubeStatus, nil
+}
+
+func revertNodePhase(phase types.NodePhase) (v1.NodePhase, error) {
+	switch phase {
+	case "":
+		return "", nil
+	case types.NodePending:
+		return v1.NodePending, nil
+	case types.NodeRunning:
+		return v1.NodeRunning, nil
+	case types.NodeTerminated:
+		return v1.NodeTerminated, nil
+	}
+
+	return "", serrors.InvalidValueErrorf(phase, "unrecognized node phase")
+}
@@ -11,6 +11,7 @@ import (
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/koki/short/converter/converters/affinity"
+	"github.com/koki/short/parser/expressions"
 	"github.com/koki/short/types"
 	"github.com/koki/short/util"
 	"github.com/koki/short/util/floatstr"
@@ -30,6 +31,7 @@ func Convert_Koki_Pod_to_Kube_v1_Pod(pod *types.PodWrapper) (*v1.Pod, error) {
 	kubePod.Kind = "Pod"
 
 	kubePod.ObjectMeta = revertPodObjectMeta(kokiPod.PodTemplateMeta)
+	kubePod.ObjectMeta.Annotations = revertSecurityAnnotations(kubePod.ObjectMeta.Annotations, kokiPod.PodTemplate)
 
 	spec, err := revertPodSpec(kokiPod.PodTemplate)
 	if err != nil {
@@ -69,7 +71,9 @@ func Convert_Koki_Pod_to_Kube_v1_Pod(pod *types.PodWrapper) (*v1.Pod, error) {
 		if err != nil {
 			return nil, err
 		}
-		initContainerStatuses = append(initContainerStatuses, status)
+		if status.ContainerID != "" {
+			initContainerStatuses = append(initContainerStatuses, status)
+		}
 	}
 	kubePod.Status.InitContainerStatuses = initContainerStatuses
 
@@ -209,6 +213,38 @@ func revertPodSpec(kokiPod types.PodTemplate) (*v1.PodSpec, error) {
 	return &spec, nil
 }
 
+// revertSecurityAnnotations adds the seccomp, AppArmor and sysctl annotations for the pod's security fields.
+func revertSecurityAnnotations(kubeAnnotations map[string]string, kokiPod types.PodTemplate) map[string]string {
+	annotations := map[string]string{}
+	if len(kokiPod.Seccomp) > 0 {
+		annotations[v1.SeccompPodAnnotationKey] = kokiPod.Seccomp
+	}
+	if len(kokiPod.Sysctls) > 0 {
+		annotations[v1.SysctlsPodAnnotationKey] = strings.Join(kokiPod.Sysctls, ",")
+	}
+	if len(kokiPod.UnsafeSysctls) > 0 {
+		annotations[v1.UnsafeSysctlsPodAnnotationKey] = strings.Join(kokiPod.UnsafeSysctls, ",")
+	}
+	for _, container := range podContainers(&kokiPod) {
+		if len(container.Seccomp) > 0 {
+			annotations[v1.SeccompContainerAnnotationKeyPrefix+container.Name] = container.Seccomp
+		}
+		if len(container.AppArmor) > 0 {
+			annotations[AppArmorContainerAnnotationKeyPrefix+container.Name] = container.AppArmor
+		}
+	}
+
+	if len(annotations) == 0 {
+		return kubeAnnotations
+	}
+
+	for key, val := range kubeAnnotations {
+		annotations[key] = val
+	}
+
+	return annotations
+}
+
 func revertVolumes(kokiVolumes map[string]types.Volume) ([]v1.Volume, error) {
 	kubeVolumes := []v1.Volume{}
 	names := []string{}
@@ -562,6 +598,7 @@ func revertFibreChannelVolume(source *types.FibreChannelVolume) *v1.FCVolumeSour
 func revertFlockerVolume(source *types.FlockerVolume) *v1.FlockerVolumeSource {
 	return &v1.FlockerVolumeSource{
 		DatasetUUID: source.DatasetUUID,
+		DatasetName: source.DatasetName,
 	}
 }
 
@@ -673,6 +710,9 @@ func revertVolume(name string, kokiVolume types.Volume) (*v1.Volume, error) {
 		}, nil
 	}
 	if kokiVolume.GcePD != nil {
+		if len(kokiVolume.GcePD.Zones) > 0 {
+			return nil, serrors.InvalidInstanceErrorf(kokiVolume.GcePD, "volume (%s): zones only apply to persistent volumes", name)
+		}
 		return &v1.Volume{
 			Name: name,
 			VolumeSource: v1.VolumeSource{
@@ -969,6 +1009,8 @@ func revertVolume(name string, kokiVolume types.Volume) (*v1.Volume, error) {
 func revertContainerStatus(container types.Container) (v1.ContainerStatus, error) {
 	var status v1.ContainerStatus
 
+	status.Name = container.Name
+	status.Image = container.Image
 	status.ContainerID = container.ContainerID
 	status.ImageID = container.ImageID
 	status.RestartCount = container.Restarts
@@ -1123,39 +1165,27 @@ func revertTolerations(tolerations []types.Toleration) ([]v1.Toleration, error)
 			TolerationSeconds: toleration.ExpiryAfter,
 		}
 
-		superFields := strings.Split(string(toleration.Selector), ":")
-		switch len(superFields) {
-		case 2:
-			switch superFields[1] {
-			case "NoSchedule":
-				kubeToleration.Effect = v1.TaintEffectNoSchedule
-			case "PreferNoSchedule":
-				kubeToleration.Effect = v1.TaintEffectPreferNoSchedule
-			case "NoExecute":
-				kubeToleration.Effect = v1.TaintEffectNoExecute
-			default:
-				return nil, serrors.InvalidInstanceErrorf(toleration, "unexpected toleration selector")
+		expr, err := expressions.ParseTaintExpr(string(toleration.Selector))
+		if err != nil {
+			return nil, serrors.InvalidInstanceContextErrorf(err, toleration, "unexpected toleration selector")
+		}
+		if expr.Seconds != nil {
+			if toleration.ExpiryAfter != nil {
+				return nil, serrors.InvalidInstanceErrorf(toleration, "set either expiry_after or the seconds in the selector, not both")
 			}
-		case 1:
-			// Do nothing
-		default:
-			return nil, serrors.InvalidInstanceErrorf(toleration, "unexpected toleration effect")
+			kubeToleration.TolerationSeconds = expr.Seconds
 		}
+		kubeToleration.Effect = v1.TaintEffect(expr.Effect)
 
-		fields := strings.Split(superFields[0], "=")
-		if len(fields) == 1 {
-			if fields[0] == "*" {
-				kubeToleration.Key = ""
-			} else {
-				kubeToleration.Key = fields[0]
-			}
-			kubeToleration.Operator = v1.TolerationOpExists
-		} else if len(fields) == 2 {
-			kubeToleration.Key = fields[0]
+		if expr.Value != nil {
+			kubeToleration.Key = expr.Key
 			kubeToleration.Operator = v1.TolerationOpEqual
-			kubeToleration.Value = fields[1]
+			kubeToleration.Value = *expr.Value
 		} else {
-			return nil, serrors.InvalidInstanceErrorf(toleration, "unexpected toleration selector")
+			if expr.Key != "*" {
+				kubeToleration.Key = expr.Key
+			}
+			kubeToleration.Operator = v1.TolerationOpExists
 		}
 
 		kubeTolerations = append(kubeTolerations, kubeToleration)
@@ -1291,7 +1321,7 @@ func revertKokiContainer(container types.Container) (v1.Container, error) {
 	kubeContainer.Env = envs
 	kubeContainer.EnvFrom = envFroms
 
-	resources, err := revertResources(container.CPU, container.Mem)
+	resources, err := revertResources(container.CPU, container.Mem, container.Resources)
 	if err != nil {
 		return v1.Container{}, err
 	}
@@ -1312,13 +1342,17 @@ func revertKokiContainer(container types.Container) (v1.Container, error) {
 	kubeContainer.TerminationMessagePath = container.TerminationMsgPath
 	kubeContainer.TerminationMessagePolicy = revertTerminationMsgPolicy(container.TerminationMsgPolicy)
 	kubeContainer.ImagePullPolicy = revertImagePullPolicy(container.Pull)
-	kubeContainer.VolumeMounts = revertVolumeMounts(container.VolumeMounts)
+	volumeMounts, err := revertVolumeMounts(container.VolumeMounts)
+	if err != nil {
+		return v1.Container{}, err
+	}
+	kubeContainer.VolumeMounts = volumeMounts
 
 	kubeContainer.Stdin = container.Stdin
 	kubeContainer.StdinOnce = container.StdinOnce
 	kubeContainer.TTY = container.TTY
 
-	lc, err := revertLifecycle(container.OnStart, container.PreStop)
+	lc, err := revertLifecycle((*types.Action)(container.OnStart), (*types.Action)(container.PreStop))
 	if err != nil {
 		return v1.Container{}, err
 	}
@@ -1460,7 +1494,7 @@ func revertLifecycleAction(action *types.Action) (*v1.Handler, error) {
 	}
 
 	if action.Net != nil {
-		urlStruct, err := url.Parse(action.Net.URL)
+		urlStruct, err := parseActionURL(action.Net.URL)
 		if err != nil {
 			return nil, serrors.InvalidInstanceErrorf(action, "couldn't parse URL: %s", err)
 		}
@@ -1471,7 +1505,7 @@ func revertLifecycleAction(action *types.Action) (*v1.Handler, error) {
 		fields := strings.Split(hostPort, ":")
 		if len(fields) == 2 {
 			host = fields[0]
-			port = intstr.FromString(fields[1])
+			port = intstr.Parse(fields[1])
 		} else if len(fields) == 1 {
 			host = hostPort
 		} else {
@@ -1522,7 +1556,7 @@ func revertLifecycleAction(action *types.Action) (*v1.Handler, error) {
 	return handler, nil
 }
 
-func revertVolumeMounts(mounts []types.VolumeMount) []v1.VolumeMount {
+func revertVolumeMounts(mounts []types.VolumeMount) ([]v1.VolumeMount, error) {
 	var kubeMounts []v1.VolumeMount
 	for i := range mounts {
 		mount := mounts[i]
@@ -1532,24 +1566,16 @@ func revertVolumeMounts(mounts []types.VolumeMount) []v1.VolumeMount {
 		}
 		kubeMount.MountPath = mount.MountPath
 
-		fields := strings.Split(mount.Store, ":")
-		if len(fields) == 1 {
-			kubeMount.Name = mount.Store
-		} else if len(fields) == 2 {
-			kubeMount.Name = fields[0]
-			if fields[1] == "ro" {
-				kubeMount.ReadOnly = true
-			} else {
-				kubeMount.SubPath = fields[1]
-			}
-		} else if len(fields) == 3 {
-			kubeMount.Name = fields[0]
-			kubeMount.SubPath = fields[1]
-			kubeMount.ReadOnly = true
+		name, subPath, readOnly, err := types.SplitMountStore(mount.Store)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "volume mount[%d]", i)
 		}
+		kubeMount.Name = name
+		kubeMount.SubPath = subPath
+		kubeMount.ReadOnly = readOnly
 		kubeMounts = append(kubeMounts, kubeMount)
 	}
-	return kubeMounts
+	return kubeMounts, nil
 }
 
 func revertMountPropagation(prop *types.MountPropagation) *v1.MountPropagationMode {
@@ -1589,6 +1615,34 @@ func revertTerminationMsgPolicy(policy types.TerminationMessagePolicy) v1.Termin
 	return ""
 }
 
+// parseActionURL parses the URL of a probe or lifecycle hook. Unlike url.Parse, it accepts
+// named ports, e.g. "HTTP://localhost:http/healthz".
+func parseActionURL(s string) (*url.URL, error) {
+	urlStruct, err := url.Parse(s)
+	if err == nil {
+		return urlStruct, nil
+	}
+
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return nil, err
+	}
+	rest := s[i+len("://"):]
+	hostEnd := strings.IndexAny(rest, "/?")
+	if hostEnd < 0 {
+		hostEnd = len(rest)
+	}
+
+	// Parse everything but the host, then put the host back.
+	urlStruct, placeholderErr := url.Parse(s[:i] + "://placeholder" + rest[hostEnd:])
+	if placeholderErr != nil {
+		return nil, err
+	}
+	urlStruct.Host = rest[:hostEnd]
+
+	return urlStruct, nil
+}
+
 func revertProbe(probe *types.Probe) (*v1.Probe, error) {
 	if probe == nil {
 		return nil, nil
@@ -1608,7 +1662,7 @@ func revertProbe(probe *types.Probe) (*v1.Probe, error) {
 	}
 
 	if probe.Net != nil {
-		urlStruct, err := url.Parse(probe.Net.URL)
+		urlStruct, err := parseActionURL(probe.Net.URL)
 		if err != nil {
 			return nil, serrors.InvalidInstanceContextErrorf(err, probe, "parsing URL")
 		}
@@ -1665,7 +1719,7 @@ func revertProbe(probe *types.Probe) (*v1.Probe, error) {
 	return kubeProbe, nil
 }
 
-func revertResources(cpu *types.CPU, mem *types.Mem) (v1.ResourceRequirements, error) {
+func revertResources(cpu *types.CPU, mem *types.Mem, others map[string]types.ResourceBounds) (v1.ResourceRequirements, error) {
 	limits := v1.ResourceList{}
 	requests := v1.ResourceList{}
 	requirements := v1.ResourceRequirements{
@@ -1709,6 +1763,29 @@ func revertResources(cpu *types.CPU, mem *types.Mem) (v1.ResourceRequirements, e
 		}
 	}
 
+	for name, bounds := range others {
+		resourceName := v1.ResourceName(name)
+		if resourceName == v1.ResourceCPU || resourceName == v1.ResourceMemory {
+			return requirements, serrors.InvalidValueErrorf(name, "use the cpu and mem fields for cpu and memory")
+		}
+
+		if bounds.Min != "" {
+			q, err := resource.ParseQuantity(bounds.Min)
+			if err != nil {
+				return requirements, serrors.InvalidInstanceErrorf(bounds, "couldn't parse min quantity of %s: %s", name, err)
+			}
+			requests[resourceName] = q
+		}
+
+		if bounds.Max != "" {
+			q, err := resource.ParseQuantity(bounds.Max)
+			if err != nil {
+				return requirements, serrors.InvalidInstanceErrorf(bounds, "couldn't parse max quantity of %s: %s", name, err)
+			}
+			limits[resourceName] = q
+		}
+	}
+
 	return requirements, nil
 }
 
@@ -1730,13 +1807,22 @@ func revertEnv(envs []types.Env) ([]v1.EnvVar, []v1.EnvFromSource, error) {
 		from := e.From
 
 		// ResourceFieldRef
-		if strings.Index(from.From, "limits.") == 0 || strings.Index(from.From, "requests.") == 0 {
+		if containerName, resourceName, divisor, ok := types.ParseResourceFieldRef(from.From); ok {
+			selector := &v1.ResourceFieldSelector{
+				ContainerName: containerName,
+				Resource:      resourceName,
+			}
+			if len(divisor) > 0 {
+				q, err := resource.ParseQuantity(divisor)
+				if err != nil {
+					return nil, nil, serrors.InvalidInstanceContextErrorf(err, e, "couldn't parse divisor")
+				}
+				selector.Divisor = q
+			}
 			envVar := v1.EnvVar{
 				Name: from.Key,
 				ValueFrom: &v1.EnvVarSource{
-					ResourceFieldRef: &v1.ResourceFieldSelector{
-						Resource: from.From,
-					},
+					ResourceFieldRef: selector,
 				},
 			}
 			envVars = append(envVars, envVar)
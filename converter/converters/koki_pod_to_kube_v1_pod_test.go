@@ -0,0 +1,77 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/koki/short/types"
+	"github.com/koki/short/yaml"
+)
+
+func TestRevertLifecycleShorthand(t *testing.T) {
+	cases := map[string]string{
+		`
+pod:
+  name: web
+  containers:
+  - name: web
+    image: nginx
+    on_start: "exec: /bin/warm --cache /var/cache"
+    pre_stop: "http: /quit:8080"
+`: `postStart:
+  exec:
+    command:
+    - /bin/warm
+    - --cache
+    - /var/cache
+preStop:
+  httpGet:
+    path: /quit
+    port: 8080
+    scheme: HTTP
+`,
+		`
+pod:
+  name: web
+  containers:
+  - name: web
+    image: nginx
+    on_start:
+      command: [sh, -c, "echo 'warm' > /tmp/ready"]
+    pre_stop:
+      net:
+        url: HTTPS://:https/quit
+`: `postStart:
+  exec:
+    command:
+    - sh
+    - -c
+    - echo 'warm' > /tmp/ready
+preStop:
+  httpGet:
+    path: /quit
+    port: https
+    scheme: HTTPS
+`,
+	}
+
+	for short, expected := range cases {
+		pod := &types.PodWrapper{}
+		err := yaml.Unmarshal([]byte(short), pod)
+		if err != nil {
+			t.Errorf("%s: %s", short, err)
+			continue
+		}
+		kubePod, err := Convert_Koki_Pod_to_Kube_v1_Pod(pod)
+		if err != nil {
+			t.Errorf("%s: %s", short, err)
+			continue
+		}
+		b, err := yaml.Marshal(kubePod.Spec.Containers[0].Lifecycle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", short, expected, b)
+		}
+	}
+}
@@ -40,7 +40,11 @@ func revertPodPreset(kokiPodPreset *types.PodPreset) (settingsv1alpha1.PodPreset
 	}
 	kubeSpec.Volumes = volumes
 
-	kubeSpec.VolumeMounts = revertVolumeMounts(kokiPodPreset.VolumeMounts)
+	volumeMounts, err := revertVolumeMounts(kokiPodPreset.VolumeMounts)
+	if err != nil {
+		return kubeSpec, err
+	}
+	kubeSpec.VolumeMounts = volumeMounts
 
 	envs, envFroms, err := revertEnv(kokiPodPreset.Env)
 	if err != nil {
@@ -5,12 +5,43 @@ import (
 
 	"k8s.io/api/core/v1"
 	exts "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/koki/short/parser"
 	"github.com/koki/short/types"
+	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
 
-func Convert_Koki_PodSecurityPolicy_to_Kube_PodSecurityPolicy(podSecurityPolicy *types.PodSecurityPolicyWrapper) (*exts.PodSecurityPolicy, error) {
+func Convert_Koki_PodSecurityPolicy_to_Kube_PodSecurityPolicy(podSecurityPolicy *types.PodSecurityPolicyWrapper) (runtime.Object, error) {
+	kubePodSecurityPolicy, err := Convert_Koki_PodSecurityPolicy_to_Kube_exts_PodSecurityPolicy(podSecurityPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if kubePodSecurityPolicy.APIVersion == "extensions/v1beta1" {
+		return kubePodSecurityPolicy, nil
+	}
+
+	// Serialize the "generic" kube PodSecurityPolicy.
+	b, err := yaml.Marshal(kubePodSecurityPolicy)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, kubePodSecurityPolicy, "couldn't serialize 'generic' kube PodSecurityPolicy")
+	}
+
+	// Deserialize a versioned kube PodSecurityPolicy using its apiVersion.
+	versionedPodSecurityPolicy, err := parser.ParseSingleKubeNativeFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := versionedPodSecurityPolicy.(*policyv1beta1.PodSecurityPolicy); !ok {
+		return nil, serrors.TypeErrorf(versionedPodSecurityPolicy, "deserialized the manifest, but not as a supported kube PodSecurityPolicy")
+	}
+
+	return versionedPodSecurityPolicy, nil
+}
+
+func Convert_Koki_PodSecurityPolicy_to_Kube_exts_PodSecurityPolicy(podSecurityPolicy *types.PodSecurityPolicyWrapper) (*exts.PodSecurityPolicy, error) {
 	kubePodSecurityPolicy := &exts.PodSecurityPolicy{}
 	kokiPodSecurityPolicy := &podSecurityPolicy.PodSecurityPolicy
 
@@ -164,6 +195,8 @@ func revertPodSecurityPolicyUIDPolicy(kokiUIDPolicy types.UIDPolicy) (exts.RunAs
 	switch kokiUIDPolicy.Policy {
 	case types.UIDPolicyMust:
 		policyType = exts.RunAsUserStrategyMustRunAs
+	case types.UIDPolicyNonRoot:
+		policyType = exts.RunAsUserStrategyMustRunAsNonRoot
 	case types.UIDPolicyAny:
 		policyType = exts.RunAsUserStrategyRunAsAny
 	case "":
@@ -321,6 +354,10 @@ func revertPodSecurityPolicyVolumePlugin(kokiVolPlugin string) (exts.FSType, err
 		return exts.Quobyte, nil
 	case types.VolumeTypeAzureDisk:
 		return exts.AzureDisk, nil
+	case types.VolumeTypeProjected:
+		return podSecurityPolicyProjected, nil
+	case types.VolumeTypeCSI:
+		return podSecurityPolicyCSI, nil
 	case types.VolumeTypeAny:
 		return exts.All, nil
 	}
@@ -7,6 +7,7 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/koki/short/parser/expressions"
 	"github.com/koki/short/types"
 	"github.com/koki/short/util"
 	serrors "github.com/koki/structurederrors"
@@ -35,14 +36,26 @@ func Convert_Koki_PersistentVolume_to_Kube_v1_PersistentVolume(pv *types.Persist
 	if err != nil {
 		return nil, err
 	}
+	kubePV.Labels, err = revertRegionalPDZones(kokiPV.GcePD, kubePV.Labels)
+	if err != nil {
+		return nil, err
+	}
 	if kokiPV.AccessModes != nil {
 		kubeSpec.AccessModes = kokiPV.AccessModes.Modes
 	}
-	kubeSpec.ClaimRef = kokiPV.Claim
+	kubeSpec.ClaimRef = (*v1.ObjectReference)(kokiPV.Claim)
 	kubeSpec.PersistentVolumeReclaimPolicy = revertReclaimPolicy(kokiPV.ReclaimPolicy)
 	kubeSpec.StorageClassName = kokiPV.StorageClass
-	if len(kokiPV.MountOptions) > 0 {
-		kubeSpec.MountOptions = strings.Split(kokiPV.MountOptions, ",")
+	if kokiPV.MountOptions != nil {
+		kubeSpec.MountOptions = kokiPV.MountOptions.Options
+	}
+	kubeSpec.VolumeMode, err = revertPersistentVolumeMode(kokiPV.VolumeMode)
+	if err != nil {
+		return nil, err
+	}
+	kubeSpec.NodeAffinity, err = revertVolumeNodeAffinity(kokiPV.NodeAffinity)
+	if err != nil {
+		return nil, err
 	}
 
 	kubePV.Status, err = revertPersistentVolumeStatus(kokiPV.PersistentVolumeStatus)
@@ -53,6 +66,24 @@ func Convert_Koki_PersistentVolume_to_Kube_v1_PersistentVolume(pv *types.Persist
 	return kubePV, nil
 }
 
+// revertRegionalPDZones writes the replica zones of a regional GCE disk to the zone label, as Kubernetes does.
+func revertRegionalPDZones(kokiPD *types.GcePDVolume, kokiLabels map[string]string) (map[string]string, error) {
+	if kokiPD == nil || len(kokiPD.Zones) == 0 {
+		return kokiLabels, nil
+	}
+	if _, ok := kokiLabels[zoneLabel]; ok {
+		return nil, serrors.InvalidInstanceErrorf(kokiPD, "expected either zones or the %s label, not both", zoneLabel)
+	}
+
+	labels := map[string]string{}
+	for key, val := range kokiLabels {
+		labels[key] = val
+	}
+	labels[zoneLabel] = strings.Join(kokiPD.Zones, multiZoneDelimiter)
+
+	return labels, nil
+}
+
 func revertPersistentVolumeStatus(kokiStatus types.PersistentVolumeStatus) (v1.PersistentVolumeStatus, error) {
 	phase, err := revertPersistentVolumePhase(kokiStatus.Phase)
 	if err != nil {
@@ -349,6 +380,45 @@ func revertSecretRef(kokiRef string) (*v1.SecretReference, error) {
 	}, nil
 }
 
+func revertPersistentVolumeMode(kokiMode *types.PersistentVolumeMode) (*v1.PersistentVolumeMode, error) {
+	if kokiMode == nil {
+		return nil, nil
+	}
+
+	var kubeMode v1.PersistentVolumeMode
+	switch *kokiMode {
+	case types.PersistentVolumeBlock:
+		kubeMode = v1.PersistentVolumeBlock
+	case types.PersistentVolumeFilesystem:
+		kubeMode = v1.PersistentVolumeFilesystem
+	default:
+		return nil, serrors.InvalidValueErrorf(*kokiMode, "unrecognized volume mode")
+	}
+
+	return &kubeMode, nil
+}
+
+func revertVolumeNodeAffinity(kokiAffinity []string) (*v1.VolumeNodeAffinity, error) {
+	if len(kokiAffinity) == 0 {
+		return nil, nil
+	}
+
+	terms := make([]v1.NodeSelectorTerm, len(kokiAffinity))
+	for i, kokiTerm := range kokiAffinity {
+		term, err := expressions.ParseNodeSelectorTerm(kokiTerm)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "node_affinity[%d]", i)
+		}
+		terms[i] = *term
+	}
+
+	return &v1.VolumeNodeAffinity{
+		Required: &v1.NodeSelector{
+			NodeSelectorTerms: terms,
+		},
+	}, nil
+}
+
 func revertReclaimPolicy(kokiPolicy types.PersistentVolumeReclaimPolicy) v1.PersistentVolumeReclaimPolicy {
 	return v1.PersistentVolumeReclaimPolicy(strings.Title(string(kokiPolicy)))
 }
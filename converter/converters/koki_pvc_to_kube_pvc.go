@@ -81,6 +81,8 @@ func revertAccessMode(accessMode types.PersistentVolumeAccessMode) v1.Persistent
 		return v1.ReadOnlyMany
 	case types.ReadWriteMany:
 		return v1.ReadWriteMany
+	case types.ReadWriteOncePod:
+		return types.KubeReadWriteOncePod
 	default:
 		return ""
 	}
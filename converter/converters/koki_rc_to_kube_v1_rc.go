@@ -127,6 +127,7 @@ func revertTemplate(kokiMeta *types.PodTemplateMeta, kokiSpec types.PodTemplate)
 		}
 		template.Spec = *spec
 	}
+	template.ObjectMeta.Annotations = revertSecurityAnnotations(template.ObjectMeta.Annotations, kokiSpec)
 
 	return &template, nil
 }
@@ -3,6 +3,7 @@ package converters
 import (
 	"reflect"
 
+	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	exts "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +35,8 @@ func Convert_Koki_ReplicaSet_to_Kube_ReplicaSet(rs *types.ReplicaSetWrapper) (in
 	}
 
 	switch versionedReplicaSet := versionedReplicaSet.(type) {
+	case *appsv1.ReplicaSet:
+		// Perform apps/v1-specific initialization here.
 	case *appsv1beta2.ReplicaSet:
 		// Perform apps/v1beta2-specific initialization here.
 	case *exts.ReplicaSet:
@@ -53,7 +56,7 @@ func Convert_Koki_ReplicaSet_to_Kube_v1beta2_ReplicaSet(rs *types.ReplicaSetWrap
 	kubeRS.Name = kokiRS.Name
 	kubeRS.Namespace = kokiRS.Namespace
 	if len(kokiRS.Version) == 0 {
-		kubeRS.APIVersion = "extensions/v1beta1"
+		kubeRS.APIVersion = defaultAPIVersion("ReplicaSet", "extensions/v1beta1")
 	} else {
 		kubeRS.APIVersion = kokiRS.Version
 	}
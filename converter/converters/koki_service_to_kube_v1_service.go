@@ -1,6 +1,8 @@
 package converters
 
 import (
+	"strings"
+
 	"k8s.io/api/core/v1"
 
 	"github.com/koki/short/types"
@@ -29,6 +31,10 @@ func Convert_Koki_Service_To_Kube_v1_Service(service *types.ServiceWrapper) (*v1
 	if len(kokiService.ExternalName) > 0 {
 		kubeService.Spec.Type = v1.ServiceTypeExternalName
 		kubeService.Spec.ExternalName = kokiService.ExternalName
+		kubeService.Spec.Ports, err = revertPorts(kokiService)
+		if err != nil {
+			return nil, err
+		}
 		return kubeService, nil
 	}
 
@@ -38,7 +44,7 @@ func Convert_Koki_Service_To_Kube_v1_Service(service *types.ServiceWrapper) (*v1
 	}
 
 	kubeService.Spec.Selector = kokiService.Selector
-	kubeService.Spec.ClusterIP = string(kokiService.ClusterIP)
+	kubeService.Spec.ClusterIP = revertClusterIP(kokiService.ClusterIP)
 	kubeService.Spec.ExternalIPs = revertExternalIPs(kokiService.ExternalIPs)
 	kubeService.Spec.SessionAffinity, kubeService.Spec.SessionAffinityConfig = revertSessionAffinity(kokiService.ClientIPAffinity)
 	kubeService.Spec.PublishNotReadyAddresses = kokiService.PublishNotReadyAddresses
@@ -80,6 +86,15 @@ func revertServiceType(kokiType types.ClusterIPServiceType) (v1.ServiceType, err
 	}
 }
 
+// revertClusterIP accepts any capitalization of "None" for headless services.
+func revertClusterIP(kokiClusterIP types.ClusterIP) string {
+	if strings.EqualFold(string(kokiClusterIP), string(types.ClusterIPNone)) {
+		return string(types.ClusterIPNone)
+	}
+
+	return string(kokiClusterIP)
+}
+
 func revertPort(name string, kokiPort *types.ServicePort, kokiNodePort int32) (*v1.ServicePort, error) {
 	kubePort := &v1.ServicePort{}
 	kubePort.Port = kokiPort.Expose
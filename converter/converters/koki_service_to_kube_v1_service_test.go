@@ -115,6 +115,21 @@ func TestRevertService(t *testing.T) {
 	}
 }
 
+func TestRevertHeadlessService(t *testing.T) {
+	kubeService, err := Convert_Koki_Service_To_Kube_v1_Service(&types.ServiceWrapper{
+		Service: types.Service{
+			Name:      "example",
+			ClusterIP: types.ClusterIP("none"),
+		}})
+	if err != nil {
+		t.Fatal(serrors.PrettyError(err))
+	}
+
+	if kubeService.Spec.ClusterIP != "None" {
+		t.Errorf("unexpected cluster IP %s", kubeService.Spec.ClusterIP)
+	}
+}
+
 func tryService(kokiService *types.ServiceWrapper, t *testing.T) *v1.Service {
 	kubeService, err := Convert_Koki_Service_To_Kube_v1_Service(kokiService)
 	if err != nil {
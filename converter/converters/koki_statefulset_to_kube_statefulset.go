@@ -1,6 +1,7 @@
 package converters
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	"k8s.io/api/core/v1"
@@ -31,6 +32,8 @@ func Convert_Koki_StatefulSet_to_Kube_StatefulSet(statefulSet *types.StatefulSet
 	}
 
 	switch versionedStatefulSet := versionedStatefulSet.(type) {
+	case *appsv1.StatefulSet:
+		// Perform apps/v1-specific initialization here.
 	case *appsv1beta1.StatefulSet:
 		// Perform apps/v1beta1-specific initialization here.
 	case *appsv1beta2.StatefulSet:
@@ -49,7 +52,11 @@ func Convert_Koki_StatefulSet_to_Kube_apps_v1beta2_StatefulSet(statefulSet *type
 
 	kubeStatefulSet.Name = kokiStatefulSet.Name
 	kubeStatefulSet.Namespace = kokiStatefulSet.Namespace
-	kubeStatefulSet.APIVersion = kokiStatefulSet.Version
+	if len(kokiStatefulSet.Version) == 0 {
+		kubeStatefulSet.APIVersion = defaultAPIVersion("StatefulSet", "")
+	} else {
+		kubeStatefulSet.APIVersion = kokiStatefulSet.Version
+	}
 	kubeStatefulSet.Kind = "StatefulSet"
 	kubeStatefulSet.ClusterName = kokiStatefulSet.Cluster
 	kubeStatefulSet.Labels = kokiStatefulSet.Labels
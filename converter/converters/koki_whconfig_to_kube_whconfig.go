@@ -1,9 +1,10 @@
 package converters
 
 import (
+	"github.com/koki/short/types"
 	admissionregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"github.com/koki/short/types"
+	"sort"
 	"strings"
 )
 
@@ -28,7 +29,7 @@ func Convert_Koki_WebhookConfiguration_to_Kube_WebhookConfiguration(webhookConfi
 	}
 }
 
-func convertMetaKokiToKube(typeMeta *metav1.TypeMeta, objectMeta *metav1.ObjectMeta, kokiWebhookConfig types.WebhookConfig, kind string) () {
+func convertMetaKokiToKube(typeMeta *metav1.TypeMeta, objectMeta *metav1.ObjectMeta, kokiWebhookConfig types.WebhookConfig, kind string) {
 	objectMeta.Name = kokiWebhookConfig.Name
 	objectMeta.Namespace = kokiWebhookConfig.Namespace
 	if len(kokiWebhookConfig.Version) == 0 {
@@ -45,7 +46,13 @@ func convertMetaKokiToKube(typeMeta *metav1.TypeMeta, objectMeta *metav1.ObjectM
 func revertMWCs(kokiWebhooks map[string]types.Webhook) []admissionregv1beta1.Webhook {
 	var kubeWebhooks []admissionregv1beta1.Webhook
 
+	names := make([]string, 0, len(kokiWebhooks))
 	for name := range kokiWebhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
 		kubeWebhook := revertMWC(kokiWebhooks[name])
 		kubeWebhooks = append(kubeWebhooks, kubeWebhook)
 	}
@@ -83,11 +90,11 @@ func revertMWC(kokiWebhook types.Webhook) admissionregv1beta1.Webhook {
 	kubeWebhookRules := revertMWCRules(kokiWebhook.Rules)
 	kubeSelector, _, _ := revertRSSelector(kokiWebhook.Name, kokiWebhook.Selector, nil)
 
-	kubeWebhook := admissionregv1beta1.Webhook {
-		Name: kokiWebhook.Name,
-		ClientConfig: kubeWebhookClientConfig,
-		FailurePolicy: kokiWebhook.FailurePolicy,
-		Rules: kubeWebhookRules,
+	kubeWebhook := admissionregv1beta1.Webhook{
+		Name:              kokiWebhook.Name,
+		ClientConfig:      kubeWebhookClientConfig,
+		FailurePolicy:     kokiWebhook.FailurePolicy,
+		Rules:             kubeWebhookRules,
 		NamespaceSelector: kubeSelector,
 	}
 
@@ -99,15 +106,14 @@ func revertMWCRules(kokiRules []types.WebhookRuleWithOperations) []admissionregv
 
 	for i := range kokiRules {
 		kokiRule := kokiRules[i]
-		internalRule := admissionregv1beta1.Rule {
-			APIGroups:  kokiRule.Groups,
-			APIVersions:  kokiRule.Versions,
-			Resources:  kokiRule.Resources,
+		internalRule := admissionregv1beta1.Rule{
+			APIGroups:   kokiRule.Groups,
+			APIVersions: kokiRule.Versions,
+			Resources:   kokiRule.Resources,
 		}
 
-		kokiOperations := strings.Split(kokiRule.Operations[0], "|")
 		kubeOperations := []admissionregv1beta1.OperationType{}
-		for _, operation := range(kokiOperations) {
+		for _, operation := range kokiRule.Operations {
 			switch operation {
 			case "*":
 				kubeOperations = append(kubeOperations, admissionregv1beta1.OperationAll)
@@ -122,9 +128,9 @@ func revertMWCRules(kokiRules []types.WebhookRuleWithOperations) []admissionregv
 			}
 		}
 
-		kubeRule := admissionregv1beta1.RuleWithOperations {
-			Operations:  kubeOperations,
-			Rule: internalRule,
+		kubeRule := admissionregv1beta1.RuleWithOperations{
+			Operations: kubeOperations,
+			Rule:       internalRule,
 		}
 		kubeRules = append(kubeRules, kubeRule)
 	}
@@ -37,7 +37,7 @@ func Convert_Kube_CronJob_to_Koki_CronJob(kubeCronJob runtime.Object) (*types.Cr
 
 		kokiCronJob := &kokiWrapper.CronJob
 
-		kokiCronJob.Version = groupVersionString
+		kokiCronJob.Version = shortAPIVersion("CronJob", groupVersionString)
 		return kokiWrapper, nil
 	}
 
@@ -38,7 +38,7 @@ func Convert_Kube_DaemonSet_to_Koki_DaemonSet(kubeDaemonSet runtime.Object) (*ty
 
 		kokiDaemonSet := &kokiWrapper.DaemonSet
 
-		kokiDaemonSet.Version = groupVersionString
+		kokiDaemonSet.Version = shortAPIVersion("DaemonSet", groupVersionString)
 
 		// Perform version-specific initialization here.
 
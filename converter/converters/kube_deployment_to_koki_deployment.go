@@ -38,7 +38,7 @@ func Convert_Kube_Deployment_to_Koki_Deployment(kubeDeployment runtime.Object) (
 
 		kokiDeployment := &kokiWrapper.Deployment
 
-		kokiDeployment.Version = groupVersionString
+		kokiDeployment.Version = shortAPIVersion("Deployment", groupVersionString)
 
 		return kokiWrapper, nil
 	}
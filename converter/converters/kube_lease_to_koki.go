@@ -0,0 +1,64 @@
+package converters
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeLease mirrors coordination.k8s.io/v1.Lease. The vendored Kubernetes API predates coordination.k8s.io,
+// so Leases are read from and written to unstructured objects.
+type kubeLease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeLeaseSpec `json:"spec,omitempty"`
+}
+
+type kubeLeaseSpec struct {
+	HolderIdentity       *string           `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds *int32            `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          *metav1.MicroTime `json:"acquireTime,omitempty"`
+	RenewTime            *metav1.MicroTime `json:"renewTime,omitempty"`
+	LeaseTransitions     *int32            `json:"leaseTransitions,omitempty"`
+}
+
+func Convert_Kube_Lease_to_Koki(kubeObj *unstructured.Unstructured) (*types.LeaseWrapper, error) {
+	kube := &kubeLease{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(kubeObj.Object, kube)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextErrorf(err, kubeObj.Object, kube, "couldn't convert to kube Lease")
+	}
+
+	// Check for unparsed fields--potential typos.
+	extraneousPaths, err := jsonutil.ExtraneousFieldPaths(kubeObj.Object, kube)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "checking for extraneous fields in Lease")
+	}
+	if len(extraneousPaths) > 0 {
+		return nil, &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+	}
+
+	koki := &types.Lease{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.Holder = kube.Spec.HolderIdentity
+	koki.DurationSeconds = kube.Spec.LeaseDurationSeconds
+	koki.AcquireTime = kube.Spec.AcquireTime
+	koki.RenewTime = kube.Spec.RenewTime
+	koki.Transitions = kube.Spec.LeaseTransitions
+
+	return &types.LeaseWrapper{
+		Lease: *koki,
+	}, nil
+}
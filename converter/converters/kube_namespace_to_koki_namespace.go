@@ -1,6 +1,8 @@
 package converters
 
 import (
+	"strings"
+
 	"k8s.io/api/core/v1"
 
 	"github.com/koki/short/types"
@@ -15,7 +17,7 @@ func Convert_Kube_Namespace_to_Koki_Namespace(kubeNamespace *v1.Namespace) (*typ
 	kokiNamespace.Namespace = kubeNamespace.Namespace
 	kokiNamespace.Version = kubeNamespace.APIVersion
 	kokiNamespace.Cluster = kubeNamespace.ClusterName
-	kokiNamespace.Labels = kubeNamespace.Labels
+	kokiNamespace.Labels, kokiNamespace.PodSecurity = convertPodSecurityLabels(kubeNamespace.Labels)
 	kokiNamespace.Annotations = kubeNamespace.Annotations
 
 	finalizers, err := convertNamespaceSpec(kubeNamespace.Spec)
@@ -44,7 +46,11 @@ func convertNamespaceSpec(kubeSpec v1.NamespaceSpec) ([]types.FinalizerName, err
 		case v1.FinalizerKubernetes:
 			kokiFinalizer = types.FinalizerKubernetes
 		default:
-			return nil, serrors.InvalidValueErrorf(kubeFinalizer, "unrecognized finalizer")
+			// Other finalizers are domain-qualified names, e.g. "example.com/cleanup".
+			if !strings.Contains(string(kubeFinalizer), "/") {
+				return nil, serrors.InvalidValueErrorf(kubeFinalizer, "unrecognized finalizer")
+			}
+			kokiFinalizer = types.FinalizerName(kubeFinalizer)
 		}
 
 		kokiFinalizers = append(kokiFinalizers, kokiFinalizer)
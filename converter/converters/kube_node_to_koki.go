@@ -0,0 +1,129 @@
+package converters
+
+import (
+	"k8s.io/api/core/v1"
+
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+func Convert_Kube_Node_to_Koki(kube *v1.Node) (*types.NodeWrapper, error) {
+	koki := &types.Node{}
+
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.PodCIDR = kube.Spec.PodCIDR
+	koki.ProviderID = kube.Spec.ProviderID
+	koki.ExternalID = kube.Spec.DoNotUse_ExternalID
+	koki.Unschedulable = kube.Spec.Unschedulable
+	koki.Taints = convertTaints(kube.Spec.Taints)
+	if kube.Spec.ConfigSource != nil {
+		configSource, err := convertTarget(kube.Spec.ConfigSource.ConfigMapRef)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "node config source")
+		}
+		koki.ConfigSource = configSource
+	}
+
+	status, err := convertNodeStatus(kube.Status)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "node status")
+	}
+	koki.NodeStatus = *status
+
+	return &types.NodeWrapper{
+		Node: *koki,
+	}, nil
+}
+
+func convertTaints(kubeTaints []v1.Taint) []types.Taint {
+	var taints []types.Taint
+	for _, kubeTaint := range kubeTaints {
+		taints = append(taints, types.Taint{
+			Key:       kubeTaint.Key,
+			Value:     kubeTaint.Value,
+			Effect:    types.TaintEffect(kubeTaint.Effect),
+			TimeAdded: kubeTaint.TimeAdded,
+		})
+	}
+
+	return taints
+}
+
+func convertNodeStatus(kubeStatus v1.NodeStatus) (*types.NodeStatus, error) {
+	status := &types.NodeStatus{
+		Capacity:    kubeStatus.Capacity,
+		Allocatable: kubeStatus.Allocatable,
+		KubeletPort: kubeStatus.DaemonEndpoints.KubeletEndpoint.Port,
+	}
+
+	phase, err := convertNodePhase(kubeStatus.Phase)
+	if err != nil {
+		return nil, err
+	}
+	status.Phase = phase
+
+	for i, kubeCondition := range kubeStatus.Conditions {
+		conditionStatus, err := convertConditionStatus(kubeCondition.Status)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "conditions[%d]", i)
+		}
+		status.Conditions = append(status.Conditions, types.NodeCondition{
+			Type:               types.NodeConditionType(kubeCondition.Type),
+			Status:             conditionStatus,
+			LastHeartbeatTime:  kubeCondition.LastHeartbeatTime,
+			LastTransitionTime: kubeCondition.LastTransitionTime,
+			Reason:             kubeCondition.Reason,
+			Message:            kubeCondition.Message,
+		})
+	}
+
+	for _, kubeAddress := range kubeStatus.Addresses {
+		status.Addresses = append(status.Addresses, types.NodeAddress{
+			Type:    string(kubeAddress.Type),
+			Address: kubeAddress.Address,
+		})
+	}
+
+	if kubeStatus.NodeInfo != (v1.NodeSystemInfo{}) {
+		info := types.NodeSystemInfo(kubeStatus.NodeInfo)
+		status.Info = &info
+	}
+
+	for _, kubeImage := range kubeStatus.Images {
+		status.Images = append(status.Images, types.ContainerImage(kubeImage))
+	}
+
+	for _, volume := range kubeStatus.VolumesInUse {
+		status.VolumesInUse = append(status.VolumesInUse, string(volume))
+	}
+
+	for _, kubeVolume := range kubeStatus.VolumesAttached {
+		status.VolumesAttached = append(status.VolumesAttached, types.AttachedVolume{
+			Name:       string(kubeVolume.Name),
+			DevicePath: kubeVolume.DevicePath,
+		})
+	}
+
+	return status, nil
+}
+
+func convertNodePhase(phase v1.NodePhase) (types.NodePhase, error) {
+	switch phase {
+	case "":
+		return "", nil
+	case v1.NodePending:
+		return types.NodePending, nil
+	case v1.NodeRunning:
+		return types.NodeRunning, nil
+	case v1.NodeTerminated:
+		return types.NodeTerminated, nil
+	}
+
+	return "", serrors.InvalidValueErrorf(phase, "unrecognized node phase")
+}
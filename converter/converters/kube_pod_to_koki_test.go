@@ -83,6 +83,23 @@ var nodeAffinity5 = &v1.PreferredSchedulingTerm{
 	},
 }
 
+var nodeAffinity6 = &v1.NodeSelectorTerm{
+	MatchExpressions: []v1.NodeSelectorRequirement{
+		v1.NodeSelectorRequirement{
+			Key:      "zone",
+			Operator: "In",
+			Values:   []string{"a"},
+		},
+	},
+	MatchFields: []v1.NodeSelectorRequirement{
+		v1.NodeSelectorRequirement{
+			Key:      "metadata.name",
+			Operator: "NotIn",
+			Values:   []string{"node-1", "node-2"},
+		},
+	},
+}
+
 var podAffinity0 = &v1.PodAffinityTerm{
 	LabelSelector: &metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
@@ -160,6 +177,7 @@ func TestAffinityConversion(t *testing.T) {
 	doTestHardNodeAffinity(nodeAffinity3, t)
 	doTestSoftNodeAffinity(nodeAffinity4, t)
 	doTestSoftNodeAffinity(nodeAffinity5, t)
+	doTestHardNodeAffinity(nodeAffinity6, t)
 
 	doTestHardPodAffinity(podAffinity0, t)
 	doTestHardPodAffinity(podAffinity1, t)
@@ -5,11 +5,45 @@ import (
 
 	"k8s.io/api/core/v1"
 	exts "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/koki/short/parser"
 	"github.com/koki/short/types"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
 )
 
-func Convert_Kube_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubePodSecurityPolicy *exts.PodSecurityPolicy) (*types.PodSecurityPolicyWrapper, error) {
+func Convert_Kube_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubePodSecurityPolicy runtime.Object) (*types.PodSecurityPolicyWrapper, error) {
+	groupVersionKind := kubePodSecurityPolicy.GetObjectKind().GroupVersionKind()
+	groupVersionString := groupVersionKind.GroupVersion().String()
+	if _, ok := kubePodSecurityPolicy.(*exts.PodSecurityPolicy); !ok {
+		// policy/v1beta1 has the same fields, so convert it as extensions/v1beta1.
+		extsGroupVersionKind := groupVersionKind
+		extsGroupVersionKind.Group = "extensions"
+		extsGroupVersionKind.Version = "v1beta1"
+		kubePodSecurityPolicy.GetObjectKind().SetGroupVersionKind(extsGroupVersionKind)
+		b, err := yaml.Marshal(kubePodSecurityPolicy)
+		kubePodSecurityPolicy.GetObjectKind().SetGroupVersionKind(groupVersionKind)
+		if err != nil {
+			return nil, serrors.InvalidInstanceContextErrorf(err, kubePodSecurityPolicy, "couldn't serialize kube PodSecurityPolicy after setting apiVersion to extensions/v1beta1")
+		}
+
+		kubePodSecurityPolicy, err = parser.ParseSingleKubeNativeFromBytes(b)
+		if err != nil {
+			return nil, serrors.InvalidInstanceContextErrorf(err, string(b), "couldn't deserialize 'generic' kube PodSecurityPolicy")
+		}
+	}
+
+	kokiWrapper, err := Convert_Kube_exts_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubePodSecurityPolicy.(*exts.PodSecurityPolicy))
+	if err != nil {
+		return nil, err
+	}
+	kokiWrapper.PodSecurityPolicy.Version = groupVersionString
+
+	return kokiWrapper, nil
+}
+
+func Convert_Kube_exts_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubePodSecurityPolicy *exts.PodSecurityPolicy) (*types.PodSecurityPolicyWrapper, error) {
 	kokiWrapper := &types.PodSecurityPolicyWrapper{}
 	kokiPodSecurityPolicy := &kokiWrapper.PodSecurityPolicy
 
@@ -271,6 +305,12 @@ func convertPodSecurityPolicyVolumePlugins(kubeVolPlugins []exts.FSType) ([]stri
 	return kokiVolPlugins, nil
 }
 
+// Volume plugins that PodSecurityPolicies allow, but that this version of the API doesn't name.
+const (
+	podSecurityPolicyProjected exts.FSType = "projected"
+	podSecurityPolicyCSI       exts.FSType = "csi"
+)
+
 func convertPodSecurityPolicyVolumePlugin(kubeVolPlugin exts.FSType) (string, error) {
 	if len(kubeVolPlugin) == 0 {
 		return "", nil
@@ -319,6 +359,10 @@ func convertPodSecurityPolicyVolumePlugin(kubeVolPlugin exts.FSType) (string, er
 		return types.VolumeTypeQuobyte, nil
 	case exts.AzureDisk:
 		return types.VolumeTypeAzureDisk, nil
+	case podSecurityPolicyProjected:
+		return types.VolumeTypeProjected, nil
+	case podSecurityPolicyCSI:
+		return types.VolumeTypeCSI, nil
 	case exts.All:
 		return types.VolumeTypeAny, nil
 	}
@@ -82,6 +82,8 @@ func convertAccessMode(accessMode v1.PersistentVolumeAccessMode) (types.Persiste
 		return types.ReadOnlyMany, nil
 	case v1.ReadWriteMany:
 		return types.ReadWriteMany, nil
+	case types.KubeReadWriteOncePod:
+		return types.ReadWriteOncePod, nil
 	default:
 		return "", serrors.InvalidValueErrorf(accessMode, "unrecognized access mode")
 	}
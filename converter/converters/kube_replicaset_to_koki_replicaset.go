@@ -41,7 +41,7 @@ func Convert_Kube_ReplicaSet_to_Koki_ReplicaSet(kubeRS runtime.Object) (*types.R
 
 		kokiRS := &kokiWrapper.ReplicaSet
 
-		kokiRS.Version = groupVersionString
+		kokiRS.Version = shortAPIVersion("ReplicaSet", groupVersionString)
 
 		// Perform version-specific initialization here.
 
@@ -38,7 +38,7 @@ func Convert_Kube_StatefulSet_to_Koki_StatefulSet(kubeStatefulSet runtime.Object
 
 		kokiStatefulSet := &kokiWrapper.StatefulSet
 
-		kokiStatefulSet.Version = groupVersionString
+		kokiStatefulSet.Version = shortAPIVersion("StatefulSet", groupVersionString)
 
 		return kokiWrapper, nil
 	}
@@ -66,14 +66,12 @@ func convertEndpointPorts(ports []v1.EndpointPort) ([]string, error) {
 	for i := range ports {
 		port := ports[i]
 
-		protocol := convertProtocol(port.Protocol)
-
-		if protocol == "" {
-			protocol = "tcp"
+		// TCP is the default, so only other protocols are written out.
+		kokiPort := fmt.Sprintf("%d", port.Port)
+		if protocol := convertProtocol(port.Protocol); len(protocol) > 0 && protocol != types.ProtocolTCP {
+			kokiPort = fmt.Sprintf("%s://%s", protocol, kokiPort)
 		}
 
-		kokiPort := fmt.Sprintf("%s://%d", protocol, port.Port)
-
 		if port.Name != "" {
 			kokiPort = fmt.Sprintf("%s:%s", kokiPort, port.Name)
 		}
@@ -29,6 +29,7 @@ func Convert_Kube_v1_Pod_to_Koki_Pod(pod *v1.Pod) (*types.PodWrapper, error) {
 	if err != nil {
 		return nil, err
 	}
+	convertSecurityAnnotations(&kokiPod.PodTemplateMeta, template)
 	kokiPod.PodTemplate = *template
 
 	kokiPod.Msg = pod.Status.Message
@@ -54,7 +55,7 @@ func Convert_Kube_v1_Pod_to_Koki_Pod(pod *v1.Pod) (*types.PodWrapper, error) {
 	}
 	kokiPod.Conditions = conditions
 
-	err = convertContainerStatuses(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, kokiPod.Containers)
+	err = convertContainerStatuses(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, &kokiPod.PodTemplate)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +147,7 @@ func convertPodSpec(kubeSpec v1.PodSpec) (*types.PodTemplate, error) {
 		securityContext := kubeSpec.SecurityContext
 		kokiPod.GIDs = securityContext.SupplementalGroups
 		kokiPod.FSGID = securityContext.FSGroup
-		for i := range kokiPod.Containers {
-			container := &kokiPod.Containers[i]
+		for _, container := range podContainers(kokiPod) {
 			if container.SELinux == nil {
 				container.SELinux = convertSELinux(securityContext.SELinuxOptions)
 			}
@@ -166,6 +166,67 @@ func convertPodSpec(kubeSpec v1.PodSpec) (*types.PodTemplate, error) {
 	return kokiPod, nil
 }
 
+// podContainers returns pointers to the init containers and containers of the pod.
+func podContainers(kokiPod *types.PodTemplate) []*types.Container {
+	containers := []*types.Container{}
+	for i := range kokiPod.InitContainers {
+		containers = append(containers, &kokiPod.InitContainers[i])
+	}
+	for i := range kokiPod.Containers {
+		containers = append(containers, &kokiPod.Containers[i])
+	}
+
+	return containers
+}
+
+const AppArmorContainerAnnotationKeyPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// convertSecurityAnnotations moves the seccomp, AppArmor and sysctl annotations into pod and container fields.
+// Annotations for containers that don't exist in the pod are left alone.
+func convertSecurityAnnotations(meta *types.PodTemplateMeta, kokiPod *types.PodTemplate) {
+	if meta == nil || len(meta.Annotations) == 0 {
+		return
+	}
+
+	containers := map[string]*types.Container{}
+	for _, container := range podContainers(kokiPod) {
+		containers[container.Name] = container
+	}
+
+	annotations := map[string]string{}
+	for key, val := range meta.Annotations {
+		switch {
+		case key == v1.SeccompPodAnnotationKey:
+			kokiPod.Seccomp = val
+		case key == v1.SysctlsPodAnnotationKey:
+			kokiPod.Sysctls = convertSysctls(val)
+		case key == v1.UnsafeSysctlsPodAnnotationKey:
+			kokiPod.UnsafeSysctls = convertSysctls(val)
+		case strings.HasPrefix(key, v1.SeccompContainerAnnotationKeyPrefix) &&
+			containers[strings.TrimPrefix(key, v1.SeccompContainerAnnotationKeyPrefix)] != nil:
+			containers[strings.TrimPrefix(key, v1.SeccompContainerAnnotationKeyPrefix)].Seccomp = val
+		case strings.HasPrefix(key, AppArmorContainerAnnotationKeyPrefix) &&
+			containers[strings.TrimPrefix(key, AppArmorContainerAnnotationKeyPrefix)] != nil:
+			containers[strings.TrimPrefix(key, AppArmorContainerAnnotationKeyPrefix)].AppArmor = val
+		default:
+			annotations[key] = val
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	meta.Annotations = annotations
+}
+
+func convertSysctls(kubeSysctls string) []string {
+	if len(kubeSysctls) == 0 {
+		return nil
+	}
+
+	return strings.Split(kubeSysctls, ",")
+}
+
 func convertVolumes(kubeVolumes []v1.Volume) (map[string]types.Volume, error) {
 	kokiVolumes := map[string]types.Volume{}
 	for _, kubeVolume := range kubeVolumes {
@@ -495,14 +556,9 @@ func convertFibreChannelVolume(source *v1.FCVolumeSource) *types.FibreChannelVol
 }
 
 func convertFlockerVolume(source *v1.FlockerVolumeSource) *types.FlockerVolume {
-	var dataset string
-	if len(source.DatasetUUID) > 0 {
-		dataset = source.DatasetUUID
-	} else {
-		dataset = source.DatasetName
-	}
 	return &types.FlockerVolume{
-		DatasetUUID: dataset,
+		DatasetUUID: source.DatasetUUID,
+		DatasetName: source.DatasetName,
 	}
 }
 
@@ -835,11 +891,12 @@ func convertContainer(container *v1.Container) (*types.Container, error) {
 	if err != nil {
 		return nil, err
 	}
-	kokiContainer.OnStart = onStart
-	kokiContainer.PreStop = preStop
+	kokiContainer.OnStart = (*types.Hook)(onStart)
+	kokiContainer.PreStop = (*types.Hook)(preStop)
 
 	kokiContainer.CPU = convertCPU(container.Resources)
 	kokiContainer.Mem = convertMem(container.Resources)
+	kokiContainer.Resources = convertOtherResources(container.Resources)
 
 	if container.SecurityContext != nil {
 		kokiContainer.Privileged = container.SecurityContext.Privileged
@@ -1069,6 +1126,33 @@ func convertMem(resources v1.ResourceRequirements) *types.Mem {
 	return nil
 }
 
+// convertOtherResources converts the resources other than cpu and memory, e.g. "nvidia.com/gpu".
+func convertOtherResources(resources v1.ResourceRequirements) map[string]types.ResourceBounds {
+	others := map[string]types.ResourceBounds{}
+	for name, q := range resources.Limits {
+		if name == v1.ResourceCPU || name == v1.ResourceMemory {
+			continue
+		}
+		bounds := others[string(name)]
+		bounds.Max = q.String()
+		others[string(name)] = bounds
+	}
+	for name, q := range resources.Requests {
+		if name == v1.ResourceCPU || name == v1.ResourceMemory {
+			continue
+		}
+		bounds := others[string(name)]
+		bounds.Min = q.String()
+		others[string(name)] = bounds
+	}
+
+	if len(others) == 0 {
+		return nil
+	}
+
+	return others
+}
+
 func convertSELinux(opts *v1.SELinuxOptions) *types.SELinux {
 	if opts == nil {
 		return nil
@@ -1190,9 +1274,12 @@ func convertEnvVars(env []v1.EnvVar, envFromSrc []v1.EnvFromSource) []types.Env
 		if v.ValueFrom.FieldRef != nil {
 			e.From = v.ValueFrom.FieldRef.FieldPath
 		}
-		if v.ValueFrom.ResourceFieldRef != nil {
-			//This might be losing some information
-			e.From = v.ValueFrom.ResourceFieldRef.Resource
+		if ref := v.ValueFrom.ResourceFieldRef; ref != nil {
+			divisor := ""
+			if !ref.Divisor.IsZero() {
+				divisor = ref.Divisor.String()
+			}
+			e.From = types.UnparseResourceFieldRef(ref.ContainerName, ref.Resource, divisor)
 		}
 		if v.ValueFrom.ConfigMapKeyRef != nil {
 			e.From = fmt.Sprintf("config:%s:%s", v.ValueFrom.ConfigMapKeyRef.Name, v.ValueFrom.ConfigMapKeyRef.Key)
@@ -1239,26 +1326,7 @@ func convertVolumeMounts(mounts []v1.VolumeMount) ([]types.VolumeMount, error) {
 			}
 			km.Propagation = &propagation
 		}
-		access := "rw"
-		if mount.ReadOnly {
-			access = "ro"
-		}
-		trailer := ""
-		if mount.SubPath == "" {
-			if access == "ro" {
-				trailer = fmt.Sprintf(access)
-			}
-		} else {
-			trailer = fmt.Sprintf("%s", mount.SubPath)
-			if access == "ro" {
-				trailer = fmt.Sprintf("%s:%s", trailer, access)
-			}
-		}
-		if trailer != "" {
-			km.Store = fmt.Sprintf("%s:%s", mount.Name, trailer)
-		} else {
-			km.Store = mount.Name
-		}
+		km.Store = types.JoinMountStore(mount.Name, mount.SubPath, mount.ReadOnly)
 		kokiMounts = append(kokiMounts, km)
 	}
 	return kokiMounts, nil
@@ -1325,65 +1393,30 @@ func convertNodeAffinity(nodeAffinity *v1.NodeAffinity) ([]types.Affinity, error
 	var affinity []types.Affinity
 	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
 		nodeHardAffinity := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
-		for i := range nodeHardAffinity.NodeSelectorTerms {
-			selectorTerm := nodeHardAffinity.NodeSelectorTerms[i]
-			affinityExprs := []string{}
-			for i := range selectorTerm.MatchExpressions {
-
-				expr := selectorTerm.MatchExpressions[i]
-				value := strings.Join(expr.Values, ",")
-				op, err := convertOperator(expr.Operator)
-				if err != nil {
-					return nil, serrors.InvalidInstanceContextErrorf(err, nodeHardAffinity, "unsupported Operator")
-				}
-				kokiExpr := fmt.Sprintf("%s%s%s", expr.Key, op, value)
-				if expr.Operator == v1.NodeSelectorOpExists {
-					kokiExpr = fmt.Sprintf("%s", expr.Key)
-				}
-				if expr.Operator == v1.NodeSelectorOpDoesNotExist {
-					kokiExpr = fmt.Sprintf("!%s", expr.Key)
-				}
-				affinityExprs = append(affinityExprs, kokiExpr)
+		for _, selectorTerm := range nodeHardAffinity.NodeSelectorTerms {
+			affinityString, err := expressions.UnparseNodeSelectorTerm(selectorTerm)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "node affinity")
 			}
-			if len(affinityExprs) > 0 {
-				affinityString := strings.Join(affinityExprs, "&")
+			if len(affinityString) > 0 {
 				affinity = append(affinity, types.Affinity{NodeAffinity: affinityString})
 			}
 		}
 	}
 
 	// Node soft affinities
-	if nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution != nil {
-		nodeSoftAffinity := nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
-		for i := range nodeSoftAffinity {
-			selectorTerm := nodeSoftAffinity[i]
-			affinityExprs := []string{}
-			weight := selectorTerm.Weight
-			for i := range selectorTerm.Preference.MatchExpressions {
-				expr := selectorTerm.Preference.MatchExpressions[i]
-				value := strings.Join(expr.Values, ",")
-				op, err := convertOperator(expr.Operator)
-				if err != nil {
-					return nil, serrors.InvalidInstanceContextErrorf(err, nodeSoftAffinity, "unsupported Operator")
-				}
-				kokiExpr := fmt.Sprintf("%s%s%s", expr.Key, op, value)
-				if expr.Operator == v1.NodeSelectorOpExists {
-					kokiExpr = fmt.Sprintf("%s", expr.Key)
-				}
-				if expr.Operator == v1.NodeSelectorOpDoesNotExist {
-					kokiExpr = fmt.Sprintf("!%s", expr.Key)
-				}
-				affinityExprs = append(affinityExprs, kokiExpr)
-			}
-			if len(affinityExprs) > 0 {
-				affinityString := strings.Join(affinityExprs, "&")
-				affinityString = fmt.Sprintf("%s:soft", affinityString)
-				// The default value for Weight is 1. 0 means "unspecified".
-				if weight != 0 {
-					affinityString = fmt.Sprintf("%s:%d", affinityString, weight)
-				}
-				affinity = append(affinity, types.Affinity{NodeAffinity: affinityString})
+	for _, selectorTerm := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		affinityString, err := expressions.UnparseNodeSelectorTerm(selectorTerm.Preference)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "node affinity")
+		}
+		if len(affinityString) > 0 {
+			affinityString = fmt.Sprintf("%s:soft", affinityString)
+			// The default value for Weight is 1. 0 means "unspecified".
+			if selectorTerm.Weight != 0 {
+				affinityString = fmt.Sprintf("%s:%d", affinityString, selectorTerm.Weight)
 			}
+			affinity = append(affinity, types.Affinity{NodeAffinity: affinityString})
 		}
 	}
 	return affinity, nil
@@ -1532,31 +1565,6 @@ func convertMatchLabelsToExprs(matchLabels map[string]string) []string {
 	return exprs
 }
 
-func convertOperator(op v1.NodeSelectorOperator) (string, error) {
-	if op == "" {
-		return "", nil
-	}
-	if op == v1.NodeSelectorOpIn {
-		return "=", nil
-	}
-	if op == v1.NodeSelectorOpNotIn {
-		return "!=", nil
-	}
-	if op == v1.NodeSelectorOpExists {
-		return "", nil
-	}
-	if op == v1.NodeSelectorOpDoesNotExist {
-		return "", nil
-	}
-	if op == v1.NodeSelectorOpGt {
-		return ">", nil
-	}
-	if op == v1.NodeSelectorOpLt {
-		return "<", nil
-	}
-	return "", serrors.InvalidInstanceError(op)
-}
-
 func convertDNSPolicy(dnsPolicy v1.DNSPolicy) (types.DNSPolicy, error) {
 	if dnsPolicy == "" {
 		return "", nil
@@ -1645,7 +1653,10 @@ func convertTolerations(tolerations []v1.Toleration) ([]types.Toleration, error)
 		toleration := tolerations[i]
 		tol := types.Toleration{}
 		tol.ExpiryAfter = toleration.TolerationSeconds
-		tolExpr := ""
+		expr := expressions.TaintExpr{
+			Key:    toleration.Key,
+			Effect: string(toleration.Effect),
+		}
 		// Toleration operator defaults to "Equal".
 		if toleration.Operator == v1.TolerationOpEqual || len(toleration.Operator) == 0 {
 			if len(toleration.Key) == 0 {
@@ -1653,24 +1664,17 @@ func convertTolerations(tolerations []v1.Toleration) ([]types.Toleration, error)
 					serrors.InvalidInstanceErrorf(toleration, "key can only be empty for Exists operator"),
 					"tolerations[%d]", i)
 			}
-			tolExpr = fmt.Sprintf("%s=%s", toleration.Key, toleration.Value)
+			value := toleration.Value
+			expr.Value = &value
 		} else if toleration.Operator == v1.TolerationOpExists {
 			if len(toleration.Key) == 0 {
-				tolExpr = "*"
-			} else {
-				tolExpr = toleration.Key
+				expr.Key = "*"
 			}
 		} else {
 			return nil, serrors.InvalidInstanceErrorf(toleration, "unsupported operator")
 		}
-		if tolExpr != "" {
-			if toleration.Effect != "" {
-				tol.Selector = types.Selector(fmt.Sprintf("%s:%s", tolExpr, toleration.Effect))
-			} else {
-				tol.Selector = types.Selector(tolExpr)
-			}
-			tols = append(tols, tol)
-		}
+		tol.Selector = types.Selector(expr.String())
+		tols = append(tols, tol)
 	}
 	return tols, nil
 }
@@ -1782,13 +1786,16 @@ func convertConditionStatus(status v1.ConditionStatus) (types.ConditionStatus, e
 	return "", serrors.InvalidInstanceError(status)
 }
 
-func convertContainerStatuses(initContainerStatuses, containerStatuses []v1.ContainerStatus, kokiContainers []types.Container) error {
-	allContainerStatuses := append(initContainerStatuses, containerStatuses...)
+func convertContainerStatuses(initContainerStatuses, containerStatuses []v1.ContainerStatus, kokiPod *types.PodTemplate) error {
+	applyContainerStatuses(initContainerStatuses, kokiPod.InitContainers)
+	applyContainerStatuses(containerStatuses, kokiPod.Containers)
+	return nil
+}
 
-	for i := range allContainerStatuses {
-		status := allContainerStatuses[i]
+func applyContainerStatuses(statuses []v1.ContainerStatus, kokiContainers []types.Container) {
+	for _, status := range statuses {
 		for i := range kokiContainers {
-			container := kokiContainers[i]
+			container := &kokiContainers[i]
 			if container.Name == status.Name {
 				container.Restarts = status.RestartCount
 				container.Ready = status.Ready
@@ -1799,10 +1806,13 @@ func convertContainerStatuses(initContainerStatuses, containerStatuses []v1.Cont
 			}
 		}
 	}
-	return nil
 }
 
 func convertContainerState(state v1.ContainerState) *types.ContainerState {
+	if state.Waiting == nil && state.Running == nil && state.Terminated == nil {
+		return nil
+	}
+
 	s := &types.ContainerState{}
 	if state.Waiting != nil {
 		s.Waiting = &types.ContainerStateWaiting{
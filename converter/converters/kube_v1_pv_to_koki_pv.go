@@ -7,11 +7,19 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/koki/short/parser/expressions"
 	"github.com/koki/short/types"
 	"github.com/koki/short/util"
 	serrors "github.com/koki/structurederrors"
 )
 
+// The label that Kubernetes writes the zones of a persistent volume to. A regional GCE disk
+// has two zones, separated by multiZoneDelimiter.
+const (
+	zoneLabel          = "failure-domain.beta.kubernetes.io/zone"
+	multiZoneDelimiter = "__"
+)
+
 func Convert_Kube_v1_PersistentVolume_to_Koki_PersistentVolume(kubePV *v1.PersistentVolume) (*types.PersistentVolumeWrapper, error) {
 	var err error
 	kokiPV := &types.PersistentVolume{}
@@ -33,16 +41,25 @@ func Convert_Kube_v1_PersistentVolume_to_Koki_PersistentVolume(kubePV *v1.Persis
 	if err != nil {
 		return nil, err
 	}
+	kokiPV.Labels = convertRegionalPDZones(kokiPV.GcePD, kokiPV.Labels)
 	if len(kubeSpec.AccessModes) > 0 {
 		kokiPV.AccessModes = &types.AccessModes{
 			Modes: kubeSpec.AccessModes,
 		}
 	}
-	kokiPV.Claim = kubeSpec.ClaimRef
+	kokiPV.Claim = (*types.ClaimReference)(kubeSpec.ClaimRef)
 	kokiPV.ReclaimPolicy = convertReclaimPolicy(kubeSpec.PersistentVolumeReclaimPolicy)
 	kokiPV.StorageClass = kubeSpec.StorageClassName
 	if len(kubeSpec.MountOptions) > 0 {
-		kokiPV.MountOptions = strings.Join(kubeSpec.MountOptions, ",")
+		kokiPV.MountOptions = &types.MountOptions{Options: kubeSpec.MountOptions}
+	}
+	kokiPV.VolumeMode, err = convertPersistentVolumeMode(kubeSpec.VolumeMode)
+	if err != nil {
+		return nil, err
+	}
+	kokiPV.NodeAffinity, err = convertVolumeNodeAffinity(kubeSpec.NodeAffinity)
+	if err != nil {
+		return nil, err
 	}
 
 	kokiPV.PersistentVolumeStatus, err = convertPersistentVolumeStatus(kubePV.Status)
@@ -337,6 +354,41 @@ func convertSecretRef(kubeRef *v1.SecretReference) string {
 	return fmt.Sprintf("%s/%s", kubeRef.Namespace, kubeRef.Name)
 }
 
+func convertPersistentVolumeMode(kubeMode *v1.PersistentVolumeMode) (*types.PersistentVolumeMode, error) {
+	if kubeMode == nil {
+		return nil, nil
+	}
+
+	var kokiMode types.PersistentVolumeMode
+	switch *kubeMode {
+	case v1.PersistentVolumeBlock:
+		kokiMode = types.PersistentVolumeBlock
+	case v1.PersistentVolumeFilesystem:
+		kokiMode = types.PersistentVolumeFilesystem
+	default:
+		return nil, serrors.InvalidValueErrorf(*kubeMode, "unrecognized volume mode")
+	}
+
+	return &kokiMode, nil
+}
+
+func convertVolumeNodeAffinity(kubeAffinity *v1.VolumeNodeAffinity) ([]string, error) {
+	if kubeAffinity == nil || kubeAffinity.Required == nil {
+		return nil, nil
+	}
+
+	kokiAffinity := make([]string, len(kubeAffinity.Required.NodeSelectorTerms))
+	for i, term := range kubeAffinity.Required.NodeSelectorTerms {
+		kokiTerm, err := expressions.UnparseNodeSelectorTerm(term)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "nodeAffinity.required.nodeSelectorTerms[%d]", i)
+		}
+		kokiAffinity[i] = kokiTerm
+	}
+
+	return kokiAffinity, nil
+}
+
 func convertReclaimPolicy(kubePolicy v1.PersistentVolumeReclaimPolicy) types.PersistentVolumeReclaimPolicy {
 	return types.PersistentVolumeReclaimPolicy(strings.ToLower(string(kubePolicy)))
 }
@@ -354,3 +406,25 @@ func convertCapacity(kubeCapacity v1.ResourceList) (*resource.Quantity, error) {
 
 	return nil, serrors.InvalidInstanceErrorf(kubeCapacity, "only supports Storage resource")
 }
+
+// convertRegionalPDZones moves the replica zones of a regional GCE disk from the zone label to the disk.
+// The label of a disk in a single zone is left alone.
+func convertRegionalPDZones(kokiPD *types.GcePDVolume, kubeLabels map[string]string) map[string]string {
+	zones := kubeLabels[zoneLabel]
+	if kokiPD == nil || !strings.Contains(zones, multiZoneDelimiter) {
+		return kubeLabels
+	}
+
+	kokiPD.Zones = strings.Split(zones, multiZoneDelimiter)
+	labels := map[string]string{}
+	for key, val := range kubeLabels {
+		if key != zoneLabel {
+			labels[key] = val
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return labels
+}
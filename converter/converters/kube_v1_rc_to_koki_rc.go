@@ -110,6 +110,7 @@ func convertTemplate(kubeTemplate v1.PodTemplateSpec) (*types.PodTemplateMeta, t
 	if err != nil {
 		return nil, types.PodTemplate{}, err
 	}
+	convertSecurityAnnotations(meta, spec)
 
 	return meta, *spec, nil
 }
@@ -26,6 +26,9 @@ func Convert_Kube_v1_Service_to_Koki_Service(kubeService *v1.Service) (*types.Se
 
 	if kubeService.Spec.Type == v1.ServiceTypeExternalName {
 		kokiService.ExternalName = kubeService.Spec.ExternalName
+		if len(kubeService.Spec.Ports) > 0 {
+			kokiService.Port, _, kokiService.Ports = convertPorts(kubeService.Spec.Ports)
+		}
 		return kokiWrapper, nil
 	}
 
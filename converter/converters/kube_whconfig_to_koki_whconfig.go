@@ -1,10 +1,10 @@
 package converters
 
 import (
-	admissionregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
-	"strings"
 	"github.com/koki/short/types"
+	admissionregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
 )
 
 func Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(webhookConfig interface{}, kind string) (interface{}, error) {
@@ -37,7 +37,7 @@ func Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(webhookConfi
 	}
 }
 
-func convertMeta(kokiConfig *types.WebhookConfig, typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) (){
+func convertMeta(kokiConfig *types.WebhookConfig, typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) {
 	kokiConfig.Name = objectMeta.Name
 	kokiConfig.Namespace = objectMeta.Namespace
 	kokiConfig.Version = typeMeta.APIVersion
@@ -73,7 +73,7 @@ func convertWebhook(webhook admissionregv1beta1.Webhook) (name string, kokiWebho
 			var updateOp bool = false
 			var deleteOp bool = false
 			var connectOp bool = false
-			for _, operation := range(rule.Operations) {
+			for _, operation := range rule.Operations {
 				kokiOperationsArr = append(kokiOperationsArr, string(operation))
 				if operation == admissionregv1beta1.Create {
 					createOp = true
@@ -93,14 +93,14 @@ func convertWebhook(webhook admissionregv1beta1.Webhook) (name string, kokiWebho
 			if createOp && updateOp && deleteOp && connectOp {
 				kokiOperations = append(kokiOperations, "*")
 			} else {
-				kokiOperations = append(kokiOperations, strings.Join(kokiOperationsArr,"|"))
+				kokiOperations = kokiOperationsArr
 			}
 
 			kokiRule := types.WebhookRuleWithOperations{
-				Groups:    rule.APIGroups,
-				Versions:  rule.APIVersions,
+				Groups:     rule.APIGroups,
+				Versions:   rule.APIVersions,
 				Operations: kokiOperations,
-				Resources: rule.Resources,
+				Resources:  rule.Resources,
 			}
 			rules = append(rules, kokiRule)
 		}
@@ -112,11 +112,11 @@ func convertWebhook(webhook admissionregv1beta1.Webhook) (name string, kokiWebho
 		serviceReference := *kokiWebhookConfig.Service
 		service := serviceReference.Name
 		serviceNS := serviceReference.Namespace
-		servicePath := *serviceReference.Path
 		s1 := []string{serviceNS, service}
-		s1Str := strings.Join(s1, "/")
-		s2 := []string{s1Str, servicePath}
-		kokiService = strings.Join(s2, ":")
+		kokiService = strings.Join(s1, "/")
+		if serviceReference.Path != nil {
+			kokiService = strings.Join([]string{kokiService, *serviceReference.Path}, ":")
+		}
 	}
 	//get selector
 	selector, _, err := convertRSLabelSelector(webhook.NamespaceSelector, nil)
@@ -126,16 +126,14 @@ func convertWebhook(webhook admissionregv1beta1.Webhook) (name string, kokiWebho
 		kokiURL = *kokiWebhookConfig.URL
 	}
 	//align the structure using above variables
- 	kokiWebhook = types.Webhook {
-		Name: name,
-		Client: kokiURL,
-		CaBundle: kokiWebhookConfig.CABundle,
-		Service: kokiService,
+	kokiWebhook = types.Webhook{
+		Name:          name,
+		Client:        kokiURL,
+		CaBundle:      kokiWebhookConfig.CABundle,
+		Service:       kokiService,
 		FailurePolicy: webhook.FailurePolicy,
-		Selector: selector,
-		Rules: rules,
+		Selector:      selector,
+		Rules:         rules,
 	}
 	return name, kokiWebhook, err
 }
-
-
@@ -0,0 +1,98 @@
+package converters
+
+import (
+	"strings"
+
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Pod Security admission reads a Namespace's labels, e.g.
+
+	pod-security.kubernetes.io/enforce: baseline
+	pod-security.kubernetes.io/enforce-version: v1.25
+
+Short syntax holds them in "pod_security" instead, as "level" or "level:version" for each mode.
+A version label without a level label stays a label, since short syntax can't express it.
+
+*/
+
+// PodSecurityLabelPrefix starts the names of the labels that Pod Security admission reads.
+const PodSecurityLabelPrefix = "pod-security.kubernetes.io/"
+
+// podSecurityModes returns the field of kokiPodSecurity for each admission mode.
+func podSecurityModes(kokiPodSecurity *types.PodSecurity) map[string]*string {
+	return map[string]*string{
+		"enforce": &kokiPodSecurity.Enforce,
+		"audit":   &kokiPodSecurity.Audit,
+		"warn":    &kokiPodSecurity.Warn,
+	}
+}
+
+// convertPodSecurityLabels moves the Pod Security labels out of kubeLabels, which isn't modified.
+func convertPodSecurityLabels(kubeLabels map[string]string) (map[string]string, *types.PodSecurity) {
+	kokiPodSecurity := &types.PodSecurity{}
+	labels := map[string]string{}
+	for key, val := range kubeLabels {
+		labels[key] = val
+	}
+
+	found := false
+	for mode, field := range podSecurityModes(kokiPodSecurity) {
+		levelKey := PodSecurityLabelPrefix + mode
+		versionKey := levelKey + "-version"
+		level, ok := labels[levelKey]
+		if !ok {
+			continue
+		}
+
+		*field = level
+		if version, ok := labels[versionKey]; ok {
+			*field = level + ":" + version
+			delete(labels, versionKey)
+		}
+		delete(labels, levelKey)
+		found = true
+	}
+
+	if !found {
+		return kubeLabels, nil
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	return labels, kokiPodSecurity
+}
+
+// revertPodSecurityLabels adds the Pod Security labels to kokiLabels, which isn't modified.
+func revertPodSecurityLabels(kokiLabels map[string]string, kokiPodSecurity *types.PodSecurity) (map[string]string, error) {
+	if kokiPodSecurity == nil {
+		return kokiLabels, nil
+	}
+
+	labels := map[string]string{}
+	for key, val := range kokiLabels {
+		labels[key] = val
+	}
+	for mode, field := range podSecurityModes(kokiPodSecurity) {
+		if len(*field) == 0 {
+			continue
+		}
+
+		segments := strings.SplitN(*field, ":", 2)
+		switch segments[0] {
+		case types.PodSecurityPrivileged, types.PodSecurityBaseline, types.PodSecurityRestricted:
+		default:
+			return nil, serrors.InvalidValueErrorf(*field, "pod_security.%s: expected privileged, baseline or restricted, optionally followed by :version", mode)
+		}
+		labels[PodSecurityLabelPrefix+mode] = segments[0]
+		if len(segments) == 2 {
+			labels[PodSecurityLabelPrefix+mode+"-version"] = segments[1]
+		}
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,33 @@
+package converters
+
+import (
+	"reflect"
+
+	"k8s.io/api/core/v1"
+
+	"github.com/koki/short/types"
+)
+
+// Convert_Kube_PodTemplateSpec_to_Koki converts a pod template, for kinds outside this package
+// (e.g. custom resources) that embed one. The metadata is nil if it's empty.
+func Convert_Kube_PodTemplateSpec_to_Koki(kubeTemplate v1.PodTemplateSpec) (*types.PodTemplateMeta, types.PodTemplate, error) {
+	meta, template, err := convertTemplate(kubeTemplate)
+	if err != nil {
+		return nil, types.PodTemplate{}, err
+	}
+	if reflect.DeepEqual(meta, &types.PodTemplateMeta{}) {
+		meta = nil
+	}
+
+	return meta, template, nil
+}
+
+// Convert_Koki_PodTemplateSpec_to_Kube reverts Convert_Kube_PodTemplateSpec_to_Koki.
+func Convert_Koki_PodTemplateSpec_to_Kube(kokiMeta *types.PodTemplateMeta, kokiTemplate types.PodTemplate) (v1.PodTemplateSpec, error) {
+	kubeTemplate, err := revertTemplate(kokiMeta, kokiTemplate)
+	if err != nil || kubeTemplate == nil {
+		return v1.PodTemplateSpec{}, err
+	}
+
+	return *kubeTemplate, nil
+}
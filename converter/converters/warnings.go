@@ -0,0 +1,44 @@
+package converters
+
+import (
+	"fmt"
+)
+
+/*
+
+Some conversions lose information that the target syntax can't hold, e.g. a generateName next to
+a name. Converters report them to the Warnings they're given, which collects the warnings of one
+object. Callers that don't want the warnings pass nil, and adding to a nil Warnings does nothing,
+so converters never have to check.
+
+*/
+
+// Warning is a problem that didn't stop a conversion.
+type Warning struct {
+	// Path is the field of the input the warning is about, if there is one, e.g. "$.metadata.generateName".
+	Path    string
+	Message string
+}
+
+// Warnings collects the warnings of converting one object. It isn't safe for concurrent use.
+type Warnings struct {
+	list []Warning
+}
+
+// Add records a warning about the field at path. Adding to a nil Warnings discards the warning.
+func (w *Warnings) Add(path, format string, args ...interface{}) {
+	if w == nil {
+		return
+	}
+
+	w.list = append(w.list, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// List returns the warnings in the order they were added.
+func (w *Warnings) List() []Warning {
+	if w == nil {
+		return nil
+	}
+
+	return w.list
+}
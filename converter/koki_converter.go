@@ -2,6 +2,8 @@ package converter
 
 import (
 	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/crdplugin"
+	"github.com/koki/short/parser"
 	"github.com/koki/short/types"
 	serrors "github.com/koki/structurederrors"
 
@@ -26,173 +28,528 @@ import (
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiregistrationv1beta1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-func DetectAndConvertFromKokiObj(kokiObj interface{}) (interface{}, error) {
-	switch kokiObj := kokiObj.(type) {
-	case *types.APIServiceWrapper:
-		return converters.Convert_Koki_APIService_to_Kube_APIService(kokiObj)
-	case *types.BindingWrapper:
-		return converters.Convert_Koki_Binding_to_Kube_Binding(kokiObj)
-	case *types.CertificateSigningRequestWrapper:
-		return converters.Convert_Koki_CSR_to_Kube_CSR(kokiObj)
-	case *types.ClusterRoleWrapper:
-		return converters.Convert_Koki_ClusterRole_to_Kube(kokiObj)
-	case *types.ClusterRoleBindingWrapper:
-		return converters.Convert_Koki_ClusterRoleBinding_to_Kube(kokiObj)
-	case *types.ConfigMapWrapper:
-		return converters.Convert_Koki_ConfigMap_to_Kube_v1_ConfigMap(kokiObj)
-	case *types.ControllerRevisionWrapper:
-		return converters.Convert_Koki_ControllerRevision_to_Kube(kokiObj)
-	case *types.CronJobWrapper:
-		return converters.Convert_Koki_CronJob_to_Kube_CronJob(kokiObj)
-	case *types.CRDWrapper:
-		return converters.Convert_Koki_CRD_to_Kube(kokiObj)
-	case *types.DaemonSetWrapper:
-		return converters.Convert_Koki_DaemonSet_to_Kube_DaemonSet(kokiObj)
-	case *types.DeploymentWrapper:
-		return converters.Convert_Koki_Deployment_to_Kube_Deployment(kokiObj)
-	case *types.EndpointsWrapper:
-		return converters.Convert_Koki_Endpoints_to_Kube_v1_Endpoints(kokiObj)
-	case *types.EventWrapper:
-		return converters.Convert_Koki_Event_to_Kube(kokiObj)
-	case *types.HorizontalPodAutoscalerWrapper:
-		return converters.Convert_Koki_HPA_to_Kube(kokiObj)
-	case *types.IngressWrapper:
-		return converters.Convert_Koki_Ingress_to_Kube_Ingress(kokiObj)
-	case *types.InitializerConfigWrapper:
-		return converters.Convert_Koki_InitializerConfig_to_Kube_InitializerConfig(kokiObj)
-	case *types.JobWrapper:
-		return converters.Convert_Koki_Job_to_Kube_Job(kokiObj)
-	case *types.LimitRangeWrapper:
-		return converters.Convert_Koki_LimitRange_to_Kube(kokiObj)
-	case *types.NamespaceWrapper:
-		return converters.Convert_Koki_Namespace_to_Kube_Namespace(kokiObj)
-	case *types.PersistentVolumeClaimWrapper:
-		return converters.Convert_Koki_PVC_to_Kube_PVC(kokiObj)
-	case *types.PersistentVolumeWrapper:
-		return converters.Convert_Koki_PersistentVolume_to_Kube_v1_PersistentVolume(kokiObj)
-	case *types.PodDisruptionBudgetWrapper:
-		return converters.Convert_Koki_PodDisruptionBudget_to_Kube_PodDisruptionBudget(kokiObj)
-	case *types.PodPresetWrapper:
-		return converters.Convert_Koki_PodPreset_to_Kube_PodPreset(kokiObj)
-	case *types.PodSecurityPolicyWrapper:
-		return converters.Convert_Koki_PodSecurityPolicy_to_Kube_PodSecurityPolicy(kokiObj)
-	case *types.PodTemplateWrapper:
-		return converters.Convert_Koki_PodTemplate_to_Kube(kokiObj)
-	case *types.PodWrapper:
-		return converters.Convert_Koki_Pod_to_Kube_v1_Pod(kokiObj)
-	case *types.PriorityClassWrapper:
-		return converters.Convert_Koki_PriorityClass_to_Kube_PriorityClass(kokiObj)
-	case *types.ReplicationControllerWrapper:
-		return converters.Convert_Koki_ReplicationController_to_Kube_v1_ReplicationController(kokiObj)
-	case *types.ReplicaSetWrapper:
-		return converters.Convert_Koki_ReplicaSet_to_Kube_ReplicaSet(kokiObj)
-	case *types.RoleWrapper:
-		return converters.Convert_Koki_Role_to_Kube(kokiObj)
-	case *types.RoleBindingWrapper:
-		return converters.Convert_Koki_RoleBinding_to_Kube(kokiObj)
-	case *types.SecretWrapper:
-		return converters.Convert_Koki_Secret_to_Kube_v1_Secret(kokiObj)
-	case *types.ServiceWrapper:
-		return converters.Convert_Koki_Service_To_Kube_v1_Service(kokiObj)
-	case *types.ServiceAccountWrapper:
-		return converters.Convert_Koki_ServiceAccount_to_Kube_ServiceAccount(kokiObj)
-	case *types.StatefulSetWrapper:
-		return converters.Convert_Koki_StatefulSet_to_Kube_StatefulSet(kokiObj)
-	case *types.StorageClassWrapper:
-		return converters.Convert_Koki_StorageClass_to_Kube_StorageClass(kokiObj)
-	case *types.VolumeWrapper:
-		return &kokiObj.Volume, nil
-	case *types.MutatingWebhookConfigWrapper:
-		return converters.Convert_Koki_WebhookConfiguration_to_Kube_WebhookConfiguration(kokiObj, "MutatingWebhookConfiguration")
-	case *types.ValidatingWebhookConfigWrapper:
-		return converters.Convert_Koki_WebhookConfiguration_to_Kube_WebhookConfiguration(kokiObj, "ValidatingWebhookConfiguration")
-	default:
-		return nil, serrors.TypeErrorf(kokiObj, "can't convert from unsupported koki type")
+// builtinKinds are the kinds that short syntax supports without a crdplugin.
+var builtinKinds = []Kind{
+	{
+		Key:       "api_service",
+		Kind:      "APIService",
+		KokiType:  &types.APIServiceWrapper{},
+		KubeTypes: []runtime.Object{&apiregistrationv1beta1.APIService{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_APIService_to_Kube_APIService(kokiObj.(*types.APIServiceWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_APIService_to_Koki_APIService(kubeObj.(*apiregistrationv1beta1.APIService))
+		},
+	},
+	{
+		Key:       "binding",
+		Kind:      "Binding",
+		KokiType:  &types.BindingWrapper{},
+		KubeTypes: []runtime.Object{&v1.Binding{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Binding_to_Kube_Binding(kokiObj.(*types.BindingWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Binding_to_Koki_Binding(kubeObj.(*v1.Binding))
+		},
+	},
+	{
+		Key:       "csr",
+		Kind:      "CertificateSigningRequest",
+		KokiType:  &types.CertificateSigningRequestWrapper{},
+		KubeTypes: []runtime.Object{&certificatesv1beta1.CertificateSigningRequest{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_CSR_to_Kube_CSR(kokiObj.(*types.CertificateSigningRequestWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_CSR_to_Koki_CSR(kubeObj.(*certificatesv1beta1.CertificateSigningRequest))
+		},
+	},
+	{
+		Key:       "cluster_role",
+		Kind:      "ClusterRole",
+		KokiType:  &types.ClusterRoleWrapper{},
+		KubeTypes: []runtime.Object{&rbac.ClusterRole{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ClusterRole_to_Kube(kokiObj.(*types.ClusterRoleWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_ClusterRole_to_Koki(kubeObj.(*rbac.ClusterRole))
+		},
+	},
+	{
+		Key:       "cluster_role_binding",
+		Kind:      "ClusterRoleBinding",
+		KokiType:  &types.ClusterRoleBindingWrapper{},
+		KubeTypes: []runtime.Object{&rbac.ClusterRoleBinding{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ClusterRoleBinding_to_Kube(kokiObj.(*types.ClusterRoleBindingWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_ClusterRoleBinding_to_Koki(kubeObj.(*rbac.ClusterRoleBinding))
+		},
+	},
+	{
+		Key:       "config_map",
+		Kind:      "ConfigMap",
+		KokiType:  &types.ConfigMapWrapper{},
+		KubeTypes: []runtime.Object{&v1.ConfigMap{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ConfigMap_to_Kube_v1_ConfigMap(kokiObj.(*types.ConfigMapWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_ConfigMap_to_Koki_ConfigMap(kubeObj.(*v1.ConfigMap))
+		},
+	},
+	{
+		Key:       "controller_revision",
+		Kind:      "ControllerRevision",
+		KokiType:  &types.ControllerRevisionWrapper{},
+		KubeTypes: []runtime.Object{&apps.ControllerRevision{}, &appsv1beta1.ControllerRevision{}, &appsv1beta2.ControllerRevision{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ControllerRevision_to_Kube(kokiObj.(*types.ControllerRevisionWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_ControllerRevision_to_Koki(kubeObj)
+		},
+	},
+	{
+		Key:       "cron_job",
+		Kind:      "CronJob",
+		KokiType:  &types.CronJobWrapper{},
+		KubeTypes: []runtime.Object{&batchv1beta1.CronJob{}, &batchv2alpha1.CronJob{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_CronJob_to_Kube_CronJob(kokiObj.(*types.CronJobWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_CronJob_to_Koki_CronJob(kubeObj)
+		},
+	},
+	{
+		Key:       "crd",
+		Kind:      "CustomResourceDefinition",
+		KokiType:  &types.CRDWrapper{},
+		KubeTypes: []runtime.Object{&apiext.CustomResourceDefinition{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_CRD_to_Kube(kokiObj.(*types.CRDWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_CRD_to_Koki(kubeObj.(*apiext.CustomResourceDefinition))
+		},
+	},
+	{
+		Key:       "daemon_set",
+		Kind:      "DaemonSet",
+		KokiType:  &types.DaemonSetWrapper{},
+		KubeTypes: []runtime.Object{&apps.DaemonSet{}, &appsv1beta2.DaemonSet{}, &exts.DaemonSet{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_DaemonSet_to_Kube_DaemonSet(kokiObj.(*types.DaemonSetWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_DaemonSet_to_Koki_DaemonSet(kubeObj)
+		},
+	},
+	{
+		Key:       "deployment",
+		Kind:      "Deployment",
+		KokiType:  &types.DeploymentWrapper{},
+		KubeTypes: []runtime.Object{&apps.Deployment{}, &appsv1beta1.Deployment{}, &appsv1beta2.Deployment{}, &exts.Deployment{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Deployment_to_Kube_Deployment(kokiObj.(*types.DeploymentWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Deployment_to_Koki_Deployment(kubeObj)
+		},
+	},
+	{
+		Key:       "endpoints",
+		Kind:      "Endpoints",
+		KokiType:  &types.EndpointsWrapper{},
+		KubeTypes: []runtime.Object{&v1.Endpoints{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Endpoints_to_Kube_v1_Endpoints(kokiObj.(*types.EndpointsWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_Endpoints_to_Koki_Endpoints(kubeObj.(*v1.Endpoints))
+		},
+	},
+	{
+		Key:       "event",
+		Kind:      "Event",
+		KokiType:  &types.EventWrapper{},
+		KubeTypes: []runtime.Object{&v1.Event{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Event_to_Kube(kokiObj.(*types.EventWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Event_to_Koki(kubeObj.(*v1.Event))
+		},
+	},
+	{
+		Key:       "hpa",
+		Kind:      "HorizontalPodAutoscaler",
+		KokiType:  &types.HorizontalPodAutoscalerWrapper{},
+		KubeTypes: []runtime.Object{&autoscaling.HorizontalPodAutoscaler{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_HPA_to_Kube(kokiObj.(*types.HorizontalPodAutoscalerWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_HPA_to_Koki(kubeObj.(*autoscaling.HorizontalPodAutoscaler))
+		},
+	},
+	{
+		Key:       "ingress",
+		Kind:      "Ingress",
+		KokiType:  &types.IngressWrapper{},
+		KubeTypes: []runtime.Object{&exts.Ingress{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Ingress_to_Kube_Ingress(kokiObj.(*types.IngressWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Ingress_to_Koki_Ingress(kubeObj.(*exts.Ingress))
+		},
+	},
+	{
+		Key:       "initializer_config",
+		Kind:      "InitializerConfiguration",
+		KokiType:  &types.InitializerConfigWrapper{},
+		KubeTypes: []runtime.Object{&admissionregv1alpha1.InitializerConfiguration{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_InitializerConfig_to_Kube_InitializerConfig(kokiObj.(*types.InitializerConfigWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_InitializerConfig_to_Koki_InitializerConfig(kubeObj.(*admissionregv1alpha1.InitializerConfiguration))
+		},
+	},
+	{
+		Key:       "job",
+		Kind:      "Job",
+		KokiType:  &types.JobWrapper{},
+		KubeTypes: []runtime.Object{&batchv1.Job{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Job_to_Kube_Job(kokiObj.(*types.JobWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Job_to_Koki_Job(kubeObj.(*batchv1.Job))
+		},
+	},
+	{
+		Key:           "lease",
+		Kind:          "Lease",
+		KokiType:      &types.LeaseWrapper{},
+		KubeGroupKind: parser.LeaseGroupKind,
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Lease_to_Kube(kokiObj.(*types.LeaseWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Lease_to_Koki(kubeObj.(*unstructured.Unstructured))
+		},
+	},
+	{
+		Key:       "limit_range",
+		Kind:      "LimitRange",
+		KokiType:  &types.LimitRangeWrapper{},
+		KubeTypes: []runtime.Object{&v1.LimitRange{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_LimitRange_to_Kube(kokiObj.(*types.LimitRangeWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_LimitRange_to_Koki(kubeObj.(*v1.LimitRange))
+		},
+	},
+	{
+		Key:       "namespace",
+		Kind:      "Namespace",
+		KokiType:  &types.NamespaceWrapper{},
+		KubeTypes: []runtime.Object{&v1.Namespace{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Namespace_to_Kube_Namespace(kokiObj.(*types.NamespaceWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Namespace_to_Koki_Namespace(kubeObj.(*v1.Namespace))
+		},
+	},
+	{
+		Key:       "node",
+		Kind:      "Node",
+		KokiType:  &types.NodeWrapper{},
+		KubeTypes: []runtime.Object{&v1.Node{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Node_to_Kube(kokiObj.(*types.NodeWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Node_to_Koki(kubeObj.(*v1.Node))
+		},
+	},
+	{
+		Key:       "pdb",
+		Kind:      "PodDisruptionBudget",
+		KokiType:  &types.PodDisruptionBudgetWrapper{},
+		KubeTypes: []runtime.Object{&policyv1beta1.PodDisruptionBudget{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PodDisruptionBudget_to_Kube_PodDisruptionBudget(kokiObj.(*types.PodDisruptionBudgetWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PodDisruptionBudget_to_Koki_PodDisruptionBudget(kubeObj.(*policyv1beta1.PodDisruptionBudget))
+		},
+	},
+	{
+		Key:       "persistent_volume",
+		Kind:      "PersistentVolume",
+		KokiType:  &types.PersistentVolumeWrapper{},
+		KubeTypes: []runtime.Object{&v1.PersistentVolume{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PersistentVolume_to_Kube_v1_PersistentVolume(kokiObj.(*types.PersistentVolumeWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_PersistentVolume_to_Koki_PersistentVolume(kubeObj.(*v1.PersistentVolume))
+		},
+	},
+	{
+		Key:       "pod",
+		Kind:      "Pod",
+		KokiType:  &types.PodWrapper{},
+		KubeTypes: []runtime.Object{&v1.Pod{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Pod_to_Kube_v1_Pod(kokiObj.(*types.PodWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_Pod_to_Koki_Pod(kubeObj.(*v1.Pod))
+		},
+	},
+	{
+		Key:       "pod_preset",
+		Kind:      "PodPreset",
+		KokiType:  &types.PodPresetWrapper{},
+		KubeTypes: []runtime.Object{&settingsv1alpha1.PodPreset{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PodPreset_to_Kube_PodPreset(kokiObj.(*types.PodPresetWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PodPreset_to_Koki_PodPreset(kubeObj.(*settingsv1alpha1.PodPreset))
+		},
+	},
+	{
+		Key:       "pod_security_policy",
+		Kind:      "PodSecurityPolicy",
+		KokiType:  &types.PodSecurityPolicyWrapper{},
+		KubeTypes: []runtime.Object{&exts.PodSecurityPolicy{}, &policyv1beta1.PodSecurityPolicy{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PodSecurityPolicy_to_Kube_PodSecurityPolicy(kokiObj.(*types.PodSecurityPolicyWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubeObj)
+		},
+	},
+	{
+		Key:       "pod_template",
+		Kind:      "PodTemplate",
+		KokiType:  &types.PodTemplateWrapper{},
+		KubeTypes: []runtime.Object{&v1.PodTemplate{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PodTemplate_to_Kube(kokiObj.(*types.PodTemplateWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PodTemplate_to_Koki(kubeObj.(*v1.PodTemplate))
+		},
+	},
+	{
+		Key:       "priority_class",
+		Kind:      "PriorityClass",
+		KokiType:  &types.PriorityClassWrapper{},
+		KubeTypes: []runtime.Object{&schedulingv1alpha1.PriorityClass{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PriorityClass_to_Kube_PriorityClass(kokiObj.(*types.PriorityClassWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PriorityClass_to_Koki_PriorityClass(kubeObj.(*schedulingv1alpha1.PriorityClass))
+		},
+	},
+	{
+		Key:       "pvc",
+		Kind:      "PersistentVolumeClaim",
+		KokiType:  &types.PersistentVolumeClaimWrapper{},
+		KubeTypes: []runtime.Object{&v1.PersistentVolumeClaim{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_PVC_to_Kube_PVC(kokiObj.(*types.PersistentVolumeClaimWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_PVC_to_Koki_PVC(kubeObj.(*v1.PersistentVolumeClaim))
+		},
+	},
+	{
+		Key:       "replica_set",
+		Kind:      "ReplicaSet",
+		KokiType:  &types.ReplicaSetWrapper{},
+		KubeTypes: []runtime.Object{&apps.ReplicaSet{}, &appsv1beta2.ReplicaSet{}, &exts.ReplicaSet{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ReplicaSet_to_Kube_ReplicaSet(kokiObj.(*types.ReplicaSetWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_ReplicaSet_to_Koki_ReplicaSet(kubeObj)
+		},
+	},
+	{
+		Key:       "replication_controller",
+		Kind:      "ReplicationController",
+		KokiType:  &types.ReplicationControllerWrapper{},
+		KubeTypes: []runtime.Object{&v1.ReplicationController{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ReplicationController_to_Kube_v1_ReplicationController(kokiObj.(*types.ReplicationControllerWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_ReplicationController_to_Koki_ReplicationController(kubeObj.(*v1.ReplicationController))
+		},
+	},
+	{
+		Key:       "role",
+		Kind:      "Role",
+		KokiType:  &types.RoleWrapper{},
+		KubeTypes: []runtime.Object{&rbac.Role{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Role_to_Kube(kokiObj.(*types.RoleWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_Role_to_Koki(kubeObj.(*rbac.Role))
+		},
+	},
+	{
+		Key:       "role_binding",
+		Kind:      "RoleBinding",
+		KokiType:  &types.RoleBindingWrapper{},
+		KubeTypes: []runtime.Object{&rbac.RoleBinding{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_RoleBinding_to_Kube(kokiObj.(*types.RoleBindingWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_RoleBinding_to_Koki(kubeObj.(*rbac.RoleBinding))
+		},
+	},
+	{
+		Key:       "secret",
+		Kind:      "Secret",
+		KokiType:  &types.SecretWrapper{},
+		KubeTypes: []runtime.Object{&v1.Secret{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Secret_to_Kube_v1_Secret(kokiObj.(*types.SecretWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_Secret_to_Koki_Secret(kubeObj.(*v1.Secret))
+		},
+	},
+	{
+		Key:       "service",
+		Kind:      "Service",
+		KokiType:  &types.ServiceWrapper{},
+		KubeTypes: []runtime.Object{&v1.Service{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_Service_To_Kube_v1_Service(kokiObj.(*types.ServiceWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_v1_Service_to_Koki_Service(kubeObj.(*v1.Service))
+		},
+	},
+	{
+		Key:       "service_account",
+		Kind:      "ServiceAccount",
+		KokiType:  &types.ServiceAccountWrapper{},
+		KubeTypes: []runtime.Object{&v1.ServiceAccount{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_ServiceAccount_to_Kube_ServiceAccount(kokiObj.(*types.ServiceAccountWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_ServiceAccount_to_Koki_ServiceAccount(kubeObj.(*v1.ServiceAccount))
+		},
+	},
+	{
+		Key:       "stateful_set",
+		Kind:      "StatefulSet",
+		KokiType:  &types.StatefulSetWrapper{},
+		KubeTypes: []runtime.Object{&apps.StatefulSet{}, &appsv1beta1.StatefulSet{}, &appsv1beta2.StatefulSet{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_StatefulSet_to_Kube_StatefulSet(kokiObj.(*types.StatefulSetWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_StatefulSet_to_Koki_StatefulSet(kubeObj)
+		},
+	},
+	{
+		Key:       "storage_class",
+		Kind:      "StorageClass",
+		KokiType:  &types.StorageClassWrapper{},
+		KubeTypes: []runtime.Object{&storagev1.StorageClass{}, &storagev1beta1.StorageClass{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_StorageClass_to_Kube_StorageClass(kokiObj.(*types.StorageClassWrapper))
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_StorageClass_to_Koki_StorageClass(kubeObj)
+		},
+	},
+	{
+		Key:      "volume",
+		Kind:     "Volume",
+		KokiType: &types.VolumeWrapper{},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return &kokiObj.(*types.VolumeWrapper).Volume, nil
+		},
+	},
+	{
+		Key:       "mutating_webhook",
+		Kind:      "MutatingWebhookConfiguration",
+		KokiType:  &types.MutatingWebhookConfigWrapper{},
+		KubeTypes: []runtime.Object{&admissionregv1beta1.MutatingWebhookConfiguration{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_WebhookConfiguration_to_Kube_WebhookConfiguration(kokiObj, "MutatingWebhookConfiguration")
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(kubeObj, types.MutatingKind)
+		},
+	},
+	{
+		Key:       "validating_webhook",
+		Kind:      "ValidatingWebhookConfiguration",
+		KokiType:  &types.ValidatingWebhookConfigWrapper{},
+		KubeTypes: []runtime.Object{&admissionregv1beta1.ValidatingWebhookConfiguration{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Koki_WebhookConfiguration_to_Kube_WebhookConfiguration(kokiObj, "ValidatingWebhookConfiguration")
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return converters.Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(kubeObj, types.ValidatingKind)
+		},
+	},
+}
+
+func init() {
+	for _, kind := range builtinKinds {
+		DefaultRegistry.MustRegister(kind)
 	}
 }
 
-func DetectAndConvertFromKubeObj(kubeObj runtime.Object) (interface{}, error) {
-	switch kubeObj := kubeObj.(type) {
-	case *apiregistrationv1beta1.APIService:
-		return converters.Convert_Kube_APIService_to_Koki_APIService(kubeObj)
-	case *v1.Binding:
-		return converters.Convert_Kube_Binding_to_Koki_Binding(kubeObj)
-	case *certificatesv1beta1.CertificateSigningRequest:
-		return converters.Convert_Kube_CSR_to_Koki_CSR(kubeObj)
-	case *rbac.ClusterRole:
-		return converters.Convert_Kube_ClusterRole_to_Koki(kubeObj)
-	case *rbac.ClusterRoleBinding:
-		return converters.Convert_Kube_ClusterRoleBinding_to_Koki(kubeObj)
-	case *v1.ConfigMap:
-		return converters.Convert_Kube_v1_ConfigMap_to_Koki_ConfigMap(kubeObj)
-	case *apps.ControllerRevision, *appsv1beta1.ControllerRevision, *appsv1beta2.ControllerRevision:
-		return converters.Convert_Kube_ControllerRevision_to_Koki(kubeObj)
-	case *batchv1beta1.CronJob, *batchv2alpha1.CronJob:
-		return converters.Convert_Kube_CronJob_to_Koki_CronJob(kubeObj)
-	case *apiext.CustomResourceDefinition:
-		return converters.Convert_Kube_CRD_to_Koki(kubeObj)
-	case *appsv1beta2.DaemonSet, *exts.DaemonSet:
-		return converters.Convert_Kube_DaemonSet_to_Koki_DaemonSet(kubeObj)
-	case *appsv1beta1.Deployment, *appsv1beta2.Deployment, *exts.Deployment:
-		return converters.Convert_Kube_Deployment_to_Koki_Deployment(kubeObj)
-	case *v1.Endpoints:
-		return converters.Convert_Kube_v1_Endpoints_to_Koki_Endpoints(kubeObj)
-	case *v1.Event:
-		return converters.Convert_Kube_Event_to_Koki(kubeObj)
-	case *autoscaling.HorizontalPodAutoscaler:
-		return converters.Convert_Kube_HPA_to_Koki(kubeObj)
-	case *exts.Ingress:
-		return converters.Convert_Kube_Ingress_to_Koki_Ingress(kubeObj)
-	case *admissionregv1alpha1.InitializerConfiguration:
-		return converters.Convert_Kube_InitializerConfig_to_Koki_InitializerConfig(kubeObj)
-	case *batchv1.Job:
-		return converters.Convert_Kube_Job_to_Koki_Job(kubeObj)
-	case *v1.LimitRange:
-		return converters.Convert_Kube_LimitRange_to_Koki(kubeObj)
-	case *v1.Namespace:
-		return converters.Convert_Kube_Namespace_to_Koki_Namespace(kubeObj)
-	case *v1.PersistentVolume:
-		return converters.Convert_Kube_v1_PersistentVolume_to_Koki_PersistentVolume(kubeObj)
-	case *v1.PersistentVolumeClaim:
-		return converters.Convert_Kube_PVC_to_Koki_PVC(kubeObj)
-	case *schedulingv1alpha1.PriorityClass:
-		return converters.Convert_Kube_PriorityClass_to_Koki_PriorityClass(kubeObj)
-	case *v1.Pod:
-		return converters.Convert_Kube_v1_Pod_to_Koki_Pod(kubeObj)
-	case *policyv1beta1.PodDisruptionBudget:
-		return converters.Convert_Kube_PodDisruptionBudget_to_Koki_PodDisruptionBudget(kubeObj)
-	case *settingsv1alpha1.PodPreset:
-		return converters.Convert_Kube_PodPreset_to_Koki_PodPreset(kubeObj)
-	case *exts.PodSecurityPolicy:
-		return converters.Convert_Kube_PodSecurityPolicy_to_Koki_PodSecurityPolicy(kubeObj)
-	case *v1.PodTemplate:
-		return converters.Convert_Kube_PodTemplate_to_Koki(kubeObj)
-	case *v1.ReplicationController:
-		return converters.Convert_Kube_v1_ReplicationController_to_Koki_ReplicationController(kubeObj)
-	case *appsv1beta2.ReplicaSet, *exts.ReplicaSet:
-		return converters.Convert_Kube_ReplicaSet_to_Koki_ReplicaSet(kubeObj)
-	case *rbac.Role:
-		return converters.Convert_Kube_Role_to_Koki(kubeObj)
-	case *rbac.RoleBinding:
-		return converters.Convert_Kube_RoleBinding_to_Koki(kubeObj)
-	case *v1.Secret:
-		return converters.Convert_Kube_v1_Secret_to_Koki_Secret(kubeObj)
-	case *v1.Service:
-		return converters.Convert_Kube_v1_Service_to_Koki_Service(kubeObj)
-	case *v1.ServiceAccount:
-		return converters.Convert_Kube_ServiceAccount_to_Koki_ServiceAccount(kubeObj)
-	case *appsv1beta1.StatefulSet, *appsv1beta2.StatefulSet:
-		return converters.Convert_Kube_StatefulSet_to_Koki_StatefulSet(kubeObj)
-	case *storagev1.StorageClass, *storagev1beta1.StorageClass:
-		return converters.Convert_Kube_StorageClass_to_Koki_StorageClass(kubeObj)
-	case *admissionregv1beta1.MutatingWebhookConfiguration:
-		return converters.Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(kubeObj, types.MutatingKind)
-	case *admissionregv1beta1.ValidatingWebhookConfiguration:
-		return converters.Convert_Kube_WebhookConfiguration_to_Koki_WebhookConfiguration(kubeObj, types.ValidatingKind)
-	default:
-		return nil, serrors.TypeErrorf(kubeObj, "can't convert from unsupported kube type")
+func convertFromKokiObj(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+	if kind, ok := DefaultRegistry.ForKoki(kokiObj); ok {
+		return kind.ToKube(kokiObj, warnings)
+	}
+	if converter, ok := crdplugin.ForKoki(kokiObj); ok {
+		return converter.ToKube(kokiObj)
 	}
+
+	return nil, serrors.TypeErrorf(kokiObj, "can't convert from unsupported koki type")
+}
+
+func convertFromKubeObj(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+	if kind, ok := DefaultRegistry.ForKube(kubeObj); ok {
+		return kind.ToKoki(kubeObj, warnings)
+	}
+	if kubeObj, ok := kubeObj.(*unstructured.Unstructured); ok {
+		if converter, ok := crdplugin.ForGroupVersionKind(kubeObj.GroupVersionKind()); ok {
+			return converter.ToKoki(kubeObj)
+		}
+	}
+
+	return nil, serrors.TypeErrorf(kubeObj, "can't convert from unsupported kube type")
 }
@@ -0,0 +1,134 @@
+package converter
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeTypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Every kind has owner references, finalizers and generateName in its metadata, so they're
+converted here instead of in each converter. Short types embed types.Ownership to hold the
+owners and finalizers. A generateName is written as the short name if it ends in "-", since
+a real name can't, and in the generate_name field of types.Ownership otherwise.
+
+The WithWarnings variants also return what the conversion lost (see converters.Warnings).
+
+*/
+
+// DetectAndConvertFromKokiObj converts a short object to its Kubernetes counterpart.
+func DetectAndConvertFromKokiObj(kokiObj interface{}) (interface{}, error) {
+	return DetectAndConvertFromKokiObjWithWarnings(kokiObj, nil)
+}
+
+// DetectAndConvertFromKokiObjWithWarnings is DetectAndConvertFromKokiObj, adding what it can't convert to warnings.
+func DetectAndConvertFromKokiObjWithWarnings(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+	kubeObj, err := convertFromKokiObj(kokiObj, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	ownership := types.OwnershipOf(kokiObj)
+	hasOwnership := ownership != nil && (len(ownership.Owners) > 0 || len(ownership.Finalizers) > 0 || len(ownership.GenerateName) > 0)
+	name := types.NameOf(kokiObj)
+	isGeneratedName := name != nil && strings.HasSuffix(*name, "-")
+	if !hasOwnership && !isGeneratedName {
+		return kubeObj, nil
+	}
+
+	kubeMeta, ok := kubeObj.(metav1.Object)
+	if !ok {
+		return nil, serrors.InvalidInstanceErrorf(kubeObj, "can't set owners, finalizers or generateName")
+	}
+	if hasOwnership {
+		kubeMeta.SetOwnerReferences(revertOwners(ownership.Owners))
+		kubeMeta.SetFinalizers(ownership.Finalizers)
+		if len(ownership.GenerateName) > 0 {
+			kubeMeta.SetGenerateName(ownership.GenerateName)
+		}
+	}
+	if isGeneratedName {
+		kubeMeta.SetName("")
+		kubeMeta.SetGenerateName(*name)
+	}
+
+	return kubeObj, nil
+}
+
+// DetectAndConvertFromKubeObj converts a Kubernetes object to its short counterpart.
+func DetectAndConvertFromKubeObj(kubeObj runtime.Object) (interface{}, error) {
+	return DetectAndConvertFromKubeObjWithWarnings(kubeObj, nil)
+}
+
+// DetectAndConvertFromKubeObjWithWarnings is DetectAndConvertFromKubeObj, adding what it can't convert to warnings.
+func DetectAndConvertFromKubeObjWithWarnings(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+	kokiObj, err := convertFromKubeObj(kubeObj, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeMeta, ok := kubeObj.(metav1.Object)
+	if !ok {
+		return kokiObj, nil
+	}
+	ownership := types.OwnershipOf(kokiObj)
+	if ownership != nil {
+		ownership.Owners = convertOwners(kubeMeta.GetOwnerReferences())
+		ownership.Finalizers = kubeMeta.GetFinalizers()
+	}
+	// generateName is only used when there's no name.
+	if name := types.NameOf(kokiObj); name != nil && len(kubeMeta.GetGenerateName()) > 0 {
+		generateName := kubeMeta.GetGenerateName()
+		switch {
+		case len(*name) > 0:
+			warnings.Add("$.metadata.generateName", "dropped, since the object has a name")
+		case strings.HasSuffix(generateName, "-"):
+			*name = generateName
+		case ownership != nil:
+			ownership.GenerateName = generateName
+		default:
+			return nil, serrors.InvalidInstanceErrorf(kubeObj, "generateName %q doesn't end in \"-\", so it can't be written as the name", generateName)
+		}
+	}
+
+	return kokiObj, nil
+}
+
+func convertOwners(kubeRefs []metav1.OwnerReference) []types.OwnerReference {
+	var refs []types.OwnerReference
+	for _, kubeRef := range kubeRefs {
+		refs = append(refs, types.OwnerReference{
+			Version:            kubeRef.APIVersion,
+			Kind:               kubeRef.Kind,
+			Name:               kubeRef.Name,
+			UID:                string(kubeRef.UID),
+			Controller:         kubeRef.Controller,
+			BlockOwnerDeletion: kubeRef.BlockOwnerDeletion,
+		})
+	}
+
+	return refs
+}
+
+func revertOwners(refs []types.OwnerReference) []metav1.OwnerReference {
+	var kubeRefs []metav1.OwnerReference
+	for _, ref := range refs {
+		kubeRefs = append(kubeRefs, metav1.OwnerReference{
+			APIVersion:         ref.Version,
+			Kind:               ref.Kind,
+			Name:               ref.Name,
+			UID:                kubeTypes.UID(ref.UID),
+			Controller:         ref.Controller,
+			BlockOwnerDeletion: ref.BlockOwnerDeletion,
+		})
+	}
+
+	return kubeRefs
+}
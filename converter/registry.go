@@ -0,0 +1,232 @@
+package converter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/crdplugin"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A Registry finds the converters of a kind from its short object or its Kubernetes object.
+
+The built-in kinds are registered in DefaultRegistry when the package is loaded. Registering
+checks each kind, and two kinds with the same short key, short type or Kubernetes type are a
+mistake, so short panics at startup instead of converting to the wrong kind later. Custom
+resources are registered with crdplugin instead.
+
+A Registry is safe for concurrent use, so programs that convert from several goroutines, e.g. an
+HTTP server, can share DefaultRegistry.
+
+*/
+
+// Kind converts one kind to and from short syntax.
+type Kind struct {
+	// Key identifies the kind in short syntax, e.g. "deployment".
+	Key string
+	// Kind is the Kubernetes kind, e.g. "Deployment".
+	Kind string
+	// KokiType is an empty short object of the kind, e.g. &types.DeploymentWrapper{}.
+	KokiType interface{}
+	// KubeTypes are empty Kubernetes objects of each apiVersion that the kind is read from, e.g. &apps.Deployment{}.
+	KubeTypes []runtime.Object
+	// KubeGroupKind is set instead of KubeTypes for kinds that the vendored Kubernetes API doesn't have types for,
+	// which are read as unstructured objects (see parser.ParseSingleKubeNative).
+	KubeGroupKind schema.GroupKind
+	// ToKube converts a short object of KokiType to a Kubernetes object, adding what it can't convert to warnings.
+	ToKube func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error)
+	// ToKoki converts a Kubernetes object of one of KubeTypes (or KubeGroupKind) to a short object, adding what it
+	// can't convert to warnings. It's nil for kinds that are only written, e.g. a pod's volume.
+	ToKoki func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error)
+}
+
+// Registry holds kinds by their short key, short type and Kubernetes types.
+type Registry struct {
+	mu          sync.RWMutex
+	byKey       map[string]*Kind
+	byKokiType  map[reflect.Type]*Kind
+	byKubeType  map[reflect.Type]*Kind
+	byGroupKind map[schema.GroupKind]*Kind
+}
+
+// DefaultRegistry has the built-in kinds.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byKey:       map[string]*Kind{},
+		byKokiType:  map[reflect.Type]*Kind{},
+		byKubeType:  map[reflect.Type]*Kind{},
+		byGroupKind: map[schema.GroupKind]*Kind{},
+	}
+}
+
+// validate checks that a kind has what converting it needs.
+func (k *Kind) validate() error {
+	if len(k.Key) == 0 || len(k.Kind) == 0 {
+		return serrors.InvalidInstanceErrorf(k, "a kind needs a short key and a Kubernetes kind")
+	}
+	if k.KokiType == nil || k.ToKube == nil {
+		return serrors.InvalidInstanceErrorf(k, "%s needs a short type and a conversion to Kubernetes syntax", k.Key)
+	}
+	readsKube := len(k.KubeTypes) > 0 || !k.KubeGroupKind.Empty()
+	if readsKube != (k.ToKoki != nil) {
+		return serrors.InvalidInstanceErrorf(k, "%s needs both Kubernetes types and a conversion to short syntax, or neither", k.Key)
+	}
+	if len(k.KubeTypes) > 0 && !k.KubeGroupKind.Empty() {
+		return serrors.InvalidInstanceErrorf(k, "%s can't have both Kubernetes types and an unstructured kind", k.Key)
+	}
+	if !parser.IsKokiNativeObject(map[string]interface{}{k.Key: nil}) {
+		return serrors.InvalidInstanceErrorf(k, "the parser doesn't know the short key %s", k.Key)
+	}
+
+	return nil
+}
+
+// Register adds a kind. It's an error if the kind is missing something, or if another kind has the same short key,
+// short type or Kubernetes type.
+func (r *Registry) Register(kind Kind) error {
+	if err := kind.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kokiType := reflect.TypeOf(kind.KokiType)
+	if _, ok := r.byKey[kind.Key]; ok {
+		return serrors.InvalidInstanceErrorf(kind, "the short key %s is already registered", kind.Key)
+	}
+	if _, ok := r.byKokiType[kokiType]; ok {
+		return serrors.InvalidInstanceErrorf(kind, "the short type %s is already registered", kokiType)
+	}
+	kubeTypes := map[reflect.Type]bool{}
+	for _, kubeObj := range kind.KubeTypes {
+		kubeType := reflect.TypeOf(kubeObj)
+		if _, ok := r.byKubeType[kubeType]; ok || kubeTypes[kubeType] {
+			return serrors.InvalidInstanceErrorf(kind, "the Kubernetes type %s is already registered", kubeType)
+		}
+		kubeTypes[kubeType] = true
+	}
+	if _, ok := r.byGroupKind[kind.KubeGroupKind]; ok && !kind.KubeGroupKind.Empty() {
+		return serrors.InvalidInstanceErrorf(kind, "the Kubernetes kind %s is already registered", kind.KubeGroupKind)
+	}
+
+	r.byKey[kind.Key] = &kind
+	r.byKokiType[kokiType] = &kind
+	for kubeType := range kubeTypes {
+		r.byKubeType[kubeType] = &kind
+	}
+	if !kind.KubeGroupKind.Empty() {
+		r.byGroupKind[kind.KubeGroupKind] = &kind
+	}
+
+	return nil
+}
+
+// MustRegister is Register, and panics if the kind can't be registered. It's for registering from init functions.
+func (r *Registry) MustRegister(kind Kind) {
+	if err := r.Register(kind); err != nil {
+		panic(fmt.Sprintf("converter: registering %s: %s", kind.Key, serrors.PrettyError(err)))
+	}
+}
+
+// ForKey returns the kind of a short syntax key.
+func (r *Registry) ForKey(key string) (*Kind, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kind, ok := r.byKey[key]
+	return kind, ok
+}
+
+// ForKoki returns the kind of a short object.
+func (r *Registry) ForKoki(kokiObj interface{}) (*Kind, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kind, ok := r.byKokiType[reflect.TypeOf(kokiObj)]
+	return kind, ok
+}
+
+// ForKube returns the kind of a Kubernetes object, if it can be converted to short syntax.
+func (r *Registry) ForKube(kubeObj runtime.Object) (*Kind, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if u, ok := kubeObj.(*unstructured.Unstructured); ok {
+		kind, ok := r.byGroupKind[u.GroupVersionKind().GroupKind()]
+		return kind, ok
+	}
+
+	kind, ok := r.byKubeType[reflect.TypeOf(kubeObj)]
+	return kind, ok
+}
+
+// SupportedKind describes a kind that short syntax supports.
+type SupportedKind struct {
+	// Key identifies the kind in short syntax, e.g. "deployment".
+	Key string `json:"key"`
+	// Kind is the Kubernetes kind, e.g. "Deployment".
+	Kind string `json:"kind"`
+	// APIVersions are the apiVersions that are converted to short syntax, sorted.
+	APIVersions []string `json:"api_versions"`
+	// Plugin is set for the custom resources of crdplugin.
+	Plugin bool `json:"plugin,omitempty"`
+}
+
+// ListSupportedKinds lists the registered kinds, sorted by key.
+func (r *Registry) ListSupportedKinds() []SupportedKind {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kinds := []SupportedKind{}
+	for _, kind := range r.byKey {
+		apiVersions := []string{}
+		for _, kubeObj := range kind.KubeTypes {
+			if gvk, err := parser.GroupVersionKind(kubeObj); err == nil {
+				apiVersions = append(apiVersions, gvk.GroupVersion().String())
+			}
+		}
+		for _, version := range parser.UnstructuredVersions(kind.KubeGroupKind) {
+			apiVersions = append(apiVersions, schema.GroupVersion{Group: kind.KubeGroupKind.Group, Version: version}.String())
+		}
+		sort.Strings(apiVersions)
+		kinds = append(kinds, SupportedKind{Key: kind.Key, Kind: kind.Kind, APIVersions: apiVersions})
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].Key < kinds[j].Key
+	})
+
+	return kinds
+}
+
+// ListSupportedKinds lists the kinds of DefaultRegistry and those of crdplugin, sorted by key.
+func ListSupportedKinds() []SupportedKind {
+	kinds := DefaultRegistry.ListSupportedKinds()
+	for _, key := range crdplugin.Keys() {
+		converter, _ := crdplugin.ForKey(key)
+		apiVersions := []string{}
+		for _, version := range converter.Versions {
+			apiVersions = append(apiVersions, schema.GroupVersion{Group: converter.GroupKind.Group, Version: version}.String())
+		}
+		sort.Strings(apiVersions)
+		kinds = append(kinds, SupportedKind{Key: key, Kind: converter.GroupKind.Kind, APIVersions: apiVersions, Plugin: true})
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].Key < kinds[j].Key
+	})
+
+	return kinds
+}
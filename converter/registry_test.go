@@ -0,0 +1,147 @@
+package converter
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/types"
+)
+
+func configMapKind() Kind {
+	return Kind{
+		Key:       "config_map",
+		Kind:      "ConfigMap",
+		KokiType:  &types.ConfigMapWrapper{},
+		KubeTypes: []runtime.Object{&v1.ConfigMap{}},
+		ToKube: func(kokiObj interface{}, warnings *converters.Warnings) (interface{}, error) {
+			return nil, nil
+		},
+		ToKoki: func(kubeObj runtime.Object, warnings *converters.Warnings) (interface{}, error) {
+			return nil, nil
+		},
+	}
+}
+
+func TestRegisterDuplicates(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(configMapKind()); err != nil {
+		t.Fatal(err)
+	}
+
+	sameKey := configMapKind()
+	sameKey.KokiType = &types.SecretWrapper{}
+	sameKey.KubeTypes = []runtime.Object{&v1.Secret{}}
+	sameKokiType := configMapKind()
+	sameKokiType.Key = "secret"
+	sameKokiType.KubeTypes = []runtime.Object{&v1.Secret{}}
+	sameKubeType := configMapKind()
+	sameKubeType.Key = "secret"
+	sameKubeType.KokiType = &types.SecretWrapper{}
+	for name, kind := range map[string]Kind{"key": sameKey, "short type": sameKokiType, "Kubernetes type": sameKubeType} {
+		if err := r.Register(kind); err == nil {
+			t.Errorf("expected an error registering a kind with the same %s", name)
+		}
+	}
+
+	if _, ok := r.ForKey("secret"); ok {
+		t.Errorf("expected a kind that failed to register not to be found")
+	}
+}
+
+func TestRegisterValidates(t *testing.T) {
+	noKey := configMapKind()
+	noKey.Key = ""
+	unknownKey := configMapKind()
+	unknownKey.Key = "config_mop"
+	noToKube := configMapKind()
+	noToKube.ToKube = nil
+	noToKoki := configMapKind()
+	noToKoki.ToKoki = nil
+	noKubeTypes := configMapKind()
+	noKubeTypes.KubeTypes = nil
+	for name, kind := range map[string]Kind{"no key": noKey, "unknown key": unknownKey, "no ToKube": noToKube, "no ToKoki": noToKoki, "no KubeTypes": noKubeTypes} {
+		if err := NewRegistry().Register(kind); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected MustRegister to panic")
+			}
+		}()
+		NewRegistry().MustRegister(noKey)
+	}()
+}
+
+func TestRegistryLookups(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(configMapKind())
+
+	for name, found := range map[string]func() (*Kind, bool){
+		"key":  func() (*Kind, bool) { return r.ForKey("config_map") },
+		"koki": func() (*Kind, bool) { return r.ForKoki(&types.ConfigMapWrapper{}) },
+		"kube": func() (*Kind, bool) { return r.ForKube(&v1.ConfigMap{}) },
+	} {
+		kind, ok := found()
+		if !ok || kind.Key != "config_map" {
+			t.Errorf("%s: expected config_map, got %v", name, kind)
+		}
+	}
+	if _, ok := r.ForKube(&v1.Secret{}); ok {
+		t.Errorf("expected an unregistered type not to be found")
+	}
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			r.Register(configMapKind())
+		}()
+		go func() {
+			defer wg.Done()
+			r.ForKube(&v1.ConfigMap{})
+			r.ListSupportedKinds()
+		}()
+	}
+	wg.Wait()
+
+	if kinds := r.ListSupportedKinds(); len(kinds) != 1 {
+		t.Errorf("expected config_map to be registered once, got %v", kinds)
+	}
+}
+
+func TestListSupportedKinds(t *testing.T) {
+	kinds := ListSupportedKinds()
+	for i := 1; i < len(kinds); i++ {
+		if kinds[i-1].Key >= kinds[i].Key {
+			t.Errorf("expected kinds sorted by unique key, got %s before %s", kinds[i-1].Key, kinds[i].Key)
+		}
+	}
+
+	found := map[string]SupportedKind{}
+	for _, kind := range kinds {
+		found[kind.Key] = kind
+	}
+	expected := SupportedKind{
+		Key:         "deployment",
+		Kind:        "Deployment",
+		APIVersions: []string{"apps/v1", "apps/v1beta1", "apps/v1beta2", "extensions/v1beta1"},
+	}
+	if !reflect.DeepEqual(found["deployment"], expected) {
+		t.Errorf("expected %v, got %v", expected, found["deployment"])
+	}
+	if lease := found["lease"]; len(lease.APIVersions) == 0 {
+		t.Errorf("expected the apiVersions of unstructured kinds, got %v", lease)
+	}
+}
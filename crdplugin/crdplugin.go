@@ -0,0 +1,142 @@
+package crdplugin
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/json/jsonutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Crdplugin lets other packages add short syntax for custom resources, e.g. Istio's
+VirtualService, without changing the parser or the converter.
+
+A plugin registers a Converter for each kind, usually from an init function, so that
+importing the plugin's package is enough to use it:
+
+	import _ "github.com/koki/short/crdplugin/istio"
+
+The vendored Kubernetes API doesn't have types for custom resources, so their Kubernetes
+objects are unstructured. FromUnstructured and ToUnstructured move them in and out of
+typed structs that mirror the custom resource's API.
+
+*/
+
+// Converter converts a custom resource kind to and from short syntax.
+type Converter struct {
+	// GroupKind identifies the kind in Kubernetes syntax.
+	GroupKind schema.GroupKind
+	// Versions are the versions of GroupKind that the converter reads, e.g. "v1beta1".
+	Versions []string
+	// Key identifies the kind in short syntax, e.g. "virtual_service".
+	Key string
+	// NewKoki returns an empty short object to parse short syntax into. It's a pointer to a struct with one field, tagged Key.
+	NewKoki func() interface{}
+	// ToKoki converts a Kubernetes object to a short object of the type that NewKoki returns.
+	ToKoki func(kubeObj *unstructured.Unstructured) (interface{}, error)
+	// ToKube converts a short object of the type that NewKoki returns to a Kubernetes object.
+	ToKube func(kokiObj interface{}) (*unstructured.Unstructured, error)
+}
+
+var (
+	byKey       = map[string]*Converter{}
+	byGroupKind = map[schema.GroupKind]*Converter{}
+	byKokiType  = map[reflect.Type]*Converter{}
+)
+
+// Register adds a converter. It panics if another converter has the same key, kind or short type.
+func Register(converter Converter) {
+	kokiType := reflect.TypeOf(converter.NewKoki())
+	if _, ok := byKey[converter.Key]; ok {
+		panic(fmt.Sprintf("crdplugin: %s is already registered", converter.Key))
+	}
+	if _, ok := byGroupKind[converter.GroupKind]; ok {
+		panic(fmt.Sprintf("crdplugin: %s is already registered", converter.GroupKind))
+	}
+	if _, ok := byKokiType[kokiType]; ok {
+		panic(fmt.Sprintf("crdplugin: %s is already registered", kokiType))
+	}
+
+	byKey[converter.Key] = &converter
+	byGroupKind[converter.GroupKind] = &converter
+	byKokiType[kokiType] = &converter
+}
+
+// ForKey returns the converter for a short syntax key.
+func ForKey(key string) (*Converter, bool) {
+	converter, ok := byKey[key]
+	return converter, ok
+}
+
+// ForGroupVersionKind returns the converter for a Kubernetes kind, if it reads that version.
+func ForGroupVersionKind(gvk schema.GroupVersionKind) (*Converter, bool) {
+	converter, ok := byGroupKind[gvk.GroupKind()]
+	if !ok {
+		return nil, false
+	}
+	for _, version := range converter.Versions {
+		if version == gvk.Version {
+			return converter, true
+		}
+	}
+
+	return nil, false
+}
+
+// ForKoki returns the converter for a short object.
+func ForKoki(kokiObj interface{}) (*Converter, bool) {
+	converter, ok := byKokiType[reflect.TypeOf(kokiObj)]
+	return converter, ok
+}
+
+// Keys returns the short syntax keys of the registered converters, sorted.
+func Keys() []string {
+	keys := []string{}
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// FromUnstructured reads a Kubernetes object into obj, a pointer to a struct that mirrors the custom resource's API.
+// Fields that obj doesn't have are an error, since they'd be dropped from the conversion.
+func FromUnstructured(kubeObj *unstructured.Unstructured, obj interface{}) error {
+	kind := kubeObj.GetKind()
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(kubeObj.Object, obj)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextErrorf(err, kubeObj.Object, obj, "couldn't convert to kube %s", kind)
+	}
+
+	// Check for unparsed fields--potential typos.
+	extraneousPaths, err := jsonutil.ExtraneousFieldPaths(kubeObj.Object, obj)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "checking for extraneous fields in %s", kind)
+	}
+	if len(extraneousPaths) > 0 {
+		return &jsonutil.ExtraneousFieldsError{Paths: extraneousPaths}
+	}
+
+	return nil
+}
+
+// ToUnstructured writes obj, a struct that mirrors the custom resource's API, as a Kubernetes object.
+func ToUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
+	objMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, obj, "converting to an unstructured object")
+	}
+
+	// Typed objects leave out the empty creationTimestamp when they're written, so do the same here.
+	unstructured.RemoveNestedField(objMap, "metadata", "creationTimestamp")
+
+	return &unstructured.Unstructured{Object: objMap}, nil
+}
@@ -0,0 +1,598 @@
+package gatewayapi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// standardProtocols are written in lowercase, e.g. "https://". Other protocols are written as they are.
+var standardProtocols = map[string]bool{
+	"HTTP":  true,
+	"HTTPS": true,
+	"TLS":   true,
+	"TCP":   true,
+	"UDP":   true,
+}
+
+// Listener is a port that a Gateway accepts connections on, written as a map from its name to
+// "PROTOCOL://[HOSTNAME]:PORT[ OPTION...]", e.g. {https: "https://*.example.com:443 cert=my-tls"}.
+//
+// The options are:
+//
+//	cert=[NAMESPACE/]SECRET[,...]  the Secrets that hold the TLS certificates
+//	tls=MODE                       Terminate or Passthrough
+//	routes=All|Same|KEY=VALUE,...  the namespaces (by name or labels) that routes may attach from
+//	kinds=KIND[,...]               the kinds of routes that may attach
+type Listener struct {
+	Name     string
+	Protocol string
+	Hostname string
+	Port     int32
+
+	Certs   []string
+	TLSMode string
+
+	RoutesFrom     string
+	RoutesSelector map[string]string
+	RouteKinds     []string
+}
+
+func ParseListener(name, s string) (*Listener, error) {
+	fields := strings.Fields(s)
+	usage := "expected PROTOCOL://[HOSTNAME]:PORT[ cert=SECRET][ tls=MODE][ routes=FROM][ kinds=KIND]"
+	if len(fields) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, usage)
+	}
+
+	listener := &Listener{Name: name}
+	i := strings.Index(fields[0], "://")
+	if i <= 0 {
+		return nil, serrors.InvalidValueErrorf(s, usage)
+	}
+	listener.Protocol = fields[0][:i]
+	if upper := strings.ToUpper(listener.Protocol); standardProtocols[upper] {
+		listener.Protocol = upper
+	}
+
+	hostPort := fields[0][i+3:]
+	colon := strings.LastIndex(hostPort, ":")
+	if colon < 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected a port")
+	}
+	port, err := strconv.ParseInt(hostPort[colon+1:], 10, 32)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port")
+	}
+	listener.Port = int32(port)
+	listener.Hostname = hostPort[:colon]
+
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected OPTION=VALUE", field)
+		}
+		switch kv[0] {
+		case "cert":
+			listener.Certs = strings.Split(kv[1], ",")
+		case "tls":
+			listener.TLSMode = kv[1]
+		case "routes":
+			if !strings.Contains(kv[1], "=") {
+				listener.RoutesFrom = kv[1]
+				break
+			}
+			listener.RoutesSelector = map[string]string{}
+			for _, label := range strings.Split(kv[1], ",") {
+				labelKV := strings.SplitN(label, "=", 2)
+				if len(labelKV) != 2 {
+					return nil, serrors.InvalidValueErrorf(s, "expected routes=KEY=VALUE[,KEY=VALUE...]")
+				}
+				listener.RoutesSelector[labelKV[0]] = labelKV[1]
+			}
+		case "kinds":
+			listener.RouteKinds = strings.Split(kv[1], ",")
+		default:
+			return nil, serrors.InvalidValueErrorf(s, "unexpected option (%s), expected cert, tls, routes or kinds", kv[0])
+		}
+	}
+
+	return listener, nil
+}
+
+func (l Listener) String() string {
+	protocol := l.Protocol
+	if standardProtocols[protocol] {
+		protocol = strings.ToLower(protocol)
+	}
+	s := fmt.Sprintf("%s://%s:%d", protocol, l.Hostname, l.Port)
+	if len(l.Certs) > 0 {
+		s = fmt.Sprintf("%s cert=%s", s, strings.Join(l.Certs, ","))
+	}
+	if len(l.TLSMode) > 0 {
+		s = fmt.Sprintf("%s tls=%s", s, l.TLSMode)
+	}
+	if len(l.RoutesSelector) > 0 {
+		labels := []string{}
+		for key, value := range l.RoutesSelector {
+			labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(labels)
+		s = fmt.Sprintf("%s routes=%s", s, strings.Join(labels, ","))
+	} else if len(l.RoutesFrom) > 0 {
+		s = fmt.Sprintf("%s routes=%s", s, l.RoutesFrom)
+	}
+	if len(l.RouteKinds) > 0 {
+		s = fmt.Sprintf("%s kinds=%s", s, strings.Join(l.RouteKinds, ","))
+	}
+
+	return s
+}
+
+func (l *Listener) UnmarshalJSON(data []byte) error {
+	obj := map[string]string{}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), l)
+	}
+	if len(obj) != 1 {
+		return serrors.InvalidValueForTypeErrorf(obj, l, "expected a single NAME: LISTENER pair")
+	}
+
+	for name, s := range obj {
+		listener, err := ParseListener(name, s)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, name)
+		}
+		*l = *listener
+	}
+
+	return nil
+}
+
+func (l Listener) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{l.Name: l.String()})
+}
+
+// ParentRef is a Gateway that a route attaches to, written as "[NAMESPACE/]NAME[#SECTION][:PORT]",
+// where SECTION is the name of one of its listeners, e.g. "infra/shared#https".
+type ParentRef struct {
+	Namespace string
+	Name      string
+	Section   string
+	Port      int32
+}
+
+func ParseParentRef(s string) (*ParentRef, error) {
+	ref := &ParentRef{}
+	rest := s
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		port, err := strconv.ParseInt(rest[i+1:], 10, 32)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port")
+		}
+		ref.Port = int32(port)
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "#"); i >= 0 {
+		ref.Section = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		ref.Namespace = rest[:i]
+		rest = rest[i+1:]
+	}
+	ref.Name = rest
+	if len(ref.Name) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected [NAMESPACE/]NAME[#SECTION][:PORT]")
+	}
+
+	return ref, nil
+}
+
+func (r ParentRef) String() string {
+	s := r.Name
+	if len(r.Namespace) > 0 {
+		s = fmt.Sprintf("%s/%s", r.Namespace, s)
+	}
+	if len(r.Section) > 0 {
+		s = fmt.Sprintf("%s#%s", s, r.Section)
+	}
+	if r.Port != 0 {
+		s = fmt.Sprintf("%s:%d", s, r.Port)
+	}
+
+	return s
+}
+
+func (r *ParentRef) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), r)
+	}
+
+	ref, err := ParseParentRef(s)
+	if err != nil {
+		return err
+	}
+	*r = *ref
+
+	return nil
+}
+
+func (r ParentRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// BackendRef is a Service that requests are sent to, written as "[NAMESPACE/]NAME[:PORT][ weight=WEIGHT]",
+// e.g. "api:8080 weight=90". Weights are relative to the other backends of the rule.
+type BackendRef struct {
+	Namespace string
+	Name      string
+	Port      int32
+	Weight    *int32
+}
+
+func ParseBackendRef(s string) (*BackendRef, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, serrors.InvalidValueErrorf(s, "expected [NAMESPACE/]NAME[:PORT][ weight=WEIGHT]")
+	}
+
+	ref := &BackendRef{}
+	if len(fields) == 2 {
+		if !strings.HasPrefix(fields[1], "weight=") {
+			return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected weight=WEIGHT", fields[1])
+		}
+		weight, err := strconv.ParseInt(strings.TrimPrefix(fields[1], "weight="), 10, 32)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the weight")
+		}
+		w := int32(weight)
+		ref.Weight = &w
+	}
+
+	rest := fields[0]
+	if i := strings.Index(rest, ":"); i >= 0 {
+		port, err := strconv.ParseInt(rest[i+1:], 10, 32)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port")
+		}
+		ref.Port = int32(port)
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		ref.Namespace = rest[:i]
+		rest = rest[i+1:]
+	}
+	ref.Name = rest
+	if len(ref.Name) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected a service name")
+	}
+
+	return ref, nil
+}
+
+func (r BackendRef) String() string {
+	s := r.Name
+	if len(r.Namespace) > 0 {
+		s = fmt.Sprintf("%s/%s", r.Namespace, s)
+	}
+	if r.Port != 0 {
+		s = fmt.Sprintf("%s:%d", s, r.Port)
+	}
+	if r.Weight != nil {
+		s = fmt.Sprintf("%s weight=%d", s, *r.Weight)
+	}
+
+	return s
+}
+
+func (r *BackendRef) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), r)
+	}
+
+	ref, err := ParseBackendRef(s)
+	if err != nil {
+		return err
+	}
+	*r = *ref
+
+	return nil
+}
+
+func (r BackendRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+const (
+	PathMatchPrefix = "PathPrefix"
+	PathMatchExact  = "Exact"
+	PathMatchRegex  = "RegularExpression"
+)
+
+// HTTPRouteMatch is a condition on requests, written as space-separated terms that must all hold:
+//
+//	/PATH, =/PATH or ~REGEX      the path starts with, is, or matches
+//	METHOD                       e.g. GET
+//	header:NAME=VALUE or ~REGEX  a header is, or matches
+//	query:NAME=VALUE or ~REGEX   a query parameter is, or matches
+//
+// e.g. "/api GET header:x-canary=true".
+type HTTPRouteMatch struct {
+	// PathType is PathMatchPrefix, PathMatchExact or PathMatchRegex, or empty to match any path.
+	PathType    string
+	Path        string
+	Method      string
+	Headers     []ValueMatch
+	QueryParams []ValueMatch
+}
+
+// ValueMatch matches a header or query parameter, exactly or with a regular expression.
+type ValueMatch struct {
+	Name  string
+	Value string
+	Regex bool
+}
+
+func ParseHTTPRouteMatch(s string) (*HTTPRouteMatch, error) {
+	match := &HTTPRouteMatch{}
+	for _, field := range strings.Fields(s) {
+		switch {
+		case strings.HasPrefix(field, "/"):
+			match.PathType, match.Path = PathMatchPrefix, field
+		case strings.HasPrefix(field, "=/"):
+			match.PathType, match.Path = PathMatchExact, field[1:]
+		case strings.HasPrefix(field, "~"):
+			match.PathType, match.Path = PathMatchRegex, field[1:]
+		case strings.HasPrefix(field, "header:"):
+			value, err := parseValueMatch(strings.TrimPrefix(field, "header:"))
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, s)
+			}
+			match.Headers = append(match.Headers, *value)
+		case strings.HasPrefix(field, "query:"):
+			value, err := parseValueMatch(strings.TrimPrefix(field, "query:"))
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, s)
+			}
+			match.QueryParams = append(match.QueryParams, *value)
+		case field == strings.ToUpper(field) && len(match.Method) == 0:
+			match.Method = field
+		default:
+			return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected a path, method, header:NAME=VALUE or query:NAME=VALUE", field)
+		}
+	}
+
+	return match, nil
+}
+
+func parseValueMatch(s string) (*ValueMatch, error) {
+	i := strings.IndexAny(s, "=~")
+	if i <= 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected NAME=VALUE or NAME~REGEX")
+	}
+
+	return &ValueMatch{
+		Name:  s[:i],
+		Value: s[i+1:],
+		Regex: s[i] == '~',
+	}, nil
+}
+
+func (v ValueMatch) String() string {
+	if v.Regex {
+		return v.Name + "~" + v.Value
+	}
+
+	return v.Name + "=" + v.Value
+}
+
+func (m HTTPRouteMatch) String() string {
+	fields := []string{}
+	switch m.PathType {
+	case PathMatchPrefix:
+		fields = append(fields, m.Path)
+	case PathMatchExact:
+		fields = append(fields, "="+m.Path)
+	case PathMatchRegex:
+		fields = append(fields, "~"+m.Path)
+	}
+	if len(m.Method) > 0 {
+		fields = append(fields, m.Method)
+	}
+	for _, header := range m.Headers {
+		fields = append(fields, "header:"+header.String())
+	}
+	for _, param := range m.QueryParams {
+		fields = append(fields, "query:"+param.String())
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func (m *HTTPRouteMatch) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), m)
+	}
+
+	match, err := ParseHTTPRouteMatch(s)
+	if err != nil {
+		return err
+	}
+	*m = *match
+
+	return nil
+}
+
+func (m HTTPRouteMatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+const (
+	FilterSetHeader            = "set-header"
+	FilterAddHeader            = "add-header"
+	FilterRemoveHeader         = "remove-header"
+	FilterSetResponseHeader    = "set-response-header"
+	FilterAddResponseHeader    = "add-response-header"
+	FilterRemoveResponseHeader = "remove-response-header"
+	FilterRedirect             = "redirect"
+	FilterRewrite              = "rewrite"
+	FilterMirror               = "mirror"
+)
+
+// HTTPRouteFilter changes a request or its response, written as "ACTION ARGS":
+//
+//	set-header NAME=VALUE, add-header NAME=VALUE, remove-header NAME
+//	set-response-header NAME=VALUE, add-response-header NAME=VALUE, remove-response-header NAME
+//	redirect [scheme=SCHEME] [host=HOST] [port=PORT] [path=PATH] [code=CODE]
+//	rewrite [host=HOST] [path=PATH]
+//	mirror BACKEND
+//
+// A PATH ending in "*" replaces only the matched prefix, e.g. "rewrite path=/v2/*".
+type HTTPRouteFilter struct {
+	Action string
+
+	// Header and Value are the arguments of the header actions.
+	Header string
+	Value  string
+
+	// Scheme, Hostname, Port, Path and StatusCode are the arguments of redirect and rewrite.
+	Scheme     string
+	Hostname   string
+	Port       *int32
+	Path       string
+	StatusCode *int
+
+	// Mirror is the argument of mirror.
+	Mirror *BackendRef
+}
+
+func ParseHTTPRouteFilter(s string) (*HTTPRouteFilter, error) {
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 2)
+	filter := &HTTPRouteFilter{Action: fields[0]}
+	args := ""
+	if len(fields) == 2 {
+		args = strings.TrimSpace(fields[1])
+	}
+
+	switch filter.Action {
+	case FilterSetHeader, FilterAddHeader, FilterSetResponseHeader, FilterAddResponseHeader:
+		kv := strings.SplitN(args, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, serrors.InvalidValueErrorf(s, "expected %s NAME=VALUE", filter.Action)
+		}
+		filter.Header, filter.Value = kv[0], kv[1]
+	case FilterRemoveHeader, FilterRemoveResponseHeader:
+		if len(args) == 0 || strings.ContainsAny(args, " =") {
+			return nil, serrors.InvalidValueErrorf(s, "expected %s NAME", filter.Action)
+		}
+		filter.Header = args
+	case FilterRedirect, FilterRewrite:
+		for _, arg := range strings.Fields(args) {
+			kv := strings.SplitN(arg, "=", 2)
+			if len(kv) != 2 {
+				return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected ARG=VALUE", arg)
+			}
+			switch {
+			case kv[0] == "host":
+				filter.Hostname = kv[1]
+			case kv[0] == "path":
+				filter.Path = kv[1]
+			case kv[0] == "scheme" && filter.Action == FilterRedirect:
+				filter.Scheme = kv[1]
+			case kv[0] == "port" && filter.Action == FilterRedirect:
+				port, err := strconv.ParseInt(kv[1], 10, 32)
+				if err != nil {
+					return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port")
+				}
+				p := int32(port)
+				filter.Port = &p
+			case kv[0] == "code" && filter.Action == FilterRedirect:
+				code, err := strconv.Atoi(kv[1])
+				if err != nil {
+					return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the status code")
+				}
+				filter.StatusCode = &code
+			default:
+				return nil, serrors.InvalidValueErrorf(s, "unexpected argument (%s) for %s", kv[0], filter.Action)
+			}
+		}
+	case FilterMirror:
+		ref, err := ParseBackendRef(args)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, s)
+		}
+		if ref.Weight != nil {
+			return nil, serrors.InvalidValueErrorf(s, "a mirror doesn't have a weight")
+		}
+		filter.Mirror = ref
+	default:
+		return nil, serrors.InvalidValueErrorf(s, "unexpected action (%s)", filter.Action)
+	}
+
+	return filter, nil
+}
+
+func (f HTTPRouteFilter) String() string {
+	switch f.Action {
+	case FilterSetHeader, FilterAddHeader, FilterSetResponseHeader, FilterAddResponseHeader:
+		return fmt.Sprintf("%s %s=%s", f.Action, f.Header, f.Value)
+	case FilterRemoveHeader, FilterRemoveResponseHeader:
+		return fmt.Sprintf("%s %s", f.Action, f.Header)
+	case FilterMirror:
+		if f.Mirror == nil {
+			return f.Action
+		}
+		return fmt.Sprintf("%s %s", f.Action, f.Mirror.String())
+	}
+
+	fields := []string{f.Action}
+	if len(f.Scheme) > 0 {
+		fields = append(fields, "scheme="+f.Scheme)
+	}
+	if len(f.Hostname) > 0 {
+		fields = append(fields, "host="+f.Hostname)
+	}
+	if f.Port != nil {
+		fields = append(fields, fmt.Sprintf("port=%d", *f.Port))
+	}
+	if len(f.Path) > 0 {
+		fields = append(fields, "path="+f.Path)
+	}
+	if f.StatusCode != nil {
+		fields = append(fields, fmt.Sprintf("code=%d", *f.StatusCode))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func (f *HTTPRouteFilter) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), f)
+	}
+
+	filter, err := ParseHTTPRouteFilter(s)
+	if err != nil {
+		return err
+	}
+	*f = *filter
+
+	return nil
+}
+
+func (f HTTPRouteFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
@@ -0,0 +1,135 @@
+package gatewayapi
+
+import (
+	"testing"
+)
+
+func TestListener(t *testing.T) {
+	for _, s := range []string{
+		"http://:80",
+		"https://*.example.com:443 cert=my-tls",
+		"https://shop.example.com:443 cert=my-tls,certs/legacy-tls tls=Terminate routes=env=prod,team=shop kinds=HTTPRoute",
+		"tls://:8443 tls=Passthrough routes=All",
+		"example.com/proto://:9000",
+	} {
+		listener, err := ParseListener("web", s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if listener.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, listener.String())
+		}
+	}
+
+	listener, err := ParseListener("https", "HTTPS://*.example.com:443 cert=my-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listener.Protocol != "HTTPS" || listener.Hostname != "*.example.com" || listener.Port != 443 {
+		t.Errorf("unexpected %#v", listener)
+	}
+
+	for _, s := range []string{"", "https:443", "https://example.com", "https://:https", "https://:443 cert", "https://:443 mode=Terminate"} {
+		if _, err := ParseListener("web", s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestParentRef(t *testing.T) {
+	for _, s := range []string{"web", "infra/web", "infra/web#https", "web:443", "infra/web#https:443"} {
+		ref, err := ParseParentRef(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if ref.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, ref.String())
+		}
+	}
+
+	for _, s := range []string{"", "infra/", "#https", "web:https"} {
+		if _, err := ParseParentRef(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestBackendRef(t *testing.T) {
+	for _, s := range []string{"api", "api:8080", "backend/api:8080 weight=90", "api weight=0"} {
+		ref, err := ParseBackendRef(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if ref.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, ref.String())
+		}
+	}
+
+	for _, s := range []string{"", ":8080", "api:http", "api 90%", "api weight=1 weight=2"} {
+		if _, err := ParseBackendRef(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestHTTPRouteMatch(t *testing.T) {
+	for _, s := range []string{
+		"/api",
+		"=/cart GET header:x-canary=true query:id~[0-9]+",
+		"~^/api/v[0-9]+",
+		"POST",
+		"header:x-env=prod header:x-team~shop-.*",
+	} {
+		match, err := ParseHTTPRouteMatch(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if match.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, match.String())
+		}
+	}
+
+	match, err := ParseHTTPRouteMatch("=/cart header:x-canary=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.PathType != PathMatchExact || match.Path != "/cart" || match.Headers[0] != (ValueMatch{Name: "x-canary", Value: "true"}) {
+		t.Errorf("unexpected %#v", match)
+	}
+
+	for _, s := range []string{"api", "GET POST", "header:x-canary", "query:=1"} {
+		if _, err := ParseHTTPRouteMatch(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestHTTPRouteFilter(t *testing.T) {
+	for _, s := range []string{
+		"set-header x-env=prod",
+		"add-response-header cache-control=no-cache, no-store",
+		"remove-header x-debug",
+		"redirect scheme=https host=shop.example.com port=443 path=/ code=301",
+		"rewrite path=/v2*",
+		"mirror shadow/store:8080",
+	} {
+		filter, err := ParseHTTPRouteFilter(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if filter.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, filter.String())
+		}
+	}
+
+	for _, s := range []string{"", "set-header x-env", "remove-header x-env=prod", "rewrite code=301", "mirror store weight=1", "drop"} {
+		if _, err := ParseHTTPRouteFilter(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package gatewayapi
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeGateway mirrors gateway.networking.k8s.io Gateway.
+type kubeGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeGatewaySpec `json:"spec,omitempty"`
+}
+
+type kubeGatewaySpec struct {
+	GatewayClassName string               `json:"gatewayClassName"`
+	Listeners        []kubeListener       `json:"listeners,omitempty"`
+	Addresses        []kubeGatewayAddress `json:"addresses,omitempty"`
+}
+
+type kubeListener struct {
+	Name          string             `json:"name"`
+	Hostname      *string            `json:"hostname,omitempty"`
+	Port          int32              `json:"port"`
+	Protocol      string             `json:"protocol"`
+	TLS           *kubeGatewayTLS    `json:"tls,omitempty"`
+	AllowedRoutes *kubeAllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+type kubeGatewayTLS struct {
+	Mode            *string                     `json:"mode,omitempty"`
+	CertificateRefs []kubeSecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+type kubeSecretObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+type kubeAllowedRoutes struct {
+	Namespaces *kubeRouteNamespaces `json:"namespaces,omitempty"`
+	Kinds      []kubeRouteGroupKind `json:"kinds,omitempty"`
+}
+
+type kubeRouteNamespaces struct {
+	From     *string               `json:"from,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+type kubeRouteGroupKind struct {
+	Group *string `json:"group,omitempty"`
+	Kind  string  `json:"kind"`
+}
+
+type kubeGatewayAddress struct {
+	Type  *string `json:"type,omitempty"`
+	Value string  `json:"value"`
+}
+
+const (
+	addressTypeIP      = "IPAddress"
+	routesFromSelector = "Selector"
+)
+
+func Convert_Kube_Gateway_to_Koki(kubeObj *unstructured.Unstructured) (*GatewayWrapper, error) {
+	kube := &kubeGateway{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &Gateway{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.Class = kube.Spec.GatewayClassName
+	for _, kubeListener := range kube.Spec.Listeners {
+		listener, err := convertListener(kubeListener)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "listener %s", kubeListener.Name)
+		}
+		koki.Listeners = append(koki.Listeners, *listener)
+	}
+
+	for _, kubeAddress := range kube.Spec.Addresses {
+		if kubeAddress.Type == nil || *kubeAddress.Type == addressTypeIP {
+			koki.Addresses = append(koki.Addresses, kubeAddress.Value)
+		} else {
+			koki.Addresses = append(koki.Addresses, fmt.Sprintf("%s=%s", *kubeAddress.Type, kubeAddress.Value))
+		}
+	}
+
+	return &GatewayWrapper{
+		Gateway: *koki,
+	}, nil
+}
+
+func convertListener(kubeListener kubeListener) (*Listener, error) {
+	listener := &Listener{
+		Name:     kubeListener.Name,
+		Protocol: kubeListener.Protocol,
+		Port:     kubeListener.Port,
+	}
+	if kubeListener.Hostname != nil {
+		listener.Hostname = *kubeListener.Hostname
+	}
+
+	if kubeTLS := kubeListener.TLS; kubeTLS != nil {
+		if kubeTLS.Mode != nil {
+			listener.TLSMode = *kubeTLS.Mode
+		}
+		for _, kubeRef := range kubeTLS.CertificateRefs {
+			if kubeRef.Group != nil && len(*kubeRef.Group) > 0 || kubeRef.Kind != nil && *kubeRef.Kind != "Secret" {
+				return nil, serrors.InvalidInstanceErrorf(kubeRef, "only Secrets are supported as certificates")
+			}
+			if kubeRef.Namespace != nil {
+				listener.Certs = append(listener.Certs, fmt.Sprintf("%s/%s", *kubeRef.Namespace, kubeRef.Name))
+			} else {
+				listener.Certs = append(listener.Certs, kubeRef.Name)
+			}
+		}
+	}
+
+	if kubeRoutes := kubeListener.AllowedRoutes; kubeRoutes != nil {
+		if kubeNamespaces := kubeRoutes.Namespaces; kubeNamespaces != nil {
+			if kubeNamespaces.From != nil {
+				listener.RoutesFrom = *kubeNamespaces.From
+			}
+			if kubeSelector := kubeNamespaces.Selector; kubeSelector != nil {
+				if len(kubeSelector.MatchExpressions) > 0 {
+					return nil, serrors.InvalidInstanceErrorf(kubeSelector, "only matchLabels are supported")
+				}
+				listener.RoutesSelector = kubeSelector.MatchLabels
+			}
+		}
+		for _, kubeKind := range kubeRoutes.Kinds {
+			if kubeKind.Group != nil && *kubeKind.Group != Group {
+				return nil, serrors.InvalidInstanceErrorf(kubeKind, "only route kinds of %s are supported", Group)
+			}
+			listener.RouteKinds = append(listener.RouteKinds, kubeKind.Kind)
+		}
+	}
+
+	return listener, nil
+}
+
+func Convert_Koki_Gateway_to_Kube(gateway *GatewayWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeGateway{}
+	koki := &gateway.Gateway
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	kube.APIVersion = revertVersion(koki.Version)
+	kube.Kind = "Gateway"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.GatewayClassName = koki.Class
+	for _, listener := range koki.Listeners {
+		kube.Spec.Listeners = append(kube.Spec.Listeners, revertListener(listener))
+	}
+
+	for _, address := range koki.Addresses {
+		addressType := addressTypeIP
+		value := address
+		if kv := strings.SplitN(address, "=", 2); len(kv) == 2 {
+			addressType, value = kv[0], kv[1]
+		}
+		kube.Spec.Addresses = append(kube.Spec.Addresses, kubeGatewayAddress{
+			Type:  &addressType,
+			Value: value,
+		})
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
+
+func revertListener(listener Listener) kubeListener {
+	kubeListener := kubeListener{
+		Name:     listener.Name,
+		Port:     listener.Port,
+		Protocol: listener.Protocol,
+	}
+	if len(listener.Hostname) > 0 {
+		hostname := listener.Hostname
+		kubeListener.Hostname = &hostname
+	}
+
+	if len(listener.TLSMode) > 0 || len(listener.Certs) > 0 {
+		kubeTLS := &kubeGatewayTLS{}
+		if len(listener.TLSMode) > 0 {
+			mode := listener.TLSMode
+			kubeTLS.Mode = &mode
+		}
+		for _, cert := range listener.Certs {
+			kubeRef := kubeSecretObjectReference{Name: cert}
+			if i := strings.Index(cert, "/"); i >= 0 {
+				namespace := cert[:i]
+				kubeRef.Namespace = &namespace
+				kubeRef.Name = cert[i+1:]
+			}
+			kubeTLS.CertificateRefs = append(kubeTLS.CertificateRefs, kubeRef)
+		}
+		kubeListener.TLS = kubeTLS
+	}
+
+	if len(listener.RoutesFrom) > 0 || len(listener.RoutesSelector) > 0 || len(listener.RouteKinds) > 0 {
+		kubeRoutes := &kubeAllowedRoutes{}
+		if len(listener.RoutesSelector) > 0 {
+			from := routesFromSelector
+			kubeRoutes.Namespaces = &kubeRouteNamespaces{
+				From: &from,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: listener.RoutesSelector,
+				},
+			}
+		} else if len(listener.RoutesFrom) > 0 {
+			from := listener.RoutesFrom
+			kubeRoutes.Namespaces = &kubeRouteNamespaces{
+				From: &from,
+			}
+		}
+		for _, kind := range listener.RouteKinds {
+			kubeRoutes.Kinds = append(kubeRoutes.Kinds, kubeRouteGroupKind{Kind: kind})
+		}
+		kubeListener.AllowedRoutes = kubeRoutes
+	}
+
+	return kubeListener
+}
@@ -0,0 +1,66 @@
+package gatewayapi
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/short/crdplugin"
+)
+
+/*
+
+Gatewayapi adds short syntax for the Kubernetes Gateway API's Gateway and HTTPRoute.
+Importing the package registers them with crdplugin. Short always includes it, without
+a build tag.
+
+A listener is written as {NAME: "PROTOCOL://[HOSTNAME]:PORT[ OPTION...]"}, and the
+matches, filters and backends of an HTTPRoute rule are compact strings, e.g.
+"/api GET header:x-canary=true", "set-header x-env=prod" and "api:8080 weight=90".
+Objects with fields that short syntax doesn't cover (e.g. non-Service backends) fail to
+convert instead of losing them.
+
+*/
+
+// Group is the API group of the resources.
+const Group = "gateway.networking.k8s.io"
+
+// Versions are the versions of Group that are read. They all have the same fields.
+var Versions = []string{"v1alpha2", "v1beta1", "v1"}
+
+// defaultVersion is written when short syntax doesn't give a version.
+const defaultVersion = Group + "/v1"
+
+func init() {
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "Gateway"},
+		Versions:  Versions,
+		Key:       "gateway",
+		NewKoki:   func() interface{} { return &GatewayWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_Gateway_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_Gateway_to_Kube(kokiObj.(*GatewayWrapper))
+		},
+	})
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "HTTPRoute"},
+		Versions:  Versions,
+		Key:       "http_route",
+		NewKoki:   func() interface{} { return &HTTPRouteWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_HTTPRoute_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_HTTPRoute_to_Kube(kokiObj.(*HTTPRouteWrapper))
+		},
+	})
+}
+
+func revertVersion(version string) string {
+	if len(version) == 0 {
+		return defaultVersion
+	}
+
+	return version
+}
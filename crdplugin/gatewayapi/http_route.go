@@ -0,0 +1,588 @@
+package gatewayapi
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeHTTPRoute mirrors gateway.networking.k8s.io HTTPRoute.
+type kubeHTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeHTTPRouteSpec `json:"spec,omitempty"`
+}
+
+type kubeHTTPRouteSpec struct {
+	ParentRefs []kubeParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string              `json:"hostnames,omitempty"`
+	Rules      []kubeHTTPRouteRule   `json:"rules,omitempty"`
+}
+
+type kubeParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+type kubeHTTPRouteRule struct {
+	Name        *string                `json:"name,omitempty"`
+	Matches     []kubeHTTPRouteMatch   `json:"matches,omitempty"`
+	Filters     []kubeHTTPRouteFilter  `json:"filters,omitempty"`
+	BackendRefs []kubeHTTPBackendRef   `json:"backendRefs,omitempty"`
+	Timeouts    *kubeHTTPRouteTimeouts `json:"timeouts,omitempty"`
+}
+
+type kubeHTTPRouteMatch struct {
+	Path        *kubeHTTPPathMatch   `json:"path,omitempty"`
+	Headers     []kubeHTTPValueMatch `json:"headers,omitempty"`
+	QueryParams []kubeHTTPValueMatch `json:"queryParams,omitempty"`
+	Method      *string              `json:"method,omitempty"`
+}
+
+type kubeHTTPPathMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// kubeHTTPValueMatch is a header or query parameter match.
+type kubeHTTPValueMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+type kubeHTTPRouteFilter struct {
+	Type                   string                   `json:"type"`
+	RequestHeaderModifier  *kubeHTTPHeaderFilter    `json:"requestHeaderModifier,omitempty"`
+	ResponseHeaderModifier *kubeHTTPHeaderFilter    `json:"responseHeaderModifier,omitempty"`
+	RequestRedirect        *kubeHTTPRequestRedirect `json:"requestRedirect,omitempty"`
+	URLRewrite             *kubeHTTPURLRewrite      `json:"urlRewrite,omitempty"`
+	RequestMirror          *kubeHTTPRequestMirror   `json:"requestMirror,omitempty"`
+}
+
+type kubeHTTPHeaderFilter struct {
+	Set    []kubeHTTPHeader `json:"set,omitempty"`
+	Add    []kubeHTTPHeader `json:"add,omitempty"`
+	Remove []string         `json:"remove,omitempty"`
+}
+
+type kubeHTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type kubeHTTPRequestRedirect struct {
+	Scheme     *string               `json:"scheme,omitempty"`
+	Hostname   *string               `json:"hostname,omitempty"`
+	Path       *kubeHTTPPathModifier `json:"path,omitempty"`
+	Port       *int32                `json:"port,omitempty"`
+	StatusCode *int                  `json:"statusCode,omitempty"`
+}
+
+type kubeHTTPURLRewrite struct {
+	Hostname *string               `json:"hostname,omitempty"`
+	Path     *kubeHTTPPathModifier `json:"path,omitempty"`
+}
+
+type kubeHTTPPathModifier struct {
+	Type               string  `json:"type"`
+	ReplaceFullPath    *string `json:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch *string `json:"replacePrefixMatch,omitempty"`
+}
+
+type kubeHTTPRequestMirror struct {
+	BackendRef kubeBackendObjectReference `json:"backendRef"`
+}
+
+type kubeBackendObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+}
+
+type kubeHTTPBackendRef struct {
+	kubeBackendObjectReference `json:",inline"`
+
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type kubeHTTPRouteTimeouts struct {
+	Request        *string `json:"request,omitempty"`
+	BackendRequest *string `json:"backendRequest,omitempty"`
+}
+
+const (
+	filterTypeRequestHeader  = "RequestHeaderModifier"
+	filterTypeResponseHeader = "ResponseHeaderModifier"
+	filterTypeRedirect       = "RequestRedirect"
+	filterTypeRewrite        = "URLRewrite"
+	filterTypeMirror         = "RequestMirror"
+
+	pathModifierFull   = "ReplaceFullPath"
+	pathModifierPrefix = "ReplacePrefixMatch"
+)
+
+func Convert_Kube_HTTPRoute_to_Koki(kubeObj *unstructured.Unstructured) (*HTTPRouteWrapper, error) {
+	kube := &kubeHTTPRoute{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &HTTPRoute{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	for _, kubeRef := range kube.Spec.ParentRefs {
+		if kubeRef.Group != nil && *kubeRef.Group != Group || kubeRef.Kind != nil && *kubeRef.Kind != "Gateway" {
+			return nil, serrors.InvalidInstanceErrorf(kubeRef, "only Gateways are supported as parents")
+		}
+		ref := ParentRef{Name: kubeRef.Name}
+		if kubeRef.Namespace != nil {
+			ref.Namespace = *kubeRef.Namespace
+		}
+		if kubeRef.SectionName != nil {
+			ref.Section = *kubeRef.SectionName
+		}
+		if kubeRef.Port != nil {
+			ref.Port = *kubeRef.Port
+		}
+		koki.Gateways = append(koki.Gateways, ref)
+	}
+
+	koki.Hostnames = kube.Spec.Hostnames
+	for i, kubeRule := range kube.Spec.Rules {
+		rule, err := convertHTTPRouteRule(kubeRule)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "rules[%d]", i)
+		}
+		koki.Rules = append(koki.Rules, *rule)
+	}
+
+	return &HTTPRouteWrapper{
+		HTTPRoute: *koki,
+	}, nil
+}
+
+func convertHTTPRouteRule(kubeRule kubeHTTPRouteRule) (*HTTPRouteRule, error) {
+	rule := &HTTPRouteRule{}
+	if kubeRule.Name != nil {
+		rule.Name = *kubeRule.Name
+	}
+
+	for _, kubeMatch := range kubeRule.Matches {
+		match, err := convertHTTPRouteMatch(kubeMatch)
+		if err != nil {
+			return nil, err
+		}
+		rule.Matches = append(rule.Matches, *match)
+	}
+
+	for _, kubeFilter := range kubeRule.Filters {
+		filters, err := convertHTTPRouteFilter(kubeFilter)
+		if err != nil {
+			return nil, err
+		}
+		rule.Filters = append(rule.Filters, filters...)
+	}
+
+	for _, kubeRef := range kubeRule.BackendRefs {
+		ref, err := convertBackendRef(kubeRef.kubeBackendObjectReference)
+		if err != nil {
+			return nil, err
+		}
+		ref.Weight = kubeRef.Weight
+		rule.Backends = append(rule.Backends, *ref)
+	}
+
+	if kubeTimeouts := kubeRule.Timeouts; kubeTimeouts != nil {
+		if kubeTimeouts.Request != nil {
+			rule.Timeout = *kubeTimeouts.Request
+		}
+		if kubeTimeouts.BackendRequest != nil {
+			rule.BackendTimeout = *kubeTimeouts.BackendRequest
+		}
+	}
+
+	return rule, nil
+}
+
+func convertHTTPRouteMatch(kubeMatch kubeHTTPRouteMatch) (*HTTPRouteMatch, error) {
+	match := &HTTPRouteMatch{}
+	if kubePath := kubeMatch.Path; kubePath != nil {
+		match.PathType = PathMatchPrefix
+		if kubePath.Type != nil {
+			match.PathType = *kubePath.Type
+		}
+		match.Path = "/"
+		if kubePath.Value != nil {
+			match.Path = *kubePath.Value
+		}
+		switch match.PathType {
+		case PathMatchPrefix, PathMatchExact, PathMatchRegex:
+		default:
+			return nil, serrors.InvalidInstanceErrorf(kubePath, "unexpected path match type")
+		}
+	}
+	if kubeMatch.Method != nil {
+		match.Method = *kubeMatch.Method
+	}
+
+	var err error
+	match.Headers, err = convertValueMatches(kubeMatch.Headers)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "headers")
+	}
+	match.QueryParams, err = convertValueMatches(kubeMatch.QueryParams)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "queryParams")
+	}
+
+	if strings.ContainsAny(match.Path, " \t\n") {
+		return nil, serrors.InvalidInstanceErrorf(kubeMatch.Path, "paths with spaces can't be written in short syntax")
+	}
+
+	return match, nil
+}
+
+func convertValueMatches(kubeMatches []kubeHTTPValueMatch) ([]ValueMatch, error) {
+	var matches []ValueMatch
+	for _, kubeMatch := range kubeMatches {
+		if strings.ContainsAny(kubeMatch.Value, " \t\n") {
+			return nil, serrors.InvalidInstanceErrorf(kubeMatch, "values with spaces can't be written in short syntax")
+		}
+		match := ValueMatch{
+			Name:  kubeMatch.Name,
+			Value: kubeMatch.Value,
+		}
+		if kubeMatch.Type != nil {
+			switch *kubeMatch.Type {
+			case PathMatchExact:
+			case PathMatchRegex:
+				match.Regex = true
+			default:
+				return nil, serrors.InvalidInstanceErrorf(kubeMatch, "unexpected match type")
+			}
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+func convertHTTPRouteFilter(kubeFilter kubeHTTPRouteFilter) ([]HTTPRouteFilter, error) {
+	switch kubeFilter.Type {
+	case filterTypeRequestHeader:
+		if kubeFilter.RequestHeaderModifier != nil {
+			return convertHeaderFilter(*kubeFilter.RequestHeaderModifier, FilterSetHeader, FilterAddHeader, FilterRemoveHeader), nil
+		}
+	case filterTypeResponseHeader:
+		if kubeFilter.ResponseHeaderModifier != nil {
+			return convertHeaderFilter(*kubeFilter.ResponseHeaderModifier, FilterSetResponseHeader, FilterAddResponseHeader, FilterRemoveResponseHeader), nil
+		}
+	case filterTypeRedirect:
+		if kubeRedirect := kubeFilter.RequestRedirect; kubeRedirect != nil {
+			filter := HTTPRouteFilter{
+				Action:     FilterRedirect,
+				Port:       kubeRedirect.Port,
+				StatusCode: kubeRedirect.StatusCode,
+			}
+			if kubeRedirect.Scheme != nil {
+				filter.Scheme = *kubeRedirect.Scheme
+			}
+			if kubeRedirect.Hostname != nil {
+				filter.Hostname = *kubeRedirect.Hostname
+			}
+			path, err := convertPathModifier(kubeRedirect.Path)
+			if err != nil {
+				return nil, err
+			}
+			filter.Path = path
+			return []HTTPRouteFilter{filter}, nil
+		}
+	case filterTypeRewrite:
+		if kubeRewrite := kubeFilter.URLRewrite; kubeRewrite != nil {
+			filter := HTTPRouteFilter{Action: FilterRewrite}
+			if kubeRewrite.Hostname != nil {
+				filter.Hostname = *kubeRewrite.Hostname
+			}
+			path, err := convertPathModifier(kubeRewrite.Path)
+			if err != nil {
+				return nil, err
+			}
+			filter.Path = path
+			return []HTTPRouteFilter{filter}, nil
+		}
+	case filterTypeMirror:
+		if kubeFilter.RequestMirror != nil {
+			ref, err := convertBackendRef(kubeFilter.RequestMirror.BackendRef)
+			if err != nil {
+				return nil, err
+			}
+			return []HTTPRouteFilter{{Action: FilterMirror, Mirror: ref}}, nil
+		}
+	default:
+		return nil, serrors.InvalidInstanceErrorf(kubeFilter, "unsupported filter type")
+	}
+
+	return nil, serrors.InvalidInstanceErrorf(kubeFilter, "expected the settings of the %s filter", kubeFilter.Type)
+}
+
+func convertHeaderFilter(kubeFilter kubeHTTPHeaderFilter, set, add, remove string) []HTTPRouteFilter {
+	filters := []HTTPRouteFilter{}
+	for _, header := range kubeFilter.Set {
+		filters = append(filters, HTTPRouteFilter{Action: set, Header: header.Name, Value: header.Value})
+	}
+	for _, header := range kubeFilter.Add {
+		filters = append(filters, HTTPRouteFilter{Action: add, Header: header.Name, Value: header.Value})
+	}
+	for _, name := range kubeFilter.Remove {
+		filters = append(filters, HTTPRouteFilter{Action: remove, Header: name})
+	}
+
+	return filters
+}
+
+func convertPathModifier(kubeModifier *kubeHTTPPathModifier) (string, error) {
+	if kubeModifier == nil {
+		return "", nil
+	}
+
+	switch {
+	case kubeModifier.Type == pathModifierFull && kubeModifier.ReplaceFullPath != nil:
+		return *kubeModifier.ReplaceFullPath, nil
+	case kubeModifier.Type == pathModifierPrefix && kubeModifier.ReplacePrefixMatch != nil:
+		return *kubeModifier.ReplacePrefixMatch + "*", nil
+	}
+
+	return "", serrors.InvalidInstanceErrorf(kubeModifier, "expected %s or %s", pathModifierFull, pathModifierPrefix)
+}
+
+func convertBackendRef(kubeRef kubeBackendObjectReference) (*BackendRef, error) {
+	if kubeRef.Group != nil && len(*kubeRef.Group) > 0 || kubeRef.Kind != nil && *kubeRef.Kind != "Service" {
+		return nil, serrors.InvalidInstanceErrorf(kubeRef, "only Services are supported as backends")
+	}
+
+	ref := &BackendRef{Name: kubeRef.Name}
+	if kubeRef.Namespace != nil {
+		ref.Namespace = *kubeRef.Namespace
+	}
+	if kubeRef.Port != nil {
+		ref.Port = *kubeRef.Port
+	}
+
+	return ref, nil
+}
+
+func Convert_Koki_HTTPRoute_to_Kube(route *HTTPRouteWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeHTTPRoute{}
+	koki := &route.HTTPRoute
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	kube.APIVersion = revertVersion(koki.Version)
+	kube.Kind = "HTTPRoute"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	for _, ref := range koki.Gateways {
+		kubeRef := kubeParentReference{
+			Name:        ref.Name,
+			Namespace:   optionalString(ref.Namespace),
+			SectionName: optionalString(ref.Section),
+		}
+		if ref.Port != 0 {
+			port := ref.Port
+			kubeRef.Port = &port
+		}
+		kube.Spec.ParentRefs = append(kube.Spec.ParentRefs, kubeRef)
+	}
+
+	kube.Spec.Hostnames = koki.Hostnames
+	for _, rule := range koki.Rules {
+		kube.Spec.Rules = append(kube.Spec.Rules, revertHTTPRouteRule(rule))
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
+
+func revertHTTPRouteRule(rule HTTPRouteRule) kubeHTTPRouteRule {
+	kubeRule := kubeHTTPRouteRule{
+		Name: optionalString(rule.Name),
+	}
+
+	for _, match := range rule.Matches {
+		kubeRule.Matches = append(kubeRule.Matches, revertHTTPRouteMatch(match))
+	}
+
+	// The header actions of a rule share one filter per direction, which is where the first of them was.
+	var requestHeaders, responseHeaders *kubeHTTPHeaderFilter
+	for _, filter := range rule.Filters {
+		switch filter.Action {
+		case FilterSetHeader, FilterAddHeader, FilterRemoveHeader:
+			if requestHeaders == nil {
+				requestHeaders = &kubeHTTPHeaderFilter{}
+				kubeRule.Filters = append(kubeRule.Filters, kubeHTTPRouteFilter{
+					Type:                  filterTypeRequestHeader,
+					RequestHeaderModifier: requestHeaders,
+				})
+			}
+			revertHeaderFilter(filter, requestHeaders)
+		case FilterSetResponseHeader, FilterAddResponseHeader, FilterRemoveResponseHeader:
+			if responseHeaders == nil {
+				responseHeaders = &kubeHTTPHeaderFilter{}
+				kubeRule.Filters = append(kubeRule.Filters, kubeHTTPRouteFilter{
+					Type:                   filterTypeResponseHeader,
+					ResponseHeaderModifier: responseHeaders,
+				})
+			}
+			revertHeaderFilter(filter, responseHeaders)
+		case FilterRedirect:
+			kubeRule.Filters = append(kubeRule.Filters, kubeHTTPRouteFilter{
+				Type: filterTypeRedirect,
+				RequestRedirect: &kubeHTTPRequestRedirect{
+					Scheme:     optionalString(filter.Scheme),
+					Hostname:   optionalString(filter.Hostname),
+					Path:       revertPathModifier(filter.Path),
+					Port:       filter.Port,
+					StatusCode: filter.StatusCode,
+				},
+			})
+		case FilterRewrite:
+			kubeRule.Filters = append(kubeRule.Filters, kubeHTTPRouteFilter{
+				Type: filterTypeRewrite,
+				URLRewrite: &kubeHTTPURLRewrite{
+					Hostname: optionalString(filter.Hostname),
+					Path:     revertPathModifier(filter.Path),
+				},
+			})
+		case FilterMirror:
+			kubeFilter := kubeHTTPRouteFilter{
+				Type:          filterTypeMirror,
+				RequestMirror: &kubeHTTPRequestMirror{},
+			}
+			if filter.Mirror != nil {
+				kubeFilter.RequestMirror.BackendRef = revertBackendRef(*filter.Mirror)
+			}
+			kubeRule.Filters = append(kubeRule.Filters, kubeFilter)
+		}
+	}
+
+	for _, ref := range rule.Backends {
+		kubeRule.BackendRefs = append(kubeRule.BackendRefs, kubeHTTPBackendRef{
+			kubeBackendObjectReference: revertBackendRef(ref),
+			Weight:                     ref.Weight,
+		})
+	}
+
+	if len(rule.Timeout) > 0 || len(rule.BackendTimeout) > 0 {
+		kubeRule.Timeouts = &kubeHTTPRouteTimeouts{
+			Request:        optionalString(rule.Timeout),
+			BackendRequest: optionalString(rule.BackendTimeout),
+		}
+	}
+
+	return kubeRule
+}
+
+func revertHTTPRouteMatch(match HTTPRouteMatch) kubeHTTPRouteMatch {
+	kubeMatch := kubeHTTPRouteMatch{
+		Method: optionalString(match.Method),
+	}
+	if len(match.PathType) > 0 {
+		pathType, path := match.PathType, match.Path
+		kubeMatch.Path = &kubeHTTPPathMatch{
+			Type:  &pathType,
+			Value: &path,
+		}
+	}
+	kubeMatch.Headers = revertValueMatches(match.Headers)
+	kubeMatch.QueryParams = revertValueMatches(match.QueryParams)
+
+	return kubeMatch
+}
+
+func revertValueMatches(matches []ValueMatch) []kubeHTTPValueMatch {
+	var kubeMatches []kubeHTTPValueMatch
+	for _, match := range matches {
+		matchType := PathMatchExact
+		if match.Regex {
+			matchType = PathMatchRegex
+		}
+		kubeMatches = append(kubeMatches, kubeHTTPValueMatch{
+			Type:  &matchType,
+			Name:  match.Name,
+			Value: match.Value,
+		})
+	}
+
+	return kubeMatches
+}
+
+func revertHeaderFilter(filter HTTPRouteFilter, kubeFilter *kubeHTTPHeaderFilter) {
+	header := kubeHTTPHeader{Name: filter.Header, Value: filter.Value}
+	switch filter.Action {
+	case FilterSetHeader, FilterSetResponseHeader:
+		kubeFilter.Set = append(kubeFilter.Set, header)
+	case FilterAddHeader, FilterAddResponseHeader:
+		kubeFilter.Add = append(kubeFilter.Add, header)
+	case FilterRemoveHeader, FilterRemoveResponseHeader:
+		kubeFilter.Remove = append(kubeFilter.Remove, filter.Header)
+	}
+}
+
+func revertPathModifier(path string) *kubeHTTPPathModifier {
+	if len(path) == 0 {
+		return nil
+	}
+
+	if strings.HasSuffix(path, "*") {
+		prefix := strings.TrimSuffix(path, "*")
+		return &kubeHTTPPathModifier{
+			Type:               pathModifierPrefix,
+			ReplacePrefixMatch: &prefix,
+		}
+	}
+
+	return &kubeHTTPPathModifier{
+		Type:            pathModifierFull,
+		ReplaceFullPath: &path,
+	}
+}
+
+func revertBackendRef(ref BackendRef) kubeBackendObjectReference {
+	kubeRef := kubeBackendObjectReference{
+		Name:      ref.Name,
+		Namespace: optionalString(ref.Namespace),
+	}
+	if ref.Port != 0 {
+		port := ref.Port
+		kubeRef.Port = &port
+	}
+
+	return kubeRef
+}
+
+func optionalString(s string) *string {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return &s
+}
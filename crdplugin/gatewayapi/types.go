@@ -0,0 +1,57 @@
+package gatewayapi
+
+import (
+	"github.com/koki/short/types"
+)
+
+type GatewayWrapper struct {
+	Gateway Gateway `json:"gateway"`
+}
+
+type Gateway struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	// Class is the name of the GatewayClass.
+	Class     string     `json:"class,omitempty"`
+	Listeners []Listener `json:"listeners,omitempty"`
+	// Addresses are "VALUE" for an IP address, or "TYPE=VALUE", e.g. "Hostname=lb.example.com".
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type HTTPRouteWrapper struct {
+	HTTPRoute HTTPRoute `json:"http_route"`
+}
+
+type HTTPRoute struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	// Gateways are the Gateways (or their listeners) that the route attaches to.
+	Gateways  []ParentRef     `json:"gateways,omitempty"`
+	Hostnames []string        `json:"hostnames,omitempty"`
+	Rules     []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+type HTTPRouteRule struct {
+	Name string `json:"name,omitempty"`
+	// Matches are alternatives: a request that satisfies any of them is routed by the rule.
+	Matches  []HTTPRouteMatch  `json:"match,omitempty"`
+	Filters  []HTTPRouteFilter `json:"filters,omitempty"`
+	Backends []BackendRef      `json:"backends,omitempty"`
+
+	// Timeout is how long the gateway waits for the whole request, e.g. "10s".
+	Timeout string `json:"timeout,omitempty"`
+	// BackendTimeout is how long the gateway waits for each request to a backend.
+	BackendTimeout string `json:"backend_timeout,omitempty"`
+}
@@ -0,0 +1,144 @@
+package istio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// Destination is a service (and optionally its port and subset) that traffic is routed to,
+// written as "HOST[:PORT][/SUBSET][ WEIGHT%]", e.g. "reviews:9080/v2 25%".
+type Destination struct {
+	Host   string
+	Port   uint32
+	Subset string
+	Weight *int32
+}
+
+func ParseDestination(s string) (*Destination, error) {
+	d := &Destination{}
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, serrors.InvalidValueErrorf(s, "expected HOST[:PORT][/SUBSET][ WEIGHT%%]")
+	}
+
+	if len(fields) == 2 {
+		if !strings.HasSuffix(fields[1], "%") {
+			return nil, serrors.InvalidValueErrorf(s, "expected the weight to be a percentage, e.g. 25%%")
+		}
+		weight, err := strconv.ParseInt(strings.TrimSuffix(fields[1], "%"), 10, 32)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the weight")
+		}
+		w := int32(weight)
+		d.Weight = &w
+	}
+
+	hostPort := fields[0]
+	if i := strings.Index(hostPort, "/"); i >= 0 {
+		d.Subset = hostPort[i+1:]
+		hostPort = hostPort[:i]
+	}
+	if i := strings.Index(hostPort, ":"); i >= 0 {
+		port, err := strconv.ParseUint(hostPort[i+1:], 10, 32)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port")
+		}
+		d.Port = uint32(port)
+		hostPort = hostPort[:i]
+	}
+	d.Host = hostPort
+	if len(d.Host) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected a host")
+	}
+
+	return d, nil
+}
+
+func (d Destination) String() string {
+	s := d.Host
+	if d.Port != 0 {
+		s = fmt.Sprintf("%s:%d", s, d.Port)
+	}
+	if len(d.Subset) > 0 {
+		s = fmt.Sprintf("%s/%s", s, d.Subset)
+	}
+	if d.Weight != nil {
+		s = fmt.Sprintf("%s %d%%", s, *d.Weight)
+	}
+
+	return s
+}
+
+func (d *Destination) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), d)
+	}
+
+	destination, err := ParseDestination(s)
+	if err != nil {
+		return err
+	}
+	*d = *destination
+
+	return nil
+}
+
+func (d Destination) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// GatewayPort is a port that a Gateway listens on, written as "NAME:NUMBER/PROTOCOL", e.g. "https:443/HTTPS".
+type GatewayPort struct {
+	Name     string
+	Number   uint32
+	Protocol string
+}
+
+func ParseGatewayPort(s string) (*GatewayPort, error) {
+	slash := strings.LastIndex(s, "/")
+	colon := strings.Index(s, ":")
+	if slash < 0 || colon < 0 || colon > slash {
+		return nil, serrors.InvalidValueErrorf(s, "expected NAME:NUMBER/PROTOCOL")
+	}
+
+	number, err := strconv.ParseUint(s[colon+1:slash], 10, 32)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the port number")
+	}
+
+	return &GatewayPort{
+		Name:     s[:colon],
+		Number:   uint32(number),
+		Protocol: s[slash+1:],
+	}, nil
+}
+
+func (p GatewayPort) String() string {
+	return fmt.Sprintf("%s:%d/%s", p.Name, p.Number, p.Protocol)
+}
+
+func (p *GatewayPort) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), p)
+	}
+
+	port, err := ParseGatewayPort(s)
+	if err != nil {
+		return err
+	}
+	*p = *port
+
+	return nil
+}
+
+func (p GatewayPort) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
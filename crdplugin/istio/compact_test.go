@@ -0,0 +1,43 @@
+package istio
+
+import (
+	"testing"
+)
+
+func TestDestination(t *testing.T) {
+	for _, s := range []string{"reviews", "reviews:9080", "reviews/v2", "reviews:9080/v2 25%", "reviews 0%"} {
+		destination, err := ParseDestination(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if destination.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, destination.String())
+		}
+	}
+
+	for _, s := range []string{"", ":9080", "reviews:http", "reviews 25", "reviews/v2 25% 75%"} {
+		if _, err := ParseDestination(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestGatewayPort(t *testing.T) {
+	port, err := ParseGatewayPort("https:443/HTTPS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *port != (GatewayPort{Name: "https", Number: 443, Protocol: "HTTPS"}) {
+		t.Errorf("unexpected %#v", port)
+	}
+	if port.String() != "https:443/HTTPS" {
+		t.Errorf("round-trip failed: got %s", port.String())
+	}
+
+	for _, s := range []string{"443/HTTPS", "https:443", "https:port/HTTPS"} {
+		if _, err := ParseGatewayPort(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
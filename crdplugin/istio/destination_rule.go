@@ -0,0 +1,161 @@
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+)
+
+// kubeDestinationRule mirrors networking.istio.io DestinationRule.
+type kubeDestinationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeDestinationRuleSpec `json:"spec,omitempty"`
+}
+
+type kubeDestinationRuleSpec struct {
+	Host          string             `json:"host,omitempty"`
+	TrafficPolicy *kubeTrafficPolicy `json:"trafficPolicy,omitempty"`
+	Subsets       []kubeSubset       `json:"subsets,omitempty"`
+	ExportTo      []string           `json:"exportTo,omitempty"`
+}
+
+type kubeTrafficPolicy struct {
+	LoadBalancer     *kubeLoadBalancerSettings `json:"loadBalancer,omitempty"`
+	ConnectionPool   map[string]interface{}    `json:"connectionPool,omitempty"`
+	OutlierDetection map[string]interface{}    `json:"outlierDetection,omitempty"`
+	TLS              *kubeClientTLSSettings    `json:"tls,omitempty"`
+}
+
+type kubeLoadBalancerSettings struct {
+	Simple string `json:"simple,omitempty"`
+}
+
+type kubeClientTLSSettings struct {
+	Mode              string   `json:"mode,omitempty"`
+	ClientCertificate string   `json:"clientCertificate,omitempty"`
+	PrivateKey        string   `json:"privateKey,omitempty"`
+	CACertificates    string   `json:"caCertificates,omitempty"`
+	CredentialName    string   `json:"credentialName,omitempty"`
+	SubjectAltNames   []string `json:"subjectAltNames,omitempty"`
+	SNI               string   `json:"sni,omitempty"`
+}
+
+type kubeSubset struct {
+	Name          string             `json:"name"`
+	Labels        map[string]string  `json:"labels,omitempty"`
+	TrafficPolicy *kubeTrafficPolicy `json:"trafficPolicy,omitempty"`
+}
+
+func Convert_Kube_DestinationRule_to_Koki(kubeObj *unstructured.Unstructured) (*DestinationRuleWrapper, error) {
+	kube := &kubeDestinationRule{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &DestinationRule{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.Host = kube.Spec.Host
+	koki.ExportTo = kube.Spec.ExportTo
+	koki.TrafficPolicy = convertTrafficPolicy(kube.Spec.TrafficPolicy)
+	for _, kubeSubset := range kube.Spec.Subsets {
+		koki.Subsets = append(koki.Subsets, Subset{
+			Name:          kubeSubset.Name,
+			Labels:        kubeSubset.Labels,
+			TrafficPolicy: convertTrafficPolicy(kubeSubset.TrafficPolicy),
+		})
+	}
+
+	return &DestinationRuleWrapper{
+		DestinationRule: *koki,
+	}, nil
+}
+
+func convertTrafficPolicy(kubePolicy *kubeTrafficPolicy) *TrafficPolicy {
+	if kubePolicy == nil {
+		return nil
+	}
+
+	policy := &TrafficPolicy{
+		ConnectionPool:   kubePolicy.ConnectionPool,
+		OutlierDetection: kubePolicy.OutlierDetection,
+	}
+	if kubePolicy.LoadBalancer != nil {
+		policy.LoadBalancer = kubePolicy.LoadBalancer.Simple
+	}
+	if kubeTLS := kubePolicy.TLS; kubeTLS != nil {
+		policy.TLS = &ClientTLS{
+			Mode:              kubeTLS.Mode,
+			ClientCertificate: kubeTLS.ClientCertificate,
+			PrivateKey:        kubeTLS.PrivateKey,
+			CACertificates:    kubeTLS.CACertificates,
+			CredentialName:    kubeTLS.CredentialName,
+			SubjectAltNames:   kubeTLS.SubjectAltNames,
+			SNI:               kubeTLS.SNI,
+		}
+	}
+
+	return policy
+}
+
+func Convert_Koki_DestinationRule_to_Kube(destinationRule *DestinationRuleWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeDestinationRule{}
+	koki := &destinationRule.DestinationRule
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	kube.APIVersion = revertVersion(koki.Version)
+	kube.Kind = "DestinationRule"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.Host = koki.Host
+	kube.Spec.ExportTo = koki.ExportTo
+	kube.Spec.TrafficPolicy = revertTrafficPolicy(koki.TrafficPolicy)
+	for _, subset := range koki.Subsets {
+		kube.Spec.Subsets = append(kube.Spec.Subsets, kubeSubset{
+			Name:          subset.Name,
+			Labels:        subset.Labels,
+			TrafficPolicy: revertTrafficPolicy(subset.TrafficPolicy),
+		})
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
+
+func revertTrafficPolicy(policy *TrafficPolicy) *kubeTrafficPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	kubePolicy := &kubeTrafficPolicy{
+		ConnectionPool:   policy.ConnectionPool,
+		OutlierDetection: policy.OutlierDetection,
+	}
+	if len(policy.LoadBalancer) > 0 {
+		kubePolicy.LoadBalancer = &kubeLoadBalancerSettings{Simple: policy.LoadBalancer}
+	}
+	if tls := policy.TLS; tls != nil {
+		kubePolicy.TLS = &kubeClientTLSSettings{
+			Mode:              tls.Mode,
+			ClientCertificate: tls.ClientCertificate,
+			PrivateKey:        tls.PrivateKey,
+			CACertificates:    tls.CACertificates,
+			CredentialName:    tls.CredentialName,
+			SubjectAltNames:   tls.SubjectAltNames,
+			SNI:               tls.SNI,
+		}
+	}
+
+	return kubePolicy
+}
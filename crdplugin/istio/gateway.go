@@ -0,0 +1,138 @@
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+)
+
+// kubeGateway mirrors networking.istio.io Gateway.
+type kubeGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeGatewaySpec `json:"spec,omitempty"`
+}
+
+type kubeGatewaySpec struct {
+	Servers  []kubeServer      `json:"servers,omitempty"`
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+type kubeServer struct {
+	Port  kubePort               `json:"port"`
+	Bind  string                 `json:"bind,omitempty"`
+	Hosts []string               `json:"hosts,omitempty"`
+	TLS   *kubeServerTLSSettings `json:"tls,omitempty"`
+	Name  string                 `json:"name,omitempty"`
+}
+
+type kubePort struct {
+	Number   uint32 `json:"number"`
+	Protocol string `json:"protocol"`
+	Name     string `json:"name"`
+}
+
+type kubeServerTLSSettings struct {
+	HTTPSRedirect      bool     `json:"httpsRedirect,omitempty"`
+	Mode               string   `json:"mode,omitempty"`
+	ServerCertificate  string   `json:"serverCertificate,omitempty"`
+	PrivateKey         string   `json:"privateKey,omitempty"`
+	CACertificates     string   `json:"caCertificates,omitempty"`
+	CredentialName     string   `json:"credentialName,omitempty"`
+	SubjectAltNames    []string `json:"subjectAltNames,omitempty"`
+	MinProtocolVersion string   `json:"minProtocolVersion,omitempty"`
+	MaxProtocolVersion string   `json:"maxProtocolVersion,omitempty"`
+}
+
+func Convert_Kube_Gateway_to_Koki(kubeObj *unstructured.Unstructured) (*GatewayWrapper, error) {
+	kube := &kubeGateway{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &Gateway{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.Selector = kube.Spec.Selector
+	for _, kubeServer := range kube.Spec.Servers {
+		server := Server{
+			Name: kubeServer.Name,
+			Port: GatewayPort{
+				Name:     kubeServer.Port.Name,
+				Number:   kubeServer.Port.Number,
+				Protocol: kubeServer.Port.Protocol,
+			},
+			Bind:  kubeServer.Bind,
+			Hosts: kubeServer.Hosts,
+		}
+		if kubeTLS := kubeServer.TLS; kubeTLS != nil {
+			server.TLS = &ServerTLS{
+				Mode:              kubeTLS.Mode,
+				HTTPSRedirect:     kubeTLS.HTTPSRedirect,
+				ServerCertificate: kubeTLS.ServerCertificate,
+				PrivateKey:        kubeTLS.PrivateKey,
+				CACertificates:    kubeTLS.CACertificates,
+				CredentialName:    kubeTLS.CredentialName,
+				SubjectAltNames:   kubeTLS.SubjectAltNames,
+				MinVersion:        kubeTLS.MinProtocolVersion,
+				MaxVersion:        kubeTLS.MaxProtocolVersion,
+			}
+		}
+		koki.Servers = append(koki.Servers, server)
+	}
+
+	return &GatewayWrapper{
+		Gateway: *koki,
+	}, nil
+}
+
+func Convert_Koki_Gateway_to_Kube(gateway *GatewayWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeGateway{}
+	koki := &gateway.Gateway
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	kube.APIVersion = revertVersion(koki.Version)
+	kube.Kind = "Gateway"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.Selector = koki.Selector
+	for _, server := range koki.Servers {
+		kubeServer := kubeServer{
+			Name: server.Name,
+			Port: kubePort{
+				Name:     server.Port.Name,
+				Number:   server.Port.Number,
+				Protocol: server.Port.Protocol,
+			},
+			Bind:  server.Bind,
+			Hosts: server.Hosts,
+		}
+		if tls := server.TLS; tls != nil {
+			kubeServer.TLS = &kubeServerTLSSettings{
+				Mode:               tls.Mode,
+				HTTPSRedirect:      tls.HTTPSRedirect,
+				ServerCertificate:  tls.ServerCertificate,
+				PrivateKey:         tls.PrivateKey,
+				CACertificates:     tls.CACertificates,
+				CredentialName:     tls.CredentialName,
+				SubjectAltNames:    tls.SubjectAltNames,
+				MinProtocolVersion: tls.MinVersion,
+				MaxProtocolVersion: tls.MaxVersion,
+			}
+		}
+		kube.Spec.Servers = append(kube.Spec.Servers, kubeServer)
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
@@ -0,0 +1,76 @@
+package istio
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/short/crdplugin"
+)
+
+/*
+
+Istio adds short syntax for Istio's traffic management resources: VirtualService,
+DestinationRule and Gateway. Importing the package registers them with crdplugin.
+
+Routes are compact: a destination is "HOST[:PORT][/SUBSET][ WEIGHT%]", and a string
+match is "exact:VALUE", "prefix:VALUE" or "regex:VALUE". Only the commonly used fields
+are supported. Objects with other fields (e.g. fault injection) fail to convert instead
+of losing them.
+
+*/
+
+// Group is the API group of the resources.
+const Group = "networking.istio.io"
+
+// Versions are the versions of Group that are read. They all have the same fields.
+var Versions = []string{"v1alpha3", "v1beta1", "v1"}
+
+// defaultVersion is written when short syntax doesn't give a version. Every Istio release since 1.5 serves it.
+const defaultVersion = Group + "/v1beta1"
+
+func init() {
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "VirtualService"},
+		Versions:  Versions,
+		Key:       "virtual_service",
+		NewKoki:   func() interface{} { return &VirtualServiceWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_VirtualService_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_VirtualService_to_Kube(kokiObj.(*VirtualServiceWrapper))
+		},
+	})
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "DestinationRule"},
+		Versions:  Versions,
+		Key:       "destination_rule",
+		NewKoki:   func() interface{} { return &DestinationRuleWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_DestinationRule_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_DestinationRule_to_Kube(kokiObj.(*DestinationRuleWrapper))
+		},
+	})
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "Gateway"},
+		Versions:  Versions,
+		Key:       "istio_gateway",
+		NewKoki:   func() interface{} { return &GatewayWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_Gateway_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_Gateway_to_Kube(kokiObj.(*GatewayWrapper))
+		},
+	})
+}
+
+func revertVersion(version string) string {
+	if len(version) == 0 {
+		return defaultVersion
+	}
+
+	return version
+}
@@ -0,0 +1,171 @@
+package istio
+
+import (
+	"github.com/koki/short/types"
+)
+
+type VirtualServiceWrapper struct {
+	VirtualService VirtualService `json:"virtual_service"`
+}
+
+type VirtualService struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	Hosts    []string    `json:"hosts,omitempty"`
+	Gateways []string    `json:"gateways,omitempty"`
+	ExportTo []string    `json:"export_to,omitempty"`
+	HTTP     []HTTPRoute `json:"http,omitempty"`
+	TCP      []TCPRoute  `json:"tcp,omitempty"`
+	TLS      []TLSRoute  `json:"tls,omitempty"`
+}
+
+type HTTPRoute struct {
+	Name     string        `json:"name,omitempty"`
+	Match    []HTTPMatch   `json:"match,omitempty"`
+	Route    []Destination `json:"route,omitempty"`
+	Redirect *HTTPRedirect `json:"redirect,omitempty"`
+	Rewrite  *HTTPRewrite  `json:"rewrite,omitempty"`
+	Timeout  string        `json:"timeout,omitempty"`
+	Retries  *HTTPRetry    `json:"retries,omitempty"`
+	// Mirror is a Destination without a weight.
+	Mirror *Destination `json:"mirror,omitempty"`
+}
+
+// HTTPMatch matches requests. Strings are matched as "exact:VALUE", "prefix:VALUE" or "regex:VALUE".
+type HTTPMatch struct {
+	Name          string            `json:"name,omitempty"`
+	URI           string            `json:"uri,omitempty"`
+	Scheme        string            `json:"scheme,omitempty"`
+	Method        string            `json:"method,omitempty"`
+	Authority     string            `json:"authority,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	QueryParams   map[string]string `json:"query_params,omitempty"`
+	IgnoreURICase bool              `json:"ignore_uri_case,omitempty"`
+	L4Match       `json:",inline"`
+}
+
+type HTTPRedirect struct {
+	URI       string `json:"uri,omitempty"`
+	Authority string `json:"authority,omitempty"`
+	Code      uint32 `json:"code,omitempty"`
+}
+
+type HTTPRewrite struct {
+	URI       string `json:"uri,omitempty"`
+	Authority string `json:"authority,omitempty"`
+}
+
+type HTTPRetry struct {
+	Attempts      int32  `json:"attempts,omitempty"`
+	PerTryTimeout string `json:"per_try_timeout,omitempty"`
+	On            string `json:"on,omitempty"`
+}
+
+type TCPRoute struct {
+	Match []L4Match     `json:"match,omitempty"`
+	Route []Destination `json:"route,omitempty"`
+}
+
+type TLSRoute struct {
+	Match []TLSMatch    `json:"match,omitempty"`
+	Route []Destination `json:"route,omitempty"`
+}
+
+type L4Match struct {
+	Port               uint32            `json:"port,omitempty"`
+	DestinationSubnets []string          `json:"destination_subnets,omitempty"`
+	SourceLabels       map[string]string `json:"source_labels,omitempty"`
+	Gateways           []string          `json:"gateways,omitempty"`
+}
+
+type TLSMatch struct {
+	SNIHosts []string `json:"sni_hosts,omitempty"`
+	L4Match  `json:",inline"`
+}
+
+type DestinationRuleWrapper struct {
+	DestinationRule DestinationRule `json:"destination_rule"`
+}
+
+type DestinationRule struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	Host          string         `json:"host,omitempty"`
+	ExportTo      []string       `json:"export_to,omitempty"`
+	TrafficPolicy *TrafficPolicy `json:"traffic_policy,omitempty"`
+	Subsets       []Subset       `json:"subsets,omitempty"`
+}
+
+type TrafficPolicy struct {
+	// LoadBalancer is a simple load balancing algorithm, e.g. "ROUND_ROBIN".
+	LoadBalancer string     `json:"load_balancer,omitempty"`
+	TLS          *ClientTLS `json:"tls,omitempty"`
+	// ConnectionPool and OutlierDetection are written as in Istio syntax.
+	ConnectionPool   map[string]interface{} `json:"connection_pool,omitempty"`
+	OutlierDetection map[string]interface{} `json:"outlier_detection,omitempty"`
+}
+
+type ClientTLS struct {
+	Mode              string   `json:"mode,omitempty"`
+	ClientCertificate string   `json:"client_cert,omitempty"`
+	PrivateKey        string   `json:"private_key,omitempty"`
+	CACertificates    string   `json:"ca_certs,omitempty"`
+	CredentialName    string   `json:"credential_name,omitempty"`
+	SubjectAltNames   []string `json:"subject_alt_names,omitempty"`
+	SNI               string   `json:"sni,omitempty"`
+}
+
+type Subset struct {
+	Name          string            `json:"name,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	TrafficPolicy *TrafficPolicy    `json:"traffic_policy,omitempty"`
+}
+
+type GatewayWrapper struct {
+	Gateway Gateway `json:"istio_gateway"`
+}
+
+type Gateway struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	Selector map[string]string `json:"selector,omitempty"`
+	Servers  []Server          `json:"servers,omitempty"`
+}
+
+type Server struct {
+	Name  string      `json:"name,omitempty"`
+	Port  GatewayPort `json:"port"`
+	Bind  string      `json:"bind,omitempty"`
+	Hosts []string    `json:"hosts,omitempty"`
+	TLS   *ServerTLS  `json:"tls,omitempty"`
+}
+
+type ServerTLS struct {
+	Mode              string   `json:"mode,omitempty"`
+	HTTPSRedirect     bool     `json:"https_redirect,omitempty"`
+	ServerCertificate string   `json:"server_cert,omitempty"`
+	PrivateKey        string   `json:"private_key,omitempty"`
+	CACertificates    string   `json:"ca_certs,omitempty"`
+	CredentialName    string   `json:"credential_name,omitempty"`
+	SubjectAltNames   []string `json:"subject_alt_names,omitempty"`
+	MinVersion        string   `json:"min_version,omitempty"`
+	MaxVersion        string   `json:"max_version,omitempty"`
+}
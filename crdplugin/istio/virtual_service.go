@@ -0,0 +1,490 @@
+package istio
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeVirtualService mirrors networking.istio.io VirtualService.
+type kubeVirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeVirtualServiceSpec `json:"spec,omitempty"`
+}
+
+type kubeVirtualServiceSpec struct {
+	Hosts    []string        `json:"hosts,omitempty"`
+	Gateways []string        `json:"gateways,omitempty"`
+	HTTP     []kubeHTTPRoute `json:"http,omitempty"`
+	TLS      []kubeTLSRoute  `json:"tls,omitempty"`
+	TCP      []kubeTCPRoute  `json:"tcp,omitempty"`
+	ExportTo []string        `json:"exportTo,omitempty"`
+}
+
+type kubeHTTPRoute struct {
+	Name     string                 `json:"name,omitempty"`
+	Match    []kubeHTTPMatchRequest `json:"match,omitempty"`
+	Route    []kubeRouteDestination `json:"route,omitempty"`
+	Redirect *kubeHTTPRedirect      `json:"redirect,omitempty"`
+	Rewrite  *kubeHTTPRewrite       `json:"rewrite,omitempty"`
+	Timeout  string                 `json:"timeout,omitempty"`
+	Retries  *kubeHTTPRetry         `json:"retries,omitempty"`
+	Mirror   *kubeDestination       `json:"mirror,omitempty"`
+}
+
+type kubeHTTPMatchRequest struct {
+	Name                  string                     `json:"name,omitempty"`
+	URI                   *kubeStringMatch           `json:"uri,omitempty"`
+	Scheme                *kubeStringMatch           `json:"scheme,omitempty"`
+	Method                *kubeStringMatch           `json:"method,omitempty"`
+	Authority             *kubeStringMatch           `json:"authority,omitempty"`
+	Headers               map[string]kubeStringMatch `json:"headers,omitempty"`
+	QueryParams           map[string]kubeStringMatch `json:"queryParams,omitempty"`
+	IgnoreURICase         bool                       `json:"ignoreUriCase,omitempty"`
+	kubeL4MatchAttributes `json:",inline"`
+}
+
+type kubeStringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type kubeRouteDestination struct {
+	Destination kubeDestination `json:"destination"`
+	Weight      *int32          `json:"weight,omitempty"`
+}
+
+type kubeDestination struct {
+	Host   string            `json:"host"`
+	Subset string            `json:"subset,omitempty"`
+	Port   *kubePortSelector `json:"port,omitempty"`
+}
+
+type kubePortSelector struct {
+	Number uint32 `json:"number,omitempty"`
+}
+
+type kubeHTTPRedirect struct {
+	URI          string `json:"uri,omitempty"`
+	Authority    string `json:"authority,omitempty"`
+	RedirectCode uint32 `json:"redirectCode,omitempty"`
+}
+
+type kubeHTTPRewrite struct {
+	URI       string `json:"uri,omitempty"`
+	Authority string `json:"authority,omitempty"`
+}
+
+type kubeHTTPRetry struct {
+	Attempts      int32  `json:"attempts,omitempty"`
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+	RetryOn       string `json:"retryOn,omitempty"`
+}
+
+type kubeTCPRoute struct {
+	Match []kubeL4MatchAttributes `json:"match,omitempty"`
+	Route []kubeRouteDestination  `json:"route,omitempty"`
+}
+
+type kubeTLSRoute struct {
+	Match []kubeTLSMatchAttributes `json:"match,omitempty"`
+	Route []kubeRouteDestination   `json:"route,omitempty"`
+}
+
+type kubeL4MatchAttributes struct {
+	DestinationSubnets []string          `json:"destinationSubnets,omitempty"`
+	Port               uint32            `json:"port,omitempty"`
+	SourceLabels       map[string]string `json:"sourceLabels,omitempty"`
+	Gateways           []string          `json:"gateways,omitempty"`
+}
+
+type kubeTLSMatchAttributes struct {
+	SNIHosts              []string `json:"sniHosts,omitempty"`
+	kubeL4MatchAttributes `json:",inline"`
+}
+
+func Convert_Kube_VirtualService_to_Koki(kubeObj *unstructured.Unstructured) (*VirtualServiceWrapper, error) {
+	kube := &kubeVirtualService{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &VirtualService{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	koki.Hosts = kube.Spec.Hosts
+	koki.Gateways = kube.Spec.Gateways
+	koki.ExportTo = kube.Spec.ExportTo
+	for i, kubeRoute := range kube.Spec.HTTP {
+		route, err := convertHTTPRoute(kubeRoute)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "http[%d]", i)
+		}
+		koki.HTTP = append(koki.HTTP, *route)
+	}
+	for _, kubeRoute := range kube.Spec.TCP {
+		route := TCPRoute{Route: convertRouteDestinations(kubeRoute.Route)}
+		for _, kubeMatch := range kubeRoute.Match {
+			route.Match = append(route.Match, convertL4Match(kubeMatch))
+		}
+		koki.TCP = append(koki.TCP, route)
+	}
+	for _, kubeRoute := range kube.Spec.TLS {
+		route := TLSRoute{Route: convertRouteDestinations(kubeRoute.Route)}
+		for _, kubeMatch := range kubeRoute.Match {
+			route.Match = append(route.Match, TLSMatch{
+				SNIHosts: kubeMatch.SNIHosts,
+				L4Match:  convertL4Match(kubeMatch.kubeL4MatchAttributes),
+			})
+		}
+		koki.TLS = append(koki.TLS, route)
+	}
+
+	return &VirtualServiceWrapper{
+		VirtualService: *koki,
+	}, nil
+}
+
+func convertHTTPRoute(kubeRoute kubeHTTPRoute) (*HTTPRoute, error) {
+	route := &HTTPRoute{
+		Name:    kubeRoute.Name,
+		Route:   convertRouteDestinations(kubeRoute.Route),
+		Timeout: kubeRoute.Timeout,
+	}
+	for i, kubeMatch := range kubeRoute.Match {
+		match, err := convertHTTPMatch(kubeMatch)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "match[%d]", i)
+		}
+		route.Match = append(route.Match, *match)
+	}
+	if kubeRoute.Redirect != nil {
+		route.Redirect = &HTTPRedirect{
+			URI:       kubeRoute.Redirect.URI,
+			Authority: kubeRoute.Redirect.Authority,
+			Code:      kubeRoute.Redirect.RedirectCode,
+		}
+	}
+	if kubeRoute.Rewrite != nil {
+		route.Rewrite = &HTTPRewrite{
+			URI:       kubeRoute.Rewrite.URI,
+			Authority: kubeRoute.Rewrite.Authority,
+		}
+	}
+	if kubeRoute.Retries != nil {
+		route.Retries = &HTTPRetry{
+			Attempts:      kubeRoute.Retries.Attempts,
+			PerTryTimeout: kubeRoute.Retries.PerTryTimeout,
+			On:            kubeRoute.Retries.RetryOn,
+		}
+	}
+	if kubeRoute.Mirror != nil {
+		mirror := convertDestination(*kubeRoute.Mirror, nil)
+		route.Mirror = &mirror
+	}
+
+	return route, nil
+}
+
+func convertHTTPMatch(kubeMatch kubeHTTPMatchRequest) (*HTTPMatch, error) {
+	var err error
+	match := &HTTPMatch{
+		Name:          kubeMatch.Name,
+		IgnoreURICase: kubeMatch.IgnoreURICase,
+		L4Match:       convertL4Match(kubeMatch.kubeL4MatchAttributes),
+	}
+	for _, field := range []struct {
+		kube *kubeStringMatch
+		koki *string
+		name string
+	}{
+		{kubeMatch.URI, &match.URI, "uri"},
+		{kubeMatch.Scheme, &match.Scheme, "scheme"},
+		{kubeMatch.Method, &match.Method, "method"},
+		{kubeMatch.Authority, &match.Authority, "authority"},
+	} {
+		if field.kube == nil {
+			continue
+		}
+		*field.koki, err = convertStringMatch(*field.kube)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, field.name)
+		}
+	}
+	match.Headers, err = convertStringMatches(kubeMatch.Headers)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "headers")
+	}
+	match.QueryParams, err = convertStringMatches(kubeMatch.QueryParams)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "queryParams")
+	}
+
+	return match, nil
+}
+
+func convertStringMatches(kubeMatches map[string]kubeStringMatch) (map[string]string, error) {
+	if kubeMatches == nil {
+		return nil, nil
+	}
+
+	matches := map[string]string{}
+	for key, kubeMatch := range kubeMatches {
+		match, err := convertStringMatch(kubeMatch)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, key)
+		}
+		matches[key] = match
+	}
+
+	return matches, nil
+}
+
+func convertStringMatch(kubeMatch kubeStringMatch) (string, error) {
+	switch {
+	case len(kubeMatch.Prefix) > 0 && len(kubeMatch.Exact) == 0 && len(kubeMatch.Regex) == 0:
+		return "prefix:" + kubeMatch.Prefix, nil
+	case len(kubeMatch.Regex) > 0 && len(kubeMatch.Exact) == 0 && len(kubeMatch.Prefix) == 0:
+		return "regex:" + kubeMatch.Regex, nil
+	case len(kubeMatch.Prefix) == 0 && len(kubeMatch.Regex) == 0:
+		return "exact:" + kubeMatch.Exact, nil
+	}
+
+	return "", serrors.InvalidInstanceErrorf(kubeMatch, "expected only one of exact, prefix or regex")
+}
+
+func convertL4Match(kubeMatch kubeL4MatchAttributes) L4Match {
+	return L4Match{
+		Port:               kubeMatch.Port,
+		DestinationSubnets: kubeMatch.DestinationSubnets,
+		SourceLabels:       kubeMatch.SourceLabels,
+		Gateways:           kubeMatch.Gateways,
+	}
+}
+
+func convertRouteDestinations(kubeDestinations []kubeRouteDestination) []Destination {
+	var destinations []Destination
+	for _, kubeDestination := range kubeDestinations {
+		destinations = append(destinations, convertDestination(kubeDestination.Destination, kubeDestination.Weight))
+	}
+
+	return destinations
+}
+
+func convertDestination(kubeDestination kubeDestination, weight *int32) Destination {
+	destination := Destination{
+		Host:   kubeDestination.Host,
+		Subset: kubeDestination.Subset,
+		Weight: weight,
+	}
+	if kubeDestination.Port != nil {
+		destination.Port = kubeDestination.Port.Number
+	}
+
+	return destination
+}
+
+func Convert_Koki_VirtualService_to_Kube(virtualService *VirtualServiceWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeVirtualService{}
+	koki := &virtualService.VirtualService
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	kube.APIVersion = revertVersion(koki.Version)
+	kube.Kind = "VirtualService"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.Hosts = koki.Hosts
+	kube.Spec.Gateways = koki.Gateways
+	kube.Spec.ExportTo = koki.ExportTo
+	for i, route := range koki.HTTP {
+		kubeRoute, err := revertHTTPRoute(route)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "http[%d]", i)
+		}
+		kube.Spec.HTTP = append(kube.Spec.HTTP, *kubeRoute)
+	}
+	for _, route := range koki.TCP {
+		kubeRoute := kubeTCPRoute{Route: revertRouteDestinations(route.Route)}
+		for _, match := range route.Match {
+			kubeRoute.Match = append(kubeRoute.Match, revertL4Match(match))
+		}
+		kube.Spec.TCP = append(kube.Spec.TCP, kubeRoute)
+	}
+	for _, route := range koki.TLS {
+		kubeRoute := kubeTLSRoute{Route: revertRouteDestinations(route.Route)}
+		for _, match := range route.Match {
+			kubeRoute.Match = append(kubeRoute.Match, kubeTLSMatchAttributes{
+				SNIHosts:              match.SNIHosts,
+				kubeL4MatchAttributes: revertL4Match(match.L4Match),
+			})
+		}
+		kube.Spec.TLS = append(kube.Spec.TLS, kubeRoute)
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
+
+func revertHTTPRoute(route HTTPRoute) (*kubeHTTPRoute, error) {
+	kubeRoute := &kubeHTTPRoute{
+		Name:    route.Name,
+		Route:   revertRouteDestinations(route.Route),
+		Timeout: route.Timeout,
+	}
+	for i, match := range route.Match {
+		kubeMatch, err := revertHTTPMatch(match)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "match[%d]", i)
+		}
+		kubeRoute.Match = append(kubeRoute.Match, *kubeMatch)
+	}
+	if route.Redirect != nil {
+		kubeRoute.Redirect = &kubeHTTPRedirect{
+			URI:          route.Redirect.URI,
+			Authority:    route.Redirect.Authority,
+			RedirectCode: route.Redirect.Code,
+		}
+	}
+	if route.Rewrite != nil {
+		kubeRoute.Rewrite = &kubeHTTPRewrite{
+			URI:       route.Rewrite.URI,
+			Authority: route.Rewrite.Authority,
+		}
+	}
+	if route.Retries != nil {
+		kubeRoute.Retries = &kubeHTTPRetry{
+			Attempts:      route.Retries.Attempts,
+			PerTryTimeout: route.Retries.PerTryTimeout,
+			RetryOn:       route.Retries.On,
+		}
+	}
+	if route.Mirror != nil {
+		if route.Mirror.Weight != nil {
+			return nil, serrors.InvalidInstanceErrorf(route.Mirror, "mirror can't have a weight")
+		}
+		mirror := revertDestination(*route.Mirror)
+		kubeRoute.Mirror = &mirror
+	}
+
+	return kubeRoute, nil
+}
+
+func revertHTTPMatch(match HTTPMatch) (*kubeHTTPMatchRequest, error) {
+	var err error
+	kubeMatch := &kubeHTTPMatchRequest{
+		Name:                  match.Name,
+		IgnoreURICase:         match.IgnoreURICase,
+		kubeL4MatchAttributes: revertL4Match(match.L4Match),
+	}
+	for _, field := range []struct {
+		koki string
+		kube **kubeStringMatch
+		name string
+	}{
+		{match.URI, &kubeMatch.URI, "uri"},
+		{match.Scheme, &kubeMatch.Scheme, "scheme"},
+		{match.Method, &kubeMatch.Method, "method"},
+		{match.Authority, &kubeMatch.Authority, "authority"},
+	} {
+		if len(field.koki) == 0 {
+			continue
+		}
+		*field.kube, err = revertStringMatch(field.koki)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, field.name)
+		}
+	}
+	kubeMatch.Headers, err = revertStringMatches(match.Headers)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "headers")
+	}
+	kubeMatch.QueryParams, err = revertStringMatches(match.QueryParams)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "query_params")
+	}
+
+	return kubeMatch, nil
+}
+
+func revertStringMatches(matches map[string]string) (map[string]kubeStringMatch, error) {
+	if matches == nil {
+		return nil, nil
+	}
+
+	kubeMatches := map[string]kubeStringMatch{}
+	for key, match := range matches {
+		kubeMatch, err := revertStringMatch(match)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, key)
+		}
+		kubeMatches[key] = *kubeMatch
+	}
+
+	return kubeMatches, nil
+}
+
+func revertStringMatch(match string) (*kubeStringMatch, error) {
+	segments := strings.SplitN(match, ":", 2)
+	if len(segments) != 2 {
+		return nil, serrors.InvalidValueErrorf(match, "expected exact:VALUE, prefix:VALUE or regex:VALUE")
+	}
+
+	switch segments[0] {
+	case "exact":
+		return &kubeStringMatch{Exact: segments[1]}, nil
+	case "prefix":
+		return &kubeStringMatch{Prefix: segments[1]}, nil
+	case "regex":
+		return &kubeStringMatch{Regex: segments[1]}, nil
+	}
+
+	return nil, serrors.InvalidValueErrorf(match, "expected exact:VALUE, prefix:VALUE or regex:VALUE")
+}
+
+func revertL4Match(match L4Match) kubeL4MatchAttributes {
+	return kubeL4MatchAttributes{
+		Port:               match.Port,
+		DestinationSubnets: match.DestinationSubnets,
+		SourceLabels:       match.SourceLabels,
+		Gateways:           match.Gateways,
+	}
+}
+
+func revertRouteDestinations(destinations []Destination) []kubeRouteDestination {
+	var kubeDestinations []kubeRouteDestination
+	for _, destination := range destinations {
+		kubeDestinations = append(kubeDestinations, kubeRouteDestination{
+			Destination: revertDestination(destination),
+			Weight:      destination.Weight,
+		})
+	}
+
+	return kubeDestinations
+}
+
+func revertDestination(destination Destination) kubeDestination {
+	kubeDestination := kubeDestination{
+		Host:   destination.Host,
+		Subset: destination.Subset,
+	}
+	if destination.Port != 0 {
+		kubeDestination.Port = &kubePortSelector{Number: destination.Port}
+	}
+
+	return kubeDestination
+}
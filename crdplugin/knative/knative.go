@@ -0,0 +1,37 @@
+package knative
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/short/crdplugin"
+)
+
+/*
+
+Knative adds short syntax for Knative Serving's Service. Importing the package registers
+it with crdplugin.
+
+The revision template is written like the pod template of a Deployment, and traffic is
+compact: "REVISION[ PERCENT%][ tag=TAG]", where the revision "@latest" follows the latest
+ready revision.
+
+*/
+
+// Group is the API group of the resources.
+const Group = "serving.knative.dev"
+
+func init() {
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "Service"},
+		Versions:  []string{"v1"},
+		Key:       "knative_service",
+		NewKoki:   func() interface{} { return &ServiceWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_Service_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_Service_to_Kube(kokiObj.(*ServiceWrapper))
+		},
+	})
+}
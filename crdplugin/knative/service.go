@@ -0,0 +1,140 @@
+package knative
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/converter/converters"
+	"github.com/koki/short/crdplugin"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeService mirrors serving.knative.dev/v1 Service.
+type kubeService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeServiceSpec `json:"spec,omitempty"`
+}
+
+type kubeServiceSpec struct {
+	Template kubeRevisionTemplateSpec `json:"template,omitempty"`
+	Traffic  []kubeTrafficTarget      `json:"traffic,omitempty"`
+}
+
+type kubeRevisionTemplateSpec struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeRevisionSpec `json:"spec,omitempty"`
+}
+
+// kubeRevisionSpec is a pod spec with a few more fields.
+type kubeRevisionSpec struct {
+	v1.PodSpec `json:",inline"`
+
+	ContainerConcurrency *int64 `json:"containerConcurrency,omitempty"`
+	TimeoutSeconds       *int64 `json:"timeoutSeconds,omitempty"`
+}
+
+type kubeTrafficTarget struct {
+	Tag            string `json:"tag,omitempty"`
+	RevisionName   string `json:"revisionName,omitempty"`
+	LatestRevision *bool  `json:"latestRevision,omitempty"`
+	Percent        *int64 `json:"percent,omitempty"`
+}
+
+func Convert_Kube_Service_to_Koki(kubeObj *unstructured.Unstructured) (*ServiceWrapper, error) {
+	kube := &kubeService{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &Service{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	kubeTemplate := kube.Spec.Template
+	koki.Concurrency = kubeTemplate.Spec.ContainerConcurrency
+	koki.Timeout = kubeTemplate.Spec.TimeoutSeconds
+	koki.TemplateMetadata, koki.PodTemplate, err = converters.Convert_Kube_PodTemplateSpec_to_Koki(v1.PodTemplateSpec{
+		ObjectMeta: kubeTemplate.ObjectMeta,
+		Spec:       kubeTemplate.Spec.PodSpec,
+	})
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "template")
+	}
+
+	for _, kubeTarget := range kube.Spec.Traffic {
+		target := TrafficTarget{
+			Revision: kubeTarget.RevisionName,
+			Percent:  kubeTarget.Percent,
+			Tag:      kubeTarget.Tag,
+		}
+		latest := kubeTarget.LatestRevision != nil && *kubeTarget.LatestRevision
+		if latest == (len(kubeTarget.RevisionName) > 0) {
+			return nil, serrors.InvalidInstanceErrorf(kubeTarget, "expected either latestRevision or revisionName")
+		}
+		if latest {
+			target.Revision = LatestRevision
+		}
+		koki.Traffic = append(koki.Traffic, target)
+	}
+
+	return &ServiceWrapper{
+		Service: *koki,
+	}, nil
+}
+
+func Convert_Koki_Service_to_Kube(service *ServiceWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeService{}
+	koki := &service.Service
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	if len(koki.Version) == 0 {
+		kube.APIVersion = "serving.knative.dev/v1"
+	} else {
+		kube.APIVersion = koki.Version
+	}
+	kube.Kind = "Service"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kubeTemplate, err := converters.Convert_Koki_PodTemplateSpec_to_Kube(koki.TemplateMetadata, koki.PodTemplate)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "template")
+	}
+	kube.Spec.Template.ObjectMeta = kubeTemplate.ObjectMeta
+	kube.Spec.Template.Spec.PodSpec = kubeTemplate.Spec
+	kube.Spec.Template.Spec.ContainerConcurrency = koki.Concurrency
+	kube.Spec.Template.Spec.TimeoutSeconds = koki.Timeout
+
+	for _, target := range koki.Traffic {
+		kubeTarget := kubeTrafficTarget{
+			RevisionName: target.Revision,
+			Percent:      target.Percent,
+			Tag:          target.Tag,
+		}
+		if target.Revision == LatestRevision {
+			latest := true
+			kubeTarget.RevisionName = ""
+			kubeTarget.LatestRevision = &latest
+		}
+		kube.Spec.Traffic = append(kube.Spec.Traffic, kubeTarget)
+	}
+
+	obj, err := crdplugin.ToUnstructured(kube)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "creationTimestamp")
+
+	return obj, nil
+}
@@ -0,0 +1,78 @@
+package knative
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// LatestRevision is the revision of a TrafficTarget that follows the latest ready revision.
+const LatestRevision = "@latest"
+
+// TrafficTarget sends a share of the traffic to a revision, written as "REVISION[ PERCENT%][ tag=TAG]",
+// e.g. "@latest 90%" or "hello-00001 10% tag=old".
+type TrafficTarget struct {
+	Revision string
+	Percent  *int64
+	Tag      string
+}
+
+func ParseTrafficTarget(s string) (*TrafficTarget, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected REVISION[ PERCENT%%][ tag=TAG]")
+	}
+
+	target := &TrafficTarget{Revision: fields[0]}
+	for _, field := range fields[1:] {
+		switch {
+		case strings.HasPrefix(field, "tag="):
+			target.Tag = strings.TrimPrefix(field, "tag=")
+		case strings.HasSuffix(field, "%"):
+			percent, err := strconv.ParseInt(strings.TrimSuffix(field, "%"), 10, 64)
+			if err != nil {
+				return nil, serrors.InvalidValueContextErrorf(err, s, "couldn't parse the percent")
+			}
+			target.Percent = &percent
+		default:
+			return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected PERCENT%% or tag=TAG", field)
+		}
+	}
+
+	return target, nil
+}
+
+func (t TrafficTarget) String() string {
+	s := t.Revision
+	if t.Percent != nil {
+		s = fmt.Sprintf("%s %d%%", s, *t.Percent)
+	}
+	if len(t.Tag) > 0 {
+		s = fmt.Sprintf("%s tag=%s", s, t.Tag)
+	}
+
+	return s
+}
+
+func (t *TrafficTarget) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), t)
+	}
+
+	target, err := ParseTrafficTarget(s)
+	if err != nil {
+		return err
+	}
+	*t = *target
+
+	return nil
+}
+
+func (t TrafficTarget) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
@@ -0,0 +1,24 @@
+package knative
+
+import (
+	"testing"
+)
+
+func TestTrafficTarget(t *testing.T) {
+	for _, s := range []string{"@latest", "@latest 100%", "hello-00001 10% tag=old", "hello-00001 tag=old"} {
+		target, err := ParseTrafficTarget(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if target.String() != s {
+			t.Errorf("round-trip failed: expected %s, got %s", s, target.String())
+		}
+	}
+
+	for _, s := range []string{"", "hello-00001 ten%", "hello-00001 old"} {
+		if _, err := ParseTrafficTarget(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
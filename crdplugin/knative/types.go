@@ -0,0 +1,30 @@
+package knative
+
+import (
+	"github.com/koki/short/types"
+)
+
+type ServiceWrapper struct {
+	Service Service `json:"knative_service"`
+}
+
+type Service struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	// Concurrency is the most requests that a container handles at once. 0 is unlimited.
+	Concurrency *int64 `json:"concurrency,omitempty"`
+	// Timeout is how long a request may take, in seconds.
+	Timeout *int64 `json:"timeout,omitempty"`
+
+	// TemplateMetadata is the metadata of each revision. Its name is the name of the next revision.
+	TemplateMetadata  *types.PodTemplateMeta `json:"pod_meta,omitempty"`
+	types.PodTemplate `json:",inline"`
+
+	Traffic []TrafficTarget `json:"traffic,omitempty"`
+}
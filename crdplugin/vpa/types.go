@@ -0,0 +1,46 @@
+package vpa
+
+import (
+	"github.com/koki/short/types"
+)
+
+type VerticalPodAutoscalerWrapper struct {
+	VPA VerticalPodAutoscaler `json:"vpa"`
+}
+
+type VerticalPodAutoscaler struct {
+	Version         string            `json:"version,omitempty"`
+	Cluster         string            `json:"cluster,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	types.Ownership `json:",inline"`
+
+	// Ref is the workload whose pods are autoscaled, written like the ref of an HPA, e.g. "apps/v1.Deployment:web".
+	Ref types.CrossVersionObjectReference `json:"ref"`
+
+	// UpdateMode is how recommendations are applied: "Off", "Initial", "Recreate", "InPlaceOrRecreate" or "Auto".
+	UpdateMode string `json:"update_mode,omitempty"`
+	// MinReplicas is the fewest live replicas for the updater to evict pods.
+	MinReplicas *int32 `json:"min_replicas,omitempty"`
+
+	Containers   []ContainerPolicy `json:"containers,omitempty"`
+	Recommenders []string          `json:"recommenders,omitempty"`
+}
+
+// ContainerPolicy limits the recommendations for a container, or for all of them if Name is "*".
+type ContainerPolicy struct {
+	Name string `json:"name"`
+	// Mode is "Auto" or "Off".
+	Mode string `json:"mode,omitempty"`
+
+	// CPU and Mem bound the recommendations, as minAllowed (min) and maxAllowed (max).
+	CPU *types.CPU `json:"cpu,omitempty"`
+	Mem *types.Mem `json:"mem,omitempty"`
+
+	// ControlledResources are the resources that are recommended, e.g. "cpu" and "memory".
+	ControlledResources []string `json:"controlled_resources,omitempty"`
+	// ControlledValues is "RequestsAndLimits" or "RequestsOnly".
+	ControlledValues string `json:"controlled_values,omitempty"`
+}
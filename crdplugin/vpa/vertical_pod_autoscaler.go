@@ -0,0 +1,257 @@
+package vpa
+
+import (
+	autoscaling "k8s.io/api/autoscaling/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/koki/short/crdplugin"
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+// kubeVerticalPodAutoscaler mirrors autoscaling.k8s.io VerticalPodAutoscaler.
+type kubeVerticalPodAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeVerticalPodAutoscalerSpec `json:"spec,omitempty"`
+}
+
+type kubeVerticalPodAutoscalerSpec struct {
+	TargetRef      *autoscaling.CrossVersionObjectReference `json:"targetRef,omitempty"`
+	UpdatePolicy   *kubePodUpdatePolicy                     `json:"updatePolicy,omitempty"`
+	ResourcePolicy *kubePodResourcePolicy                   `json:"resourcePolicy,omitempty"`
+	Recommenders   []kubeRecommenderSelector                `json:"recommenders,omitempty"`
+}
+
+type kubePodUpdatePolicy struct {
+	UpdateMode  *string `json:"updateMode,omitempty"`
+	MinReplicas *int32  `json:"minReplicas,omitempty"`
+}
+
+type kubePodResourcePolicy struct {
+	ContainerPolicies []kubeContainerResourcePolicy `json:"containerPolicies,omitempty"`
+}
+
+type kubeContainerResourcePolicy struct {
+	ContainerName       string            `json:"containerName,omitempty"`
+	Mode                *string           `json:"mode,omitempty"`
+	MinAllowed          v1.ResourceList   `json:"minAllowed,omitempty"`
+	MaxAllowed          v1.ResourceList   `json:"maxAllowed,omitempty"`
+	ControlledResources []v1.ResourceName `json:"controlledResources,omitempty"`
+	ControlledValues    *string           `json:"controlledValues,omitempty"`
+}
+
+type kubeRecommenderSelector struct {
+	Name string `json:"name"`
+}
+
+func Convert_Kube_VerticalPodAutoscaler_to_Koki(kubeObj *unstructured.Unstructured) (*VerticalPodAutoscalerWrapper, error) {
+	kube := &kubeVerticalPodAutoscaler{}
+	err := crdplugin.FromUnstructured(kubeObj, kube)
+	if err != nil {
+		return nil, err
+	}
+
+	koki := &VerticalPodAutoscaler{}
+	koki.Name = kube.Name
+	koki.Namespace = kube.Namespace
+	koki.Version = kube.APIVersion
+	koki.Cluster = kube.ClusterName
+	koki.Labels = kube.Labels
+	koki.Annotations = kube.Annotations
+
+	if kube.Spec.TargetRef == nil {
+		return nil, serrors.InvalidInstanceErrorf(kube, "expected a targetRef")
+	}
+	koki.Ref = types.CrossVersionObjectReference{
+		Kind:       kube.Spec.TargetRef.Kind,
+		Name:       kube.Spec.TargetRef.Name,
+		APIVersion: kube.Spec.TargetRef.APIVersion,
+	}
+
+	if kubePolicy := kube.Spec.UpdatePolicy; kubePolicy != nil {
+		if kubePolicy.UpdateMode != nil {
+			koki.UpdateMode = *kubePolicy.UpdateMode
+		}
+		koki.MinReplicas = kubePolicy.MinReplicas
+	}
+
+	if kube.Spec.ResourcePolicy != nil {
+		for _, kubePolicy := range kube.Spec.ResourcePolicy.ContainerPolicies {
+			policy, err := convertContainerPolicy(kubePolicy)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "container %s", kubePolicy.ContainerName)
+			}
+			koki.Containers = append(koki.Containers, *policy)
+		}
+	}
+
+	for _, kubeRecommender := range kube.Spec.Recommenders {
+		koki.Recommenders = append(koki.Recommenders, kubeRecommender.Name)
+	}
+
+	return &VerticalPodAutoscalerWrapper{
+		VPA: *koki,
+	}, nil
+}
+
+func convertContainerPolicy(kubePolicy kubeContainerResourcePolicy) (*ContainerPolicy, error) {
+	policy := &ContainerPolicy{
+		Name: kubePolicy.ContainerName,
+	}
+	if kubePolicy.Mode != nil {
+		policy.Mode = *kubePolicy.Mode
+	}
+	if kubePolicy.ControlledValues != nil {
+		policy.ControlledValues = *kubePolicy.ControlledValues
+	}
+	for _, name := range kubePolicy.ControlledResources {
+		policy.ControlledResources = append(policy.ControlledResources, string(name))
+	}
+
+	for _, resources := range []v1.ResourceList{kubePolicy.MinAllowed, kubePolicy.MaxAllowed} {
+		for name := range resources {
+			if name != v1.ResourceCPU && name != v1.ResourceMemory {
+				return nil, serrors.InvalidValueErrorf(name, "only cpu and memory are supported")
+			}
+		}
+	}
+
+	if q, ok := kubePolicy.MinAllowed[v1.ResourceCPU]; ok {
+		policy.CPU = &types.CPU{Min: q.String()}
+	}
+	if q, ok := kubePolicy.MaxAllowed[v1.ResourceCPU]; ok {
+		if policy.CPU == nil {
+			policy.CPU = &types.CPU{}
+		}
+		policy.CPU.Max = q.String()
+	}
+	if q, ok := kubePolicy.MinAllowed[v1.ResourceMemory]; ok {
+		policy.Mem = &types.Mem{Min: q.String()}
+	}
+	if q, ok := kubePolicy.MaxAllowed[v1.ResourceMemory]; ok {
+		if policy.Mem == nil {
+			policy.Mem = &types.Mem{}
+		}
+		policy.Mem.Max = q.String()
+	}
+
+	return policy, nil
+}
+
+func Convert_Koki_VerticalPodAutoscaler_to_Kube(vpa *VerticalPodAutoscalerWrapper) (*unstructured.Unstructured, error) {
+	kube := &kubeVerticalPodAutoscaler{}
+	koki := &vpa.VPA
+
+	kube.Name = koki.Name
+	kube.Namespace = koki.Namespace
+	if len(koki.Version) == 0 {
+		kube.APIVersion = defaultVersion
+	} else {
+		kube.APIVersion = koki.Version
+	}
+	kube.Kind = "VerticalPodAutoscaler"
+	kube.ClusterName = koki.Cluster
+	kube.Labels = koki.Labels
+	kube.Annotations = koki.Annotations
+
+	kube.Spec.TargetRef = &autoscaling.CrossVersionObjectReference{
+		Kind:       koki.Ref.Kind,
+		Name:       koki.Ref.Name,
+		APIVersion: koki.Ref.APIVersion,
+	}
+
+	if len(koki.UpdateMode) > 0 || koki.MinReplicas != nil {
+		kube.Spec.UpdatePolicy = &kubePodUpdatePolicy{
+			MinReplicas: koki.MinReplicas,
+		}
+		if len(koki.UpdateMode) > 0 {
+			mode := koki.UpdateMode
+			kube.Spec.UpdatePolicy.UpdateMode = &mode
+		}
+	}
+
+	for _, policy := range koki.Containers {
+		kubePolicy, err := revertContainerPolicy(policy)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "container %s", policy.Name)
+		}
+		if kube.Spec.ResourcePolicy == nil {
+			kube.Spec.ResourcePolicy = &kubePodResourcePolicy{}
+		}
+		kube.Spec.ResourcePolicy.ContainerPolicies = append(kube.Spec.ResourcePolicy.ContainerPolicies, *kubePolicy)
+	}
+
+	for _, recommender := range koki.Recommenders {
+		kube.Spec.Recommenders = append(kube.Spec.Recommenders, kubeRecommenderSelector{Name: recommender})
+	}
+
+	return crdplugin.ToUnstructured(kube)
+}
+
+func revertContainerPolicy(policy ContainerPolicy) (*kubeContainerResourcePolicy, error) {
+	kubePolicy := &kubeContainerResourcePolicy{
+		ContainerName: policy.Name,
+	}
+	if len(policy.Mode) > 0 {
+		mode := policy.Mode
+		kubePolicy.Mode = &mode
+	}
+	if len(policy.ControlledValues) > 0 {
+		values := policy.ControlledValues
+		kubePolicy.ControlledValues = &values
+	}
+	for _, name := range policy.ControlledResources {
+		kubePolicy.ControlledResources = append(kubePolicy.ControlledResources, v1.ResourceName(name))
+	}
+
+	var min, max []bound
+	if policy.CPU != nil {
+		min = append(min, bound{v1.ResourceCPU, policy.CPU.Min})
+		max = append(max, bound{v1.ResourceCPU, policy.CPU.Max})
+	}
+	if policy.Mem != nil {
+		min = append(min, bound{v1.ResourceMemory, policy.Mem.Min})
+		max = append(max, bound{v1.ResourceMemory, policy.Mem.Max})
+	}
+
+	var err error
+	kubePolicy.MinAllowed, err = revertBounds(min)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "min")
+	}
+	kubePolicy.MaxAllowed, err = revertBounds(max)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "max")
+	}
+
+	return kubePolicy, nil
+}
+
+type bound struct {
+	name     v1.ResourceName
+	quantity string
+}
+
+func revertBounds(bounds []bound) (v1.ResourceList, error) {
+	var resources v1.ResourceList
+	for _, b := range bounds {
+		if len(b.quantity) == 0 {
+			continue
+		}
+		q, err := resource.ParseQuantity(b.quantity)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, b.quantity, "couldn't parse the %s quantity", b.name)
+		}
+		if resources == nil {
+			resources = v1.ResourceList{}
+		}
+		resources[b.name] = q
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,39 @@
+package vpa
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestContainerPolicyBounds(t *testing.T) {
+	kubePolicy := kubeContainerResourcePolicy{
+		ContainerName: "web",
+		MinAllowed:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+		MaxAllowed:    v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	policy, err := convertContainerPolicy(kubePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.CPU == nil || policy.CPU.Min != "100m" || len(policy.CPU.Max) > 0 {
+		t.Errorf("unexpected cpu %#v", policy.CPU)
+	}
+	if policy.Mem == nil || len(policy.Mem.Min) > 0 || policy.Mem.Max != "1Gi" {
+		t.Errorf("unexpected mem %#v", policy.Mem)
+	}
+
+	reverted, err := revertContainerPolicy(*policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted.MinAllowed) != 1 || len(reverted.MaxAllowed) != 1 {
+		t.Errorf("unexpected bounds %v, %v", reverted.MinAllowed, reverted.MaxAllowed)
+	}
+
+	kubePolicy.MaxAllowed[v1.ResourceEphemeralStorage] = resource.MustParse("1Gi")
+	if _, err := convertContainerPolicy(kubePolicy); err == nil {
+		t.Error("expected an error for ephemeral-storage")
+	}
+}
@@ -0,0 +1,43 @@
+package vpa
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/short/crdplugin"
+)
+
+/*
+
+Vpa adds short syntax for the VerticalPodAutoscaler of the Kubernetes autoscaler project.
+Importing the package registers it with crdplugin. Like the Gateway API plugin, short
+always includes it.
+
+The bounds of a container's recommendations are written like its resources, with the
+cpu and mem fields: min is minAllowed and max is maxAllowed.
+
+*/
+
+// Group is the API group of the resources.
+const Group = "autoscaling.k8s.io"
+
+// Versions are the versions of Group that are read. They all have the same fields.
+var Versions = []string{"v1beta2", "v1"}
+
+// defaultVersion is written when short syntax doesn't give a version.
+const defaultVersion = Group + "/v1"
+
+func init() {
+	crdplugin.Register(crdplugin.Converter{
+		GroupKind: schema.GroupKind{Group: Group, Kind: "VerticalPodAutoscaler"},
+		Versions:  Versions,
+		Key:       "vpa",
+		NewKoki:   func() interface{} { return &VerticalPodAutoscalerWrapper{} },
+		ToKoki: func(kubeObj *unstructured.Unstructured) (interface{}, error) {
+			return Convert_Kube_VerticalPodAutoscaler_to_Koki(kubeObj)
+		},
+		ToKube: func(kokiObj interface{}) (*unstructured.Unstructured, error) {
+			return Convert_Koki_VerticalPodAutoscaler_to_Kube(kokiObj.(*VerticalPodAutoscalerWrapper))
+		},
+	})
+}
@@ -0,0 +1,7 @@
+package main
+
+// The Gateway API and the VerticalPodAutoscaler are Kubernetes projects, so their short syntax is always built in.
+import (
+	_ "github.com/koki/short/crdplugin/gatewayapi"
+	_ "github.com/koki/short/crdplugin/vpa"
+)
@@ -0,0 +1,6 @@
+//go:build istio
+
+package main
+
+// Build with "-tags istio" for short syntax for Istio's VirtualService, DestinationRule and Gateway.
+import _ "github.com/koki/short/crdplugin/istio"
@@ -0,0 +1,6 @@
+//go:build knative
+
+package main
+
+// Build with "-tags knative" for short syntax for Knative Serving's Service.
+import _ "github.com/koki/short/crdplugin/knative"
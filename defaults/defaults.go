@@ -0,0 +1,268 @@
+package defaults
+
+import (
+	"strings"
+
+	"github.com/koki/short/compat"
+	"github.com/koki/short/util/objutil"
+)
+
+/*
+
+Defaults fills in the values that the Kubernetes API server would otherwise fill in, so that
+the output of a conversion is exactly what ends up in the cluster. Short manifests can leave
+these fields out either way.
+
+Only fields that are missing are set. The values are those of Kubernetes 1.10, and some depend
+on the apiVersion of the object, e.g. Deployments keep 10 old ReplicaSets in apps/v1 but all of
+them in extensions/v1beta1, and surge by 25% in apps/v1 but by 1 pod in extensions/v1beta1.
+
+*/
+
+// Default is the default value of a field, and when it applies.
+type Default struct {
+	// Path is the location of the field, e.g. "spec.replicas".
+	Path string
+	// APIVersions limits the default to some apiVersions. It applies to every apiVersion if it's empty.
+	APIVersions []string
+	Value       interface{}
+}
+
+var podSpecDefaults = []Default{
+	{Path: "dnsPolicy", Value: "ClusterFirst"},
+	{Path: "schedulerName", Value: "default-scheduler"},
+	{Path: "terminationGracePeriodSeconds", Value: int64(30)},
+}
+
+var containerDefaults = []Default{
+	{Path: "terminationMessagePath", Value: "/dev/termination-log"},
+	{Path: "terminationMessagePolicy", Value: "File"},
+}
+
+var probeDefaults = []Default{
+	{Path: "failureThreshold", Value: int64(3)},
+	{Path: "periodSeconds", Value: int64(10)},
+	{Path: "successThreshold", Value: int64(1)},
+	{Path: "timeoutSeconds", Value: int64(1)},
+}
+
+var kindDefaults = map[string][]Default{
+	"CronJob": {
+		{Path: "spec.concurrencyPolicy", Value: "Allow"},
+		{Path: "spec.failedJobsHistoryLimit", Value: int64(1)},
+		{Path: "spec.successfulJobsHistoryLimit", Value: int64(3)},
+		{Path: "spec.suspend", Value: false},
+	},
+	"DaemonSet": {
+		{Path: "spec.revisionHistoryLimit", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: int64(10)},
+		{Path: "spec.updateStrategy.type", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: "RollingUpdate"},
+		{Path: "spec.updateStrategy.type", APIVersions: []string{"extensions/v1beta1"}, Value: "OnDelete"},
+	},
+	"Deployment": {
+		{Path: "spec.replicas", Value: int64(1)},
+		{Path: "spec.progressDeadlineSeconds", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: int64(600)},
+		{Path: "spec.revisionHistoryLimit", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: int64(10)},
+		{Path: "spec.revisionHistoryLimit", APIVersions: []string{"apps/v1beta1"}, Value: int64(2)},
+		{Path: "spec.strategy.type", Value: "RollingUpdate"},
+	},
+	"Job": {
+		{Path: "spec.backoffLimit", Value: int64(6)},
+		{Path: "spec.parallelism", Value: int64(1)},
+	},
+	"ReplicaSet": {
+		{Path: "spec.replicas", Value: int64(1)},
+	},
+	"ReplicationController": {
+		{Path: "spec.replicas", Value: int64(1)},
+	},
+	"Service": {
+		{Path: "spec.sessionAffinity", Value: "None"},
+		{Path: "spec.type", Value: "ClusterIP"},
+	},
+	"StatefulSet": {
+		{Path: "spec.podManagementPolicy", Value: "OrderedReady"},
+		{Path: "spec.replicas", Value: int64(1)},
+		{Path: "spec.revisionHistoryLimit", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: int64(10)},
+		{Path: "spec.updateStrategy.type", APIVersions: []string{"apps/v1", "apps/v1beta2"}, Value: "RollingUpdate"},
+		{Path: "spec.updateStrategy.type", APIVersions: []string{"apps/v1beta1"}, Value: "OnDelete"},
+	},
+}
+
+// Apply fills in the defaults of a Kubernetes object, in place.
+func Apply(obj map[string]interface{}) {
+	kind, _ := obj["kind"].(string)
+	apiVersion, _ := obj["apiVersion"].(string)
+
+	if kind == "Job" {
+		// Jobs without completions are work queues, unless parallelism isn't set either.
+		if spec, ok := obj["spec"].(map[string]interface{}); ok && spec["completions"] == nil && spec["parallelism"] == nil {
+			spec["completions"] = int64(1)
+		}
+	}
+	setDefaults(obj, apiVersion, kindDefaults[kind])
+
+	switch kind {
+	case "Deployment":
+		if valueAt(obj, "spec.strategy.type") == "RollingUpdate" {
+			setDefaults(obj, apiVersion, []Default{
+				{Path: "spec.strategy.rollingUpdate.maxSurge", APIVersions: []string{"apps/v1", "apps/v1beta1", "apps/v1beta2"}, Value: "25%"},
+				{Path: "spec.strategy.rollingUpdate.maxUnavailable", APIVersions: []string{"apps/v1", "apps/v1beta1", "apps/v1beta2"}, Value: "25%"},
+				{Path: "spec.strategy.rollingUpdate.maxSurge", APIVersions: []string{"extensions/v1beta1"}, Value: int64(1)},
+				{Path: "spec.strategy.rollingUpdate.maxUnavailable", APIVersions: []string{"extensions/v1beta1"}, Value: int64(1)},
+			})
+		}
+	case "DaemonSet":
+		if valueAt(obj, "spec.updateStrategy.type") == "RollingUpdate" {
+			setDefaults(obj, apiVersion, []Default{{Path: "spec.updateStrategy.rollingUpdate.maxUnavailable", Value: int64(1)}})
+		}
+	case "StatefulSet":
+		if valueAt(obj, "spec.updateStrategy.type") == "RollingUpdate" {
+			setDefaults(obj, apiVersion, []Default{{Path: "spec.updateStrategy.rollingUpdate.partition", Value: int64(0)}})
+		}
+	case "Service":
+		spec, _ := obj["spec"].(map[string]interface{})
+		ports, _ := spec["ports"].([]interface{})
+		for _, port := range ports {
+			if portMap, ok := port.(map[string]interface{}); ok {
+				setIfMissing(portMap, "protocol", "TCP")
+				if _, ok := portMap["targetPort"]; !ok && portMap["port"] != nil {
+					portMap["targetPort"] = portMap["port"]
+				}
+			}
+		}
+	}
+
+	if path, ok := compat.PodSpecPath(kind); ok {
+		if podSpec, ok := valueAt(obj, path).(map[string]interface{}); ok {
+			applyPodSpec(kind, apiVersion, podSpec)
+		}
+	}
+}
+
+func applyPodSpec(kind, apiVersion string, podSpec map[string]interface{}) {
+	setDefaults(podSpec, apiVersion, podSpecDefaults)
+	// Jobs have to set their restart policy, since the default (Always) isn't allowed for them.
+	if kind != "Job" && kind != "CronJob" {
+		setIfMissing(podSpec, "restartPolicy", "Always")
+	}
+
+	for _, key := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[key].([]interface{})
+		for _, container := range containers {
+			if containerMap, ok := container.(map[string]interface{}); ok {
+				applyContainer(apiVersion, containerMap)
+			}
+		}
+	}
+}
+
+func applyContainer(apiVersion string, container map[string]interface{}) {
+	setDefaults(container, apiVersion, containerDefaults)
+	if image, ok := container["image"].(string); ok {
+		setIfMissing(container, "imagePullPolicy", imagePullPolicy(image))
+	}
+
+	ports, _ := container["ports"].([]interface{})
+	for _, port := range ports {
+		if portMap, ok := port.(map[string]interface{}); ok {
+			setIfMissing(portMap, "protocol", "TCP")
+		}
+	}
+
+	for _, key := range []string{"livenessProbe", "readinessProbe"} {
+		probe, ok := container[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		setDefaults(probe, apiVersion, probeDefaults)
+		if httpGet, ok := probe["httpGet"].(map[string]interface{}); ok {
+			setIfMissing(httpGet, "path", "/")
+			setIfMissing(httpGet, "scheme", "HTTP")
+		}
+	}
+}
+
+// imagePullPolicy is Always for images without a tag or with the "latest" tag, and IfNotPresent otherwise.
+func imagePullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	if i < 0 || name[i+1:] == "latest" {
+		return "Always"
+	}
+
+	return "IfNotPresent"
+}
+
+// Objs fills in the defaults of Kubernetes objects.
+func Objs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		Apply(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
+
+func setDefaults(obj map[string]interface{}, apiVersion string, defaults []Default) {
+	for _, d := range defaults {
+		if len(d.APIVersions) > 0 && !contains(d.APIVersions, apiVersion) {
+			continue
+		}
+
+		segments := strings.Split(d.Path, ".")
+		parent := obj
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := parent[segment].(map[string]interface{})
+			if !ok {
+				if _, exists := parent[segment]; exists {
+					// Not an object, so leave it alone.
+					parent = nil
+					break
+				}
+				child = map[string]interface{}{}
+				parent[segment] = child
+			}
+			parent = child
+		}
+		if parent != nil {
+			setIfMissing(parent, segments[len(segments)-1], d.Value)
+		}
+	}
+}
+
+func setIfMissing(obj map[string]interface{}, key string, value interface{}) {
+	if val, ok := obj[key]; !ok || val == nil {
+		obj[key] = value
+	}
+}
+
+func valueAt(obj interface{}, path string) interface{} {
+	for _, segment := range strings.Split(path, ".") {
+		objMap, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = objMap[segment]
+	}
+
+	return obj
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package defaults
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestApply(t *testing.T) {
+	obj := map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"restartPolicy": "Never",
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":            "migrate",
+							"image":           "registry.local:5000/migrate",
+							"imagePullPolicy": "Never",
+							"ports":           []interface{}{map[string]interface{}{"containerPort": 80}},
+						},
+						map[string]interface{}{"name": "sidecar", "image": "proxy:v2"},
+					},
+				},
+			},
+		},
+	}
+	expected := map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"spec": map[string]interface{}{
+			"backoffLimit": int64(6),
+			"completions":  int64(1),
+			"parallelism":  int64(1),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"restartPolicy":                 "Never",
+					"dnsPolicy":                     "ClusterFirst",
+					"schedulerName":                 "default-scheduler",
+					"terminationGracePeriodSeconds": int64(30),
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":                     "migrate",
+							"image":                    "registry.local:5000/migrate",
+							"imagePullPolicy":          "Never",
+							"ports":                    []interface{}{map[string]interface{}{"containerPort": 80, "protocol": "TCP"}},
+							"terminationMessagePath":   "/dev/termination-log",
+							"terminationMessagePolicy": "File",
+						},
+						map[string]interface{}{
+							"name":                     "sidecar",
+							"image":                    "proxy:v2",
+							"imagePullPolicy":          "IfNotPresent",
+							"terminationMessagePath":   "/dev/termination-log",
+							"terminationMessagePolicy": "File",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	Apply(obj)
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("%s", pretty.Diff(expected, obj))
+	}
+}
+
+func TestImagePullPolicy(t *testing.T) {
+	cases := map[string]string{
+		"nginx":                       "Always",
+		"nginx:latest":                "Always",
+		"nginx:1.25":                  "IfNotPresent",
+		"registry.local:5000/nginx":   "Always",
+		"nginx@sha256:0123456789abcd": "IfNotPresent",
+	}
+	for image, expected := range cases {
+		if policy := imagePullPolicy(image); policy != expected {
+			t.Errorf("%s: expected %s, got %s", image, expected, policy)
+		}
+	}
+}
+
+func TestApplyRollingUpdate(t *testing.T) {
+	for apiVersion, expected := range map[string]interface{}{
+		"apps/v1":            "25%",
+		"apps/v1beta1":       "25%",
+		"extensions/v1beta1": int64(1),
+	} {
+		obj := map[string]interface{}{"apiVersion": apiVersion, "kind": "Deployment", "spec": map[string]interface{}{}}
+		Apply(obj)
+		for _, path := range []string{"spec.strategy.rollingUpdate.maxSurge", "spec.strategy.rollingUpdate.maxUnavailable"} {
+			if val := valueAt(obj, path); val != expected {
+				t.Errorf("%s: expected %s to be %v, got %v", apiVersion, path, expected, val)
+			}
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package deprecation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/compat"
+)
+
+/*
+
+Deprecation knows which apiVersions and fields of Kubernetes objects are deprecated or
+removed, and what replaces them, so manifests can be fixed before a cluster upgrade breaks
+them rather than after.
+
+Removed apiVersions are listed by the release that stopped serving them. Fields are found by
+checks, along with a fix for those that can be migrated without a person deciding anything,
+e.g. moving a pod's serviceAccount to serviceAccountName. apiVersions are migrated by the
+migrate package, since that goes through short syntax.
+
+Objects are Kubernetes dictionaries.
+
+*/
+
+// API is a deprecated apiVersion of a kind.
+type API struct {
+	APIVersion string
+	Kind       string
+	// Removed is the release that stopped serving it, or "" if it's only deprecated.
+	Removed string
+	// Replacement is the apiVersion to use instead, or "" if there isn't one.
+	Replacement string
+	// Advice says what to do instead, when it's more than moving to Replacement.
+	Advice string
+	// Migratable is set when objects can be migrated to Replacement, i.e. short syntax supports it.
+	Migratable bool
+}
+
+// Message says what's deprecated and what to do instead.
+func (a API) Message() string {
+	message := fmt.Sprintf("%s %s is deprecated", a.APIVersion, a.Kind)
+	if len(a.Removed) > 0 {
+		message = fmt.Sprintf("%s %s is removed in Kubernetes %s", a.APIVersion, a.Kind, a.Removed)
+	}
+	if len(a.Replacement) > 0 {
+		message = fmt.Sprintf("%s, use %s", message, a.Replacement)
+	}
+	if len(a.Advice) > 0 {
+		message = fmt.Sprintf("%s, %s", message, a.Advice)
+	}
+
+	return message
+}
+
+var apis = []API{
+	{APIVersion: "admissionregistration.k8s.io/v1alpha1", Kind: "InitializerConfiguration", Removed: "1.14", Advice: "use a mutating admission webhook"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", Removed: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", Removed: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", Removed: "1.22", Replacement: "apiextensions.k8s.io/v1", Advice: "which requires a structural schema for each version"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", Removed: "1.22", Replacement: "apiregistration.k8s.io/v1"},
+	{APIVersion: "apps/v1beta1", Kind: "ControllerRevision", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "apps/v1beta1", Kind: "StatefulSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "apps/v1beta2", Kind: "ControllerRevision", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "DaemonSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "apps/v1beta2", Kind: "Deployment", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "apps/v1beta2", Kind: "StatefulSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", Removed: "1.25", Replacement: "autoscaling/v2"},
+	{APIVersion: "batch/v1beta1", Kind: "CronJob", Removed: "1.25", Replacement: "batch/v1"},
+	{APIVersion: "batch/v2alpha1", Kind: "CronJob", Removed: "1.21", Replacement: "batch/v1"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", Removed: "1.22", Replacement: "certificates.k8s.io/v1", Advice: "which requires a signerName"},
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", Removed: "1.22", Replacement: "networking.k8s.io/v1", Advice: "where each path has a pathType and backends are written as service.name and service.port"},
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", Removed: "1.16", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", Removed: "1.16", Replacement: "policy/v1beta1", Advice: "which is removed in Kubernetes 1.25 (see short pod-security)"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", Removed: "1.16", Replacement: "apps/v1", Migratable: true},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Removed: "1.25", Replacement: "policy/v1"},
+	{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", Removed: "1.25", Advice: "use Pod Security admission (see short pod-security)"},
+	{APIVersion: "rbac.authorization.k8s.io/v1alpha1", Kind: "ClusterRole", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1alpha1", Kind: "ClusterRoleBinding", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1alpha1", Kind: "Role", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1alpha1", Kind: "RoleBinding", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1alpha1", Kind: "PriorityClass", Removed: "1.17", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", Removed: "1.22", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "settings.k8s.io/v1alpha1", Kind: "PodPreset", Removed: "1.20", Advice: "use a mutating admission webhook"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", Removed: "1.22", Replacement: "storage.k8s.io/v1", Migratable: true},
+}
+
+// ForAPI looks up a deprecated apiVersion of a kind.
+func ForAPI(apiVersion, kind string) (API, bool) {
+	for _, api := range apis {
+		if api.APIVersion == apiVersion && api.Kind == kind {
+			return api, true
+		}
+	}
+
+	return API{}, false
+}
+
+// APIs lists the deprecated apiVersions, sorted by apiVersion and kind.
+func APIs() []API {
+	return append([]API{}, apis...)
+}
+
+// Finding is a deprecated field of an object.
+type Finding struct {
+	// Path is the field, e.g. "$.spec.template.spec.serviceAccount".
+	Path    string
+	Message string
+	// Fixable is set when Fix migrates the field.
+	Fixable bool
+}
+
+// field checks objects for a deprecated field, and fixes them in place if it can.
+type field struct {
+	check func(kind string, obj map[string]interface{}) []Finding
+	fix   func(kind string, obj map[string]interface{}) bool
+}
+
+var fields = []field{
+	{check: checkServiceAccount, fix: fixServiceAccount},
+	{check: checkCriticalPod, fix: fixCriticalPod},
+	{check: checkSeccompAnnotations},
+	{check: checkStorageClassAnnotation, fix: fixStorageClassAnnotation},
+	{check: checkVolumes},
+	{check: checkDockercfg, fix: fixDockercfg},
+	{check: checkClusterName, fix: fixClusterName},
+}
+
+// Check finds the deprecated fields of a Kubernetes object. Its apiVersion is looked up with ForAPI.
+func Check(obj map[string]interface{}) []Finding {
+	kind, _ := obj["kind"].(string)
+	findings := []Finding{}
+	for _, f := range fields {
+		for _, finding := range f.check(kind, obj) {
+			finding.Fixable = f.fix != nil
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// Fix migrates the deprecated fields of a Kubernetes object that can be migrated, in place.
+// It returns whether anything changed.
+func Fix(obj map[string]interface{}) bool {
+	kind, _ := obj["kind"].(string)
+	fixed := false
+	for _, f := range fields {
+		if f.fix != nil && len(f.check(kind, obj)) > 0 && f.fix(kind, obj) {
+			fixed = true
+		}
+	}
+
+	return fixed
+}
+
+// Annotations that have been replaced by fields.
+const (
+	criticalPodAnnotation            = "scheduler.alpha.kubernetes.io/critical-pod"
+	podSeccompAnnotation             = "seccomp.security.alpha.kubernetes.io/pod"
+	containerSeccompAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+	storageClassAnnotation           = "volume.beta.kubernetes.io/storage-class"
+)
+
+// criticalPriorityClass is the priority class that replaces the critical-pod annotation.
+const criticalPriorityClass = "system-cluster-critical"
+
+// podPaths returns the paths of the pod spec and pod metadata of a kind that has a pod template.
+func podPaths(kind string) ([]string, []string, bool) {
+	podSpecPath, ok := compat.PodSpecPath(kind)
+	if !ok {
+		return nil, nil, false
+	}
+	spec := strings.Split(podSpecPath, ".")
+	metadata := append(append([]string{}, spec[:len(spec)-1]...), "metadata")
+	if kind == "Pod" {
+		metadata = []string{"metadata"}
+	}
+
+	return spec, metadata, true
+}
+
+// dictionaryAt returns the dictionary at a path, creating it (and its parents) if create is set.
+func dictionaryAt(obj map[string]interface{}, path []string, create bool) map[string]interface{} {
+	for _, segment := range path {
+		child, ok := obj[segment].(map[string]interface{})
+		if !ok {
+			if !create {
+				return nil
+			}
+			child = map[string]interface{}{}
+			obj[segment] = child
+		}
+		obj = child
+	}
+
+	return obj
+}
+
+func jsonPath(path []string, key string) string {
+	return "$." + strings.Join(append(append([]string{}, path...), key), ".")
+}
+
+func checkServiceAccount(kind string, obj map[string]interface{}) []Finding {
+	spec, _, ok := podPaths(kind)
+	if !ok {
+		return nil
+	}
+	if podSpec := dictionaryAt(obj, spec, false); podSpec == nil || podSpec["serviceAccount"] == nil {
+		return nil
+	}
+
+	return []Finding{{
+		Path:    jsonPath(spec, "serviceAccount"),
+		Message: "serviceAccount is deprecated, use serviceAccountName",
+	}}
+}
+
+func fixServiceAccount(kind string, obj map[string]interface{}) bool {
+	spec, _, _ := podPaths(kind)
+	podSpec := dictionaryAt(obj, spec, false)
+	if _, ok := podSpec["serviceAccountName"]; !ok {
+		podSpec["serviceAccountName"] = podSpec["serviceAccount"]
+	}
+	delete(podSpec, "serviceAccount")
+
+	return true
+}
+
+func annotationsAt(obj map[string]interface{}, metadata []string) map[string]interface{} {
+	return dictionaryAt(obj, append(append([]string{}, metadata...), "annotations"), false)
+}
+
+// removeAnnotation deletes an annotation, and the annotations if it was the only one. It returns the annotation's value.
+func removeAnnotation(obj map[string]interface{}, metadata []string, key string) interface{} {
+	annotations := annotationsAt(obj, metadata)
+	val := annotations[key]
+	delete(annotations, key)
+	if len(annotations) == 0 {
+		delete(dictionaryAt(obj, metadata, false), "annotations")
+	}
+
+	return val
+}
+
+func checkCriticalPod(kind string, obj map[string]interface{}) []Finding {
+	_, metadata, ok := podPaths(kind)
+	if !ok {
+		return nil
+	}
+	if _, ok := annotationsAt(obj, metadata)[criticalPodAnnotation]; !ok {
+		return nil
+	}
+
+	return []Finding{{
+		Path:    jsonPath(append(metadata, "annotations"), criticalPodAnnotation),
+		Message: fmt.Sprintf("the annotation is removed in Kubernetes 1.16, use priorityClassName: %s (or system-node-critical)", criticalPriorityClass),
+	}}
+}
+
+func fixCriticalPod(kind string, obj map[string]interface{}) bool {
+	spec, metadata, _ := podPaths(kind)
+	critical := removeAnnotation(obj, metadata, criticalPodAnnotation)
+	podSpec := dictionaryAt(obj, spec, true)
+	if _, ok := podSpec["priorityClassName"]; !ok && critical != "false" {
+		podSpec["priorityClassName"] = criticalPriorityClass
+	}
+
+	return true
+}
+
+func checkSeccompAnnotations(kind string, obj map[string]interface{}) []Finding {
+	_, metadata, ok := podPaths(kind)
+	if !ok {
+		return nil
+	}
+
+	findings := []Finding{}
+	annotations := annotationsAt(obj, metadata)
+	for _, key := range sortedKeys(annotations) {
+		if key == podSeccompAnnotation || strings.HasPrefix(key, containerSeccompAnnotationPrefix) {
+			findings = append(findings, Finding{
+				Path:    jsonPath(append(metadata, "annotations"), key),
+				Message: "seccomp annotations are deprecated, and ignored from Kubernetes 1.27. Use securityContext.seccompProfile",
+			})
+		}
+	}
+
+	return findings
+}
+
+func checkStorageClassAnnotation(kind string, obj map[string]interface{}) []Finding {
+	if kind != "PersistentVolumeClaim" && kind != "PersistentVolume" {
+		return nil
+	}
+	if _, ok := annotationsAt(obj, []string{"metadata"})[storageClassAnnotation]; !ok {
+		return nil
+	}
+
+	return []Finding{{
+		Path:    jsonPath([]string{"metadata", "annotations"}, storageClassAnnotation),
+		Message: "the annotation is deprecated, use spec.storageClassName",
+	}}
+}
+
+func fixStorageClassAnnotation(kind string, obj map[string]interface{}) bool {
+	storageClass := removeAnnotation(obj, []string{"metadata"}, storageClassAnnotation)
+	spec := dictionaryAt(obj, []string{"spec"}, true)
+	if _, ok := spec["storageClassName"]; !ok {
+		spec["storageClassName"] = storageClass
+	}
+
+	return true
+}
+
+// removedVolumes maps in-tree volume plugins to the release that removed them, or "" if they're only deprecated.
+var removedVolumes = map[string]string{
+	"flocker":   "1.25",
+	"gitRepo":   "",
+	"glusterfs": "1.26",
+	"quobyte":   "1.25",
+	"storageos": "1.25",
+}
+
+// volumeAdvice says what to use instead of a volume plugin.
+var volumeAdvice = map[string]string{
+	"gitRepo": "clone the repo into an emptyDir volume with an init container",
+}
+
+func checkVolumes(kind string, obj map[string]interface{}) []Finding {
+	volumes := []interface{}{}
+	paths := [][]string{}
+	if kind == "PersistentVolume" {
+		volumes = append(volumes, dictionaryAt(obj, []string{"spec"}, false))
+		paths = append(paths, []string{"spec"})
+	} else if spec, _, ok := podPaths(kind); ok {
+		if podSpec := dictionaryAt(obj, spec, false); podSpec != nil {
+			podVolumes, _ := podSpec["volumes"].([]interface{})
+			for i, volume := range podVolumes {
+				volumes = append(volumes, volume)
+				paths = append(paths, append(append([]string{}, spec...), fmt.Sprintf("volumes[%d]", i)))
+			}
+		}
+	}
+
+	findings := []Finding{}
+	for i, volume := range volumes {
+		volume, ok := volume.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, plugin := range sortedKeys(volume) {
+			removed, ok := removedVolumes[plugin]
+			if !ok {
+				continue
+			}
+			message := fmt.Sprintf("the %s volume plugin is deprecated", plugin)
+			if len(removed) > 0 {
+				message = fmt.Sprintf("the %s volume plugin is removed in Kubernetes %s", plugin, removed)
+			}
+			advice, ok := volumeAdvice[plugin]
+			if !ok {
+				advice = "use a CSI driver"
+			}
+			findings = append(findings, Finding{
+				Path:    jsonPath(paths[i], plugin),
+				Message: fmt.Sprintf("%s, %s", message, advice),
+			})
+		}
+	}
+
+	return findings
+}
+
+// Docker config Secrets.
+const (
+	dockercfgType        = "kubernetes.io/dockercfg"
+	dockercfgKey         = ".dockercfg"
+	dockerConfigJSONType = "kubernetes.io/dockerconfigjson"
+	dockerConfigJSONKey  = ".dockerconfigjson"
+)
+
+func checkDockercfg(kind string, obj map[string]interface{}) []Finding {
+	if kind != "Secret" || obj["type"] != dockercfgType {
+		return nil
+	}
+
+	return []Finding{{
+		Path:    "$.type",
+		Message: fmt.Sprintf("%s is the legacy format of registry credentials, use %s", dockercfgType, dockerConfigJSONType),
+	}}
+}
+
+// fixDockercfg moves the registry credentials of a .dockercfg to the "auths" of a .dockerconfigjson.
+// Secrets whose data can't be read are left as they are.
+func fixDockercfg(kind string, obj map[string]interface{}) bool {
+	data, _ := obj["data"].(map[string]interface{})
+	encoded, _ := data[dockercfgKey].(string)
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	auths := map[string]interface{}{}
+	if err := json.Unmarshal(decoded, &auths); err != nil {
+		return false
+	}
+	config, err := json.Marshal(map[string]interface{}{"auths": auths})
+	if err != nil {
+		return false
+	}
+
+	delete(data, dockercfgKey)
+	data[dockerConfigJSONKey] = base64.StdEncoding.EncodeToString(config)
+	obj["type"] = dockerConfigJSONType
+	return true
+}
+
+func checkClusterName(kind string, obj map[string]interface{}) []Finding {
+	if metadata := dictionaryAt(obj, []string{"metadata"}, false); metadata == nil || metadata["clusterName"] == nil {
+		return nil
+	}
+
+	return []Finding{{
+		Path:    "$.metadata.clusterName",
+		Message: "clusterName is removed in Kubernetes 1.25, and was never used by Kubernetes itself",
+	}}
+}
+
+func fixClusterName(kind string, obj map[string]interface{}) bool {
+	delete(dictionaryAt(obj, []string{"metadata"}, false), "clusterName")
+	return true
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
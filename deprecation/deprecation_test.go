@@ -0,0 +1,164 @@
+package deprecation
+
+import (
+	"encoding/base64"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+func parse(t *testing.T, doc string) map[string]interface{} {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func TestAPIs(t *testing.T) {
+	all := APIs()
+	if !sort.SliceIsSorted(all, func(i, j int) bool {
+		return all[i].APIVersion < all[j].APIVersion || (all[i].APIVersion == all[j].APIVersion && all[i].Kind < all[j].Kind)
+	}) {
+		t.Error("expected the apiVersions to be sorted")
+	}
+
+	api, ok := ForAPI("extensions/v1beta1", "Ingress")
+	if !ok || api.Migratable {
+		t.Fatalf("unexpected %#v", api)
+	}
+	if message := api.Message(); message != "extensions/v1beta1 Ingress is removed in Kubernetes 1.22, use networking.k8s.io/v1, where each path has a pathType and backends are written as service.name and service.port" {
+		t.Errorf("unexpected message %s", message)
+	}
+
+	if _, ok := ForAPI("networking.k8s.io/v1", "Ingress"); ok {
+		t.Error("networking.k8s.io/v1 isn't deprecated")
+	}
+}
+
+var deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  clusterName: prod
+spec:
+  template:
+    metadata:
+      annotations:
+        scheduler.alpha.kubernetes.io/critical-pod: ""
+        container.seccomp.security.alpha.kubernetes.io/web: runtime/default
+    spec:
+      serviceAccount: web
+      containers:
+      - name: web
+        image: nginx
+      volumes:
+      - name: data
+        flocker:
+          datasetName: web
+`
+
+var fixedDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      annotations:
+        container.seccomp.security.alpha.kubernetes.io/web: runtime/default
+    spec:
+      serviceAccountName: web
+      priorityClassName: system-cluster-critical
+      containers:
+      - name: web
+        image: nginx
+      volumes:
+      - name: data
+        flocker:
+          datasetName: web
+`
+
+func TestCheckAndFix(t *testing.T) {
+	obj := parse(t, deployment)
+	findings := Check(obj)
+	expected := []Finding{
+		{Path: "$.spec.template.spec.serviceAccount", Message: "serviceAccount is deprecated, use serviceAccountName", Fixable: true},
+		{Path: "$.spec.template.metadata.annotations.scheduler.alpha.kubernetes.io/critical-pod", Message: "the annotation is removed in Kubernetes 1.16, use priorityClassName: system-cluster-critical (or system-node-critical)", Fixable: true},
+		{Path: "$.spec.template.metadata.annotations.container.seccomp.security.alpha.kubernetes.io/web", Message: "seccomp annotations are deprecated, and ignored from Kubernetes 1.27. Use securityContext.seccompProfile"},
+		{Path: "$.spec.template.spec.volumes[0].flocker", Message: "the flocker volume plugin is removed in Kubernetes 1.25, use a CSI driver"},
+		{Path: "$.metadata.clusterName", Message: "clusterName is removed in Kubernetes 1.25, and was never used by Kubernetes itself", Fixable: true},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("%s", pretty.Sprint(pretty.Diff(findings, expected)))
+	}
+
+	if !Fix(obj) {
+		t.Fatal("expected fixes")
+	}
+	if expected := parse(t, fixedDeployment); !reflect.DeepEqual(obj, expected) {
+		t.Errorf("%s", pretty.Sprint(pretty.Diff(obj, expected)))
+	}
+	if Fix(obj) {
+		t.Error("didn't expect more fixes")
+	}
+}
+
+func TestFixStorageClassAnnotation(t *testing.T) {
+	obj := parse(t, `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    volume.beta.kubernetes.io/storage-class: fast
+`)
+	if !Fix(obj) {
+		t.Fatal("expected a fix")
+	}
+	expected := parse(t, `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  storageClassName: fast
+`)
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("%s", pretty.Sprint(pretty.Diff(obj, expected)))
+	}
+}
+
+func TestFixDockercfg(t *testing.T) {
+	dockercfg := base64.StdEncoding.EncodeToString([]byte(`{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}`))
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/dockercfg",
+		"data":       map[string]interface{}{".dockercfg": dockercfg},
+	}
+	if len(Check(obj)) != 1 || !Fix(obj) {
+		t.Fatalf("expected a fixable finding for %#v", obj)
+	}
+
+	config, _ := base64.StdEncoding.DecodeString(obj["data"].(map[string]interface{})[".dockerconfigjson"].(string))
+	if obj["type"] != "kubernetes.io/dockerconfigjson" || string(config) != `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}` {
+		t.Errorf("unexpected %#v (%s)", obj, config)
+	}
+
+	invalid := map[string]interface{}{
+		"kind": "Secret",
+		"type": "kubernetes.io/dockercfg",
+		"data": map[string]interface{}{".dockercfg": "not base64"},
+	}
+	if Fix(invalid) || invalid["type"] != "kubernetes.io/dockercfg" {
+		t.Errorf("expected unreadable data to be left as it is, got %#v", invalid)
+	}
+}
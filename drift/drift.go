@@ -0,0 +1,164 @@
+package drift
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/koki/json"
+	"github.com/koki/short/client"
+	"github.com/koki/short/impact"
+	"github.com/koki/short/kubectl"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/site"
+	"github.com/koki/short/transform"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Drift is how the objects in a cluster differ from what applying manifests to it would make them,
+i.e. what "kubectl apply" would change. It's reported like an impact, by Kubernetes kind and field:
+
+	~ Deployment prod/web
+	    spec.replicas: 3 -> 2
+	+ Service prod/web
+
+The manifests are applied like "short apply" does, with a client-side "kubectl apply", but as a
+server-side dry run: the API server merges them into the live objects, fills in defaults and runs
+admission as it would for a real apply, and nothing is changed. The result is compared to the live
+object, leaving out the status and server-populated fields (see transform.StripServerFields) and the
+last-applied-configuration annotation that apply keeps its bookkeeping in. Objects that don't exist
+yet are reported as added.
+
+Apply keeps the fields of a live object that the manifest doesn't set, unless an earlier apply set
+them, so changes made in the cluster to fields that the manifests never set don't show up as drift.
+
+*/
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Check dry-runs applying the Kubernetes objects to the cluster of flags, and reports the objects it would change.
+func Check(ctx context.Context, flags kubectl.Flags, kubeObjs []interface{}) ([]impact.Change, error) {
+	if len(kubeObjs) == 0 {
+		return []impact.Change{}, nil
+	}
+
+	applied, err := dryRunApply(ctx, flags, kubeObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []impact.Change{}
+	for _, obj := range applied {
+		id := objectID(obj)
+		live, err := getLive(ctx, flags, obj)
+		if err != nil {
+			return nil, err
+		}
+		if live == nil {
+			changes = append(changes, impact.Change{ID: id, Action: impact.Added})
+			continue
+		}
+
+		for _, o := range []map[string]interface{}{live, obj} {
+			transform.StripServerFields(o)
+			stripLastApplied(o)
+		}
+		fields, err := impact.CompareFields(live, obj)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			changes = append(changes, impact.Change{ID: id, Action: impact.Changed, Fields: fields})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ID.Less(changes[j].ID)
+	})
+
+	return changes, nil
+}
+
+// dryRunApply returns the objects as the API server would store them if they were applied.
+func dryRunApply(ctx context.Context, flags kubectl.Flags, kubeObjs []interface{}) ([]map[string]interface{}, error) {
+	buf := &bytes.Buffer{}
+	err := client.WriteObjsToYamlStream(kubeObjs, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := flags.Pipe(ctx, buf, "apply", "-f", "-", "--dry-run=server", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]interface{}{}
+	err = json.Unmarshal(out, &obj)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "parsing kubectl output")
+	}
+
+	return parser.FlattenList(obj)
+}
+
+// stripLastApplied removes the annotation that client-side apply records the applied manifest in.
+func stripLastApplied(obj map[string]interface{}) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	delete(annotations, lastAppliedAnnotation)
+	if annotations != nil && len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+}
+
+// getLive returns the object in the cluster, or nil if it doesn't exist.
+func getLive(ctx context.Context, flags kubectl.Flags, obj map[string]interface{}) (map[string]interface{}, error) {
+	id := objectID(obj)
+	if len(id.Namespace) > 0 {
+		flags.Namespace = id.Namespace
+	}
+
+	out, err := flags.OutputContext(ctx, "get", resource(obj), id.Name, "-o", "json", "--ignore-not-found")
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	live := map[string]interface{}{}
+	err = json.Unmarshal(out, &live)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "parsing kubectl output")
+	}
+
+	return live, nil
+}
+
+// resource is how kubectl names the kind of an object, e.g. "Deployment.v1.apps", so it isn't mistaken
+// for a kind of the same name in another group.
+func resource(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	apiVersion, _ := obj["apiVersion"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || len(gv.Group) == 0 {
+		return kind
+	}
+
+	return kind + "." + gv.Version + "." + gv.Group
+}
+
+func objectID(obj map[string]interface{}) site.ID {
+	id := site.ID{}
+	id.Kind, _ = obj["kind"].(string)
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		id.Name, _ = metadata["name"].(string)
+		id.Namespace, _ = metadata["namespace"].(string)
+	}
+
+	return id
+}
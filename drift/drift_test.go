@@ -0,0 +1,110 @@
+package drift
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/impact"
+	"github.com/koki/short/kubectl"
+	"github.com/koki/short/site"
+)
+
+// fakeKubectl puts a kubectl on the PATH that prints the dry-run result for "apply", and the
+// live web Deployment for "get". Other objects don't exist.
+func fakeKubectl(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "kubectl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := `#!/bin/sh
+case "$1" in
+apply)
+	# short apply is a client-side apply, so the dry run has to be one too.
+	case " $* " in
+	*" --server-side "*) echo "unexpected --server-side" >&2; exit 1 ;;
+	esac
+	cat <<'EOF'
+{"apiVersion": "v1", "kind": "List", "items": [
+  {"apiVersion": "apps/v1", "kind": "Deployment",
+   "metadata": {"name": "web", "namespace": "prod", "uid": "1", "resourceVersion": "8", "generation": 4,
+     "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"spec\":{\"replicas\":2}}"}},
+   "spec": {"replicas": 2, "template": {"spec": {"containers": [{"name": "web", "image": "web:2", "imagePullPolicy": "IfNotPresent"}]}}}},
+  {"apiVersion": "v1", "kind": "Service",
+   "metadata": {"name": "web", "namespace": "prod"},
+   "spec": {"ports": [{"port": 80}]}},
+  {"apiVersion": "v1", "kind": "ConfigMap",
+   "metadata": {"name": "settings", "namespace": "prod"},
+   "data": {"a": "b"}}
+]}
+EOF
+	;;
+get)
+	if [ "$2" = Deployment.v1.apps ] && [ "$3" = web ]; then
+		cat <<'EOF'
+{"apiVersion": "apps/v1", "kind": "Deployment",
+ "metadata": {"name": "web", "namespace": "prod", "uid": "1", "resourceVersion": "7", "generation": 3,
+   "annotations": {"deployment.kubernetes.io/revision": "3"}},
+ "spec": {"replicas": 3, "template": {"spec": {"containers": [{"name": "web", "image": "web:2", "imagePullPolicy": "IfNotPresent"}]}}},
+ "status": {"replicas": 3}}
+EOF
+	fi
+	if [ "$2" = ConfigMap ] && [ "$3" = settings ]; then
+		echo '{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings", "namespace": "prod", "uid": "2"}, "data": {"a": "b"}}'
+	fi
+	;;
+esac
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	return func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cleanup := fakeKubectl(t)
+	defer cleanup()
+
+	// The fake kubectl doesn't read its input, so any object will do.
+	objs := []interface{}{map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+	changes, err := Check(context.Background(), kubectl.Flags{}, objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []impact.Change{
+		{
+			ID:     site.ID{Kind: "Deployment", Namespace: "prod", Name: "web"},
+			Action: impact.Changed,
+			Fields: []impact.FieldChange{{Path: "spec.replicas", Old: "3", New: "2"}},
+		},
+		{
+			ID:     site.ID{Kind: "Service", Namespace: "prod", Name: "web"},
+			Action: impact.Added,
+		},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Error(pretty.Diff(changes, expected))
+	}
+}
+
+func TestResource(t *testing.T) {
+	for apiVersion, expected := range map[string]string{"apps/v1": "Deployment.v1.apps", "v1": "Deployment"} {
+		if r := resource(map[string]interface{}{"apiVersion": apiVersion, "kind": "Deployment"}); r != expected {
+			t.Errorf("expected %s, got %s", expected, r)
+		}
+	}
+}
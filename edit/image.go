@@ -0,0 +1,246 @@
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/comments"
+	"github.com/koki/short/compat"
+	"github.com/koki/short/util/objutil"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Edit changes manifests in place, keeping their formatting and comments: only the lines
+that change are rewritten.
+
+Manifests may be in short or Kubernetes syntax, in block-style YAML. Lines are found with
+the same indentation-based tracking that carries comments through conversions.
+
+*/
+
+// Target selects the objects to edit.
+type Target struct {
+	// Kind is a short kind (e.g. "stateful_set") or a Kubernetes kind (e.g. "StatefulSet"), in any case.
+	Kind string
+	Name string
+	// Namespace is optional. If it's empty, objects in any namespace match.
+	Namespace string
+}
+
+// SetImage sets the images of the containers of the target objects in a YAML stream.
+// Images are keyed by container name, and "*" sets the image of every container.
+// It returns the edited stream and the number of containers it changed.
+func SetImage(data []byte, target Target, images map[string]string) ([]byte, int, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return nil, 0, serrors.InvalidValueErrorf(target.Kind, "only YAML manifests can be edited in place")
+	}
+
+	lines := splitLines(data)
+	found := false
+	changed := 0
+	for _, doc := range documents(lines) {
+		docText := []byte(strings.Join(lines[doc.start:doc.end], "\n"))
+		obj := map[string]interface{}{}
+		err := yaml.Unmarshal(docText, &obj)
+		if err != nil {
+			return nil, 0, serrors.InvalidValueContextErrorf(err, string(docText), "parsing manifest")
+		}
+
+		containersPath, ok := matchTarget(obj, target)
+		if !ok {
+			continue
+		}
+		found = true
+
+		// Paths of the image lines to change, with the new images.
+		edits := map[string]string{}
+		for _, key := range []string{"initContainers", "containers", "init_containers"} {
+			path := joinPath(containersPath, key)
+			val, _ := objutil.AtPathIn(obj, strings.Split(path, "."))
+			containers, _ := val.([]interface{})
+			for i, container := range containers {
+				containerMap, _ := container.(map[string]interface{})
+				name, _ := containerMap["name"].(string)
+				image, ok := images[name]
+				if !ok {
+					image, ok = images["*"]
+				}
+				if ok {
+					edits[fmt.Sprintf("%s.%d.image", path, i)] = image
+				}
+			}
+		}
+
+		paths := comments.LinePaths(docText)
+		for i, path := range paths {
+			line := &lines[doc.start+i]
+			if image, ok := edits[path]; ok {
+				*line = setValue(*line, image)
+				delete(edits, path)
+				changed++
+				continue
+			}
+			// A list item that starts with its image, e.g. "- image: nginx".
+			if image, ok := edits[path+".image"]; ok && isItemKey(*line, "image") {
+				*line = setValue(*line, image)
+				delete(edits, path+".image")
+				changed++
+			}
+		}
+		for path := range edits {
+			return nil, 0, serrors.InvalidValueErrorf(path, "couldn't find the line of the image to change (flow-style YAML isn't supported)")
+		}
+	}
+
+	if !found {
+		return nil, 0, serrors.InvalidValueErrorf(target, "no %s (%s) in the input", target.Kind, target.Name)
+	}
+
+	edited := strings.Join(lines, "\n")
+	if bytes.HasSuffix(data, []byte("\n")) {
+		edited += "\n"
+	}
+
+	return []byte(edited), changed, nil
+}
+
+// Contains is true if a YAML stream defines the target object.
+func Contains(data []byte, target Target) bool {
+	lines := splitLines(data)
+	for _, doc := range documents(lines) {
+		obj := map[string]interface{}{}
+		err := yaml.Unmarshal([]byte(strings.Join(lines[doc.start:doc.end], "\n")), &obj)
+		if err != nil {
+			continue
+		}
+		if _, ok := matchTarget(obj, target); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchTarget returns the path of the object's pod spec, if it's the target.
+func matchTarget(obj map[string]interface{}, target Target) (string, bool) {
+	kind, name, namespace := "", "", ""
+	containersPath := ""
+	if kubeKind, ok := obj["kind"].(string); ok {
+		kind = kubeKind
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		name, _ = metadata["name"].(string)
+		namespace, _ = metadata["namespace"].(string)
+		containersPath, ok = compat.PodSpecPath(kind)
+		if !ok {
+			return "", false
+		}
+	} else if len(obj) == 1 {
+		for key, val := range obj {
+			kind = key
+			containersPath = key
+			body, _ := val.(map[string]interface{})
+			name, _ = body["name"].(string)
+			namespace, _ = body["namespace"].(string)
+		}
+	}
+
+	if objutil.NormalizeKind(kind) != objutil.NormalizeKind(target.Kind) || name != target.Name {
+		return "", false
+	}
+	if len(target.Namespace) > 0 && namespace != target.Namespace {
+		return "", false
+	}
+
+	return containersPath, true
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// isItemKey is true if the line is a list item that starts with the key, e.g. "- image: nginx".
+func isItemKey(line, key string) bool {
+	content := strings.TrimLeft(line, " ")
+	if !strings.HasPrefix(content, "-") {
+		return false
+	}
+
+	return strings.HasPrefix(strings.TrimLeft(content[1:], " "), key+":")
+}
+
+// setValue replaces the value of a "key: value" line, keeping its indentation, quoting and comment.
+func setValue(line, value string) string {
+	keyEnd := strings.Index(line, ":")
+	if keyEnd < 0 {
+		return line
+	}
+
+	rest := line[keyEnd+1:]
+	oldValue := strings.TrimSpace(rest)
+	comment := ""
+	if i := strings.Index(rest, " #"); i >= 0 {
+		oldValue = strings.TrimSpace(rest[:i])
+		comment = " " + strings.TrimSpace(rest[i:])
+	}
+
+	switch {
+	case strings.HasPrefix(oldValue, "\""):
+		value = strconv.Quote(value)
+	case strings.HasPrefix(oldValue, "'"):
+		value = "'" + strings.Replace(value, "'", "''", -1) + "'"
+	}
+
+	return line[:keyEnd+1] + " " + value + comment
+}
+
+type document struct {
+	// start and end are the line range of the document, without its "---" separator.
+	start, end int
+}
+
+// documents splits the lines of a YAML stream into documents, leaving out documents without content.
+func documents(lines []string) []document {
+	docs := []document{}
+	start := 0
+	hasContent := false
+	for i, line := range lines {
+		if line == "---" || strings.HasPrefix(line, "--- ") {
+			if hasContent {
+				docs = append(docs, document{start: start, end: i})
+			}
+			start = i + 1
+			hasContent = false
+			continue
+		}
+		content := strings.TrimSpace(line)
+		if len(content) > 0 && !strings.HasPrefix(content, "#") {
+			hasContent = true
+		}
+	}
+	if hasContent {
+		docs = append(docs, document{start: start, end: len(lines)})
+	}
+
+	return docs
+}
+
+func splitLines(data []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines
+}
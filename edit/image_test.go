@@ -0,0 +1,109 @@
+package edit
+
+import (
+	"testing"
+)
+
+func TestSetImageShort(t *testing.T) {
+	input := `# the web tier
+deployment:
+  name: web
+  containers:
+  - name: web
+    image: "nginx:1.23" # pinned
+  - image: busybox
+    name: sidecar
+---
+deployment:
+  name: other
+  containers:
+  - name: web
+    image: nginx:1.23
+`
+	expected := `# the web tier
+deployment:
+  name: web
+  containers:
+  - name: web
+    image: "nginx:1.25" # pinned
+  - image: busybox
+    name: sidecar
+---
+deployment:
+  name: other
+  containers:
+  - name: web
+    image: nginx:1.23
+`
+
+	edited, changed, err := SetImage([]byte(input), Target{Kind: "Deployment", Name: "web"}, map[string]string{"web": "nginx:1.25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 1 {
+		t.Errorf("expected 1 change, got %d", changed)
+	}
+	if string(edited) != expected {
+		t.Errorf("unexpected output:\n%s", edited)
+	}
+}
+
+func TestSetImageKube(t *testing.T) {
+	input := `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: report
+  namespace: jobs
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - image: 'alpine:3.7'
+            name: init
+          containers:
+          - name: report
+            image: report:v1
+`
+	expected := `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: report
+  namespace: jobs
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - image: 'report:v2'
+            name: init
+          containers:
+          - name: report
+            image: report:v2
+`
+
+	target := Target{Kind: "cron_job", Name: "report", Namespace: "jobs"}
+	edited, changed, err := SetImage([]byte(input), target, map[string]string{"*": "report:v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 {
+		t.Errorf("expected 2 changes, got %d", changed)
+	}
+	if string(edited) != expected {
+		t.Errorf("unexpected output:\n%s", edited)
+	}
+
+	target.Namespace = "other"
+	if Contains([]byte(input), target) {
+		t.Errorf("expected no match in another namespace")
+	}
+	_, _, err = SetImage([]byte(input), target, map[string]string{"*": "report:v2"})
+	if err == nil {
+		t.Errorf("expected an error for a missing object")
+	}
+}
@@ -0,0 +1,241 @@
+package hcl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Hcl is an experimental output format, "-o hcl", for platform teams that standardize on HCL.
+Importing the package registers it with client. Build short with "-tags hcl" to include it.
+
+Each short document is a block, labeled with its name, whose fields are HCL attributes:
+
+	deployment "web" {
+	  replicas = 1
+	  selector = {
+	    app = "web"
+	  }
+	  containers = [
+	    {
+	      image = "nginx"
+	      name = "web"
+	    },
+	  ]
+	}
+
+Keys are sorted. Only short syntax can be written, since a block needs the document's key.
+
+*/
+
+// Format is the name of the output format.
+const Format = "hcl"
+
+func init() {
+	client.RegisterEncoder(Format, client.EncoderFunc(Encode))
+}
+
+// Encode writes short objects as HCL blocks.
+func Encode(objs []interface{}, w io.Writer) error {
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
+
+		b, err := Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Marshal writes a short object as an HCL block.
+func Marshal(obj interface{}) ([]byte, error) {
+	objMap, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(objMap) != 1 {
+		return nil, serrors.InvalidValueErrorf(objMap, "only short syntax can be written as HCL")
+	}
+
+	buf := &bytes.Buffer{}
+	for key, val := range objMap {
+		body, ok := val.(map[string]interface{})
+		if !ok || !identifierRegexp.MatchString(key) {
+			return nil, serrors.InvalidValueErrorf(objMap, "only short syntax can be written as HCL")
+		}
+
+		buf.WriteString(key)
+		attrs := body
+		if name, ok := body["name"].(string); ok {
+			fmt.Fprintf(buf, " %s", quoteString(name))
+			attrs = map[string]interface{}{}
+			for attr, attrVal := range body {
+				if attr != "name" {
+					attrs[attr] = attrVal
+				}
+			}
+		}
+		buf.WriteString(" {\n")
+		for _, attr := range sortedKeys(attrs) {
+			if !identifierRegexp.MatchString(attr) {
+				return nil, serrors.InvalidValueErrorf(attr, "%s: expected a field name", key)
+			}
+			fmt.Fprintf(buf, "  %s = ", attr)
+			err = writeExpr(buf, "  ", attrs[attr])
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "%s.%s", key, attr)
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeExpr writes a value, starting on the current line. indent is the indentation of that line.
+func writeExpr(buf *bytes.Buffer, indent string, val interface{}) error {
+	switch val := val.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		buf.WriteString(quoteString(val))
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			buf.WriteString(strconv.FormatInt(int64(val), 10))
+		} else {
+			buf.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+		}
+	case int64:
+		buf.WriteString(strconv.FormatInt(val, 10))
+	case int:
+		buf.WriteString(strconv.Itoa(val))
+	case []interface{}:
+		if isScalarList(val) {
+			buf.WriteString("[")
+			for i, item := range val {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				if err := writeExpr(buf, indent, item); err != nil {
+					return err
+				}
+			}
+			buf.WriteString("]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for _, item := range val {
+			buf.WriteString(indent + "  ")
+			if err := writeExpr(buf, indent+"  ", item); err != nil {
+				return err
+			}
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(indent + "]")
+	case map[string]interface{}:
+		if len(val) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for _, key := range sortedKeys(val) {
+			fmt.Fprintf(buf, "%s  %s = ", indent, objectKey(key))
+			if err := writeExpr(buf, indent+"  ", val[key]); err != nil {
+				return err
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	default:
+		return serrors.TypeErrorf(val, "can't write as HCL")
+	}
+
+	return nil
+}
+
+func isScalarList(list []interface{}) bool {
+	for _, item := range list {
+		switch item.(type) {
+		case []interface{}, map[string]interface{}:
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// objectKey writes an object key, quoted unless it's an identifier.
+func objectKey(key string) string {
+	if identifierRegexp.MatchString(key) && key != "null" && key != "true" && key != "false" {
+		return key
+	}
+
+	return quoteString(key)
+}
+
+// quoteString writes an HCL string, escaping the template sequences "${" and "%{".
+func quoteString(s string) string {
+	s = strings.Replace(s, "${", "$${", -1)
+	s = strings.Replace(s, "%{", "%%{", -1)
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
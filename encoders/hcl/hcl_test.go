@@ -0,0 +1,86 @@
+package hcl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koki/short/client"
+)
+
+func TestMarshal(t *testing.T) {
+	obj := map[string]interface{}{
+		"deployment": map[string]interface{}{
+			"name":     "web",
+			"replicas": 2,
+			"paused":   nil,
+			"labels":   map[string]interface{}{"app.kubernetes.io/name": "web"},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name": "web",
+					"args": []interface{}{"-c", "echo \"${HOME}\"\n"},
+				},
+			},
+			"pod_meta": map[string]interface{}{},
+		},
+	}
+
+	b, err := Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `deployment "web" {
+  containers = [
+    {
+      args = ["-c", "echo \"$${HOME}\"\n"]
+      name = "web"
+    },
+  ]
+  labels = {
+    "app.kubernetes.io/name" = "web"
+  }
+  paused = null
+  pod_meta = {}
+  replicas = 2
+}
+`
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b)
+	}
+
+	for _, obj := range []interface{}{
+		map[string]interface{}{"apiVersion": "v1", "kind": "Namespace"},
+		map[string]interface{}{"namespace": "a"},
+	} {
+		if _, err := Marshal(obj); err == nil {
+			t.Errorf("%v: expected an error", obj)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	encoder, ok := client.EncoderFor(Format)
+	if !ok {
+		t.Fatal("expected hcl to be registered")
+	}
+
+	buf := &bytes.Buffer{}
+	err := encoder.Encode([]interface{}{
+		map[string]interface{}{"namespace": map[string]interface{}{"name": "a"}},
+		map[string]interface{}{"namespace": map[string]interface{}{"labels": map[string]interface{}{"team": "b"}}},
+	}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `namespace "a" {
+}
+
+namespace {
+  labels = {
+    team = "b"
+  }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
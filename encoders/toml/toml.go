@@ -0,0 +1,257 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Toml is an experimental output format, "-o toml", for platform teams that don't use YAML.
+Importing the package registers it with client. Build short with "-tags toml" to include it.
+
+Each document is a TOML document of its own, e.g.
+
+	[deployment]
+	name = "web"
+	replicas = 1
+
+	[[deployment.containers]]
+	image = "nginx"
+	name = "web"
+
+and documents are separated by "# ---" lines, like the "---" lines of a YAML stream.
+Keys are sorted. TOML doesn't have null, so null values are left out. Lists of
+dictionaries are arrays of tables, and other lists are inline arrays.
+
+*/
+
+// Format is the name of the output format.
+const Format = "toml"
+
+// Separator is the line between documents.
+const Separator = "# ---"
+
+func init() {
+	client.RegisterEncoder(Format, client.EncoderFunc(Encode))
+}
+
+// Encode writes objects in either syntax as TOML documents.
+func Encode(objs []interface{}, w io.Writer) error {
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := fmt.Fprintf(w, "%s\n", Separator); err != nil {
+				return err
+			}
+		}
+
+		b, err := Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Marshal writes an object as a TOML document.
+func Marshal(obj interface{}) ([]byte, error) {
+	objMap, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err = writeTable(buf, nil, objMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimPrefix(buf.Bytes(), []byte("\n")), nil
+}
+
+// writeTable writes the key/value pairs of a table, then its tables, then its arrays of tables.
+func writeTable(buf *bytes.Buffer, path []string, table map[string]interface{}) error {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := table[key]
+		if val == nil || isTable(val) || isTableArray(val) {
+			continue
+		}
+		s, err := inline(val)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "%s", strings.Join(append(path, key), "."))
+		}
+		fmt.Fprintf(buf, "%s = %s\n", quoteKey(key), s)
+	}
+
+	for _, key := range keys {
+		if subtable, ok := table[key].(map[string]interface{}); ok {
+			subpath := append(append([]string{}, path...), key)
+			fmt.Fprintf(buf, "\n[%s]\n", headerKey(subpath))
+			if err := writeTable(buf, subpath, subtable); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if !isTableArray(table[key]) {
+			continue
+		}
+		subpath := append(append([]string{}, path...), key)
+		for _, item := range table[key].([]interface{}) {
+			fmt.Fprintf(buf, "\n[[%s]]\n", headerKey(subpath))
+			if err := writeTable(buf, subpath, item.(map[string]interface{})); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func isTable(val interface{}) bool {
+	_, ok := val.(map[string]interface{})
+	return ok
+}
+
+// isTableArray is true for non-empty lists of dictionaries.
+func isTableArray(val interface{}) bool {
+	list, ok := val.([]interface{})
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if !isTable(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inline writes a value on one line.
+func inline(val interface{}) (string, error) {
+	switch val := val.(type) {
+	case string:
+		return quoteString(val), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			return strconv.FormatInt(int64(val), 10), nil
+		}
+		return strconv.FormatFloat(val, 'g', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case int:
+		return strconv.Itoa(val), nil
+	case []interface{}:
+		items := []string{}
+		for _, item := range val {
+			if item == nil {
+				continue
+			}
+			s, err := inline(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := []string{}
+		for _, key := range keys {
+			if val[key] == nil {
+				continue
+			}
+			s, err := inline(val[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, quoteKey(key)+" = "+s)
+		}
+		if len(items) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	}
+
+	return "", serrors.TypeErrorf(val, "can't write as TOML")
+}
+
+var bareKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func quoteKey(key string) string {
+	if bareKeyRegexp.MatchString(key) {
+		return key
+	}
+
+	return quoteString(key)
+}
+
+func headerKey(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = quoteKey(key)
+	}
+
+	return strings.Join(keys, ".")
+}
+
+// quoteString writes a TOML basic string.
+func quoteString(s string) string {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
@@ -0,0 +1,73 @@
+package toml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koki/short/client"
+)
+
+func TestMarshal(t *testing.T) {
+	obj := map[string]interface{}{
+		"deployment": map[string]interface{}{
+			"name":     "web",
+			"replicas": 2,
+			"paused":   nil,
+			"labels":   map[string]interface{}{"app.kubernetes.io/name": "web"},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":   "web",
+					"args":   []interface{}{"-c", "echo \"hi\"\n"},
+					"expose": []interface{}{8080, map[string]interface{}{"metrics": 9090}},
+				},
+			},
+			"tolerations": []interface{}{},
+		},
+	}
+
+	b, err := Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[deployment]
+name = "web"
+replicas = 2
+tolerations = []
+
+[deployment.labels]
+"app.kubernetes.io/name" = "web"
+
+[[deployment.containers]]
+args = ["-c", "echo \"hi\"\n"]
+expose = [8080, { metrics = 9090 }]
+name = "web"
+`
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	encoder, ok := client.EncoderFor(Format)
+	if !ok {
+		t.Fatal("expected toml to be registered")
+	}
+
+	buf := &bytes.Buffer{}
+	err := encoder.Encode([]interface{}{
+		map[string]interface{}{"namespace": map[string]interface{}{"name": "a"}},
+		map[string]interface{}{"namespace": map[string]interface{}{"name": "b"}},
+	}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[namespace]
+name = "a"
+# ---
+[namespace]
+name = "b"
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,6 @@
+//go:build hcl
+
+package main
+
+// Build with "-tags hcl" for the experimental HCL output format, "-o hcl".
+import _ "github.com/koki/short/encoders/hcl"
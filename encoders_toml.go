@@ -0,0 +1,6 @@
+//go:build toml
+
+package main
+
+// Build with "-tags toml" for the experimental TOML output format, "-o toml".
+import _ "github.com/koki/short/encoders/toml"
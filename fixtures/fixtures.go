@@ -0,0 +1,268 @@
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/util/objutil"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Golden files live in testdata/<dir>, one directory per kind. Each fixture is a set of files with the same name:
+
+	<name>.yaml         the Kubernetes manifest
+	<name>.short.yaml   the same object in short syntax
+	<name>.rekube.yaml  the Kubernetes manifest converted back from short syntax,
+	                    only if it differs from <name>.yaml
+
+The functional tests convert each .yaml to short syntax and compare it with the .short.yaml,
+then convert the .short.yaml back and compare it with the .rekube.yaml (or the .yaml).
+
+*/
+
+// Dirs maps the short syntax key of each kind to its directory under testdata.
+var Dirs = map[string]string{
+	"api_service":            "api_services",
+	"binding":                "bindings",
+	"csr":                    "csrs",
+	"cluster_role":           "cluster_roles",
+	"cluster_role_binding":   "cluster_role_bindings",
+	"config_map":             "config_maps",
+	"controller_revision":    "controller_revisions",
+	"crd":                    "crds",
+	"cron_job":               "cron_jobs",
+	"daemon_set":             "daemon_sets",
+	"deployment":             "deployments",
+	"endpoints":              "endpoints",
+	"event":                  "events",
+	"hpa":                    "hpas",
+	"ingress":                "ingress",
+	"initializer_config":     "initializer_config",
+	"job":                    "jobs",
+	"lease":                  "leases",
+	"limit_range":            "limit_range",
+	"namespace":              "namespaces",
+	"node":                   "nodes",
+	"pdb":                    "pod_disruption_policy",
+	"persistent_volume":      "persistent_volumes",
+	"pod":                    "pods",
+	"pod_preset":             "pod_preset",
+	"pod_security_policy":    "pod_security_policy",
+	"pod_template":           "pod_templates",
+	"priority_class":         "priority_class",
+	"pvc":                    "pvcs",
+	"replica_set":            "replica_sets",
+	"replication_controller": "replication_controllers",
+	"role":                   "roles",
+	"role_binding":           "role_bindings",
+	"secret":                 "secrets",
+	"service":                "services",
+	"service_account":        "serviceaccounts",
+	"stateful_set":           "stateful_sets",
+	"storage_class":          "storage_class",
+	"mutating_webhook":       "mutatingwh_config",
+	"validating_webhook":     "validatingwh_config",
+
+	// Custom resources, if their crdplugin package is built in.
+	"destination_rule": "destination_rules",
+	"gateway":          "gateways",
+	"http_route":       "http_routes",
+	"istio_gateway":    "istio_gateways",
+	"knative_service":  "knative_services",
+	"virtual_service":  "virtual_services",
+	"vpa":              "vpas",
+}
+
+// Fixture is the golden files of one object.
+type Fixture struct {
+	// Dir is the directory of the fixture under testdata, e.g. "deployments".
+	Dir string
+	// Name is the file name of the fixture without its extension, e.g. "deployment_web".
+	Name string
+
+	Kube  []byte
+	Short []byte
+	// Rekube is empty if the object converts back to the same Kubernetes object.
+	Rekube []byte
+}
+
+var unsafeFileNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Generate converts each Kubernetes object to short syntax and back, and returns its golden files.
+func Generate(kubeObjs []map[string]interface{}) ([]Fixture, error) {
+	fixtures := []Fixture{}
+	for _, kubeObj := range kubeObjs {
+		fixture, err := generate(kubeObj)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "generating fixture for %s", objectID(kubeObj))
+		}
+		fixtures = append(fixtures, *fixture)
+	}
+
+	return fixtures, nil
+}
+
+func generate(kubeObj map[string]interface{}) (*Fixture, error) {
+	kubeBytes, err := yaml.Marshal(kubeObj)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, kubeObj, "marshalling to YAML")
+	}
+
+	// Conversion may change its input, so each step starts from the YAML.
+	kokiObjs, err := client.ConvertKubeMaps(parseMaps(kubeBytes))
+	if err != nil {
+		return nil, err
+	}
+	shortBytes, err := writeYaml(kokiObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	kokiMap, err := objutil.ToDictionary(kokiObjs[0])
+	if err != nil {
+		return nil, err
+	}
+	key := ""
+	for k := range kokiMap {
+		key = k
+	}
+	dir, ok := Dirs[key]
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(key, "no testdata directory for this kind")
+	}
+
+	rekubeObjs, err := client.ConvertKokiMaps(parseMaps(shortBytes))
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "converting back to Kubernetes syntax")
+	}
+	rekubeBytes, err := writeYaml(rekubeObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{
+		Dir:   dir,
+		Name:  fileName(key, kubeObj),
+		Kube:  kubeBytes,
+		Short: shortBytes,
+	}
+
+	same, err := sameKubeObject(kubeBytes, rekubeObjs[0])
+	if err != nil {
+		return nil, err
+	}
+	if !same {
+		fixture.Rekube = rekubeBytes
+	}
+
+	return fixture, nil
+}
+
+func parseMaps(b []byte) []map[string]interface{} {
+	obj := map[string]interface{}{}
+	// The YAML was just written by yaml.Marshal.
+	_ = yaml.Unmarshal(b, &obj)
+	return []map[string]interface{}{obj}
+}
+
+func writeYaml(objs []interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := client.WriteObjsToYamlStream(objs, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Golden files don't end with a blank line.
+	return append(bytes.TrimRight(buf.Bytes(), "\n"), '\n'), nil
+}
+
+// sameKubeObject compares a Kubernetes manifest with a converted object the way the functional tests do,
+// by their typed fields, so differences in formatting and in empty fields are ignored.
+func sameKubeObject(kubeBytes []byte, converted interface{}) (bool, error) {
+	typedObj, err := parser.ParseSingleKubeNative(parseMaps(kubeBytes)[0])
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(typedObj, converted) || len(pretty.Diff(typedObj, converted)) == 0, nil
+}
+
+// fileName names a fixture after its kind and the name of the object, e.g. "deployment_web".
+func fileName(key string, kubeObj map[string]interface{}) string {
+	name := ""
+	if metadata, ok := kubeObj["metadata"].(map[string]interface{}); ok {
+		if name, _ = metadata["name"].(string); len(name) == 0 {
+			name, _ = metadata["generateName"].(string)
+		}
+	}
+
+	fileName := unsafeFileNameRegexp.ReplaceAllString(strings.ToLower(key+"_"+name), "_")
+	return strings.Trim(fileName, "_")
+}
+
+func objectID(kubeObj map[string]interface{}) string {
+	kind, _ := kubeObj["kind"].(string)
+	name := ""
+	if metadata, ok := kubeObj["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	return fmt.Sprintf("%s %s", kind, name)
+}
+
+// Write writes fixtures under root, e.g. "testdata", and returns the paths it wrote.
+// Existing fixtures are only overwritten if overwrite is set.
+func Write(fixtures []Fixture, root string, overwrite bool) ([]string, error) {
+	paths := []string{}
+	for _, fixture := range fixtures {
+		dir := filepath.Join(root, fixture.Dir)
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "creating %s", dir)
+		}
+
+		base := filepath.Join(dir, fixture.Name)
+		if _, err := os.Stat(base + ".yaml"); err == nil && !overwrite {
+			return nil, serrors.InvalidValueErrorf(base+".yaml", "fixture already exists")
+		}
+
+		files := map[string][]byte{
+			base + ".yaml":       fixture.Kube,
+			base + ".short.yaml": fixture.Short,
+		}
+		if len(fixture.Rekube) > 0 {
+			files[base+".rekube.yaml"] = fixture.Rekube
+		} else {
+			err := os.Remove(base + ".rekube.yaml")
+			if err != nil && !os.IsNotExist(err) {
+				return nil, serrors.ContextualizeErrorf(err, "removing %s", base+".rekube.yaml")
+			}
+		}
+
+		filePaths := []string{}
+		for path, b := range files {
+			err = ioutil.WriteFile(path, b, 0644)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "writing %s", path)
+			}
+			filePaths = append(filePaths, path)
+		}
+		sort.Strings(filePaths)
+		paths = append(paths, filePaths...)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,78 @@
+package fixtures
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Web.Settings
+  namespace: default
+data:
+  a: b
+`
+
+func TestGenerate(t *testing.T) {
+	obj := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(configMap), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := Generate([]map[string]interface{}{obj})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixture := generated[0]
+	if fixture.Dir != "config_maps" || fixture.Name != "config_map_web_settings" {
+		t.Error(pretty.Sprint(fixture.Dir, fixture.Name))
+	}
+	expectedShort := `config_map:
+  version: v1
+  name: Web.Settings
+  namespace: default
+  data:
+    a: b
+`
+	if string(fixture.Short) != expectedShort {
+		t.Error(pretty.Sprint(string(fixture.Short)))
+	}
+	if len(fixture.Rekube) > 0 {
+		t.Error(pretty.Sprint(string(fixture.Rekube)))
+	}
+
+	root, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	paths, err := Write(generated, root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPaths := []string{
+		filepath.Join(root, "config_maps", "config_map_web_settings.short.yaml"),
+		filepath.Join(root, "config_maps", "config_map_web_settings.yaml"),
+	}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Error(pretty.Sprint(paths))
+	}
+
+	if _, err := Write(generated, root, false); err == nil {
+		t.Error("expected an error for an existing fixture")
+	}
+	if _, err := Write(generated, root, true); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,115 @@
+package hook
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/koki/json"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Hooks are programs that change documents before or after they're converted, for changes
+that are particular to an organization, e.g. injecting secrets or mirroring images, without
+changing the converters.
+
+A hook is a shell command. It reads the documents as a JSON list on stdin, and writes the
+changed documents to stdout as a JSON list with the same number of documents, in the same
+order, so they still line up with the files they came from. Its stderr is passed through.
+The environment tells it what it's given:
+
+	SHORT_HOOK_STAGE   pre (before conversion) or post (after)
+	SHORT_HOOK_SYNTAX  short or kube, the syntax of the documents
+	SHORT_HOOK_FILE    the file the documents were read from, for pre hooks of files
+
+short doesn't embed a WebAssembly runtime. WebAssembly modules compiled for WASI run as
+hooks through one, e.g. "wasmtime run mirror.wasm".
+
+*/
+
+// Stages of a conversion that hooks run at.
+const (
+	Pre  = "pre"
+	Post = "post"
+)
+
+// Syntaxes of the documents given to a hook.
+const (
+	Short = "short"
+	Kube  = "kube"
+)
+
+// Run passes documents through each hook in turn.
+func Run(hooks []string, stage, syntax, file string, objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	for _, command := range hooks {
+		var err error
+		objs, err = run(command, stage, syntax, file, objs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objs, nil
+}
+
+// RunOnObjs is Run for typed objects, returning them as dictionaries.
+func RunOnObjs(hooks []string, stage, syntax string, objs []interface{}) ([]interface{}, error) {
+	if len(hooks) == 0 {
+		return objs, nil
+	}
+
+	objMaps := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		var err error
+		objMaps[i], err = objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objMaps, err := Run(hooks, stage, syntax, "", objMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(objMaps))
+	for i, obj := range objMaps {
+		results[i] = obj
+	}
+	return results, nil
+}
+
+func run(command, stage, syntax, file string, objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	if fields := strings.Fields(command); len(fields) > 0 && strings.HasSuffix(fields[0], ".wasm") {
+		return nil, serrors.InvalidValueErrorf(command, "WebAssembly hooks run through a WASI runtime, e.g. \"wasmtime run %s\"", fields[0])
+	}
+
+	in, err := json.Marshal(objs)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, objs, "serializing documents for hook %s", command)
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "SHORT_HOOK_STAGE="+stage, "SHORT_HOOK_SYNTAX="+syntax, "SHORT_HOOK_FILE="+file)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "%s hook %s", stage, command)
+	}
+
+	results := []map[string]interface{}{}
+	err = json.Unmarshal(out, &results)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "%s hook %s: expected a JSON list of documents", stage, command)
+	}
+	if len(results) != len(objs) {
+		return nil, serrors.InvalidValueErrorf(string(out), "%s hook %s: expected %d documents, got %d", stage, command, len(objs), len(results))
+	}
+
+	return results, nil
+}
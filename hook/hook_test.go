@@ -0,0 +1,55 @@
+package hook
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	objs := []map[string]interface{}{
+		{"pod": map[string]interface{}{"name": "web"}},
+		{"service": map[string]interface{}{"name": "web"}},
+	}
+
+	results, err := Run([]string{"cat", "cat"}, Pre, Short, "web.short.yaml", objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, objs) {
+		t.Errorf("expected the documents unchanged, got %#v", results)
+	}
+
+	results, err = Run([]string{`printf '[{"stage":"%s","syntax":"%s","file":"%s"}]' "$SHORT_HOOK_STAGE" "$SHORT_HOOK_SYNTAX" "$SHORT_HOOK_FILE"`}, Pre, Short, "web.short.yaml", objs[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"stage": "pre", "syntax": "short", "file": "web.short.yaml"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %#v, got %#v", expected, results)
+	}
+
+	for _, command := range []string{"exit 3", "echo nope", "echo '[]'", "mirror.wasm --fast"} {
+		if _, err := Run([]string{command}, Post, Kube, "", objs); err == nil {
+			t.Errorf("%s: expected an error", command)
+		}
+	}
+}
+
+func TestRunOnObjs(t *testing.T) {
+	objs, err := RunOnObjs(nil, Post, Kube, []interface{}{"unchanged"})
+	if err != nil || !reflect.DeepEqual(objs, []interface{}{"unchanged"}) {
+		t.Errorf("expected the objects unchanged without hooks, got %#v, %v", objs, err)
+	}
+
+	objs, err = RunOnObjs([]string{"cat"}, Post, Kube, []interface{}{
+		struct {
+			Kind string `json:"kind"`
+		}{Kind: "Pod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(objs, []interface{}{map[string]interface{}{"kind": "Pod"}}) {
+		t.Errorf("unexpected objects %#v", objs)
+	}
+}
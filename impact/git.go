@@ -0,0 +1,90 @@
+package impact
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+// git runs a git command in the current directory and returns its output.
+func git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// ChangedFiles lists the files matching pathspecs that differ between two revisions, relative to the current directory.
+func ChangedFiles(from, to string, pathspecs []string) ([]string, error) {
+	args := append([]string{"diff", "--no-renames", "--name-only", "--relative", from, to, "--"}, pathspecs...)
+	out, err := git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if len(line) > 0 {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
+
+// ReadObjects parses a file as of a revision. A file that doesn't exist at that revision has no objects.
+func ReadObjects(rev, path string) ([]map[string]interface{}, error) {
+	object := rev + ":./" + path
+	if _, err := git("cat-file", "-e", object); err != nil {
+		return []map[string]interface{}{}, nil
+	}
+
+	b, err := git("show", object)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(bytes.NewReader(b))})
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "parsing %s at %s", path, rev)
+	}
+
+	return objs, nil
+}
+
+// CompareRevisions reports the impact of the changes to the files matching pathspecs between two revisions.
+func CompareRevisions(from, to string, pathspecs []string) ([]Change, error) {
+	files, err := ChangedFiles(from, to, pathspecs)
+	if err != nil {
+		return nil, err
+	}
+
+	before := []map[string]interface{}{}
+	after := []map[string]interface{}{}
+	for _, file := range files {
+		objs, err := ReadObjects(from, file)
+		if err != nil {
+			return nil, err
+		}
+		before = append(before, objs...)
+
+		objs, err = ReadObjects(to, file)
+		if err != nil {
+			return nil, err
+		}
+		after = append(after, objs...)
+	}
+
+	return Compare(before, after)
+}
@@ -0,0 +1,187 @@
+package impact
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/site"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+The impact of a change is the set of objects it adds, removes or changes.
+Each changed object lists the fields that differ, by their path in short syntax:
+
+	~ deployment default/web
+	    deployment.replicas: 2 -> 3
+	    + deployment.containers[0].env[1] = "LOG=debug"
+	    - deployment.containers[0].args[0] = "--verbose"
+	+ service default/web
+	- config_map default/old
+
+Objects are matched across revisions by kind, namespace and name, so renaming an object
+shows up as one removal and one addition.
+
+*/
+
+type Action string
+
+const (
+	Added   Action = "+"
+	Removed Action = "-"
+	Changed Action = "~"
+)
+
+type Change struct {
+	site.ID
+	Action Action
+
+	// Fields that differ, only for Changed objects.
+	Fields []FieldChange
+}
+
+// FieldChange is a leaf value that differs. Old is empty if the field was added, New is empty if it was removed.
+type FieldChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+func (f FieldChange) String() string {
+	switch {
+	case len(f.Old) == 0:
+		return fmt.Sprintf("+ %s = %s", f.Path, f.New)
+	case len(f.New) == 0:
+		return fmt.Sprintf("- %s = %s", f.Path, f.Old)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", f.Path, f.Old, f.New)
+	}
+}
+
+// Compare converts the objects of both revisions to short syntax and reports how they differ.
+// The objects may be in either syntax. Every object must also convert to Kubernetes syntax.
+func Compare(before, after []map[string]interface{}) ([]Change, error) {
+	beforeObjs, err := index(before)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "before")
+	}
+	afterObjs, err := index(after)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "after")
+	}
+
+	changes := []Change{}
+	for id, beforeObj := range beforeObjs {
+		afterObj, ok := afterObjs[id]
+		if !ok {
+			changes = append(changes, Change{ID: id, Action: Removed})
+			continue
+		}
+
+		fields, err := CompareFields(beforeObj, afterObj)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{ID: id, Action: Changed, Fields: fields})
+		}
+	}
+	for id := range afterObjs {
+		if _, ok := beforeObjs[id]; !ok {
+			changes = append(changes, Change{ID: id, Action: Added})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ID.Less(changes[j].ID)
+	})
+
+	return changes, nil
+}
+
+// index converts each object to short syntax, checks that it converts to Kubernetes syntax, and looks it up by ID.
+func index(objs []map[string]interface{}) (map[site.ID]map[string]interface{}, error) {
+	kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.ConvertKokiMaps(kokiObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[site.ID]map[string]interface{}{}
+	for _, kokiObj := range kokiObjs {
+		id := site.ObjectID(kokiObj)
+		if _, ok := byID[id]; ok {
+			return nil, serrors.InvalidValueErrorf(kokiObj, "duplicate object (%s %s/%s)", id.Kind, id.Namespace, id.Name)
+		}
+		byID[id] = kokiObj
+	}
+
+	return byID, nil
+}
+
+// CompareFields reports the leaf values that differ between two versions of an object, by their path.
+func CompareFields(before, after map[string]interface{}) ([]FieldChange, error) {
+	beforeLeaves, err := objutil.ExplodeLeaves(before)
+	if err != nil {
+		return nil, err
+	}
+	afterLeaves, err := objutil.ExplodeLeaves(after)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeValues := map[string]string{}
+	for _, leaf := range beforeLeaves {
+		beforeValues[leaf.Path] = leaf.Value
+	}
+	afterValues := map[string]string{}
+	for _, leaf := range afterLeaves {
+		afterValues[leaf.Path] = leaf.Value
+	}
+
+	fields := []FieldChange{}
+	for _, leaf := range afterLeaves {
+		old, ok := beforeValues[leaf.Path]
+		if !ok {
+			fields = append(fields, FieldChange{Path: leaf.Path, New: leaf.Value})
+		} else if old != leaf.Value {
+			fields = append(fields, FieldChange{Path: leaf.Path, Old: old, New: leaf.Value})
+		}
+	}
+	for _, leaf := range beforeLeaves {
+		if _, ok := afterValues[leaf.Path]; !ok {
+			fields = append(fields, FieldChange{Path: leaf.Path, Old: leaf.Value})
+		}
+	}
+
+	return fields, nil
+}
+
+// Write prints the changes in the format described above.
+func Write(changes []Change, w io.Writer) error {
+	for _, change := range changes {
+		name := change.Name
+		if len(change.Namespace) > 0 {
+			name = change.Namespace + "/" + change.Name
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", change.Action, change.Kind, name); err != nil {
+			return err
+		}
+
+		for _, field := range change.Fields {
+			if _, err := fmt.Fprintf(w, "    %s\n", field); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
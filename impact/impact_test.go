@@ -0,0 +1,95 @@
+package impact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koki/short/yaml"
+)
+
+var before = `
+deployment:
+  name: web
+  namespace: default
+  replicas: 2
+  selector:
+    app: web
+  containers:
+  - name: web
+    image: nginx
+    args:
+    - --verbose
+---
+config_map:
+  name: old
+  namespace: default
+  data:
+    a: b
+`
+
+var after = `
+deployment:
+  name: web
+  namespace: default
+  replicas: 3
+  selector:
+    app: web
+  containers:
+  - name: web
+    image: nginx
+    env:
+    - LOG=debug
+---
+service:
+  name: web
+  namespace: default
+  selector:
+    app: web
+  port: 80
+`
+
+func parseDocs(t *testing.T, docs string) []map[string]interface{} {
+	objs := []map[string]interface{}{}
+	for _, doc := range bytes.Split([]byte(docs), []byte("\n---\n")) {
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			t.Fatal(err)
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs
+}
+
+func TestCompare(t *testing.T) {
+	changes, err := Compare(parseDocs(t, before), parseDocs(t, after))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(changes, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `- config_map default/old
+~ deployment default/web
+    + deployment.containers[0].env[0] = "LOG=debug"
+    deployment.replicas: 2 -> 3
+    - deployment.containers[0].args[0] = "--verbose"
++ service default/web
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	changes, err := Compare(parseDocs(t, before), parseDocs(t, before))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) > 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
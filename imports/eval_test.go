@@ -3,10 +3,12 @@ package imports
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kr/pretty"
 
+	"github.com/koki/short/template"
 	"github.com/koki/short/yaml"
 )
 
@@ -152,3 +154,59 @@ func doTestEval(modulePath string, t *testing.T, expectEvalError bool) {
 		t.Fatal(pretty.Sprintf("%s: evaluated module doesn't match expected\n(%# v)\n(%# v)", modulePath, module.Export.Raw, evalResults[modulePath]))
 	}
 }
+
+func TestEvalVariables(t *testing.T) {
+	evalContext := getFullEvalContext(t)
+	evalContext.Variables = template.ResolverForParams(map[string]interface{}{"param0": "from variables"})
+	evalContext.KeepUnresolved = true
+	evalModules["module8"] = `
+value:
+- ${param0}
+- ${param1}
+- prefix-${param1}
+`
+	defer delete(evalModules, "module8")
+
+	modules, err := evalContext.Parse("module8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := &modules[0]
+	err = evalContext.EvaluateModule(module, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"value": []interface{}{"from variables", "${param1}", "prefix-${param1}"},
+	}
+	if !reflect.DeepEqual(module.Export.Raw, expected) {
+		t.Fatal(pretty.Sprintf("evaluated module doesn't match expected\n(%# v)\n(%# v)", module.Export.Raw, expected))
+	}
+
+	// Holes that nothing fills are errors unless they're kept.
+	evalContext.KeepUnresolved = false
+	modules, err = evalContext.Parse("module8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	module = &modules[0]
+	err = evalContext.EvaluateModule(module, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid template param (param1)") {
+		t.Fatal(pretty.Sprintf("expected an error for the unresolved param, got (%v) (%# v)", err, module.Export.Raw))
+	}
+
+	// Params win over variables.
+	modules, err = evalContext.Parse("module1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	module = &modules[0]
+	err = evalContext.EvaluateModule(module, map[string]interface{}{"param0": "from params"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.Export.Raw["value"] != "from params" {
+		t.Fatal(pretty.Sprintf("expected the param's value, got (%# v)", module.Export.Raw))
+	}
+}
@@ -15,6 +15,11 @@ func (c *EvalContext) Parse(rootPath string) ([]Module, error) {
 		return nil, serrors.InvalidValueContextErrorf(err, rootPath, "reading module")
 	}
 
+	objs, err = ExpandTemplates(rootPath, objs)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(objs) > 1 {
 		glog.V(1).Infof("(%s) has multiple sections. only the first section can be imported by other modules.", rootPath)
 	}
@@ -3,6 +3,8 @@ package imports
 import (
 	"strings"
 
+	"github.com/golang/glog"
+
 	"github.com/koki/json/jsonutil"
 	"github.com/koki/short/template"
 	serrors "github.com/koki/structurederrors"
@@ -43,6 +45,22 @@ func (c *EvalContext) ResolverForModule(module *Module, params map[string]interf
 			}
 		}
 
+		// Check the variables for the identifier.
+		if c.Variables != nil {
+			if variable, err := c.Variables(segments[0]); err == nil {
+				val, err := jsonutil.AtPathIn(variable, segments[1:])
+				if err != nil {
+					return nil, serrors.InvalidValueContextErrorf(err, variable, "resolving %s", ident)
+				}
+				return val, nil
+			}
+		}
+
+		if c.KeepUnresolved {
+			glog.Warningf("nothing sets (%s) for (%s), leaving it as is", ident, module.Path)
+			return "${" + ident + "}", nil
+		}
+
 		return nil, serrors.InvalidValueErrorf(ident, "invalid template param (%s) for (%s)", ident, module.Path)
 	})
 }
@@ -0,0 +1,244 @@
+package imports
+
+import (
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Templates are fragments (env blocks, probes, resource limits, ...) that are defined once in a
+file's "templates" section and used by name from any resource in the same file.
+
+A dictionary uses a template with the "$template" key, naming one template or a list of them.
+The template's fields are merged into the dictionary. Later templates override earlier ones,
+and the dictionary's own fields override all of them. Nested dictionaries are merged the same
+way, while lists and other values are replaced. A field set to null removes the template's field.
+
+A list item that's only {"$template": NAME}, where the template is a list, is replaced with the
+template's items.
+
+Templates may use other templates, but not themselves.
+
+*/
+
+const (
+	// TemplatesKey is the section of a file that defines its templates.
+	TemplatesKey = "templates"
+	// TemplateRefKey uses a template.
+	TemplateRefKey = "$template"
+)
+
+// DefinesOnlyTemplates is true if the section of a file only defines templates and has no resource.
+func DefinesOnlyTemplates(obj map[string]interface{}) bool {
+	_, ok := obj[TemplatesKey]
+	return ok && len(obj) == 1
+}
+
+// ExpandTemplates takes the templates out of the sections of a file and uses them to fill in its resources.
+// Sections that only define templates are dropped.
+func ExpandTemplates(path string, objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	expander := &templateExpander{
+		path:      path,
+		templates: map[string]interface{}{},
+		expanded:  map[string]interface{}{},
+	}
+
+	resources := []map[string]interface{}{}
+	for _, obj := range objs {
+		if templates, ok := obj[TemplatesKey]; ok {
+			templates, ok := templates.(map[string]interface{})
+			if !ok {
+				return nil, serrors.InvalidInstanceErrorf(obj[TemplatesKey], "expected a dictionary of templates in (%s)", path)
+			}
+			for name, template := range templates {
+				if _, ok := expander.templates[name]; ok {
+					return nil, serrors.InvalidValueErrorf(name, "template (%s) is defined more than once in (%s)", name, path)
+				}
+				expander.templates[name] = template
+			}
+		}
+
+		if !DefinesOnlyTemplates(obj) {
+			resources = append(resources, obj)
+		}
+	}
+
+	for i, obj := range resources {
+		resource := map[string]interface{}{}
+		for key, val := range obj {
+			if key != TemplatesKey {
+				resource[key] = val
+			}
+		}
+
+		expanded, err := expander.expand(resource)
+		if err != nil {
+			return nil, err
+		}
+		resources[i] = expanded.(map[string]interface{})
+	}
+
+	return resources, nil
+}
+
+type templateExpander struct {
+	path      string
+	templates map[string]interface{}
+
+	// expanded templates, by name.
+	expanded map[string]interface{}
+	// expanding are the templates being expanded, innermost last. Used to detect cycles.
+	expanding []string
+}
+
+func (e *templateExpander) expand(val interface{}) (interface{}, error) {
+	switch val := val.(type) {
+	case map[string]interface{}:
+		return e.expandMap(val)
+	case []interface{}:
+		list := []interface{}{}
+		for _, item := range val {
+			if name, ok := onlyTemplateRef(item); ok {
+				template, err := e.template(name)
+				if err != nil {
+					return nil, err
+				}
+				if items, ok := template.([]interface{}); ok {
+					list = append(list, items...)
+					continue
+				}
+			}
+
+			expandedItem, err := e.expand(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, expandedItem)
+		}
+		return list, nil
+	default:
+		return val, nil
+	}
+}
+
+func (e *templateExpander) expandMap(obj map[string]interface{}) (interface{}, error) {
+	result := map[string]interface{}{}
+	if ref, ok := obj[TemplateRefKey]; ok {
+		names, err := templateNames(ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			template, err := e.template(name)
+			if err != nil {
+				return nil, err
+			}
+			templateMap, ok := template.(map[string]interface{})
+			if !ok {
+				return nil, serrors.InvalidInstanceErrorf(template, "template (%s) must be a dictionary to be merged into a dictionary", name)
+			}
+			result = mergeTemplate(result, templateMap)
+		}
+	}
+
+	fields := map[string]interface{}{}
+	for key, val := range obj {
+		if key == TemplateRefKey {
+			continue
+		}
+		expandedVal, err := e.expand(val)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = expandedVal
+	}
+
+	return mergeTemplate(result, fields), nil
+}
+
+// template expands a template by name.
+func (e *templateExpander) template(name string) (interface{}, error) {
+	if expanded, ok := e.expanded[name]; ok {
+		return expanded, nil
+	}
+
+	template, ok := e.templates[name]
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(name, "no template (%s) in (%s)", name, e.path)
+	}
+
+	for i, expanding := range e.expanding {
+		if expanding == name {
+			cycle := append(append([]string{}, e.expanding[i:]...), name)
+			return nil, serrors.InvalidValueErrorf(name, "templates in (%s) use each other in a cycle: %s", e.path, strings.Join(cycle, " -> "))
+		}
+	}
+
+	e.expanding = append(e.expanding, name)
+	expanded, err := e.expand(template)
+	e.expanding = e.expanding[:len(e.expanding)-1]
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "expanding template (%s)", name)
+	}
+
+	e.expanded[name] = expanded
+	return expanded, nil
+}
+
+// onlyTemplateRef gets the template name of a list item that's only a "$template" reference.
+func onlyTemplateRef(item interface{}) (string, bool) {
+	obj, ok := item.(map[string]interface{})
+	if !ok || len(obj) != 1 {
+		return "", false
+	}
+
+	name, ok := obj[TemplateRefKey].(string)
+	return name, ok
+}
+
+func templateNames(ref interface{}) ([]string, error) {
+	switch ref := ref.(type) {
+	case string:
+		return []string{ref}, nil
+	case []interface{}:
+		names := []string{}
+		for _, name := range ref {
+			name, ok := name.(string)
+			if !ok {
+				return nil, serrors.InvalidInstanceErrorf(ref, "expected a template name or a list of template names")
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	default:
+		return nil, serrors.InvalidInstanceErrorf(ref, "expected a template name or a list of template names")
+	}
+}
+
+// mergeTemplate merges the overrides into a copy of the base dictionary.
+func mergeTemplate(base, overrides map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, val := range base {
+		result[key] = val
+	}
+
+	for key, val := range overrides {
+		if _, ok := result[key]; ok && val == nil {
+			delete(result, key)
+			continue
+		}
+
+		baseMap, baseIsMap := result[key].(map[string]interface{})
+		valMap, valIsMap := val.(map[string]interface{})
+		if baseIsMap && valIsMap {
+			result[key] = mergeTemplate(baseMap, valMap)
+			continue
+		}
+
+		result[key] = val
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package imports
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var templates0 = `
+templates:
+  limits:
+    cpu:
+      min: 100m
+      max: 200m
+    mem: 1Gi
+  container:
+    $template: limits
+    image: nginx
+    pull: always
+---
+pod:
+  name: web
+  containers:
+  - $template: container
+    name: web
+    cpu:
+      max: 500m
+    pull: null
+`
+
+var expanded0 = `
+pod:
+  name: web
+  containers:
+  - name: web
+    image: nginx
+    cpu:
+      min: 100m
+      max: 500m
+    mem: 1Gi
+`
+
+func parseDocs(t *testing.T, data string) []map[string]interface{} {
+	objs := []map[string]interface{}{}
+	for _, doc := range strings.Split(data, "\n---\n") {
+		obj := map[string]interface{}{}
+		err := yaml.Unmarshal([]byte(doc), &obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs
+}
+
+func TestExpandTemplates(t *testing.T) {
+	objs, err := ExpandTemplates("templates0", parseDocs(t, templates0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := parseDocs(t, expanded0)
+	if !reflect.DeepEqual(objs, expected) {
+		t.Error(pretty.Diff(objs, expected))
+	}
+}
+
+var cycle0 = `
+templates:
+  a:
+    $template: b
+  b:
+    x:
+      $template: a
+pod:
+  $template: a
+`
+
+func TestExpandTemplatesCycle(t *testing.T) {
+	_, err := ExpandTemplates("cycle0", parseDocs(t, cycle0))
+	if err == nil {
+		t.Fatal("expected an error for templates that use each other")
+	}
+	if !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Error(err)
+	}
+}
@@ -1,5 +1,9 @@
 package imports
 
+import (
+	"github.com/koki/short/template"
+)
+
 type Import struct {
 	Name   string
 	Path   string
@@ -43,4 +47,10 @@ type EvalContext struct {
 
 	// Read the contents of a given path.
 	ReadFromPath func(path string) ([]map[string]interface{}, error)
+
+	// Variables resolves template holes that aren't a param or an import, e.g. from the command line or the environment. Optional.
+	Variables template.Resolver
+
+	// KeepUnresolved leaves template holes that nothing resolves as they are, instead of failing.
+	KeepUnresolved bool
 }
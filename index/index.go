@@ -0,0 +1,165 @@
+package index
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Index writes short objects as a SQL script for SQLite, e.g.
+
+	short index -f manifests/ | sqlite3 manifests.sqlite
+
+The "objects" table has one row per object. "labels" and "body" are JSON, so they
+can be queried with SQLite's JSON functions:
+
+	SELECT name FROM objects WHERE kind = 'deployment' AND json_extract(body, '$.replicas') < 2;
+
+The script also creates a few views for common queries (see Views). Load runs the script with
+the sqlite3 command line tool instead, replacing the objects of an existing database.
+
+*/
+
+const Schema = `CREATE TABLE IF NOT EXISTS objects (
+  kind TEXT NOT NULL,
+  name TEXT,
+  namespace TEXT,
+  labels TEXT,
+  body TEXT NOT NULL
+);
+`
+
+// Views are canned queries over the objects table.
+var Views = []string{
+	`CREATE VIEW IF NOT EXISTS containers AS
+  SELECT o.kind, o.name, o.namespace,
+    json_extract(c.value, '$.name') AS container,
+    json_extract(c.value, '$.image') AS image,
+    json_extract(c.value, '$.cpu') AS cpu,
+    json_extract(c.value, '$.mem') AS mem
+  FROM objects o, json_each(o.body, '$.containers') c;
+`,
+	`CREATE VIEW IF NOT EXISTS images AS
+  SELECT image, count(*) AS uses FROM containers GROUP BY image;
+`,
+	`CREATE VIEW IF NOT EXISTS unlimited_containers AS
+  SELECT * FROM containers WHERE json_extract(mem, '$.max') IS NULL OR json_extract(cpu, '$.max') IS NULL;
+`,
+	`CREATE VIEW IF NOT EXISTS privileged_containers AS
+  SELECT o.kind, o.name, o.namespace, json_extract(c.value, '$.name') AS container
+  FROM objects o, json_each(o.body, '$.containers') c
+  WHERE json_extract(c.value, '$.privileged') = 1;
+`,
+}
+
+// WriteSQL writes the schema, views and one INSERT per object.
+// Each object is a short object, i.e. it has a single top-level key for its kind.
+func WriteSQL(w io.Writer, objs []map[string]interface{}) error {
+	return writeSQL(w, objs, false)
+}
+
+// Load writes the objects into the SQLite database at path, which is created if it doesn't exist. The objects
+// already in the database are replaced. It needs the sqlite3 command line tool, with JSON support.
+func Load(path string, objs []map[string]interface{}) error {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return serrors.ContextualizeErrorf(err, "loading %s needs the sqlite3 command line tool", path)
+	}
+
+	script := &bytes.Buffer{}
+	err := writeSQL(script, objs, true)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sqlite3", "-bail", path)
+	cmd.Stdin = script
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "sqlite3 %s: %s", path, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// writeSQL is WriteSQL, deleting the objects that are already in the table first if replace is set.
+func writeSQL(w io.Writer, objs []map[string]interface{}, replace bool) error {
+	if _, err := io.WriteString(w, "BEGIN TRANSACTION;\n"+Schema); err != nil {
+		return err
+	}
+	if replace {
+		if _, err := io.WriteString(w, "DELETE FROM objects;\n"); err != nil {
+			return err
+		}
+	}
+	for _, view := range Views {
+		if _, err := io.WriteString(w, view); err != nil {
+			return err
+		}
+	}
+
+	for _, obj := range objs {
+		kinds := make([]string, 0, len(obj))
+		for kind := range obj {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			stmt, err := insertStatement(kind, obj[kind])
+			if err != nil {
+				return serrors.ContextualizeErrorf(err, kind)
+			}
+			if _, err := io.WriteString(w, stmt); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, "COMMIT;\n")
+	return err
+}
+
+func insertStatement(kind string, body interface{}) (string, error) {
+	bodyMap, _ := body.(map[string]interface{})
+	name, _ := bodyMap["name"].(string)
+	namespace, _ := bodyMap["namespace"].(string)
+
+	labels := "NULL"
+	if bodyLabels, ok := bodyMap["labels"]; ok {
+		b, err := json.Marshal(bodyLabels)
+		if err != nil {
+			return "", serrors.InvalidValueContextErrorf(err, bodyLabels, "marshalling labels to JSON")
+		}
+		labels = quote(string(b))
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", serrors.InvalidValueContextErrorf(err, body, "marshalling to JSON")
+	}
+
+	return fmt.Sprintf("INSERT INTO objects (kind, name, namespace, labels, body) VALUES (%s, %s, %s, %s, %s);\n",
+		quote(kind), quoteOrNull(name), quoteOrNull(namespace), labels, quote(string(b))), nil
+}
+
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+func quoteOrNull(s string) string {
+	if len(s) == 0 {
+		return "NULL"
+	}
+
+	return quote(s)
+}
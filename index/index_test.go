@@ -0,0 +1,89 @@
+package index
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSQL(t *testing.T) {
+	objs := []map[string]interface{}{
+		{
+			"deployment": map[string]interface{}{
+				"name":   "web",
+				"labels": map[string]interface{}{"app": "o'brien"},
+			},
+		},
+		{
+			"service": map[string]interface{}{},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := WriteSQL(buf, objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`INSERT INTO objects (kind, name, namespace, labels, body) VALUES ('deployment', 'web', NULL, '{"app":"o''brien"}', '{"labels":{"app":"o''brien"},"name":"web"}');`,
+		`INSERT INTO objects (kind, name, namespace, labels, body) VALUES ('service', NULL, NULL, NULL, '{}');`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("missing (%s) in:\n%s", expected, buf.String())
+		}
+	}
+	if !strings.HasSuffix(buf.String(), "COMMIT;\n") {
+		t.Error("expected the script to end with COMMIT")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 isn't installed")
+	}
+	dir, err := ioutil.TempDir("", "index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db := filepath.Join(dir, "manifests.sqlite")
+
+	objs := []map[string]interface{}{
+		{
+			"deployment": map[string]interface{}{
+				"name": "web",
+				"containers": []interface{}{
+					map[string]interface{}{"name": "web", "image": "nginx", "privileged": true},
+					map[string]interface{}{"name": "cache", "image": "redis", "mem": map[string]interface{}{"max": "1G"}, "cpu": map[string]interface{}{"max": "1"}},
+				},
+			},
+		},
+	}
+	// Loading again replaces the objects instead of adding them twice.
+	for i := 0; i < 2; i++ {
+		err = Load(db, objs)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for query, expected := range map[string]string{
+		"SELECT count(*) FROM objects":                            "1",
+		"SELECT image, uses FROM images ORDER BY image":           "nginx|1\nredis|1",
+		"SELECT container FROM unlimited_containers":              "web",
+		"SELECT kind, name, container FROM privileged_containers": "deployment|web|web",
+	} {
+		out, err := exec.Command("sqlite3", db, query).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(out)) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", query, expected, out)
+		}
+	}
+}
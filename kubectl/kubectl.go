@@ -0,0 +1,128 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/koki/json"
+	"github.com/koki/short/parser"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Kubectl runs kubectl for the commands that talk to a cluster, so short can be used as a kubectl
+plugin ("kubectl short get deploy web") without linking a Kubernetes client.
+
+Flags has the same kubeconfig, context and namespace flags as kubectl, and passes them on.
+
+*/
+
+// Flags select the cluster and namespace, like kubectl's flags of the same names.
+type Flags struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+}
+
+// AddTo adds the flags to a command's flag set.
+func (f *Flags) AddTo(flags *pflag.FlagSet) {
+	flags.StringVar(&f.Kubeconfig, "kubeconfig", "", "path to the kubeconfig file to use")
+	flags.StringVar(&f.Context, "context", "", "kubeconfig context to use (default the current context)")
+	flags.StringVarP(&f.Namespace, "namespace", "n", "", "namespace to use (default the context's namespace)")
+}
+
+// Args are the kubectl arguments for the flags that are set.
+func (f *Flags) Args() []string {
+	args := []string{}
+	if len(f.Kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", f.Kubeconfig)
+	}
+	if len(f.Context) > 0 {
+		args = append(args, "--context", f.Context)
+	}
+	if len(f.Namespace) > 0 {
+		args = append(args, "--namespace", f.Namespace)
+	}
+
+	return args
+}
+
+// Output runs kubectl and returns what it writes to stdout.
+func (f *Flags) Output(args ...string) ([]byte, error) {
+	return f.OutputContext(context.Background(), args...)
+}
+
+// OutputContext is Output, killing kubectl when ctx is done.
+func (f *Flags) OutputContext(ctx context.Context, args ...string) ([]byte, error) {
+	return f.Pipe(ctx, nil, args...)
+}
+
+// Pipe runs kubectl with the input on stdin, and returns what it writes to stdout.
+func (f *Flags) Pipe(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	args = append(args, f.Args()...)
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stdin = stdin
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "kubectl %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// Run runs kubectl with the input on stdin, passing its output through.
+func (f *Flags) Run(stdin io.Reader, args ...string) error {
+	return f.RunContext(context.Background(), stdin, args...)
+}
+
+// RunContext is Run, killing kubectl when ctx is done.
+func (f *Flags) RunContext(ctx context.Context, stdin io.Reader, args ...string) error {
+	args = append(args, f.Args()...)
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "kubectl %s", strings.Join(args, " "))
+	}
+
+	return nil
+}
+
+// Get runs "kubectl get" and returns the objects it finds.
+func (f *Flags) Get(args ...string) ([]map[string]interface{}, error) {
+	return f.GetContext(context.Background(), args...)
+}
+
+// GetContext is Get, killing kubectl when ctx is done. Fetching every object of a big cluster can
+// take a while, so it's worth cancelling when the caller gives up.
+func (f *Flags) GetContext(ctx context.Context, args ...string) ([]map[string]interface{}, error) {
+	out, err := f.OutputContext(ctx, append([]string{"get", "-o", "json"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]interface{}{}
+	err = json.Unmarshal(out, &obj)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "parsing kubectl output")
+	}
+
+	return parser.FlattenList(obj)
+}
@@ -0,0 +1,82 @@
+package kubectl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+// fakeKubectl puts a kubectl on the PATH that prints the output.
+func fakeKubectl(t *testing.T, output string) func() {
+	return fakeKubectlScript(t, "cat <<'EOF'\n"+output+"\nEOF\n")
+}
+
+// fakeKubectlScript puts a kubectl on the PATH that runs a shell script.
+func fakeKubectlScript(t *testing.T, script string) func() {
+	dir, err := ioutil.TempDir("", "kubectl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script = "#!/bin/sh\n" + script
+	err = ioutil.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	return func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGet(t *testing.T) {
+	cleanup := fakeKubectl(t, `{"kind": "List", "items": [{"kind": "Pod", "metadata": {"name": "a"}}, {"kind": "Pod", "metadata": {"name": "b"}}]}`)
+	defer cleanup()
+
+	flags := Flags{Context: "staging", Namespace: "web"}
+	objs, err := flags.Get("pods")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]interface{}{
+		{"kind": "Pod", "metadata": map[string]interface{}{"name": "a"}},
+		{"kind": "Pod", "metadata": map[string]interface{}{"name": "b"}},
+	}
+	if !reflect.DeepEqual(objs, expected) {
+		t.Error(pretty.Diff(objs, expected))
+	}
+}
+
+func TestGetContext(t *testing.T) {
+	cleanup := fakeKubectlScript(t, "exec sleep 60\n")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := (&Flags{}).GetContext(ctx, "pods")
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected kubectl to be killed, took %s", elapsed)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	flags := Flags{Kubeconfig: "/tmp/config", Namespace: "web"}
+	expected := []string{"--kubeconfig", "/tmp/config", "--namespace", "web"}
+	if args := flags.Args(); !reflect.DeepEqual(args, expected) {
+		t.Error(pretty.Diff(args, expected))
+	}
+}
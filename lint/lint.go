@@ -0,0 +1,281 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/site"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Lint checks manifests against best-practice rules (see Rules), and checks the references
+between the objects of a stream, which each object's own validation can't see:
+
+  - objects that use a ConfigMap, Secret, PVC or ServiceAccount that isn't in the stream
+  - Services whose selector matches no pod template in the stream
+  - Ingresses whose backends are Services that aren't in the stream
+
+Optional references (e.g. an optional secretKeyRef) and the "default" ServiceAccount are
+always satisfied. Reference paths are in Kubernetes syntax, while the best-practice rules
+check short objects and report paths in short syntax.
+
+*/
+
+// Warning is a problem found by a rule.
+type Warning struct {
+	Rule      string
+	File      string
+	Kind      string
+	Namespace string
+	Name      string
+	// Path is the location of the problem, e.g. "$.spec.volumes.0.secret" or "$.deployment.containers.0.cpu".
+	Path    string
+	Message string
+	// Labels are the object's labels, to find its owner (see the owners package).
+	Labels map[string]string
+}
+
+func (w Warning) String() string {
+	name := w.Name
+	if len(w.Namespace) > 0 {
+		name = w.Namespace + "/" + w.Name
+	}
+
+	return fmt.Sprintf("%s (%s): %s: %s [%s]", w.Kind, name, w.Path, w.Message, w.Rule)
+}
+
+// shortKinds are the short kinds of the Kubernetes kinds that can be referenced.
+var shortKinds = map[string]string{
+	"ConfigMap":             "config_map",
+	"Secret":                "secret",
+	"PersistentVolumeClaim": "pvc",
+	"ServiceAccount":        "service_account",
+}
+
+// podTemplatePaths are the locations of the pod template metadata in each kind that has one.
+var podTemplatePaths = map[string][]string{
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "metadata"},
+	"DaemonSet":             {"spec", "template", "metadata"},
+	"Deployment":            {"spec", "template", "metadata"},
+	"Job":                   {"spec", "template", "metadata"},
+	"Pod":                   {"metadata"},
+	"ReplicaSet":            {"spec", "template", "metadata"},
+	"ReplicationController": {"spec", "template", "metadata"},
+	"StatefulSet":           {"spec", "template", "metadata"},
+}
+
+// Files converts the objects in each file, in short or Kubernetes syntax, and checks them together with the given rules.
+func Files(files []string, rules []string) ([]Warning, error) {
+	objs := []map[string]interface{}{}
+	objFiles := []string{}
+	ruleWarnings := []Warning{}
+	for _, file := range files {
+		fileObjs, err := parser.Parse([]string{file}, false)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "parsing %s", file)
+		}
+
+		kokiObjs, err := client.ConvertEitherMapsToKoki(fileObjs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		kubeObjs, err := client.ConvertKokiMaps(kokiObjs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		for i, kubeObj := range kubeObjs {
+			obj, err := objutil.ToDictionary(kubeObj)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+			objFiles = append(objFiles, file)
+
+			kokiObj, err := parser.ParseKokiNativeObject(kokiObjs[i])
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "parsing %s", file)
+			}
+			kind, namespace, name := identify(obj)
+			for _, warning := range CheckRules(kind, kokiObj, rules) {
+				warning.File, warning.Kind, warning.Namespace, warning.Name = file, kind, namespace, name
+				warning.Labels = objutil.Labels(obj)
+				ruleWarnings = append(ruleWarnings, warning)
+			}
+		}
+	}
+
+	warnings := []Warning{}
+	if hasRule(rules, ReferencesRule) {
+		refWarnings, objIndices, err := check(objs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range refWarnings {
+			refWarnings[i].File = objFiles[objIndices[i]]
+			refWarnings[i].Labels = objutil.Labels(objs[objIndices[i]])
+		}
+		warnings = append(warnings, refWarnings...)
+	}
+
+	return append(warnings, ruleWarnings...), nil
+}
+
+// Check checks the references between Kubernetes objects.
+func Check(objs []map[string]interface{}) ([]Warning, error) {
+	warnings, _, err := check(objs)
+	return warnings, err
+}
+
+// check also returns the index of the object of each warning.
+func check(objs []map[string]interface{}) ([]Warning, []int, error) {
+	defined := map[site.ID]bool{}
+	services := map[string]bool{}
+	for _, obj := range objs {
+		kind, namespace, name := identify(obj)
+		if shortKind, ok := shortKinds[kind]; ok {
+			defined[site.ID{Kind: shortKind, Namespace: namespace, Name: name}] = true
+		}
+		if kind == "Service" {
+			services[namespace+"/"+name] = true
+		}
+	}
+
+	warnings := []Warning{}
+	objIndices := []int{}
+	for i, obj := range objs {
+		kind, namespace, name := identify(obj)
+		warn := func(path, message string) {
+			warnings = append(warnings, Warning{Rule: ReferencesRule, Kind: kind, Namespace: namespace, Name: name, Path: path, Message: message})
+			objIndices = append(objIndices, i)
+		}
+
+		refs, err := site.FindRefPaths(namespace, obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, ref := range refs {
+			if ref.Optional || defined[ref.ID] {
+				continue
+			}
+			if ref.Kind == "service_account" && ref.Name == client.DefaultServiceAccount {
+				continue
+			}
+			warn(ref.Path, fmt.Sprintf("uses %s (%s), which isn't in the input", ref.Kind, ref.Name))
+		}
+
+		switch kind {
+		case "Service":
+			spec, _ := obj["spec"].(map[string]interface{})
+			selector, _ := spec["selector"].(map[string]interface{})
+			if len(selector) > 0 && !selectsPods(objs, namespace, selector) {
+				warn("$.spec.selector", "selects no pods in the input")
+			}
+		case "Ingress":
+			for _, backend := range ingressBackends(obj) {
+				if !services[namespace+"/"+backend.service] {
+					warn(backend.path, fmt.Sprintf("routes to service (%s), which isn't in the input", backend.service))
+				}
+			}
+		}
+	}
+
+	return warnings, objIndices, nil
+}
+
+func hasRule(rules []string, rule string) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func identify(obj map[string]interface{}) (kind, namespace, name string) {
+	kind, _ = obj["kind"].(string)
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		namespace, _ = metadata["namespace"].(string)
+		name, _ = metadata["name"].(string)
+	}
+
+	return kind, namespace, name
+}
+
+// selectsPods is true if the selector matches the labels of a pod or pod template in the namespace.
+func selectsPods(objs []map[string]interface{}, namespace string, selector map[string]interface{}) bool {
+	for _, obj := range objs {
+		kind, objNamespace, _ := identify(obj)
+		path, ok := podTemplatePaths[kind]
+		if !ok || objNamespace != namespace {
+			continue
+		}
+
+		metadata := atPath(obj, path)
+		labels, _ := metadata["labels"].(map[string]interface{})
+		if matches(selector, labels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matches(selector, labels map[string]interface{}) bool {
+	for key, val := range selector {
+		if labels[key] != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+func atPath(obj map[string]interface{}, path []string) map[string]interface{} {
+	for _, segment := range path {
+		obj, _ = obj[segment].(map[string]interface{})
+	}
+
+	return obj
+}
+
+type ingressBackend struct {
+	service string
+	path    string
+}
+
+// ingressBackends lists the Services an Ingress routes to, in path order.
+func ingressBackends(obj map[string]interface{}) []ingressBackend {
+	backends := []ingressBackend{}
+	add := func(backend map[string]interface{}, path string) {
+		if service, ok := backend["serviceName"].(string); ok && len(service) > 0 {
+			backends = append(backends, ingressBackend{service: service, path: path + ".serviceName"})
+		}
+	}
+
+	spec, _ := obj["spec"].(map[string]interface{})
+	if backend, ok := spec["backend"].(map[string]interface{}); ok {
+		add(backend, "$.spec.backend")
+	}
+
+	rules, _ := spec["rules"].([]interface{})
+	for i, rule := range rules {
+		ruleMap, _ := rule.(map[string]interface{})
+		http, _ := ruleMap["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for j, path := range paths {
+			pathMap, _ := path.(map[string]interface{})
+			if backend, ok := pathMap["backend"].(map[string]interface{}); ok {
+				add(backend, fmt.Sprintf("$.spec.rules.%d.http.paths.%d.backend", i, j))
+			}
+		}
+	}
+
+	return backends
+}
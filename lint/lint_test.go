@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestCheck(t *testing.T) {
+	objs := []map[string]interface{}{
+		{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"namespace": "prod", "name": "web"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+					"spec": map[string]interface{}{
+						"serviceAccountName": "default",
+						"volumes": []interface{}{
+							map[string]interface{}{"name": "a", "secret": map[string]interface{}{"secretName": "creds"}},
+							map[string]interface{}{"name": "b", "secret": map[string]interface{}{"secretName": "extra", "optional": true}},
+							map[string]interface{}{"name": "c", "configMap": map[string]interface{}{"name": "settings"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			"kind":     "ConfigMap",
+			"metadata": map[string]interface{}{"namespace": "prod", "name": "settings"},
+		},
+		{
+			"kind":     "Service",
+			"metadata": map[string]interface{}{"namespace": "prod", "name": "web"},
+			"spec":     map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+		},
+		{
+			"kind":     "Service",
+			"metadata": map[string]interface{}{"namespace": "prod", "name": "typo"},
+			"spec":     map[string]interface{}{"selector": map[string]interface{}{"app": "wbe"}},
+		},
+		{
+			"kind":     "Ingress",
+			"metadata": map[string]interface{}{"namespace": "prod", "name": "web"},
+			"spec": map[string]interface{}{
+				"backend": map[string]interface{}{"serviceName": "web", "servicePort": 80},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"http": map[string]interface{}{
+							"paths": []interface{}{
+								map[string]interface{}{"backend": map[string]interface{}{"serviceName": "api", "servicePort": 80}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	warnings, err := Check(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Warning{
+		{Rule: ReferencesRule, Kind: "Deployment", Namespace: "prod", Name: "web", Path: "$.spec.template.spec.volumes.0.secret", Message: "uses secret (creds), which isn't in the input"},
+		{Rule: ReferencesRule, Kind: "Service", Namespace: "prod", Name: "typo", Path: "$.spec.selector", Message: "selects no pods in the input"},
+		{Rule: ReferencesRule, Kind: "Ingress", Namespace: "prod", Name: "web", Path: "$.spec.rules.0.http.paths.0.backend.serviceName", Message: "routes to service (api), which isn't in the input"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Error(pretty.Diff(warnings, expected))
+	}
+}
+
+func TestFilesRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "jobs.short.yaml")
+	err = ioutil.WriteFile(file, []byte(`
+cron_job:
+  name: backup
+  version: batch/v1beta1
+  schedule: '0 * * * *'
+  restart_policy: on-failure
+  containers:
+  - name: backup
+    image: registry:5000/backup
+    privileged: true
+    cpu:
+      max: 100m
+    mem:
+      max: 100Mi
+    volume:
+    - mount: /data
+      store: data
+  volumes:
+    data: host_path:/var/data
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := SelectRules(nil, []string{ReferencesRule})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := Files([]string{file}, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Warning{
+		{Rule: "host-path", File: file, Kind: "CronJob", Name: "backup", Path: "$.cron_job.volumes.data", Message: "volume (data) mounts /var/data from the node"},
+		{Rule: "latest-tag", File: file, Kind: "CronJob", Name: "backup", Path: "$.cron_job.containers.0.image", Message: "image (registry:5000/backup) isn't pinned to a tag"},
+		{Rule: "privileged", File: file, Kind: "CronJob", Name: "backup", Path: "$.cron_job.containers.0.privileged", Message: "container (backup) is privileged"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Error(pretty.Diff(warnings, expected))
+	}
+
+	_, err = SelectRules([]string{"no-such-rule"}, nil)
+	if err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
@@ -0,0 +1,256 @@
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/types"
+	serrors "github.com/koki/structurederrors"
+)
+
+// ReferencesRule is the name of the checks of references between objects.
+const ReferencesRule = "references"
+
+// Rule is a best-practice check of the pods in a short object.
+type Rule struct {
+	Name        string
+	Description string
+	// Check returns a message for each problem, keyed by its path relative to the pod template.
+	Check func(kind string, pod *types.PodTemplate) map[string]string
+}
+
+// Rules are the best-practice rules, by name.
+var Rules = map[string]Rule{
+	"resource-limits": {
+		Name:        "resource-limits",
+		Description: "containers should have cpu and mem limits (max)",
+		Check:       checkResourceLimits,
+	},
+	"latest-tag": {
+		Name:        "latest-tag",
+		Description: "images should have a tag other than latest, or a digest",
+		Check:       checkLatestTag,
+	},
+	"liveness-probe": {
+		Name:        "liveness-probe",
+		Description: "containers of long-running pods should have a liveness probe",
+		Check:       checkLivenessProbe,
+	},
+	"privileged": {
+		Name:        "privileged",
+		Description: "containers shouldn't be privileged",
+		Check:       checkPrivileged,
+	},
+	"host-path": {
+		Name:        "host-path",
+		Description: "pods shouldn't mount directories of the node (host_path volumes)",
+		Check:       checkHostPath,
+	},
+}
+
+// RuleNames lists every rule, including the reference checks, in order.
+func RuleNames() []string {
+	names := []string{ReferencesRule}
+	for name := range Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
+
+// SelectRules picks the rules to run: the given ones (default all), except the skipped ones.
+func SelectRules(only, skip []string) ([]string, error) {
+	known := map[string]bool{}
+	for _, name := range RuleNames() {
+		known[name] = true
+	}
+	for _, name := range append(append([]string{}, only...), skip...) {
+		if !known[name] {
+			return nil, serrors.InvalidValueErrorf(name, "unknown lint rule, expected one of %s", strings.Join(RuleNames(), ", "))
+		}
+	}
+
+	if len(only) == 0 {
+		only = RuleNames()
+	}
+	skipped := map[string]bool{}
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	selected := []string{}
+	for _, name := range only {
+		if !skipped[name] {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+// CheckRules runs the best-practice rules on a short object (a parsed wrapper, e.g. *types.DeploymentWrapper).
+// It returns the problems with paths in short syntax, e.g. "$.deployment.containers.0.cpu".
+func CheckRules(kind string, kokiObj interface{}, rules []string) []Warning {
+	v := reflect.ValueOf(kokiObj)
+	if v.Kind() != reflect.Ptr {
+		// Pod templates are returned by pointer, so they need to be addressable.
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+
+	warnings := []Warning{}
+	for _, found := range findPodTemplates(v, "$") {
+		for _, name := range rules {
+			rule, ok := Rules[name]
+			if !ok {
+				continue
+			}
+
+			problems := rule.Check(kind, found.pod)
+			paths := []string{}
+			for path := range problems {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			for _, path := range paths {
+				warnings = append(warnings, Warning{Rule: name, Path: found.path + path, Message: problems[path]})
+			}
+		}
+	}
+
+	return warnings
+}
+
+type podTemplateAt struct {
+	pod  *types.PodTemplate
+	path string
+}
+
+var podTemplateType = reflect.TypeOf(types.PodTemplate{})
+
+// findPodTemplates finds the pod templates in a short object, with their paths (by JSON field name).
+func findPodTemplates(v reflect.Value, path string) []podTemplateAt {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	if v.Type() == podTemplateType && v.CanAddr() {
+		return []podTemplateAt{{pod: v.Addr().Interface().(*types.PodTemplate), path: path}}
+	}
+
+	found := []podTemplateAt{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if len(field.PkgPath) > 0 {
+			// Unexported.
+			continue
+		}
+
+		fieldPath := path
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.Anonymous && len(name) > 0 && name != "-" {
+			fieldPath = path + "." + name
+		}
+		found = append(found, findPodTemplates(v.Field(i), fieldPath)...)
+	}
+
+	return found
+}
+
+// eachContainer calls fn with each container and its path.
+func eachContainer(pod *types.PodTemplate, fn func(path string, container *types.Container)) {
+	for i := range pod.InitContainers {
+		fn(fmt.Sprintf(".init_containers.%d", i), &pod.InitContainers[i])
+	}
+	for i := range pod.Containers {
+		fn(fmt.Sprintf(".containers.%d", i), &pod.Containers[i])
+	}
+}
+
+func checkResourceLimits(kind string, pod *types.PodTemplate) map[string]string {
+	problems := map[string]string{}
+	eachContainer(pod, func(path string, container *types.Container) {
+		if container.CPU == nil || len(container.CPU.Max) == 0 {
+			problems[path+".cpu"] = fmt.Sprintf("container (%s) has no cpu limit", container.Name)
+		}
+		if container.Mem == nil || len(container.Mem.Max) == 0 {
+			problems[path+".mem"] = fmt.Sprintf("container (%s) has no mem limit", container.Name)
+		}
+	})
+
+	return problems
+}
+
+func checkLatestTag(kind string, pod *types.PodTemplate) map[string]string {
+	problems := map[string]string{}
+	eachContainer(pod, func(path string, container *types.Container) {
+		image := container.Image
+		if len(image) == 0 || strings.Contains(image, "@") {
+			return
+		}
+
+		tag := ""
+		// The tag is after the last ":" that comes after the last "/" (a registry may have a port).
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			tag = image[i+1:]
+		}
+		if len(tag) == 0 || tag == "latest" {
+			problems[path+".image"] = fmt.Sprintf("image (%s) isn't pinned to a tag", image)
+		}
+	})
+
+	return problems
+}
+
+// shortLivedKinds run pods to completion, so they don't need liveness probes.
+var shortLivedKinds = map[string]bool{
+	"Job":     true,
+	"CronJob": true,
+}
+
+func checkLivenessProbe(kind string, pod *types.PodTemplate) map[string]string {
+	problems := map[string]string{}
+	if shortLivedKinds[kind] {
+		return problems
+	}
+
+	for i := range pod.Containers {
+		container := &pod.Containers[i]
+		if container.LivenessProbe == nil {
+			problems[fmt.Sprintf(".containers.%d", i)] = fmt.Sprintf("container (%s) has no liveness probe", container.Name)
+		}
+	}
+
+	return problems
+}
+
+func checkPrivileged(kind string, pod *types.PodTemplate) map[string]string {
+	problems := map[string]string{}
+	eachContainer(pod, func(path string, container *types.Container) {
+		if container.Privileged != nil && *container.Privileged {
+			problems[path+".privileged"] = fmt.Sprintf("container (%s) is privileged", container.Name)
+		}
+	})
+
+	return problems
+}
+
+func checkHostPath(kind string, pod *types.PodTemplate) map[string]string {
+	problems := map[string]string{}
+	for name, volume := range pod.Volumes {
+		if volume.HostPath != nil {
+			problems[".volumes."+name] = fmt.Sprintf("volume (%s) mounts %s from the node", name, volume.HostPath.Path)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,330 @@
+package lock
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/imports"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/util/objutil"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A lock file pins everything a set of Short manifests depends on:
+
+	imports:
+	- path: lib/app.short.yaml
+	  sha256: 3f2a...
+	images:
+	- image: nginx:1.13
+	  digest: sha256:9d7c...
+
+Import paths are relative to the lock file, so the vendor directory can be used from any
+working directory. In memory, they're relative to the working directory like any other path.
+
+Vendor copies the imported files into a directory next to the lock file, so
+conversions can read them from there (see VendoredReader) without the original tree.
+When a lock file is used for a conversion, each locked image with a digest is pinned to it
+(see PinImages).
+
+*/
+
+const (
+	LockFilename = "short.lock"
+	ImportsDir   = "imports"
+)
+
+type Lock struct {
+	Imports []LockedImport `json:"imports,omitempty"`
+	Images  []LockedImage  `json:"images,omitempty"`
+}
+
+type LockedImport struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+type LockedImage struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// CollectImports finds every file imported (directly or indirectly) by the given Short files.
+func CollectImports(filenames []string) ([]string, error) {
+	evalContext := imports.EvalContext{
+		ResolveImportPath: imports.ResolveImportLocalPath,
+		ReadFromPath:      imports.ReadFromLocalPath,
+	}
+
+	seen := map[string]bool{}
+	paths := []string{}
+	var visit func(modules []imports.Module)
+	visit = func(modules []imports.Module) {
+		for _, module := range modules {
+			for _, imprt := range module.Imports {
+				path := filepath.Clean(imprt.Path)
+				if seen[path] {
+					continue
+				}
+				seen[path] = true
+				paths = append(paths, path)
+
+				if imprt.Module != nil {
+					visit([]imports.Module{*imprt.Module})
+				}
+			}
+		}
+	}
+
+	for _, filename := range filenames {
+		modules, err := evalContext.Parse(filename)
+		if err != nil {
+			return nil, err
+		}
+		visit(modules)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CollectImages finds the container images used by objects in either Short or Kubernetes syntax.
+func CollectImages(objs []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	images := []string{}
+	var walk func(key string, obj interface{})
+	walk = func(key string, obj interface{}) {
+		switch obj := obj.(type) {
+		case map[string]interface{}:
+			for childKey, val := range obj {
+				walk(childKey, val)
+			}
+		case []interface{}:
+			isContainers := key == "containers" || key == "init_containers" || key == "initContainers"
+			for _, val := range obj {
+				if container, ok := val.(map[string]interface{}); ok && isContainers {
+					if image, ok := container["image"].(string); ok && len(image) > 0 && !seen[image] {
+						seen[image] = true
+						images = append(images, image)
+					}
+				}
+				walk(key, val)
+			}
+		}
+	}
+
+	for _, obj := range objs {
+		walk("", obj)
+	}
+
+	sort.Strings(images)
+	return images
+}
+
+// Build hashes the imports and resolves the images to digests. If resolver is nil, digests are left empty.
+func Build(importPaths []string, images []string, resolver Resolver) (*Lock, error) {
+	lock := &Lock{}
+	for _, path := range importPaths {
+		hash, err := hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		lock.Imports = append(lock.Imports, LockedImport{Path: path, SHA256: hash})
+	}
+
+	for _, image := range images {
+		locked := LockedImage{Image: image}
+		if resolver != nil {
+			digest, err := resolver.Digest(image)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "resolving digest for image (%s)", image)
+			}
+			locked.Digest = digest
+		}
+		lock.Images = append(lock.Images, locked)
+	}
+
+	return lock, nil
+}
+
+func hashFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "reading %s", path)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+// Vendor writes the lock file and copies each import into dir.
+func Vendor(lock *Lock, dir string) error {
+	importsDir := filepath.Join(dir, ImportsDir)
+	err := os.MkdirAll(importsDir, 0755)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "creating %s", importsDir)
+	}
+
+	for _, imprt := range lock.Imports {
+		b, err := ioutil.ReadFile(imprt.Path)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "reading %s", imprt.Path)
+		}
+
+		vendoredPath := filepath.Join(importsDir, imprt.SHA256+".yaml")
+		err = ioutil.WriteFile(vendoredPath, b, 0644)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "writing %s", vendoredPath)
+		}
+	}
+
+	written := &Lock{Images: lock.Images}
+	for _, imprt := range lock.Imports {
+		path, err := relativePath(dir, imprt.Path)
+		if err != nil {
+			return err
+		}
+		written.Imports = append(written.Imports, LockedImport{Path: path, SHA256: imprt.SHA256})
+	}
+
+	b, err := yaml.Marshal(written)
+	if err != nil {
+		return serrors.InvalidInstanceContextErrorf(err, written, "marshalling lock file")
+	}
+
+	lockPath := filepath.Join(dir, LockFilename)
+	err = ioutil.WriteFile(lockPath, b, 0644)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "writing %s", lockPath)
+	}
+
+	return nil
+}
+
+// ReadLock reads the lock file in a vendor directory.
+func ReadLock(dir string) (*Lock, error) {
+	lockPath := filepath.Join(dir, LockFilename)
+	b, err := ioutil.ReadFile(lockPath)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading %s", lockPath)
+	}
+
+	lock := &Lock{}
+	err = yaml.Unmarshal(b, lock)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextError(err, string(b), lock)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading %s", lockPath)
+	}
+	for i, imprt := range lock.Imports {
+		if !filepath.IsAbs(imprt.Path) {
+			lock.Imports[i].Path, err = relativePath(wd, filepath.Join(dir, imprt.Path))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return lock, nil
+}
+
+// relativePath returns path relative to dir, either of which can be relative to the working directory.
+func relativePath(dir, path string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "finding %s", dir)
+	}
+	rel, err := filepath.Rel(absDir, absPath(path))
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "finding %s from %s", path, dir)
+	}
+
+	return rel, nil
+}
+
+// absPath returns the absolute form of path, so paths that are written differently can be compared.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	return abs
+}
+
+// VendoredReader reads locked imports from the vendor directory instead of their original paths.
+// Other paths are read as usual.
+func VendoredReader(lock *Lock, dir string) func(path string) ([]map[string]interface{}, error) {
+	vendored := map[string]string{}
+	for _, imprt := range lock.Imports {
+		vendored[absPath(imprt.Path)] = filepath.Join(dir, ImportsDir, imprt.SHA256+".yaml")
+	}
+
+	return func(path string) ([]map[string]interface{}, error) {
+		if vendoredPath, ok := vendored[absPath(path)]; ok {
+			path = vendoredPath
+		}
+
+		return parser.Parse([]string{path}, false)
+	}
+}
+
+// PinImages adds the locked digest to each image of an object in either syntax, e.g. "nginx:1.13" becomes
+// "nginx:1.13@sha256:9d7c...". Images that are already pinned, or that have no locked digest, are left as they are.
+// The object is returned as a dictionary if any image was pinned.
+func PinImages(obj interface{}, lock *Lock) (interface{}, error) {
+	digests := map[string]string{}
+	for _, image := range lock.Images {
+		if len(image.Digest) > 0 {
+			digests[image.Image] = image.Digest
+		}
+	}
+	if len(digests) == 0 {
+		return obj, nil
+	}
+
+	dict, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := false
+	var walk func(key string, obj interface{})
+	walk = func(key string, obj interface{}) {
+		switch obj := obj.(type) {
+		case map[string]interface{}:
+			for childKey, val := range obj {
+				walk(childKey, val)
+			}
+		case []interface{}:
+			isContainers := key == "containers" || key == "init_containers" || key == "initContainers"
+			for _, val := range obj {
+				if container, ok := val.(map[string]interface{}); ok && isContainers {
+					image, _ := container["image"].(string)
+					if digest, ok := digests[image]; ok && !strings.Contains(image, "@") {
+						container["image"] = image + "@" + digest
+						pinned = true
+					}
+				}
+				walk(key, val)
+			}
+		}
+	}
+	walk("", dict)
+
+	if !pinned {
+		return obj, nil
+	}
+
+	return dict, nil
+}
@@ -0,0 +1,133 @@
+package lock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+func TestCollectImports(t *testing.T) {
+	paths, err := CollectImports([]string{"../testdata/imports/import_depth_two.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"../testdata/imports/params.yaml", "../testdata/imports/params_and_imports.yaml"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Error(pretty.Diff(paths, expected))
+	}
+}
+
+func TestCollectImages(t *testing.T) {
+	objs := []map[string]interface{}{
+		{"deployment": map[string]interface{}{
+			"containers":      []interface{}{map[string]interface{}{"image": "nginx"}},
+			"init_containers": []interface{}{map[string]interface{}{"image": "busybox"}},
+		}},
+		{"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"image": "nginx"}},
+		}},
+	}
+
+	images := CollectImages(objs)
+	expected := []string{"busybox", "nginx"}
+	if !reflect.DeepEqual(images, expected) {
+		t.Error(pretty.Diff(images, expected))
+	}
+}
+
+func TestVendor(t *testing.T) {
+	paths, err := CollectImports([]string{"../testdata/imports/import_depth_two.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := Build(paths, []string{"nginx"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "short-vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = Vendor(lock, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readLock, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock, readLock) {
+		t.Error(pretty.Diff(lock, readLock))
+	}
+
+	// The lock file has the imports relative to itself.
+	b, err := ioutil.ReadFile(filepath.Join(dir, LockFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := &Lock{}
+	err = yaml.Unmarshal(b, written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, imprt := range written.Imports {
+		if filepath.IsAbs(imprt.Path) || absPath(filepath.Join(dir, imprt.Path)) != absPath(lock.Imports[i].Path) {
+			t.Errorf("expected (%s) relative to the lock file, got (%s)", lock.Imports[i].Path, imprt.Path)
+		}
+	}
+
+	// Vendored imports are read even if the original is gone.
+	read := VendoredReader(&Lock{Imports: []LockedImport{{Path: "missing/params.yaml", SHA256: lock.Imports[0].SHA256}}}, dir)
+	objs, err := read("missing/params.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) == 0 {
+		t.Error("expected vendored objects")
+	}
+}
+
+func TestPinImages(t *testing.T) {
+	lock := &Lock{Images: []LockedImage{{Image: "nginx:1.13", Digest: "sha256:9d7c"}, {Image: "busybox"}}}
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"image": "nginx:1.13"},
+				map[string]interface{}{"image": "busybox"},
+			},
+			"initContainers": []interface{}{
+				map[string]interface{}{"image": "nginx:1.13@sha256:0000"},
+			},
+		},
+	}
+
+	pinned, err := PinImages(obj, lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"image": "nginx:1.13@sha256:9d7c"},
+				map[string]interface{}{"image": "busybox"},
+			},
+			"initContainers": []interface{}{
+				map[string]interface{}{"image": "nginx:1.13@sha256:0000"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(pinned, expected) {
+		t.Error(pretty.Diff(pinned, expected))
+	}
+}
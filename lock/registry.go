@@ -0,0 +1,204 @@
+package lock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// Resolver finds the digest for an image reference.
+type Resolver interface {
+	Digest(image string) (string, error)
+}
+
+const (
+	DefaultRegistry = "registry-1.docker.io"
+
+	// dockerHubHost is how Docker Hub images may be written in manifests.
+	dockerHubHost = "docker.io"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// ImageRef is a parsed image reference, e.g. "gcr.io/project/app:1.0".
+type ImageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseImageRef fills in Docker's defaults (registry, "library/" and "latest") for short references.
+func ParseImageRef(image string) (*ImageRef, error) {
+	ref := &ImageRef{}
+	rest := image
+	if ix := strings.Index(rest, "@"); ix >= 0 {
+		ref.Digest = rest[ix+1:]
+		rest = rest[:ix]
+	}
+
+	segs := strings.SplitN(rest, "/", 2)
+	if len(segs) == 2 && (strings.ContainsAny(segs[0], ".:") || segs[0] == "localhost") {
+		ref.Registry = segs[0]
+		rest = segs[1]
+	} else {
+		ref.Registry = DefaultRegistry
+	}
+	if ref.Registry == dockerHubHost {
+		ref.Registry = DefaultRegistry
+	}
+
+	// A tag follows the last ":" after the last "/".
+	if ix := strings.LastIndex(rest, ":"); ix > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[ix+1:]
+		rest = rest[:ix]
+	}
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		ref.Tag = "latest"
+	}
+
+	if ref.Registry == DefaultRegistry && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+	ref.Repository = rest
+
+	if len(ref.Repository) == 0 {
+		return nil, serrors.InvalidValueErrorf(image, "missing repository in image reference")
+	}
+
+	return ref, nil
+}
+
+// RegistryResolver asks the image's registry (Docker Registry HTTP API V2) for the digest of its manifest.
+type RegistryResolver struct {
+	Client *http.Client
+
+	// Scheme is "https" unless overridden (e.g. for tests).
+	Scheme string
+}
+
+func NewRegistryResolver(timeout time.Duration) *RegistryResolver {
+	return &RegistryResolver{
+		Client: &http.Client{Timeout: timeout},
+		Scheme: "https",
+	}
+}
+
+func (r *RegistryResolver) Digest(image string) (string, error) {
+	ref, err := ParseImageRef(image)
+	if err != nil {
+		return "", err
+	}
+	if len(ref.Digest) > 0 {
+		return ref.Digest, nil
+	}
+
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", r.Scheme, ref.Registry, ref.Repository, ref.Tag)
+	resp, err := r.headManifest(manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.fetchToken(resp.Header.Get("Www-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+
+		resp, err = r.headManifest(manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", serrors.InvalidValueErrorf(image, "registry responded with (%s)", resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if len(digest) == 0 {
+		return "", serrors.InvalidValueErrorf(image, "registry didn't return a digest")
+	}
+
+	return digest, nil
+}
+
+func (r *RegistryResolver) headManifest(manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, manifestURL, "building manifest request")
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "requesting %s", manifestURL)
+	}
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken gets an anonymous token for a "Bearer realm=...,service=...,scope=..." challenge.
+func (r *RegistryResolver) fetchToken(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", serrors.InvalidValueErrorf(challenge, "unsupported registry authentication")
+	}
+
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(params["realm"]) == 0 {
+		return "", serrors.InvalidValueErrorf(challenge, "missing realm in registry authentication challenge")
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if val, ok := params[key]; ok {
+			query.Set(key, val)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := r.Client.Get(realm.String())
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "requesting registry token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", serrors.InvalidValueErrorf(realm.String(), "token request responded with (%s)", resp.Status)
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", serrors.ContextualizeErrorf(err, "decoding registry token")
+	}
+	if len(body.Token) > 0 {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
@@ -0,0 +1,70 @@
+package lock
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+func TestParseImageRef(t *testing.T) {
+	for image, expected := range map[string]ImageRef{
+		"nginx":                         {Registry: DefaultRegistry, Repository: "library/nginx", Tag: "latest"},
+		"docker.io/me/app:1.0":          {Registry: DefaultRegistry, Repository: "me/app", Tag: "1.0"},
+		"localhost:5000/app":            {Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+		"gcr.io/project/app@sha256:abc": {Registry: "gcr.io", Repository: "project/app", Digest: "sha256:abc"},
+	} {
+		ref, err := ParseImageRef(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*ref, expected) {
+			t.Errorf("%s: %s", image, pretty.Diff(*ref, expected))
+		}
+	}
+}
+
+func TestRegistryResolver(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "t0k3n"}`)
+		case r.URL.Path == "/v2/team/app/manifests/1.0":
+			if r.Header.Get("Authorization") != "Bearer t0k3n" {
+				w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:team/app:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:0123")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewRegistryResolver(5 * time.Second)
+	resolver.Scheme = "http"
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	digest, err := resolver.Digest(host + "/team/app:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != "sha256:0123" {
+		t.Errorf("unexpected digest (%s)", digest)
+	}
+
+	if _, err := resolver.Digest(host + "/team/missing:1.0"); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+}
@@ -0,0 +1,71 @@
+package lock
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Verify compares a lock file against a freshly built one and describes each difference.
+// Image digests are only compared when both locks have one.
+func Verify(locked, current *Lock) []string {
+	problems := []string{}
+
+	// Imports are compared by their absolute paths, since the same file can be written differently.
+	lockedImports := map[string]string{}
+	for _, imprt := range locked.Imports {
+		lockedImports[absPath(imprt.Path)] = imprt.SHA256
+	}
+	currentImports := map[string]bool{}
+	for _, imprt := range current.Imports {
+		currentImports[absPath(imprt.Path)] = true
+		hash, ok := lockedImports[absPath(imprt.Path)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("import (%s) isn't in the lock file", imprt.Path))
+		} else if hash != imprt.SHA256 {
+			problems = append(problems, fmt.Sprintf("import (%s) changed: locked sha256 (%s), found (%s)", imprt.Path, hash, imprt.SHA256))
+		}
+	}
+	for _, imprt := range locked.Imports {
+		if !currentImports[absPath(imprt.Path)] {
+			problems = append(problems, fmt.Sprintf("import (%s) is locked but no longer used", imprt.Path))
+		}
+	}
+
+	lockedImages := map[string]string{}
+	for _, image := range locked.Images {
+		lockedImages[image.Image] = image.Digest
+	}
+	currentImages := map[string]bool{}
+	for _, image := range current.Images {
+		currentImages[image.Image] = true
+		digest, ok := lockedImages[image.Image]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("image (%s) isn't in the lock file", image.Image))
+		} else if len(digest) > 0 && len(image.Digest) > 0 && digest != image.Digest {
+			problems = append(problems, fmt.Sprintf("image (%s) changed: locked digest (%s), found (%s)", image.Image, digest, image.Digest))
+		}
+	}
+	for _, image := range locked.Images {
+		if !currentImages[image.Image] {
+			problems = append(problems, fmt.Sprintf("image (%s) is locked but no longer used", image.Image))
+		}
+	}
+
+	return problems
+}
+
+// VerifyVendored checks that each locked import in dir still has the locked hash.
+func VerifyVendored(lock *Lock, dir string) []string {
+	problems := []string{}
+	for _, imprt := range lock.Imports {
+		vendoredPath := filepath.Join(dir, ImportsDir, imprt.SHA256+".yaml")
+		hash, err := hashFile(vendoredPath)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("import (%s) isn't vendored: %s", imprt.Path, err.Error()))
+		} else if hash != imprt.SHA256 {
+			problems = append(problems, fmt.Sprintf("vendored import (%s) was modified", vendoredPath))
+		}
+	}
+
+	return problems
+}
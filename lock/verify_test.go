@@ -0,0 +1,71 @@
+package lock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestVerify(t *testing.T) {
+	locked := &Lock{
+		Imports: []LockedImport{{Path: "a.yaml", SHA256: "aaa"}, {Path: "b.yaml", SHA256: "bbb"}},
+		Images:  []LockedImage{{Image: "nginx", Digest: "sha256:1"}, {Image: "busybox"}},
+	}
+	current := &Lock{
+		Imports: []LockedImport{{Path: "a.yaml", SHA256: "aab"}, {Path: "c.yaml", SHA256: "ccc"}},
+		Images:  []LockedImage{{Image: "nginx", Digest: "sha256:2"}, {Image: "busybox", Digest: "sha256:3"}},
+	}
+
+	problems := Verify(locked, current)
+	expected := []string{
+		"import (a.yaml) changed: locked sha256 (aaa), found (aab)",
+		"import (c.yaml) isn't in the lock file",
+		"import (b.yaml) is locked but no longer used",
+		"image (nginx) changed: locked digest (sha256:1), found (sha256:2)",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Error(pretty.Diff(problems, expected))
+	}
+
+	if problems := Verify(locked, locked); len(problems) > 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
+
+func TestVerifyVendored(t *testing.T) {
+	paths, err := CollectImports([]string{"../testdata/imports/import_depth_two.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := Build(paths, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "short-vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = Vendor(lock, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := VerifyVendored(lock, dir); len(problems) > 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+
+	vendoredPath := filepath.Join(dir, ImportsDir, lock.Imports[0].SHA256+".yaml")
+	err = ioutil.WriteFile(vendoredPath, []byte("edited: true\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := VerifyVendored(lock, dir); len(problems) != 1 {
+		t.Errorf("expected one problem, got %v", problems)
+	}
+}
@@ -0,0 +1,233 @@
+package migrate
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/deprecation"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Migrating a Kubernetes manifest from one API version to another goes through short syntax:
+
+	kube (from)  ->  short  ->  kube (to)
+
+Short syntax doesn't depend on the API version, so only "version" changes in between.
+Fields the new API version requires, like a Deployment's selector, are filled in by the converters.
+
+Some defaults changed between API versions. E.g. an extensions/v1beta1 Deployment rolls out one pod
+at a time, but an apps/v1 Deployment rolls out 25% of its pods at a time. Before converting, unset fields
+whose default differs are set to the old default, so the migrated object behaves the same.
+
+*/
+
+// defaults are the values of unset fields, by path.
+type defaults map[string]interface{}
+
+var (
+	unlimited = int64(math.MaxInt32)
+
+	extensionsDeployment = defaults{
+		"spec.strategy.type":                         "RollingUpdate",
+		"spec.strategy.rollingUpdate.maxUnavailable": int64(1),
+		"spec.strategy.rollingUpdate.maxSurge":       int64(1),
+		"spec.revisionHistoryLimit":                  unlimited,
+		"spec.progressDeadlineSeconds":               unlimited,
+	}
+	appsv1beta1Deployment = defaults{
+		"spec.strategy.type":                         "RollingUpdate",
+		"spec.strategy.rollingUpdate.maxUnavailable": "25%",
+		"spec.strategy.rollingUpdate.maxSurge":       "25%",
+		"spec.revisionHistoryLimit":                  int64(2),
+		"spec.progressDeadlineSeconds":               int64(600),
+	}
+	appsDeployment = defaults{
+		"spec.strategy.type":                         "RollingUpdate",
+		"spec.strategy.rollingUpdate.maxUnavailable": "25%",
+		"spec.strategy.rollingUpdate.maxSurge":       "25%",
+		"spec.revisionHistoryLimit":                  int64(10),
+		"spec.progressDeadlineSeconds":               int64(600),
+	}
+
+	extensionsDaemonSet = defaults{
+		"spec.updateStrategy.type":  "OnDelete",
+		"spec.revisionHistoryLimit": int64(10),
+	}
+	appsDaemonSet = defaults{
+		"spec.updateStrategy.type":                         "RollingUpdate",
+		"spec.updateStrategy.rollingUpdate.maxUnavailable": int64(1),
+		"spec.revisionHistoryLimit":                        int64(10),
+	}
+
+	appsv1beta1StatefulSet = defaults{
+		"spec.updateStrategy.type":  "OnDelete",
+		"spec.podManagementPolicy":  "OrderedReady",
+		"spec.revisionHistoryLimit": int64(10),
+	}
+	appsStatefulSet = defaults{
+		"spec.updateStrategy.type":                    "RollingUpdate",
+		"spec.updateStrategy.rollingUpdate.partition": int64(0),
+		"spec.podManagementPolicy":                    "OrderedReady",
+		"spec.revisionHistoryLimit":                   int64(10),
+	}
+)
+
+// apiDefaults maps each kind and apiVersion to its defaults. Kinds and fields that aren't listed have the same defaults in every version.
+var apiDefaults = map[string]map[string]defaults{
+	"Deployment": {
+		"extensions/v1beta1": extensionsDeployment,
+		"apps/v1beta1":       appsv1beta1Deployment,
+		"apps/v1beta2":       appsDeployment,
+		"apps/v1":            appsDeployment,
+	},
+	"DaemonSet": {
+		"extensions/v1beta1": extensionsDaemonSet,
+		"apps/v1beta2":       appsDaemonSet,
+		"apps/v1":            appsDaemonSet,
+	},
+	"StatefulSet": {
+		"apps/v1beta1": appsv1beta1StatefulSet,
+		"apps/v1beta2": appsStatefulSet,
+		"apps/v1":      appsStatefulSet,
+	},
+}
+
+// Migrate converts the Kubernetes objects with apiVersion from to apiVersion to. Other objects are returned unchanged.
+func Migrate(objs []map[string]interface{}, from, to string) ([]interface{}, error) {
+	migrated := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		if apiVersion, _ := obj["apiVersion"].(string); apiVersion != from {
+			migrated[i] = obj
+			continue
+		}
+
+		kubeObj, err := migrateObj(obj, from, to)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "migrating %s %s", obj["kind"], name(obj))
+		}
+		migrated[i] = kubeObj
+	}
+
+	return migrated, nil
+}
+
+func name(obj map[string]interface{}) string {
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ := metadata["name"].(string)
+		return name
+	}
+
+	return ""
+}
+
+func migrateObj(obj map[string]interface{}, from, to string) (interface{}, error) {
+	kind, _ := obj["kind"].(string)
+	setOldDefaults(obj, apiDefaults[kind][from], apiDefaults[kind][to])
+
+	kokiObjs, err := client.ConvertEitherMapsToKoki([]map[string]interface{}{obj})
+	if err != nil {
+		return nil, err
+	}
+
+	kokiObj := kokiObjs[0]
+	for _, body := range kokiObj {
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return nil, serrors.InvalidValueErrorf(kokiObj, "expected a dictionary")
+		}
+		bodyMap["version"] = to
+	}
+
+	kubeObjs, err := client.ConvertKokiMaps(kokiObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubeObjs[0], nil
+}
+
+// setOldDefaults sets each unset field whose default differs between the old and new API version to its old default.
+func setOldDefaults(obj map[string]interface{}, oldDefaults, newDefaults defaults) {
+	paths := []string{}
+	for path := range oldDefaults {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		oldDefault := oldDefaults[path]
+		if newDefault, ok := newDefaults[path]; ok && reflect.DeepEqual(oldDefault, newDefault) {
+			continue
+		}
+
+		segments := strings.Split(path, ".")
+		if _, ok := lookup(obj, segments); ok {
+			continue
+		}
+
+		// rollingUpdate parameters only apply to the RollingUpdate strategy.
+		if n := len(segments); n > 2 && segments[n-2] == "rollingUpdate" {
+			typePath := append(append([]string{}, segments[:n-2]...), "type")
+			strategyType, ok := lookup(obj, typePath)
+			if !ok {
+				strategyType = oldDefaults[strings.Join(typePath, ".")]
+			}
+			if strategyType != "RollingUpdate" {
+				continue
+			}
+		}
+
+		parent := obj
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := parent[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[segment] = child
+			}
+			parent = child
+		}
+		parent[segments[len(segments)-1]] = oldDefault
+	}
+}
+
+func lookup(obj map[string]interface{}, segments []string) (interface{}, bool) {
+	var val interface{} = obj
+	for _, segment := range segments {
+		valMap, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		val, ok = valMap[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+// FixDeprecated migrates the deprecated fields of a Kubernetes object that deprecation.Fix can migrate,
+// and then its apiVersion, if it's deprecated and the objects can be migrated to the replacement.
+// It returns the object and whether anything changed.
+func FixDeprecated(obj map[string]interface{}) (interface{}, bool, error) {
+	fixed := deprecation.Fix(obj)
+
+	kind, _ := obj["kind"].(string)
+	apiVersion, _ := obj["apiVersion"].(string)
+	api, ok := deprecation.ForAPI(apiVersion, kind)
+	if !ok || !api.Migratable {
+		return obj, fixed, nil
+	}
+
+	migrated, err := Migrate([]map[string]interface{}{obj}, api.APIVersion, api.Replacement)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return migrated[0], true, nil
+}
@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/koki/short/yaml"
+)
+
+var extensionsDeployment0 = `
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+var extensionsDeployment1 = `
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  revisionHistoryLimit: 3
+  strategy:
+    type: Recreate
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+var configMap0 = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: b
+`
+
+func parse(t *testing.T, doc string) map[string]interface{} {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func TestMigrateDeployment(t *testing.T) {
+	objs := []map[string]interface{}{
+		parse(t, extensionsDeployment0),
+		parse(t, extensionsDeployment1),
+		parse(t, configMap0),
+	}
+
+	migrated, err := Migrate(objs, "extensions/v1beta1", "apps/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rollingUpdate, ok := migrated[0].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("expected an apps/v1 Deployment, got %T", migrated[0])
+	}
+	if rollingUpdate.Spec.Selector == nil || rollingUpdate.Spec.Selector.MatchLabels["app"] != "web" {
+		t.Errorf("expected the selector to be filled in, got %#v", rollingUpdate.Spec.Selector)
+	}
+	params := rollingUpdate.Spec.Strategy.RollingUpdate
+	if params == nil || *params.MaxUnavailable != intstr.FromInt(1) || *params.MaxSurge != intstr.FromInt(1) {
+		t.Errorf("expected the extensions/v1beta1 rollout defaults, got %#v", params)
+	}
+	if rollingUpdate.Spec.RevisionHistoryLimit == nil || *rollingUpdate.Spec.RevisionHistoryLimit != int32(unlimited) {
+		t.Errorf("expected unlimited revision history, got %v", rollingUpdate.Spec.RevisionHistoryLimit)
+	}
+
+	recreate, ok := migrated[1].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("expected an apps/v1 Deployment, got %T", migrated[1])
+	}
+	if recreate.Spec.Strategy.RollingUpdate != nil {
+		t.Errorf("didn't expect rollout parameters for the Recreate strategy, got %#v", recreate.Spec.Strategy.RollingUpdate)
+	}
+	if *recreate.Spec.RevisionHistoryLimit != 3 {
+		t.Errorf("expected the revision history limit to be kept, got %d", *recreate.Spec.RevisionHistoryLimit)
+	}
+
+	if _, ok := migrated[2].(map[string]interface{}); !ok {
+		t.Errorf("expected other objects to be unchanged, got %T", migrated[2])
+	}
+}
+
+func TestFixDeprecated(t *testing.T) {
+	obj := parse(t, extensionsDeployment0)
+	obj["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["serviceAccount"] = "web"
+
+	fixed, ok, err := FixDeprecated(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment, isDeployment := fixed.(*appsv1.Deployment)
+	if !ok || !isDeployment {
+		t.Fatalf("expected an apps/v1 Deployment, got %T", fixed)
+	}
+	if deployment.Spec.Template.Spec.ServiceAccountName != "web" {
+		t.Errorf("expected the service account to be kept, got %#v", deployment.Spec.Template.Spec)
+	}
+
+	_, ok, err = FixDeprecated(parse(t, configMap0))
+	if err != nil || ok {
+		t.Errorf("expected a ConfigMap to be unchanged, got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,224 @@
+package order
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/koki/short/site"
+	"github.com/koki/short/util/objutil"
+)
+
+/*
+
+Order sorts Kubernetes objects so they can be applied in one go: each object comes after the
+objects it needs, e.g. a Deployment after its Namespace and the ConfigMaps it mounts.
+
+Objects are ordered by kind first (see kindRanks), then by the references that site.FindRefs
+finds between them, e.g. a configMapKeyRef. Otherwise the input order is kept.
+
+ByName is for reading instead: it groups objects by kind and sorts them by namespace and name,
+so bundles dumped from a cluster in any order come out the same.
+
+*/
+
+// kindRanks order kinds for apply. Kinds that aren't listed, e.g. custom resources, come after the workloads.
+var kindRanks = map[string]int{
+	"Namespace": 0,
+
+	"CustomResourceDefinition": 1,
+
+	"PodSecurityPolicy": 2,
+	"PriorityClass":     2,
+	"StorageClass":      2,
+	"LimitRange":        2,
+	"ResourceQuota":     2,
+
+	"ServiceAccount": 3,
+	"ClusterRole":    3,
+	"Role":           3,
+
+	"ClusterRoleBinding": 4,
+	"RoleBinding":        4,
+
+	"ConfigMap":        5,
+	"Secret":           5,
+	"SealedSecret":     5, // from --seal-cert, in place of a Secret
+	"PersistentVolume": 5,
+
+	"PersistentVolumeClaim": 6,
+
+	"Service":   7,
+	"Endpoints": 7,
+
+	"Pod":                   8,
+	"PodTemplate":           8,
+	"ReplicationController": 8,
+	"ReplicaSet":            8,
+	"Deployment":            8,
+	"StatefulSet":           8,
+	"DaemonSet":             8,
+	"Job":                   8,
+	"CronJob":               8,
+
+	"HorizontalPodAutoscaler": 9,
+	"PodDisruptionBudget":     9,
+	"Ingress":                 9,
+	"NetworkPolicy":           9,
+
+	"APIService":                     11,
+	"InitializerConfiguration":       11,
+	"MutatingWebhookConfiguration":   11,
+	"ValidatingWebhookConfiguration": 11,
+}
+
+// customRank is the rank of kinds that aren't in kindRanks.
+const customRank = 10
+
+// shortKinds are the short kinds of the Kubernetes kinds that site.FindRefs finds references to.
+var shortKinds = map[string]string{
+	"ConfigMap":             "config_map",
+	"Secret":                "secret",
+	"PersistentVolumeClaim": "pvc",
+	"ServiceAccount":        "service_account",
+}
+
+// ForApply sorts Kubernetes objects so each comes after the objects it needs.
+func ForApply(objs []interface{}) ([]interface{}, error) {
+	ranks := make([]int, len(objs))
+	indices := map[site.ID]int{}
+	dicts := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		dict, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		dicts[i] = dict
+
+		kind, namespace, name := identify(dict)
+		ranks[i] = rank(kind)
+		if shortKind, ok := shortKinds[kind]; ok {
+			indices[site.ID{Kind: shortKind, Namespace: namespace, Name: name}] = i
+		}
+	}
+
+	// needs[i] are the objects that object i references.
+	needs := make([][]int, len(objs))
+	for i, dict := range dicts {
+		_, namespace, _ := identify(dict)
+		refs, err := site.FindRefs(namespace, dict)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if j, ok := indices[ref]; ok && j != i {
+				needs[i] = append(needs[i], j)
+			}
+		}
+	}
+
+	candidates := make([]int, len(objs))
+	for i := range candidates {
+		candidates[i] = i
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return ranks[candidates[a]] < ranks[candidates[b]]
+	})
+
+	// Take the first candidate whose needs are all placed. If there's a cycle, take the first candidate.
+	placed := make([]bool, len(objs))
+	sorted := make([]interface{}, 0, len(objs))
+	for len(candidates) > 0 {
+		next := 0
+		for c, i := range candidates {
+			if allPlaced(needs[i], placed) {
+				next = c
+				break
+			}
+		}
+
+		i := candidates[next]
+		placed[i] = true
+		sorted = append(sorted, objs[i])
+		candidates = append(candidates[:next], candidates[next+1:]...)
+	}
+
+	return sorted, nil
+}
+
+// ByName returns the order of the objects, in Kubernetes or short syntax, grouped by kind
+// (alphabetically) and sorted by namespace and name within each kind.
+// It returns indices, so whatever goes along with each object can be sorted the same way.
+func ByName(objs []interface{}) ([]int, error) {
+	keys := make([][3]string, len(objs))
+	for i, obj := range objs {
+		dict, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		kind, namespace, name := identifyEither(dict)
+		keys[i] = [3]string{kind, namespace, name}
+	}
+
+	indices := make([]int, len(objs))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		keyA, keyB := keys[indices[a]], keys[indices[b]]
+		for k := range keyA {
+			if keyA[k] != keyB[k] {
+				return keyA[k] < keyB[k]
+			}
+		}
+		return false
+	})
+
+	return indices, nil
+}
+
+// identifyEither is identify for objects in Kubernetes or short syntax. Short kinds are the key of the object, e.g. "deployment".
+func identifyEither(obj map[string]interface{}) (kind, namespace, name string) {
+	if _, ok := obj["kind"]; ok {
+		return identify(obj)
+	}
+
+	for key, val := range obj {
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		body, _ := val.(map[string]interface{})
+		namespace, _ = body["namespace"].(string)
+		name, _ = body["name"].(string)
+		return key, namespace, name
+	}
+
+	return "", "", ""
+}
+
+func identify(obj map[string]interface{}) (kind, namespace, name string) {
+	kind, _ = obj["kind"].(string)
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		namespace, _ = metadata["namespace"].(string)
+		name, _ = metadata["name"].(string)
+	}
+
+	return kind, namespace, name
+}
+
+func rank(kind string) int {
+	if r, ok := kindRanks[kind]; ok {
+		return r
+	}
+
+	return customRank
+}
+
+func allPlaced(indices []int, placed []bool) bool {
+	for _, i := range indices {
+		if !placed[i] {
+			return false
+		}
+	}
+
+	return true
+}
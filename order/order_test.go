@@ -0,0 +1,127 @@
+package order
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func obj(kind, namespace, name string, fields map[string]interface{}) map[string]interface{} {
+	o := map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"namespace": namespace, "name": name},
+	}
+	for key, val := range fields {
+		o[key] = val
+	}
+
+	return o
+}
+
+func names(objs []interface{}) []string {
+	result := []string{}
+	for _, o := range objs {
+		kind, _, name := identify(o.(map[string]interface{}))
+		result = append(result, kind+"/"+name)
+	}
+
+	return result
+}
+
+func TestForApply(t *testing.T) {
+	objs := []interface{}{
+		obj("ValidatingWebhookConfiguration", "", "check", nil),
+		obj("Deployment", "prod", "web", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"serviceAccountName": "web",
+						"containers": []interface{}{
+							map[string]interface{}{
+								"env": []interface{}{
+									map[string]interface{}{
+										"valueFrom": map[string]interface{}{
+											"secretKeyRef": map[string]interface{}{"name": "creds", "key": "password"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}),
+		obj("Widget", "prod", "custom", nil),
+		obj("Secret", "prod", "creds", nil),
+		obj("ServiceAccount", "prod", "web", nil),
+		obj("Namespace", "", "prod", nil),
+		obj("CustomResourceDefinition", "", "widgets.example.com", nil),
+		obj("Service", "prod", "web", nil),
+	}
+
+	sorted, err := ForApply(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"Namespace/prod",
+		"CustomResourceDefinition/widgets.example.com",
+		"ServiceAccount/web",
+		"Secret/creds",
+		"Service/web",
+		"Deployment/web",
+		"Widget/custom",
+		"ValidatingWebhookConfiguration/check",
+	}
+	if actual := names(sorted); !reflect.DeepEqual(actual, expected) {
+		t.Error(pretty.Diff(actual, expected))
+	}
+}
+
+func TestForApplyReferences(t *testing.T) {
+	// ServiceAccounts rank before Secrets, but this one lists a Secret, so the Secret goes first.
+	objs := []interface{}{
+		obj("ServiceAccount", "prod", "web", map[string]interface{}{
+			"secrets": []interface{}{
+				map[string]interface{}{"name": "token"},
+			},
+		}),
+		obj("ServiceAccount", "prod", "other", nil),
+		obj("Secret", "prod", "token", nil),
+	}
+
+	sorted, err := ForApply(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"ServiceAccount/other",
+		"Secret/token",
+		"ServiceAccount/web",
+	}
+	if actual := names(sorted); !reflect.DeepEqual(actual, expected) {
+		t.Error(pretty.Diff(actual, expected))
+	}
+}
+
+func TestByName(t *testing.T) {
+	objs := []interface{}{
+		obj("Service", "prod", "web", nil),
+		obj("Deployment", "prod", "web", nil),
+		obj("Deployment", "dev", "web", nil),
+		map[string]interface{}{"config_map": map[string]interface{}{"name": "settings", "namespace": "prod"}},
+		obj("Deployment", "prod", "api", nil),
+	}
+
+	indices, err := ByName(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{2, 4, 1, 0, 3}
+	if !reflect.DeepEqual(indices, expected) {
+		t.Errorf("got %v, expected %v", indices, expected)
+	}
+}
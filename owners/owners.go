@@ -0,0 +1,350 @@
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Owners routes findings about manifests, e.g. lint warnings, to the teams that own the manifests,
+so platform teams can hand each team its own list. Teams are declared in an
+owners file:
+
+	# short.owners.yaml
+	teams:
+	  payments:
+	    dirs: [services/payments, services/billing]
+	    max_findings: 0
+	  search:
+	    labels: {team: search}
+	    max_findings: 5
+	default: platform
+	codeowners: .github/CODEOWNERS
+
+A finding belongs to the first team found by:
+  - labels: a team whose labels the object has
+  - dirs: the team with the longest directory that holds the finding's file
+  - codeowners: the first owner of the last CODEOWNERS rule that matches the file, without its "@",
+    e.g. "acme/payments"
+  - the default team
+
+Findings that none of these match are unowned. Directories and the CODEOWNERS file are relative
+to the owners file, and CODEOWNERS patterns are relative to its directory too, which should be the
+top of the repository. Without codeowners, CODEOWNERS, .github/CODEOWNERS and docs/CODEOWNERS are
+used if they exist.
+
+A team fails the report when it has more findings than its max_findings, 0 by default. Teams that
+aren't declared, e.g. those only named in CODEOWNERS, and unowned findings have a max_findings of 0.
+
+*/
+
+// DefaultFile is the owners file that's used if none is given and it exists in the current directory.
+const DefaultFile = "short.owners.yaml"
+
+// Unowned is the team of findings without an owner.
+const Unowned = "(unowned)"
+
+// codeOwnersFiles are where GitHub looks for a CODEOWNERS file, in order.
+var codeOwnersFiles = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"}
+
+type Team struct {
+	Dirs   []string          `json:"dirs,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// MaxFindings is the most findings the team can have before the report fails.
+	MaxFindings int `json:"max_findings,omitempty"`
+}
+
+type Owners struct {
+	Teams      map[string]Team `json:"teams,omitempty"`
+	Default    string          `json:"default,omitempty"`
+	CodeOwners string          `json:"codeowners,omitempty"`
+
+	// Dir is the directory of the owners file.
+	Dir string `json:"-"`
+	// rules are the CODEOWNERS rules, in the file's order.
+	rules []rule
+}
+
+// rule is a line of a CODEOWNERS file.
+type rule struct {
+	pattern string
+	owner   string
+}
+
+// Load reads an owners file, or DefaultFile if filename is empty. It returns nil if filename is empty
+// and DefaultFile doesn't exist.
+func Load(filename string) (*Owners, error) {
+	if len(filename) == 0 {
+		if _, err := os.Stat(DefaultFile); err != nil {
+			return nil, nil
+		}
+		filename = DefaultFile
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading owners %s", filename)
+	}
+
+	o := &Owners{}
+	err = yaml.Unmarshal(b, o)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextError(err, string(b), o)
+	}
+	o.Dir = filepath.Dir(filename)
+	for name, team := range o.Teams {
+		if team.MaxFindings < 0 {
+			return nil, serrors.InvalidValueErrorf(team.MaxFindings, "team %s: max_findings can't be negative", name)
+		}
+	}
+
+	codeOwners := o.CodeOwners
+	if len(codeOwners) == 0 {
+		for _, file := range codeOwnersFiles {
+			if _, err := os.Stat(filepath.Join(o.Dir, file)); err == nil {
+				codeOwners = file
+				break
+			}
+		}
+	}
+	if len(codeOwners) > 0 {
+		b, err := ioutil.ReadFile(filepath.Join(o.Dir, codeOwners))
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading %s", codeOwners)
+		}
+		o.rules = parseCodeOwners(b)
+	}
+
+	return o, nil
+}
+
+func parseCodeOwners(data []byte) []rule {
+	rules := []rule{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		r := rule{pattern: fields[0]}
+		// A rule without owners unsets the owners of the files it matches.
+		if len(fields) > 1 && !strings.HasPrefix(fields[1], "#") {
+			r.owner = strings.TrimPrefix(fields[1], "@")
+		}
+		rules = append(rules, r)
+	}
+
+	return rules
+}
+
+// matches is true if a CODEOWNERS pattern matches a slash-separated path from the top of the repository.
+// Like .gitignore patterns, a pattern with a "/" before its end is anchored to the top, and other
+// patterns match at any depth. A pattern matches the files under a directory it matches.
+func matches(pattern, file string) bool {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(pattern, "**"), "/"), "/")
+	if len(pattern) == 0 || pattern == "*" {
+		return true
+	}
+
+	segments := strings.Split(file, "/")
+	depth := len(strings.Split(pattern, "/"))
+	for start := 0; start+depth <= len(segments); start++ {
+		if anchored && start > 0 {
+			break
+		}
+		if ok, _ := path.Match(pattern, strings.Join(segments[start:start+depth], "/")); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TeamOf returns the team that owns an object, from its file and labels. Either can be empty.
+func (o *Owners) TeamOf(file string, labels map[string]string) string {
+	for _, name := range o.teamNames() {
+		team := o.Teams[name]
+		if len(team.Labels) > 0 && hasLabels(labels, team.Labels) {
+			return name
+		}
+	}
+	if len(file) == 0 {
+		return o.defaultTeam()
+	}
+
+	rel, ok := o.relative(file)
+	if !ok {
+		return o.defaultTeam()
+	}
+
+	owner, longest := "", -1
+	for _, name := range o.teamNames() {
+		for _, dir := range o.Teams[name].Dirs {
+			dir = filepath.ToSlash(filepath.Clean(dir))
+			if (dir == "." || rel == dir || strings.HasPrefix(rel, dir+"/")) && len(dir) > longest {
+				owner, longest = name, len(dir)
+			}
+		}
+	}
+	if len(owner) > 0 {
+		return owner
+	}
+
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if matches(o.rules[i].pattern, rel) {
+			if len(o.rules[i].owner) > 0 {
+				return o.rules[i].owner
+			}
+			break
+		}
+	}
+
+	return o.defaultTeam()
+}
+
+func (o *Owners) defaultTeam() string {
+	if len(o.Default) > 0 {
+		return o.Default
+	}
+
+	return Unowned
+}
+
+// teamNames are sorted, so the first team with matching labels is always the same one.
+func (o *Owners) teamNames() []string {
+	names := make([]string, 0, len(o.Teams))
+	for name := range o.Teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// relative returns the slash-separated path of a file from the owners file's directory.
+func (o *Owners) relative(file string) (string, bool) {
+	dir, err := filepath.Abs(o.Dir)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+func hasLabels(labels, selector map[string]string) bool {
+	for key, val := range selector {
+		if labels[key] != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Finding is a problem reported about an object, e.g. a lint warning.
+type Finding struct {
+	File   string
+	Labels map[string]string
+	// Text is how the finding is printed. It can have several lines.
+	Text string
+}
+
+// Group is the findings of one team.
+type Group struct {
+	Team        string
+	MaxFindings int
+	Findings    []Finding
+}
+
+// Over is true if the team has more findings than it's allowed.
+func (g Group) Over() bool {
+	return len(g.Findings) > g.MaxFindings
+}
+
+// Group sorts findings by the team that owns them. Groups are sorted by team, with unowned findings last.
+func (o *Owners) Group(findings []Finding) []Group {
+	byTeam := map[string]*Group{}
+	teams := []string{}
+	for _, finding := range findings {
+		team := o.TeamOf(finding.File, finding.Labels)
+		if _, ok := byTeam[team]; !ok {
+			byTeam[team] = &Group{Team: team, MaxFindings: o.Teams[team].MaxFindings}
+			teams = append(teams, team)
+		}
+		byTeam[team].Findings = append(byTeam[team].Findings, finding)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if (teams[i] == Unowned) != (teams[j] == Unowned) {
+			return teams[j] == Unowned
+		}
+		return teams[i] < teams[j]
+	})
+
+	groups := make([]Group, len(teams))
+	for i, team := range teams {
+		groups[i] = *byTeam[team]
+	}
+
+	return groups
+}
+
+// Write prints each team's findings under a heading with its count and maximum:
+//
+//	payments: 2 findings, at most 0
+//	  services/payments/web.short.yaml: ...
+func Write(groups []Group, w io.Writer) error {
+	for _, group := range groups {
+		noun := "findings"
+		if len(group.Findings) == 1 {
+			noun = "finding"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %d %s, at most %d\n", group.Team, len(group.Findings), noun, group.MaxFindings); err != nil {
+			return err
+		}
+		for _, finding := range group.Findings {
+			for _, line := range strings.Split(strings.TrimRight(finding.Text, "\n"), "\n") {
+				if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check returns an error naming the teams that have more findings than they're allowed, if any.
+func Check(groups []Group) error {
+	over := []string{}
+	for _, group := range groups {
+		if group.Over() {
+			over = append(over, fmt.Sprintf("%s (%d > %d)", group.Team, len(group.Findings), group.MaxFindings))
+		}
+	}
+	if len(over) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("over max_findings: %s", strings.Join(over, ", "))
+}
@@ -0,0 +1,147 @@
+package owners
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTeamOf(t *testing.T) {
+	dir, err := ioutil.TempDir("", "short-owners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		DefaultFile: `
+teams:
+  payments:
+    dirs: [services/payments]
+    max_findings: 2
+  billing:
+    dirs: [services/payments/billing]
+  search:
+    labels: {team: search}
+`,
+		".github/CODEOWNERS": `
+# Platform owns everything else under services.
+/services/ @acme/platform
+*.json @acme/data
+services/legacy/
+`,
+	})
+
+	o, err := Load(filepath.Join(dir, DefaultFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		file     string
+		labels   map[string]string
+		expected string
+	}{
+		{"services/payments/web.short.yaml", nil, "payments"},
+		{"services/payments/billing/db.short.yaml", nil, "billing"},
+		{"services/payments/web.short.yaml", map[string]string{"team": "search"}, "search"},
+		{"", map[string]string{"team": "search", "tier": "web"}, "search"},
+		{"services/search/web.short.yaml", nil, "acme/platform"},
+		{"services/search/dump.json", nil, "acme/data"},
+		{"services/legacy/old.short.yaml", nil, Unowned},
+		{"other/x.short.yaml", nil, Unowned},
+		{"", nil, Unowned},
+	}
+	for _, c := range cases {
+		file := c.file
+		if len(file) > 0 {
+			file = filepath.Join(dir, file)
+		}
+		if team := o.TeamOf(file, c.labels); team != c.expected {
+			t.Errorf("%s %v: expected %s, got %s", c.file, c.labels, c.expected, team)
+		}
+	}
+
+	o.Default = "platform"
+	if team := o.TeamOf(filepath.Join(dir, "other/x.short.yaml"), nil); team != "platform" {
+		t.Errorf("expected the default team, got %s", team)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		file     string
+		expected bool
+	}{
+		{"*", "a/b.yaml", true},
+		{"*.yaml", "a/b.yaml", true},
+		{"docs/", "a/docs/b.md", true},
+		{"/docs/", "a/docs/b.md", false},
+		{"apps/**", "apps/web/a.yaml", true},
+		{"apps/**", "x/apps/a.yaml", false},
+		{"a/*.yaml", "a/b/c.yaml", false},
+	}
+	for _, c := range cases {
+		if matches(c.pattern, c.file) != c.expected {
+			t.Errorf("%s %s: expected %t", c.pattern, c.file, c.expected)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	o := &Owners{
+		Teams: map[string]Team{
+			"payments": {Labels: map[string]string{"team": "payments"}, MaxFindings: 1},
+			"search":   {Labels: map[string]string{"team": "search"}},
+		},
+	}
+	groups := o.Group([]Finding{
+		{Labels: map[string]string{"team": "search"}, Text: "a"},
+		{Text: "b"},
+		{Labels: map[string]string{"team": "payments"}, Text: "c\n    d"},
+		{Labels: map[string]string{"team": "search"}, Text: "e"},
+	})
+
+	buf := &bytes.Buffer{}
+	err := Write(groups, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `payments: 1 finding, at most 1
+  c
+      d
+search: 2 findings, at most 0
+  a
+  e
+(unowned): 1 finding, at most 0
+  b
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	err = Check(groups)
+	if err == nil || err.Error() != "over max_findings: search (2 > 0), (unowned) (1 > 0)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Check(groups[:1]); err != nil {
+		t.Errorf("expected payments to be within its max_findings, got %v", err)
+	}
+}
@@ -11,6 +11,9 @@ import (
 	serrors "github.com/koki/structurederrors"
 )
 
+// ParseLabelSelector parses a label selector, e.g. "app=web&tier!=cache,db&!canary".
+// Requirements are separated by "&" or by commas outside of parentheses, and may use
+// set-based syntax, e.g. "app in (web,api), tier notin (db), !canary".
 func ParseLabelSelector(s string) (*metav1.LabelSelector, error) {
 	if len(s) == 0 {
 		return nil, nil
@@ -18,9 +21,20 @@ func ParseLabelSelector(s string) (*metav1.LabelSelector, error) {
 
 	labels := map[string]string{}
 	reqs := []metav1.LabelSelectorRequirement{}
-	segs := strings.Split(s, "&")
+	segs, err := splitLabelRequirements(s)
+	if err != nil {
+		return nil, err
+	}
 	for _, seg := range segs {
-		expr, err := ParseExpr(seg, []string{"!=", "="})
+		if req, ok, err := parseSetRequirement(seg); ok {
+			if err != nil {
+				return nil, serrors.InvalidValueForTypeContextErrorf(err, s, metav1.LabelSelector{}, "couldn't parse subexpression")
+			}
+			reqs = append(reqs, *req)
+			continue
+		}
+
+		expr, err := ParseExpr(strings.Replace(seg, "==", "=", 1), []string{"!=", "="})
 		if err != nil {
 			return nil, serrors.InvalidValueForTypeContextErrorf(err, s, metav1.LabelSelector{}, "couldn't parse subexpression")
 		}
@@ -28,7 +42,7 @@ func ParseLabelSelector(s string) (*metav1.LabelSelector, error) {
 		if expr == nil {
 			if seg[0] == '!' {
 				reqs = append(reqs, metav1.LabelSelectorRequirement{
-					Key:      seg[1:],
+					Key:      strings.TrimSpace(seg[1:]),
 					Operator: metav1.LabelSelectorOpDoesNotExist,
 				})
 			} else {
@@ -41,6 +55,11 @@ func ParseLabelSelector(s string) (*metav1.LabelSelector, error) {
 			continue
 		}
 
+		expr.Key = strings.TrimSpace(expr.Key)
+		for i, value := range expr.Values {
+			expr.Values[i] = strings.TrimSpace(value)
+		}
+
 		var op metav1.LabelSelectorOperator
 		switch expr.Op {
 		case "=":
@@ -76,6 +95,118 @@ func ParseLabelSelector(s string) (*metav1.LabelSelector, error) {
 	}, nil
 }
 
+// splitLabelRequirements splits a label selector into its requirements. A comma outside of
+// parentheses separates requirements, except that "key=a,b" and "key!=a,b" keep listing values
+// as long as there's no space after the comma.
+func splitLabelRequirements(s string) ([]string, error) {
+	pieces := []string{}
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, serrors.InvalidValueErrorf(s, "unbalanced parentheses")
+			}
+		case '&':
+			if depth == 0 {
+				pieces = append(pieces, s[start:i])
+				start = i + 1
+			}
+		case ',':
+			if depth == 0 {
+				pieces = append(pieces, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, serrors.InvalidValueErrorf(s, "unbalanced parentheses")
+	}
+	pieces = append(pieces, s[start:])
+
+	// Put the values of "key=a,b" back together.
+	segs := []string{}
+	for i, piece := range pieces {
+		if i > 0 && isValueContinuation(s, pieces[:i], piece) {
+			segs[len(segs)-1] = segs[len(segs)-1] + "," + piece
+			continue
+		}
+		segs = append(segs, strings.TrimSpace(piece))
+	}
+
+	for _, seg := range segs {
+		if len(seg) == 0 {
+			return nil, serrors.InvalidValueErrorf(s, "empty subexpression")
+		}
+	}
+
+	return segs, nil
+}
+
+// isValueContinuation is true if a piece of a label selector is another value of the previous
+// "key=a" or "key!=a" requirement, rather than a requirement of its own.
+func isValueContinuation(s string, previous []string, piece string) bool {
+	// Find the separator before this piece.
+	offset := len(previous)
+	for _, p := range previous {
+		offset += len(p)
+	}
+	if s[offset-1] != ',' {
+		return false
+	}
+
+	last := strings.TrimSpace(previous[len(previous)-1])
+	if !strings.Contains(last, "=") || strings.Contains(last, "(") {
+		return false
+	}
+
+	return len(piece) > 0 && !strings.HasPrefix(piece, " ") && !strings.HasPrefix(piece, "!") &&
+		!strings.ContainsAny(piece, "=()") && !strings.Contains(piece, " ")
+}
+
+// parseSetRequirement parses "key in (a,b)" and "key notin (a,b)". It returns false if the
+// requirement doesn't use either form.
+func parseSetRequirement(seg string) (*metav1.LabelSelectorRequirement, bool, error) {
+	open := strings.Index(seg, "(")
+	if open < 0 {
+		return nil, false, nil
+	}
+
+	fields := strings.Fields(seg[:open])
+	if len(fields) != 2 || !strings.HasSuffix(seg, ")") {
+		return nil, true, serrors.InvalidValueErrorf(seg, "expected 'key in (values)' or 'key notin (values)'")
+	}
+
+	var op metav1.LabelSelectorOperator
+	switch fields[1] {
+	case "in":
+		op = metav1.LabelSelectorOpIn
+	case "notin":
+		op = metav1.LabelSelectorOpNotIn
+	default:
+		return nil, true, serrors.InvalidValueErrorf(seg, "unknown operator (%s), expected 'in' or 'notin'", fields[1])
+	}
+
+	values := []string{}
+	for _, value := range strings.Split(seg[open+1:len(seg)-1], ",") {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			return nil, true, serrors.InvalidValueErrorf(seg, "empty value")
+		}
+		values = append(values, value)
+	}
+
+	return &metav1.LabelSelectorRequirement{
+		Key:      fields[0],
+		Operator: op,
+		Values:   values,
+	}, true, nil
+}
+
 func UnparseLabelSelector(kubeSelector *metav1.LabelSelector) (string, error) {
 	if kubeSelector == nil {
 		return "", nil
@@ -0,0 +1,57 @@
+package expressions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	inOp := metav1.LabelSelectorOpIn
+	notInOp := metav1.LabelSelectorOpNotIn
+	cases := map[string]*metav1.LabelSelector{
+		"app=web": {
+			MatchLabels: map[string]string{"app": "web"},
+		},
+		"app=web,api&!canary": {
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: inOp, Values: []string{"web", "api"}},
+				{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+			},
+		},
+		"app in (web, api), tier notin (db), !canary, release": {
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: inOp, Values: []string{"web", "api"}},
+				{Key: "tier", Operator: notInOp, Values: []string{"db"}},
+				{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+				{Key: "release", Operator: metav1.LabelSelectorOpExists},
+			},
+		},
+		"app == web, tier != db,cache": {
+			MatchLabels: map[string]string{"app": "web"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: notInOp, Values: []string{"db", "cache"}},
+			},
+		},
+	}
+
+	for s, expected := range cases {
+		selector, err := ParseLabelSelector(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(selector, expected) {
+			t.Errorf("%s: %s", s, pretty.Diff(expected, selector))
+		}
+	}
+
+	for _, s := range []string{"app in (web", "app is (web)", "app in ()", "app=web,,api"} {
+		if _, err := ParseLabelSelector(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
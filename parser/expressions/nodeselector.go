@@ -0,0 +1,125 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	"github.com/golang/glog"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// FieldPrefix marks a node selector requirement on a node field (matchFields) rather than a label.
+const FieldPrefix = "@"
+
+// ParseNodeSelectorTerm parses "&"-separated node selector requirements, e.g. "zone=a,b&!gpu&cores>4".
+// Requirements on node fields are prefixed with "@", e.g. "@metadata.name=node-1".
+func ParseNodeSelectorTerm(s string) (*v1.NodeSelectorTerm, error) {
+	term := &v1.NodeSelectorTerm{}
+	segs := strings.Split(s, "&")
+	for _, seg := range segs {
+		isField := strings.HasPrefix(seg, FieldPrefix)
+		if isField {
+			seg = seg[len(FieldPrefix):]
+		}
+
+		req, err := parseNodeSelectorRequirement(seg)
+		if err != nil {
+			return nil, serrors.InvalidValueForTypeContextError(err, s, v1.NodeSelectorTerm{})
+		}
+
+		if isField {
+			term.MatchFields = append(term.MatchFields, *req)
+		} else {
+			term.MatchExpressions = append(term.MatchExpressions, *req)
+		}
+	}
+
+	return term, nil
+}
+
+func parseNodeSelectorRequirement(seg string) (*v1.NodeSelectorRequirement, error) {
+	expr, err := ParseExpr(seg, []string{"!=", "=", ">", "<"})
+	if err != nil {
+		return nil, err
+	}
+
+	if expr == nil {
+		if len(seg) == 0 {
+			return nil, serrors.InvalidValueErrorf(seg, "empty subexpression")
+		}
+		if seg[0] == '!' {
+			return &v1.NodeSelectorRequirement{
+				Key:      seg[1:],
+				Operator: v1.NodeSelectorOpDoesNotExist,
+			}, nil
+		}
+
+		return &v1.NodeSelectorRequirement{
+			Key:      seg,
+			Operator: v1.NodeSelectorOpExists,
+		}, nil
+	}
+
+	var op v1.NodeSelectorOperator
+	switch expr.Op {
+	case "=":
+		op = v1.NodeSelectorOpIn
+	case "!=":
+		op = v1.NodeSelectorOpNotIn
+	case ">":
+		op = v1.NodeSelectorOpGt
+	case "<":
+		op = v1.NodeSelectorOpLt
+	default:
+		glog.Fatal("unreachable")
+	}
+
+	return &v1.NodeSelectorRequirement{
+		Key:      expr.Key,
+		Operator: op,
+		Values:   expr.Values,
+	}, nil
+}
+
+// UnparseNodeSelectorTerm is the inverse of ParseNodeSelectorTerm.
+func UnparseNodeSelectorTerm(term v1.NodeSelectorTerm) (string, error) {
+	exprs := []string{}
+	for _, req := range term.MatchExpressions {
+		expr, err := unparseNodeSelectorRequirement(req)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	for _, req := range term.MatchFields {
+		expr, err := unparseNodeSelectorRequirement(req)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, FieldPrefix+expr)
+	}
+
+	return strings.Join(exprs, "&"), nil
+}
+
+func unparseNodeSelectorRequirement(req v1.NodeSelectorRequirement) (string, error) {
+	switch req.Operator {
+	case v1.NodeSelectorOpIn:
+		return fmt.Sprintf("%s=%s", req.Key, strings.Join(req.Values, ",")), nil
+	case v1.NodeSelectorOpNotIn:
+		return fmt.Sprintf("%s!=%s", req.Key, strings.Join(req.Values, ",")), nil
+	case v1.NodeSelectorOpGt:
+		return fmt.Sprintf("%s>%s", req.Key, strings.Join(req.Values, ",")), nil
+	case v1.NodeSelectorOpLt:
+		return fmt.Sprintf("%s<%s", req.Key, strings.Join(req.Values, ",")), nil
+	case v1.NodeSelectorOpExists:
+		return req.Key, nil
+	case v1.NodeSelectorOpDoesNotExist:
+		return fmt.Sprintf("!%s", req.Key), nil
+	default:
+		return "", serrors.InvalidInstanceErrorf(req, "unsupported operator")
+	}
+}
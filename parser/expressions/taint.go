@@ -0,0 +1,88 @@
+package expressions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// TaintExpr is the grammar shared by node taints and pod tolerations:
+//
+//	key[=value][:Effect[:seconds]]
+//
+// A toleration without "=value" tolerates any value of the key (operator Exists),
+// and the key "*" tolerates every taint. Taints always have an effect, and only tolerations have seconds.
+type TaintExpr struct {
+	Key string
+	// Value is nil if there's no "=value".
+	Value   *string
+	Effect  string
+	Seconds *int64
+}
+
+// TaintEffects are the effects a taint can have.
+var TaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+func ParseTaintExpr(s string) (*TaintExpr, error) {
+	segments := strings.Split(s, ":")
+	if len(segments) > 3 {
+		return nil, serrors.InvalidValueErrorf(s, "expected key[=value][:Effect[:seconds]]")
+	}
+
+	expr := &TaintExpr{}
+	fields := strings.SplitN(segments[0], "=", 2)
+	expr.Key = fields[0]
+	if len(expr.Key) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "taint key can't be empty, use '*' to match any key")
+	}
+	if len(fields) == 2 {
+		if expr.Key == "*" {
+			return nil, serrors.InvalidValueErrorf(s, "'*' matches any key, so it can't have a value")
+		}
+		expr.Value = &fields[1]
+	}
+
+	if len(segments) > 1 {
+		expr.Effect = segments[1]
+		if !isTaintEffect(expr.Effect) {
+			return nil, serrors.InvalidValueErrorf(s, "unexpected taint effect, expected one of (%s)", strings.Join(TaintEffects, ", "))
+		}
+	}
+
+	if len(segments) > 2 {
+		seconds, err := strconv.ParseInt(segments[2], 10, 64)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, s, "expected a number of seconds after the effect")
+		}
+		expr.Seconds = &seconds
+	}
+
+	return expr, nil
+}
+
+func (e TaintExpr) String() string {
+	s := e.Key
+	if e.Value != nil {
+		s = fmt.Sprintf("%s=%s", s, *e.Value)
+	}
+	if len(e.Effect) > 0 {
+		s = fmt.Sprintf("%s:%s", s, e.Effect)
+		if e.Seconds != nil {
+			s = fmt.Sprintf("%s:%d", s, *e.Seconds)
+		}
+	}
+
+	return s
+}
+
+func isTaintEffect(effect string) bool {
+	for _, taintEffect := range TaintEffects {
+		if effect == taintEffect {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package expressions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestParseTaintExpr(t *testing.T) {
+	gpu := "gpu"
+	empty := ""
+	seconds := int64(300)
+	cases := map[string]*TaintExpr{
+		"dedicated=gpu:NoExecute:300": {Key: "dedicated", Value: &gpu, Effect: "NoExecute", Seconds: &seconds},
+		"dedicated=gpu:NoSchedule":    {Key: "dedicated", Value: &gpu, Effect: "NoSchedule"},
+		"dedicated=:NoSchedule":       {Key: "dedicated", Value: &empty, Effect: "NoSchedule"},
+		"dedicated:PreferNoSchedule":  {Key: "dedicated", Effect: "PreferNoSchedule"},
+		"dedicated=gpu":               {Key: "dedicated", Value: &gpu},
+		"dedicated":                   {Key: "dedicated"},
+		"*:NoExecute":                 {Key: "*", Effect: "NoExecute"},
+		"*":                           {Key: "*"},
+	}
+
+	for s, expected := range cases {
+		expr, err := ParseTaintExpr(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(expr, expected) {
+			t.Errorf("%s: %s", s, pretty.Diff(expr, expected))
+		}
+		if expr.String() != s {
+			t.Errorf("%s: didn't round-trip, got %s", s, expr.String())
+		}
+	}
+
+	for _, s := range []string{"", "=gpu:NoSchedule", "*=gpu", "dedicated:NoRun", "dedicated:NoExecute:soon", "a:NoExecute:1:2"} {
+		if _, err := ParseTaintExpr(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package parser
 import (
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/golang/glog"
 
@@ -24,3 +26,39 @@ func OpenStreamsFromFiles(filenames []string) ([]io.ReadCloser, error) {
 
 	return readers, nil
 }
+
+// ExpandFilenames replaces each directory in filenames with the manifest files (.yaml, .yml, .json) inside it.
+func ExpandFilenames(filenames []string) ([]string, error) {
+	expanded := []string{}
+	for _, name := range filenames {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading %s", name)
+		}
+
+		if !info.IsDir() {
+			expanded = append(expanded, name)
+			continue
+		}
+
+		err = filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				expanded = append(expanded, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "reading directory %s", name)
+		}
+	}
+
+	return expanded, nil
+}
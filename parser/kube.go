@@ -3,11 +3,22 @@ package parser
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/koki/short/crdplugin"
 	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
 
+// LeaseGroupKind identifies Leases, which the vendored Kubernetes API doesn't know.
+var LeaseGroupKind = schema.GroupKind{Group: "coordination.k8s.io", Kind: "Lease"}
+
+// unstructuredKinds are the kinds (and their versions) that are parsed as unstructured objects,
+// because the vendored Kubernetes API doesn't have types for them.
+var unstructuredKinds = map[schema.GroupKind][]string{
+	LeaseGroupKind: {"v1", "v1beta1"},
+}
+
 func ParseSingleKubeNativeFromBytes(data []byte) (runtime.Object, error) {
 	obj := map[string]interface{}{}
 	err := yaml.Unmarshal(data, &obj)
@@ -23,7 +34,17 @@ func ParseSingleKubeNative(obj map[string]interface{}) (runtime.Object, error) {
 		Object: obj,
 	}
 
-	typedObj, err := creator.New(u.GetObjectKind().GroupVersionKind())
+	gvk := u.GetObjectKind().GroupVersionKind()
+	for _, version := range unstructuredKinds[gvk.GroupKind()] {
+		if gvk.Version == version {
+			return u, nil
+		}
+	}
+	if _, ok := crdplugin.ForGroupVersionKind(gvk); ok {
+		return u, nil
+	}
+
+	typedObj, err := creator.New(gvk)
 	if err != nil {
 		return nil, serrors.InvalidValueContextErrorf(err, u, "unsupported apiVersion/kind (is the manifest kube-native format?)")
 	}
@@ -33,3 +54,34 @@ func ParseSingleKubeNative(obj map[string]interface{}) (runtime.Object, error) {
 	}
 	return typedObj, nil
 }
+
+// SetGroupVersionKind fills in the apiVersion and kind of a typed kube object that doesn't have them,
+// e.g. one returned by client-go.
+func SetGroupVersionKind(obj runtime.Object) error {
+	if !obj.GetObjectKind().GroupVersionKind().Empty() {
+		return nil
+	}
+
+	gvk, err := GroupVersionKind(obj)
+	if err != nil {
+		return err
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// GroupVersionKind returns the apiVersion and kind of a typed kube object's type.
+func GroupVersionKind(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, _, err := creator.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, serrors.TypeErrorf(obj, "unsupported kube type")
+	}
+
+	return gvks[0], nil
+}
+
+// UnstructuredVersions returns the versions of a kind that are parsed as unstructured objects, if any.
+func UnstructuredVersions(gk schema.GroupKind) []string {
+	return unstructuredKinds[gk]
+}
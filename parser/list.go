@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// FlattenList returns the items of a Kubernetes List (e.g. the output of "kubectl get all -o yaml")
+// as individual objects. Any other object is returned as-is.
+// Items of typed lists (e.g. PodList) often omit their own kind and apiVersion,
+// so they're filled in from the list.
+func FlattenList(obj map[string]interface{}) ([]map[string]interface{}, error) {
+	kind, _ := obj["kind"].(string)
+	items, hasItems := obj["items"]
+	if !strings.HasSuffix(kind, "List") || !hasItems {
+		return []map[string]interface{}{obj}, nil
+	}
+
+	if items == nil {
+		return []map[string]interface{}{}, nil
+	}
+
+	itemList, ok := items.([]interface{})
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(items, "expected a list of Kubernetes objects in %s", kind)
+	}
+
+	objs := []map[string]interface{}{}
+	for _, item := range itemList {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, serrors.InvalidValueErrorf(item, "expected a Kubernetes object in %s", kind)
+		}
+		if _, ok := itemObj["kind"]; !ok && kind != "List" {
+			itemObj["kind"] = strings.TrimSuffix(kind, "List")
+		}
+		if _, ok := itemObj["apiVersion"]; !ok {
+			if apiVersion, ok := obj["apiVersion"]; ok {
+				itemObj["apiVersion"] = apiVersion
+			}
+		}
+		objs = append(objs, itemObj)
+	}
+
+	return objs, nil
+}
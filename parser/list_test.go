@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenList(t *testing.T) {
+	pod := map[string]interface{}{"kind": "Pod"}
+	objs, err := FlattenList(pod)
+	if err != nil || len(objs) != 1 || !reflect.DeepEqual(objs[0], pod) {
+		t.Errorf("non-list object wasn't returned as-is: %#v (%v)", objs, err)
+	}
+
+	list := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "PodList",
+		"items": []interface{}{
+			map[string]interface{}{"metadata": map[string]interface{}{"name": "a"}},
+			map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "metadata": map[string]interface{}{"name": "b"}},
+		},
+	}
+	objs, err = FlattenList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("expected 2 items, got %#v", objs)
+	}
+	for _, obj := range objs {
+		if obj["kind"] != "Pod" || obj["apiVersion"] != "v1" {
+			t.Errorf("typed list item is missing its kind or apiVersion: %#v", obj)
+		}
+	}
+
+	objs, err = FlattenList(map[string]interface{}{"kind": "List", "items": nil})
+	if err != nil || len(objs) != 0 {
+		t.Errorf("empty list should have no items: %#v (%v)", objs, err)
+	}
+
+	_, err = FlattenList(map[string]interface{}{"kind": "List", "items": []interface{}{"oops"}})
+	if err == nil {
+		t.Errorf("expected an error for a list item that isn't an object")
+	}
+}
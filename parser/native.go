@@ -2,7 +2,9 @@ package parser
 
 import (
 	"github.com/koki/json"
+	"github.com/koki/short/crdplugin"
 	"github.com/koki/short/types"
+	"github.com/koki/short/util/humanize"
 	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
@@ -18,6 +20,12 @@ func ParseKokiNativeObject(obj interface{}) (interface{}, error) {
 		return nil, serrors.InvalidValueErrorf(objMap, "Invalid koki syntax")
 	}
 
+	// Durations may be written in friendlier units, e.g. "1m30s".
+	err := humanize.ParseDurations(objMap)
+	if err != nil {
+		return nil, err
+	}
+
 	bytes, err := json.Marshal(objMap)
 	if err != nil {
 		return nil, serrors.InvalidValueContextErrorf(err, objMap, "error converting to JSON before re-parsing as as koki obj")
@@ -26,7 +34,7 @@ func ParseKokiNativeObject(obj interface{}) (interface{}, error) {
 	for k := range objMap {
 		switch k {
 		case "api_service":
-			apiService := &types.APIService{}
+			apiService := &types.APIServiceWrapper{}
 			err := json.Unmarshal(bytes, apiService)
 			if err != nil {
 				return nil, serrors.InvalidValueForTypeContextError(err, objMap, apiService)
@@ -144,6 +152,13 @@ func ParseKokiNativeObject(obj interface{}) (interface{}, error) {
 				return nil, serrors.InvalidValueForTypeContextError(err, objMap, job)
 			}
 			return job, nil
+		case "lease":
+			lease := &types.LeaseWrapper{}
+			err := json.Unmarshal(bytes, lease)
+			if err != nil {
+				return nil, serrors.InvalidValueForTypeContextError(err, objMap, lease)
+			}
+			return lease, nil
 		case "limit_range":
 			result := &types.LimitRangeWrapper{}
 			err := json.Unmarshal(bytes, result)
@@ -158,6 +173,13 @@ func ParseKokiNativeObject(obj interface{}) (interface{}, error) {
 				return nil, serrors.InvalidValueForTypeContextError(err, objMap, namespace)
 			}
 			return namespace, nil
+		case "node":
+			node := &types.NodeWrapper{}
+			err := json.Unmarshal(bytes, node)
+			if err != nil {
+				return nil, serrors.InvalidValueForTypeContextError(err, objMap, node)
+			}
+			return node, nil
 		case "pdb":
 			pdb := &types.PodDisruptionBudgetWrapper{}
 			err := json.Unmarshal(bytes, pdb)
@@ -299,11 +321,49 @@ func ParseKokiNativeObject(obj interface{}) (interface{}, error) {
 			}
 			return validatingConfig, nil
 		}
+		if converter, ok := crdplugin.ForKey(k); ok {
+			result := converter.NewKoki()
+			err := json.Unmarshal(bytes, result)
+			if err != nil {
+				return nil, serrors.InvalidValueForTypeContextError(err, objMap, result)
+			}
+			return result, nil
+		}
 		return nil, serrors.TypeErrorf(objMap, "Unexpected key (%s)", k)
 	}
 	return nil, nil
 }
 
+// kokiKeys are the keys of the short syntax objects that ParseKokiNativeObject parses, besides those of crdplugin.
+var kokiKeys = map[string]bool{
+	"api_service": true, "binding": true, "csr": true, "cluster_role": true,
+	"cluster_role_binding": true, "config_map": true, "controller_revision": true, "crd": true,
+	"cron_job": true, "daemon_set": true, "deployment": true, "endpoints": true, "event": true,
+	"hpa": true, "ingress": true, "initializer_config": true, "job": true, "lease": true,
+	"limit_range": true, "namespace": true, "node": true, "pdb": true, "persistent_volume": true,
+	"pod": true, "pod_preset": true, "pod_security_policy": true, "pod_template": true,
+	"priority_class": true, "pvc": true, "replica_set": true, "replication_controller": true,
+	"role": true, "role_binding": true, "secret": true, "service": true, "service_account": true,
+	"stateful_set": true, "storage_class": true, "volume": true, "mutating_webhook": true,
+	"validating_webhook": true,
+}
+
+// IsKokiNativeObject is true if obj looks like a short syntax object, i.e. its only key is a short syntax key
+// such as "persistent_volume". It doesn't check the rest of the object.
+func IsKokiNativeObject(obj map[string]interface{}) bool {
+	if len(obj) != 1 {
+		return false
+	}
+	for k := range obj {
+		if _, ok := crdplugin.ForKey(k); ok {
+			return true
+		}
+		return kokiKeys[k]
+	}
+
+	return false
+}
+
 func UnparseKokiNativeObject(kokiObj interface{}) (map[string]interface{}, error) {
 	// Marshal the koki object back into yaml.
 	bytes, err := yaml.Marshal(kokiObj)
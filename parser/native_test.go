@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsKokiNativeObject(t *testing.T) {
+	for _, obj := range []map[string]interface{}{
+		{"persistent_volume": map[string]interface{}{"name": "data"}},
+		{"deployment": map[string]interface{}{}},
+	} {
+		if !IsKokiNativeObject(obj) {
+			t.Errorf("expected short syntax: %#v", obj)
+		}
+	}
+
+	for _, obj := range []map[string]interface{}{
+		{"apiVersion": "v1", "kind": "PersistentVolume"},
+		{"kind": "Pod"},
+		{"widget": map[string]interface{}{}},
+		{"pod": map[string]interface{}{}, "kind": "Pod"},
+		{},
+	} {
+		if IsKokiNativeObject(obj) {
+			t.Errorf("expected Kubernetes syntax: %#v", obj)
+		}
+	}
+}
+
+func TestKokiKeys(t *testing.T) {
+	for key := range kokiKeys {
+		_, err := ParseKokiNativeObject(map[string]interface{}{key: map[string]interface{}{}})
+		if err != nil && strings.Contains(err.Error(), "Unexpected key") {
+			t.Errorf("%s isn't parsed by ParseKokiNativeObject", key)
+		}
+	}
+}
@@ -35,7 +35,7 @@ func Parse(filenames []string, useStdin bool) ([]map[string]interface{}, error)
 	return ParseStreams(streams)
 }
 
-//parses each stream into a go object and closes the stream once done
+// parses each stream into a go object and closes the stream once done
 func ParseStreams(streams []io.ReadCloser) ([]map[string]interface{}, error) {
 	structs := []map[string]interface{}{}
 
@@ -54,10 +54,12 @@ func ParseStreams(streams []io.ReadCloser) ([]map[string]interface{}, error) {
 				return nil, err
 			}
 			if err == nil {
-				structs = append(structs, into)
+				objs, err := FlattenList(into)
+				if err != nil {
+					return nil, err
+				}
+				structs = append(structs, objs...)
 			}
-			// TBD: Add support for v1.List type by flattening it
-			// and then converting them to individual objects
 		}
 	}
 	return structs, nil
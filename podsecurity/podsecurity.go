@@ -0,0 +1,214 @@
+package podsecurity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koki/short/types"
+)
+
+/*
+
+Podsecurity maps PodSecurityPolicies to the Pod Security Standards that replace them, for
+clusters moving from PodSecurityPolicy to Pod Security admission.
+
+A policy maps to the most restrictive level that still admits every pod the policy admits,
+following the Kubernetes guide "Mapping PodSecurityPolicies to Pod Security Standards".
+Only the fields that restrict pods are checked. Fields that change pods (e.g. defaults) are
+ignored, since Pod Security admission never changes pods.
+
+Policies are Kubernetes dictionaries, in extensions/v1beta1 or policy/v1beta1.
+
+*/
+
+// Levels are the Pod Security Standard levels, from least to most restrictive.
+var Levels = []string{types.PodSecurityPrivileged, types.PodSecurityBaseline, types.PodSecurityRestricted}
+
+// baselineCapabilities may be added to containers by pods at the baseline level.
+var baselineCapabilities = map[string]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// baselineSELinuxTypes are the SELinux types that pods at the baseline level may use.
+var baselineSELinuxTypes = map[string]bool{
+	"":                 true,
+	"container_t":      true,
+	"container_init_t": true,
+	"container_kvm_t":  true,
+}
+
+// restrictedVolumes are the volume types that pods at the restricted level may use.
+var restrictedVolumes = map[string]bool{
+	"configMap":             true,
+	"csi":                   true,
+	"downwardAPI":           true,
+	"emptyDir":              true,
+	"ephemeral":             true,
+	"persistentVolumeClaim": true,
+	"projected":             true,
+	"secret":                true,
+}
+
+// SeccompAnnotation lists the seccomp profiles that a policy allows.
+const SeccompAnnotation = "seccomp.security.alpha.kubernetes.io/allowedProfileNames"
+
+// Level returns the most restrictive level that admits every pod the policy admits.
+// It also returns why the policy doesn't map to a more restrictive level.
+func Level(psp map[string]interface{}) (string, []string) {
+	spec, _ := psp["spec"].(map[string]interface{})
+
+	privileged := []string{}
+	for _, field := range []string{"privileged", "hostNetwork", "hostPID", "hostIPC"} {
+		if spec[field] == true {
+			privileged = append(privileged, fmt.Sprintf("allows %s", field))
+		}
+	}
+	if hostPorts, _ := spec["hostPorts"].([]interface{}); len(hostPorts) > 0 {
+		privileged = append(privileged, "allows host ports")
+	}
+	volumes := stringsAt(spec, "volumes")
+	for _, volume := range volumes {
+		if volume == "*" || volume == "hostPath" {
+			privileged = append(privileged, fmt.Sprintf("allows %s volumes", volume))
+		}
+	}
+	capabilities := append(stringsAt(spec, "allowedCapabilities"), stringsAt(spec, "defaultAddCapabilities")...)
+	for _, capability := range capabilities {
+		if !baselineCapabilities[capability] {
+			privileged = append(privileged, fmt.Sprintf("allows capability %s", capability))
+		}
+	}
+	seLinux, _ := spec["seLinux"].(map[string]interface{})
+	seLinuxOptions, _ := seLinux["seLinuxOptions"].(map[string]interface{})
+	seLinuxType, _ := seLinuxOptions["type"].(string)
+	if seLinux["rule"] != "MustRunAs" || seLinuxOptions["user"] != nil || seLinuxOptions["role"] != nil || !baselineSELinuxTypes[seLinuxType] {
+		privileged = append(privileged, "allows custom SELinux options")
+	}
+	if sysctls := stringsAt(spec, "allowedUnsafeSysctls"); len(sysctls) > 0 {
+		privileged = append(privileged, fmt.Sprintf("allows unsafe sysctls %s", strings.Join(sysctls, ", ")))
+	}
+	for _, procMount := range stringsAt(spec, "allowedProcMountTypes") {
+		if procMount != "Default" {
+			privileged = append(privileged, fmt.Sprintf("allows %s proc mounts", procMount))
+		}
+	}
+	if len(privileged) > 0 {
+		return types.PodSecurityPrivileged, privileged
+	}
+
+	baseline := []string{}
+	for _, volume := range volumes {
+		if !restrictedVolumes[volume] {
+			baseline = append(baseline, fmt.Sprintf("allows %s volumes", volume))
+		}
+	}
+	if spec["allowPrivilegeEscalation"] != false {
+		baseline = append(baseline, "allows privilege escalation")
+	}
+	if !requiresNonRoot(spec) {
+		baseline = append(baseline, "allows running as root")
+	}
+	if !contains(stringsAt(spec, "requiredDropCapabilities"), "ALL") {
+		baseline = append(baseline, "doesn't drop ALL capabilities")
+	}
+	for _, capability := range capabilities {
+		if capability != "NET_BIND_SERVICE" {
+			baseline = append(baseline, fmt.Sprintf("allows capability %s", capability))
+		}
+	}
+	if !requiresSeccomp(psp) {
+		baseline = append(baseline, "allows running without a seccomp profile")
+	}
+	if len(baseline) > 0 {
+		return types.PodSecurityBaseline, baseline
+	}
+
+	return types.PodSecurityRestricted, nil
+}
+
+// LeastRestrictive returns the least restrictive of the levels, or restricted if there aren't any.
+func LeastRestrictive(levels []string) string {
+	result := len(Levels) - 1
+	for _, level := range levels {
+		for i := range Levels {
+			if Levels[i] == level && i < result {
+				result = i
+			}
+		}
+	}
+
+	return Levels[result]
+}
+
+// requiresNonRoot is true if the policy makes pods run as a user other than root.
+func requiresNonRoot(spec map[string]interface{}) bool {
+	runAsUser, _ := spec["runAsUser"].(map[string]interface{})
+	switch runAsUser["rule"] {
+	case "MustRunAsNonRoot":
+		return true
+	case "MustRunAs":
+		ranges, _ := runAsUser["ranges"].([]interface{})
+		for _, r := range ranges {
+			rangeMap, _ := r.(map[string]interface{})
+			if min, ok := rangeMap["min"].(float64); !ok || min < 1 {
+				return false
+			}
+		}
+		return len(ranges) > 0
+	}
+
+	return false
+}
+
+// requiresSeccomp is true if the policy only allows the runtime's default or local seccomp profiles.
+func requiresSeccomp(psp map[string]interface{}) bool {
+	metadata, _ := psp["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	allowed, _ := annotations[SeccompAnnotation].(string)
+	if len(allowed) == 0 {
+		return false
+	}
+
+	for _, profile := range strings.Split(allowed, ",") {
+		profile = strings.TrimSpace(profile)
+		if profile != "runtime/default" && profile != "docker/default" && !strings.HasPrefix(profile, "localhost/") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringsAt(obj map[string]interface{}, key string) []string {
+	list, _ := obj[key].([]interface{})
+	result := []string{}
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
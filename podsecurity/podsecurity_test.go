@@ -0,0 +1,101 @@
+package podsecurity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/types"
+)
+
+func TestLevel(t *testing.T) {
+	restricted := func() map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodSecurityPolicy",
+			"metadata": map[string]interface{}{
+				"name": "restricted",
+				"annotations": map[string]interface{}{
+					SeccompAnnotation: "runtime/default, localhost/audit",
+				},
+			},
+			"spec": map[string]interface{}{
+				"allowPrivilegeEscalation": false,
+				"requiredDropCapabilities": []interface{}{"ALL"},
+				"volumes":                  []interface{}{"configMap", "secret", "projected"},
+				"runAsUser": map[string]interface{}{
+					"rule":   "MustRunAs",
+					"ranges": []interface{}{map[string]interface{}{"min": float64(1000), "max": float64(2000)}},
+				},
+				"seLinux": map[string]interface{}{"rule": "MustRunAs"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		change          func(psp, spec map[string]interface{})
+		expectedLevel   string
+		expectedReasons []string
+	}{
+		{
+			change:        func(psp, spec map[string]interface{}) {},
+			expectedLevel: types.PodSecurityRestricted,
+		},
+		{
+			change: func(psp, spec map[string]interface{}) {
+				spec["runAsUser"] = map[string]interface{}{
+					"rule":   "MustRunAs",
+					"ranges": []interface{}{map[string]interface{}{"min": float64(0), "max": float64(2000)}},
+				}
+				spec["allowedCapabilities"] = []interface{}{"CHOWN"}
+				delete(psp, "metadata")
+			},
+			expectedLevel: types.PodSecurityBaseline,
+			expectedReasons: []string{
+				"allows running as root",
+				"allows capability CHOWN",
+				"allows running without a seccomp profile",
+			},
+		},
+		{
+			change: func(psp, spec map[string]interface{}) {
+				spec["hostPID"] = true
+				spec["volumes"] = []interface{}{"secret", "hostPath"}
+				spec["seLinux"] = map[string]interface{}{"rule": "RunAsAny"}
+			},
+			expectedLevel: types.PodSecurityPrivileged,
+			expectedReasons: []string{
+				"allows hostPID",
+				"allows hostPath volumes",
+				"allows custom SELinux options",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		psp := restricted()
+		testCase.change(psp, psp["spec"].(map[string]interface{}))
+		level, reasons := Level(psp)
+		if level != testCase.expectedLevel || !reflect.DeepEqual(reasons, testCase.expectedReasons) {
+			t.Errorf("case %d: expected %s %s, got %s %s", i, testCase.expectedLevel, pretty.Sprint(testCase.expectedReasons), level, pretty.Sprint(reasons))
+		}
+	}
+}
+
+func TestLeastRestrictive(t *testing.T) {
+	testCases := []struct {
+		levels   []string
+		expected string
+	}{
+		{levels: nil, expected: types.PodSecurityRestricted},
+		{levels: []string{types.PodSecurityRestricted, types.PodSecurityBaseline}, expected: types.PodSecurityBaseline},
+		{levels: []string{types.PodSecurityBaseline, types.PodSecurityPrivileged, types.PodSecurityRestricted}, expected: types.PodSecurityPrivileged},
+	}
+
+	for _, testCase := range testCases {
+		if level := LeastRestrictive(testCase.levels); level != testCase.expected {
+			t.Errorf("%v: expected %s, got %s", testCase.levels, testCase.expected, level)
+		}
+	}
+}
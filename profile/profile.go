@@ -0,0 +1,304 @@
+package profile
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/compat"
+	"github.com/koki/short/util/objutil"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A profile is an organization's base layer for the objects short converts to Kubernetes syntax:
+labels, annotations, a namespace, container resources and an image registry that every object
+gets unless it sets its own.
+
+	namespace: payments
+	labels:
+	  team: payments
+	annotations:
+	  owner: payments@example.com
+	registry: registry.example.com
+	resources:
+	  cpu: {min: 100m, max: "1"}
+	  mem: {min: 128Mi, max: 512Mi}
+	kinds:
+	  job:
+	    labels:
+	      tier: batch
+	    resources:
+	      mem: {max: 2Gi}
+
+Kinds are short or Kubernetes kinds (e.g. "stateful_set" or "StatefulSet"). Their settings are
+merged over the top-level ones.
+
+*/
+
+// DefaultFile is the profile that's used if none is given and it exists in the current directory.
+const DefaultFile = ".shortrc"
+
+// Bounds are the minimum (request) and maximum (limit) of a resource.
+type Bounds struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+type Resources struct {
+	CPU *Bounds `json:"cpu,omitempty"`
+	Mem *Bounds `json:"mem,omitempty"`
+}
+
+// Settings are what a profile adds to each object.
+type Settings struct {
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Registry is prefixed to images that don't name a registry, e.g. "nginx:1.25".
+	Registry  string     `json:"registry,omitempty"`
+	Resources *Resources `json:"resources,omitempty"`
+}
+
+type Profile struct {
+	Settings `json:",inline"`
+	Kinds    map[string]Settings `json:"kinds,omitempty"`
+}
+
+// ClusterScopedKinds never get a namespace.
+var ClusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"CertificateSigningRequest":      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"InitializerConfiguration":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// Load reads a profile. If filename is empty, it reads DefaultFile if it exists, and returns nil otherwise.
+func Load(filename string) (*Profile, error) {
+	if len(filename) == 0 {
+		if _, err := os.Stat(DefaultFile); err != nil {
+			return nil, nil
+		}
+		filename = DefaultFile
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading profile %s", filename)
+	}
+
+	p := &Profile{}
+	err = yaml.Unmarshal(b, p)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextError(err, string(b), p)
+	}
+
+	return p, nil
+}
+
+// SettingsFor merges the settings for a Kubernetes kind over the top-level ones.
+func (p *Profile) SettingsFor(kind string) Settings {
+	s := Settings{
+		Namespace:   p.Namespace,
+		Labels:      merge(nil, p.Labels),
+		Annotations: merge(nil, p.Annotations),
+		Registry:    p.Registry,
+		Resources:   mergeResources(nil, p.Resources),
+	}
+
+	// Visit kinds in order, so the result doesn't depend on map order if a kind is listed twice.
+	keys := []string{}
+	for key := range p.Kinds {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if objutil.NormalizeKind(key) != objutil.NormalizeKind(kind) {
+			continue
+		}
+		override := p.Kinds[key]
+		if len(override.Namespace) > 0 {
+			s.Namespace = override.Namespace
+		}
+		if len(override.Registry) > 0 {
+			s.Registry = override.Registry
+		}
+		s.Labels = merge(s.Labels, override.Labels)
+		s.Annotations = merge(s.Annotations, override.Annotations)
+		s.Resources = mergeResources(s.Resources, override.Resources)
+	}
+
+	return s
+}
+
+// Apply adds the profile's settings to a Kubernetes object, in place. Values the object sets itself are kept.
+func (p *Profile) Apply(obj map[string]interface{}) {
+	kind, _ := obj["kind"].(string)
+	s := p.SettingsFor(kind)
+
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	if len(s.Namespace) > 0 && !ClusterScopedKinds[kind] {
+		if namespace, _ := metadata["namespace"].(string); len(namespace) == 0 {
+			metadata["namespace"] = s.Namespace
+		}
+	}
+	addAll(metadata, "labels", s.Labels)
+	addAll(metadata, "annotations", s.Annotations)
+
+	path, ok := compat.PodSpecPath(kind)
+	if !ok {
+		return
+	}
+	val, err := objutil.AtPathIn(obj, strings.Split(path, "."))
+	if err != nil {
+		return
+	}
+	podSpec, ok := val.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, key := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[key].([]interface{})
+		for _, container := range containers {
+			if containerMap, ok := container.(map[string]interface{}); ok {
+				applyContainer(s, containerMap)
+			}
+		}
+	}
+}
+
+// Objs adds the profile's settings to Kubernetes objects.
+func (p *Profile) Objs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		p.Apply(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
+
+func applyContainer(s Settings, container map[string]interface{}) {
+	if image, ok := container["image"].(string); ok && len(s.Registry) > 0 && !hasRegistry(image) {
+		container["image"] = strings.TrimSuffix(s.Registry, "/") + "/" + image
+	}
+
+	if s.Resources == nil {
+		return
+	}
+	resources, ok := container["resources"].(map[string]interface{})
+	if !ok {
+		resources = map[string]interface{}{}
+	}
+	for resource, bounds := range map[string]*Bounds{"cpu": s.Resources.CPU, "memory": s.Resources.Mem} {
+		if bounds == nil {
+			continue
+		}
+		addAll(resources, "requests", map[string]string{resource: bounds.Min})
+		addAll(resources, "limits", map[string]string{resource: bounds.Max})
+	}
+	if len(resources) > 0 {
+		container["resources"] = resources
+	}
+}
+
+// hasRegistry is true if an image names its registry, e.g. "gcr.io/project/app" or "localhost:5000/app".
+func hasRegistry(image string) bool {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return false
+	}
+
+	host := image[:i]
+	return strings.ContainsAny(host, ".:") || host == "localhost"
+}
+
+// addAll sets the values that aren't set yet in the map under key, leaving out empty values.
+func addAll(obj map[string]interface{}, key string, values map[string]string) {
+	target, ok := obj[key].(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+	for k, v := range values {
+		if _, ok := target[k]; !ok && len(v) > 0 {
+			target[k] = v
+		}
+	}
+	if len(target) > 0 {
+		obj[key] = target
+	}
+}
+
+func merge(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func mergeResources(base, override *Resources) *Resources {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	merged := &Resources{}
+	for _, r := range []*Resources{base, override} {
+		if r == nil {
+			continue
+		}
+		merged.CPU = mergeBounds(merged.CPU, r.CPU)
+		merged.Mem = mergeBounds(merged.Mem, r.Mem)
+	}
+
+	return merged
+}
+
+func mergeBounds(base, override *Bounds) *Bounds {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		b := *override
+		return &b
+	}
+
+	merged := *base
+	if len(override.Min) > 0 {
+		merged.Min = override.Min
+	}
+	if len(override.Max) > 0 {
+		merged.Max = override.Max
+	}
+
+	return &merged
+}
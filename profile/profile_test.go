@@ -0,0 +1,136 @@
+package profile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "profile.yaml")
+	err = ioutil.WriteFile(file, []byte(`namespace: payments
+labels:
+  team: payments
+registry: registry.example.com
+resources:
+  cpu: {min: 100m, max: "1"}
+kinds:
+  deployment:
+    labels:
+      tier: web
+    resources:
+      cpu: {max: "2"}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs := []interface{}{
+		map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "web", "labels": map[string]interface{}{"team": "frontend"}},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "web", "image": "nginx:1.25"},
+							map[string]interface{}{
+								"name":      "proxy",
+								"image":     "gcr.io/proxy:v2",
+								"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "500m"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"kind":     "Namespace",
+			"metadata": map[string]interface{}{"name": "payments"},
+		},
+	}
+	expected := []interface{}{
+		map[string]interface{}{
+			"kind": "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "payments",
+				"labels":    map[string]interface{}{"team": "frontend", "tier": "web"},
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "web",
+								"image": "registry.example.com/nginx:1.25",
+								"resources": map[string]interface{}{
+									"limits":   map[string]interface{}{"cpu": "2"},
+									"requests": map[string]interface{}{"cpu": "100m"},
+								},
+							},
+							map[string]interface{}{
+								"name":  "proxy",
+								"image": "gcr.io/proxy:v2",
+								"resources": map[string]interface{}{
+									"limits":   map[string]interface{}{"cpu": "500m"},
+									"requests": map[string]interface{}{"cpu": "100m"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"kind":     "Namespace",
+			"metadata": map[string]interface{}{"name": "payments", "labels": map[string]interface{}{"team": "payments"}},
+		},
+	}
+
+	results, err := p.Objs(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("%s", pretty.Diff(expected, results))
+	}
+}
+
+func TestLoadMissingDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load("")
+	if err != nil || p != nil {
+		t.Errorf("expected no profile, got %v (%v)", p, err)
+	}
+}
@@ -0,0 +1,213 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/parser"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A monorepo can hold several independent sets of manifests ("projects").
+A directory is a project root if it has a config file:
+
+	# short.project.yaml
+	name: payments        # defaults to the directory's name
+	manifests:            # relative to the directory, defaults to the directory itself
+	- k8s
+	transforms: transforms.yaml
+	vendor_dir: vendor
+
+Otherwise, by convention, the topmost directories that directly contain *.short.yaml files are project roots.
+Discovery doesn't look inside a project root, or inside hidden and "vendor" directories.
+
+*/
+
+const (
+	ConfigFilename = "short.project.yaml"
+
+	// DefaultVendorDir is where the vendor command writes, relative to the project root.
+	DefaultVendorDir = "vendor"
+
+	shortSuffix = ".short.yaml"
+)
+
+type Project struct {
+	Name       string   `json:"name,omitempty"`
+	Manifests  []string `json:"manifests,omitempty"`
+	Transforms string   `json:"transforms,omitempty"`
+	VendorDir  string   `json:"vendor_dir,omitempty"`
+
+	// Dir is the project root.
+	Dir string `json:"-"`
+	// Configured is true if the project has a config file, rather than being found by convention.
+	Configured bool `json:"-"`
+}
+
+// Discover finds the projects under root, sorted by directory.
+func Discover(root string) ([]*Project, error) {
+	projects := []*Project{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, "reading %s", path)
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == DefaultVendorDir) {
+			return filepath.SkipDir
+		}
+
+		project, err := load(path)
+		if err != nil {
+			return err
+		}
+		if project != nil {
+			projects = append(projects, project)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Dir < projects[j].Dir
+	})
+
+	return projects, nil
+}
+
+// load returns the project rooted at dir, or nil if dir isn't a project root.
+func load(dir string) (*Project, error) {
+	configPath := filepath.Join(dir, ConfigFilename)
+	b, err := ioutil.ReadFile(configPath)
+	if err == nil {
+		project := &Project{}
+		err = yaml.Unmarshal(b, project)
+		if err != nil {
+			return nil, serrors.InvalidValueForTypeContextError(err, string(b), project)
+		}
+		project.Dir = dir
+		project.Configured = true
+		if len(project.Name) == 0 {
+			project.Name = dirName(dir)
+		}
+
+		return project, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, serrors.ContextualizeErrorf(err, "reading %s", configPath)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading %s", dir)
+	}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), shortSuffix) {
+			return &Project{Name: dirName(dir), Dir: dir}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func dirName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+
+	return filepath.Base(abs)
+}
+
+// Find looks up a project by name, or by its directory.
+func Find(projects []*Project, name string) (*Project, error) {
+	matches := []*Project{}
+	for _, project := range projects {
+		if project.Name == name || filepath.Clean(project.Dir) == filepath.Clean(name) {
+			matches = append(matches, project)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, serrors.InvalidValueErrorf(name, "no such project (%s)", name)
+	case 1:
+		return matches[0], nil
+	default:
+		dirs := make([]string, len(matches))
+		for i, project := range matches {
+			dirs[i] = project.Dir
+		}
+		return nil, serrors.InvalidValueErrorf(name, "ambiguous project name (%s), use one of the directories (%s)", name, strings.Join(dirs, ", "))
+	}
+}
+
+// Path resolves a path relative to the project root.
+func (p *Project) Path(path string) string {
+	if len(path) == 0 || filepath.IsAbs(path) || path == "-" {
+		return path
+	}
+
+	return filepath.Join(p.Dir, path)
+}
+
+// Inputs returns the project's manifest files and directories.
+func (p *Project) Inputs() []string {
+	if len(p.Manifests) == 0 {
+		return []string{p.Dir}
+	}
+
+	inputs := make([]string, len(p.Manifests))
+	for i, manifest := range p.Manifests {
+		inputs[i] = p.Path(manifest)
+	}
+
+	return inputs
+}
+
+// Files expands Inputs into manifest files, leaving out the project's own config, transforms and vendor directory.
+func (p *Project) Files() ([]string, error) {
+	files, err := parser.ExpandFilenames(p.Inputs())
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[string]bool{
+		filepath.Join(p.Dir, ConfigFilename): true,
+	}
+	if len(p.Transforms) > 0 {
+		excluded[p.Path(p.Transforms)] = true
+	}
+	vendorPrefix := p.Vendor() + string(filepath.Separator)
+
+	manifests := []string{}
+	for _, file := range files {
+		file = filepath.Clean(file)
+		if excluded[file] || strings.HasPrefix(file, vendorPrefix) {
+			continue
+		}
+		manifests = append(manifests, file)
+	}
+
+	return manifests, nil
+}
+
+// Vendor returns the project's vendor directory.
+func (p *Project) Vendor() string {
+	if len(p.VendorDir) == 0 {
+		return p.Path(DefaultVendorDir)
+	}
+
+	return p.Path(p.VendorDir)
+}
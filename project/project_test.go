@@ -0,0 +1,95 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "short-projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"services/payments/short.project.yaml":         "name: payments\nmanifests:\n- k8s\ntransforms: transforms.yaml\n",
+		"services/payments/transforms.yaml":            "transforms: []\n",
+		"services/payments/k8s/app.short.yaml":         "pod:\n  name: app\n",
+		"services/payments/k8s/nested/db.short.yaml":   "pod:\n  name: db\n",
+		"services/search/deploy/web.short.yaml":        "pod:\n  name: web\n",
+		"services/search/deploy/vendor/imports/x.yaml": "pod:\n  name: x\n",
+		"services/search/deploy/sub/more.short.yaml":   "pod:\n  name: more\n",
+		".git/ignored.short.yaml":                      "pod:\n  name: ignored\n",
+	})
+
+	projects, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"payments", "deploy"}) {
+		t.Fatalf("unexpected projects %v", names)
+	}
+
+	payments, err := Find(projects, "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !payments.Configured || payments.Path(payments.Transforms) != filepath.Join(dir, "services/payments/transforms.yaml") {
+		t.Errorf("unexpected project %# v", pretty.Formatter(payments))
+	}
+	files, err := payments.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		filepath.Join(dir, "services/payments/k8s/app.short.yaml"),
+		filepath.Join(dir, "services/payments/k8s/nested/db.short.yaml"),
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Error(pretty.Diff(files, expected))
+	}
+
+	search, err := Find(projects, filepath.Join(dir, "services/search/deploy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err = search.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		filepath.Join(dir, "services/search/deploy/sub/more.short.yaml"),
+		filepath.Join(dir, "services/search/deploy/web.short.yaml"),
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Error(pretty.Diff(files, expected))
+	}
+
+	if _, err := Find(projects, "missing"); err == nil {
+		t.Error("expected an error for a missing project")
+	}
+}
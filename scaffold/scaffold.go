@@ -0,0 +1,376 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A scaffold is a minimal short manifest of a kind for new users to start from, e.g. for
+"short new deployment web". Its values are ${param} template holes, like those of modules:
+
+	deployment:
+	  version: apps/v1
+	  name: ${name}
+	  replicas: ${replicas}
+	  ...
+
+Fill replaces the holes of the params that have a value, or a default, and leaves the
+rest to be filled later, e.g. with "short -k --set image=nginx". The result is a text
+template rather than a dictionary, so that it keeps the order of its fields.
+
+*/
+
+type Param struct {
+	Name        string
+	Description string
+	// Default is used when the param isn't given a value. Params without one are left as holes.
+	Default string
+	// Int params only take whole numbers.
+	Int bool
+}
+
+type Scaffold struct {
+	// Kind is the short syntax key, e.g. "deployment".
+	Kind        string
+	Description string
+	Params      []Param
+	Template    string
+}
+
+var (
+	nameParam      = Param{Name: "name", Description: "name of the object"}
+	imageParam     = Param{Name: "image", Description: "container image, e.g. nginx:1.15"}
+	portParam      = Param{Name: "port", Description: "container port", Default: "8080", Int: true}
+	replicasParam  = Param{Name: "replicas", Description: "number of pods", Default: "1", Int: true}
+	namespaceParam = Param{Name: "namespace", Description: "namespace of the object", Default: "default"}
+)
+
+var scaffolds = []Scaffold{
+	{
+		Kind:        "config_map",
+		Description: "configuration data for pods",
+		Params:      []Param{nameParam, namespaceParam, {Name: "key", Description: "key of the first entry", Default: "config"}, {Name: "value", Description: "value of the first entry"}},
+		Template: `config_map:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+  data:
+    ${key}: ${value}
+`,
+	},
+	{
+		Kind:        "cron_job",
+		Description: "a job that runs on a schedule",
+		Params:      []Param{nameParam, namespaceParam, {Name: "schedule", Description: "cron schedule", Default: "0 * * * *"}, imageParam},
+		Template: `cron_job:
+  version: batch/v1beta1
+  name: ${name}
+  namespace: ${namespace}
+  schedule: ${schedule}
+  restart_policy: on-failure
+  containers:
+  - name: ${name}
+    image: ${image}
+`,
+	},
+	{
+		Kind:        "daemon_set",
+		Description: "a pod on every node",
+		Params:      []Param{nameParam, namespaceParam, imageParam},
+		Template: `daemon_set:
+  version: apps/v1
+  name: ${name}
+  namespace: ${namespace}
+  selector:
+    app: ${name}
+  containers:
+  - name: ${name}
+    image: ${image}
+`,
+	},
+	{
+		Kind:        "deployment",
+		Description: "replicated stateless pods",
+		Params:      []Param{nameParam, namespaceParam, imageParam, replicasParam, portParam},
+		Template: `deployment:
+  version: apps/v1
+  name: ${name}
+  namespace: ${namespace}
+  replicas: ${replicas}
+  selector:
+    app: ${name}
+  containers:
+  - name: ${name}
+    image: ${image}
+    expose:
+    - ${port}
+`,
+	},
+	{
+		Kind:        "ingress",
+		Description: "HTTP routing from outside the cluster to a service",
+		Params:      []Param{nameParam, namespaceParam, {Name: "host", Description: "host name", Default: "example.com"}, {Name: "service", Description: "service to route to"}, {Name: "service_port", Description: "port of the service", Default: "80", Int: true}},
+		Template: `ingress:
+  version: extensions/v1beta1
+  name: ${name}
+  namespace: ${namespace}
+  rules:
+  - host: ${host}
+    paths:
+    - path: /
+      service: ${service}
+      port: ${service_port}
+`,
+	},
+	{
+		Kind:        "job",
+		Description: "pods that run to completion",
+		Params:      []Param{nameParam, namespaceParam, imageParam},
+		Template: `job:
+  version: batch/v1
+  name: ${name}
+  namespace: ${namespace}
+  restart_policy: never
+  containers:
+  - name: ${name}
+    image: ${image}
+`,
+	},
+	{
+		Kind:        "namespace",
+		Description: "a namespace",
+		Params:      []Param{nameParam},
+		Template: `namespace:
+  version: v1
+  name: ${name}
+`,
+	},
+	{
+		Kind:        "pod",
+		Description: "a single pod",
+		Params:      []Param{nameParam, namespaceParam, imageParam, portParam},
+		Template: `pod:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+  containers:
+  - name: ${name}
+    image: ${image}
+    expose:
+    - ${port}
+`,
+	},
+	{
+		Kind:        "pvc",
+		Description: "a persistent volume claim",
+		Params:      []Param{nameParam, namespaceParam, {Name: "storage", Description: "size of the volume", Default: "1Gi"}},
+		Template: `pvc:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+  access_modes:
+  - rw_once
+  storage: ${storage}
+`,
+	},
+	{
+		Kind:        "secret",
+		Description: "sensitive configuration data for pods",
+		Params:      []Param{nameParam, namespaceParam, {Name: "key", Description: "key of the first entry", Default: "password"}, {Name: "value", Description: "value of the first entry"}},
+		Template: `secret:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+  type: opaque
+  string_data:
+    ${key}: ${value}
+`,
+	},
+	{
+		Kind:        "service",
+		Description: "a stable address for a set of pods",
+		Params:      []Param{nameParam, namespaceParam, {Name: "port", Description: "port of the service", Default: "80", Int: true}, {Name: "target_port", Description: "port of the pods", Default: "8080", Int: true}},
+		Template: `service:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+  selector:
+    app: ${name}
+  ports:
+  - http: ${port}:${target_port}
+`,
+	},
+	{
+		Kind:        "service_account",
+		Description: "an identity for pods",
+		Params:      []Param{nameParam, namespaceParam},
+		Template: `service_account:
+  version: v1
+  name: ${name}
+  namespace: ${namespace}
+`,
+	},
+	{
+		Kind:        "stateful_set",
+		Description: "replicated pods with stable names and storage",
+		Params:      []Param{nameParam, namespaceParam, imageParam, replicasParam, {Name: "storage", Description: "size of each pod's volume", Default: "1Gi"}},
+		Template: `stateful_set:
+  version: apps/v1
+  name: ${name}
+  namespace: ${namespace}
+  service: ${name}
+  replicas: ${replicas}
+  selector:
+    app: ${name}
+  containers:
+  - name: ${name}
+    image: ${image}
+    volume:
+    - mount: /data
+      store: data
+  pvcs:
+  - name: data
+    access_modes:
+    - rw_once
+    storage: ${storage}
+`,
+	},
+}
+
+// Kinds are the short syntax keys that have scaffolds, in order.
+func Kinds() []string {
+	kinds := make([]string, len(scaffolds))
+	for i, s := range scaffolds {
+		kinds[i] = s.Kind
+	}
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// ForKind returns the scaffold of a short syntax key.
+func ForKind(kind string) (*Scaffold, bool) {
+	for i := range scaffolds {
+		if scaffolds[i].Kind == kind {
+			return &scaffolds[i], true
+		}
+	}
+
+	return nil, false
+}
+
+var holeRegexp = regexp.MustCompile(`\$\{([a-z_]+)\}`)
+
+// Fill writes the manifest, with the values of the params, or their defaults. Params without
+// either stay ${param} holes.
+func (s *Scaffold) Fill(values map[string]string) ([]byte, error) {
+	for name := range values {
+		if _, ok := s.Param(name); !ok {
+			return nil, serrors.InvalidValueErrorf(name, "%s doesn't have a param %s (%s)", s.Kind, name, strings.Join(s.ParamNames(), ", "))
+		}
+	}
+
+	replacements := map[string]string{}
+	for _, param := range s.Params {
+		val, ok := values[param.Name]
+		if !ok {
+			if len(param.Default) == 0 {
+				continue
+			}
+			val = param.Default
+		}
+		if param.Int {
+			if _, err := strconv.Atoi(val); err != nil {
+				return nil, serrors.InvalidValueErrorf(val, "expected a whole number for %s", param.Name)
+			}
+			replacements[param.Name] = val
+			continue
+		}
+		replacements[param.Name] = quote(val)
+	}
+
+	return []byte(holeRegexp.ReplaceAllStringFunc(s.Template, func(hole string) string {
+		name := holeRegexp.FindStringSubmatch(hole)[1]
+		if val, ok := replacements[name]; ok {
+			return val
+		}
+		return hole
+	})), nil
+}
+
+// Param returns the param with a name.
+func (s *Scaffold) Param(name string) (Param, bool) {
+	for _, param := range s.Params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+
+	return Param{}, false
+}
+
+// ParamNames are the names of the params, in order.
+func (s *Scaffold) ParamNames() []string {
+	names := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		names[i] = param.Name
+	}
+
+	return names
+}
+
+// quote writes a string as a YAML scalar, with quotes if it would otherwise be read as
+// something else, e.g. a number.
+func quote(val string) string {
+	b, err := yaml.Marshal(val)
+	if err != nil {
+		return strconv.Quote(val)
+	}
+
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// Prompt asks for the value of each param that isn't in values, and adds the answers to values.
+// An empty answer takes the default, or leaves the param a hole if it doesn't have one.
+func (s *Scaffold) Prompt(values map[string]string, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for _, param := range s.Params {
+		if _, ok := values[param.Name]; ok {
+			continue
+		}
+
+		for {
+			if len(param.Default) > 0 {
+				fmt.Fprintf(out, "%s, %s [%s]: ", param.Name, param.Description, param.Default)
+			} else {
+				fmt.Fprintf(out, "%s, %s: ", param.Name, param.Description)
+			}
+			if !scanner.Scan() {
+				return scanner.Err()
+			}
+
+			answer := strings.TrimSpace(scanner.Text())
+			if len(answer) == 0 {
+				break
+			}
+			if _, err := strconv.Atoi(answer); param.Int && err != nil {
+				fmt.Fprintf(out, "expected a whole number\n")
+				continue
+			}
+			values[param.Name] = answer
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+)
+
+func TestFill(t *testing.T) {
+	for _, kind := range Kinds() {
+		s, _ := ForKind(kind)
+		values := map[string]string{}
+		for _, param := range s.Params {
+			if len(param.Default) == 0 {
+				values[param.Name] = "web"
+			}
+		}
+		b, err := s.Fill(values)
+		if err != nil {
+			t.Errorf("%s: %v", kind, err)
+			continue
+		}
+		if strings.Contains(string(b), "${") {
+			t.Errorf("%s: unfilled holes:\n%s", kind, b)
+		}
+
+		objs, err := parser.ParseStreams([]io.ReadCloser{ioutil.NopCloser(bytes.NewReader(b))})
+		if err != nil {
+			t.Errorf("%s: %v\n%s", kind, err, b)
+			continue
+		}
+		if _, err := client.ConvertKokiMaps(objs); err != nil {
+			t.Errorf("%s: %v\n%s", kind, err, b)
+		}
+	}
+}
+
+func TestFillHoles(t *testing.T) {
+	s, ok := ForKind("deployment")
+	if !ok {
+		t.Fatal("expected a deployment scaffold")
+	}
+
+	b, err := s.Fill(map[string]string{"name": "web", "replicas": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"name: web", "replicas: 3", "image: ${image}", "- 8080"} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected %q in\n%s", expected, b)
+		}
+	}
+
+	// Values that YAML would read as something else are quoted.
+	b, err = s.Fill(map[string]string{"name": "true", "image": "nginx:1.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `name: "true"`) {
+		t.Errorf("expected a quoted name in\n%s", b)
+	}
+
+	for _, values := range []map[string]string{
+		{"replicas": "three"},
+		{"tag": "1.10"},
+	} {
+		if _, err := s.Fill(values); err == nil {
+			t.Errorf("%v: expected an error", values)
+		}
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	s, _ := ForKind("deployment")
+	values := map[string]string{"name": "web"}
+	// namespace takes its default, image is answered, replicas is answered again after a mistake,
+	// and the input ends before port.
+	in := strings.NewReader("\nnginx\nthree\n3\n")
+	out := &bytes.Buffer{}
+	err := s.Prompt(values, in, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"name": "web", "image": "nginx", "replicas": "3"}
+	if len(values) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+	for key, val := range expected {
+		if values[key] != val {
+			t.Errorf("expected %v, got %v", expected, values)
+		}
+	}
+	if strings.Contains(out.String(), "name,") || !strings.Contains(out.String(), "replicas, number of pods [1]: ") ||
+		!strings.Contains(out.String(), "expected a whole number") {
+		t.Errorf("unexpected prompts:\n%s", out.String())
+	}
+}
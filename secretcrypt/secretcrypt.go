@@ -0,0 +1,163 @@
+package secretcrypt
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/koki/json"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Secretcrypt keeps the data of Kubernetes Secrets encrypted, so that the output of short can
+be committed, and decrypts it on the way back to short syntax. It runs the tools of two
+common workflows, which have to be installed:
+
+	SOPS (sops): Encrypt encrypts the data and stringData of a Secret in place, and adds
+	sops' metadata. The keys come from sops' usual configuration, e.g. .sops.yaml or
+	SOPS_AGE_RECIPIENTS. Decrypt reverses it.
+
+	Sealed Secrets (kubeseal): Seal turns a Secret into a SealedSecret, which only the
+	controller in the cluster can decrypt, using the controller's public certificate.
+	Unseal turns it back, using the controller's private key.
+
+Documents are passed to the tools as JSON.
+
+*/
+
+// Commands that are run. They're variables so that tests can replace them.
+var (
+	SOPSCommand     = "sops"
+	KubesealCommand = "kubeseal"
+)
+
+// EncryptedRegex matches the fields of a Secret that SOPS encrypts.
+const EncryptedRegex = "^(data|stringData)$"
+
+// IsSecret is true for a Kubernetes Secret.
+func IsSecret(obj map[string]interface{}) bool {
+	return obj["kind"] == "Secret" && obj["apiVersion"] == "v1"
+}
+
+// IsEncrypted is true for a document that SOPS encrypted.
+func IsEncrypted(obj map[string]interface{}) bool {
+	_, ok := obj["sops"].(map[string]interface{})
+	return ok
+}
+
+// IsSealed is true for a SealedSecret.
+func IsSealed(obj map[string]interface{}) bool {
+	apiVersion, _ := obj["apiVersion"].(string)
+	return obj["kind"] == "SealedSecret" && strings.HasPrefix(apiVersion, "bitnami.com/")
+}
+
+// Encrypt encrypts the data of a Secret with SOPS.
+func Encrypt(obj map[string]interface{}) (map[string]interface{}, error) {
+	return run(obj, SOPSCommand, "--encrypt", "--input-type", "json", "--output-type", "json", "--encrypted-regex", EncryptedRegex, "/dev/stdin")
+}
+
+// Decrypt decrypts a document that SOPS encrypted.
+func Decrypt(obj map[string]interface{}) (map[string]interface{}, error) {
+	return run(obj, SOPSCommand, "--decrypt", "--input-type", "json", "--output-type", "json", "/dev/stdin")
+}
+
+// Seal turns a Secret into a SealedSecret with the certificate of the Sealed Secrets controller.
+func Seal(obj map[string]interface{}, cert string) (map[string]interface{}, error) {
+	return run(obj, KubesealCommand, "--cert", cert, "--format", "json")
+}
+
+// Unseal turns a SealedSecret back into a Secret with the private key of the Sealed Secrets controller.
+func Unseal(obj map[string]interface{}, privateKey string) (map[string]interface{}, error) {
+	return run(obj, KubesealCommand, "--recovery-unseal", "--recovery-private-key", privateKey, "--format", "json")
+}
+
+// Options choose how Secrets are kept encrypted.
+type Options struct {
+	// SOPS encrypts Secrets with SOPS, and decrypts documents that SOPS encrypted.
+	SOPS bool
+	// SealCert is the certificate to seal Secrets with, if they're sealed.
+	SealCert string
+	// UnsealKey is the private key to unseal SealedSecrets with, if they're unsealed.
+	UnsealKey string
+}
+
+// EncryptObjs encrypts or seals the Secrets among converted Kubernetes objects, and leaves the other objects as they are.
+func (o Options) EncryptObjs(objs []interface{}) ([]interface{}, error) {
+	if !o.SOPS && len(o.SealCert) == 0 {
+		return objs, nil
+	}
+
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		results[i] = obj
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !IsSecret(objMap) {
+			continue
+		}
+
+		if o.SOPS {
+			results[i], err = Encrypt(objMap)
+		} else {
+			results[i], err = Seal(objMap, o.SealCert)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// Decrypt decrypts the documents that SOPS encrypted, and unseals SealedSecrets, before they're converted to short syntax.
+func (o Options) Decrypt(objs []map[string]interface{}) ([]map[string]interface{}, error) {
+	results := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		var err error
+		results[i] = obj
+		switch {
+		case IsEncrypted(obj) && o.SOPS:
+			results[i], err = Decrypt(obj)
+		case IsEncrypted(obj):
+			err = serrors.InvalidValueErrorf(obj, "the document is encrypted with SOPS (decrypt it with --sops)")
+		case IsSealed(obj) && len(o.UnsealKey) > 0:
+			results[i], err = Unseal(obj, o.UnsealKey)
+		case IsSealed(obj):
+			err = serrors.InvalidValueErrorf(obj, "a SealedSecret can only be converted after it's unsealed (with --unseal-key)")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func run(obj map[string]interface{}, command string, args ...string) (map[string]interface{}, error) {
+	in, err := json.Marshal(obj)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, obj, "serializing document for %s", command)
+	}
+
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "running %s", command)
+	}
+
+	result := map[string]interface{}{}
+	err = json.Unmarshal(out, &result)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "%s: expected a JSON document", command)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package secretcrypt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeCommand writes a script that ignores its input and writes a document with its arguments.
+func fakeCommand(t *testing.T, dir, name, doc string) string {
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\ncat > /dev/null\nprintf '" + doc + "' \"$*\"\n"
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestEncryptObjs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(sops, kubeseal string) { SOPSCommand, KubesealCommand = sops, kubeseal }(SOPSCommand, KubesealCommand)
+	SOPSCommand = fakeCommand(t, dir, "sops", `{"apiVersion":"v1","kind":"Secret","data":{"a":"ENC"},"sops":{"args":"%s"}}`)
+	KubesealCommand = fakeCommand(t, dir, "kubeseal", `{"apiVersion":"bitnami.com/v1alpha1","kind":"SealedSecret","spec":{"args":"%s"}}`)
+
+	secret := map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "data": map[string]interface{}{"a": "Yg=="}}
+	configMap := map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}
+
+	objs, err := Options{SOPS: true}.EncryptObjs([]interface{}{secret, configMap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"data":       map[string]interface{}{"a": "ENC"},
+		"sops":       map[string]interface{}{"args": "--encrypt --input-type json --output-type json --encrypted-regex ^(data|stringData)$ /dev/stdin"},
+	}
+	if !reflect.DeepEqual(objs[0], expected) || !reflect.DeepEqual(objs[1], configMap) {
+		t.Errorf("unexpected objects %#v", objs)
+	}
+	if !IsEncrypted(objs[0].(map[string]interface{})) {
+		t.Error("expected the Secret to be encrypted")
+	}
+
+	objs, err = Options{SealCert: "cert.pem"}.EncryptObjs([]interface{}{secret, configMap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := objs[0].(map[string]interface{})
+	if !IsSealed(sealed) || sealed["spec"].(map[string]interface{})["args"] != "--cert cert.pem --format json" {
+		t.Errorf("unexpected SealedSecret %#v", sealed)
+	}
+
+	objs, err = Options{}.EncryptObjs([]interface{}{secret})
+	if err != nil || !reflect.DeepEqual(objs[0], secret) {
+		t.Errorf("expected the Secret to be left alone, got %#v (%v)", objs, err)
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(sops, kubeseal string) { SOPSCommand, KubesealCommand = sops, kubeseal }(SOPSCommand, KubesealCommand)
+	SOPSCommand = fakeCommand(t, dir, "sops", `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"%s"}}`)
+	KubesealCommand = fakeCommand(t, dir, "kubeseal", `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"%s"}}`)
+
+	encrypted := map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "sops": map[string]interface{}{}}
+	sealed := map[string]interface{}{"apiVersion": "bitnami.com/v1alpha1", "kind": "SealedSecret"}
+	configMap := map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}
+
+	objs, err := Options{SOPS: true, UnsealKey: "key.pem"}.Decrypt([]map[string]interface{}{encrypted, sealed, configMap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []interface{}{
+		objs[0]["metadata"].(map[string]interface{})["name"],
+		objs[1]["metadata"].(map[string]interface{})["name"],
+	}
+	if !reflect.DeepEqual(names, []interface{}{
+		"--decrypt --input-type json --output-type json /dev/stdin",
+		"--recovery-unseal --recovery-private-key key.pem --format json",
+	}) || !reflect.DeepEqual(objs[2], configMap) {
+		t.Errorf("unexpected objects %#v", objs)
+	}
+
+	for _, obj := range []map[string]interface{}{encrypted, sealed} {
+		if _, err := (Options{}).Decrypt([]map[string]interface{}{obj}); err == nil {
+			t.Errorf("%v: expected an error", obj)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/koki/json"
+	"github.com/koki/short/client"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+A static HTML site for browsing a set of manifests:
+
+	index.html                      namespaces
+	ns/<namespace>.html             objects in a namespace
+	obj/<namespace>/<kind>-<name>.html
+	                                short and Kubernetes syntax side by side, and
+	                                the objects it references or is referenced by
+
+*/
+
+// Object is a manifest in both syntaxes.
+type Object struct {
+	ID
+	Short string
+	Kube  string
+
+	Refs         []Link
+	ReferencedBy []Link
+}
+
+// ID identifies an object by its short kind (e.g. "config_map"), namespace and name.
+type ID struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Link is a reference to another object. Path is empty if the object isn't part of the site.
+type Link struct {
+	ID
+	Path string
+}
+
+type Site struct {
+	Objects []*Object
+}
+
+// Build converts each object to both syntaxes and resolves references between them.
+func Build(objs []map[string]interface{}) (*Site, error) {
+	site := &Site{}
+	refs := map[*Object][]ID{}
+	for _, obj := range objs {
+		kokiObjs, err := client.ConvertEitherMapsToKoki([]map[string]interface{}{obj})
+		if err != nil {
+			return nil, err
+		}
+		kokiObj := kokiObjs[0]
+
+		kubeObjs, err := client.ConvertKokiMaps(kokiObjs)
+		if err != nil {
+			return nil, err
+		}
+
+		object := &Object{ID: ObjectID(kokiObj)}
+		shortYAML, err := yaml.Marshal(kokiObj)
+		if err != nil {
+			return nil, serrors.InvalidValueErrorf(kokiObj, "couldn't serialize as yaml")
+		}
+		object.Short = string(shortYAML)
+
+		kubeYAML, err := yaml.Marshal(kubeObjs[0])
+		if err != nil {
+			return nil, serrors.InvalidValueErrorf(kubeObjs[0], "couldn't serialize as yaml")
+		}
+		object.Kube = string(kubeYAML)
+
+		refs[object], err = FindRefs(object.Namespace, kubeObjs[0])
+		if err != nil {
+			return nil, err
+		}
+
+		site.Objects = append(site.Objects, object)
+	}
+
+	sort.Slice(site.Objects, func(i, j int) bool {
+		return site.Objects[i].ID.Less(site.Objects[j].ID)
+	})
+
+	byID := map[ID]*Object{}
+	for _, object := range site.Objects {
+		byID[object.ID] = object
+	}
+	for _, object := range site.Objects {
+		for _, id := range refs[object] {
+			link := Link{ID: id}
+			if target, ok := byID[id]; ok {
+				link.Path = target.Path()
+				target.ReferencedBy = append(target.ReferencedBy, Link{ID: object.ID, Path: object.Path()})
+			}
+			object.Refs = append(object.Refs, link)
+		}
+	}
+
+	return site, nil
+}
+
+// ObjectID identifies an object in short syntax.
+func ObjectID(kokiObj map[string]interface{}) ID {
+	id := ID{}
+	for kind, body := range kokiObj {
+		id.Kind = kind
+		if bodyMap, ok := body.(map[string]interface{}); ok {
+			id.Name, _ = bodyMap["name"].(string)
+			id.Namespace, _ = bodyMap["namespace"].(string)
+		}
+	}
+
+	return id
+}
+
+// Less orders IDs by namespace, then kind, then name.
+func (id ID) Less(other ID) bool {
+	if id.Namespace != other.Namespace {
+		return id.Namespace < other.Namespace
+	}
+	if id.Kind != other.Kind {
+		return id.Kind < other.Kind
+	}
+	return id.Name < other.Name
+}
+
+var unsafePathCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+func pathSegment(s string) string {
+	if len(s) == 0 {
+		return "_"
+	}
+
+	return unsafePathCharsRegexp.ReplaceAllString(s, "_")
+}
+
+// Path is the location of the object's page, relative to the root of the site.
+func (id ID) Path() string {
+	return path.Join("obj", pathSegment(id.Namespace), pathSegment(id.Kind)+"-"+pathSegment(id.Name)+".html")
+}
+
+// refFields maps a Kubernetes field to the kind it references and the field that holds the name.
+var refFields = map[string]struct{ kind, nameField string }{
+	"configMap":             {"config_map", "name"},
+	"configMapRef":          {"config_map", "name"},
+	"configMapKeyRef":       {"config_map", "name"},
+	"secret":                {"secret", "secretName"},
+	"secretRef":             {"secret", "name"},
+	"secretKeyRef":          {"secret", "name"},
+	"persistentVolumeClaim": {"pvc", "claimName"},
+	"imagePullSecrets":      {"secret", "name"},
+	"secrets":               {"secret", "name"},
+	"tls":                   {"secret", "secretName"},
+}
+
+// Ref is a reference from a Kubernetes object to another object.
+type Ref struct {
+	ID
+	// Path is the location of the reference, e.g. "$.spec.volumes.0.secret".
+	Path string
+	// Optional is true if the object works without the referenced object, e.g. an optional secretKeyRef.
+	Optional bool
+}
+
+// FindRefs finds the ConfigMaps, Secrets, PVCs and ServiceAccounts used by a Kubernetes object.
+func FindRefs(namespace string, kubeObj interface{}) ([]ID, error) {
+	refs, err := FindRefPaths(namespace, kubeObj)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []ID{}
+	seen := map[ID]bool{}
+	for _, ref := range refs {
+		if !seen[ref.ID] {
+			seen[ref.ID] = true
+			ids = append(ids, ref.ID)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Less(ids[j])
+	})
+
+	return ids, nil
+}
+
+// FindRefPaths finds each reference to a ConfigMap, Secret, PVC or ServiceAccount in a Kubernetes object, in path order.
+func FindRefPaths(namespace string, kubeObj interface{}) ([]Ref, error) {
+	b, err := json.Marshal(kubeObj)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, kubeObj, "marshalling to JSON")
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(b, &generic)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(b), "converting to dictionary")
+	}
+
+	refs := []Ref{}
+	addRef := func(kind, namespace, name, path string, optional bool) {
+		if len(name) > 0 {
+			refs = append(refs, Ref{ID: ID{Kind: kind, Namespace: namespace, Name: name}, Path: path, Optional: optional})
+		}
+	}
+
+	var walk func(path string, obj interface{})
+	walk = func(path string, obj interface{}) {
+		switch obj := obj.(type) {
+		case map[string]interface{}:
+			keys := []string{}
+			for key := range obj {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				val := obj[key]
+				keyPath := path + "." + key
+				if key == "serviceAccountName" {
+					name, _ := val.(string)
+					addRef("service_account", namespace, name, keyPath, false)
+				}
+				if key == "subjects" {
+					// RoleBinding subjects may be in another namespace.
+					for i, subject := range asList(val) {
+						if subjectMap, ok := subject.(map[string]interface{}); ok && subjectMap["kind"] == "ServiceAccount" {
+							name, _ := subjectMap["name"].(string)
+							subjectNamespace, _ := subjectMap["namespace"].(string)
+							if len(subjectNamespace) == 0 {
+								subjectNamespace = namespace
+							}
+							addRef("service_account", subjectNamespace, name, fmt.Sprintf("%s.%d", keyPath, i), false)
+						}
+					}
+				}
+				if field, ok := refFields[key]; ok {
+					_, isList := val.([]interface{})
+					for i, target := range asList(val) {
+						if targetMap, ok := target.(map[string]interface{}); ok {
+							name, _ := targetMap[field.nameField].(string)
+							optional, _ := targetMap["optional"].(bool)
+							targetPath := keyPath
+							if isList {
+								targetPath = fmt.Sprintf("%s.%d", keyPath, i)
+							}
+							addRef(field.kind, namespace, name, targetPath, optional)
+						}
+					}
+				}
+				walk(keyPath, val)
+			}
+		case []interface{}:
+			for i, val := range obj {
+				walk(fmt.Sprintf("%s.%d", path, i), val)
+			}
+		}
+	}
+	walk("$", generic)
+
+	return refs, nil
+}
+
+func asList(obj interface{}) []interface{} {
+	if list, ok := obj.([]interface{}); ok {
+		return list
+	}
+
+	return []interface{}{obj}
+}
+
+// Namespaces returns the objects grouped by namespace.
+func (s *Site) Namespaces() map[string][]*Object {
+	namespaces := map[string][]*Object{}
+	for _, object := range s.Objects {
+		namespaces[object.Namespace] = append(namespaces[object.Namespace], object)
+	}
+
+	return namespaces
+}
+
+// NamespacePath is the location of a namespace's page, relative to the root of the site.
+func NamespacePath(namespace string) string {
+	return path.Join("ns", pathSegment(namespace)+".html")
+}
+
+// Write renders the site into dir.
+func (s *Site) Write(dir string) error {
+	namespaces := s.Namespaces()
+	names := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+
+	err := writePage(dir, "index.html", indexTemplate, map[string]interface{}{
+		"Namespaces": names,
+		"Counts":     namespaces,
+	})
+	if err != nil {
+		return err
+	}
+
+	for namespace, objects := range namespaces {
+		err = writePage(dir, NamespacePath(namespace), namespaceTemplate, map[string]interface{}{
+			"Namespace": namespace,
+			"Objects":   objects,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, object := range s.Objects {
+		err = writePage(dir, object.Path(), objectTemplate, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writePage(dir, pagePath string, tmpl *template.Template, data interface{}) error {
+	filename := filepath.Join(dir, filepath.FromSlash(pagePath))
+	err := os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "creating directory for %s", filename)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "creating %s", filename)
+	}
+	defer f.Close()
+
+	err = tmpl.Execute(f, map[string]interface{}{
+		"Root": rootPath(pagePath),
+		"Data": data,
+	})
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "rendering %s", filename)
+	}
+
+	return nil
+}
+
+// rootPath is the relative path from a page back to the root of the site.
+func rootPath(pagePath string) string {
+	root := ""
+	for dir := path.Dir(pagePath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		root += "../"
+	}
+
+	return root
+}
@@ -0,0 +1,86 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var objs0 = []string{`
+config_map:
+  name: settings
+  namespace: prod
+  data:
+    a: b
+`, `
+deployment:
+  name: web
+  namespace: prod
+  containers:
+  - name: web
+    image: nginx
+    env:
+    - from: config:settings:a
+      key: A
+  volumes:
+    creds: secret:creds
+`}
+
+func TestBuild(t *testing.T) {
+	objs := []map[string]interface{}{}
+	for _, s := range objs0 {
+		obj := map[string]interface{}{}
+		err := yaml.Unmarshal([]byte(s), &obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objs = append(objs, obj)
+	}
+
+	site, err := Build(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(site.Objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(site.Objects))
+	}
+
+	configMap, deployment := site.Objects[0], site.Objects[1]
+	expectedRefs := []Link{
+		{ID: configMap.ID, Path: "obj/prod/config_map-settings.html"},
+		{ID: ID{Kind: "secret", Namespace: "prod", Name: "creds"}},
+	}
+	if !reflect.DeepEqual(deployment.Refs, expectedRefs) {
+		t.Error(pretty.Diff(deployment.Refs, expectedRefs))
+	}
+	expectedReferencedBy := []Link{{ID: deployment.ID, Path: "obj/prod/deployment-web.html"}}
+	if !reflect.DeepEqual(configMap.ReferencedBy, expectedReferencedBy) {
+		t.Error(pretty.Diff(configMap.ReferencedBy, expectedReferencedBy))
+	}
+	if !strings.Contains(deployment.Kube, "kind: Deployment") {
+		t.Errorf("expected Kubernetes syntax, got:\n%s", deployment.Kube)
+	}
+
+	dir, err := ioutil.TempDir("", "short-site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = site.Write(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []string{"index.html", "ns/prod.html", deployment.Path(), configMap.Path()} {
+		if _, err := os.Stat(filepath.Join(dir, page)); err != nil {
+			t.Error(err)
+		}
+	}
+}
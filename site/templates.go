@@ -0,0 +1,82 @@
+package site
+
+import (
+	"html/template"
+)
+
+const style = `<style>
+body { font-family: sans-serif; margin: 2em; }
+.side-by-side { display: flex; gap: 1em; }
+.side-by-side div { flex: 1; min-width: 0; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+</style>`
+
+var funcs = template.FuncMap{
+	"namespacePath": NamespacePath,
+	"displayNamespace": func(namespace string) string {
+		if len(namespace) == 0 {
+			return "(no namespace)"
+		}
+		return namespace
+	},
+}
+
+var indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Manifests</title>` + style + `</head>
+<body>
+<h1>Namespaces</h1>
+<ul>
+{{- range .Data.Namespaces}}
+<li><a href="{{$.Root}}{{namespacePath .}}">{{displayNamespace .}}</a> ({{len (index $.Data.Counts .)}} objects)</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+var namespaceTemplate = template.Must(template.New("namespace").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{displayNamespace .Data.Namespace}}</title>` + style + `</head>
+<body>
+<p><a href="{{.Root}}index.html">Namespaces</a></p>
+<h1>{{displayNamespace .Data.Namespace}}</h1>
+<table>
+<tr><th>Kind</th><th>Name</th></tr>
+{{- range .Data.Objects}}
+<tr><td>{{.Kind}}</td><td><a href="{{$.Root}}{{.Path}}">{{.Name}}</a></td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var objectTemplate = template.Must(template.New("object").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Data.Kind}} {{.Data.Name}}</title>` + style + `</head>
+<body>
+<p><a href="{{.Root}}index.html">Namespaces</a> / <a href="{{.Root}}{{namespacePath .Data.Namespace}}">{{displayNamespace .Data.Namespace}}</a></p>
+<h1>{{.Data.Kind}} {{.Data.Name}}</h1>
+{{- if .Data.Refs}}
+<h2>References</h2>
+<ul>
+{{- range .Data.Refs}}
+<li>{{.Kind}} {{if .Path}}<a href="{{$.Root}}{{.Path}}">{{.Name}}</a>{{else}}{{.Name}} (not found){{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Data.ReferencedBy}}
+<h2>Referenced by</h2>
+<ul>
+{{- range .Data.ReferencedBy}}
+<li>{{.Kind}} <a href="{{$.Root}}{{.Path}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+<div class="side-by-side">
+<div><h2>Short</h2><pre>{{.Data.Short}}</pre></div>
+<div><h2>Kubernetes</h2><pre>{{.Data.Kube}}</pre></div>
+</div>
+</body>
+</html>
+`))
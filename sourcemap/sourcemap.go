@@ -0,0 +1,439 @@
+package sourcemap
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/short/comments"
+	"github.com/koki/short/util/objutil"
+)
+
+/*
+
+A source map relates each line of Kubernetes output to the short field and line it was
+converted from, so editors can jump between the two and errors about the output (e.g. from
+kubectl) can point at the short manifest.
+
+Converters don't record where fields come from, so it's found by changing one short field
+at a time, converting again, and seeing which Kubernetes fields change. A Kubernetes field
+that several short fields change belongs to the one that changes the fewest fields. Fields
+that no short field changes, e.g. kind, belong to the short field that their neighbors come
+from, or to the whole document.
+
+Paths are keys and list indices joined with ".", as in comments.LinePaths, e.g.
+"spec.template.spec.containers.0.image". Lines are counted from 1.
+
+*/
+
+// Version is the version of the source map format.
+const Version = 1
+
+// SourceMap is written as JSON.
+type SourceMap struct {
+	Version  int       `json:"version"`
+	Mappings []Mapping `json:"mappings"`
+}
+
+// Mapping relates a line of the output to the short field it comes from.
+type Mapping struct {
+	// Document is the index of the output document, from 0.
+	Document int `json:"document"`
+	// Path is the field on the line.
+	Path string `json:"path"`
+	// Line is the line in the output.
+	Line int `json:"line"`
+
+	// Source is the short manifest.
+	Source string `json:"source"`
+	// SourceDocument is the index of the document in the short manifest, from 0.
+	SourceDocument int `json:"source_document"`
+	// SourcePath is the short field.
+	SourcePath string `json:"source_path"`
+	// SourceLine is the line of the short field in the manifest, or 0 if it isn't known.
+	SourceLine int `json:"source_line,omitempty"`
+}
+
+// Source is the short document that an output document was converted from.
+type Source struct {
+	File     string
+	Document int
+	// Fields maps each Kubernetes path to a short path, as returned by Fields.
+	Fields map[string]string
+	// Lines maps short paths to their lines in File, as returned by DocumentLines. It's nil if they aren't known.
+	Lines map[string]int
+}
+
+// Build makes the source map of YAML output. sources has the source of each output document,
+// or nil for documents that don't have one.
+func Build(output []byte, sources []*Source) *SourceMap {
+	m := &SourceMap{Version: Version, Mappings: []Mapping{}}
+	paths := comments.LinePaths(output)
+	document := 0
+	for i, line := range strings.Split(string(output), "\n") {
+		if isSeparator(line) {
+			if i > 0 {
+				document++
+			}
+			continue
+		}
+		if i >= len(paths) || len(paths[i]) == 0 || document >= len(sources) || sources[document] == nil {
+			continue
+		}
+
+		source := sources[document]
+		sourcePath, ok := source.Fields[paths[i]]
+		if !ok {
+			continue
+		}
+		m.Mappings = append(m.Mappings, Mapping{
+			Document:       document,
+			Path:           paths[i],
+			Line:           i + 1,
+			Source:         source.File,
+			SourceDocument: source.Document,
+			SourcePath:     sourcePath,
+			SourceLine:     lineOf(source.Lines, sourcePath),
+		})
+	}
+
+	return m
+}
+
+func isSeparator(line string) bool {
+	return line == "---" || strings.HasPrefix(line, "--- ")
+}
+
+// lineOf returns the line of a path, or of its nearest parent that has one.
+func lineOf(lines map[string]int, path string) int {
+	for segments := strings.Split(path, "."); len(segments) > 0; segments = segments[:len(segments)-1] {
+		if line, ok := lines[strings.Join(segments, ".")]; ok {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// DocumentLines returns the line of each path in each document of a YAML file that has content,
+// in the same order as comments.SplitDocuments. A path's line is where it starts.
+func DocumentLines(data []byte) []map[string]int {
+	docs := []map[string]int{}
+	doc := map[string]int{}
+	hasContent := false
+	lines := strings.Split(string(data), "\n")
+	paths := comments.LinePaths(data)
+	for i, line := range lines {
+		if isSeparator(line) {
+			if hasContent {
+				docs = append(docs, doc)
+			}
+			doc = map[string]int{}
+			hasContent = false
+			continue
+		}
+		content := strings.TrimSpace(line)
+		if len(content) > 0 && !strings.HasPrefix(content, "#") {
+			hasContent = true
+		}
+		if i < len(paths) && len(paths[i]) > 0 {
+			if _, ok := doc[paths[i]]; !ok {
+				doc[paths[i]] = i + 1
+			}
+		}
+	}
+	if hasContent {
+		docs = append(docs, doc)
+	}
+
+	return docs
+}
+
+// Lookup finds the mapping of a field of an output document, or of its nearest parent that has one.
+// The path can also be written with brackets, as kubectl does, e.g. "spec.containers[0].image".
+func (m *SourceMap) Lookup(document int, path string) (Mapping, bool) {
+	byPath := map[string]Mapping{}
+	for _, mapping := range m.Mappings {
+		if mapping.Document == document {
+			byPath[mapping.Path] = mapping
+		}
+	}
+
+	path = strings.TrimPrefix(bracketRegexp.ReplaceAllString(path, ".$1"), ".")
+	for segments := strings.Split(path, "."); len(segments) > 0; segments = segments[:len(segments)-1] {
+		if mapping, ok := byPath[strings.Join(segments, ".")]; ok {
+			return mapping, true
+		}
+	}
+
+	return Mapping{}, false
+}
+
+var bracketRegexp = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// Converter converts a short document to a Kubernetes object.
+type Converter func(obj map[string]interface{}) (map[string]interface{}, error)
+
+// Fields maps the path of each field of a short document's Kubernetes object to the path of the short
+// field it comes from.
+func Fields(obj map[string]interface{}, convert Converter) (map[string]string, error) {
+	base, err := convertCopy(obj, convert)
+	if err != nil {
+		return nil, err
+	}
+	baseLeaves := leaves(nil, base, map[string]interface{}{})
+
+	// Each Kubernetes leaf belongs to the short field whose change changes the fewest leaves.
+	owners := map[string]string{}
+	ownerCounts := map[string]int{}
+	for _, shortPath := range leafPaths(nil, obj) {
+		changed, err := changedBy(obj, shortPath, baseLeaves, convert)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range changed {
+			if count, ok := ownerCounts[path]; !ok || len(changed) < count {
+				owners[path] = strings.Join(shortPath, ".")
+				ownerCounts[path] = len(changed)
+			}
+		}
+	}
+
+	// Parents belong to the common parent of their children's short fields.
+	fields := map[string]string{}
+	for path := range baseLeaves {
+		segments := strings.Split(path, ".")
+		for i := 1; i < len(segments); i++ {
+			parent := strings.Join(segments[:i], ".")
+			if _, ok := fields[parent]; !ok {
+				fields[parent] = ""
+			}
+			if owner, ok := owners[path]; ok {
+				fields[parent] = commonPrefix(fields[parent], owner)
+			}
+		}
+	}
+	for path, owner := range owners {
+		fields[path] = owner
+	}
+
+	// Fields left without one belong to their nearest parent's, or to the document.
+	root := rootKey(obj)
+	for path := range baseLeaves {
+		if _, ok := fields[path]; !ok {
+			fields[path] = ""
+		}
+	}
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if fields[path] == rootOwner {
+			fields[path] = root
+		}
+		if len(fields[path]) > 0 {
+			continue
+		}
+		fields[path] = root
+		segments := strings.Split(path, ".")
+		for i := len(segments) - 1; i > 0; i-- {
+			if owner := fields[strings.Join(segments[:i], ".")]; len(owner) > 0 && owner != rootOwner {
+				fields[path] = owner
+				break
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// rootOwner marks the common parent of children that come from different short fields.
+const rootOwner = "\x00"
+
+func commonPrefix(a, b string) string {
+	if len(a) == 0 {
+		return b
+	}
+	if a == rootOwner || a == b {
+		return a
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	i := 0
+	for i < len(as) && i < len(bs) && as[i] == bs[i] {
+		i++
+	}
+	// A common prefix of just the document key says nothing more than the document does.
+	if i <= 1 {
+		return rootOwner
+	}
+
+	return strings.Join(as[:i], ".")
+}
+
+func rootKey(obj map[string]interface{}) string {
+	keys := []string{}
+	for key := range obj {
+		if !strings.HasPrefix(key, "_") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return ""
+	}
+
+	return keys[0]
+}
+
+// changedBy returns the Kubernetes leaves that change when the short field at path changes,
+// or nothing if no change to the field converts.
+func changedBy(obj map[string]interface{}, path []string, baseLeaves map[string]interface{}, convert Converter) ([]string, error) {
+	for _, change := range []func(parent interface{}, key string) bool{mutate, remove} {
+		changedObj, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := objutil.AtPathIn(changedObj, path[:len(path)-1])
+		if err != nil || !change(parent, path[len(path)-1]) {
+			continue
+		}
+
+		kube, err := convert(changedObj)
+		if err != nil {
+			continue
+		}
+		kubeLeaves := leaves(nil, kube, map[string]interface{}{})
+		changed := []string{}
+		for leafPath, val := range baseLeaves {
+			if kubeVal, ok := kubeLeaves[leafPath]; !ok || !reflect.DeepEqual(val, kubeVal) {
+				changed = append(changed, leafPath)
+			}
+		}
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			return changed, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// mutate changes a scalar value to another one of the same type.
+func mutate(parent interface{}, key string) bool {
+	change := func(val interface{}) (interface{}, bool) {
+		switch val := val.(type) {
+		case string:
+			return val + "1", true
+		case bool:
+			return !val, true
+		case float64:
+			return val + 1, true
+		case int64:
+			return val + 1, true
+		case int:
+			return val + 1, true
+		}
+		return nil, false
+	}
+
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		if val, ok := change(parent[key]); ok {
+			parent[key] = val
+			return true
+		}
+	case []interface{}:
+		if i := listIndex(parent, key); i >= 0 {
+			if val, ok := change(parent[i]); ok {
+				parent[i] = val
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// remove deletes a field. List items aren't removed, since the items after them would move.
+func remove(parent interface{}, key string) bool {
+	if parent, ok := parent.(map[string]interface{}); ok {
+		delete(parent, key)
+		return true
+	}
+
+	return false
+}
+
+func listIndex(list []interface{}, key string) int {
+	for i := range list {
+		if strconv.Itoa(i) == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func convertCopy(obj map[string]interface{}, convert Converter) (map[string]interface{}, error) {
+	copied, err := objutil.ToDictionary(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(copied)
+}
+
+// leaves adds the scalars, empty dictionaries and empty lists of obj to result, by path.
+func leaves(path []string, obj interface{}, result map[string]interface{}) map[string]interface{} {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		if len(obj) > 0 {
+			for key, val := range obj {
+				leaves(append(append([]string{}, path...), key), val, result)
+			}
+			return result
+		}
+	case []interface{}:
+		if len(obj) > 0 {
+			for i, val := range obj {
+				leaves(append(append([]string{}, path...), strconv.Itoa(i)), val, result)
+			}
+			return result
+		}
+	}
+	if len(path) > 0 {
+		result[strings.Join(path, ".")] = obj
+	}
+
+	return result
+}
+
+// leafPaths returns the path of each scalar in obj, in a stable order.
+func leafPaths(path []string, obj interface{}) [][]string {
+	result := [][]string{}
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result = append(result, leafPaths(append(append([]string{}, path...), key), obj[key])...)
+		}
+	case []interface{}:
+		for i, val := range obj {
+			result = append(result, leafPaths(append(append([]string{}, path...), strconv.Itoa(i)), val)...)
+		}
+	case nil:
+	default:
+		result = append(result, path)
+	}
+
+	return result
+}
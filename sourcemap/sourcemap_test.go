@@ -0,0 +1,112 @@
+package sourcemap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/util/objutil"
+)
+
+func convertKoki(obj map[string]interface{}) (map[string]interface{}, error) {
+	kubeObjs, err := client.ConvertKokiMaps([]map[string]interface{}{obj})
+	if err != nil {
+		return nil, err
+	}
+
+	return objutil.ToDictionary(kubeObjs[0])
+}
+
+func TestFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"service": map[string]interface{}{
+			"name":     "web",
+			"selector": map[string]interface{}{"app": "web"},
+			"port":     "80:8080",
+		},
+	}
+
+	fields, err := Fields(obj, convertKoki)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"apiVersion":              "service",
+		"kind":                    "service",
+		"metadata":                "service.name",
+		"metadata.name":           "service.name",
+		"spec":                    "service",
+		"spec.ports":              "service.port",
+		"spec.ports.0":            "service.port",
+		"spec.ports.0.port":       "service.port",
+		"spec.ports.0.protocol":   "service.port",
+		"spec.ports.0.targetPort": "service.port",
+		"spec.selector":           "service.selector.app",
+		"spec.selector.app":       "service.selector.app",
+		"spec.type":               "service",
+	}
+	for path, shortPath := range expected {
+		if fields[path] != shortPath {
+			t.Errorf("%s: expected %s, got %s", path, shortPath, fields[path])
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	output := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)
+	source := []byte(`# The web frontend.
+service:
+  name: web
+  port: 80:8080
+`)
+
+	lines := DocumentLines(source)
+	if !reflect.DeepEqual(lines, []map[string]int{{"service": 2, "service.name": 3, "service.port": 4}}) {
+		t.Fatalf("unexpected lines %s", pretty.Sprint(lines))
+	}
+
+	m := Build(output, []*Source{
+		{
+			File:   "web.short.yaml",
+			Fields: map[string]string{"metadata.name": "service.name", "spec.ports.0": "service.port", "spec.ports.0.targetPort": "service.port"},
+			Lines:  lines[0],
+		},
+		nil,
+	})
+	expected := []Mapping{
+		{Document: 0, Path: "metadata.name", Line: 4, Source: "web.short.yaml", SourcePath: "service.name", SourceLine: 3},
+		{Document: 0, Path: "spec.ports.0", Line: 7, Source: "web.short.yaml", SourcePath: "service.port", SourceLine: 4},
+		{Document: 0, Path: "spec.ports.0.targetPort", Line: 8, Source: "web.short.yaml", SourcePath: "service.port", SourceLine: 4},
+	}
+	if !reflect.DeepEqual(m.Mappings, expected) {
+		t.Errorf("unexpected mappings %s", pretty.Sprint(m.Mappings))
+	}
+
+	mapping, ok := m.Lookup(0, "spec.ports[0].targetPort")
+	if !ok || mapping.Line != 8 {
+		t.Errorf("unexpected mapping %s", pretty.Sprint(mapping))
+	}
+	mapping, ok = m.Lookup(0, "spec.ports[0].port")
+	if !ok || mapping.Path != "spec.ports.0" {
+		t.Errorf("expected the mapping of the parent, got %s", pretty.Sprint(mapping))
+	}
+	if _, ok = m.Lookup(1, "metadata.name"); ok {
+		t.Errorf("expected no mapping for a document without a source")
+	}
+}
@@ -0,0 +1,167 @@
+package table
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/koki/json"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Columns select values from the short representation of each object:
+
+	kind,name,replicas,image=containers.*.image,cpu=containers.*.cpu.min
+
+"kind" is the top-level key of the object (e.g. "deployment"). Every other column
+is a path into the object body, optionally preceded by a header and "=".
+Paths with several matches (e.g. via "*") are joined with ",".
+
+*/
+
+const (
+	FormatCSV      = "csv"
+	FormatTSV      = "tsv"
+	FormatMarkdown = "markdown"
+
+	KindColumn = "kind"
+)
+
+type Column struct {
+	Header string
+	Path   string
+}
+
+// ParseColumns parses a comma-separated list of columns, e.g. "kind,name,image=containers.*.image".
+func ParseColumns(s string) ([]Column, error) {
+	columns := []Column{}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) == 0 {
+			continue
+		}
+
+		column := Column{Header: field, Path: field}
+		if ix := strings.Index(field, "="); ix >= 0 {
+			column.Header = strings.TrimSpace(field[:ix])
+			column.Path = strings.TrimSpace(field[ix+1:])
+		}
+		if len(column.Header) == 0 || len(column.Path) == 0 {
+			return nil, serrors.InvalidValueErrorf(field, "expected 'path' or 'header=path'")
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "no columns")
+	}
+
+	return columns, nil
+}
+
+// Rows selects the column values from each short object. Each object has a single top-level key.
+func Rows(objs []map[string]interface{}, columns []Column) ([][]string, error) {
+	rows := [][]string{}
+	for _, obj := range objs {
+		kinds := make([]string, 0, len(obj))
+		for kind := range obj {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				if column.Path == KindColumn {
+					row[i] = kind
+					continue
+				}
+
+				vals, err := objutil.SelectPath(obj[kind], column.Path)
+				if err != nil {
+					return nil, serrors.ContextualizeErrorf(err, "column %s", column.Header)
+				}
+
+				row[i], err = joinValues(vals)
+				if err != nil {
+					return nil, serrors.ContextualizeErrorf(err, "column %s", column.Header)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+func joinValues(vals []interface{}) (string, error) {
+	strs := make([]string, 0, len(vals))
+	for _, val := range vals {
+		switch val := val.(type) {
+		case nil:
+			continue
+		case string:
+			strs = append(strs, val)
+		default:
+			b, err := json.Marshal(val)
+			if err != nil {
+				return "", serrors.InvalidValueContextErrorf(err, val, "marshalling to JSON")
+			}
+			strs = append(strs, string(b))
+		}
+	}
+
+	return strings.Join(strs, ","), nil
+}
+
+// Write writes a header row and the rows in the given format.
+func Write(w io.Writer, format string, columns []Column, rows [][]string) error {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+
+	switch format {
+	case FormatCSV, FormatTSV:
+		writer := csv.NewWriter(w)
+		if format == FormatTSV {
+			writer.Comma = '\t'
+		}
+		if err := writer.Write(headers); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return nil
+	case FormatMarkdown:
+		return writeMarkdown(w, headers, rows)
+	default:
+		return serrors.InvalidValueErrorf(format, "unsupported format (expected %s, %s or %s)", FormatCSV, FormatTSV, FormatMarkdown)
+	}
+}
+
+func writeMarkdown(w io.Writer, headers []string, rows [][]string) error {
+	separators := make([]string, len(headers))
+	for i := range separators {
+		separators[i] = "---"
+	}
+
+	for _, row := range append([][]string{headers, separators}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.Replace(cell, "|", `\|`, -1)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koki/short/yaml"
+)
+
+var objs0 = `
+deployment:
+  name: web
+  replicas: 2
+  containers:
+  - name: nginx
+    image: nginx:1.13
+  - name: sidecar
+    image: envoy
+`
+
+func TestTable(t *testing.T) {
+	obj := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(objs0), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := ParseColumns("kind,name,replicas,image=containers.*.image,missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := Rows([]map[string]interface{}{obj}, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for format, expected := range map[string]string{
+		FormatCSV: "kind,name,replicas,image,missing\n" +
+			"deployment,web,2,\"nginx:1.13,envoy\",\n",
+		FormatTSV: "kind\tname\treplicas\timage\tmissing\n" +
+			"deployment\tweb\t2\tnginx:1.13,envoy\t\n",
+		FormatMarkdown: "| kind | name | replicas | image | missing |\n" +
+			"| --- | --- | --- | --- | --- |\n" +
+			"| deployment | web | 2 | nginx:1.13,envoy |  |\n",
+	} {
+		buf := &bytes.Buffer{}
+		err = Write(buf, format, columns, rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("%s: unexpected output:\n%s", format, buf.String())
+		}
+	}
+}
+
+func TestParseColumnsErrors(t *testing.T) {
+	for _, s := range []string{"", "=name", "header="} {
+		if _, err := ParseColumns(s); err == nil {
+			t.Errorf("expected error for (%s)", s)
+		}
+	}
+}
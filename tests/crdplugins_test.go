@@ -0,0 +1,9 @@
+package tests
+
+// The golden files of custom resources need their converters.
+import (
+	_ "github.com/koki/short/crdplugin/gatewayapi"
+	_ "github.com/koki/short/crdplugin/istio"
+	_ "github.com/koki/short/crdplugin/knative"
+	_ "github.com/koki/short/crdplugin/vpa"
+)
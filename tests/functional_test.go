@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/udhos/equalfile"
 
 	"github.com/koki/short/client"
+	"github.com/koki/short/fixtures"
 	"github.com/koki/short/parser"
 )
 
@@ -29,234 +31,50 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-func TestPods(t *testing.T) {
-	err := testResource("pods", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestDeployments(t *testing.T) {
-	err := testResource("deployments", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPersistentVolumes(t *testing.T) {
-	err := testResource("persistent_volumes", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestReplicaSets(t *testing.T) {
-	err := testResource("replica_sets", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestReplicationControllers(t *testing.T) {
-	err := testResource("replication_controllers", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestServices(t *testing.T) {
-	err := testResource("services", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestJobs(t *testing.T) {
-	err := testResource("jobs", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestDaemonSets(t *testing.T) {
-	err := testResource("daemon_sets", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestCronJobs(t *testing.T) {
-	err := testResource("cron_jobs", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPVCs(t *testing.T) {
-	err := testResource("pvcs", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestStatefulSets(t *testing.T) {
-	err := testResource("stateful_sets", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestStorageClass(t *testing.T) {
-	err := testResource("storage_class", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestConfigMap(t *testing.T) {
-	err := testResource("config_maps", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestIngress(t *testing.T) {
-	err := testResource("ingress", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestControllerRevision(t *testing.T) {
-	err := testResource("controller_revisions", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestCRDs(t *testing.T) {
-	err := testResource("crds", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
+// goldenDirs are the directories of golden files under testdata: one for each kind, and one for lists.
+func goldenDirs() []string {
+	dirs := []string{"lists"}
+	for _, dir := range fixtures.Dirs {
+		dirs = append(dirs, dir)
 	}
+	sort.Strings(dirs)
+	return dirs
 }
 
-func TestEvents(t *testing.T) {
-	err := testResource("events", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestInitializerConfiguration(t *testing.T) {
-	err := testResource("initializer_config", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestMutatingWebhookConfiguration(t *testing.T) {
-	err := testResource("mutatingwh_config", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestValidatingWebhookConfiguration(t *testing.T) {
-	err := testResource("validatingwh_config", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPodDisruptionPolicy(t *testing.T) {
-	err := testResource("pod_disruption_policy", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPriorityClass(t *testing.T) {
-	err := testResource("priority_class", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPodPreset(t *testing.T) {
-	err := testResource("pod_preset", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestPodSecurityPolicy(t *testing.T) {
-	err := testResource("pod_security_policy", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestLimitRange(t *testing.T) {
-	err := testResource("limit_range", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestHorizontalPodAutoscaler(t *testing.T) {
-	err := testResource("hpas", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestClusterRole(t *testing.T) {
-	err := testResource("cluster_roles", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestClusterRoleBinding(t *testing.T) {
-	err := testResource("cluster_role_bindings", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
-	}
-}
-
-func TestRole(t *testing.T) {
-	err := testResource("roles", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
+func TestGolden(t *testing.T) {
+	for _, dir := range goldenDirs() {
+		dir := dir
+		t.Run(dir, func(t *testing.T) {
+			err := testResource(dir, testFuncGenerator(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
 	}
 }
 
-func TestRoleBinding(t *testing.T) {
-	err := testResource("role_bindings", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
+// TestGoldenDirs checks that every kind has golden files, and that every directory of golden files is tested.
+func TestGoldenDirs(t *testing.T) {
+	tested := map[string]bool{
+		// imports_test.go covers these.
+		"imports": true,
 	}
-}
-
-func TestCertificateSigningRequest(t *testing.T) {
-	err := testResource("csrs", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
+	for _, dir := range goldenDirs() {
+		tested[dir] = true
+		filePairs, err := filePairsForResource(dir)
+		if err != nil || len(filePairs) == 0 {
+			t.Errorf("no golden files in testdata/%s (generate some with \"short fixtures\")", dir)
+		}
 	}
-}
 
-func TestServiceAccounts(t *testing.T) {
-	err := testResource("serviceaccounts", testFuncGenerator(t))
+	infos, err := ioutil.ReadDir("../testdata")
 	if err != nil {
 		t.Fatal(err)
 	}
-}
-
-func TestPodTemplates(t *testing.T) {
-	err := testResource("pod_templates", testFuncGenerator(t))
-	if err != nil {
-		t.Fatal(err)
+	for _, info := range infos {
+		if info.IsDir() && !tested[info.Name()] {
+			t.Errorf("testdata/%s isn't tested, add its kind to fixtures.Dirs", info.Name())
+		}
 	}
 }
 
@@ -0,0 +1,255 @@
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/koki/short/compat"
+	"github.com/koki/short/profile"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Common labels, annotations and name affixes are given to every Kubernetes object, like
+kustomize's commonLabels, commonAnnotations, namePrefix and nameSuffix.
+
+Labels are also added to pod templates and to the selectors that pick those pods, so
+workloads and Services keep matching them. Selectors that aren't set are left unset,
+since Kubernetes fills them in from the template. Annotations are added to pod templates.
+
+A namespace override moves every namespaced object, and rewrites the references that
+name a namespace the objects are moved out of: ServiceAccount subjects of role bindings,
+the services of webhooks and APIServices, and the claims of PersistentVolumes. References
+to other namespaces are left alone.
+
+Only metadata.name is renamed (or generateName, if there's no name), not references to
+the object from other objects. Namespaces and CustomResourceDefinitions aren't renamed,
+since their names have to match what refers to them.
+
+*/
+
+// Common holds what Apply adds to every object.
+type Common struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	NamePrefix  string
+	NameSuffix  string
+	// Namespace moves every namespaced object to this namespace.
+	Namespace string
+	// FromNamespaces are the namespaces that references are moved out of (see NamespacesOf).
+	// "" stands for references that don't name a namespace.
+	FromNamespaces map[string]bool
+}
+
+// namespaceReferences are the references to namespaced objects in each kind, as paths to the namespace.
+// "[]" stands for every item of a list, and "(kind=...)" limits the items to those of a kind.
+var namespaceReferences = map[string][]string{
+	"APIService":                     {"spec.service.namespace"},
+	"ClusterRoleBinding":             {"subjects[](kind=ServiceAccount).namespace"},
+	"MutatingWebhookConfiguration":   {"webhooks[].clientConfig.service.namespace"},
+	"PersistentVolume":               {"spec.claimRef.namespace"},
+	"RoleBinding":                    {"subjects[](kind=ServiceAccount).namespace"},
+	"ValidatingWebhookConfiguration": {"webhooks[].clientConfig.service.namespace"},
+}
+
+// selectorPaths are the label selectors of each kind that select pods, as maps of labels.
+var selectorPaths = map[string][]string{
+	"DaemonSet":             {"spec.selector.matchLabels"},
+	"Deployment":            {"spec.selector.matchLabels"},
+	"Job":                   {"spec.selector.matchLabels"},
+	"NetworkPolicy":         {"spec.podSelector.matchLabels"},
+	"PodDisruptionBudget":   {"spec.selector.matchLabels"},
+	"ReplicaSet":            {"spec.selector.matchLabels"},
+	"ReplicationController": {"spec.selector"},
+	"Service":               {"spec.selector"},
+	"StatefulSet":           {"spec.selector.matchLabels"},
+}
+
+// referenceSegmentRegexp matches a segment of a namespaceReferences path: a key, optionally with "[]" and a kind.
+var referenceSegmentRegexp = regexp.MustCompile(`^([^\[(]+)(\[\](?:\(kind=([^)]*)\))?)?$`)
+
+// unrenamedKinds are the kinds whose names can't be changed on their own.
+var unrenamedKinds = map[string]bool{
+	"CustomResourceDefinition": true,
+	"Namespace":                true,
+}
+
+// ParseKeyValues parses "key=value" pairs, e.g. from --add-label.
+func ParseKeyValues(pairs []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, pair := range pairs {
+		segments := strings.SplitN(pair, "=", 2)
+		if len(segments) != 2 || len(segments[0]) == 0 {
+			return nil, serrors.InvalidValueErrorf(pair, "expected key=value")
+		}
+		result[segments[0]] = segments[1]
+	}
+
+	return result, nil
+}
+
+// NamespacesOf returns the namespaces of the objects, with "" for objects that don't set one.
+func NamespacesOf(objs []map[string]interface{}) map[string]bool {
+	namespaces := map[string]bool{}
+	for _, obj := range objs {
+		kind, _ := obj["kind"].(string)
+		if profile.ClusterScopedKinds[kind] {
+			continue
+		}
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		namespace, _ := metadata["namespace"].(string)
+		namespaces[namespace] = true
+	}
+
+	return namespaces
+}
+
+// IsEmpty is true if Apply wouldn't change anything.
+func (c *Common) IsEmpty() bool {
+	return len(c.Labels) == 0 && len(c.Annotations) == 0 && len(c.NamePrefix) == 0 && len(c.NameSuffix) == 0 && len(c.Namespace) == 0
+}
+
+// Apply adds the labels, annotations and name affixes to a Kubernetes object in place.
+func (c *Common) Apply(obj map[string]interface{}) {
+	kind, _ := obj["kind"].(string)
+	metadata := childMap(obj, "metadata")
+	addAll(metadata, "labels", c.Labels)
+	addAll(metadata, "annotations", c.Annotations)
+
+	if podSpecPath, ok := compat.PodSpecPath(kind); ok && (len(c.Labels) > 0 || len(c.Annotations) > 0) {
+		templatePath := strings.Split(strings.TrimSuffix(podSpecPath, ".spec"), ".")
+		if template, err := objutil.AtPathIn(obj, templatePath); err == nil {
+			if template, ok := template.(map[string]interface{}); ok {
+				templateMetadata := childMap(template, "metadata")
+				addAll(templateMetadata, "labels", c.Labels)
+				addAll(templateMetadata, "annotations", c.Annotations)
+			}
+		}
+	}
+
+	for _, path := range selectorPaths[kind] {
+		segments := strings.Split(path, ".")
+		if selector, err := objutil.AtPathIn(obj, segments[:len(segments)-1]); err == nil {
+			if selector, ok := selector.(map[string]interface{}); ok && selector[segments[len(segments)-1]] != nil {
+				addAll(selector, segments[len(segments)-1], c.Labels)
+			}
+		}
+	}
+
+	if len(c.Namespace) > 0 {
+		c.applyNamespace(kind, obj, metadata)
+	}
+
+	if unrenamedKinds[kind] {
+		return
+	}
+	for _, key := range []string{"name", "generateName"} {
+		if name, ok := metadata[key].(string); ok && len(name) > 0 {
+			if key == "generateName" {
+				// The random suffix comes after the generateName.
+				metadata[key] = c.NamePrefix + name
+			} else {
+				metadata[key] = c.NamePrefix + name + c.NameSuffix
+			}
+			break
+		}
+	}
+}
+
+func (c *Common) applyNamespace(kind string, obj, metadata map[string]interface{}) {
+	if !profile.ClusterScopedKinds[kind] {
+		metadata["namespace"] = c.Namespace
+	}
+
+	for _, path := range namespaceReferences[kind] {
+		c.moveReferences(obj, strings.Split(path, "."))
+	}
+}
+
+// moveReferences sets the namespace at the path if it's one of FromNamespaces.
+func (c *Common) moveReferences(obj interface{}, segments []string) {
+	objMap, ok := obj.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	segment := segments[0]
+	if len(segments) == 1 {
+		namespace, _ := objMap[segment].(string)
+		if c.FromNamespaces[namespace] {
+			objMap[segment] = c.Namespace
+		}
+		return
+	}
+
+	match := referenceSegmentRegexp.FindStringSubmatch(segment)
+	if match == nil {
+		return
+	}
+	val, ok := objMap[match[1]]
+	if !ok {
+		return
+	}
+	if len(match[2]) == 0 {
+		c.moveReferences(val, segments[1:])
+		return
+	}
+
+	items, _ := val.([]interface{})
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if ok && (len(match[3]) == 0 || itemMap["kind"] == match[3]) {
+			c.moveReferences(itemMap, segments[1:])
+		}
+	}
+}
+
+// ApplyToObjs applies to converted (typed) Kubernetes objects, returning them as dictionaries.
+// If FromNamespaces isn't set, it's the namespaces of the objects.
+func (c *Common) ApplyToObjs(objs []interface{}) ([]interface{}, error) {
+	objMaps := make([]map[string]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		objMaps[i] = objMap
+	}
+
+	common := *c
+	if common.FromNamespaces == nil {
+		common.FromNamespaces = NamespacesOf(objMaps)
+	}
+	results := make([]interface{}, len(objs))
+	for i, objMap := range objMaps {
+		common.Apply(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
+
+// childMap returns the map stored under key, creating it if it isn't there.
+func childMap(obj map[string]interface{}, key string) map[string]interface{} {
+	child, ok := obj[key].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		obj[key] = child
+	}
+
+	return child
+}
+
+// addAll adds vals to the map stored under key, creating it if there are any.
+func addAll(obj map[string]interface{}, key string, vals map[string]string) {
+	if len(vals) == 0 {
+		return
+	}
+
+	child := childMap(obj, key)
+	for k, val := range vals {
+		child[k] = val
+	}
+}
@@ -0,0 +1,168 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var plainDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+var commonDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web-v2
+  labels:
+    env: prod
+  annotations:
+    team: storefront
+spec:
+  selector:
+    matchLabels:
+      app: web
+      env: prod
+  template:
+    metadata:
+      labels:
+        app: web
+        env: prod
+      annotations:
+        team: storefront
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+func TestCommonApply(t *testing.T) {
+	common := &Common{
+		Labels:      map[string]string{"env": "prod"},
+		Annotations: map[string]string{"team": "storefront"},
+		NamePrefix:  "prod-",
+		NameSuffix:  "-v2",
+	}
+
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(plainDeployment), &obj); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(commonDeployment), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	common.Apply(obj)
+	if !reflect.DeepEqual(obj, expected) {
+		t.Error(pretty.Sprint(pretty.Diff(obj, expected)))
+	}
+
+	// A Service without a selector keeps selecting nothing, and a Namespace keeps its name.
+	service := map[string]interface{}{"kind": "Service", "metadata": map[string]interface{}{"name": "db"}, "spec": map[string]interface{}{}}
+	common.Apply(service)
+	if _, ok := service["spec"].(map[string]interface{})["selector"]; ok {
+		t.Error("expected no selector to be added to the Service")
+	}
+	namespace := map[string]interface{}{"kind": "Namespace", "metadata": map[string]interface{}{"name": "shop"}}
+	common.Apply(namespace)
+	if name := namespace["metadata"].(map[string]interface{})["name"]; name != "shop" {
+		t.Errorf("expected the Namespace not to be renamed, got %s", name)
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	vals, err := ParseKeyValues([]string{"app=web", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"app": "web", "note": "a=b", "empty": ""}
+	if !reflect.DeepEqual(vals, expected) {
+		t.Errorf("got %v, expected %v", vals, expected)
+	}
+
+	for _, pair := range []string{"app", "=web"} {
+		if _, err := ParseKeyValues([]string{pair}); err == nil {
+			t.Errorf("expected an error for %s", pair)
+		}
+	}
+}
+
+func TestCommonNamespace(t *testing.T) {
+	objs := []interface{}{
+		map[string]interface{}{
+			"kind":     "ServiceAccount",
+			"metadata": map[string]interface{}{"name": "web", "namespace": "staging"},
+		},
+		map[string]interface{}{
+			"kind":     "ClusterRoleBinding",
+			"metadata": map[string]interface{}{"name": "web"},
+			"subjects": []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": "web", "namespace": "staging"},
+				map[string]interface{}{"kind": "ServiceAccount", "name": "monitor", "namespace": "kube-system"},
+				map[string]interface{}{"kind": "Group", "name": "staging"},
+			},
+		},
+		map[string]interface{}{
+			"kind":     "PersistentVolume",
+			"metadata": map[string]interface{}{"name": "data"},
+			"spec": map[string]interface{}{
+				"claimRef": map[string]interface{}{"name": "data", "namespace": "staging"},
+			},
+		},
+	}
+
+	common := &Common{Namespace: "prod"}
+	results, err := common.ApplyToObjs(objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"kind":     "ServiceAccount",
+			"metadata": map[string]interface{}{"name": "web", "namespace": "prod"},
+		},
+		map[string]interface{}{
+			"kind":     "ClusterRoleBinding",
+			"metadata": map[string]interface{}{"name": "web"},
+			"subjects": []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": "web", "namespace": "prod"},
+				map[string]interface{}{"kind": "ServiceAccount", "name": "monitor", "namespace": "kube-system"},
+				map[string]interface{}{"kind": "Group", "name": "staging"},
+			},
+		},
+		map[string]interface{}{
+			"kind":     "PersistentVolume",
+			"metadata": map[string]interface{}{"name": "data"},
+			"spec": map[string]interface{}{
+				"claimRef": map[string]interface{}{"name": "data", "namespace": "prod"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Error(pretty.Sprint(pretty.Diff(results, expected)))
+	}
+	if common.FromNamespaces != nil {
+		t.Error("expected ApplyToObjs to leave FromNamespaces unset")
+	}
+}
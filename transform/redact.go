@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"encoding/base64"
+	"regexp"
+
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Redaction replaces sensitive values of Kubernetes objects with placeholders, so that the
+output can be committed or shared, e.g. in an issue:
+
+	the values of a Secret's data and stringData, and its kubectl last-applied-configuration
+	annotation, which has them too
+	the literal values of env vars (in any "env" list, e.g. of containers) that match a
+	pattern, as "NAME=value"
+
+The values are replaced rather than removed, so the objects still convert and the keys
+show what was there.
+
+*/
+
+// Redacted is what redacted values are replaced with. Secret data is base64 encoded, so it's encoded there too.
+const Redacted = "REDACTED"
+
+// DefaultRedactEnv matches env vars whose names look like they hold credentials.
+var DefaultRedactEnv = []string{`(?i)^[^=]*(password|passwd|secret|token|api_?key|credential|private_?key)[^=]*=`}
+
+// Redaction holds what Apply redacts.
+type Redaction struct {
+	// Env matches the env vars to redact, as "NAME=value".
+	Env []*regexp.Regexp
+}
+
+// NewRedaction redacts the env vars that match any of the patterns.
+func NewRedaction(envPatterns []string) (*Redaction, error) {
+	r := &Redaction{}
+	for _, pattern := range envPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, pattern, "expected a regular expression")
+		}
+		r.Env = append(r.Env, re)
+	}
+
+	return r, nil
+}
+
+// Apply redacts a Kubernetes object in place.
+func (r *Redaction) Apply(obj map[string]interface{}) {
+	if obj["kind"] == "Secret" {
+		encoded := base64.StdEncoding.EncodeToString([]byte(Redacted))
+		redactValues(obj, "data", encoded)
+		redactValues(obj, "stringData", Redacted)
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+				if _, ok := annotations[lastAppliedAnnotation]; ok {
+					annotations[lastAppliedAnnotation] = Redacted
+				}
+			}
+		}
+	}
+
+	r.redactEnv(obj)
+}
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func redactValues(obj map[string]interface{}, key, placeholder string) {
+	values, ok := obj[key].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k := range values {
+		values[k] = placeholder
+	}
+}
+
+// redactEnv redacts the matching env vars of every "env" list in obj.
+func (r *Redaction) redactEnv(obj interface{}) {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		for key, val := range obj {
+			if env, ok := val.([]interface{}); ok && key == "env" {
+				r.redactEnvVars(env)
+			}
+			r.redactEnv(val)
+		}
+	case []interface{}:
+		for _, val := range obj {
+			r.redactEnv(val)
+		}
+	}
+}
+
+func (r *Redaction) redactEnvVars(env []interface{}) {
+	for _, item := range env {
+		envVar, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := envVar["name"].(string)
+		value, ok := envVar["value"].(string)
+		if !ok {
+			continue
+		}
+		for _, re := range r.Env {
+			if re.MatchString(name + "=" + value) {
+				envVar["value"] = Redacted
+				break
+			}
+		}
+	}
+}
+
+// ApplyToObjs redacts converted (typed) Kubernetes objects, returning them as dictionaries.
+func (r *Redaction) ApplyToObjs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Apply(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
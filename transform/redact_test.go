@@ -0,0 +1,114 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var unredacted = `
+apiVersion: v1
+kind: Secret
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{"data":{"password":"aHVudGVyMg=="}}'
+  name: db
+data:
+  password: aHVudGVyMg==
+stringData:
+  user: admin
+`
+
+var redacted = `
+apiVersion: v1
+kind: Secret
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: REDACTED
+  name: db
+data:
+  password: UkVEQUNURUQ=
+stringData:
+  user: REDACTED
+`
+
+var unredactedDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        env:
+        - name: DB_PASSWORD
+          value: hunter2
+        - name: API_KEY
+          value: abc
+        - name: LOG_LEVEL
+          value: debug
+        - name: AWS_ACCESS_KEY_ID
+          value: AKIAEXAMPLE
+        - name: TOKEN
+          valueFrom:
+            secretKeyRef:
+              name: db
+              key: token
+`
+
+var redactedDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        env:
+        - name: DB_PASSWORD
+          value: REDACTED
+        - name: API_KEY
+          value: REDACTED
+        - name: LOG_LEVEL
+          value: debug
+        - name: AWS_ACCESS_KEY_ID
+          value: REDACTED
+        - name: TOKEN
+          valueFrom:
+            secretKeyRef:
+              name: db
+              key: token
+`
+
+func TestRedaction(t *testing.T) {
+	r, err := NewRedaction(append(DefaultRedactEnv, `=AKIA`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, output := range map[string]string{
+		unredacted:           redacted,
+		unredactedDeployment: redactedDeployment,
+	} {
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(input), &obj); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(output), &expected); err != nil {
+			t.Fatal(err)
+		}
+
+		r.Apply(obj)
+		if !reflect.DeepEqual(obj, expected) {
+			t.Error(pretty.Diff(obj, expected))
+		}
+	}
+
+	if _, err := NewRedaction([]string{"("}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"strings"
+
+	"github.com/koki/short/util/objutil"
+)
+
+/*
+
+Objects read back from the API server (e.g. with "kubectl get -o yaml") carry fields the server fills in.
+Applying such an object again fails or pins it to stale values, so StripServerFields removes:
+
+	status
+	metadata: creationTimestamp, deletionTimestamp, deletionGracePeriodSeconds, generation,
+	          managedFields, resourceVersion, selfLink, uid
+	annotations set by kubectl and controllers (see ServerAnnotations)
+	a Service's allocated spec.clusterIP, unless it's headless
+
+*/
+
+// ServerMetadataFields are the metadata fields populated by the API server.
+var ServerMetadataFields = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// ServerAnnotations are annotations written by kubectl and controllers rather than by the manifest's author.
+var ServerAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// StripServerFields removes the status and server-populated fields from a Kubernetes object in place.
+func StripServerFields(obj map[string]interface{}) {
+	delete(obj, "status")
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range ServerMetadataFields {
+			delete(metadata, field)
+		}
+
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for _, annotation := range ServerAnnotations {
+				delete(annotations, annotation)
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	if obj["kind"] == "Service" {
+		if spec, ok := obj["spec"].(map[string]interface{}); ok {
+			if clusterIP, _ := spec["clusterIP"].(string); !strings.EqualFold(clusterIP, "None") {
+				delete(spec, "clusterIP")
+			}
+		}
+	}
+}
+
+// StripServerFieldsFromObjs strips converted (typed) Kubernetes objects, returning them as dictionaries.
+func StripServerFieldsFromObjs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		StripServerFields(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
@@ -0,0 +1,63 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var liveService = `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+  creationTimestamp: 2018-03-01T10:00:00Z
+  name: web
+  resourceVersion: "12345"
+  uid: 0b6c4a7e-1d2f-11e8-9d2c-42010a800002
+spec:
+  clusterIP: 10.0.0.12
+  ports:
+  - port: 80
+status:
+  loadBalancer: {}
+`
+
+var strippedService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+
+func TestStripServerFields(t *testing.T) {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(liveService), &obj); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(strippedService), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	StripServerFields(obj)
+	if !reflect.DeepEqual(obj, expected) {
+		t.Error(pretty.Diff(obj, expected))
+	}
+
+	headless := map[string]interface{}{
+		"kind": "Service",
+		"spec": map[string]interface{}{"clusterIP": "None"},
+	}
+	StripServerFields(headless)
+	if headless["spec"].(map[string]interface{})["clusterIP"] != "None" {
+		t.Error("expected a headless Service to keep its clusterIP")
+	}
+}
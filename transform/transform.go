@@ -0,0 +1,206 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/koki/short/util/objutil"
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Transforms rewrite individual fields of converted objects, e.g. to sanitize
+manifests before handing them to auditors.
+
+A Rule matches map keys by glob ("*" matches any characters, including "." and "/").
+If "in" is set, only keys of maps stored under a key matching "in" are considered.
+
+	transforms:
+	- in: annotations
+	  key: kubectl.kubernetes.io/last-applied-configuration
+	  action: drop
+	- in: annotations
+	  key: "*.token"
+	  action: hash
+
+Built-in actions are "drop", "hash" and "redact". Programs embedding short can
+add their own with RegisterAction.
+
+*/
+
+// ActionFunc transforms the value of a matched field. Returning false drops the field.
+type ActionFunc func(val interface{}) (interface{}, bool)
+
+const (
+	ActionDrop   = "drop"
+	ActionHash   = "hash"
+	ActionRedact = "redact"
+
+	RedactedValue = "REDACTED"
+)
+
+var (
+	actionsLock sync.RWMutex
+	actions     = map[string]ActionFunc{
+		ActionDrop: func(val interface{}) (interface{}, bool) {
+			return nil, false
+		},
+		ActionHash: func(val interface{}) (interface{}, bool) {
+			return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(fmt.Sprintf("%v", val)))), true
+		},
+		ActionRedact: func(val interface{}) (interface{}, bool) {
+			return RedactedValue, true
+		},
+	}
+)
+
+// RegisterAction makes a custom action available to Rules by name.
+func RegisterAction(name string, fn ActionFunc) {
+	actionsLock.Lock()
+	defer actionsLock.Unlock()
+
+	actions[name] = fn
+}
+
+func lookupAction(name string) (ActionFunc, bool) {
+	actionsLock.RLock()
+	defer actionsLock.RUnlock()
+
+	fn, ok := actions[name]
+	return fn, ok
+}
+
+type Rule struct {
+	In     string `json:"in,omitempty"`
+	Key    string `json:"key"`
+	Action string `json:"action"`
+
+	inRegexp  *regexp.Regexp
+	keyRegexp *regexp.Regexp
+	fn        ActionFunc
+}
+
+type Config struct {
+	Transforms []*Rule `json:"transforms"`
+}
+
+// LoadConfig reads transform rules from a yaml or json file.
+func LoadConfig(filename string) (*Config, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "reading transforms from %s", filename)
+	}
+
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, serrors.InvalidValueForTypeContextError(err, string(b), config)
+	}
+
+	err = config.Compile()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, filename)
+	}
+
+	return config, nil
+}
+
+// Compile validates the rules and prepares them for matching.
+func (c *Config) Compile() error {
+	for i, rule := range c.Transforms {
+		if len(rule.Key) == 0 {
+			return serrors.InvalidInstanceErrorf(rule, "transforms[%d]: key is required", i)
+		}
+
+		fn, ok := lookupAction(rule.Action)
+		if !ok {
+			return serrors.InvalidValueErrorf(rule.Action, "transforms[%d]: unrecognized action", i)
+		}
+
+		rule.fn = fn
+		rule.keyRegexp = globRegexp(rule.Key)
+		if len(rule.In) > 0 {
+			rule.inRegexp = globRegexp(rule.In)
+		}
+	}
+
+	return nil
+}
+
+func globRegexp(glob string) *regexp.Regexp {
+	segs := strings.Split(glob, "*")
+	for i, seg := range segs {
+		segs[i] = regexp.QuoteMeta(seg)
+	}
+
+	return regexp.MustCompile(fmt.Sprintf("^%s$", strings.Join(segs, ".*")))
+}
+
+// Apply transforms a dictionary in place.
+func (c *Config) Apply(obj map[string]interface{}) {
+	c.applyMap("", obj)
+}
+
+func (c *Config) applyAny(parentKey string, obj interface{}) {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		c.applyMap(parentKey, obj)
+	case []interface{}:
+		for _, val := range obj {
+			c.applyAny(parentKey, val)
+		}
+	}
+}
+
+func (c *Config) applyMap(parentKey string, obj map[string]interface{}) {
+	for key, val := range obj {
+		rule := c.match(parentKey, key)
+		if rule == nil {
+			c.applyAny(key, val)
+			continue
+		}
+
+		newVal, keep := rule.fn(val)
+		if keep {
+			obj[key] = newVal
+		} else {
+			delete(obj, key)
+		}
+	}
+}
+
+func (c *Config) match(parentKey, key string) *Rule {
+	for _, rule := range c.Transforms {
+		if rule.inRegexp != nil && !rule.inRegexp.MatchString(parentKey) {
+			continue
+		}
+
+		if rule.keyRegexp.MatchString(key) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// ApplyToObjs transforms converted (typed) objects, returning them as dictionaries.
+func (c *Config) ApplyToObjs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Apply(objMap)
+		results[i] = objMap
+	}
+
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var config0 = `
+transforms:
+- in: annotations
+  key: kubectl.kubernetes.io/last-applied-configuration
+  action: drop
+- in: annotations
+  key: "*.token"
+  action: redact
+- key: password
+  action: upper
+`
+
+var obj0 = `
+pod:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+    example.com/api.token: secret
+    keep: me
+  containers:
+  - name: a
+    env:
+    - password: hunter2
+  labels:
+    example.com/api.token: not-an-annotation
+`
+
+var result0 = `
+pod:
+  annotations:
+    example.com/api.token: REDACTED
+    keep: me
+  containers:
+  - name: a
+    env:
+    - password: HUNTER2
+  labels:
+    example.com/api.token: not-an-annotation
+`
+
+func TestApply(t *testing.T) {
+	RegisterAction("upper", func(val interface{}) (interface{}, bool) {
+		return strings.ToUpper(val.(string)), true
+	})
+
+	config := &Config{}
+	err := yaml.Unmarshal([]byte(config0), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(obj0), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(result0), &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Apply(obj)
+	if !reflect.DeepEqual(obj, expected) {
+		t.Error(pretty.Diff(obj, expected))
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, rule := range []*Rule{
+		{Key: "a", Action: "unknown"},
+		{Action: ActionDrop},
+	} {
+		config := &Config{Transforms: []*Rule{rule}}
+		if err := config.Compile(); err == nil {
+			t.Errorf("expected error for rule %# v", rule)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/site"
+)
+
+/*
+
+A terminal browser for a set of manifests. It has three views:
+
+	list    objects, optionally filtered to one namespace
+	detail  the selected object in short and Kubernetes syntax, side by side
+	diff    the short syntax of the marked object against the selected one
+
+The browser itself only keeps state and renders it as lines of text,
+so it can be driven by the terminal (see Run) or by tests.
+
+*/
+
+type View int
+
+const (
+	ViewList View = iota
+	ViewDetail
+	ViewDiff
+)
+
+type Browser struct {
+	objects []*site.Object
+
+	view View
+
+	// namespaces to filter by, in order. The first entry, "", shows every namespace.
+	namespaces []string
+	namespace  int
+
+	// cursor is the selected row of the (filtered) list.
+	cursor int
+
+	// scroll is the first visible line of the detail or diff view.
+	scroll int
+
+	marked *site.Object
+
+	// Copied holds the short syntax of each object copied with Copy.
+	Copied []string
+
+	message string
+}
+
+func NewBrowser(objects []*site.Object) *Browser {
+	seen := map[string]bool{}
+	namespaces := []string{""}
+	for _, object := range objects {
+		if !seen[object.Namespace] {
+			seen[object.Namespace] = true
+			namespaces = append(namespaces, object.Namespace)
+		}
+	}
+	sort.Strings(namespaces[1:])
+
+	return &Browser{
+		objects:    objects,
+		namespaces: namespaces,
+	}
+}
+
+// Visible returns the objects shown in the list view.
+func (b *Browser) Visible() []*site.Object {
+	if b.namespace == 0 {
+		return b.objects
+	}
+
+	visible := []*site.Object{}
+	for _, object := range b.objects {
+		if object.Namespace == b.namespaces[b.namespace] {
+			visible = append(visible, object)
+		}
+	}
+
+	return visible
+}
+
+// Selected returns the object under the cursor, if any.
+func (b *Browser) Selected() *site.Object {
+	visible := b.Visible()
+	if b.cursor < len(visible) {
+		return visible[b.cursor]
+	}
+
+	return nil
+}
+
+func (b *Browser) View() View {
+	return b.view
+}
+
+func (b *Browser) Up() {
+	if b.view == ViewList {
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	} else if b.scroll > 0 {
+		b.scroll--
+	}
+}
+
+func (b *Browser) Down() {
+	if b.view == ViewList {
+		if b.cursor < len(b.Visible())-1 {
+			b.cursor++
+		}
+	} else {
+		b.scroll++
+	}
+}
+
+// NextNamespace filters the list to the next namespace, or to every namespace after the last one.
+func (b *Browser) NextNamespace() {
+	if b.view != ViewList {
+		return
+	}
+
+	b.namespace = (b.namespace + 1) % len(b.namespaces)
+	b.cursor = 0
+}
+
+// Open shows the selected object in the detail view.
+func (b *Browser) Open() {
+	if b.view == ViewList && b.Selected() != nil {
+		b.view = ViewDetail
+		b.scroll = 0
+	}
+}
+
+// Back returns to the list view. It returns false if the list is already shown.
+func (b *Browser) Back() bool {
+	if b.view == ViewList {
+		return false
+	}
+
+	b.view = ViewList
+	b.scroll = 0
+	return true
+}
+
+// Mark remembers the selected object to diff against.
+func (b *Browser) Mark() {
+	if selected := b.Selected(); selected != nil {
+		b.marked = selected
+		b.message = fmt.Sprintf("marked %s", describe(selected))
+	}
+}
+
+// Diff compares the marked object with the selected one.
+func (b *Browser) Diff() {
+	if b.marked == nil {
+		b.message = "mark an object with 'm' first"
+		return
+	}
+	if b.Selected() == nil {
+		return
+	}
+
+	b.view = ViewDiff
+	b.scroll = 0
+}
+
+// Copy saves the short syntax of the selected object, to be printed when the browser exits.
+func (b *Browser) Copy() {
+	if selected := b.Selected(); selected != nil {
+		b.Copied = append(b.Copied, selected.Short)
+		b.message = fmt.Sprintf("copied %s", describe(selected))
+	}
+}
+
+func describe(object *site.Object) string {
+	if len(object.Namespace) > 0 {
+		return fmt.Sprintf("%s %s/%s", object.Kind, object.Namespace, object.Name)
+	}
+
+	return fmt.Sprintf("%s %s", object.Kind, object.Name)
+}
+
+// Render draws the current view as lines of at most width runes, filling height lines.
+// The last line is the status line. It also returns the index of the highlighted line, or -1.
+func (b *Browser) Render(width, height int) ([]string, int) {
+	if height < 2 {
+		return nil, -1
+	}
+
+	var lines []string
+	highlight := -1
+	switch b.view {
+	case ViewList:
+		lines, highlight = b.renderList(height - 1)
+	case ViewDetail:
+		lines = b.renderDetail(width, height-1)
+	case ViewDiff:
+		lines = b.renderDiff(height - 1)
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, b.status())
+	b.message = ""
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+
+	return lines, highlight
+}
+
+func (b *Browser) renderList(height int) ([]string, int) {
+	namespace := "all namespaces"
+	if b.namespace > 0 {
+		namespace = "namespace " + b.namespaces[b.namespace]
+	}
+	lines := []string{fmt.Sprintf("%-20s %-24s %s    (%s)", "NAMESPACE", "KIND", "NAME", namespace)}
+
+	visible := b.Visible()
+	rows := height - 1
+	first := 0
+	if b.cursor >= rows {
+		first = b.cursor - rows + 1
+	}
+	for i := first; i < len(visible) && i < first+rows; i++ {
+		object := visible[i]
+		marker := " "
+		if object == b.marked {
+			marker = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s%-19s %-24s %s", marker, object.Namespace, object.Kind, object.Name))
+	}
+
+	return lines, b.cursor - first + 1
+}
+
+func (b *Browser) renderDetail(width, height int) []string {
+	object := b.Selected()
+	columnWidth := (width - 3) / 2
+	if columnWidth < 1 {
+		columnWidth = 1
+	}
+
+	left := append([]string{"SHORT", ""}, splitLines(object.Short)...)
+	right := append([]string{"KUBERNETES", ""}, splitLines(object.Kube)...)
+	rows := len(left)
+	if len(right) > rows {
+		rows = len(right)
+	}
+
+	lines := []string{}
+	for i := b.clampScroll(rows, height); i < rows && len(lines) < height; i++ {
+		l, r := "", ""
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		lines = append(lines, pad(truncate(l, columnWidth), columnWidth)+" | "+r)
+	}
+
+	return lines
+}
+
+func (b *Browser) renderDiff(height int) []string {
+	diff := append([]string{
+		fmt.Sprintf("--- %s", describe(b.marked)),
+		fmt.Sprintf("+++ %s", describe(b.Selected())),
+	}, LineDiff(b.marked.Short, b.Selected().Short)...)
+
+	first := b.clampScroll(len(diff), height)
+	last := first + height
+	if last > len(diff) {
+		last = len(diff)
+	}
+
+	return diff[first:last]
+}
+
+// clampScroll keeps the detail and diff views from scrolling past their last line.
+func (b *Browser) clampScroll(rows, height int) int {
+	if b.scroll > rows-height {
+		b.scroll = rows - height
+	}
+	if b.scroll < 0 {
+		b.scroll = 0
+	}
+
+	return b.scroll
+}
+
+func (b *Browser) status() string {
+	if len(b.message) > 0 {
+		return b.message
+	}
+
+	switch b.view {
+	case ViewList:
+		return "enter: open  n: next namespace  m: mark  d: diff with marked  c: copy  q: quit"
+	default:
+		return "esc: back  up/down: scroll  c: copy  q: quit"
+	}
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+
+	return s
+}
+
+func pad(s string, width int) string {
+	if n := width - len([]rune(s)); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+
+	return s
+}
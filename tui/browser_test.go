@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/site"
+)
+
+var objects0 = []*site.Object{
+	{ID: site.ID{Kind: "config_map", Namespace: "dev", Name: "settings"}, Short: "config_map:\n  name: settings\n", Kube: "kind: ConfigMap\n"},
+	{ID: site.ID{Kind: "config_map", Namespace: "prod", Name: "settings"}, Short: "config_map:\n  name: settings\n  namespace: prod\n", Kube: "kind: ConfigMap\n"},
+}
+
+func TestBrowser(t *testing.T) {
+	b := NewBrowser(objects0)
+
+	lines, highlight := b.Render(80, 5)
+	if len(lines) != 5 || highlight != 1 || !strings.HasSuffix(lines[1], "settings") {
+		t.Errorf("unexpected list %q (highlight %d)", lines, highlight)
+	}
+
+	b.NextNamespace()
+	b.NextNamespace()
+	if visible := b.Visible(); len(visible) != 1 || visible[0] != objects0[1] {
+		t.Errorf("expected only the prod object, got %v", visible)
+	}
+	b.NextNamespace()
+	if len(b.Visible()) != 2 {
+		t.Error("expected every namespace after the last one")
+	}
+
+	b.Open()
+	if b.View() != ViewDetail {
+		t.Fatal("expected the detail view")
+	}
+	lines, _ = b.Render(23, 4)
+	expected := []string{
+		"SHORT      | KUBERNETES",
+		"           | ",
+		"config_map | kind: Conf",
+		"esc: back  up/down: scr",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Error(pretty.Diff(lines, expected))
+	}
+	b.Back()
+
+	b.Mark()
+	b.Down()
+	b.Diff()
+	if b.View() != ViewDiff {
+		t.Fatal("expected the diff view")
+	}
+	lines, _ = b.Render(80, 7)
+	expected = []string{
+		"--- config_map dev/settings",
+		"+++ config_map prod/settings",
+		"  config_map:",
+		"    name: settings",
+		"+   namespace: prod",
+		"",
+		"marked config_map dev/settings",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Error(pretty.Diff(lines, expected))
+	}
+
+	b.Copy()
+	if !reflect.DeepEqual(b.Copied, []string{objects0[1].Short}) {
+		t.Errorf("unexpected copies %q", b.Copied)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	diff := LineDiff("a\nb\nc\n", "a\nc\nd\n")
+	expected := []string{"  a", "- b", "  c", "+ d"}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Error(pretty.Diff(diff, expected))
+	}
+}
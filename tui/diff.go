@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+)
+
+// LineDiff compares two texts line by line. Each line of the result is prefixed with
+// "  " (unchanged), "- " (only in a) or "+ " (only in b).
+func LineDiff(a, b string) []string {
+	aLines := splitLines(a)
+	bLines := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of aLines[i:] and bLines[j:].
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := []string{}
+	i, j := 0, 0
+	for i < len(aLines) && j < len(bLines) {
+		switch {
+		case aLines[i] == bLines[j]:
+			diff = append(diff, "  "+aLines[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+aLines[i])
+			i++
+		default:
+			diff = append(diff, "+ "+bLines[j])
+			j++
+		}
+	}
+	for ; i < len(aLines); i++ {
+		diff = append(diff, "- "+aLines[i])
+	}
+	for ; j < len(bLines); j++ {
+		diff = append(diff, "+ "+bLines[j])
+	}
+
+	return diff
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if len(s) == 0 {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
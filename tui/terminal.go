@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Run draws the browser in the terminal and handles keys until the user quits.
+func Run(b *Browser) error {
+	err := termbox.Init()
+	if err != nil {
+		return err
+	}
+	defer termbox.Close()
+
+	termbox.SetInputMode(termbox.InputEsc)
+
+	for {
+		draw(b)
+
+		ev := termbox.PollEvent()
+		switch ev.Type {
+		case termbox.EventError:
+			return ev.Err
+		case termbox.EventKey:
+			switch ev.Key {
+			case termbox.KeyArrowUp, termbox.KeyCtrlP:
+				b.Up()
+			case termbox.KeyArrowDown, termbox.KeyCtrlN:
+				b.Down()
+			case termbox.KeyEnter:
+				b.Open()
+			case termbox.KeyEsc, termbox.KeyBackspace, termbox.KeyBackspace2:
+				b.Back()
+			case termbox.KeyCtrlC, termbox.KeyCtrlD:
+				return nil
+			}
+
+			switch ev.Ch {
+			case 'k':
+				b.Up()
+			case 'j':
+				b.Down()
+			case 'n':
+				b.NextNamespace()
+			case 'm':
+				b.Mark()
+			case 'd':
+				b.Diff()
+			case 'c':
+				b.Copy()
+			case 'q':
+				return nil
+			}
+		}
+	}
+}
+
+func draw(b *Browser) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+	width, height := termbox.Size()
+	lines, highlight := b.Render(width, height)
+	for y, line := range lines {
+		fg, bg := termbox.ColorDefault, termbox.ColorDefault
+		if y == highlight {
+			fg, bg = termbox.ColorBlack, termbox.ColorWhite
+		}
+		x := 0
+		for _, ch := range line {
+			termbox.SetCell(x, y, ch, fg, bg)
+			x++
+		}
+		if y == highlight {
+			for ; x < width; x++ {
+				termbox.SetCell(x, y, ' ', fg, bg)
+			}
+		}
+	}
+
+	termbox.Flush()
+}
@@ -15,6 +15,7 @@ type APIService struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Service      string `json:"service,omitempty"`
 	GroupVersion string `json:"group_version,omitempty"`
@@ -11,6 +11,7 @@ type Binding struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Target ObjectReference `json:"target"`
 }
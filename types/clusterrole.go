@@ -20,6 +20,7 @@ type ClusterRole struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Rules []PolicyRule `json:"rules"`
 
@@ -50,6 +51,7 @@ type ClusterRoleBinding struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Subjects holds references to the objects the role applies to.
 	Subjects []Subject `json:"subjects"`
@@ -11,5 +11,6 @@ type ConfigMap struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 	Data        map[string]string `json:"data,omitempty"`
 }
@@ -7,42 +7,45 @@ import (
 )
 
 type Container struct {
-	Command              []string                 `json:"command,omitempty"`
-	Args                 []floatstr.FloatOrString `json:"args,omitempty"`
-	Env                  []Env                    `json:"env,omitempty"`
-	Image                string                   `json:"image"`
-	Pull                 PullPolicy               `json:"pull,omitempty"`
-	OnStart              *Action                  `json:"on_start,omitempty"`
-	PreStop              *Action                  `json:"pre_stop,omitempty"`
-	CPU                  *CPU                     `json:"cpu,omitempty"`
-	Mem                  *Mem                     `json:"mem,omitempty"`
-	Name                 string                   `json:"name,omitempty"`
-	AddCapabilities      []string                 `json:"cap_add,omitempty"`
-	DelCapabilities      []string                 `json:"cap_drop,omitempty"`
-	Privileged           *bool                    `json:"privileged,omitempty"`
-	AllowEscalation      *bool                    `json:"allow_escalation,omitempty"`
-	RW                   *bool                    `json:"rw,omitempty"`
-	RO                   *bool                    `json:"ro,omitempty"`
-	ForceNonRoot         *bool                    `json:"force_non_root,omitempty"`
-	UID                  *int64                   `json:"uid,omitempty"`
-	GID                  *int64                   `json:"gid,omitempty"`
-	SELinux              *SELinux                 `json:"selinux,omitempty"`
-	LivenessProbe        *Probe                   `json:"liveness_probe,omitempty"`
-	ReadinessProbe       *Probe                   `json:"readiness_probe,omitempty"`
-	Expose               []Port                   `json:"expose,omitempty"`
-	Stdin                bool                     `json:"stdin,omitempty"`
-	StdinOnce            bool                     `json:"stdin_once,omitempty"`
-	TTY                  bool                     `json:"tty,omitempty"`
-	WorkingDir           string                   `json:"wd,omitempty"`
-	TerminationMsgPath   string                   `json:"termination_msg_path,omitempty"`
-	TerminationMsgPolicy TerminationMessagePolicy `json:"termination_msg_policy,omitempty"`
-	ContainerID          string                   `json:"container_id,omitempty"`
-	ImageID              string                   `json:"image_id,omitempty"`
-	Ready                bool                     `json:"ready,omitempty"`
-	LastState            *ContainerState          `json:"last_state,omitempty"`
-	CurrentState         *ContainerState          `json:"current_state,omitempty"`
-	VolumeMounts         []VolumeMount            `json:"volume,omitempty"`
-	Restarts             int32                    `json:"restarts,omitempty"`
+	Command              []string                  `json:"command,omitempty"`
+	Args                 []floatstr.FloatOrString  `json:"args,omitempty"`
+	Env                  []Env                     `json:"env,omitempty"`
+	Image                string                    `json:"image"`
+	Pull                 PullPolicy                `json:"pull,omitempty"`
+	OnStart              *Hook                     `json:"on_start,omitempty"`
+	PreStop              *Hook                     `json:"pre_stop,omitempty"`
+	CPU                  *CPU                      `json:"cpu,omitempty"`
+	Mem                  *Mem                      `json:"mem,omitempty"`
+	Resources            map[string]ResourceBounds `json:"resources,omitempty"`
+	Name                 string                    `json:"name,omitempty"`
+	AddCapabilities      []string                  `json:"cap_add,omitempty"`
+	DelCapabilities      []string                  `json:"cap_drop,omitempty"`
+	Privileged           *bool                     `json:"privileged,omitempty"`
+	AllowEscalation      *bool                     `json:"allow_escalation,omitempty"`
+	RW                   *bool                     `json:"rw,omitempty"`
+	RO                   *bool                     `json:"ro,omitempty"`
+	ForceNonRoot         *bool                     `json:"force_non_root,omitempty"`
+	UID                  *int64                    `json:"uid,omitempty"`
+	GID                  *int64                    `json:"gid,omitempty"`
+	SELinux              *SELinux                  `json:"selinux,omitempty"`
+	Seccomp              string                    `json:"seccomp,omitempty"`
+	AppArmor             string                    `json:"apparmor,omitempty"`
+	LivenessProbe        *Probe                    `json:"liveness_probe,omitempty"`
+	ReadinessProbe       *Probe                    `json:"readiness_probe,omitempty"`
+	Expose               []Port                    `json:"expose,omitempty"`
+	Stdin                bool                      `json:"stdin,omitempty"`
+	StdinOnce            bool                      `json:"stdin_once,omitempty"`
+	TTY                  bool                      `json:"tty,omitempty"`
+	WorkingDir           string                    `json:"wd,omitempty"`
+	TerminationMsgPath   string                    `json:"termination_msg_path,omitempty"`
+	TerminationMsgPolicy TerminationMessagePolicy  `json:"termination_msg_policy,omitempty"`
+	ContainerID          string                    `json:"container_id,omitempty"`
+	ImageID              string                    `json:"image_id,omitempty"`
+	Ready                bool                      `json:"ready,omitempty"`
+	LastState            *ContainerState           `json:"last_state,omitempty"`
+	CurrentState         *ContainerState           `json:"current_state,omitempty"`
+	VolumeMounts         []VolumeMount             `json:"volume,omitempty"`
+	Restarts             int32                     `json:"restarts,omitempty"`
 }
 
 type ContainerState struct {
@@ -70,9 +73,9 @@ type ContainerStateTerminated struct {
 }
 
 type VolumeMount struct {
-	MountPath   string           `json:"mount,omitempty"`
+	MountPath   string            `json:"mount,omitempty"`
 	Propagation *MountPropagation `json:"propagation,omitempty"`
-	Store       string           `json:"store,omitempty"`
+	Store       string            `json:"store,omitempty"`
 }
 
 type MountPropagation string
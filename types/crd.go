@@ -16,6 +16,7 @@ type CustomResourceDefinition struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Spec::CRDSpec
 	//   Group::string, Version::string, Names::CRDNames
@@ -16,6 +16,7 @@ type CronJob struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Schedule                string `json:"schedule,omitempty"`
 	Suspend                 *bool  `json:"suspend,omitempty"`
@@ -15,6 +15,7 @@ type CertificateSigningRequest struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	//Spec fields
 	Request  []byte              `json:"request,omitempty"`
@@ -15,6 +15,7 @@ type DaemonSet struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	OnDelete       bool                `json:"replace_on_delete,omitempty"`
 	MaxUnavailable *intstr.IntOrString `json:"max_unavailable,omitempty"`
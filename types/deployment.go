@@ -16,6 +16,7 @@ type Deployment struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Replicas       *int32              `json:"replicas,omitempty"`
 	Recreate       bool                `json:"recreate,omitempty"`
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
 type EndpointsWrapper struct {
 	Endpoints Endpoints `json:"endpoints,omitempty"`
 }
@@ -11,6 +16,7 @@ type Endpoints struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Subsets []EndpointSubset `json:"subsets,omitempty"`
 }
@@ -27,3 +33,44 @@ type EndpointAddress struct {
 	Nodename *string          `json:"node,omitempty"`
 	Target   *ObjectReference `json:"target,omitempty"`
 }
+
+// endpointAddress avoids recursing into EndpointAddress's (Un)MarshalJSON.
+type endpointAddress EndpointAddress
+
+// UnmarshalJSON accepts a bare IP address string as well as the full address object.
+func (a *EndpointAddress) UnmarshalJSON(data []byte) error {
+	str := ""
+	err := json.Unmarshal(data, &str)
+	if err == nil {
+		*a = EndpointAddress{IP: str}
+		return nil
+	}
+
+	addr := endpointAddress{}
+	err = json.Unmarshal(data, &addr)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "endpoint address should be an IP string or an object")
+	}
+	*a = EndpointAddress(addr)
+
+	return nil
+}
+
+// MarshalJSON writes an address with only an IP as a bare string.
+func (a EndpointAddress) MarshalJSON() ([]byte, error) {
+	if len(a.Hostname) == 0 && a.Nodename == nil && a.Target == nil {
+		b, err := json.Marshal(a.IP)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "endpoint address IP")
+		}
+
+		return b, nil
+	}
+
+	b, err := json.Marshal(endpointAddress(a))
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, a, "marshalling endpoint address")
+	}
+
+	return b, nil
+}
@@ -177,11 +177,97 @@ func UnparseEnvVal(val EnvVal) string {
 	return fmt.Sprintf("%s=%s", val.Key, val.Val)
 }
 
+// ParseEnvFromString parses the compact string form of an EnvFrom, e.g.
+// "from config:settings as APP_" or "DB_PASSWORD from secret:db:password".
+// It returns false if the string isn't in this form.
+func ParseEnvFromString(s string) (*EnvFrom, bool, error) {
+	if strings.HasPrefix(s, "from ") {
+		source := strings.TrimSpace(strings.TrimPrefix(s, "from "))
+		prefix := ""
+		if i := strings.Index(source, " as "); i >= 0 {
+			prefix = strings.TrimSpace(source[i+len(" as "):])
+			source = strings.TrimSpace(source[:i])
+		}
+		if !strings.HasPrefix(source, "config:") && !strings.HasPrefix(source, "secret:") || strings.Count(source, ":") != 1 {
+			return nil, true, util.InvalidValueErrorf(s, "expected 'from config:NAME' or 'from secret:NAME', optionally followed by 'as PREFIX'")
+		}
+		return &EnvFrom{Key: prefix, From: source}, true, nil
+	}
+
+	i := strings.Index(s, " from ")
+	if i < 0 {
+		return nil, false, nil
+	}
+	key := s[:i]
+	if len(key) == 0 || strings.ContainsAny(key, "= ") {
+		return nil, false, nil
+	}
+	source := strings.TrimSpace(s[i+len(" from "):])
+	if len(source) == 0 {
+		return nil, true, util.InvalidValueErrorf(s, "expected a source after 'from'")
+	}
+	if (strings.HasPrefix(source, "config:") || strings.HasPrefix(source, "secret:")) && strings.Count(source, ":") != 2 {
+		return nil, true, util.InvalidValueErrorf(s, "expected 'KEY from config:NAME:KEY' or 'KEY from secret:NAME:KEY'")
+	}
+
+	return &EnvFrom{Key: key, From: source}, true, nil
+}
+
+// ParseResourceFieldRef parses the source of an env var that holds a resource of a container,
+// e.g. "limits.cpu", "limits.cpu:1m" (with a divisor) or "web:limits.memory:1Mi" (for another container).
+// It returns false if the source isn't a resource.
+func ParseResourceFieldRef(from string) (containerName, resource, divisor string, ok bool) {
+	isResource := func(s string) bool {
+		return strings.HasPrefix(s, "limits.") || strings.HasPrefix(s, "requests.")
+	}
+
+	segments := strings.Split(from, ":")
+	switch {
+	case isResource(segments[0]) && len(segments) <= 2:
+		resource = segments[0]
+		if len(segments) == 2 {
+			divisor = segments[1]
+		}
+		return "", resource, divisor, true
+	case len(segments) >= 2 && len(segments) <= 3 && isResource(segments[1]):
+		containerName, resource = segments[0], segments[1]
+		if len(segments) == 3 {
+			divisor = segments[2]
+		}
+		return containerName, resource, divisor, true
+	}
+
+	return "", "", "", false
+}
+
+// UnparseResourceFieldRef is the inverse of ParseResourceFieldRef.
+func UnparseResourceFieldRef(containerName, resource, divisor string) string {
+	segments := []string{}
+	if len(containerName) > 0 {
+		segments = append(segments, containerName)
+	}
+	segments = append(segments, resource)
+	if len(divisor) > 0 {
+		segments = append(segments, divisor)
+	}
+
+	return strings.Join(segments, ":")
+}
+
 // UnmarshalJSON implements the json.Unmarshaller interface.
 func (e *Env) UnmarshalJSON(value []byte) error {
 	var s string
 	err := json.Unmarshal(value, &s)
 	if err == nil {
+		from, ok, err := ParseEnvFromString(s)
+		if err != nil {
+			return err
+		}
+		if ok {
+			e.SetFrom(*from)
+			return nil
+		}
+
 		envVal := ParseEnvVal(s)
 		e.SetVal(*envVal)
 		return nil
@@ -250,3 +250,62 @@ func TestNewEnvFromSecretOrConfigWithInvalidInputs(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvCompactFromSyntax(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want EnvFrom
+	}{
+		{`"from config:settings"`, EnvFrom{From: "config:settings"}},
+		{`"from secret:creds as DB_"`, EnvFrom{Key: "DB_", From: "secret:creds"}},
+		{`"PASSWORD from secret:creds:password"`, EnvFrom{Key: "PASSWORD", From: "secret:creds:password"}},
+		{`"POD_NAME from metadata.name"`, EnvFrom{Key: "POD_NAME", From: "metadata.name"}},
+		{`"CPU from sidecar:limits.cpu:1m"`, EnvFrom{Key: "CPU", From: "sidecar:limits.cpu:1m"}},
+	}
+
+	for _, testCase := range testCases {
+		e := Env{}
+		if err := e.UnmarshalJSON([]byte(testCase.in)); err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if e.Type != EnvFromEnvType || e.From == nil || *e.From != testCase.want {
+			t.Errorf("%s: got %#v, want %#v", testCase.in, e.From, testCase.want)
+		}
+	}
+
+	e := Env{}
+	if err := e.UnmarshalJSON([]byte(`"GREETING=hello from koki"`)); err != nil || e.Type != EnvValEnvType {
+		t.Errorf("key-value env with 'from' in its value was parsed as %#v (%v)", e, err)
+	}
+
+	for _, invalid := range []string{`"from config:settings:key"`, `"from metadata.name"`, `"KEY from config:settings"`} {
+		if err := e.UnmarshalJSON([]byte(invalid)); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestParseResourceFieldRef(t *testing.T) {
+	testCases := []struct {
+		from, containerName, resource, divisor string
+		ok                                     bool
+	}{
+		{"limits.cpu", "", "limits.cpu", "", true},
+		{"requests.memory:1Mi", "", "requests.memory", "1Mi", true},
+		{"sidecar:limits.cpu", "sidecar", "limits.cpu", "", true},
+		{"sidecar:limits.cpu:1m", "sidecar", "limits.cpu", "1m", true},
+		{"metadata.name", "", "", "", false},
+		{"config:settings:limits.cpu", "", "", "", false},
+	}
+
+	for _, testCase := range testCases {
+		containerName, resource, divisor, ok := ParseResourceFieldRef(testCase.from)
+		if ok != testCase.ok || containerName != testCase.containerName || resource != testCase.resource || divisor != testCase.divisor {
+			t.Errorf("%s: got (%s, %s, %s, %v)", testCase.from, containerName, resource, divisor, ok)
+		}
+		if ok && UnparseResourceFieldRef(containerName, resource, divisor) != testCase.from {
+			t.Errorf("%s: didn't round-trip", testCase.from)
+		}
+	}
+}
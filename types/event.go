@@ -15,6 +15,7 @@ type Event struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// The object that this event is about.
 	InvolvedObject ObjectReference `json:"involved"`
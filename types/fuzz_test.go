@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/koki/json"
+)
+
+// fuzzTargets returns a new value of each type that parses its own JSON, so the fuzzers below
+// can check that malformed input is rejected with an error rather than a panic.
+func fuzzTargets() []interface{} {
+	return []interface{}{
+		&RoleRef{},
+		&Subject{},
+		&EndpointAddress{},
+		&Env{},
+		&CrossVersionObjectReference{},
+		&InitializerRule{},
+		&Hook{},
+		&LimitRangeItem{},
+		&Taint{},
+		&NodeAddress{},
+		&OwnerReference{},
+		&AccessModes{},
+		&ClaimReference{},
+		&MountOptions{},
+		&PersistentVolume{},
+		&PersistentVolumeSource{},
+		&SecretReference{},
+		&CephFSPersistentSecretFileOrRef{},
+		&Port{},
+		&RSSelector{},
+		&ResourceBounds{},
+		&LoadBalancerIngress{},
+		&ServicePort{},
+		&NamedServicePort{},
+		&Toleration{},
+		&Volume{},
+		&CephFSSecretFileOrRef{},
+		new(FileMode),
+		&KeyAndMode{},
+		&ObjectFieldSelector{},
+		&VolumeResourceFieldSelector{},
+		&VolumeProjection{},
+		&VolumeMount{},
+		&WebhookRuleWithOperations{},
+	}
+}
+
+var fuzzStrings = []string{
+	"",
+	":",
+	"\\",
+	"/",
+	"=",
+	"@",
+	"host_path:/data:directory",
+	"gce_pd:disk-1:ext4:ro",
+	"nfs:server:/exports:ro",
+	"glusterfs:endpoints:/path:ro",
+	"empty_dir:memory:1Gi",
+	"azure_disk",
+	"pvc:claim:rw",
+	`secret:a\:b`,
+	"config:app:/etc/cfg:ro:hosttocontainer",
+	"udp://1.2.3.4:8080:80",
+	"8080:80",
+	"key=value:NoSchedule",
+	"rw_once,ro_many",
+	"ro,noatime",
+	"default/data",
+	"ReplicaSet/web@apps/v1 uid=1 controller",
+	"ns:secret",
+	"0644",
+	"metadata.name",
+	"limits.cpu:1m",
+}
+
+func fuzzUnmarshal(t *testing.T, data []byte) {
+	for _, target := range fuzzTargets() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("unmarshalling %q into %T panicked: %v", data, target, r)
+				}
+			}()
+
+			_ = json.Unmarshal(data, target)
+		}()
+	}
+}
+
+// FuzzUnmarshalString unmarshals a JSON string into each type, since most short syntax is a string.
+func FuzzUnmarshalString(f *testing.F) {
+	for _, s := range fuzzStrings {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Skip()
+		}
+		fuzzUnmarshal(t, data)
+	})
+}
+
+// FuzzUnmarshalJSON unmarshals arbitrary JSON into each type.
+func FuzzUnmarshalJSON(f *testing.F) {
+	for _, s := range fuzzStrings {
+		f.Add([]byte(fmt.Sprintf("%q", s)))
+		f.Add([]byte(fmt.Sprintf(`{"vol_type": "host_path", "vol_id": %q, "path": %q}`, s, s)))
+		f.Add([]byte(fmt.Sprintf(`{"%s": %q}`, s, s)))
+	}
+	f.Add([]byte(`{"vol_type": "projected", "sources": [{"secret": "s", "items": {"a": "b:0644"}}]}`))
+	f.Add([]byte(`{"mount": "/data", "store": "vol:sub:ro", "propagation": "bidirectional"}`))
+	f.Add([]byte(`[1, 2.5, null, true]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzUnmarshal(t, data)
+	})
+}
+
+// FuzzInitFromString parses strings with the InitFromString methods.
+func FuzzInitFromString(f *testing.F) {
+	for _, s := range fuzzStrings {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("parsing %q panicked: %v", s, r)
+			}
+		}()
+
+		_ = (&AccessModes{}).InitFromString(s)
+		_ = (&MountOptions{}).InitFromString(s)
+		_ = (&Port{}).InitFromString(s)
+		_ = (&ServicePort{}).InitFromString(s)
+		(&LoadBalancerIngress{}).InitFromString(s)
+		_, _ = ParseVolumeMount(s)
+		_, _, _, _ = SplitMountStore(s)
+		_, _ = ParseOwnerReference(s)
+	})
+}
@@ -21,6 +21,7 @@ type HorizontalPodAutoscaler struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	HorizontalPodAutoscalerSpec `json:",inline"`
 
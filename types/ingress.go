@@ -15,6 +15,7 @@ type Ingress struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Backend::*IngressBackend
 	ServiceName string              `json:"backend,omitempty"`
@@ -18,6 +18,7 @@ type InitializerConfig struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Rules map[string][]InitializerRule `json:"rules,omitempty"`
 }
@@ -28,6 +29,9 @@ type InitializerRule struct {
 	Resources []string
 }
 
+// initializerRule has the fields of InitializerRule without its JSON methods.
+type initializerRule InitializerRule
+
 func (i *InitializerRule) UnmarshalJSON(data []byte) error {
 	var ruleString string
 	strErr1 := json.Unmarshal(data, &ruleString)
@@ -50,7 +54,7 @@ func (i *InitializerRule) UnmarshalJSON(data []byte) error {
 
 		return nil
 	}
-	var ruleStruct InitializerRule
+	var ruleStruct initializerRule
 	strErr2 := json.Unmarshal(data, &ruleStruct)
 	if strErr2 != nil {
 		return strErr2
@@ -70,7 +74,7 @@ func (i InitializerRule) MarshalJSON() ([]byte, error) {
 	var rule interface{}
 	var ruleType string
 
-	rule = i
+	rule = initializerRule(i)
 	ruleType = "struct"
 
 	if len(i.Resources) == 1 && len(i.Versions) == 1 && len(i.Resources) == 1 {
@@ -12,6 +12,7 @@ type Job struct {
 	Version string `json:"version,omitempty"`
 
 	PodTemplateMeta `json:",inline"`
+	Ownership       `json:",inline"`
 	JobTemplate     `json:",inline"`
 	JobStatus       `json:",inline"`
 }
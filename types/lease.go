@@ -0,0 +1,25 @@
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type LeaseWrapper struct {
+	Lease Lease `json:"lease"`
+}
+
+type Lease struct {
+	Version     string            `json:"version,omitempty"`
+	Cluster     string            `json:"cluster,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
+
+	Holder          *string           `json:"holder,omitempty"`
+	DurationSeconds *int32            `json:"duration,omitempty"`
+	AcquireTime     *metav1.MicroTime `json:"acquired,omitempty"`
+	RenewTime       *metav1.MicroTime `json:"renewed,omitempty"`
+	Transitions     *int32            `json:"transitions,omitempty"`
+}
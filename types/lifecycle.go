@@ -1,6 +1,71 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
 type Lifecycle struct {
 	OnStart *Action `json:"on_start,omitempty"`
 	PreStop *Action `json:"pre_stop,omitempty"`
 }
+
+// Hook is the action of a container lifecycle hook (on_start or pre_stop).
+// Besides the fields of an Action, it can be written as a string:
+//
+//	pre_stop: "exec: /bin/drain --timeout 30"
+//	pre_stop: "http: /quit:8080"
+//	pre_stop: "https: /quit:8443"
+//
+// The command of the string form is split on spaces, without shell quoting, so a command with
+// quotes or backslashes has to be written as a list, e.g. command: [sh, -c, "kill -QUIT 1"].
+type Hook Action
+
+func (h *Hook) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		action := Action{}
+		err = json.Unmarshal(data, &action)
+		if err != nil {
+			return serrors.InvalidValueForTypeContextError(err, string(data), h)
+		}
+		*h = Hook(action)
+		return nil
+	}
+
+	segments := strings.SplitN(str, ":", 2)
+	if len(segments) != 2 {
+		return serrors.InvalidValueForTypeErrorf(str, h, "expected 'exec: COMMAND' or 'http: /PATH:PORT'")
+	}
+
+	value := strings.TrimSpace(segments[1])
+	switch scheme := strings.TrimSpace(segments[0]); scheme {
+	case "exec":
+		if strings.ContainsAny(value, `"'\`) {
+			return serrors.InvalidValueForTypeErrorf(str, h, "'exec:' doesn't support quoting, write the command as a list instead, e.g. command: [sh, -c, \"...\"]")
+		}
+		command := strings.Fields(value)
+		if len(command) == 0 {
+			return serrors.InvalidValueForTypeErrorf(str, h, "expected a command after 'exec:'")
+		}
+		*h = Hook{Command: command}
+	case "http", "https":
+		i := strings.LastIndex(value, ":")
+		if i < 0 || !strings.HasPrefix(value, "/") {
+			return serrors.InvalidValueForTypeErrorf(str, h, "expected '%s: /PATH:PORT'", scheme)
+		}
+		path, port := value[:i], value[i+1:]
+		if len(port) == 0 {
+			return serrors.InvalidValueForTypeErrorf(str, h, "expected a port after the path")
+		}
+		*h = Hook{Net: &NetAction{URL: fmt.Sprintf("%s://:%s%s", strings.ToUpper(scheme), port, path)}}
+	default:
+		return serrors.InvalidValueForTypeErrorf(str, h, "unknown hook type (%s), expected exec, http or https", scheme)
+	}
+
+	return nil
+}
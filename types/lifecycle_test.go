@@ -0,0 +1,37 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+func TestHook(t *testing.T) {
+	cases := map[string]Hook{
+		`"exec: /bin/drain --timeout 30"`: {Command: []string{"/bin/drain", "--timeout", "30"}},
+		`"http: /quit:8080"`:              {Net: &NetAction{URL: "HTTP://:8080/quit"}},
+		`"https: /v1/quit:8443"`:          {Net: &NetAction{URL: "HTTPS://:8443/v1/quit"}},
+		"command: [cat, /tmp/healthy]":    {Command: []string{"cat", "/tmp/healthy"}},
+	}
+	for s, expected := range cases {
+		hook := Hook{}
+		err := yaml.Unmarshal([]byte(s), &hook)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(hook, expected) {
+			t.Errorf("%s: %s", s, pretty.Diff(expected, hook))
+		}
+	}
+
+	for _, s := range []string{`"exec:"`, `"http: quit"`, `"http: /quit:"`, `"tcp: 8080"`, `"exec: sh -c 'kill -QUIT 1'"`, `'exec: echo "a b"'`} {
+		hook := Hook{}
+		if err := yaml.Unmarshal([]byte(s), &hook); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
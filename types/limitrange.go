@@ -1,7 +1,15 @@
 package types
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
 )
 
 type LimitRangeWrapper struct {
@@ -15,6 +23,7 @@ type LimitRange struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Spec::LimitRangeSpec
 	Limits []LimitRangeItem `json:"limits"`
@@ -24,15 +33,17 @@ type LimitRangeItem struct {
 	// Type of resource that this limit applies to.
 	Type LimitType `json:"kind,omitempty"`
 	// Max usage constraints on this kind by resource name.
-	Max v1.ResourceList `json:"max,omitempty"`
+	// Written with Min as "ranges", e.g. "cpu=100m-2".
+	Max v1.ResourceList `json:"-"`
 	// Min usage constraints on this kind by resource name.
-	Min v1.ResourceList `json:"min,omitempty"`
+	Min v1.ResourceList `json:"-"`
 	// Default resource requirement limit value by resource name
 	//   (if resource limit is omitted)
-	Default v1.ResourceList `json:"default_max,omitempty"`
+	// Written with DefaultRequest as "defaults", e.g. "cpu=50m-100m".
+	Default v1.ResourceList `json:"-"`
 	// default resource requirement request value by resource name
 	// (if resource request is omitted)
-	DefaultRequest v1.ResourceList `json:"default_min,omitempty"`
+	DefaultRequest v1.ResourceList `json:"-"`
 	// MaxLimitRequestRatio represents the max burst for the named resource.
 	MaxLimitRequestRatio v1.ResourceList `json:"max_burst_ratio,omitempty"`
 }
@@ -44,3 +55,151 @@ const (
 	LimitTypeContainer             LimitType = "container"
 	LimitTypePersistentVolumeClaim LimitType = "pvc"
 )
+
+// limitRangeItem avoids recursing into LimitRangeItem's (Un)MarshalJSON.
+type limitRangeItem LimitRangeItem
+
+// compactLimitRangeItem holds the "cpu=100m-2" ranges for Min/Max and DefaultRequest/Default.
+type compactLimitRangeItem struct {
+	limitRangeItem
+
+	Ranges   []string `json:"ranges,omitempty"`
+	Defaults []string `json:"defaults,omitempty"`
+}
+
+func (i *LimitRangeItem) UnmarshalJSON(data []byte) error {
+	compact := compactLimitRangeItem{}
+	err := json.Unmarshal(data, &compact)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "limit range item")
+	}
+
+	*i = LimitRangeItem(compact.limitRangeItem)
+	i.Min, i.Max, err = unmarshalLimitRanges(compact.Ranges)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "ranges")
+	}
+	i.DefaultRequest, i.Default, err = unmarshalLimitRanges(compact.Defaults)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, "defaults")
+	}
+
+	return nil
+}
+
+func (i LimitRangeItem) MarshalJSON() ([]byte, error) {
+	compact := compactLimitRangeItem{
+		limitRangeItem: limitRangeItem(i),
+		Ranges:         marshalLimitRanges(i.Min, i.Max),
+		Defaults:       marshalLimitRanges(i.DefaultRequest, i.Default),
+	}
+
+	b, err := json.Marshal(compact)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, i, "marshalling limit range item")
+	}
+
+	return b, nil
+}
+
+// unmarshalLimitRanges splits ranges like "cpu=100m-2", "cpu=100m-" or "cpu=-2" into low and high resource lists.
+func unmarshalLimitRanges(ranges []string) (v1.ResourceList, v1.ResourceList, error) {
+	var low, high v1.ResourceList
+	for _, r := range ranges {
+		segments := strings.SplitN(r, "=", 2)
+		if len(segments) != 2 || !strings.Contains(segments[1], "-") {
+			return nil, nil, serrors.InvalidValueErrorf(r, "expected 'resource=low-high'")
+		}
+
+		name := v1.ResourceName(segments[0])
+		bounds, err := splitLimitRange(segments[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		for ix, bound := range bounds {
+			if bound == nil {
+				continue
+			}
+
+			list := &low
+			if ix == 1 {
+				list = &high
+			}
+			if *list == nil {
+				*list = v1.ResourceList{}
+			}
+			if _, ok := (*list)[name]; ok {
+				return nil, nil, serrors.InvalidValueErrorf(r, "duplicate range for resource (%s)", name)
+			}
+			(*list)[name] = *bound
+		}
+	}
+
+	return low, high, nil
+}
+
+// splitLimitRange splits "low-high" into its quantities, which are nil if they're left out. Quantities can
+// contain "-" too (e.g. "1e-3" or "-1"), so it splits at the first "-" that leaves a quantity on each side.
+func splitLimitRange(r string) ([]*resource.Quantity, error) {
+	var firstErr error
+	for ix := strings.Index(r, "-"); ix >= 0; {
+		bounds := []*resource.Quantity{nil, nil}
+		var err error
+		for boundIx, bound := range []string{r[:ix], r[ix+1:]} {
+			if len(bound) == 0 {
+				continue
+			}
+
+			quantity, parseErr := resource.ParseQuantity(bound)
+			if parseErr != nil {
+				err = serrors.InvalidValueContextErrorf(parseErr, r, "parsing quantity (%s)", bound)
+				break
+			}
+			bounds[boundIx] = &quantity
+		}
+		if err == nil {
+			return bounds, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		next := strings.Index(r[ix+1:], "-")
+		if next < 0 {
+			break
+		}
+		ix += next + 1
+	}
+	if firstErr == nil {
+		firstErr = serrors.InvalidValueErrorf(r, "expected 'low-high'")
+	}
+
+	return nil, firstErr
+}
+
+func marshalLimitRanges(low, high v1.ResourceList) []string {
+	names := []string{}
+	for name := range low {
+		names = append(names, string(name))
+	}
+	for name := range high {
+		if _, ok := low[name]; !ok {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+
+	ranges := make([]string, len(names))
+	for ix, name := range names {
+		lowStr, highStr := "", ""
+		if quantity, ok := low[v1.ResourceName(name)]; ok {
+			lowStr = quantity.String()
+		}
+		if quantity, ok := high[v1.ResourceName(name)]; ok {
+			highStr = quantity.String()
+		}
+		ranges[ix] = fmt.Sprintf("%s=%s-%s", name, lowStr, highStr)
+	}
+
+	return ranges
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koki/json"
+)
+
+func TestLimitRangeItem(t *testing.T) {
+	str := `{"kind":"container","ranges":["cpu=100m-2","memory=-1Gi"],"defaults":["cpu=200m-"]}`
+	item := LimitRangeItem{}
+	err := json.Unmarshal([]byte(str), &item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := LimitRangeItem{
+		Type:           LimitTypeContainer,
+		Min:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+		Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+		DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")},
+	}
+	if !reflect.DeepEqual(item, expected) {
+		t.Fatalf("unexpected item %#v", item)
+	}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"kind":"container","ranges":["cpu=100m-2","memory=-1Gi"],"defaults":["cpu=200m-"]}` {
+		t.Errorf("unexpected json %s", string(b))
+	}
+
+	// Quantities with a "-" of their own.
+	str = `{"ranges":["cpu=1e-3-2","memory=-1e-3"],"defaults":["cpu=2e-3-"]}`
+	item = LimitRangeItem{}
+	err = json.Unmarshal([]byte(str), &item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = LimitRangeItem{
+		Min:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1e-3")},
+		Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1e-3")},
+		DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2e-3")},
+	}
+	if !reflect.DeepEqual(item, expected) {
+		t.Fatalf("unexpected item %#v", item)
+	}
+
+	for _, str := range []string{`{"ranges":["cpu"]}`, `{"ranges":["cpu=2"]}`, `{"ranges":["cpu=1-x"]}`, `{"ranges":["cpu=1-","cpu=2-"]}`, `{"ranges":["cpu=1e-x-2"]}`} {
+		if err := json.Unmarshal([]byte(str), &LimitRangeItem{}); err == nil {
+			t.Errorf("expected error for %s", str)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package types
 
+import (
+	"reflect"
+)
+
 // Name indicates a string that may contain colons.
 // Escape its colons before joining with other strings (using colon as a separator).
 type Name string
@@ -68,3 +72,23 @@ func SplitAtUnescapedColons(s string) []string {
 
 	return segments
 }
+
+// NameOf finds the name field of a short object, or of the object in a wrapper (e.g. *PodWrapper).
+// It returns nil if there isn't one.
+func NameOf(obj interface{}) *string {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	if _, ok := v.Type().FieldByName("Name"); !ok && v.NumField() == 1 && v.Field(0).Kind() == reflect.Struct {
+		v = v.Field(0)
+	}
+
+	name := v.FieldByName("Name")
+	if !name.IsValid() || name.Kind() != reflect.String || !name.CanSet() {
+		return nil
+	}
+
+	return name.Addr().Interface().(*string)
+}
@@ -40,3 +40,19 @@ func testOneSplit(s string, t *testing.T, segments ...string) {
 		t.Fatalf("actual:\n  %#v\nexpected:\n  %#v", actualSegments, segments)
 	}
 }
+
+func TestNameOf(t *testing.T) {
+	job := &JobWrapper{}
+	if NameOf(job) != &job.Job.Name {
+		t.Error("expected the Job's name")
+	}
+
+	pv := &PersistentVolumeWrapper{}
+	if NameOf(pv) != &pv.PersistentVolume.Name {
+		t.Error("expected the PersistentVolume's name")
+	}
+
+	if NameOf(&struct{ Replicas int }{}) != nil {
+		t.Error("expected no name")
+	}
+}
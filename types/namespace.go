@@ -11,12 +11,31 @@ type Namespace struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
+
+	// PodSecurity is held in the pod-security.kubernetes.io labels.
+	PodSecurity *PodSecurity `json:"pod_security,omitempty"`
 
 	Finalizers []FinalizerName `json:"finalizers,omitempty"`
 
 	Phase NamespacePhase `json:"phase,omitempty"`
 }
 
+// PodSecurity is the Pod Security Standard that each mode of Pod Security admission applies to
+// the Namespace's pods, as "level" or "level:version", e.g. "restricted:v1.25".
+type PodSecurity struct {
+	Enforce string `json:"enforce,omitempty"`
+	Audit   string `json:"audit,omitempty"`
+	Warn    string `json:"warn,omitempty"`
+}
+
+// The Pod Security Standard levels, from most to least permissive.
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
 type NamespacePhase string
 
 const (
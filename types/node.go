@@ -0,0 +1,211 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koki/json"
+	"github.com/koki/short/parser/expressions"
+	serrors "github.com/koki/structurederrors"
+)
+
+type NodeWrapper struct {
+	Node Node `json:"node"`
+}
+
+type Node struct {
+	Version     string            `json:"version,omitempty"`
+	Cluster     string            `json:"cluster,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
+
+	PodCIDR       string           `json:"pod_cidr,omitempty"`
+	ProviderID    string           `json:"provider_id,omitempty"`
+	ExternalID    string           `json:"external_id,omitempty"`
+	Unschedulable bool             `json:"unschedulable,omitempty"`
+	Taints        []Taint          `json:"taints,omitempty"`
+	ConfigSource  *ObjectReference `json:"config,omitempty"`
+
+	NodeStatus `json:",inline"`
+}
+
+type NodeStatus struct {
+	Capacity        v1.ResourceList  `json:"capacity,omitempty"`
+	Allocatable     v1.ResourceList  `json:"allocatable,omitempty"`
+	Phase           NodePhase        `json:"phase,omitempty"`
+	Conditions      []NodeCondition  `json:"condition,omitempty"`
+	Addresses       []NodeAddress    `json:"addresses,omitempty"`
+	KubeletPort     int32            `json:"kubelet_port,omitempty"`
+	Info            *NodeSystemInfo  `json:"info,omitempty"`
+	Images          []ContainerImage `json:"images,omitempty"`
+	VolumesInUse    []string         `json:"volumes_in_use,omitempty"`
+	VolumesAttached []AttachedVolume `json:"volumes_attached,omitempty"`
+}
+
+type NodePhase string
+
+const (
+	NodePending    NodePhase = "pending"
+	NodeRunning    NodePhase = "running"
+	NodeTerminated NodePhase = "terminated"
+)
+
+type TaintEffect string
+
+const (
+	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	TaintEffectNoExecute        TaintEffect = "NoExecute"
+)
+
+// Taint is written as "key=value:Effect" (or "key:Effect").
+// It's written as a struct only if it also records when it was added.
+type Taint struct {
+	Key       string
+	Value     string
+	Effect    TaintEffect
+	TimeAdded *metav1.Time
+}
+
+type taintWithTime struct {
+	Taint     string       `json:"taint"`
+	TimeAdded *metav1.Time `json:"added,omitempty"`
+}
+
+// NodeConditionType is passed through as-is, because node problem detectors add their own condition types.
+type NodeConditionType string
+
+type NodeCondition struct {
+	Type               NodeConditionType `json:"type"`
+	Status             ConditionStatus   `json:"status"`
+	LastHeartbeatTime  metav1.Time       `json:"heartbeat,omitempty"`
+	LastTransitionTime metav1.Time       `json:"last_change,omitempty"`
+	Reason             string            `json:"reason,omitempty"`
+	Message            string            `json:"message,omitempty"`
+}
+
+// NodeAddress is written as "Type:address", e.g. "InternalIP:10.0.0.1".
+type NodeAddress struct {
+	Type    string
+	Address string
+}
+
+type NodeSystemInfo struct {
+	MachineID               string `json:"machine_id,omitempty"`
+	SystemUUID              string `json:"system_uuid,omitempty"`
+	BootID                  string `json:"boot_id,omitempty"`
+	KernelVersion           string `json:"kernel,omitempty"`
+	OSImage                 string `json:"os_image,omitempty"`
+	ContainerRuntimeVersion string `json:"runtime,omitempty"`
+	KubeletVersion          string `json:"kubelet,omitempty"`
+	KubeProxyVersion        string `json:"kube_proxy,omitempty"`
+	OperatingSystem         string `json:"os,omitempty"`
+	Architecture            string `json:"arch,omitempty"`
+}
+
+type ContainerImage struct {
+	Names     []string `json:"names"`
+	SizeBytes int64    `json:"size,omitempty"`
+}
+
+type AttachedVolume struct {
+	Name       string `json:"name"`
+	DevicePath string `json:"device"`
+}
+
+func (t Taint) String() string {
+	expr := expressions.TaintExpr{
+		Key:    t.Key,
+		Effect: string(t.Effect),
+	}
+	if len(t.Value) > 0 {
+		expr.Value = &t.Value
+	}
+
+	return expr.String()
+}
+
+func ParseTaint(s string) (*Taint, error) {
+	expr, err := expressions.ParseTaintExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Key == "*" || len(expr.Effect) == 0 || expr.Seconds != nil {
+		return nil, serrors.InvalidValueErrorf(s, "expected a taint in the form key=value:Effect")
+	}
+
+	taint := &Taint{
+		Key:    expr.Key,
+		Effect: TaintEffect(expr.Effect),
+	}
+	if expr.Value != nil {
+		taint.Value = *expr.Value
+	}
+
+	return taint, nil
+}
+
+func (t *Taint) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err == nil {
+		taint, err := ParseTaint(s)
+		if err != nil {
+			return err
+		}
+		*t = *taint
+		return nil
+	}
+
+	obj := taintWithTime{}
+	err = json.Unmarshal(data, &obj)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), t)
+	}
+	taint, err := ParseTaint(obj.Taint)
+	if err != nil {
+		return err
+	}
+	*t = *taint
+	t.TimeAdded = obj.TimeAdded
+
+	return nil
+}
+
+func (t Taint) MarshalJSON() ([]byte, error) {
+	if t.TimeAdded == nil {
+		return json.Marshal(t.String())
+	}
+
+	return json.Marshal(taintWithTime{
+		Taint:     t.String(),
+		TimeAdded: t.TimeAdded,
+	})
+}
+
+func (a *NodeAddress) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), a)
+	}
+
+	segments := strings.SplitN(s, ":", 2)
+	if len(segments) != 2 || len(segments[0]) == 0 {
+		return serrors.InvalidValueErrorf(s, "expected a node address in the form Type:address")
+	}
+	a.Type = segments[0]
+	a.Address = segments[1]
+
+	return nil
+}
+
+func (a NodeAddress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%s:%s", a.Type, a.Address))
+}
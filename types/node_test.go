@@ -0,0 +1,75 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/koki/short/yaml"
+)
+
+func TestTaintRoundTrip(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want Taint
+	}{
+		{"node-role.kubernetes.io/master:NoSchedule", Taint{Key: "node-role.kubernetes.io/master", Effect: TaintEffectNoSchedule}},
+		{"dedicated=gpu:NoExecute", Taint{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute}},
+		{"spot=true:PreferNoSchedule", Taint{Key: "spot", Value: "true", Effect: TaintEffectPreferNoSchedule}},
+	}
+
+	for _, testCase := range testCases {
+		taint, err := ParseTaint(testCase.in)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(*taint, testCase.want) {
+			t.Errorf("%s: got %#v, want %#v", testCase.in, *taint, testCase.want)
+		}
+		if taint.String() != testCase.in {
+			t.Errorf("%s: didn't round-trip, got %s", testCase.in, taint.String())
+		}
+	}
+
+	for _, invalid := range []string{"dedicated=gpu", "dedicated=gpu:NoRun", ":NoSchedule", "a:b:NoSchedule"} {
+		if _, err := ParseTaint(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestNodeAddressesAndTaintsYAML(t *testing.T) {
+	in := []byte(`taints:
+- dedicated=gpu:NoExecute
+- added: "2024-01-01T00:00:00Z"
+  taint: spot:NoSchedule
+addresses:
+- InternalIP:10.0.0.1
+- InternalIP:fd00::1
+`)
+	node := Node{}
+	err := yaml.Unmarshal(in, &node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(node.Taints) != 2 || node.Taints[1].TimeAdded == nil || node.Taints[1].Key != "spot" {
+		t.Errorf("unexpected taints %#v", node.Taints)
+	}
+	if len(node.Addresses) != 2 || node.Addresses[1] != (NodeAddress{Type: "InternalIP", Address: "fd00::1"}) {
+		t.Errorf("unexpected addresses %#v", node.Addresses)
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTripped := Node{}
+	err = yaml.Unmarshal(out, &roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(node.Taints[0], roundTripped.Taints[0]) || !reflect.DeepEqual(node.Addresses, roundTripped.Addresses) {
+		t.Errorf("node didn't round-trip:\n%s", out)
+	}
+}
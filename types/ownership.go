@@ -0,0 +1,149 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// Ownership is the metadata that garbage collection uses. It's embedded in every short type.
+type Ownership struct {
+	Owners     []OwnerReference `json:"owners,omitempty"`
+	Finalizers []string         `json:"finalizers,omitempty"`
+	// GenerateName is a generateName that can't be written as the name, since it doesn't end in "-".
+	GenerateName string `json:"generate_name,omitempty"`
+}
+
+// OwnerReference is written as "Kind/name@apiVersion", optionally followed by
+// "uid=UID", "controller" and "block-deletion", e.g. "ReplicaSet/web-5c7b8d9f6@apps/v1 uid=0123 controller".
+type OwnerReference struct {
+	Version            string
+	Kind               string
+	Name               string
+	UID                string
+	Controller         *bool
+	BlockOwnerDeletion *bool
+}
+
+type ownershipHolder interface {
+	ownership() *Ownership
+}
+
+func (o *Ownership) ownership() *Ownership {
+	return o
+}
+
+// OwnershipOf finds the Ownership of a short object, or of the object in a wrapper (e.g. *PodWrapper).
+// It returns nil if there isn't one.
+func OwnershipOf(obj interface{}) *Ownership {
+	if holder, ok := obj.(ownershipHolder); ok {
+		return holder.ownership()
+	}
+
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).CanInterface() {
+			continue
+		}
+		if holder, ok := v.Field(i).Addr().Interface().(ownershipHolder); ok {
+			return holder.ownership()
+		}
+	}
+
+	return nil
+}
+
+func (r OwnerReference) String() string {
+	segments := []string{fmt.Sprintf("%s/%s@%s", r.Kind, r.Name, r.Version)}
+	if len(r.UID) > 0 {
+		segments = append(segments, "uid="+r.UID)
+	}
+	segments = append(segments, flagString("controller", r.Controller)...)
+	segments = append(segments, flagString("block-deletion", r.BlockOwnerDeletion)...)
+
+	return strings.Join(segments, " ")
+}
+
+func flagString(name string, val *bool) []string {
+	if val == nil {
+		return nil
+	}
+	if *val {
+		return []string{name}
+	}
+
+	return []string{name + "=false"}
+}
+
+// ParseOwnerReference parses the string form of an OwnerReference.
+func ParseOwnerReference(s string) (*OwnerReference, error) {
+	segments := strings.Fields(s)
+	if len(segments) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected an owner in the form Kind/name@apiVersion")
+	}
+
+	ref := &OwnerReference{}
+	owner := strings.SplitN(segments[0], "@", 2)
+	kindAndName := strings.SplitN(owner[0], "/", 2)
+	if len(owner) != 2 || len(owner[1]) == 0 || len(kindAndName) != 2 || len(kindAndName[0]) == 0 || len(kindAndName[1]) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected an owner in the form Kind/name@apiVersion")
+	}
+	ref.Kind, ref.Name, ref.Version = kindAndName[0], kindAndName[1], owner[1]
+
+	for _, segment := range segments[1:] {
+		keyVal := strings.SplitN(segment, "=", 2)
+		switch keyVal[0] {
+		case "uid":
+			if len(keyVal) != 2 {
+				return nil, serrors.InvalidValueErrorf(s, "expected uid=UID")
+			}
+			ref.UID = keyVal[1]
+		case "controller", "block-deletion":
+			val := true
+			if len(keyVal) == 2 {
+				parsed, err := strconv.ParseBool(keyVal[1])
+				if err != nil {
+					return nil, serrors.InvalidValueContextErrorf(err, s, "expected %s=true or %s=false", keyVal[0], keyVal[0])
+				}
+				val = parsed
+			}
+			if keyVal[0] == "controller" {
+				ref.Controller = &val
+			} else {
+				ref.BlockOwnerDeletion = &val
+			}
+		default:
+			return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected uid=UID, controller or block-deletion", segment)
+		}
+	}
+
+	return ref, nil
+}
+
+func (r *OwnerReference) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), r)
+	}
+
+	ref, err := ParseOwnerReference(s)
+	if err != nil {
+		return err
+	}
+	*r = *ref
+
+	return nil
+}
+
+func (r OwnerReference) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwnerReferenceRoundTrip(t *testing.T) {
+	yes, no := true, false
+	testCases := []struct {
+		in   string
+		want OwnerReference
+	}{
+		{"Deployment/web@apps/v1", OwnerReference{Kind: "Deployment", Name: "web", Version: "apps/v1"}},
+		{"Node/worker-1@v1 uid=6f0c", OwnerReference{Kind: "Node", Name: "worker-1", Version: "v1", UID: "6f0c"}},
+		{"ReplicaSet/web-5c7b8d9f6@apps/v1 uid=0123 controller block-deletion",
+			OwnerReference{Kind: "ReplicaSet", Name: "web-5c7b8d9f6", Version: "apps/v1", UID: "0123", Controller: &yes, BlockOwnerDeletion: &yes}},
+		{"Job/backup@batch/v1 controller=false", OwnerReference{Kind: "Job", Name: "backup", Version: "batch/v1", Controller: &no}},
+	}
+
+	for _, testCase := range testCases {
+		ref, err := ParseOwnerReference(testCase.in)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(*ref, testCase.want) {
+			t.Errorf("%s: got %#v, want %#v", testCase.in, *ref, testCase.want)
+		}
+		if ref.String() != testCase.in {
+			t.Errorf("%s: didn't round-trip, got %s", testCase.in, ref.String())
+		}
+	}
+
+	for _, invalid := range []string{"", "Deployment/web", "web@apps/v1", "Deployment/@apps/v1", "Deployment/web@apps/v1 uid", "Deployment/web@apps/v1 controller=maybe", "Deployment/web@apps/v1 owner"} {
+		if _, err := ParseOwnerReference(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestOwnershipOf(t *testing.T) {
+	wrapper := &ReplicaSetWrapper{}
+	ownership := OwnershipOf(wrapper)
+	if ownership != &wrapper.ReplicaSet.Ownership {
+		t.Errorf("expected the ReplicaSet's Ownership, got %#v", ownership)
+	}
+
+	if OwnershipOf(&struct{ Name string }{}) != nil {
+		t.Error("expected no Ownership")
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/koki/json"
 	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/util/objutil"
 	serrors "github.com/koki/structurederrors"
 )
 
@@ -29,15 +31,22 @@ type PersistentVolumeMeta struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Storage       *resource.Quantity            `json:"storage,omitempty"`
 	AccessModes   *AccessModes                  `json:"modes,omitempty"`
-	Claim         *v1.ObjectReference           `json:"claim,omitempty"`
+	Claim         *ClaimReference               `json:"claim,omitempty"`
 	ReclaimPolicy PersistentVolumeReclaimPolicy `json:"reclaim,omitempty"`
 	StorageClass  string                        `json:"storage_class,omitempty"`
 
 	// comma-separated list of options
-	MountOptions string `json:"mount_opts,omitempty"`
+	MountOptions *MountOptions `json:"mount_opts,omitempty"`
+
+	VolumeMode *PersistentVolumeMode `json:"volume_mode,omitempty"`
+
+	// Nodes the volume can be accessed from. Each entry is a node selector term,
+	// e.g. "kubernetes.io/hostname=node-1,node-2", and a node must match at least one.
+	NodeAffinity []string `json:"node_affinity,omitempty"`
 
 	PersistentVolumeStatus `json:",inline"`
 }
@@ -58,6 +67,13 @@ const (
 	VolumeFailed    PersistentVolumePhase = "failed"
 )
 
+type PersistentVolumeMode string
+
+const (
+	PersistentVolumeBlock      PersistentVolumeMode = "block"
+	PersistentVolumeFilesystem PersistentVolumeMode = "filesystem"
+)
+
 type PersistentVolumeReclaimPolicy string
 
 const (
@@ -178,6 +194,10 @@ type CSIPersistentVolume struct {
 	ReadOnly     bool   `json:"ro,omitempty"`
 }
 
+// KubeReadWriteOncePod is the Kubernetes name of the "rw-pod" access mode,
+// which is newer than the vendored Kubernetes API.
+const KubeReadWriteOncePod v1.PersistentVolumeAccessMode = "ReadWriteOncePod"
+
 // comma-separated list of modes
 type AccessModes struct {
 	Modes []v1.PersistentVolumeAccessMode
@@ -201,6 +221,8 @@ func (a *AccessModes) ToString() (string, error) {
 			modes[i] = "rw"
 		case v1.ReadWriteOnce:
 			modes[i] = "rw-once"
+		case KubeReadWriteOncePod:
+			modes[i] = "rw-pod"
 		default:
 			return "", serrors.InvalidInstanceError(mode)
 		}
@@ -209,24 +231,28 @@ func (a *AccessModes) ToString() (string, error) {
 	return strings.Join(modes, ","), nil
 }
 
+// InitFromString parses a comma-separated list of access modes, e.g. "ro, rw-once".
+// The Kubernetes names (e.g. "ReadWriteOnce") are accepted too. Case and whitespace are ignored.
 func (a *AccessModes) InitFromString(s string) error {
-	modes := strings.Split(s, ",")
-	if len(modes) == 0 {
+	if len(strings.TrimSpace(s)) == 0 {
 		a.Modes = nil
 		return nil
 	}
 
+	modes := strings.Split(s, ",")
 	a.Modes = make([]v1.PersistentVolumeAccessMode, len(modes))
 	for i, mode := range modes {
-		switch mode {
-		case "ro":
+		switch strings.ToLower(strings.TrimSpace(mode)) {
+		case "ro", "readonlymany":
 			a.Modes[i] = v1.ReadOnlyMany
-		case "rw":
+		case "rw", "readwritemany":
 			a.Modes[i] = v1.ReadWriteMany
-		case "rw-once":
+		case "rw-once", "readwriteonce":
 			a.Modes[i] = v1.ReadWriteOnce
+		case "rw-pod", "readwriteoncepod":
+			a.Modes[i] = KubeReadWriteOncePod
 		default:
-			return serrors.InvalidValueErrorf(a, "couldn't parse (%s)", s)
+			return serrors.InvalidValueErrorf(s, "couldn't parse access mode (%s)", mode)
 		}
 	}
 
@@ -257,6 +283,140 @@ func (a *AccessModes) UnmarshalJSON(data []byte) error {
 	return a.InitFromString(str)
 }
 
+// ClaimReference is written as "namespace/name" (or "name") when that's all it has,
+// and in the same form as v1.ObjectReference otherwise.
+type ClaimReference v1.ObjectReference
+
+func (r ClaimReference) isShorthand() bool {
+	return len(r.Name) > 0 && reflect.DeepEqual(r, ClaimReference{Namespace: r.Namespace, Name: r.Name})
+}
+
+func (r ClaimReference) MarshalJSON() ([]byte, error) {
+	if !r.isShorthand() {
+		return json.Marshal(v1.ObjectReference(r))
+	}
+
+	if len(r.Namespace) > 0 {
+		return json.Marshal(r.Namespace + "/" + r.Name)
+	}
+
+	return json.Marshal(r.Name)
+}
+
+func (r *ClaimReference) UnmarshalJSON(data []byte) error {
+	str := ""
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		ref := v1.ObjectReference{}
+		err = json.Unmarshal(data, &ref)
+		if err != nil {
+			return serrors.InvalidValueForTypeContextError(err, string(data), r)
+		}
+		*r = ClaimReference(ref)
+		return nil
+	}
+
+	segments := strings.Split(str, "/")
+	switch {
+	case len(segments) == 1 && len(segments[0]) > 0:
+		*r = ClaimReference{Name: segments[0]}
+	case len(segments) == 2 && len(segments[0]) > 0 && len(segments[1]) > 0:
+		*r = ClaimReference{Namespace: segments[0], Name: segments[1]}
+	default:
+		return serrors.InvalidValueErrorf(str, "expected a claim in the form namespace/name or name")
+	}
+
+	return nil
+}
+
+// MountOptions is written as a comma-separated list, or as a list of strings.
+// In the comma-separated form, "\," is a literal comma and "\\" is a literal backslash.
+type MountOptions struct {
+	Options []string
+}
+
+func (m *MountOptions) ToString() string {
+	options := make([]string, len(m.Options))
+	for i, option := range m.Options {
+		options[i] = strings.NewReplacer(`\`, `\\`, ",", `\,`).Replace(option)
+	}
+
+	return strings.Join(options, ",")
+}
+
+func (m *MountOptions) InitFromString(s string) error {
+	options := []string{}
+	option := []rune{}
+	isEscaped := false
+	for _, r := range s {
+		switch {
+		case isEscaped:
+			option = append(option, r)
+			isEscaped = false
+		case r == '\\':
+			isEscaped = true
+		case r == ',':
+			options = append(options, string(option))
+			option = []rune{}
+		default:
+			option = append(option, r)
+		}
+	}
+	if isEscaped {
+		return serrors.InvalidValueErrorf(s, "trailing escape character in mount options")
+	}
+	options = append(options, string(option))
+
+	return m.InitFromList(options)
+}
+
+// InitFromList trims whitespace from each option and removes duplicates.
+func (m *MountOptions) InitFromList(options []string) error {
+	m.Options = nil
+	seen := map[string]bool{}
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if len(option) == 0 {
+			if len(options) == 1 {
+				return nil
+			}
+			return serrors.InvalidValueErrorf(options, "empty mount option")
+		}
+		if seen[option] {
+			continue
+		}
+		seen[option] = true
+		m.Options = append(m.Options, option)
+	}
+
+	return nil
+}
+
+func (m MountOptions) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(m.ToString())
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, m, "marshalling to JSON")
+	}
+
+	return b, nil
+}
+
+func (m *MountOptions) UnmarshalJSON(data []byte) error {
+	str := ""
+	err := json.Unmarshal(data, &str)
+	if err == nil {
+		return m.InitFromString(str)
+	}
+
+	options := []string{}
+	err = json.Unmarshal(data, &options)
+	if err != nil {
+		return serrors.InvalidValueErrorf(string(data), "expected a string or a list of strings for mount options")
+	}
+
+	return m.InitFromList(options)
+}
+
 func (v *PersistentVolume) UnmarshalJSON(data []byte) error {
 	err := json.Unmarshal(data, &v.PersistentVolumeSource)
 	if err != nil {
@@ -282,31 +442,35 @@ func (v PersistentVolume) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
-	metaObj := map[string]interface{}{}
-	err = json.Unmarshal(b, &metaObj)
-	if err != nil {
-		return nil, serrors.InvalidValueForTypeContextErrorf(err, string(b), v.PersistentVolumeMeta, "converting metadata to dictionary")
-	}
-
-	sourceObj := map[string]interface{}{}
-	err = json.Unmarshal(bb, &sourceObj)
-	if err != nil {
-		return nil, serrors.InvalidValueForTypeContextErrorf(err, string(bb), v.PersistentVolumeSource, "converting volume source to dictionary")
-	}
-
 	// Merge metadata with volume-source
-	for key, val := range metaObj {
-		sourceObj[key] = val
-	}
-
-	result, err := json.Marshal(sourceObj)
+	result, err := objutil.MergeJSONObjects(bb, b)
 	if err != nil {
-		return nil, serrors.InvalidValueForTypeContextErrorf(err, result, v, "marshalling PersistentVolume-as-dictionary to JSON")
+		return nil, serrors.InvalidInstanceContextErrorf(err, v, "merging metadata with volume source")
 	}
 
 	return result, nil
 }
 
+// persistentVolumeSelectorFields names the selector segments of each persistent volume type.
+// See volumeSelectorFields.
+var persistentVolumeSelectorFields = map[string][]string{
+	VolumeTypeGcePD:     volumeSelectorFields[VolumeTypeGcePD],
+	VolumeTypeAwsEBS:    volumeSelectorFields[VolumeTypeAwsEBS],
+	VolumeTypeHostPath:  volumeSelectorFields[VolumeTypeHostPath],
+	VolumeTypeNFS:       volumeSelectorFields[VolumeTypeNFS],
+	VolumeTypeCinder:    volumeSelectorFields[VolumeTypeCinder],
+	VolumeTypeFlocker:   volumeSelectorFields[VolumeTypeFlocker],
+	VolumeTypeFlex:      volumeSelectorFields[VolumeTypeFlex],
+	VolumeTypeGlusterfs: volumeSelectorFields[VolumeTypeGlusterfs],
+	VolumeTypeVsphere:   volumeSelectorFields[VolumeTypeVsphere],
+	VolumeTypeQuobyte:   volumeSelectorFields[VolumeTypeQuobyte],
+	VolumeTypePhotonPD:  volumeSelectorFields[VolumeTypePhotonPD],
+	VolumeTypePortworx:  volumeSelectorFields[VolumeTypePortworx],
+	VolumeTypeScaleIO:   volumeSelectorFields[VolumeTypeScaleIO],
+	VolumeTypeStorageOS: volumeSelectorFields[VolumeTypeStorageOS],
+	VolumeTypeCSI:       {"handle"},
+}
+
 func (v *PersistentVolumeSource) UnmarshalJSON(data []byte) error {
 	var err error
 	obj := map[string]interface{}{}
@@ -315,20 +479,26 @@ func (v *PersistentVolumeSource) UnmarshalJSON(data []byte) error {
 		return serrors.InvalidValueErrorf(string(data), "expected dictionary for persistent volume")
 	}
 
-	var selector []string
+	volType, err := jsonutil.GetStringEntry(obj, "vol_type")
+	if err != nil {
+		return err
+	}
+
+	selector, err := unmarshalNamedVolumeSelector(obj, persistentVolumeSelectorFields[volType])
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, volType)
+	}
 	if val, ok := obj["vol_id"]; ok {
+		if len(selector) > 0 {
+			return serrors.InvalidValueErrorf(string(data), "expected either \"vol_id\" or named selector fields, not both")
+		}
 		if volName, ok := val.(string); ok {
-			selector = strings.Split(volName, ":")
+			selector = SplitVolumeSelector(volName)
 		} else {
 			return serrors.InvalidValueErrorf(string(data), "expected string for key \"vol_id\"")
 		}
 	}
 
-	volType, err := jsonutil.GetStringEntry(obj, "vol_type")
-	if err != nil {
-		return err
-	}
-
 	return v.Unmarshal(obj, volType, selector)
 }
 
@@ -345,7 +515,7 @@ func (v *PersistentVolumeSource) Unmarshal(obj map[string]interface{}, volType s
 		return v.HostPath.Unmarshal(selector)
 	case VolumeTypeGlusterfs:
 		v.Glusterfs = &GlusterfsVolume{}
-		return v.Glusterfs.Unmarshal(obj, selector)
+		return v.Glusterfs.Unmarshal(selector)
 	case VolumeTypeNFS:
 		v.NFS = &NFSVolume{}
 		return v.NFS.Unmarshal(selector)
@@ -360,7 +530,7 @@ func (v *PersistentVolumeSource) Unmarshal(obj map[string]interface{}, volType s
 		return v.FibreChannel.Unmarshal(obj, selector)
 	case VolumeTypeFlocker:
 		v.Flocker = &FlockerVolume{}
-		return v.Flocker.Unmarshal(selector)
+		return v.Flocker.Unmarshal(obj, selector)
 	case VolumeTypeFlex:
 		v.Flex = &FlexVolume{}
 		return v.Flex.Unmarshal(obj, selector)
@@ -483,17 +653,7 @@ func (v PersistentVolumeSource) MarshalJSON() ([]byte, error) {
 		return nil, serrors.InvalidInstanceErrorf(v, "empty volume definition")
 	}
 
-	if len(marshalledVolume.ExtraFields) == 0 {
-		marshalledVolume.ExtraFields = map[string]interface{}{}
-	}
-
-	obj := marshalledVolume.ExtraFields
-	obj["vol_type"] = marshalledVolume.Type
-	if len(marshalledVolume.Selector) > 0 {
-		obj["vol_id"] = strings.Join(marshalledVolume.Selector, ":")
-	}
-
-	return json.Marshal(obj)
+	return marshalledVolume.marshalObject(persistentVolumeSelectorFields[marshalledVolume.Type])
 }
 
 var secretRefRegexp = regexp.MustCompile(`^(.*):([^:]*)`)
@@ -545,7 +705,7 @@ func (s *ISCSIPersistentVolume) Unmarshal(obj map[string]interface{}, selector [
 }
 
 func (s ISCSIPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeISCSI)
 	}
@@ -570,7 +730,7 @@ func (s *RBDPersistentVolume) Unmarshal(obj map[string]interface{}, selector []s
 }
 
 func (s RBDPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeRBD)
 	}
@@ -625,7 +785,7 @@ func (s *CephFSPersistentVolume) Unmarshal(obj map[string]interface{}, selector
 }
 
 func (s CephFSPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeCephFS)
 	}
@@ -650,7 +810,7 @@ func (s *AzureFilePersistentVolume) Unmarshal(obj map[string]interface{}, select
 }
 
 func (s AzureFilePersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeAzureFile)
 	}
@@ -676,7 +836,7 @@ func (s *ScaleIOPersistentVolume) Unmarshal(obj map[string]interface{}, selector
 }
 
 func (s ScaleIOPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeScaleIO)
 	}
@@ -702,7 +862,7 @@ func (s *LocalVolume) Unmarshal(obj map[string]interface{}, selector []string) e
 }
 
 func (s LocalVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeLocal)
 	}
@@ -728,7 +888,7 @@ func (s *StorageOSPersistentVolume) Unmarshal(obj map[string]interface{}, select
 }
 
 func (s StorageOSPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeStorageOS)
 	}
@@ -755,7 +915,7 @@ func (s *CSIPersistentVolume) Unmarshal(obj map[string]interface{}, selector []s
 }
 
 func (s CSIPersistentVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeCSI)
 	}
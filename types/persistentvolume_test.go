@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/kr/pretty"
 
+	"github.com/koki/json"
 	"github.com/koki/short/yaml"
 	serrors "github.com/koki/structurederrors"
 )
@@ -255,13 +257,13 @@ func testPersistentVolumeSource(v PersistentVolumeSource, t *testing.T) {
 					v1.ReadWriteOnce,
 				},
 			},
-			Claim: &v1.ObjectReference{
+			Claim: &ClaimReference{
 				Name:      "claimName",
 				Namespace: "claimNamespace",
 			},
 			ReclaimPolicy: PersistentVolumeReclaimRecycle,
 			StorageClass:  "storageClass",
-			MountOptions:  "option 1,option 2,option 3",
+			MountOptions:  &MountOptions{Options: []string{"option 1", "option 2", "option 3"}},
 			PersistentVolumeStatus: PersistentVolumeStatus{
 				Phase:   VolumeAvailable,
 				Message: "user-friendly message about the status",
@@ -296,3 +298,141 @@ func testPersistentVolumeSource(v PersistentVolumeSource, t *testing.T) {
 		return
 	}
 }
+
+func TestAccessModes(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want []v1.PersistentVolumeAccessMode
+		out  string
+	}{
+		{"rw-once", []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}, "rw-once"},
+		{"ro, rw", []v1.PersistentVolumeAccessMode{v1.ReadOnlyMany, v1.ReadWriteMany}, "ro,rw"},
+		{"ReadWriteOnce,ReadOnlyMany", []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadOnlyMany}, "rw-once,ro"},
+		{" RW-Pod ", []v1.PersistentVolumeAccessMode{KubeReadWriteOncePod}, "rw-pod"},
+		{"readwriteoncepod", []v1.PersistentVolumeAccessMode{KubeReadWriteOncePod}, "rw-pod"},
+		{"", nil, ""},
+	}
+
+	for _, testCase := range testCases {
+		modes := AccessModes{}
+		err := modes.InitFromString(testCase.in)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(modes.Modes, testCase.want) {
+			t.Errorf("%s: got %v, want %v", testCase.in, modes.Modes, testCase.want)
+		}
+		out, err := modes.ToString()
+		if err != nil || out != testCase.out {
+			t.Errorf("%s: got %s (%v), want %s", testCase.in, out, err, testCase.out)
+		}
+	}
+
+	for _, invalid := range []string{"rw-twice", "ro,,rw", "read-write"} {
+		if err := (&AccessModes{}).InitFromString(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestMountOptions(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want []string
+		out  string
+	}{
+		{`"hard,nfsvers=4.1"`, []string{"hard", "nfsvers=4.1"}, `hard,nfsvers=4.1`},
+		{`"hard, nfsvers=4.1, hard"`, []string{"hard", "nfsvers=4.1"}, `hard,nfsvers=4.1`},
+		{`"context=system_u:object_r:nfs_t\\,s0,ro"`, []string{"context=system_u:object_r:nfs_t,s0", "ro"}, `context=system_u:object_r:nfs_t\,s0,ro`},
+		{`["hard", "context=a,b", "hard"]`, []string{"hard", "context=a,b"}, `hard,context=a\,b`},
+	}
+
+	for _, testCase := range testCases {
+		options := MountOptions{}
+		err := yaml.Unmarshal([]byte(testCase.in), &options)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(options.Options, testCase.want) {
+			t.Errorf("%s: got %#v, want %#v", testCase.in, options.Options, testCase.want)
+		}
+		if options.ToString() != testCase.out {
+			t.Errorf("%s: got %s, want %s", testCase.in, options.ToString(), testCase.out)
+		}
+
+		roundTripped := MountOptions{}
+		if err := roundTripped.InitFromString(options.ToString()); err != nil || !reflect.DeepEqual(roundTripped, options) {
+			t.Errorf("%s: didn't round-trip, got %#v (%v)", testCase.in, roundTripped, err)
+		}
+	}
+
+	for _, invalid := range []string{"hard,", "hard,,ro", `hard\`} {
+		if err := (&MountOptions{}).InitFromString(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestClaimReference(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want ClaimReference
+		out  string
+	}{
+		{`"storage/data-0"`, ClaimReference{Namespace: "storage", Name: "data-0"}, `"storage/data-0"`},
+		{`"data-0"`, ClaimReference{Name: "data-0"}, `"data-0"`},
+		{`{"namespace": "storage", "name": "data-0"}`, ClaimReference{Namespace: "storage", Name: "data-0"}, `"storage/data-0"`},
+		{`{"kind": "PersistentVolumeClaim", "namespace": "storage", "name": "data-0", "uid": "0123"}`,
+			ClaimReference{Kind: "PersistentVolumeClaim", Namespace: "storage", Name: "data-0", UID: "0123"},
+			`{"kind":"PersistentVolumeClaim","namespace":"storage","name":"data-0","uid":"0123"}`},
+	}
+
+	for _, testCase := range testCases {
+		ref := ClaimReference{}
+		err := json.Unmarshal([]byte(testCase.in), &ref)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(ref, testCase.want) {
+			t.Errorf("%s: got %#v, want %#v", testCase.in, ref, testCase.want)
+		}
+		b, err := json.Marshal(ref)
+		if err != nil || string(b) != testCase.out {
+			t.Errorf("%s: got %s (%v), want %s", testCase.in, string(b), err, testCase.out)
+		}
+	}
+
+	for _, invalid := range []string{`""`, `"a/b/c"`, `"/data-0"`, `"storage/"`} {
+		if err := json.Unmarshal([]byte(invalid), &ClaimReference{}); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func BenchmarkPersistentVolumeMarshal(b *testing.B) {
+	storage := resource.MustParse("10Gi")
+	v := PersistentVolume{
+		PersistentVolumeMeta: PersistentVolumeMeta{
+			Version:       "v1",
+			Name:          "pv-0",
+			Labels:        map[string]string{"app": "db", "tier": "storage"},
+			Storage:       &storage,
+			AccessModes:   &AccessModes{Modes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}},
+			Claim:         &ClaimReference{Namespace: "default", Name: "data"},
+			ReclaimPolicy: PersistentVolumeReclaimRetain,
+			StorageClass:  "ssd",
+		},
+		PersistentVolumeSource: kokiPersistentRBDVolume0,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := json.Marshal(v)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -15,6 +15,7 @@ type PersistentVolumeClaim struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	StorageClass *string                      `json:"storage_class,omitempty"`
 	Volume       string                       `json:"volume,omitempty"`
@@ -35,6 +36,8 @@ const (
 	ReadWriteOnce PersistentVolumeAccessMode = "rw_once"
 	ReadOnlyMany  PersistentVolumeAccessMode = "ro_many"
 	ReadWriteMany PersistentVolumeAccessMode = "rw_many"
+	// ReadWriteOncePod can be mounted read/write by a single pod.
+	ReadWriteOncePod PersistentVolumeAccessMode = "rw_pod"
 )
 
 type PersistentVolumeClaimStatus struct {
@@ -21,6 +21,7 @@ type Pod struct {
 	Reason     string         `json:"reason,omitempty"`
 
 	PodTemplateMeta `json:",inline"`
+	Ownership       `json:",inline"`
 	PodTemplate     `json:",inline"`
 }
 
@@ -45,6 +46,9 @@ type PodTemplate struct {
 	HostMode               []HostMode        `json:"host_mode,omitempty"`
 	FSGID                  *int64            `json:"fs_gid,omitempty"`
 	GIDs                   []int64           `json:"gids,omitempty"`
+	Seccomp                string            `json:"seccomp,omitempty"`
+	Sysctls                []string          `json:"sysctls,omitempty"`
+	UnsafeSysctls          []string          `json:"unsafe_sysctls,omitempty"`
 	Registries             []string          `json:"registry_secrets,omitempty"`
 	Hostname               string            `json:"hostname,omitempty"`
 	Affinity               []Affinity        `json:"affinity,omitempty"`
@@ -17,6 +17,7 @@ type PodDisruptionBudget struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	MaxEvictionsAllowed *floatstr.FloatOrString `json:"max_evictions,omitempty"`
 	MinPodsRequired     *floatstr.FloatOrString `json:"min_pods,omitempty"`
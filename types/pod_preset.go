@@ -11,6 +11,7 @@ type PodPreset struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Selector in ReplicaSet can express more complex rules than just matching
 	// pod labels, so it needs its own field (unlike in ReplicationController).
@@ -11,6 +11,7 @@ type PodSecurityPolicy struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Privileged          bool     `json:"privileged,omitempty"`
 	AllowCapabilities   []string `json:"cap_allow,omitempty"`
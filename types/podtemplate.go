@@ -13,6 +13,7 @@ type PodTemplateResource struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	TemplateMetadata PodTemplateMeta `json:"pod_meta,omitempty"`
 	PodTemplate      `json:",inline"`
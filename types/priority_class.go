@@ -11,8 +11,9 @@ type PriorityClass struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Value         int32  `json:"priority,omitempty"`
-	GlobalDefault bool   `json:"default",omitempty`
+	GlobalDefault bool   `json:"default,omitempty"`
 	Description   string `json:"description,omitempty"`
 }
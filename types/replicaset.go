@@ -18,6 +18,7 @@ type ReplicaSet struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Replicas        *int32 `json:"replicas,omitempty"`
 	MinReadySeconds int32  `json:"ready_seconds,omitempty"`
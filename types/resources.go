@@ -1,5 +1,12 @@
 package types
 
+import (
+	"strconv"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
 type CPU struct {
 	Min string `json:"min,omitempty"`
 	Max string `json:"max,omitempty"`
@@ -9,3 +16,42 @@ type Mem struct {
 	Min string `json:"min,omitempty"`
 	Max string `json:"max,omitempty"`
 }
+
+// ResourceBounds are the request (min) and limit (max) of a resource other than cpu and memory,
+// e.g. "ephemeral-storage", "hugepages-2Mi" or "nvidia.com/gpu".
+type ResourceBounds struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+// UnmarshalJSON accepts numbers as well as strings, e.g. "max: 1" for one GPU.
+func (b *ResourceBounds) UnmarshalJSON(data []byte) error {
+	fields := map[string]interface{}{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), b)
+	}
+
+	for key, val := range fields {
+		var quantity string
+		switch val := val.(type) {
+		case string:
+			quantity = val
+		case float64:
+			quantity = strconv.FormatFloat(val, 'f', -1, 64)
+		default:
+			return serrors.InvalidValueForTypeErrorf(val, b, "expected a quantity for %s", key)
+		}
+
+		switch key {
+		case "min":
+			b.Min = quantity
+		case "max":
+			b.Max = quantity
+		default:
+			return serrors.InvalidValueForTypeErrorf(key, b, "unexpected field, expected min or max")
+		}
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ type Role struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Rules []PolicyRule `json:"rules"`
 }
@@ -30,6 +31,7 @@ type RoleBinding struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// Subjects holds references to the objects the role applies to.
 	Subjects []Subject `json:"subjects"`
@@ -38,4 +40,3 @@ type RoleBinding struct {
 	// If the RoleRef cannot be resolved, the Authorizer must return an error.
 	RoleRef RoleRef `json:"role"`
 }
-
@@ -11,6 +11,7 @@ type Secret struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 	StringData  map[string]string `json:"string_data,omitempty"`
 	Data        map[string][]byte `json:"data,omitempty"`
 	SecretType  SecretType        `json:"type,omitempty"`
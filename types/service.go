@@ -19,6 +19,7 @@ type Service struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	// ExternalName services only.
 	ExternalName string `json:"cname,omitempty"`
@@ -11,6 +11,7 @@ type ServiceAccount struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Secrets          []ObjectReference `json:"secrets,omitempty"`
 	ImagePullSecrets []string          `json:"registry_secrets,omitempty"`
@@ -11,6 +11,7 @@ type StatefulSet struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Replicas  *int32 `json:"replicas,omitempty"`
 	OnDelete  bool   `json:"replace_on_delete,omitempty"`
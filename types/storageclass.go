@@ -11,6 +11,7 @@ type StorageClass struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 
 	Provisioner          string                         `json:"provisioner,omitempty"`
 	Parameters           map[string]string              `json:"params, omitempty"`
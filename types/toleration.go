@@ -1,8 +1,33 @@
 package types
 
+import (
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
 type Selector string
 
+// Toleration can also be written as just its selector, e.g. "dedicated=gpu:NoExecute:300".
 type Toleration struct {
 	ExpiryAfter *int64 `json:"expiry_after,omitempty"`
 	Selector    `json:"selector"`
 }
+
+type toleration Toleration
+
+func (t *Toleration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err == nil {
+		t.Selector = Selector(s)
+		t.ExpiryAfter = nil
+		return nil
+	}
+
+	err = json.Unmarshal(data, (*toleration)(t))
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), t)
+	}
+
+	return nil
+}
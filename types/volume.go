@@ -13,6 +13,7 @@ import (
 
 	"github.com/koki/json"
 	"github.com/koki/json/jsonutil"
+	"github.com/koki/short/util/objutil"
 	serrors "github.com/koki/structurederrors"
 )
 
@@ -119,6 +120,10 @@ type GcePDVolume struct {
 	FSType    string `json:"fs,omitempty"`
 	Partition int32  `json:"partition,omitempty"`
 	ReadOnly  bool   `json:"ro,omitempty"`
+
+	// Zones are the replica zones of a regional disk. They only apply to persistent volumes,
+	// where they're written to the failure-domain.beta.kubernetes.io/zone label.
+	Zones []string `json:"zones,omitempty"`
 }
 
 type AwsEBSVolume struct {
@@ -193,6 +198,8 @@ type FlexVolume struct {
 
 type FlockerVolume struct {
 	DatasetUUID string `json:"-"`
+	// DatasetName is deprecated in favor of DatasetUUID.
+	DatasetName string `json:"dataset_name,omitempty"`
 }
 
 type GlusterfsVolume struct {
@@ -402,7 +409,7 @@ func (v *Volume) UnmarshalJSON(data []byte) error {
 	str := ""
 	err = json.Unmarshal(data, &str)
 	if err == nil {
-		segments := strings.Split(str, ":")
+		segments := SplitVolumeSelector(str)
 		return v.Unmarshal(nil, segments[0], segments[1:])
 	}
 
@@ -412,18 +419,27 @@ func (v *Volume) UnmarshalJSON(data []byte) error {
 		return serrors.InvalidValueErrorf(string(data), "expected either string or dictionary")
 	}
 
-	selector := []string{}
+	volType, err := jsonutil.GetStringEntry(obj, "vol_type")
+	if err != nil {
+		return err
+	}
+
+	selector, err := unmarshalNamedVolumeSelector(obj, volumeSelectorFields[volType])
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, volType)
+	}
 	if val, ok := obj["vol_id"]; ok {
+		if len(selector) > 0 {
+			return serrors.InvalidValueErrorf(string(data), "expected either \"vol_id\" or named selector fields, not both")
+		}
 		if volName, ok := val.(string); ok {
 			selector = append(selector, volName)
 		} else {
 			return serrors.InvalidValueErrorf(string(data), "expected string for key \"vol_id\"")
 		}
 	}
-
-	volType, err := jsonutil.GetStringEntry(obj, "vol_type")
-	if err != nil {
-		return err
+	if selector == nil {
+		selector = []string{}
 	}
 
 	return v.Unmarshal(obj, volType, selector)
@@ -460,10 +476,10 @@ func (v *Volume) Unmarshal(obj map[string]interface{}, volType string, selector
 		return v.Flex.Unmarshal(obj, selector)
 	case VolumeTypeFlocker:
 		v.Flocker = &FlockerVolume{}
-		return v.Flocker.Unmarshal(selector)
+		return v.Flocker.Unmarshal(obj, selector)
 	case VolumeTypeGlusterfs:
 		v.Glusterfs = &GlusterfsVolume{}
-		return v.Glusterfs.Unmarshal(obj, selector)
+		return v.Glusterfs.Unmarshal(selector)
 	case VolumeTypeISCSI:
 		v.ISCSI = &ISCSIVolume{}
 		return v.ISCSI.Unmarshal(obj, selector)
@@ -508,9 +524,31 @@ func (v *Volume) Unmarshal(obj map[string]interface{}, volType string, selector
 }
 
 type MarshalledVolume struct {
-	Type        string
-	Selector    []string
-	ExtraFields map[string]interface{}
+	Type     string
+	Selector []string
+	// ExtraFields are the fields that aren't in the selector, as a JSON object.
+	ExtraFields []byte
+}
+
+// hasExtraFields is true if any fields aren't in the selector.
+func (v *MarshalledVolume) hasExtraFields() bool {
+	return len(v.ExtraFields) > 0 && string(v.ExtraFields) != "{}"
+}
+
+// marshalObject writes the volume as a JSON object, with its type and selector fields merged into
+// its extra fields as they are, instead of decoding them into a map and encoding it again.
+func (v *MarshalledVolume) marshalObject(selectorFields []string) ([]byte, error) {
+	obj := map[string]interface{}{"vol_type": v.Type}
+	marshalVolumeSelector(obj, selectorFields, v.Selector)
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !v.hasExtraFields() {
+		return b, nil
+	}
+
+	return objutil.MergeJSONObjects(v.ExtraFields, b)
 }
 
 func (v Volume) MarshalJSON() ([]byte, error) {
@@ -606,19 +644,13 @@ func (v Volume) MarshalJSON() ([]byte, error) {
 		return nil, serrors.InvalidInstanceErrorf(v, "empty volume definition")
 	}
 
-	if len(marshalledVolume.ExtraFields) == 0 {
+	if !marshalledVolume.hasExtraFields() && isColonSafe(marshalledVolume.Selector) {
 		segments := []string{marshalledVolume.Type}
 		segments = append(segments, marshalledVolume.Selector...)
 		return json.Marshal(strings.Join(segments, ":"))
 	}
 
-	obj := marshalledVolume.ExtraFields
-	obj["vol_type"] = marshalledVolume.Type
-	if len(marshalledVolume.Selector) > 0 {
-		obj["vol_id"] = strings.Join(marshalledVolume.Selector, ":")
-	}
-
-	return json.Marshal(obj)
+	return marshalledVolume.marshalObject(volumeSelectorFields[marshalledVolume.Type])
 }
 
 func (s *HostPathVolume) Unmarshal(selector []string) error {
@@ -640,7 +672,8 @@ func (s *HostPathVolume) Unmarshal(selector []string) error {
 		case HostPathCharDev:
 		case HostPathBlockDev:
 		default:
-			return serrors.InvalidValueErrorf(hostPathType, "invalid 'vol_type' selector for %s", VolumeTypeHostPath)
+			return serrors.InvalidValueErrorf(hostPathType, "invalid type for %s, expected one of %s, %s, %s, %s, %s, %s or %s", VolumeTypeHostPath,
+				HostPathDirectoryOrCreate, HostPathDirectory, HostPathFileOrCreate, HostPathFile, HostPathSocket, HostPathCharDev, HostPathBlockDev)
 		}
 
 		s.Type = hostPathType
@@ -664,38 +697,83 @@ func (s HostPathVolume) Marshal() (*MarshalledVolume, error) {
 
 func (v *Volume) UnmarshalEmptyDirVolume(obj map[string]interface{}, selector []string) error {
 	source := EmptyDirVolume{}
-	if len(selector) > 0 {
-		return serrors.InvalidValueErrorf(selector, "expected zero selector segments for %s", VolumeTypeEmptyDir)
-	}
-
 	err := jsonutil.UnmarshalMap(obj, &source)
 	if err != nil {
 		return serrors.ContextualizeErrorf(err, VolumeTypeEmptyDir)
 	}
 
+	if len(selector) > 0 {
+		if len(source.Medium) > 0 || source.SizeLimit != nil {
+			return serrors.InvalidValueErrorf(selector, "expected either selector segments or medium and max_size for %s, not both", VolumeTypeEmptyDir)
+		}
+		err = source.unmarshalSelector(selector)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, VolumeTypeEmptyDir)
+		}
+	}
+
 	v.EmptyDir = &source
 	return nil
 }
 
+// unmarshalSelector reads the medium and size limit from a selector, e.g. "memory:1Gi" or "memory,1Gi".
+func (s *EmptyDirVolume) unmarshalSelector(selector []string) error {
+	segments := []string{}
+	for _, segment := range selector {
+		for _, subsegment := range strings.Split(segment, ",") {
+			segments = append(segments, strings.TrimSpace(subsegment))
+		}
+	}
+
+	for _, segment := range segments {
+		switch StorageMedium(segment) {
+		case StorageMediumMemory, StorageMediumHugePages:
+			if len(s.Medium) > 0 {
+				return serrors.InvalidValueErrorf(selector, "expected at most one medium")
+			}
+			s.Medium = StorageMedium(segment)
+		default:
+			if s.SizeLimit != nil {
+				return serrors.InvalidValueErrorf(selector, "expected at most one size limit")
+			}
+			quantity, err := resource.ParseQuantity(segment)
+			if err != nil {
+				return serrors.InvalidValueContextErrorf(err, segment, "expected a medium (memory or huge-pages) or a size limit")
+			}
+			s.SizeLimit = &quantity
+		}
+	}
+
+	return nil
+}
+
 func (s EmptyDirVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
-	if err != nil {
-		return nil, serrors.ContextualizeErrorf(err, VolumeTypeEmptyDir)
+	selector := []string{}
+	if len(s.Medium) > 0 {
+		selector = append(selector, string(s.Medium))
+	}
+	if s.SizeLimit != nil {
+		selector = append(selector, s.SizeLimit.String())
 	}
 
 	return &MarshalledVolume{
-		Type:        VolumeTypeEmptyDir,
-		ExtraFields: obj,
+		Type:     VolumeTypeEmptyDir,
+		Selector: selector,
 	}, nil
 }
 
 func (s *GcePDVolume) Unmarshal(obj map[string]interface{}, selector []string) error {
-	if len(selector) != 1 {
-		return serrors.InvalidValueErrorf(selector, "expected 1 selector segment (disk name) for %s", VolumeTypeGcePD)
+	if len(selector) == 0 {
+		return serrors.InvalidValueErrorf(selector, "expected a disk name, then optionally fs type, partition and %s segments for %s", SelectorSegmentReadOnly, VolumeTypeGcePD)
 	}
 	s.PDName = selector[0]
 
-	err := jsonutil.UnmarshalMap(obj, &s)
+	err := jsonutil.UnmarshalMap(obj, s)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, VolumeTypeGcePD)
+	}
+
+	err = unmarshalDiskSelector(selector[1:], &s.FSType, &s.Partition, &s.ReadOnly)
 	if err != nil {
 		return serrors.ContextualizeErrorf(err, VolumeTypeGcePD)
 	}
@@ -704,29 +782,41 @@ func (s *GcePDVolume) Unmarshal(obj map[string]interface{}, selector []string) e
 }
 
 func (s GcePDVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
-	if err != nil {
-		return nil, serrors.ContextualizeErrorf(err, VolumeTypeGcePD)
-	}
-
 	if len(s.PDName) == 0 {
 		return nil, serrors.InvalidInstanceErrorf(&s, "selector must contain disk name")
 	}
 
+	selector, ok := marshalDiskSelector(s.PDName, s.FSType, s.Partition, s.ReadOnly)
+	extra := s
+	if ok {
+		extra.FSType, extra.Partition, extra.ReadOnly = "", 0, false
+	} else {
+		selector = []string{s.PDName}
+	}
+	obj, err := json.Marshal(&extra)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, VolumeTypeGcePD)
+	}
+
 	return &MarshalledVolume{
 		Type:        VolumeTypeGcePD,
-		Selector:    []string{s.PDName},
+		Selector:    selector,
 		ExtraFields: obj,
 	}, nil
 }
 
 func (s *AwsEBSVolume) Unmarshal(obj map[string]interface{}, selector []string) error {
-	if len(selector) != 1 {
-		return serrors.InvalidValueErrorf(selector, "expected 1 selector segment (ebs uuid) for %s", VolumeTypeAwsEBS)
+	if len(selector) == 0 {
+		return serrors.InvalidValueErrorf(selector, "expected an ebs uuid, then optionally fs type, partition and %s segments for %s", SelectorSegmentReadOnly, VolumeTypeAwsEBS)
 	}
 	s.VolumeID = selector[0]
 
-	err := jsonutil.UnmarshalMap(obj, &s)
+	err := jsonutil.UnmarshalMap(obj, s)
+	if err != nil {
+		return serrors.ContextualizeErrorf(err, VolumeTypeAwsEBS)
+	}
+
+	err = unmarshalDiskSelector(selector[1:], &s.FSType, &s.Partition, &s.ReadOnly)
 	if err != nil {
 		return serrors.ContextualizeErrorf(err, VolumeTypeAwsEBS)
 	}
@@ -735,47 +825,125 @@ func (s *AwsEBSVolume) Unmarshal(obj map[string]interface{}, selector []string)
 }
 
 func (s AwsEBSVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
-	if err != nil {
-		return nil, serrors.ContextualizeErrorf(err, VolumeTypeAwsEBS)
-	}
-
 	if len(s.VolumeID) == 0 {
 		return nil, serrors.InvalidInstanceErrorf(&s, "selector must contain ebs uuid")
 	}
 
+	selector, ok := marshalDiskSelector(s.VolumeID, s.FSType, s.Partition, s.ReadOnly)
+	extra := s
+	if ok {
+		extra.FSType, extra.Partition, extra.ReadOnly = "", 0, false
+	} else {
+		selector = []string{s.VolumeID}
+	}
+	obj, err := json.Marshal(&extra)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, VolumeTypeAwsEBS)
+	}
+
 	return &MarshalledVolume{
 		Type:        VolumeTypeAwsEBS,
-		Selector:    []string{s.VolumeID},
+		Selector:    selector,
 		ExtraFields: obj,
 	}, nil
 }
 
+// unmarshalDiskSelector reads the selector segments after a disk's name: its fs type, partition
+// number and "ro", in any order, e.g. "ext4:1:ro". They can also be given as fields, but not both.
+func unmarshalDiskSelector(segments []string, fsType *string, partition *int32, readOnly *bool) error {
+	for _, segment := range segments {
+		if segment == SelectorSegmentReadOnly {
+			if *readOnly {
+				return serrors.InvalidValueErrorf(segments, "%s is given twice", SelectorSegmentReadOnly)
+			}
+			*readOnly = true
+			continue
+		}
+
+		if n, err := strconv.ParseInt(segment, 10, 32); err == nil {
+			if n <= 0 {
+				return serrors.InvalidValueErrorf(segment, "expected a partition number greater than 0")
+			}
+			if *partition != 0 {
+				return serrors.InvalidValueErrorf(segments, "the partition is given twice")
+			}
+			*partition = int32(n)
+			continue
+		}
+
+		if len(segment) == 0 {
+			return serrors.InvalidValueErrorf(segments, "unexpected empty selector segment")
+		}
+		if len(*fsType) > 0 {
+			return serrors.InvalidValueErrorf(segments, "the fs type is given twice")
+		}
+		*fsType = segment
+	}
+
+	return nil
+}
+
+// marshalDiskSelector is the inverse of unmarshalDiskSelector. It returns false if the disk's name
+// can't be written that way (it needs escaping, so the fields are easier to read), or its fs type
+// would be read as something else.
+func marshalDiskSelector(name, fsType string, partition int32, readOnly bool) ([]string, bool) {
+	if !isColonSafe([]string{name, fsType}) || fsType == SelectorSegmentReadOnly || partition < 0 {
+		return nil, false
+	}
+	if _, err := strconv.ParseInt(fsType, 10, 32); err == nil {
+		return nil, false
+	}
+
+	selector := []string{name}
+	if len(fsType) > 0 {
+		selector = append(selector, fsType)
+	}
+	if partition != 0 {
+		selector = append(selector, strconv.Itoa(int(partition)))
+	}
+	if readOnly {
+		selector = append(selector, SelectorSegmentReadOnly)
+	}
+
+	return selector, true
+}
+
 func (s *AzureDiskVolume) Unmarshal(obj map[string]interface{}, selector []string) error {
 	if len(selector) != 0 {
 		return serrors.InvalidValueErrorf(selector, "expected zero selector segments for %s", VolumeTypeAzureDisk)
 	}
 
-	err := jsonutil.UnmarshalMap(obj, &s)
-	if err != nil {
-		return serrors.ContextualizeErrorf(err, VolumeTypeAzureDisk)
+	if obj != nil {
+		err := jsonutil.UnmarshalMap(obj, &s)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, VolumeTypeAzureDisk)
+		}
+	}
+
+	return s.validate()
+}
+
+func (s AzureDiskVolume) validate() error {
+	if len(s.DiskName) == 0 {
+		return serrors.InvalidInstanceErrorf(&s, "disk_name is required for %s", VolumeTypeAzureDisk)
+	}
+
+	if len(s.DataDiskURI) == 0 {
+		return serrors.InvalidInstanceErrorf(&s, "disk_uri is required for %s", VolumeTypeAzureDisk)
 	}
 
 	return nil
 }
 
 func (s AzureDiskVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeAzureDisk)
 	}
 
-	if len(s.DiskName) == 0 {
-		return nil, serrors.InvalidInstanceErrorf(&s, "disk_name is required for %s", VolumeTypeAzureDisk)
-	}
-
-	if len(s.DataDiskURI) == 0 {
-		return nil, serrors.InvalidInstanceErrorf(&s, "disk_uri is required for %s", VolumeTypeAzureDisk)
+	err = s.validate()
+	if err != nil {
+		return nil, err
 	}
 
 	return &MarshalledVolume{
@@ -835,7 +1003,7 @@ func (v *Volume) UnmarshalCephFSVolume(obj map[string]interface{}, selector []st
 }
 
 func (s CephFSVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeCephFS)
 	}
@@ -893,7 +1061,7 @@ func (s *CinderVolume) Unmarshal(obj map[string]interface{}, selector []string)
 }
 
 func (s CinderVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeCinder)
 	}
@@ -919,7 +1087,7 @@ func (s *FibreChannelVolume) Unmarshal(obj map[string]interface{}, selector []st
 }
 
 func (s FibreChannelVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeFibreChannel)
 	}
@@ -945,7 +1113,7 @@ func (s *FlexVolume) Unmarshal(obj map[string]interface{}, selector []string) er
 }
 
 func (s FlexVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeFlex)
 	}
@@ -957,45 +1125,82 @@ func (s FlexVolume) Marshal() (*MarshalledVolume, error) {
 	}, nil
 }
 
-func (s *FlockerVolume) Unmarshal(selector []string) error {
-	if len(selector) != 1 {
-		return serrors.InvalidValueErrorf(selector, "expected exactly one selector segment (dataset UUID) for %s", VolumeTypeFlocker)
+func (s *FlockerVolume) Unmarshal(obj map[string]interface{}, selector []string) error {
+	if len(selector) > 1 {
+		return serrors.InvalidValueErrorf(selector, "expected at most one selector segment (dataset UUID) for %s", VolumeTypeFlocker)
+	}
+	if len(selector) > 0 {
+		s.DatasetUUID = selector[0]
 	}
 
-	s.DatasetUUID = selector[0]
+	if obj != nil {
+		err := jsonutil.UnmarshalMap(obj, &s)
+		if err != nil {
+			return serrors.ContextualizeErrorf(err, VolumeTypeFlocker)
+		}
+	}
+
+	if len(s.DatasetUUID) == 0 && len(s.DatasetName) == 0 {
+		return serrors.InvalidValueErrorf(selector, "expected either a dataset UUID or dataset_name for %s", VolumeTypeFlocker)
+	}
+	if len(s.DatasetUUID) > 0 && len(s.DatasetName) > 0 {
+		return serrors.InvalidValueErrorf(selector, "expected either a dataset UUID or dataset_name for %s, not both", VolumeTypeFlocker)
+	}
 
 	return nil
 }
 
 func (s FlockerVolume) Marshal() (*MarshalledVolume, error) {
+	obj, err := json.Marshal(&s)
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, VolumeTypeFlocker)
+	}
+
+	var selector []string
+	if len(s.DatasetUUID) > 0 {
+		selector = []string{s.DatasetUUID}
+	}
+
 	return &MarshalledVolume{
-		Type:     VolumeTypeFlocker,
-		Selector: []string{s.DatasetUUID},
+		Type:        VolumeTypeFlocker,
+		Selector:    selector,
+		ExtraFields: obj,
 	}, nil
 }
 
-func (s *GlusterfsVolume) Unmarshal(obj map[string]interface{}, selector []string) error {
-	if len(selector) != 0 {
-		return serrors.InvalidValueErrorf(selector, "expected zero selector segments for %s", VolumeTypeGlusterfs)
+func (s *GlusterfsVolume) Unmarshal(selector []string) error {
+	if len(selector) == 1 {
+		// A pod volume's "vol_id" isn't split.
+		selector = SplitVolumeSelector(selector[0])
+	}
+	if len(selector) > 3 || len(selector) < 2 {
+		return serrors.InvalidValueErrorf(selector, "expected two or three selector segments (endpoints:path[:ro]) for %s", VolumeTypeGlusterfs)
 	}
 
-	err := jsonutil.UnmarshalMap(obj, &s)
-	if err != nil {
-		return serrors.ContextualizeErrorf(err, VolumeTypeGlusterfs)
+	s.EndpointsName = selector[0]
+	s.Path = selector[1]
+
+	if len(selector) > 2 {
+		switch selector[2] {
+		case SelectorSegmentReadOnly:
+			s.ReadOnly = true
+		default:
+			return serrors.InvalidValueErrorf(selector[2], "invalid selector segment for %s", VolumeTypeGlusterfs)
+		}
 	}
 
 	return nil
 }
 
 func (s GlusterfsVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
-	if err != nil {
-		return nil, serrors.ContextualizeErrorf(err, VolumeTypeGlusterfs)
+	selector := []string{s.EndpointsName, s.Path}
+	if s.ReadOnly {
+		selector = append(selector, SelectorSegmentReadOnly)
 	}
 
 	return &MarshalledVolume{
-		Type:        VolumeTypeGlusterfs,
-		ExtraFields: obj,
+		Type:     VolumeTypeGlusterfs,
+		Selector: selector,
 	}, nil
 }
 
@@ -1013,7 +1218,7 @@ func (s *ISCSIVolume) Unmarshal(obj map[string]interface{}, selector []string) e
 }
 
 func (s ISCSIVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeISCSI)
 	}
@@ -1099,7 +1304,7 @@ func (s *PortworxVolume) Unmarshal(obj map[string]interface{}, selector []string
 }
 
 func (s PortworxVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypePortworx)
 	}
@@ -1124,7 +1329,7 @@ func (v *Volume) UnmarshalPVCVolume(selector []string) error {
 		case SelectorSegmentReadOnly:
 			source.ReadOnly = true
 		default:
-			return serrors.InvalidValueErrorf(selector[2], "invalid selector segment for %s", VolumeTypePVC)
+			return serrors.InvalidValueErrorf(selector[1], "invalid selector segment for %s", VolumeTypePVC)
 		}
 	}
 
@@ -1160,7 +1365,7 @@ func (s *QuobyteVolume) Unmarshal(obj map[string]interface{}, selector []string)
 }
 
 func (s QuobyteVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeQuobyte)
 	}
@@ -1189,7 +1394,7 @@ func (v *Volume) UnmarshalScaleIOVolume(obj map[string]interface{}, selector []s
 }
 
 func (s ScaleIOVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeScaleIO)
 	}
@@ -1216,7 +1421,7 @@ func (s *VsphereVolume) Unmarshal(obj map[string]interface{}, selector []string)
 }
 
 func (s VsphereVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeVsphere)
 	}
@@ -1308,7 +1513,7 @@ func (v *Volume) UnmarshalConfigMapVolume(obj map[string]interface{}, selector [
 }
 
 func (s ConfigMapVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeConfigMap)
 	}
@@ -1337,7 +1542,7 @@ func (v *Volume) UnmarshalSecretVolume(obj map[string]interface{}, selector []st
 }
 
 func (s SecretVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeSecret)
 	}
@@ -1446,7 +1651,7 @@ func (v *Volume) UnmarshalDownwardAPIVolume(obj map[string]interface{}, selector
 }
 
 func (s DownwardAPIVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeDownwardAPI)
 	}
@@ -1473,7 +1678,7 @@ func (v *Volume) UnmarshalProjectedVolume(obj map[string]interface{}, selector [
 }
 
 func (s ProjectedVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeProjected)
 	}
@@ -1533,7 +1738,7 @@ func (v *Volume) UnmarshalGitVolume(obj map[string]interface{}, selector []strin
 }
 
 func (s GitVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeGit)
 	}
@@ -1559,7 +1764,7 @@ func (s *RBDVolume) Unmarshal(obj map[string]interface{}, selector []string) err
 }
 
 func (s RBDVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeRBD)
 	}
@@ -1585,7 +1790,7 @@ func (s *StorageOSVolume) Unmarshal(obj map[string]interface{}, selector []strin
 }
 
 func (s StorageOSVolume) Marshal() (*MarshalledVolume, error) {
-	obj, err := jsonutil.MarshalMap(&s)
+	obj, err := json.Marshal(&s)
 	if err != nil {
 		return nil, serrors.ContextualizeErrorf(err, VolumeTypeStorageOS)
 	}
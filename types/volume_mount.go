@@ -0,0 +1,152 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// SplitMountStore parses the "store" of a VolumeMount: "name[:subpath][:ro|:rw]".
+// Colons in the name or subpath are escaped as "\:".
+func SplitMountStore(store string) (name, subPath string, readOnly bool, err error) {
+	segments := SplitAtUnescapedColons(store)
+	for i, segment := range segments {
+		segments[i] = string(UnescapeName(segment))
+	}
+
+	name = segments[0]
+	switch len(segments) {
+	case 1:
+	case 2:
+		switch segments[1] {
+		case SelectorSegmentReadOnly:
+			readOnly = true
+		case "rw":
+		default:
+			subPath = segments[1]
+		}
+	case 3:
+		subPath = segments[1]
+		switch segments[2] {
+		case SelectorSegmentReadOnly:
+			readOnly = true
+		case "rw":
+		default:
+			return "", "", false, serrors.InvalidValueErrorf(store, "expected ro or rw after the subpath")
+		}
+	default:
+		return "", "", false, serrors.InvalidValueErrorf(store, "expected name[:subpath][:ro]")
+	}
+
+	if len(name) == 0 {
+		return "", "", false, serrors.InvalidValueErrorf(store, "expected a volume name")
+	}
+
+	return name, subPath, readOnly, nil
+}
+
+// JoinMountStore is the inverse of SplitMountStore.
+func JoinMountStore(name, subPath string, readOnly bool) string {
+	segments := []string{EscapeName(Name(name))}
+	if len(subPath) > 0 {
+		segments = append(segments, EscapeName(Name(subPath)))
+	}
+
+	if readOnly {
+		segments = append(segments, SelectorSegmentReadOnly)
+	} else if subPath == SelectorSegmentReadOnly || subPath == "rw" {
+		// Otherwise, the subpath would look like an access mode.
+		segments = append(segments, "rw")
+	}
+
+	return strings.Join(segments, ":")
+}
+
+// parseMountPropagation accepts the MountPropagation values, with or without dashes.
+func parseMountPropagation(s string) (MountPropagation, bool) {
+	for _, propagation := range []MountPropagation{MountPropagationHostToContainer, MountPropagationBidirectional, MountPropagationNone} {
+		if strings.ToLower(strings.Replace(s, "-", "", -1)) == strings.Replace(string(propagation), "-", "", -1) {
+			return propagation, true
+		}
+	}
+
+	return "", false
+}
+
+// ParseVolumeMount parses the string form of a VolumeMount: "name[:subpath]:/mount/path[:ro|:rw][:propagation]".
+// The mount path is the first segment that starts with "/", since a subpath must be relative.
+func ParseVolumeMount(s string) (*VolumeMount, error) {
+	segments := SplitAtUnescapedColons(s)
+	for i, segment := range segments {
+		segments[i] = string(UnescapeName(segment))
+	}
+
+	mountIndex := -1
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "/") {
+			mountIndex = i
+			break
+		}
+	}
+	if mountIndex < 1 || mountIndex > 2 {
+		return nil, serrors.InvalidValueErrorf(s, "expected name[:subpath]:/mount/path[:ro][:propagation]")
+	}
+
+	name := segments[0]
+	subPath := ""
+	if mountIndex == 2 {
+		subPath = segments[1]
+	}
+	if len(name) == 0 {
+		return nil, serrors.InvalidValueErrorf(s, "expected a volume name")
+	}
+
+	mount := &VolumeMount{MountPath: segments[mountIndex]}
+	readOnly := false
+	hasAccess := false
+	for _, flag := range segments[mountIndex+1:] {
+		switch flag {
+		case SelectorSegmentReadOnly, "rw":
+			if hasAccess {
+				return nil, serrors.InvalidValueErrorf(s, "expected at most one of ro and rw")
+			}
+			hasAccess = true
+			readOnly = flag == SelectorSegmentReadOnly
+		default:
+			propagation, ok := parseMountPropagation(flag)
+			if !ok {
+				return nil, serrors.InvalidValueErrorf(s, "unexpected (%s), expected ro, rw, host-to-container, bidirectional or none", flag)
+			}
+			if mount.Propagation != nil {
+				return nil, serrors.InvalidValueErrorf(s, "expected at most one mount propagation")
+			}
+			mount.Propagation = &propagation
+		}
+	}
+
+	mount.Store = JoinMountStore(name, subPath, readOnly)
+	return mount, nil
+}
+
+type volumeMount VolumeMount
+
+func (m *VolumeMount) UnmarshalJSON(data []byte) error {
+	str := ""
+	err := json.Unmarshal(data, &str)
+	if err == nil {
+		mount, err := ParseVolumeMount(str)
+		if err != nil {
+			return err
+		}
+		*m = *mount
+		return nil
+	}
+
+	err = json.Unmarshal(data, (*volumeMount)(m))
+	if err != nil {
+		return serrors.InvalidValueForTypeContextError(err, string(data), m)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+	serrors "github.com/koki/structurederrors"
+)
+
+func TestVolumeMountString(t *testing.T) {
+	hostToContainer := MountPropagationHostToContainer
+	bidirectional := MountPropagationBidirectional
+
+	testCases := map[string]VolumeMount{
+		"config:/etc/cfg":                       {Store: "config", MountPath: "/etc/cfg"},
+		"config:/etc/cfg:ro":                    {Store: "config:ro", MountPath: "/etc/cfg"},
+		"config:/etc/cfg:ro:hosttocontainer":    {Store: "config:ro", MountPath: "/etc/cfg", Propagation: &hostToContainer},
+		"config:/etc/cfg:host-to-container:rw":  {Store: "config", MountPath: "/etc/cfg", Propagation: &hostToContainer},
+		"config:app/cfg:/etc/cfg:Bidirectional": {Store: "config:app/cfg", MountPath: "/etc/cfg", Propagation: &bidirectional},
+		"config:ro:/etc/cfg":                    {Store: "config:ro:rw", MountPath: "/etc/cfg"},
+		`config:a\:b:/etc/cfg:ro`:               {Store: `config:a\:b:ro`, MountPath: "/etc/cfg"},
+	}
+
+	for str, expected := range testCases {
+		mount := VolumeMount{}
+		err := yaml.Unmarshal([]byte(str), &mount)
+		if err != nil {
+			t.Error(pretty.Sprint(serrors.PrettyError(err), str))
+			continue
+		}
+
+		if !reflect.DeepEqual(mount, expected) {
+			t.Error(pretty.Sprint(str, mount, expected))
+		}
+	}
+
+	for _, str := range []string{"config", "config:etc/cfg", "config:a:b:/etc/cfg", ":/etc/cfg", "config:/etc/cfg:ro:rw", "config:/etc/cfg:sideways"} {
+		mount := VolumeMount{}
+		if err := yaml.Unmarshal([]byte(str), &mount); err == nil {
+			t.Error(pretty.Sprint("expected an error", str, mount))
+		}
+	}
+}
+
+func TestMountStore(t *testing.T) {
+	testCases := []struct {
+		name     string
+		subPath  string
+		readOnly bool
+		store    string
+	}{
+		{"config", "", false, "config"},
+		{"config", "", true, "config:ro"},
+		{"config", "app", false, "config:app"},
+		{"config", "app", true, "config:app:ro"},
+		{"config", "ro", false, "config:ro:rw"},
+		{"config", "rw", true, "config:rw:ro"},
+		{"config", "a:b", false, `config:a\:b`},
+	}
+
+	for _, testCase := range testCases {
+		store := JoinMountStore(testCase.name, testCase.subPath, testCase.readOnly)
+		if store != testCase.store {
+			t.Error(pretty.Sprint(testCase, store))
+		}
+
+		name, subPath, readOnly, err := SplitMountStore(store)
+		if err != nil {
+			t.Error(pretty.Sprint(serrors.PrettyError(err), store))
+			continue
+		}
+
+		if name != testCase.name || subPath != testCase.subPath || readOnly != testCase.readOnly {
+			t.Error(pretty.Sprint(testCase, name, subPath, readOnly))
+		}
+	}
+}
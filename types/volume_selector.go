@@ -0,0 +1,131 @@
+package types
+
+import (
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// volumeSelectorFields names the selector segments of each volume type.
+// A volume can use these fields instead of "vol_id", e.g. when a segment contains a colon.
+var volumeSelectorFields = map[string][]string{
+	VolumeTypeHostPath:  {"path", "type"},
+	VolumeTypeGcePD:     {"disk"},
+	VolumeTypeAwsEBS:    {"volume_id"},
+	VolumeTypeAzureFile: {"secret_name", "share", SelectorSegmentReadOnly},
+	VolumeTypeCinder:    {"volume_id"},
+	VolumeTypeFlex:      {"driver"},
+	VolumeTypeFlocker:   {"dataset"},
+	VolumeTypeGlusterfs: {"endpoints", "path", SelectorSegmentReadOnly},
+	VolumeTypeNFS:       {"server", "path", SelectorSegmentReadOnly},
+	VolumeTypePhotonPD:  {"pd", "fs"},
+	VolumeTypePortworx:  {"volume_id"},
+	VolumeTypePVC:       {"claim", SelectorSegmentReadOnly},
+	VolumeTypeQuobyte:   {"volume"},
+	VolumeTypeScaleIO:   {"volume"},
+	VolumeTypeVsphere:   {"path"},
+	VolumeTypeConfigMap: {"name"},
+	VolumeTypeSecret:    {"name"},
+	VolumeTypeGit:       {"repo"},
+	VolumeTypeStorageOS: {"volume"},
+}
+
+// isSelectorFlag is true for selector segments that are either present (as their own name) or absent.
+// They're bools in the named form.
+func isSelectorFlag(field string) bool {
+	return field == SelectorSegmentReadOnly
+}
+
+// SplitVolumeSelector splits a "vol_id" at its colons. "\:" is a literal colon, and "\\" is a literal backslash.
+func SplitVolumeSelector(s string) []string {
+	segments := SplitAtUnescapedColons(s)
+	for i, segment := range segments {
+		segments[i] = string(UnescapeName(segment))
+	}
+
+	return segments
+}
+
+// JoinVolumeSelector is the inverse of SplitVolumeSelector.
+func JoinVolumeSelector(selector []string) string {
+	segments := make([]string, len(selector))
+	for i, segment := range selector {
+		segments[i] = EscapeName(Name(segment))
+	}
+
+	return strings.Join(segments, ":")
+}
+
+// isColonSafe is true if none of the selector's segments need escaping.
+func isColonSafe(selector []string) bool {
+	for _, segment := range selector {
+		if strings.ContainsAny(segment, `:\`) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unmarshalNamedVolumeSelector removes the named selector fields from obj,
+// and returns the selector they make up. It returns nil if obj has none of them.
+func unmarshalNamedVolumeSelector(obj map[string]interface{}, fields []string) ([]string, error) {
+	var selector []string
+	for _, field := range fields {
+		val, ok := obj[field]
+		if !ok {
+			continue
+		}
+		delete(obj, field)
+
+		segment := ""
+		if isSelectorFlag(field) {
+			flag, ok := val.(bool)
+			if !ok {
+				return nil, serrors.InvalidValueErrorf(val, "expected bool for key \"%s\"", field)
+			}
+			if !flag {
+				continue
+			}
+			segment = field
+		} else {
+			str, ok := val.(string)
+			if !ok {
+				return nil, serrors.InvalidValueErrorf(val, "expected string for key \"%s\"", field)
+			}
+			segment = str
+		}
+
+		// Fill in any skipped segments.
+		for _, prevField := range fields[len(selector):] {
+			if prevField == field {
+				break
+			}
+			selector = append(selector, "")
+		}
+		selector = append(selector, segment)
+	}
+
+	return selector, nil
+}
+
+// marshalVolumeSelector adds a selector to a volume dictionary. It's a "vol_id" if it's colon-safe,
+// and named fields otherwise.
+func marshalVolumeSelector(obj map[string]interface{}, fields []string, selector []string) {
+	if len(selector) == 0 {
+		return
+	}
+
+	if isColonSafe(selector) || len(fields) < len(selector) {
+		obj["vol_id"] = JoinVolumeSelector(selector)
+		return
+	}
+
+	for i, segment := range selector {
+		if isSelectorFlag(fields[i]) {
+			obj[fields[i]] = len(segment) > 0
+		} else {
+			obj[fields[i]] = segment
+		}
+	}
+}
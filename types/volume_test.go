@@ -59,6 +59,13 @@ var kokiAwsEBS1 = Volume{
 	},
 }
 
+var kokiAwsEBSWithZone = Volume{
+	AwsEBS: &AwsEBSVolume{
+		VolumeID: "aws://us-east-1a/vol-0123456789abcdef0",
+		FSType:   "ext4",
+	},
+}
+
 var azureDiskCachingMode0 = AzureDataDiskCachingReadWrite
 var azureDiskKind0 = AzureSharedBlobDisk
 var kokiAzureDisk0 = Volume{
@@ -72,6 +79,15 @@ var kokiAzureDisk0 = Volume{
 	},
 }
 
+var azureDiskKind1 = AzureManagedDisk
+var kokiAzureDisk1 = Volume{
+	AzureDisk: &AzureDiskVolume{
+		DiskName:    "managed-disk",
+		DataDiskURI: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/managed-disk",
+		Kind:        &azureDiskKind1,
+	},
+}
+
 var kokiAzureFile0 = Volume{
 	AzureFile: &AzureFileVolume{
 		SecretName: "azure-secret",
@@ -168,6 +184,11 @@ var kokiFlockerVolume0 = Volume{
 		DatasetUUID: "flocker_uuid",
 	},
 }
+var kokiFlockerVolume1 = Volume{
+	Flocker: &FlockerVolume{
+		DatasetName: "flocker_dataset",
+	},
+}
 
 var kokiGlusterfsVolume0 = Volume{
 	Glusterfs: &GlusterfsVolume{
@@ -467,6 +488,13 @@ var kokiProjectedVolume1 = Volume{
 	},
 }
 
+var kokiHostPathWithColon = Volume{
+	HostPath: &HostPathVolume{
+		Path: `C:\data`,
+		Type: HostPathDirectory,
+	},
+}
+
 var kokiGitVolume0 = Volume{
 	Git: &GitVolume{
 		Repository: "git@github.com:koki/short.git",
@@ -517,13 +545,16 @@ var kokiStorageOSVolume0 = Volume{
 
 func TestVolume(t *testing.T) {
 	testVolumeSource(kokiHostPath0, t, true)
-	testVolumeSource(kokiEmptyDir0, t, false)
+	testVolumeSource(kokiHostPathWithColon, t, false)
+	testVolumeSource(kokiEmptyDir0, t, true)
 	testVolumeSource(kokiEmptyDir1, t, true)
-	testVolumeSource(kokiGcePD0, t, false)
+	testVolumeSource(kokiGcePD0, t, true)
 	testVolumeSource(kokiGcePD1, t, true)
-	testVolumeSource(kokiAwsEBS0, t, false)
+	testVolumeSource(kokiAwsEBS0, t, true)
 	testVolumeSource(kokiAwsEBS1, t, true)
+	testVolumeSource(kokiAwsEBSWithZone, t, false)
 	testVolumeSource(kokiAzureDisk0, t, false)
+	testVolumeSource(kokiAzureDisk1, t, false)
 	testVolumeSource(kokiAzureFile0, t, true)
 	testVolumeSource(kokiAzureFile1, t, true)
 	testVolumeSource(kokiCephFS0, t, false)
@@ -534,7 +565,8 @@ func TestVolume(t *testing.T) {
 	testVolumeSource(kokiFlexVolume0, t, false)
 	testVolumeSource(kokiFlexVolume1, t, true)
 	testVolumeSource(kokiFlockerVolume0, t, true)
-	testVolumeSource(kokiGlusterfsVolume0, t, false)
+	testVolumeSource(kokiFlockerVolume1, t, false)
+	testVolumeSource(kokiGlusterfsVolume0, t, true)
 	testVolumeSource(kokiISCSIVolume0, t, false)
 	testVolumeSource(kokiNFSVolume0, t, true)
 	testVolumeSource(kokiNFSVolume1, t, true)
@@ -556,12 +588,110 @@ func TestVolume(t *testing.T) {
 	testVolumeSource(kokiProjectedVolume0, t, false)
 	testVolumeSource(kokiProjectedVolume1, t, false)
 	testVolumeSource(kokiGitVolume0, t, false)
-	testVolumeSource(kokiGitVolume1, t, true)
+	testVolumeSource(kokiGitVolume1, t, false)
 	testVolumeSource(kokiRBDVolume0, t, false)
 	testVolumeSource(kokiRBDVolume1, t, false)
 	testVolumeSource(kokiStorageOSVolume0, t, false)
 }
 
+func TestEmptyDirSelector(t *testing.T) {
+	for _, data := range []string{
+		"empty_dir:memory:1Gi",
+		"empty_dir:1Gi:memory",
+		"empty_dir:memory, 1Gi",
+		"vol_type: empty_dir\nmedium: memory\nmax_size: 1Gi",
+	} {
+		volume := Volume{}
+		err := yaml.Unmarshal([]byte(data), &volume)
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if volume.EmptyDir == nil || volume.EmptyDir.Medium != StorageMediumMemory || volume.EmptyDir.SizeLimit == nil || volume.EmptyDir.SizeLimit.String() != "1Gi" {
+			t.Error(pretty.Sprintf("%s: got %# v", data, volume))
+		}
+	}
+
+	for _, data := range []string{
+		"empty_dir:memory:huge-pages",
+		"empty_dir:1Gi:2Gi",
+		"empty_dir:tmpfs",
+		"vol_type: empty_dir\nvol_id: memory\nmax_size: 1Gi",
+	} {
+		if err := yaml.Unmarshal([]byte(data), &Volume{}); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestVolumeSelectorForms(t *testing.T) {
+	for _, data := range []string{
+		`host_path:C\:\\data:dir`,
+		"vol_type: host_path\npath: C:\\data\ntype: dir",
+		"vol_type: host_path\ntype: dir\npath: C:\\data",
+	} {
+		volume := Volume{}
+		err := yaml.Unmarshal([]byte(data), &volume)
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if !reflect.DeepEqual(volume, kokiHostPathWithColon) {
+			t.Error(pretty.Sprintf("%s: got %# v", data, volume))
+		}
+	}
+
+	for _, data := range []string{
+		"host_path:/data:directory",
+		"host_path:/data:dir:ro",
+		"vol_type: host_path\npath: /data\ntype: Directory",
+	} {
+		if err := yaml.Unmarshal([]byte(data), &Volume{}); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+
+	volume := Volume{}
+	err := yaml.Unmarshal([]byte("vol_type: nfs\nserver: nfs.example.com\npath: /exports/a:b\nro: true"), &volume)
+	if err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(volume.NFS, &NFSVolume{Server: "nfs.example.com", Path: "/exports/a:b", ReadOnly: true}) {
+		t.Error(pretty.Sprintf("got %# v", volume.NFS))
+	}
+
+	for _, data := range []string{
+		"glusterfs:glusterfs-cluster:kube_vol:ro",
+		"vol_type: glusterfs\nvol_id: glusterfs-cluster:kube_vol:ro",
+		"vol_type: glusterfs\nendpoints: glusterfs-cluster\npath: kube_vol\nro: true",
+	} {
+		volume := Volume{}
+		err := yaml.Unmarshal([]byte(data), &volume)
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+		} else if !reflect.DeepEqual(volume, kokiGlusterfsVolume0) {
+			t.Error(pretty.Sprintf("%s: got %# v", data, volume))
+		}
+	}
+
+	err = yaml.Unmarshal([]byte("vol_type: host_path\nvol_id: /data\npath: /data"), &Volume{})
+	if err == nil {
+		t.Error("expected an error for both vol_id and named selector fields")
+	}
+}
+
+func TestAzureDiskRequiredFields(t *testing.T) {
+	for _, data := range []string{
+		"vol_type: azure_disk\ndisk_uri: https://someaccount.blob.microsoft.net/vhds/test.vhd",
+		"vol_type: azure_disk\ndisk_name: test.vhd",
+	} {
+		volume := Volume{}
+		err := yaml.Unmarshal([]byte(data), &volume)
+		if err == nil {
+			t.Errorf("expected error for incomplete azure_disk (%s)", data)
+		}
+	}
+}
+
 func isString(data []byte, t *testing.T) bool {
 	str := ""
 	err := yaml.Unmarshal(data, &str)
@@ -599,3 +729,39 @@ func testVolumeSource(kokiVolume Volume, t *testing.T, expectString bool) {
 		return
 	}
 }
+
+func TestDiskVolumeSelector(t *testing.T) {
+	expected := &GcePDVolume{PDName: "data-disk", FSType: "ext4", Partition: 2, ReadOnly: true}
+	for _, data := range []string{
+		"gce_pd:data-disk:ext4:2:ro",
+		"gce_pd:data-disk:ro:2:ext4",
+		"vol_type: gce_pd\nvol_id: data-disk\nfs: ext4\npartition: 2\nro: true",
+	} {
+		volume := Volume{}
+		err := yaml.Unmarshal([]byte(data), &volume)
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if !reflect.DeepEqual(volume.GcePD, expected) {
+			t.Error(pretty.Sprintf("%s: got %# v", data, volume.GcePD))
+		}
+	}
+
+	for _, data := range []string{
+		"gce_pd:data-disk:ext4:xfs",
+		"gce_pd:data-disk:0",
+		"gce_pd:data-disk:1:2",
+		"gce_pd:data-disk:ro:ro",
+		"aws_ebs:volume-id::ro",
+	} {
+		if err := yaml.Unmarshal([]byte(data), &Volume{}); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+
+	source := PersistentVolumeSource{}
+	if err := yaml.Unmarshal([]byte("vol_type: aws_ebs\nvol_id: volume-id:ext4\nfs: xfs"), &source); err == nil {
+		t.Errorf("expected an error for an fs type given twice")
+	}
+}
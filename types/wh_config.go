@@ -9,7 +9,7 @@ import (
 )
 
 const (
-	MutatingKind string = "MutatingWebhookConfiguration"
+	MutatingKind   string = "MutatingWebhookConfiguration"
 	ValidatingKind string = "ValidatingWebhookConfiguration"
 )
 
@@ -22,12 +22,13 @@ type ValidatingWebhookConfigWrapper struct {
 }
 
 type WebhookConfig struct {
-	Version     string             `json:"version,omitempty"`
-	Cluster     string             `json:"cluster,omitempty"`
-	Name        string             `json:"name,omitempty"`
-	Namespace   string             `json:"namespace,omitempty"`
-	Labels      map[string]string  `json:"labels,omitempty"`
-	Annotations map[string]string  `json:"annotations,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Cluster     string            `json:"cluster,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Ownership   `json:",inline"`
 	Webhooks    map[string]Webhook `json:"webhooks,omitempty"`
 }
 
@@ -44,7 +45,7 @@ type Webhook struct {
 type WebhookRuleWithOperations struct {
 	Groups     []string `json:"groups,omitempty"`
 	Versions   []string `json:"versions,omitempty"`
-	Operations []string   `json:"operations,omitempty"`
+	Operations []string `json:"operations,omitempty"`
 	Resources  []string `json:"resources,omitempty"`
 }
 
@@ -59,30 +60,33 @@ type LabelSelectorRequirement struct {
 	Values   []string `json:"values,omitempty"`
 }
 
+const (
+	// WebhookRuleCoreGroup stands for the core API group ("") in rule strings.
+	WebhookRuleCoreGroup = "core"
+
+	// WebhookRuleAll matches every operation, resource, group or version.
+	WebhookRuleAll = "*"
+)
+
+/*
+
+A rule is written as "OPERATIONS RESOURCES [in GROUPS[/VERSIONS]]", e.g.
+
+	CREATE,UPDATE pods
+	CREATE,UPDATE deployments,replicasets in apps
+	* ingresses in extensions,networking.k8s.io/v1beta1
+
+Without "in", the rule matches the core group. Without versions, it matches every version.
+The older "group/version/resource/OP|OP" strings are still accepted.
+
+*/
+
 func (i *WebhookRuleWithOperations) UnmarshalJSON(data []byte) error {
 	var ruleString string
 
 	strErr1 := json.Unmarshal(data, &ruleString)
 	if strErr1 == nil {
-		parts := strings.SplitN(ruleString, "/", 4)
-		if len(parts) < 3 {
-			return serrors.InvalidValueForTypeErrorf(ruleString, i, "couldn't parse JSON: Invalid format")
-		}
-
-		if len(parts) == 3 {
-			i.Groups = []string{""}
-			i.Versions = []string{parts[0]}
-			i.Resources = []string{parts[1]}
-			i.Operations = []string{parts[2]}
-			return nil
-		}
-
-		i.Groups = []string{parts[0]}
-		i.Versions = []string{parts[1]}
-		i.Resources = []string{parts[2]}
-		i.Operations = []string{parts[3]}
-
-		return nil
+		return i.UnmarshalString(ruleString)
 	}
 	var ruleStruct map[string][]string
 	strErr2 := json.Unmarshal(data, &ruleStruct)
@@ -101,38 +105,97 @@ func (i *WebhookRuleWithOperations) UnmarshalJSON(data []byte) error {
 		return serrors.InvalidInstanceError("couldn't parse JSON: Resources cannot be empty")
 	}
 
-	if len(ruleStruct["operations"]) > 0 {
-		i.Operations = ruleStruct["operations"]
+	for _, operations := range ruleStruct["operations"] {
+		i.Operations = append(i.Operations, strings.Split(operations, "|")...)
 	}
 	return nil
 }
 
-func (i WebhookRuleWithOperations) MarshalJSON() ([]byte, error) {
+func (i *WebhookRuleWithOperations) UnmarshalString(ruleString string) error {
+	fields := strings.Fields(ruleString)
+	switch {
+	case len(fields) == 1 && strings.Contains(fields[0], "/"):
+		return i.unmarshalSlashString(ruleString)
+	case len(fields) == 2:
+		i.Groups = []string{""}
+		i.Versions = []string{WebhookRuleAll}
+	case len(fields) == 4 && fields[2] == "in":
+		groupsAndVersions := strings.SplitN(fields[3], "/", 2)
+		i.Groups = strings.Split(groupsAndVersions[0], ",")
+		for ix, group := range i.Groups {
+			if group == WebhookRuleCoreGroup {
+				i.Groups[ix] = ""
+			}
+		}
+		i.Versions = []string{WebhookRuleAll}
+		if len(groupsAndVersions) > 1 {
+			i.Versions = strings.Split(groupsAndVersions[1], ",")
+		}
+	default:
+		return serrors.InvalidValueForTypeErrorf(ruleString, i, "expected 'OPERATIONS RESOURCES [in GROUPS[/VERSIONS]]'")
+	}
+
+	i.Operations = strings.Split(fields[0], ",")
+	i.Resources = strings.Split(fields[1], ",")
+	return nil
+}
+
+// unmarshalSlashString parses the older "group/version/resource/OP|OP" format.
+func (i *WebhookRuleWithOperations) unmarshalSlashString(ruleString string) error {
+	parts := strings.SplitN(ruleString, "/", 4)
+	if len(parts) < 3 {
+		return serrors.InvalidValueForTypeErrorf(ruleString, i, "couldn't parse JSON: Invalid format")
+	}
+
+	if len(parts) == 3 {
+		parts = append([]string{""}, parts...)
+	}
+
+	i.Groups = []string{parts[0]}
+	i.Versions = []string{parts[1]}
+	i.Resources = []string{parts[2]}
+	i.Operations = strings.Split(parts[3], "|")
+
+	return nil
+}
+
+func (i WebhookRuleWithOperations) MarshalString() (string, error) {
 	if len(i.Resources) == 0 || len(i.Versions) == 0 || len(i.Groups) == 0 || len(i.Operations) == 0 {
-		return []byte{}, serrors.InvalidInstanceErrorf(i, "Invalid Webhook Format")
+		return "", serrors.InvalidInstanceErrorf(i, "Invalid Webhook Format")
 	}
 
-	var rule interface{}
-	var ruleType string
-	ruleType = "struct"
+	ruleString := strings.Join(i.Operations, ",") + " " + strings.Join(i.Resources, ",")
 
-	if len(i.Resources) == 1 && len(i.Versions) == 1 && len(i.Groups) == 1 && len(i.Operations) == 1 {
-		ruleString := strings.Join(append(append(append(i.Groups, i.Versions...), i.Resources...), i.Operations...), "/")
-		rule = ruleString
-		ruleType = "string"
+	isCore := len(i.Groups) == 1 && len(i.Groups[0]) == 0
+	isAllVersions := len(i.Versions) == 1 && i.Versions[0] == WebhookRuleAll
+	if isCore && isAllVersions {
+		return ruleString, nil
 	}
-	if ruleType == "struct" {
-		rule = map[string]interface{}{
-			"groups":     i.Groups,
-			"versions":   i.Versions,
-			"resources":  i.Resources,
-			"operations": i.Operations,
+
+	groups := make([]string, len(i.Groups))
+	for ix, group := range i.Groups {
+		if len(group) == 0 {
+			group = WebhookRuleCoreGroup
 		}
+		groups[ix] = group
+	}
+	ruleString = ruleString + " in " + strings.Join(groups, ",")
+	if !isAllVersions {
+		ruleString = ruleString + "/" + strings.Join(i.Versions, ",")
+	}
+
+	return ruleString, nil
+}
+
+func (i WebhookRuleWithOperations) MarshalJSON() ([]byte, error) {
+	ruleString, err := i.MarshalString()
+	if err != nil {
+		return nil, err
 	}
 
-	b, err := json.Marshal(rule)
+	b, err := json.Marshal(ruleString)
 	if err != nil {
-		return nil, serrors.InvalidInstanceContextErrorf(err, i, "marshalling webhook rule %s to JSON", ruleType)
+		return nil, serrors.InvalidInstanceContextErrorf(err, i, "marshalling webhook rule to JSON")
 	}
 	return b, nil
 }
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/koki/json"
+)
+
+func TestWebhookRuleWithOperations(t *testing.T) {
+	for str, rule := range map[string]WebhookRuleWithOperations{
+		"CREATE,UPDATE pods": {
+			Groups:     []string{""},
+			Versions:   []string{"*"},
+			Resources:  []string{"pods"},
+			Operations: []string{"CREATE", "UPDATE"},
+		},
+		"CREATE,UPDATE deployments,replicasets in apps": {
+			Groups:     []string{"apps"},
+			Versions:   []string{"*"},
+			Resources:  []string{"deployments", "replicasets"},
+			Operations: []string{"CREATE", "UPDATE"},
+		},
+		"* ingresses in core,extensions/v1beta1": {
+			Groups:     []string{"", "extensions"},
+			Versions:   []string{"v1beta1"},
+			Resources:  []string{"ingresses"},
+			Operations: []string{"*"},
+		},
+	} {
+		newRule := WebhookRuleWithOperations{}
+		err := json.Unmarshal([]byte(`"`+str+`"`), &newRule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rule, newRule) {
+			t.Errorf("%s: unexpected rule %#v", str, newRule)
+		}
+
+		b, err := json.Marshal(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `"`+str+`"` {
+			t.Errorf("%s: unexpected string %s", str, string(b))
+		}
+	}
+
+	// The older slash-separated format is still accepted.
+	rule := WebhookRuleWithOperations{}
+	err := json.Unmarshal([]byte(`"apps/v1/deployments/CREATE|UPDATE"`), &rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := WebhookRuleWithOperations{
+		Groups:     []string{"apps"},
+		Versions:   []string{"v1"},
+		Resources:  []string{"deployments"},
+		Operations: []string{"CREATE", "UPDATE"},
+	}
+	if !reflect.DeepEqual(rule, expected) {
+		t.Errorf("unexpected rule %#v", rule)
+	}
+
+	for _, str := range []string{"CREATE", "CREATE pods on apps", "a/b"} {
+		if err := json.Unmarshal([]byte(`"`+str+`"`), &WebhookRuleWithOperations{}); err == nil {
+			t.Errorf("expected error for (%s)", str)
+		}
+	}
+}
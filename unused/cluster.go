@@ -0,0 +1,51 @@
+package unused
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/koki/json"
+	"github.com/koki/short/site"
+	serrors "github.com/koki/structurederrors"
+)
+
+// LiveRefs lists the objects used by the pods running in the cluster, using kubectl and its current context.
+func LiveRefs(kubeContext string) ([]site.ID, error) {
+	args := []string{"get", "pods", "--all-namespaces", "-o", "json"}
+	if len(kubeContext) > 0 {
+		args = append(args, "--context", kubeContext)
+	}
+
+	cmd := exec.Command("kubectl", args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "kubectl %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	list := struct {
+		Items []map[string]interface{} `json:"items"`
+	}{}
+	err = json.Unmarshal(out, &list)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(out), "parsing pod list from kubectl")
+	}
+
+	refs := []site.ID{}
+	for _, pod := range list.Items {
+		namespace := ""
+		if metadata, ok := pod["metadata"].(map[string]interface{}); ok {
+			namespace, _ = metadata["namespace"].(string)
+		}
+
+		podRefs, err := site.FindRefs(namespace, pod)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, podRefs...)
+	}
+
+	return refs, nil
+}
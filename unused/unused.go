@@ -0,0 +1,190 @@
+package unused
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/site"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+ConfigMaps, Secrets, PVCs and ServiceAccounts are only useful if something references them.
+An object of one of these kinds is unused if no other object in the tree references it
+(see site.FindRefs), and, optionally, no pod running in the cluster uses it.
+
+Every namespace has a "default" ServiceAccount used by pods that don't name one, so it's never unused.
+
+*/
+
+// Kinds lists the kinds that are checked, in short syntax.
+var Kinds = map[string]bool{
+	"config_map":      true,
+	"secret":          true,
+	"pvc":             true,
+	"service_account": true,
+}
+
+const defaultNamespace = "default"
+
+// Object is an object defined in the tree.
+type Object struct {
+	site.ID
+	// File is the manifest that defines the object.
+	File string
+}
+
+// Read reads the objects defined in each file, along with the references they make.
+func Read(files []string) ([]Object, []site.ID, error) {
+	objects := []Object{}
+	refs := []site.ID{}
+	for _, file := range files {
+		objs, err := parser.Parse([]string{file}, false)
+		if err != nil {
+			return nil, nil, serrors.ContextualizeErrorf(err, "parsing %s", file)
+		}
+
+		kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+		if err != nil {
+			return nil, nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		kubeObjs, err := client.ConvertKokiMaps(kokiObjs)
+		if err != nil {
+			return nil, nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		for i, kokiObj := range kokiObjs {
+			id := site.ObjectID(kokiObj)
+			objects = append(objects, Object{ID: id, File: file})
+
+			objRefs, err := site.FindRefs(id.Namespace, kubeObjs[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			refs = append(refs, objRefs...)
+		}
+	}
+
+	return objects, refs, nil
+}
+
+// Find returns the objects of the checked kinds that aren't referenced, sorted by ID.
+func Find(objects []Object, refs []site.ID) []Object {
+	referenced := map[site.ID]bool{}
+	for _, ref := range refs {
+		referenced[ref] = true
+		// Objects without a namespace are usually applied to the default namespace.
+		if ref.Namespace == defaultNamespace {
+			ref.Namespace = ""
+			referenced[ref] = true
+		}
+	}
+
+	unused := []Object{}
+	for _, object := range objects {
+		if !Kinds[object.Kind] || referenced[object.ID] {
+			continue
+		}
+		if object.Kind == "service_account" && object.Name == "default" {
+			continue
+		}
+		unused = append(unused, object)
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].ID.Less(unused[j].ID)
+	})
+
+	return unused
+}
+
+// MoveToAttic moves the files whose objects are all unused into the attic directory.
+// Each file keeps its path under the attic, e.g. "attic/apps/web.short.yaml" for "apps/web.short.yaml", and
+// "attic/home/me/web.short.yaml" for "/home/me/web.short.yaml". Nothing is moved if a relative path would end up
+// outside the attic (e.g. "../app.short.yaml"), if two files would be moved to the same place, or if there's
+// already a file there.
+// It returns the files that were moved, and the files that were kept because they also define objects in use.
+func MoveToAttic(objects, unused []Object, attic string) (moved, kept []string, err error) {
+	isUnused := map[Object]bool{}
+	for _, object := range unused {
+		isUnused[object] = true
+	}
+
+	allUnused := map[string]bool{}
+	files := []string{}
+	for _, object := range objects {
+		if _, ok := allUnused[object.File]; !ok {
+			allUnused[object.File] = true
+			files = append(files, object.File)
+		}
+		if !isUnused[object] {
+			allUnused[object.File] = false
+		}
+	}
+
+	dests := map[string]string{}
+	sources := map[string]string{}
+	for _, file := range files {
+		if !allUnused[file] {
+			for _, object := range unused {
+				if object.File == file {
+					kept = append(kept, file)
+					break
+				}
+			}
+			continue
+		}
+
+		dest, err := atticPath(attic, file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if source, ok := sources[dest]; ok {
+			return nil, nil, serrors.InvalidValueErrorf(file, "both it and %s would be moved to %s", source, dest)
+		}
+		if _, err := os.Lstat(dest); err == nil {
+			return nil, nil, serrors.InvalidValueErrorf(file, "%s is already in the attic", dest)
+		} else if !os.IsNotExist(err) {
+			return nil, nil, serrors.ContextualizeErrorf(err, "checking %s", dest)
+		}
+		dests[file] = dest
+		sources[dest] = file
+	}
+
+	for _, file := range files {
+		dest, ok := dests[file]
+		if !ok {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, nil, serrors.ContextualizeErrorf(err, "creating %s", filepath.Dir(dest))
+		}
+		if err := os.Rename(file, dest); err != nil {
+			return nil, nil, serrors.ContextualizeErrorf(err, "moving %s to %s", file, dest)
+		}
+		moved = append(moved, file)
+	}
+
+	return moved, kept, nil
+}
+
+// atticPath is where a file is moved to in the attic.
+func atticPath(attic, file string) (string, error) {
+	if filepath.IsAbs(file) {
+		return filepath.Join(attic, strings.TrimPrefix(file, filepath.VolumeName(file))), nil
+	}
+
+	dest := filepath.Join(attic, file)
+	rel, err := filepath.Rel(attic, dest)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", serrors.InvalidValueErrorf(file, "moving it to the attic (%s) would put it outside the attic", attic)
+	}
+
+	return dest, nil
+}
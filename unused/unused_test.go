@@ -0,0 +1,177 @@
+package unused
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/site"
+)
+
+var files0 = map[string]string{
+	"web.short.yaml": `
+deployment:
+  name: web
+  namespace: prod
+  account: web
+  containers:
+  - name: web
+    image: nginx
+    env:
+    - from: config:settings:a
+      key: A
+`,
+	"config.short.yaml": `
+config_map:
+  name: settings
+  namespace: prod
+  data:
+    a: b
+---
+config_map:
+  name: stale
+  namespace: prod
+  data:
+    a: b
+`,
+	"accounts.short.yaml": `
+service_account:
+  name: web
+  namespace: prod
+---
+service_account:
+  name: fluentd
+  namespace: logging
+---
+service_account:
+  name: default
+  namespace: prod
+---
+role_binding:
+  name: fluentd-read
+  role: rbac.authorization.k8s.io.ClusterRole:fluentd-read
+  subjects:
+  - ServiceAccount:logging:fluentd
+  version: rbac.authorization.k8s.io/v1
+`,
+	"old.short.yaml": `
+secret:
+  name: old-creds
+  namespace: prod
+  string_data:
+    a: b
+`,
+}
+
+func TestFindAndMove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "short-unused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{}
+	for name, contents := range files0 {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	objects, refs, err := Read(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unused := Find(objects, refs)
+	expected := []Object{
+		{ID: site.ID{Kind: "config_map", Namespace: "prod", Name: "stale"}, File: filepath.Join(dir, "config.short.yaml")},
+		{ID: site.ID{Kind: "secret", Namespace: "prod", Name: "old-creds"}, File: filepath.Join(dir, "old.short.yaml")},
+	}
+	if !reflect.DeepEqual(unused, expected) {
+		t.Error(pretty.Diff(unused, expected))
+	}
+
+	liveRefs := []site.ID{{Kind: "secret", Namespace: "prod", Name: "old-creds"}}
+	if unused := Find(objects, append(refs, liveRefs...)); len(unused) != 1 {
+		t.Errorf("expected objects used by running pods to count as referenced, got %v", unused)
+	}
+
+	attic := filepath.Join(dir, "attic")
+	moved, kept, err := MoveToAttic(objects, unused, attic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(moved, []string{filepath.Join(dir, "old.short.yaml")}) {
+		t.Errorf("unexpected moved files %v", moved)
+	}
+	if !reflect.DeepEqual(kept, []string{filepath.Join(dir, "config.short.yaml")}) {
+		t.Errorf("unexpected kept files %v", kept)
+	}
+	if _, err := os.Stat(filepath.Join(attic, dir, "old.short.yaml")); err != nil {
+		t.Errorf("expected the file in the attic: %v", err)
+	}
+	// Relative paths can't leave the attic.
+	outside := []Object{{ID: site.ID{Kind: "secret", Name: "outside"}, File: "../outside.short.yaml"}}
+	if _, _, err := MoveToAttic(outside, outside, attic); err == nil {
+		t.Error("expected an error for a file that would be moved outside the attic")
+	}
+}
+
+func TestMoveToAtticKeepsPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "short-unused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	objects := []Object{}
+	for _, sub := range []string{"a", "b"} {
+		file := filepath.Join(dir, sub, "cm.short.yaml")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(sub), 0644); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, Object{ID: site.ID{Kind: "config_map", Name: sub}, File: file})
+	}
+
+	attic := filepath.Join(dir, "attic")
+	moved, _, err := MoveToAttic(objects, objects, attic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 2 {
+		t.Errorf("unexpected moved files %v", moved)
+	}
+	for _, sub := range []string{"a", "b"} {
+		b, err := ioutil.ReadFile(filepath.Join(attic, dir, sub, "cm.short.yaml"))
+		if err != nil || string(b) != sub {
+			t.Errorf("expected %s/cm.short.yaml in the attic, got %q (%v)", sub, b, err)
+		}
+	}
+
+	// Files already in the attic aren't overwritten.
+	file := objects[0].File
+	if err := ioutil.WriteFile(file, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := MoveToAttic(objects[:1], objects[:1], attic); err == nil {
+		t.Error("expected an error for a file that's already in the attic")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("expected the file to stay in place: %v", err)
+	}
+
+	// Two spellings of the same destination.
+	same := []Object{{ID: site.ID{Kind: "config_map", Name: "x"}, File: "x/cm.short.yaml"}, {ID: site.ID{Kind: "config_map", Name: "y"}, File: "x/./cm.short.yaml"}}
+	if _, _, err := MoveToAttic(same, same, attic); err == nil {
+		t.Error("expected an error for files moved to the same place")
+	}
+}
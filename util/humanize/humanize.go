@@ -0,0 +1,312 @@
+package humanize
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Humanize renders durations and resource quantities of short manifests in friendlier units,
+e.g. "1m30s" instead of 90 (seconds) and "1.5Gi" instead of "1536Mi".
+
+Both forms are valid short syntax. Durations are read back strictly: they must be a whole
+number of seconds. Quantities are Kubernetes quantities either way.
+
+Paths are relative to the body of a short object (e.g. the value of "deployment"), and "*"
+stands for every item of a list or every value of a map.
+
+*/
+
+// durationPaths are the fields that hold a number of seconds.
+var durationPaths = []string{
+	"active_deadline",
+	"min_ready",
+	"progress_deadline",
+	"ready_seconds",
+	"start_deadline",
+	"termination_grace_period",
+	"containers.*.liveness_probe.delay",
+	"containers.*.liveness_probe.interval",
+	"containers.*.liveness_probe.timeout",
+	"containers.*.readiness_probe.delay",
+	"containers.*.readiness_probe.interval",
+	"containers.*.readiness_probe.timeout",
+	"init_containers.*.liveness_probe.delay",
+	"init_containers.*.liveness_probe.interval",
+	"init_containers.*.liveness_probe.timeout",
+	"init_containers.*.readiness_probe.delay",
+	"init_containers.*.readiness_probe.interval",
+	"init_containers.*.readiness_probe.timeout",
+}
+
+// quantityPaths are the fields that hold a resource quantity.
+var quantityPaths = []string{
+	"storage",
+	"volumes.*.max_size",
+	"containers.*.cpu.min",
+	"containers.*.cpu.max",
+	"containers.*.mem.min",
+	"containers.*.mem.max",
+	"init_containers.*.cpu.min",
+	"init_containers.*.cpu.max",
+	"init_containers.*.mem.min",
+	"init_containers.*.mem.max",
+	"containers.*.resources.*.min",
+	"containers.*.resources.*.max",
+	"init_containers.*.resources.*.min",
+	"init_containers.*.resources.*.max",
+}
+
+// Duration renders a number of seconds, e.g. "30s", "5m" or "1h30m".
+func Duration(seconds int64) string {
+	if seconds <= 0 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+
+	s := (time.Duration(seconds) * time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+// ParseDuration is the inverse of Duration. It also accepts a plain number of seconds.
+func ParseDuration(s string) (int64, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return seconds, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, serrors.InvalidValueErrorf(s, "expected a duration like 30s, 5m or 1h30m")
+	}
+	if d%time.Second != 0 {
+		return 0, serrors.InvalidValueErrorf(s, "expected a whole number of seconds")
+	}
+
+	return int64(d / time.Second), nil
+}
+
+// binarySuffixes and decimalSuffixes are the units Quantity picks from, largest first.
+var binarySuffixes = []string{"Ei", "Pi", "Ti", "Gi", "Mi", "Ki"}
+var decimalSuffixes = []string{"E", "P", "T", "G", "M", "k"}
+
+// Quantity renders a resource quantity in the largest unit that needs at most two decimals,
+// e.g. "1.5Gi" for "1536Mi". CPU amounts below one are
+// rendered in millicores, e.g. "250m" for "0.25". Plain numbers stay in decimal units, e.g. "1.5G" for "1500M".
+func Quantity(s string) (string, error) {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return "", serrors.InvalidValueContextErrorf(err, s, "expected a resource quantity")
+	}
+
+	value := q.AsDec()
+	if value.Sign() <= 0 {
+		return q.String(), nil
+	}
+
+	suffixes := decimalSuffixes
+	base := int64(1000)
+	if q.Format == resource.BinarySI {
+		suffixes = binarySuffixes
+		base = 1024
+	}
+
+	rat := new(big.Rat)
+	if _, ok := rat.SetString(value.String()); !ok {
+		return q.String(), nil
+	}
+
+	if rat.Cmp(big.NewRat(1, 1)) < 0 {
+		millis := new(big.Rat).Mul(rat, big.NewRat(1000, 1))
+		if millis.IsInt() {
+			return millis.Num().String() + "m", nil
+		}
+		return q.String(), nil
+	}
+
+	for i, suffix := range suffixes {
+		unit := new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(len(suffixes)-i)), nil)
+		scaled := new(big.Rat).Quo(rat, new(big.Rat).SetInt(unit))
+		if scaled.Cmp(big.NewRat(1, 1)) < 0 {
+			continue
+		}
+		if rendered, ok := withDecimals(scaled, 2); ok {
+			return rendered + suffix, nil
+		}
+	}
+
+	if rendered, ok := withDecimals(rat, 2); ok {
+		return rendered, nil
+	}
+
+	return q.String(), nil
+}
+
+// withDecimals renders a number exactly with at most the given number of decimals.
+func withDecimals(r *big.Rat, decimals int) (string, bool) {
+	s := r.FloatString(decimals)
+	exact, ok := new(big.Rat).SetString(s)
+	if !ok || exact.Cmp(r) != 0 {
+		return "", false
+	}
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	return s, true
+}
+
+// Obj renders the durations and quantities of a short object (e.g. {"deployment": {...}}) in friendlier units.
+// Kubernetes objects, e.g. ones that --partial passes through, are left as they are.
+func Obj(obj map[string]interface{}) error {
+	if _, ok := obj["kind"]; ok {
+		return nil
+	}
+
+	for _, body := range obj {
+		for _, path := range durationPaths {
+			err := update(body, strings.Split(path, "."), func(val interface{}) (interface{}, error) {
+				seconds, ok := toInt(val)
+				if !ok {
+					return val, nil
+				}
+				return Duration(seconds), nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, path := range quantityPaths {
+			err := update(body, strings.Split(path, "."), func(val interface{}) (interface{}, error) {
+				s, ok := val.(string)
+				if !ok {
+					return val, nil
+				}
+				return Quantity(s)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Objs renders the durations and quantities of short objects in friendlier units.
+func Objs(objs []interface{}) ([]interface{}, error) {
+	results := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		objMap, err := objutil.ToDictionary(obj)
+		if err != nil {
+			return nil, err
+		}
+		err = Obj(objMap)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = objMap
+	}
+
+	return results, nil
+}
+
+// ParseDurations replaces the durations of a short object with numbers of seconds, in place.
+func ParseDurations(obj map[string]interface{}) error {
+	for key, body := range obj {
+		for _, path := range durationPaths {
+			err := update(body, strings.Split(path, "."), func(val interface{}) (interface{}, error) {
+				s, ok := val.(string)
+				if !ok {
+					return val, nil
+				}
+				return ParseDuration(s)
+			})
+			if err != nil {
+				return serrors.ContextualizeErrorf(err, "%s.%s", key, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// update replaces the values at a path.
+func update(obj interface{}, segments []string, fn func(interface{}) (interface{}, error)) error {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	segment := segments[0]
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		for key, val := range obj {
+			if segment != "*" && segment != key {
+				continue
+			}
+			if len(segments) > 1 {
+				err := update(val, segments[1:], fn)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			newVal, err := fn(val)
+			if err != nil {
+				return err
+			}
+			obj[key] = newVal
+		}
+	case []interface{}:
+		if segment != "*" {
+			return nil
+		}
+		for i, val := range obj {
+			if len(segments) > 1 {
+				err := update(val, segments[1:], fn)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			newVal, err := fn(val)
+			if err != nil {
+				return err
+			}
+			obj[i] = newVal
+		}
+	}
+
+	return nil
+}
+
+func toInt(val interface{}) (int64, bool) {
+	switch val := val.(type) {
+	case int:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case float64:
+		if val == float64(int64(val)) {
+			return int64(val), true
+		}
+	}
+
+	return 0, false
+}
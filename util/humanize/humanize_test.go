@@ -0,0 +1,104 @@
+package humanize
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestDuration(t *testing.T) {
+	for seconds, expected := range map[int64]string{0: "0s", 30: "30s", 60: "1m", 90: "1m30s", 3600: "1h", 3660: "1h1m", 3601: "1h0m1s"} {
+		s := Duration(seconds)
+		if s != expected {
+			t.Errorf("%d: expected %s, got %s", seconds, expected, s)
+		}
+		parsed, err := ParseDuration(s)
+		if err != nil || parsed != seconds {
+			t.Errorf("%s: expected %d, got %d (%v)", s, seconds, parsed, err)
+		}
+	}
+
+	for _, s := range []string{"1.5s", "500ms", "soon"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	cases := map[string]string{
+		"1536Mi": "1.5Gi",
+		"1000Mi": "1000Mi",
+		"1Gi":    "1Gi",
+		"1500M":  "1.5G",
+		"0.25":   "250m",
+		"1500m":  "1.5",
+		"2":      "2",
+		"100m":   "100m",
+		"0":      "0",
+	}
+	for s, expected := range cases {
+		rendered, err := Quantity(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if rendered != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, rendered)
+		}
+	}
+}
+
+func TestObj(t *testing.T) {
+	obj := map[string]interface{}{
+		"deployment": map[string]interface{}{
+			"min_ready": float64(90),
+			"containers": []interface{}{
+				map[string]interface{}{
+					"mem":            map[string]interface{}{"max": "1536Mi"},
+					"liveness_probe": map[string]interface{}{"delay": float64(120)},
+				},
+			},
+			"volumes": map[string]interface{}{
+				"cache": map[string]interface{}{"vol_type": "empty_dir", "max_size": "500M"},
+			},
+		},
+	}
+	expected := map[string]interface{}{
+		"deployment": map[string]interface{}{
+			"min_ready": "1m30s",
+			"containers": []interface{}{
+				map[string]interface{}{
+					"mem":            map[string]interface{}{"max": "1.5Gi"},
+					"liveness_probe": map[string]interface{}{"delay": "2m"},
+				},
+			},
+			"volumes": map[string]interface{}{
+				"cache": map[string]interface{}{"vol_type": "empty_dir", "max_size": "500M"},
+			},
+		},
+	}
+
+	err := Obj(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("%s", pretty.Diff(expected, obj))
+	}
+
+	err = ParseDurations(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := obj["deployment"].(map[string]interface{})
+	if body["min_ready"] != int64(90) {
+		t.Errorf("expected min_ready to be read back as 90 seconds, got %#v", body["min_ready"])
+	}
+
+	body["min_ready"] = "1.5s"
+	if err := ParseDurations(obj); err == nil {
+		t.Errorf("expected an error for a fractional duration")
+	}
+}
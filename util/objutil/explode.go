@@ -0,0 +1,316 @@
+package objutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Exploded documents have one leaf value per line, addressed by its full path:
+
+	pod.containers[0].image = "nginx"
+	pod.labels["app.kubernetes.io/name"] = "web"
+	pod.volumes = {}
+
+Map keys are written as ".key", or as ["key"] if they aren't plain identifiers.
+List indices are written as [i]. Values are JSON. Documents are separated by "---".
+
+*/
+
+var plainKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_\-]*$`)
+
+// Explode writes each document as one line per leaf value.
+func Explode(objs []interface{}, w io.Writer) error {
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		lines, err := ExplodeObj(obj)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Leaf is a single value of an exploded document.
+type Leaf struct {
+	Path string
+	// Value is JSON.
+	Value string
+}
+
+func (l Leaf) String() string {
+	return fmt.Sprintf("%s = %s", l.Path, l.Value)
+}
+
+// ExplodeObj returns the lines for a single document.
+func ExplodeObj(obj interface{}) ([]string, error) {
+	leaves, err := ExplodeLeaves(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, len(leaves))
+	for i, leaf := range leaves {
+		lines[i] = leaf.String()
+	}
+
+	return lines, nil
+}
+
+// ExplodeLeaves returns the leaf values of a single document, in the order they're written by Explode.
+func ExplodeLeaves(obj interface{}) ([]Leaf, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, obj, "marshalling to JSON")
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(b, &generic)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(b), "converting to dictionary")
+	}
+
+	leaves := []Leaf{}
+	return explodeAny("", generic, leaves)
+}
+
+func explodeAny(path string, obj interface{}, leaves []Leaf) ([]Leaf, error) {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		if len(obj) == 0 {
+			return append(leaves, Leaf{path, "{}"}), nil
+		}
+
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var err error
+		for _, key := range keys {
+			leaves, err = explodeAny(path+keySegment(key, len(path) == 0), obj[key], leaves)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return leaves, nil
+	case []interface{}:
+		if len(obj) == 0 {
+			return append(leaves, Leaf{path, "[]"}), nil
+		}
+
+		var err error
+		for i, val := range obj {
+			leaves, err = explodeAny(fmt.Sprintf("%s[%d]", path, i), val, leaves)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return leaves, nil
+	default:
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return nil, serrors.InvalidValueContextErrorf(err, obj, "marshalling value to JSON")
+		}
+		return append(leaves, Leaf{path, string(b)}), nil
+	}
+}
+
+func keySegment(key string, isRoot bool) string {
+	if plainKeyRegexp.MatchString(key) {
+		if isRoot {
+			return key
+		}
+		return "." + key
+	}
+
+	return fmt.Sprintf("[%s]", strconv.Quote(key))
+}
+
+// Implode is the inverse of Explode.
+func Implode(r io.Reader) ([]map[string]interface{}, error) {
+	objs := []map[string]interface{}{}
+	var obj map[string]interface{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		if line == "---" {
+			if obj != nil {
+				objs = append(objs, obj)
+			}
+			obj = nil
+			continue
+		}
+
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+
+		path, val, err := parseExplodedLine(line)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "line %d", lineNum)
+		}
+
+		newObj, err := setAtPath(obj, path, val)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "line %d", lineNum)
+		}
+		obj = newObj.(map[string]interface{})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if obj != nil {
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+// A path segment is either a map key or a list index.
+type pathSegment struct {
+	key   string
+	index int
+	isKey bool
+}
+
+func parseExplodedLine(line string) ([]pathSegment, interface{}, error) {
+	path := []pathSegment{}
+	i := 0
+	for i < len(line) && line[i] != ' ' && line[i] != '=' {
+		switch {
+		case line[i] == '.':
+			i++
+			fallthrough
+		case len(path) == 0 && line[i] != '[':
+			j := i
+			for j < len(line) && line[j] != '.' && line[j] != '[' && line[j] != ' ' && line[j] != '=' {
+				j++
+			}
+			if j == i {
+				return nil, nil, serrors.InvalidValueErrorf(line, "empty key at column %d", i)
+			}
+			path = append(path, pathSegment{key: line[i:j], isKey: true})
+			i = j
+		case line[i] == '[':
+			end := strings.IndexByte(line[i:], ']')
+			if i+1 < len(line) && line[i+1] == '"' {
+				// Quoted keys may contain ']', so find the closing quote first.
+				quoted, err := strconv.QuotedPrefix(line[i+1:])
+				if err != nil {
+					return nil, nil, serrors.InvalidValueContextErrorf(err, line, "bad quoted key at column %d", i)
+				}
+				key, _ := strconv.Unquote(quoted)
+				end = 1 + len(quoted)
+				if i+end >= len(line) || line[i+end] != ']' {
+					return nil, nil, serrors.InvalidValueErrorf(line, "expected ] at column %d", i+end)
+				}
+				path = append(path, pathSegment{key: key, isKey: true})
+			} else {
+				if end < 0 {
+					return nil, nil, serrors.InvalidValueErrorf(line, "expected ] after column %d", i)
+				}
+				index, err := strconv.Atoi(line[i+1 : i+end])
+				if err != nil || index < 0 {
+					return nil, nil, serrors.InvalidValueErrorf(line, "bad list index at column %d", i)
+				}
+				path = append(path, pathSegment{index: index})
+			}
+			i += end + 1
+		default:
+			return nil, nil, serrors.InvalidValueErrorf(line, "unexpected character at column %d", i)
+		}
+	}
+
+	rest := strings.TrimSpace(line[i:])
+	if !strings.HasPrefix(rest, "=") {
+		return nil, nil, serrors.InvalidValueErrorf(line, "expected '=' after path")
+	}
+	if len(path) == 0 || !path[0].isKey {
+		return nil, nil, serrors.InvalidValueErrorf(line, "path must start with a key")
+	}
+
+	var val interface{}
+	valString := strings.TrimSpace(rest[1:])
+	decoder := json.NewDecoder(bytes.NewReader([]byte(valString)))
+	decoder.UseNumber()
+	err := decoder.Decode(&val)
+	if err != nil {
+		return nil, nil, serrors.InvalidValueContextErrorf(err, valString, "value should be JSON")
+	}
+
+	return path, val, nil
+}
+
+func setAtPath(obj interface{}, path []pathSegment, val interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+
+	seg := path[0]
+	if seg.isKey {
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+		objMap, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, serrors.InvalidValueErrorf(seg.key, "can't set key on non-dictionary")
+		}
+
+		newVal, err := setAtPath(objMap[seg.key], path[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		objMap[seg.key] = newVal
+		return objMap, nil
+	}
+
+	if obj == nil {
+		obj = []interface{}{}
+	}
+	objSlice, ok := obj.([]interface{})
+	if !ok {
+		return nil, serrors.InvalidValueErrorf(seg.index, "can't index into non-list")
+	}
+	for len(objSlice) <= seg.index {
+		objSlice = append(objSlice, nil)
+	}
+
+	newVal, err := setAtPath(objSlice[seg.index], path[1:], val)
+	if err != nil {
+		return nil, err
+	}
+	objSlice[seg.index] = newVal
+	return objSlice, nil
+}
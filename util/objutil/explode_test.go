@@ -0,0 +1,91 @@
+package objutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+
+	"github.com/koki/short/yaml"
+)
+
+var doc0 = `
+pod:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+    "with \"quotes\" and ]": x
+  containers:
+  - name: nginx
+    args: [1, 2.5, true, null]
+    env: []
+  volumes: {}
+`
+
+var exploded0 = `pod.containers[0].args[0] = 1
+pod.containers[0].args[1] = 2.5
+pod.containers[0].args[2] = true
+pod.containers[0].args[3] = null
+pod.containers[0].env = []
+pod.containers[0].name = "nginx"
+pod.labels["app.kubernetes.io/name"] = "web"
+pod.labels["with \"quotes\" and ]"] = "x"
+pod.name = "web"
+pod.volumes = {}
+`
+
+func TestExplodeImplode(t *testing.T) {
+	obj := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(doc0), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Explode([]interface{}{obj, obj}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != exploded0+"---\n"+exploded0 {
+		t.Errorf("unexpected exploded output:\n%s", buf.String())
+	}
+
+	objs, err := Implode(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(objs))
+	}
+
+	for _, imploded := range objs {
+		b, err := yaml.Marshal(imploded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roundTripped := map[string]interface{}{}
+		err = yaml.Unmarshal(b, &roundTripped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(obj, roundTripped) {
+			t.Error(pretty.Diff(obj, roundTripped))
+		}
+	}
+}
+
+func TestImplodeErrors(t *testing.T) {
+	for _, line := range []string{
+		`pod.name "web"`,
+		`pod.name = web`,
+		`pod.containers[x] = 1`,
+		`[0] = 1`,
+		`pod.name = "a"` + "\n" + `pod.name.first = "b"`,
+	} {
+		if _, err := Implode(strings.NewReader(line)); err == nil {
+			t.Errorf("expected error for (%s)", line)
+		}
+	}
+}
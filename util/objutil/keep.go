@@ -0,0 +1,106 @@
+package objutil
+
+import (
+	"sort"
+	"strings"
+)
+
+// keptItems are the list items kept so far, by their index in the original list.
+type keptItems map[int]interface{}
+
+// keptWhole is a value that's kept with everything in it, so later paths into it don't narrow it down.
+type keptWhole struct {
+	val interface{}
+}
+
+// KeepPaths copies the values at the paths (see SelectPath) out of obj, along with the maps and lists
+// that lead to them, and leaves out everything else. A leading "$." is optional.
+// It returns nil if none of the paths are in obj.
+func KeepPaths(obj interface{}, paths []string) (interface{}, error) {
+	var kept interface{}
+	for _, path := range paths {
+		segs, err := splitSelectPath(strings.TrimPrefix(path, "$."))
+		if err != nil {
+			return nil, err
+		}
+		kept = keepPath(obj, segs, kept)
+	}
+
+	return finishKept(kept), nil
+}
+
+// keepPath adds the values at segs in obj to what's already kept from obj.
+func keepPath(obj interface{}, segs []string, kept interface{}) interface{} {
+	if _, ok := kept.(keptWhole); ok {
+		return kept
+	}
+	if len(segs) == 0 {
+		return keptWhole{obj}
+	}
+
+	seg := segs[0]
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		keys := []string{seg}
+		if seg == "*" {
+			keys = sortedKeys(obj)
+		}
+		keptMap, _ := kept.(map[string]interface{})
+		for _, key := range keys {
+			val, ok := obj[key]
+			if !ok {
+				continue
+			}
+			if child := keepPath(val, segs[1:], keptMap[key]); child != nil {
+				if keptMap == nil {
+					keptMap = map[string]interface{}{}
+				}
+				keptMap[key] = child
+			}
+		}
+		if keptMap != nil {
+			return keptMap
+		}
+	case []interface{}:
+		items, _ := kept.(keptItems)
+		for _, i := range listIndices(obj, seg) {
+			if child := keepPath(obj[i], segs[1:], items[i]); child != nil {
+				if items == nil {
+					items = keptItems{}
+				}
+				items[i] = child
+			}
+		}
+		if items != nil {
+			return items
+		}
+	}
+
+	return kept
+}
+
+// finishKept turns the kept list items back into lists, in their original order, and unwraps whole values.
+func finishKept(kept interface{}) interface{} {
+	switch kept := kept.(type) {
+	case map[string]interface{}:
+		for key, val := range kept {
+			kept[key] = finishKept(val)
+		}
+	case keptItems:
+		indices := []int{}
+		for i := range kept {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+
+		list := make([]interface{}, len(indices))
+		for j, i := range indices {
+			list[j] = finishKept(kept[i])
+		}
+		return list
+	case keptWhole:
+		return kept.val
+	}
+
+	return kept
+}
@@ -0,0 +1,86 @@
+package objutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestKeepPaths(t *testing.T) {
+	obj := func() map[string]interface{} {
+		return map[string]interface{}{
+			"deployment": map[string]interface{}{
+				"name":     "web",
+				"replicas": 2,
+				"containers": []interface{}{
+					map[string]interface{}{"name": "web", "image": "nginx", "cpu": "100m"},
+					map[string]interface{}{"name": "log", "image": "fluentd"},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		paths    []string
+		expected interface{}
+	}{
+		{
+			paths: []string{"$.deployment.containers"},
+			expected: map[string]interface{}{
+				"deployment": map[string]interface{}{
+					"containers": obj()["deployment"].(map[string]interface{})["containers"],
+				},
+			},
+		},
+		{
+			paths: []string{"deployment.name", "deployment.containers[name=log].image"},
+			expected: map[string]interface{}{
+				"deployment": map[string]interface{}{
+					"name": "web",
+					"containers": []interface{}{
+						map[string]interface{}{"image": "fluentd"},
+					},
+				},
+			},
+		},
+		{
+			paths: []string{"deployment.containers[1].name", "deployment.containers.*.image"},
+			expected: map[string]interface{}{
+				"deployment": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"image": "nginx"},
+						map[string]interface{}{"name": "log", "image": "fluentd"},
+					},
+				},
+			},
+		},
+		{
+			// A value that's kept whole isn't narrowed down by a later path into it.
+			paths: []string{"deployment.containers", "deployment.containers[0].image"},
+			expected: map[string]interface{}{
+				"deployment": map[string]interface{}{
+					"containers": obj()["deployment"].(map[string]interface{})["containers"],
+				},
+			},
+		},
+		{
+			paths:    []string{"service.name"},
+			expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		kept, err := KeepPaths(obj(), testCase.paths)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(kept, testCase.expected) {
+			t.Errorf("case %d: %s", i, pretty.Sprint(pretty.Diff(kept, testCase.expected)))
+		}
+	}
+
+	if _, err := KeepPaths(obj(), []string{"deployment.containers[0"}); err == nil {
+		t.Error("expected an error for an unclosed [")
+	}
+}
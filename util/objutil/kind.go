@@ -0,0 +1,10 @@
+package objutil
+
+import (
+	"strings"
+)
+
+// NormalizeKind makes short and Kubernetes kinds comparable, e.g. "stateful_set" and "StatefulSet".
+func NormalizeKind(kind string) string {
+	return strings.ToLower(strings.Replace(kind, "_", "", -1))
+}
@@ -0,0 +1,147 @@
+package objutil
+
+import (
+	"bytes"
+	"sort"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// jsonField is a top-level entry of a JSON object: its quoted key and its value, as they are.
+type jsonField struct {
+	key   []byte
+	value []byte
+}
+
+// MergeJSONObjects merges the top-level fields of JSON objects into one object, sorted by key.
+// A field in a later object replaces the same field in an earlier one.
+// Values are copied as they are, so nothing is decoded and encoded again.
+func MergeJSONObjects(objs ...[]byte) ([]byte, error) {
+	fields := []jsonField{}
+	indices := map[string]int{}
+	size := 2
+	for _, obj := range objs {
+		objFields, err := splitJSONObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range objFields {
+			if i, ok := indices[string(field.key)]; ok {
+				fields[i] = field
+				continue
+			}
+			indices[string(field.key)] = len(fields)
+			fields = append(fields, field)
+		}
+		size += len(obj)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return bytes.Compare(fields[i].key, fields[j].key) < 0
+	})
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(field.key)
+		buf.WriteByte(':')
+		buf.Write(field.value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// splitJSONObject lists the top-level fields of a JSON object without decoding their values.
+func splitJSONObject(obj []byte) ([]jsonField, error) {
+	obj = bytes.TrimSpace(obj)
+	if len(obj) < 2 || obj[0] != '{' || obj[len(obj)-1] != '}' {
+		return nil, serrors.InvalidValueErrorf(string(obj), "expected a JSON object")
+	}
+
+	body := obj[1 : len(obj)-1]
+	fields := []jsonField{}
+	i := skipJSONSpace(body, 0)
+	for i < len(body) {
+		if body[i] != '"' {
+			return nil, serrors.InvalidValueErrorf(string(obj), "expected a key at offset %d", i+1)
+		}
+		keyEnd, ok := scanJSONString(body, i)
+		if !ok {
+			return nil, serrors.InvalidValueErrorf(string(obj), "unterminated key at offset %d", i+1)
+		}
+		key := body[i:keyEnd]
+
+		i = skipJSONSpace(body, keyEnd)
+		if i == len(body) || body[i] != ':' {
+			return nil, serrors.InvalidValueErrorf(string(obj), "expected ':' after key %s", key)
+		}
+
+		valueStart := skipJSONSpace(body, i+1)
+		valueEnd, ok := scanJSONValue(body, valueStart)
+		if !ok || valueEnd == valueStart {
+			return nil, serrors.InvalidValueErrorf(string(obj), "expected a value for key %s", key)
+		}
+		fields = append(fields, jsonField{key: key, value: bytes.TrimSpace(body[valueStart:valueEnd])})
+
+		i = valueEnd
+		if i < len(body) {
+			// scanJSONValue stops at the separating comma.
+			i = skipJSONSpace(body, i+1)
+		}
+	}
+
+	return fields, nil
+}
+
+func skipJSONSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\n' || b[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// scanJSONString returns the offset just past the string that starts at b[i].
+func scanJSONString(b []byte, i int) (int, bool) {
+	for j := i + 1; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, true
+		}
+	}
+	return 0, false
+}
+
+// scanJSONValue returns the offset of the comma after the value that starts at b[i], or len(b).
+func scanJSONValue(b []byte, i int) (int, bool) {
+	depth := 0
+	for i < len(b) {
+		switch b[i] {
+		case '"':
+			end, ok := scanJSONString(b, i)
+			if !ok {
+				return 0, false
+			}
+			i = end
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth < 0 {
+				return 0, false
+			}
+		case ',':
+			if depth == 0 {
+				return i, true
+			}
+		}
+		i++
+	}
+	return i, depth == 0
+}
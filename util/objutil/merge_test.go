@@ -0,0 +1,38 @@
+package objutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/koki/json"
+	"github.com/kr/pretty"
+)
+
+func TestMergeJSONObjects(t *testing.T) {
+	merged, err := MergeJSONObjects(
+		[]byte(`{"vol_type":"rbd","ro":true,"name":"old","nested":{"a":[1,{"b":"},"}]}}`),
+		[]byte(` { "name" : "pv-0", "labels":{"app":"db"}, "quote\"d":"x\\"} `),
+		[]byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"labels":{"app":"db"},"name":"pv-0","nested":{"a":[1,{"b":"},"}]},"quote\"d":"x\\","ro":true,"vol_type":"rbd"}`
+	if string(merged) != expected {
+		t.Error(pretty.Sprint(string(merged), expected))
+	}
+
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(merged, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(obj["name"], "pv-0") {
+		t.Error(pretty.Sprint(obj))
+	}
+
+	for _, invalid := range []string{``, `[]`, `{"a"}`, `{"a":}`, `{a:1}`, `{"a":[1}`, `{"a":"b}`} {
+		if _, err := MergeJSONObjects([]byte(invalid)); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
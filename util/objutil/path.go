@@ -3,9 +3,26 @@ package objutil
 import (
 	"strconv"
 
+	"github.com/koki/json"
 	serrors "github.com/koki/structurederrors"
 )
 
+// ToDictionary converts a typed object to a dictionary through its JSON form.
+func ToDictionary(obj interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, serrors.InvalidInstanceContextErrorf(err, obj, "marshalling to JSON")
+	}
+
+	objMap := map[string]interface{}{}
+	err = json.Unmarshal(b, &objMap)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, string(b), "converting to dictionary")
+	}
+
+	return objMap, nil
+}
+
 func AtPathIn(obj interface{}, path []string) (interface{}, error) {
 	if len(path) == 0 {
 		return obj, nil
@@ -41,3 +58,21 @@ func AtPathIn(obj interface{}, path []string) (interface{}, error) {
 		return nil, serrors.InvalidValueErrorf(key, "can only index into slice or map")
 	}
 }
+
+// Labels returns the metadata.labels of a Kubernetes object in dictionary form, or nil if it has none.
+func Labels(obj map[string]interface{}) map[string]string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	objLabels, _ := metadata["labels"].(map[string]interface{})
+	if len(objLabels) == 0 {
+		return nil
+	}
+
+	labels := map[string]string{}
+	for key, val := range objLabels {
+		if s, ok := val.(string); ok {
+			labels[key] = s
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,129 @@
+package objutil
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	serrors "github.com/koki/structurederrors"
+)
+
+// SelectPath finds the values at a dotted path, e.g. "containers.*.image", "containers[0].image"
+// or "containers[name=web].image". "*" selects every entry of a map or list, and "name=..." selects
+// the list items with that name. Missing entries are skipped.
+func SelectPath(obj interface{}, path string) ([]interface{}, error) {
+	segs, err := splitSelectPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := []interface{}{obj}
+	for _, seg := range segs {
+		next := []interface{}{}
+		for _, val := range vals {
+			switch val := val.(type) {
+			case map[string]interface{}:
+				if seg == "*" {
+					for _, key := range sortedKeys(val) {
+						next = append(next, val[key])
+					}
+				} else if entry, ok := val[seg]; ok {
+					next = append(next, entry)
+				}
+			case []interface{}:
+				for _, i := range listIndices(val, seg) {
+					next = append(next, val[i])
+				}
+			}
+		}
+		vals = next
+	}
+
+	return vals, nil
+}
+
+// listIndices returns the indices of the list items that a path segment selects.
+func listIndices(list []interface{}, seg string) []int {
+	indices := []int{}
+	switch {
+	case seg == "*":
+		for i := range list {
+			indices = append(indices, i)
+		}
+	case strings.HasPrefix(seg, "name="):
+		name := strings.TrimPrefix(seg, "name=")
+		for i, item := range list {
+			if itemMap, ok := item.(map[string]interface{}); ok && itemMap["name"] == name {
+				indices = append(indices, i)
+			}
+		}
+	default:
+		if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(list) {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+func splitSelectPath(path string) ([]string, error) {
+	segs := []string{}
+	seg := ""
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			if len(seg) > 0 {
+				segs = append(segs, seg)
+			}
+			seg = ""
+		case '[':
+			if len(seg) > 0 {
+				segs = append(segs, seg)
+			}
+			seg = ""
+
+			rest := path[i+1:]
+			if strings.HasPrefix(rest, `"`) {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return nil, serrors.InvalidValueContextErrorf(err, path, "bad quoted key")
+				}
+				key, _ := strconv.Unquote(quoted)
+				rest = rest[len(quoted):]
+				if !strings.HasPrefix(rest, "]") {
+					return nil, serrors.InvalidValueErrorf(path, "expected ] after quoted key")
+				}
+				segs = append(segs, key)
+				i += len(quoted) + 1
+				continue
+			}
+
+			end := strings.IndexByte(rest, ']')
+			if end <= 0 {
+				return nil, serrors.InvalidValueErrorf(path, "expected index between [ and ]")
+			}
+			segs = append(segs, rest[:end])
+			i += end + 1
+		default:
+			seg += string(path[i])
+		}
+	}
+	if len(seg) > 0 {
+		segs = append(segs, seg)
+	}
+
+	if len(segs) == 0 {
+		return nil, serrors.InvalidValueErrorf(path, "empty path")
+	}
+
+	return segs, nil
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
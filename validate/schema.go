@@ -0,0 +1,129 @@
+package validate
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/koki/json"
+	serrors "github.com/koki/structurederrors"
+)
+
+// Schema is a Kubernetes OpenAPI (v2) document, as served by the API server at /openapi/v2.
+type Schema struct {
+	Definitions map[string]*Definition `json:"definitions"`
+
+	// definitionsByKind maps "group/version/kind" to the name of its definition.
+	definitionsByKind map[string]string
+}
+
+// Definition is the schema of a value.
+type Definition struct {
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*Definition `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *Definition            `json:"items,omitempty"`
+	// AdditionalProperties is either a Definition or a boolean.
+	AdditionalProperties json.RawMessage    `json:"additionalProperties,omitempty"`
+	GroupVersionKinds    []GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// GroupVersionKind identifies the kind a definition is for.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+func (gvk GroupVersionKind) String() string {
+	return gvk.Group + "/" + gvk.Version + "/" + gvk.Kind
+}
+
+// LoadSchema reads an OpenAPI document, e.g. from "kubectl get --raw /openapi/v2".
+func LoadSchema(data []byte) (*Schema, error) {
+	schema := &Schema{}
+	err := json.Unmarshal(data, schema)
+	if err != nil {
+		return nil, serrors.InvalidValueContextErrorf(err, "(OpenAPI document)", "parsing OpenAPI schema")
+	}
+	if len(schema.Definitions) == 0 {
+		return nil, serrors.InvalidValueErrorf("(OpenAPI document)", "OpenAPI schema has no definitions")
+	}
+
+	schema.definitionsByKind = map[string]string{}
+	for name, definition := range schema.Definitions {
+		for _, gvk := range definition.GroupVersionKinds {
+			schema.definitionsByKind[gvk.String()] = name
+		}
+	}
+
+	return schema, nil
+}
+
+// FetchSchema reads the OpenAPI document of a live cluster, using kubectl and its current context.
+func FetchSchema(kubeContext string) (*Schema, error) {
+	args := []string{"get", "--raw", "/openapi/v2"}
+	if len(kubeContext) > 0 {
+		args = append(args, "--context", kubeContext)
+	}
+
+	cmd := exec.Command("kubectl", args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, serrors.ContextualizeErrorf(err, "kubectl %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return LoadSchema(out)
+}
+
+// definitionFor finds the definition of a Kubernetes object's apiVersion and kind.
+func (s *Schema) definitionFor(apiVersion, kind string) (*Definition, bool) {
+	group, version := "", apiVersion
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		group, version = apiVersion[:i], apiVersion[i+1:]
+	}
+
+	name, ok := s.definitionsByKind[GroupVersionKind{Group: group, Version: version, Kind: kind}.String()]
+	if !ok {
+		return nil, false
+	}
+
+	return s.Definitions[name], true
+}
+
+// resolve follows a definition's $ref, e.g. "#/definitions/io.k8s.api.core.v1.PodSpec".
+func (s *Schema) resolve(definition *Definition) (*Definition, error) {
+	for definition != nil && len(definition.Ref) > 0 {
+		name := strings.TrimPrefix(definition.Ref, "#/definitions/")
+		resolved, ok := s.Definitions[name]
+		if !ok {
+			return nil, serrors.InvalidValueErrorf(definition.Ref, "OpenAPI schema has no such definition")
+		}
+		definition = resolved
+	}
+
+	return definition, nil
+}
+
+// additionalProperties returns the definition of a dictionary's values, or false if it doesn't allow any keys but its properties.
+func (s *Schema) additionalProperties(definition *Definition) (*Definition, bool) {
+	raw := strings.TrimSpace(string(definition.AdditionalProperties))
+	switch raw {
+	case "", "false":
+		return nil, false
+	case "true":
+		return &Definition{}, true
+	}
+
+	additional := &Definition{}
+	if err := json.Unmarshal(definition.AdditionalProperties, additional); err != nil {
+		return &Definition{}, true
+	}
+
+	return additional, true
+}
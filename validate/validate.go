@@ -0,0 +1,238 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/koki/short/client"
+	"github.com/koki/short/parser"
+	"github.com/koki/short/util/objutil"
+	serrors "github.com/koki/structurederrors"
+)
+
+/*
+
+Validate checks manifests against the OpenAPI schema of a Kubernetes release before they're applied.
+
+Short manifests are converted to Kubernetes objects first, so the schema checks what would be
+sent to the API server: fields the release doesn't know, values of the wrong type, and required
+fields that are missing.
+
+*/
+
+// Problem is something wrong with an object.
+type Problem struct {
+	File string
+	Kind string
+	Name string
+	// Path is the location of the problem in the Kubernetes object, e.g. "$.spec.template.spec.containers.0.image".
+	Path    string
+	Message string
+	// Labels are the object's labels, to find its owner (see the owners package).
+	Labels map[string]string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (%s): %s: %s", p.File, p.Kind, p.Name, p.Path, p.Message)
+}
+
+// Files validates the objects in each file, in short or Kubernetes syntax.
+func (s *Schema) Files(files []string) ([]Problem, error) {
+	problems := []Problem{}
+	for _, file := range files {
+		objs, err := parser.Parse([]string{file}, false)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "parsing %s", file)
+		}
+
+		kokiObjs, err := client.ConvertEitherMapsToKoki(objs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		kubeObjs, err := client.ConvertKokiMaps(kokiObjs)
+		if err != nil {
+			return nil, serrors.ContextualizeErrorf(err, "converting %s", file)
+		}
+
+		for _, kubeObj := range kubeObjs {
+			obj, err := objutil.ToDictionary(kubeObj)
+			if err != nil {
+				return nil, err
+			}
+
+			objProblems, err := s.Validate(obj)
+			if err != nil {
+				return nil, serrors.ContextualizeErrorf(err, "validating %s", file)
+			}
+			for _, problem := range objProblems {
+				problem.File = file
+				problem.Labels = objutil.Labels(obj)
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// Validate checks a Kubernetes object against the schema of its apiVersion and kind.
+func (s *Schema) Validate(obj map[string]interface{}) ([]Problem, error) {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	name := ""
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+
+	definition, ok := s.definitionFor(apiVersion, kind)
+	if !ok {
+		return []Problem{{Kind: kind, Name: name, Path: "$", Message: fmt.Sprintf("%s %s isn't served by this Kubernetes release", apiVersion, kind)}}, nil
+	}
+
+	messages := map[string][]string{}
+	err := s.check("$", obj, definition, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range messages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	problems := []Problem{}
+	for _, path := range paths {
+		for _, message := range messages[path] {
+			problems = append(problems, Problem{Kind: kind, Name: name, Path: path, Message: message})
+		}
+	}
+
+	return problems, nil
+}
+
+func (s *Schema) check(path string, val interface{}, definition *Definition, messages map[string][]string) error {
+	definition, err := s.resolve(definition)
+	if err != nil {
+		return err
+	}
+	if definition == nil || val == nil {
+		return nil
+	}
+
+	switch definition.Type {
+	case "object":
+		return s.checkObject(path, val, definition, messages)
+	case "array":
+		list, ok := val.([]interface{})
+		if !ok {
+			messages[path] = append(messages[path], fmt.Sprintf("expected a list, got %s", describe(val)))
+			return nil
+		}
+		for i, item := range list {
+			err := s.check(fmt.Sprintf("%s.%d", path, i), item, definition.Items, messages)
+			if err != nil {
+				return err
+			}
+		}
+	case "string":
+		_, isString := val.(string)
+		if !isString && !(definition.Format == "int-or-string" && isNumber(val)) {
+			messages[path] = append(messages[path], fmt.Sprintf("expected a string, got %s", describe(val)))
+		}
+	case "integer":
+		if !isInteger(val) {
+			messages[path] = append(messages[path], fmt.Sprintf("expected an integer, got %s", describe(val)))
+		}
+	case "number":
+		if !isNumber(val) {
+			messages[path] = append(messages[path], fmt.Sprintf("expected a number, got %s", describe(val)))
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			messages[path] = append(messages[path], fmt.Sprintf("expected true or false, got %s", describe(val)))
+		}
+	default:
+		// Untyped definitions without properties, e.g. RawExtension, accept anything.
+		if len(definition.Properties) > 0 {
+			return s.checkObject(path, val, definition, messages)
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) checkObject(path string, val interface{}, definition *Definition, messages map[string][]string) error {
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		messages[path] = append(messages[path], fmt.Sprintf("expected a dictionary, got %s", describe(val)))
+		return nil
+	}
+
+	for _, required := range definition.Required {
+		if fieldVal, ok := obj[required]; !ok || fieldVal == nil {
+			messages[path+"."+required] = append(messages[path+"."+required], "required field is missing")
+		}
+	}
+
+	additional, allowsAdditional := s.additionalProperties(definition)
+	for key, fieldVal := range obj {
+		fieldPath := path + "." + key
+		if fieldDefinition, ok := definition.Properties[key]; ok {
+			if err := s.check(fieldPath, fieldVal, fieldDefinition, messages); err != nil {
+				return err
+			}
+			continue
+		}
+		if allowsAdditional {
+			if err := s.check(fieldPath, fieldVal, additional, messages); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(definition.Properties) > 0 {
+			messages[fieldPath] = append(messages[fieldPath], "unknown field")
+		}
+	}
+
+	return nil
+}
+
+func isNumber(val interface{}) bool {
+	switch val.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isInteger(val interface{}) bool {
+	switch val := val.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return val == math.Trunc(val)
+	case float32:
+		return float64(val) == math.Trunc(float64(val))
+	default:
+		return false
+	}
+}
+
+func describe(val interface{}) string {
+	switch val.(type) {
+	case string:
+		return fmt.Sprintf("the string %q", val)
+	case bool:
+		return fmt.Sprintf("%t", val)
+	case map[string]interface{}:
+		return "a dictionary"
+	case []interface{}:
+		return "a list"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,140 @@
+package validate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+var schema0 = `{
+  "definitions": {
+    "io.k8s.api.core.v1.Pod": {
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.core.v1.PodSpec"}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Pod"}]
+    },
+    "io.k8s.api.core.v1.PodSpec": {
+      "required": ["containers"],
+      "properties": {
+        "containers": {"type": "array", "items": {"$ref": "#/definitions/io.k8s.api.core.v1.Container"}},
+        "hostNetwork": {"type": "boolean"}
+      }
+    },
+    "io.k8s.api.core.v1.Container": {
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "image": {"type": "string"},
+        "resources": {"type": "object"},
+        "ports": {"type": "array", "items": {"$ref": "#/definitions/io.k8s.api.core.v1.ContainerPort"}}
+      }
+    },
+    "io.k8s.api.core.v1.ContainerPort": {
+      "required": ["containerPort"],
+      "properties": {
+        "containerPort": {"type": "integer", "format": "int32"},
+        "protocol": {"type": "string"}
+      }
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "properties": {
+        "name": {"type": "string"},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    }
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	schema, err := LoadSchema([]byte(schema0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":   "web",
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"hostNetwork": "yes",
+			"containers": []interface{}{
+				map[string]interface{}{
+					"image":      "nginx",
+					"stdinOnce2": true,
+					"ports": []interface{}{
+						map[string]interface{}{"containerPort": 80.5},
+					},
+				},
+			},
+		},
+	}
+
+	problems, err := schema.Validate(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Problem{
+		{Kind: "Pod", Name: "web", Path: "$.spec.containers.0.name", Message: "required field is missing"},
+		{Kind: "Pod", Name: "web", Path: "$.spec.containers.0.ports.0.containerPort", Message: "expected an integer, got 80.5"},
+		{Kind: "Pod", Name: "web", Path: "$.spec.containers.0.stdinOnce2", Message: "unknown field"},
+		{Kind: "Pod", Name: "web", Path: "$.spec.hostNetwork", Message: `expected true or false, got the string "yes"`},
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Error(pretty.Diff(problems, expected))
+	}
+
+	problems, err = schema.Validate(map[string]interface{}{"apiVersion": "v1", "kind": "Service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Message != "v1 Service isn't served by this Kubernetes release" {
+		t.Error(pretty.Sprint(problems))
+	}
+}
+
+func TestFiles(t *testing.T) {
+	schema, err := LoadSchema([]byte(schema0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "pod.short.yaml")
+	err = ioutil.WriteFile(file, []byte(`
+pod:
+  name: web
+  containers:
+  - name: web
+    image: nginx
+    expose:
+    - 80
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := schema.Files([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Error(pretty.Sprint(problems))
+	}
+}
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/koki/short/parser"
+)
+
+/*
+
+Watch notices when input files change, for a dev loop that converts or applies manifests
+on every save.
+
+It polls the files' modification times and sizes, since fsnotify isn't vendored. Directories
+are expanded again on every poll, so new files are picked up too.
+
+*/
+
+// stamp identifies a version of a file.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls files and directories for changes.
+type Watcher struct {
+	// Paths are the files and directories to watch.
+	Paths []string
+
+	seen map[string]stamp
+}
+
+// NewWatcher watches files and directories. Its first poll reports every file.
+func NewWatcher(paths []string) *Watcher {
+	return &Watcher{
+		Paths: paths,
+		seen:  map[string]stamp{},
+	}
+}
+
+// Poll lists the files that are new or have changed since the last poll, in order.
+// Removed files are forgotten, so they count as new if they come back.
+func (w *Watcher) Poll() ([]string, error) {
+	files, err := parser.ExpandFilenames(w.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]stamp{}
+	changed := []string{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			// Removed since the directory was read.
+			continue
+		}
+
+		s := stamp{modTime: info.ModTime(), size: info.Size()}
+		seen[file] = s
+		if previous, ok := w.seen[file]; !ok || previous != s {
+			changed = append(changed, file)
+		}
+	}
+	w.seen = seen
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Run polls every interval until stop is closed, calling onChange with each batch of changed files.
+// An error from onChange is passed to onError instead of stopping the watch, so a bad save doesn't end it.
+func (w *Watcher) Run(interval time.Duration, stop <-chan struct{}, onChange func(files []string) error, onError func(err error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := w.Poll()
+		if err != nil {
+			return err
+		}
+		if len(changed) > 0 {
+			if err := onChange(changed); err != nil {
+				onError(err)
+			}
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+func TestPoll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a.short.yaml")
+	b := filepath.Join(dir, "b.short.yaml")
+	write := func(file, contents string) {
+		err := ioutil.WriteFile(file, []byte(contents), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	poll := func(w *Watcher, expected []string) {
+		changed, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(changed, expected) {
+			t.Error(pretty.Diff(changed, expected))
+		}
+	}
+
+	write(a, "pod: {}")
+	write(filepath.Join(dir, "notes.txt"), "not a manifest")
+	w := NewWatcher([]string{dir})
+
+	// Everything is new at first.
+	poll(w, []string{a})
+	poll(w, []string{})
+
+	// New files in the directory, and changed files.
+	write(b, "pod: {}")
+	write(a, "pod: {name: a}")
+	poll(w, []string{a, b})
+
+	// A change of the modification time alone.
+	later := time.Now().Add(time.Hour)
+	err = os.Chtimes(b, later, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poll(w, []string{b})
+
+	// Removed files aren't reported, but count as new if they come back.
+	err = os.Remove(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poll(w, []string{})
+	write(b, "pod: {}")
+	poll(w, []string{b})
+}